@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// accessibleMode replaces the full-screen TUIs with plain prompts when set
+// via --accessible
+var accessibleMode bool
+
+// accessiblePageSize is the number of versions listed at a time
+const accessiblePageSize = 20
+
+// errPromptCancelled is returned when the prompts are left with q or the
+// input ends
+var errPromptCancelled = errors.New("cancelled")
+
+// accessibleEnabled reports whether plain prompts should be used, either via
+// the --accessible flag or a truthy SMFAMAN_ACCESSIBLE environment variable
+func accessibleEnabled() bool {
+	if accessibleMode {
+		return true
+	}
+	enabled, err := strconv.ParseBool(os.Getenv("SMFAMAN_ACCESSIBLE"))
+	return err == nil && enabled
+}
+
+// linePrompter asks questions one line at a time, so screen readers read
+// each question and answer in order without redrawn screens
+type linePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newLinePrompter(in io.Reader, out io.Writer) *linePrompter {
+	return &linePrompter{in: bufio.NewReader(in), out: out}
+}
+
+// readLine reads one answer, returning errPromptCancelled for q or when the
+// input ends without an answer
+func (p *linePrompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(p.out)
+		return "", errPromptCancelled
+	}
+	line = strings.TrimSpace(line)
+	if strings.EqualFold(line, "q") {
+		return "", errPromptCancelled
+	}
+	return line, nil
+}
+
+// text asks for a line of text, returning def for an empty answer
+func (p *linePrompter) text(question, def string) (string, error) {
+	fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	answer, err := p.readLine()
+	if err != nil || answer != "" {
+		return answer, err
+	}
+	return def, nil
+}
+
+// number asks for a positive whole number, returning 0 for an empty answer
+func (p *linePrompter) number(question, def string) (int, error) {
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		answer, err := p.readLine()
+		if err != nil || answer == "" {
+			return 0, err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n > 0 {
+			return n, nil
+		}
+		fmt.Fprintf(p.out, "%q is not a positive number.\n", answer)
+	}
+}
+
+// choose lists the options as a numbered menu and returns the index of the
+// one picked by number or name; an empty answer picks def
+func (p *linePrompter) choose(question string, options []string, def int) (int, error) {
+	fmt.Fprintln(p.out, question)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d. %s\n", i+1, option)
+	}
+	for {
+		fmt.Fprintf(p.out, "Enter a number [%d]: ", def+1)
+		answer, err := p.readLine()
+		if err != nil {
+			return 0, err
+		}
+		if answer == "" {
+			return def, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		if i := slices.IndexFunc(options, func(o string) bool { return strings.EqualFold(o, answer) }); i >= 0 {
+			return i, nil
+		}
+		fmt.Fprintf(p.out, "Choose a number from 1 to %d.\n", len(options))
+	}
+}
+
+// runAccessibleInit asks the init questions as plain prompts and writes the
+// config at path. Leaving with q writes nothing.
+func runAccessibleInit(in io.Reader, out io.Writer, path string) error {
+	p := newLinePrompter(in, out)
+	fmt.Fprintf(out, "Creating %s. Press Enter to keep the value in brackets, or type q to cancel.\n\n", path)
+
+	opts, err := askInitOptions(p)
+	if errors.Is(err, errPromptCancelled) {
+		fmt.Fprintln(out, "Cancelled.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeNewConfig(path, newProjectConfig(opts)); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, initSuccessMessage(path, opts))
+	return nil
+}
+
+// askInitOptions asks for the same choices as the init form
+func askInitOptions(p *linePrompter) (initOptions, error) {
+	var opts initOptions
+	var err error
+
+	if opts.ProjectName, err = p.text("Project name", "my-project"); err != nil {
+		return opts, err
+	}
+	if opts.Destination, err = p.text("Destination", "./frontend/{library_name}"); err != nil {
+		return opts, err
+	}
+
+	cdns := []string{string(frontend_config.CDNUnpkg), string(frontend_config.CDNCdnjs), string(frontend_config.CDNJsdelivr)}
+	choice, err := p.choose("Default CDN:", cdns, max(slices.Index(cdns, string(settingsCDN())), 0))
+	if err != nil {
+		return opts, err
+	}
+	opts.CDN = frontend_config.CDN(cdns[choice])
+
+	modes := []string{string(frontend_config.FilesModeEntrypoints), string(frontend_config.FilesModeAll), string(frontend_config.FilesModeMinified)}
+	if choice, err = p.choose("Files to download:", modes, 0); err != nil {
+		return opts, err
+	}
+	opts.FilesMode = frontend_config.FilesMode(modes[choice])
+
+	if opts.Concurrency, err = p.number("Parallel CDN requests", fmt.Sprintf("%d from the concurrency setting", settingsConcurrency())); err != nil {
+		return opts, err
+	}
+
+	if choice, err = p.choose("Write a lockfile?", []string{"yes", "no"}, 0); err != nil {
+		return opts, err
+	}
+	opts.Lockfile = choice == 0
+	return opts, nil
+}
+
+// runAccessibleVersionSelect lists versions (newest first) as numbered pages
+// and returns the one picked by number or typed in, or "" when cancelled
+func runAccessibleVersionSelect(in io.Reader, out io.Writer, packageName, cdn, latestVersion string, versions []string) (string, error) {
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no versions found for %s", packageName)
+	}
+	p := newLinePrompter(in, out)
+	fmt.Fprintf(out, "%d %s of %s on %s. Latest: %s\n", len(versions), pluralize(len(versions), "version", "versions"), packageName, cdn, latestVersion)
+
+	start := 0
+	for {
+		end := start + accessiblePageSize
+		if end > len(versions) {
+			end = len(versions)
+		}
+		for i := start; i < end; i++ {
+			fmt.Fprintf(out, "  %d. %s\n", i+1, versions[i])
+		}
+
+		more := ""
+		if end < len(versions) {
+			more = ", m for more"
+		}
+		fmt.Fprintf(out, "Enter a number or a version (Enter for %s%s, q to cancel): ", latestVersion, more)
+
+		for {
+			answer, err := p.readLine()
+			if errors.Is(err, errPromptCancelled) {
+				return "", nil
+			}
+			if answer == "" {
+				return latestVersion, nil
+			}
+			if strings.EqualFold(answer, "m") && more != "" {
+				break
+			}
+			if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(versions) {
+				return versions[n-1], nil
+			}
+			if slices.Contains(versions, answer) {
+				return answer, nil
+			}
+			fmt.Fprintf(out, "%q is not a listed number or a version of %s. Try again: ", answer, packageName)
+		}
+		start = end
+	}
+}
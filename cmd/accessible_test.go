@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestAccessibleEnabled(t *testing.T) {
+	t.Setenv("SMFAMAN_ACCESSIBLE", "")
+	if accessibleEnabled() {
+		t.Error("accessibleEnabled() = true without the flag or variable")
+	}
+	t.Setenv("SMFAMAN_ACCESSIBLE", "1")
+	if !accessibleEnabled() {
+		t.Error("accessibleEnabled() = false with SMFAMAN_ACCESSIBLE=1")
+	}
+}
+
+func TestRunAccessibleInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	// Name, default destination, CDN by name, files by number, a bad then a
+	// good concurrency, no lockfile
+	in := strings.NewReader("shop\n\njsdelivr\n3\nfast\n4\n2\n")
+
+	var out bytes.Buffer
+	if err := runAccessibleInit(in, &out, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"  1. unpkg", `"fast" is not a positive number`, "✓ Created"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ProjectName != "shop" || config.Destination != "./frontend/{library_name}" || config.CDN != frontend_config.CDNJsdelivr ||
+		config.FilesMode != frontend_config.FilesModeMinified || config.Concurrency != 4 || config.Lockfile == nil || *config.Lockfile {
+		t.Errorf("unexpected config: %+v", config)
+	}
+}
+
+func TestRunAccessibleInitCancelled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	for _, input := range []string{"shop\nq\n", "shop\n"} {
+		var out bytes.Buffer
+		if err := runAccessibleInit(strings.NewReader(input), &out, path); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), "Cancelled.") {
+			t.Errorf("input %q: output missing Cancelled.:\n%s", input, out.String())
+		}
+		if _, err := os.Stat(path); err == nil {
+			t.Errorf("input %q: config written after cancelling", input)
+		}
+	}
+}
+
+func TestRunAccessibleVersionSelect(t *testing.T) {
+	var versions []string
+	for i := 25; i > 0; i-- {
+		versions = append(versions, fmt.Sprintf("1.%d.0", i))
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"\n", "1.25.0"},
+		{"3\n", "1.23.0"},
+		{"1.2.0\n", "1.2.0"},
+		{"9.9.9\n0\n2\n", "1.24.0"},
+		{"m\n25\n", "1.1.0"},
+		{"q\n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := runAccessibleVersionSelect(strings.NewReader(tt.input), &out, "pkg", "unpkg", "1.25.0", versions)
+		if err != nil {
+			t.Fatalf("input %q: unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("input %q: got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	var out bytes.Buffer
+	runAccessibleVersionSelect(strings.NewReader("m\n1\n"), &out, "pkg", "unpkg", "1.25.0", versions)
+	if !strings.Contains(out.String(), "  21. 1.5.0") || strings.Count(out.String(), "m for more") != 1 {
+		t.Errorf("unexpected paging:\n%s", out.String())
+	}
+}
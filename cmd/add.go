@@ -9,19 +9,27 @@ import (
 	"gopkg.in/yaml.v3"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+	"nexus-sds.com/smfaman/pkgs/tui"
 )
 
 var (
-	addCDN         string
-	addInteractive bool
-	addForce       bool
-	addFiles       []string
-	addOutputPath  string
+	addCDN               string
+	addInteractive       bool
+	addForce             bool
+	addFiles             []string
+	addOutputPath        string
+	addMetadata          bool
+	addAutoCDN           bool
+	addSaveExact         bool
+	addSaveRange         bool
+	addNoAlias           bool
+	addIncludePrerelease bool
+	addDev               bool
 )
 
 // addCmd represents the add command
 var addCmd = &cobra.Command{
-	Use:     "add <package-name>[@version]",
+	Use:     "add [package-name[@version]]",
 	Aliases: []string{"pkgadd", "a"},
 	Short:   "Add new library to the Smart Frontend Asset Manager Configuration",
 	Long: `Add a new library to your frontend configuration file.
@@ -29,8 +37,16 @@ var addCmd = &cobra.Command{
 You can specify the version directly using package@version syntax, or use
 the --interactive flag to browse and select from available versions.
 
-The package name is required in all cases. If no version is specified and
-interactive mode is not enabled, the latest version will be used.
+The version may also be a range (^18, ~1.2, 5.x, ">=1.0 <2") or a dist-tag
+(next). It is resolved to the highest matching published version, which is
+recorded in the config. Use --save-range to record the range as written
+instead; sync then lets the CDN pick the matching version.
+
+The package name is required unless --interactive is given, which then
+opens the package search to pick one. If no version is specified and
+interactive mode is not enabled, the latest stable version will be used,
+even when the CDN tags a prerelease as latest. Use --include-prerelease to
+take the newest version published, prerelease or not.
 
 The library will be added to the config file specified by the -f flag
 (default: smartfrontend.yaml). If the library already exists, use --force
@@ -40,20 +56,52 @@ You can optionally specify:
   - CDN to use with --cdn flag
   - Specific files to download with --files flag
   - Custom output path with --output flag
+  - Record description, homepage and license with --metadata
+  - Add it as a development-only library with --dev
+
+If the package (or version) isn't available on the selected CDN, the other
+CDNs are checked and you are offered one that has it. Use --auto-cdn to
+switch without asking.
+
+With --dev the library goes under dev_libraries instead of libraries. Dev
+libraries (live-reload scripts, debug builds) are only synced with
+'smfaman sync --dev' and never with the prod profile, so they stay out of
+production vendor folders.
+
+Package names are lowercased and checked against npm's naming rules; for a
+name that is invalid or not found, a similar package is suggested.
+
+Well-known names that differ from the published package are resolved
+automatically (fontawesome → @fortawesome/fontawesome-free, tailwind →
+tailwindcss). Add your own in the aliases setting, or use --no-alias to add
+the package exactly as named.
 
 Examples:
   smfaman add react@18.2.0
+  smfaman add react@^18
+  smfaman add bootstrap@5.x --save-range
   smfaman add react --interactive
+  smfaman add --interactive  # Search for the package first
   smfaman add bootstrap --cdn cdnjs
   smfaman add jquery@3.7.1 --files "dist/jquery.min.js"
-  smfaman add lodash --output "./custom/lodash"`,
-	Args: cobra.ExactArgs(1),
+  smfaman add lodash --output "./custom/lodash"
+  smfaman add alpinejs --metadata
+  smfaman add @hotwired/turbo --auto-cdn
+  smfaman add fontawesome@6
+  smfaman add livereload-js --dev`,
+	Args: cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
-		packageSpec := args[0]
+		packageSpec, err := addPackageSpec(args)
+		if err != nil {
+			exitWithError(err)
+		}
+		if packageSpec == "" {
+			fmt.Println("Cancelled.")
+			return
+		}
 
 		if err := addLibraryToConfig(packageSpec); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -66,12 +114,45 @@ func init() {
 	addCmd.Flags().BoolVar(&addForce, "force", false, "Overwrite library if it already exists in config")
 	addCmd.Flags().StringArrayVar(&addFiles, "files", nil, "Specific files to download (can be specified multiple times)")
 	addCmd.Flags().StringVar(&addOutputPath, "output", "", "Custom output path for this library")
+	addCmd.Flags().BoolVarP(&addMetadata, "metadata", "m", false, "Record package description, homepage and license in the metadata file")
+	addCmd.Flags().BoolVar(&addAutoCDN, "auto-cdn", false, "Switch to another CDN without asking if the package isn't on the selected one")
+	addCmd.Flags().BoolVar(&addSaveExact, "save-exact", false, "Record the exact version a range resolves to (default)")
+	addCmd.Flags().BoolVar(&addSaveRange, "save-range", false, "Record a version range or dist-tag as written")
+	addCmd.Flags().BoolVar(&addIncludePrerelease, "include-prerelease", false, "Let the latest version be a prerelease when one is newer")
+	addCmd.Flags().BoolVar(&addNoAlias, "no-alias", false, "Don't resolve well-known names to their published package")
+	addCmd.Flags().BoolVar(&addDev, "dev", false, "Add the library to dev_libraries (only synced with 'sync --dev')")
+	addCmd.MarkFlagsMutuallyExclusive("save-exact", "save-range")
+}
+
+// addPackageSpec returns the package to add: the argument, or with
+// --interactive and no argument the package picked in the search ("" when
+// the search is cancelled)
+func addPackageSpec(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	if !addInteractive {
+		return "", fmt.Errorf("requires a package name (or --interactive to search for one)")
+	}
+	if err := requireTerminal("add --interactive without a package name", "pass the package name"); err != nil {
+		return "", err
+	}
+
+	picked, err := tui.SelectPackage(packagePickerOptions(""))
+	if err != nil || picked == nil {
+		return "", err
+	}
+	return picked.Name, nil
 }
 
 // addLibraryToConfig adds a library to the frontend config
 func addLibraryToConfig(packageSpec string) error {
 	// Parse package name and version
 	packageName, specifiedVersion := parsePackageSpec(packageSpec)
+	if pkg, ok := resolvePackageAlias(packageName); ok && !addNoAlias {
+		fmt.Printf("• %s is published as %s\n", packageName, pkg)
+		packageName = pkg
+	}
 
 	// Load existing config
 	config, err := loadConfig(FrontendConfig)
@@ -79,24 +160,51 @@ func addLibraryToConfig(packageSpec string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// Determine CDN to use
+	cdn := determineCDNForAdd(config)
+	selectedCDN := cdn
+
+	packageName, err = normalizePackageName(packageName, cdn)
+	if err != nil {
+		return err
+	}
+
+	// Development-only libraries have their own section; a library is in one of them
+	libraries, other, otherSection := config.Libraries, config.DevLibraries, "dev_libraries"
+	if addDev {
+		if config.DevLibraries == nil {
+			config.DevLibraries = make(map[string]frontend_config.LibraryConfig)
+		}
+		libraries, other, otherSection = config.DevLibraries, config.Libraries, "libraries"
+	}
+	if _, inOther := other[packageName]; inOther {
+		return fmt.Errorf("library '%s' is already under %s, delete it there first", packageName, otherSection)
+	}
+
 	// Check if library already exists
-	if _, exists := config.Libraries[packageName]; exists && !addForce {
+	existing, exists := libraries[packageName]
+	if exists && !addForce {
 		return fmt.Errorf("library '%s' already exists in config, use --force to overwrite", packageName)
 	}
 
-	// Determine CDN to use
-	cdn := determineCDNForAdd(config)
-
 	var selectedVersion string
 
 	// If interactive mode, launch version selector
-	if addInteractive {
-		versions, latestVersion, err := fetchVersionsForCDN(packageName, cdn)
+	if addInteractive && interactiveAvailable() {
+		versions, latestVersion, usedCDN, err := fetchVersionsWithFallback(packageName, "", cdn, fetchVersionsForCDN, addAutoCDN)
 		if err != nil {
-			return err
+			return suggestPackage(packageName, cdn, err)
 		}
-
-		selectedVersion, err = runInteractive(packageName, string(cdn), latestVersion, versions)
+		cdn = usedCDN
+		latestVersion = frontend_mgr.LatestVersion(versions, latestVersion, addIncludePrerelease)
+
+		// A version or prefix given with the name narrows the list, and
+		// --force preselects the version already configured
+		options := versionSelectorOptions{ShowPrereleases: addIncludePrerelease, Current: existing.Version}
+		if frontend_mgr.IsExactVersion(specifiedVersion) || frontend_mgr.IsVersionPrefix(specifiedVersion) {
+			options.Filter = specifiedVersion
+		}
+		selectedVersion, err = runVersionSelector(packageName, string(cdn), latestVersion, versions, options)
 		if err != nil {
 			return fmt.Errorf("interactive mode error: %w", err)
 		}
@@ -105,31 +213,62 @@ func addLibraryToConfig(packageSpec string) error {
 			fmt.Println("Cancelled.")
 			return nil
 		}
+	} else if specifiedVersion != "" && !frontend_mgr.IsExactVersion(specifiedVersion) {
+		// Resolve a range or dist-tag to the highest matching version
+		versions, latestVersion, usedCDN, err := fetchVersionsWithFallback(packageName, "", cdn, fetchVersionsForCDN, addAutoCDN)
+		if err != nil {
+			return suggestPackage(packageName, cdn, err)
+		}
+		cdn = usedCDN
+
+		resolved, err := resolveRequestedVersion(packageName, specifiedVersion, versions, latestVersion, cdn)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s@%s resolves to %s\n", packageName, specifiedVersion, resolved)
+
+		selectedVersion = resolved
+		if addSaveRange {
+			if cdn == frontend_config.CDNCdnjs {
+				return fmt.Errorf("cdnjs only serves exact versions, use --save-exact or another CDN")
+			}
+			selectedVersion = specifiedVersion
+		}
 	} else if specifiedVersion != "" {
 		// Validate specified version
-		selectedVersion = specifiedVersion
-		if err := validateVersion(packageName, selectedVersion, cdn); err != nil {
-			return err
+		versions, _, usedCDN, err := fetchVersionsWithFallback(packageName, specifiedVersion, cdn, fetchVersionsForCDN, addAutoCDN)
+		if err != nil {
+			return suggestPackage(packageName, cdn, err)
 		}
+		cdn = usedCDN
+		// Record the version as the CDN published it, with or without a "v"
+		selectedVersion, _ = frontend_mgr.FindVersion(versions, specifiedVersion)
+		fmt.Printf("✓ Version %s found for %s\n", selectedVersion, packageName)
 	} else {
 		// No version specified and not interactive - use latest
-		_, latestVersion, err := fetchVersionsForCDN(packageName, cdn)
+		versions, latestVersion, usedCDN, err := fetchVersionsWithFallback(packageName, "", cdn, fetchVersionsForCDN, addAutoCDN)
 		if err != nil {
-			return err
+			return suggestPackage(packageName, cdn, err)
 		}
-		selectedVersion = latestVersion
-		fmt.Printf("No version specified, using latest: %s\n", latestVersion)
+		cdn = usedCDN
+		selectedVersion = frontend_mgr.LatestVersion(versions, latestVersion, addIncludePrerelease)
+		fmt.Printf("No version specified, using latest: %s\n", versionLabel(selectedVersion))
 	}
 
-	// Create library config
+	// Create library config, keeping the notes and tags of an overwritten entry
 	libConfig := frontend_config.LibraryConfig{
 		Version: selectedVersion,
+		Tags:    existing.Tags,
+		Notes:   existing.Notes,
 	}
 
 	// Add optional fields if specified
 	if addCDN != "" {
 		libConfig.CDN = frontend_config.CDN(addCDN)
 	}
+	if cdn != selectedCDN {
+		libConfig.CDN = cdn
+	}
 
 	if len(addFiles) > 0 {
 		libConfig.Files = addFiles
@@ -140,7 +279,7 @@ func addLibraryToConfig(packageSpec string) error {
 	}
 
 	// Add to config
-	config.Libraries[packageName] = libConfig
+	libraries[packageName] = libConfig
 
 	// Save config
 	if err := saveConfig(FrontendConfig, config); err != nil {
@@ -157,9 +296,23 @@ func addLibraryToConfig(packageSpec string) error {
 	if len(libConfig.Files) > 0 {
 		fmt.Printf("Files:    %v\n", libConfig.Files)
 	}
+	if addDev {
+		fmt.Printf("Section:  dev_libraries\n")
+	}
+	if addMetadata {
+		if meta, err := recordLibraryMetadata(FrontendConfig, packageName, cdn); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record metadata: %v\n", err)
+		} else if meta.License != "" {
+			fmt.Printf("License:  %s\n", meta.License)
+		}
+	}
 	fmt.Printf("\nConfig updated: %s\n", FrontendConfig)
 	fmt.Printf("\nNext steps:\n")
-	fmt.Printf("  • Sync libraries: smfaman sync\n")
+	if addDev {
+		fmt.Printf("  • Sync libraries: smfaman sync --dev\n")
+	} else {
+		fmt.Printf("  • Sync libraries: smfaman sync\n")
+	}
 
 	return nil
 }
@@ -188,9 +341,29 @@ func parsePackageSpec(spec string) (name, version string) {
 	return
 }
 
+// resolveRequestedVersion resolves a version prefix ("18", "3.4"), range or
+// dist-tag to the highest matching published version
+func resolveRequestedVersion(packageName, spec string, versions []string, latest string, cdn frontend_config.CDN) (string, error) {
+	distTags := map[string]string{"latest": latest}
+	if _, err := frontend_mgr.ParseVersionRange(spec); err != nil {
+		// Not a range, so look it up among all the dist-tags
+		_, tags, err := fetchPinVersions(packageName, cdn)
+		if err != nil {
+			return "", err
+		}
+		distTags = tags
+	}
+
+	resolved, err := frontend_mgr.ResolveVersionSpec(spec, versions, distTags)
+	if err != nil {
+		return "", notFoundError(fmt.Errorf("failed to resolve %s@%s: %w", packageName, spec, err))
+	}
+	return resolved, nil
+}
+
 // determineCDNForAdd determines which CDN to use for adding a library
 func determineCDNForAdd(config *frontend_config.FrontendConfig) frontend_config.CDN {
-	// Priority: --cdn flag > config default > unpkg
+	// Priority: --cdn flag > config default > cdn setting > unpkg
 	if addCDN != "" {
 		cdn := frontend_config.CDN(addCDN)
 		if !frontend_config.IsValidCDN(cdn) {
@@ -204,92 +377,40 @@ func determineCDNForAdd(config *frontend_config.FrontendConfig) frontend_config.
 		return config.CDN
 	}
 
-	return frontend_config.CDNUnpkg
+	return settingsCDN()
 }
 
 // fetchVersionsForCDN fetches versions from the appropriate CDN
 func fetchVersionsForCDN(packageName string, cdn frontend_config.CDN) (versions []string, latest string, err error) {
 	fmt.Printf("Fetching versions for '%s' from %s...\n", packageName, cdn)
-
-	switch cdn {
-	case frontend_config.CDNUnpkg:
-		result, err := frontend_mgr.FetchUnpkgVersions(packageName)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to fetch versions from unpkg: %w", err)
-		}
-		versions = make([]string, 0, len(result.Versions))
-		for ver := range result.Versions {
-			versions = append(versions, ver)
-		}
-		latest = result.DistTags["latest"]
-
-	case frontend_config.CDNCdnjs:
-		result, err := frontend_mgr.FetchCdnjsVersions(packageName)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to fetch versions from cdnjs: %w", err)
-		}
-		versions = result.Versions
-		latest = result.Version
-
-	case frontend_config.CDNJsdelivr:
-		result, err := frontend_mgr.FetchJsdelivrVersions(packageName)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to fetch versions from jsdelivr: %w", err)
-		}
-		versions = make([]string, 0, len(result.Versions))
-		for _, vInfo := range result.Versions {
-			versions = append(versions, vInfo.Version)
-		}
-		latest = result.Tags["latest"]
-
-	default:
-		return nil, "", fmt.Errorf("unsupported CDN: %s", cdn)
-	}
-
-	if len(versions) == 0 {
-		return nil, "", fmt.Errorf("no versions found for package '%s'", packageName)
-	}
-
-	// Sort versions
-	sortedVersions := frontend_mgr.SortVersions(versions)
-	return sortedVersions, latest, nil
-}
-
-// validateVersion checks if a version exists for a package on a CDN
-func validateVersion(packageName, version string, cdn frontend_config.CDN) error {
-	versions, _, err := fetchVersionsForCDN(packageName, cdn)
-	if err != nil {
-		return err
-	}
-
-	// Check if version exists
-	for _, v := range versions {
-		if v == version {
-			fmt.Printf("✓ Version %s found for %s\n", version, packageName)
-			return nil
-		}
-	}
-
-	return fmt.Errorf("version '%s' not found for package '%s' on %s", version, packageName, cdn)
+	return fetchVersionsForUpgrade(packageName, cdn)
 }
 
 // loadConfig loads a frontend config from a file
 func loadConfig(path string) (*frontend_config.FrontendConfig, error) {
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file '%s' does not exist. Run 'smfaman init' first", path)
+		if !offerInit(path) {
+			return nil, configError(fmt.Errorf("config file '%s' does not exist. Run 'smfaman init' first", path))
+		}
+		fmt.Println()
 	}
 
 	// Read file
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, configError(fmt.Errorf("failed to read config file: %w", err))
 	}
 
 	// Parse YAML
 	var config frontend_config.FrontendConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, configError(fmt.Errorf("failed to parse config file: %w", err))
+	}
+
+	// Fail fast on configs written for a newer smfaman
+	if err := checkRequiredVersion(path, &config); err != nil {
+		return nil, err
 	}
 
 	// Ensure Libraries map is initialized
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -34,6 +35,18 @@ func TestParsePackageSpec(t *testing.T) {
 			expectedName:    "@babel/core",
 			expectedVersion: "7.22.0",
 		},
+		{
+			name:            "package with version range",
+			spec:            "react@^18",
+			expectedName:    "react",
+			expectedVersion: "^18",
+		},
+		{
+			name:            "scoped package with version range",
+			spec:            "@babel/core@~7.22",
+			expectedName:    "@babel/core",
+			expectedVersion: "~7.22",
+		},
 		{
 			name:            "scoped package without version",
 			spec:            "@babel/core",
@@ -236,6 +249,32 @@ func TestAddLibraryToConfigWithExistingLibrary(t *testing.T) {
 	addForce = false // Reset
 }
 
+func TestAddLibraryToConfigKeepsSectionsApart(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "dev.yaml")
+	writeTestFile(t, configPath, `destination: ./frontend
+libraries:
+  htmx.org:
+    version: 2.0.4
+dev_libraries:
+  livereload-js:
+    version: 4.0.2
+`)
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig, addDev, addForce = oldConfig, false, false }()
+
+	addForce = true
+	if err := addLibraryToConfig("livereload-js@4.0.2"); err == nil || !strings.Contains(err.Error(), "dev_libraries") {
+		t.Errorf("expected an error for a library already under dev_libraries, got %v", err)
+	}
+
+	addDev = true
+	if err := addLibraryToConfig("htmx.org@2.0.4"); err == nil || !strings.Contains(err.Error(), "already under libraries") {
+		t.Errorf("expected an error for a library already under libraries, got %v", err)
+	}
+}
+
 func TestAddLibraryToConfigNonExistentConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "nonexistent.yaml")
@@ -339,3 +378,42 @@ func TestSaveConfigPreservesAllFields(t *testing.T) {
 		t.Errorf("bootstrap output path mismatch: expected %q, got %q", "./custom/bootstrap", bootstrap.OutputPath)
 	}
 }
+
+func TestResolveRequestedVersion(t *testing.T) {
+	origFetch := fetchPinVersions
+	defer func() { fetchPinVersions = origFetch }()
+	fetchPinVersions = func(packageName string, cdn frontend_config.CDN) ([]string, map[string]string, error) {
+		return nil, map[string]string{"latest": "18.3.1", "next": "19.0.0-rc.1"}, nil
+	}
+
+	versions := []string{"19.0.0-rc.1", "18.3.1", "18.2.0", "17.0.2"}
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"^18", "18.3.1", false},
+		{"18", "18.3.1", false},
+		{"17", "17.0.2", false},
+		{"18.2", "18.2.0", false},
+		{"19", "", true},
+		{"~18.2.0", "18.2.0", false},
+		{"17.x", "17.0.2", false},
+		{"latest", "18.3.1", false},
+		{"next", "19.0.0-rc.1", false},
+		{"^20", "", true},
+		{"canary", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := resolveRequestedVersion("react", tt.spec, versions, "18.3.1", frontend_config.CDNUnpkg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveRequestedVersion(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveRequestedVersion(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
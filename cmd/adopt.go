@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var (
+	adoptApply   bool
+	adoptCDN     string
+	adoptOffline bool
+)
+
+// adoptCmd represents the adopt command
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <vendor-dir>",
+	Short: "Detect existing vendored libraries and propose config entries",
+	Long: `Scan a folder of hand-vendored JavaScript and CSS files, identify the
+libraries in it and propose configuration entries, so legacy projects can be
+moved onto smfaman.
+
+Libraries are identified by, in order:
+  • A package.json (name and version) in the file's folder or a parent
+  • The license banner at the top of the file (e.g. "/*! jQuery v3.7.1")
+  • A versioned file name (e.g. htmx-1.9.12.min.js)
+
+Each candidate is then looked up on the CDN: local files are matched to the
+published files by name and verified against the CDN's integrity hashes, and
+the matched CDN paths become the proposed 'files' list. Use --offline to skip
+the lookup.
+
+Use --apply to add the proposed libraries to the configuration. Libraries
+that are already configured are left alone. Note that sync writes files at
+their CDN paths under output_path, which may differ from the current layout.
+
+Examples:
+  smfaman adopt ./static/vendor
+  smfaman adopt ./static/vendor --apply
+  smfaman adopt ./public/js --cdn jsdelivr
+  smfaman adopt ./legacy --offline`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAdopt(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+	adoptCmd.Flags().BoolVar(&adoptApply, "apply", false, "Add the proposed libraries to the config")
+	adoptCmd.Flags().StringVar(&adoptCDN, "cdn", "", "CDN to verify against (unpkg, cdnjs, jsdelivr)")
+	adoptCmd.Flags().BoolVar(&adoptOffline, "offline", false, "Don't look the libraries up on the CDN")
+}
+
+// fetchAdoptFiles lists the files of a package version (replaced in tests)
+var fetchAdoptFiles = fetchFileList
+
+// adoptFile is a local file attributed to a library
+type adoptFile struct {
+	Path     string // Slash path relative to the current directory
+	CDNPath  string // Matching file on the CDN, if any
+	Verified bool   // Contents match the CDN's integrity hash
+}
+
+// adoptCandidate is a library detected in the vendor folder
+type adoptCandidate struct {
+	Name    string
+	Version string
+	Source  string // How it was detected: package.json, banner or file name
+	Dir     string // Folder holding its files
+	Files   []adoptFile
+	OnCDN   bool
+	CDNErr  error
+}
+
+var (
+	// bannerPattern matches license banners like "/*! jQuery v3.7.1 |" or
+	// " * Bootstrap v5.3.0 (https://getbootstrap.com/)"
+	bannerPattern = regexp.MustCompile(`(?m)^\s*(?:/\*!?|\*|//)\s*(?:@license\s+)?([A-Za-z][\w.\- ]{0,40}?)\s+v(\d+\.\d+\.\d+(?:-[0-9A-Za-z.]+)?)\b`)
+
+	// versionedFilePattern matches file names like htmx-1.9.12.min.js
+	versionedFilePattern = regexp.MustCompile(`^([a-z][\w.-]*?)[-.@]v?(\d+\.\d+\.\d+(?:-[0-9a-z.]+)?)(?:\.min)?\.(?:m?js|css)$`)
+)
+
+// runAdopt executes the adopt command
+func runAdopt(vendorDir string) error {
+	info, err := os.Stat(vendorDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", vendorDir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", vendorDir)
+	}
+
+	var config *frontend_config.FrontendConfig
+	if _, err := os.Stat(FrontendConfig); err == nil || adoptApply {
+		if config, err = loadConfig(FrontendConfig); err != nil {
+			return err
+		}
+	}
+
+	cdn := settingsCDN()
+	if config != nil && frontend_config.IsValidCDN(config.CDN) {
+		cdn = config.CDN
+	}
+	if adoptCDN != "" {
+		cdn = frontend_config.CDN(adoptCDN)
+		if !frontend_config.IsValidCDN(cdn) {
+			return fmt.Errorf("invalid CDN '%s'", adoptCDN)
+		}
+	}
+
+	fmt.Printf("Scanning %s...\n\n", vendorDir)
+	candidates, unknown, err := scanVendorDir(vendorDir)
+	if err != nil {
+		return err
+	}
+
+	if !adoptOffline {
+		for _, c := range candidates {
+			verifyAdoptCandidate(c, cdn)
+		}
+	}
+
+	proposed := make(map[string]frontend_config.LibraryConfig)
+	for _, c := range candidates {
+		printAdoptCandidate(c, cdn)
+
+		if !adoptOffline && !c.OnCDN {
+			continue
+		}
+		if config != nil {
+			if _, exists := config.Libraries[c.Name]; exists {
+				fmt.Printf("    • already configured, skipping\n")
+				continue
+			}
+		}
+		if _, exists := proposed[c.Name]; exists {
+			fmt.Printf("    • another version of %s was found first, skipping\n", c.Name)
+			continue
+		}
+		proposed[c.Name] = c.libraryConfig()
+	}
+
+	if len(unknown) > 0 {
+		fmt.Printf("\n• Unrecognized files (%d):\n", len(unknown))
+		for _, p := range unknown {
+			fmt.Printf("    %s\n", p)
+		}
+	}
+
+	if len(proposed) == 0 {
+		fmt.Println("\nNo libraries to adopt.")
+		return nil
+	}
+
+	data, err := yaml.Marshal(map[string]any{"libraries": proposed})
+	if err != nil {
+		return fmt.Errorf("failed to encode proposal: %w", err)
+	}
+	fmt.Printf("\nProposed configuration:\n\n%s", data)
+
+	if !adoptApply {
+		fmt.Printf("\nRun with --apply to add %s to %s.\n", pluralize(len(proposed), "this library", "these libraries"), FrontendConfig)
+		return nil
+	}
+
+	for name, libConfig := range proposed {
+		config.Libraries[name] = libConfig
+	}
+	if err := saveConfig(FrontendConfig, config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Printf("\n✓ Added %d %s to %s\n", len(proposed), pluralize(len(proposed), "library", "libraries"), FrontendConfig)
+	return nil
+}
+
+// scanVendorDir walks a vendor folder and groups its JavaScript and CSS
+// files by detected library. Files that can't be attributed are returned
+// in unknown.
+func scanVendorDir(root string) (candidates []*adoptCandidate, unknown []string, err error) {
+	packages := make(map[string]packageIdentity) // Folder -> package.json identity
+	var assets []string
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if d.Name() == "package.json" {
+			if id, ok := readPackageIdentity(p); ok {
+				packages[filepath.Dir(p)] = id
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".js", ".mjs", ".css":
+			assets = append(assets, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	sort.Strings(assets)
+
+	byKey := make(map[string]*adoptCandidate)
+	for _, p := range assets {
+		id, source, dir := identifyAsset(p, root, packages)
+		rel := filepath.ToSlash(p)
+		if id.Name == "" {
+			unknown = append(unknown, rel)
+			continue
+		}
+
+		key := id.Name + "@" + id.Version
+		c, ok := byKey[key]
+		if !ok {
+			c = &adoptCandidate{Name: id.Name, Version: id.Version, Source: source, Dir: filepath.ToSlash(dir)}
+			byKey[key] = c
+			candidates = append(candidates, c)
+		}
+		c.Files = append(c.Files, adoptFile{Path: rel})
+		if source != "package.json" {
+			c.Dir = commonDir(c.Dir, filepath.ToSlash(dir))
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Name != candidates[j].Name {
+			return candidates[i].Name < candidates[j].Name
+		}
+		return candidates[i].Version < candidates[j].Version
+	})
+	return candidates, unknown, nil
+}
+
+// packageIdentity is a package name and version
+type packageIdentity struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// readPackageIdentity reads the name and version from a package.json
+func readPackageIdentity(p string) (packageIdentity, bool) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return packageIdentity{}, false
+	}
+	var id packageIdentity
+	if json.Unmarshal(data, &id) != nil || id.Name == "" || !frontend_mgr.IsExactVersion(id.Version) {
+		return packageIdentity{}, false
+	}
+	return id, true
+}
+
+// identifyAsset works out which library a file belongs to, returning the
+// identity, how it was found and the folder the library lives in
+func identifyAsset(p, root string, packages map[string]packageIdentity) (packageIdentity, string, string) {
+	root = filepath.Clean(root)
+	for dir := filepath.Dir(p); ; dir = filepath.Dir(dir) {
+		if id, ok := packages[dir]; ok {
+			return id, "package.json", dir
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	if head, err := readHead(p, 2048); err == nil {
+		if m := bannerPattern.FindStringSubmatch(head); m != nil {
+			name := strings.ToLower(strings.Join(strings.Fields(m[1]), "-"))
+			return packageIdentity{Name: name, Version: m[2]}, "banner", filepath.Dir(p)
+		}
+	}
+
+	if m := versionedFilePattern.FindStringSubmatch(strings.ToLower(filepath.Base(p))); m != nil {
+		return packageIdentity{Name: m[1], Version: m[2]}, "file name", filepath.Dir(p)
+	}
+
+	return packageIdentity{}, "", ""
+}
+
+// readHead returns up to n bytes from the start of a file
+func readHead(p string, n int) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, n)
+	read, err := f.Read(buf)
+	if err != nil && read == 0 {
+		return "", err
+	}
+	return string(buf[:read]), nil
+}
+
+// commonDir returns the deepest folder containing both slash paths
+func commonDir(a, b string) string {
+	for a != b {
+		if len(a) > len(b) {
+			a = path.Dir(a)
+		} else {
+			b = path.Dir(b)
+		}
+		if a == "." || b == "." {
+			return "."
+		}
+	}
+	return a
+}
+
+// verifyAdoptCandidate looks the candidate up on the CDN and matches its
+// local files to published files, verifying them by integrity or size
+func verifyAdoptCandidate(c *adoptCandidate, cdn frontend_config.CDN) {
+	published, err := fetchAdoptFiles(c.Name, c.Version, cdn)
+	if err != nil {
+		c.CDNErr = err
+		return
+	}
+	c.OnCDN = true
+
+	byBase := make(map[string][]CDNFile)
+	for _, f := range published {
+		base := strings.ToLower(path.Base(f.Path))
+		byBase[base] = append(byBase[base], f)
+	}
+
+	for i := range c.Files {
+		local := &c.Files[i]
+		matches := byBase[strings.ToLower(path.Base(local.Path))]
+		if len(matches) == 0 {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.FromSlash(local.Path))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			if (m.Integrity != "" && frontend_mgr.VerifySRI(data, m.Integrity) == nil) ||
+				(m.Integrity == "" && m.Size > 0 && m.Size == int64(len(data))) {
+				local.CDNPath, local.Verified = m.Path, true
+				break
+			}
+		}
+		if local.CDNPath == "" {
+			local.CDNPath = matches[0].Path
+		}
+	}
+}
+
+// libraryConfig returns the proposed config entry for a candidate
+func (c *adoptCandidate) libraryConfig() frontend_config.LibraryConfig {
+	libConfig := frontend_config.LibraryConfig{Version: c.Version}
+
+	seen := make(map[string]bool)
+	for _, f := range c.Files {
+		if f.CDNPath != "" && !seen[f.CDNPath] {
+			seen[f.CDNPath] = true
+			libConfig.Files = append(libConfig.Files, f.CDNPath)
+		}
+	}
+	sort.Strings(libConfig.Files)
+
+	switch {
+	case c.Dir == "" || c.Dir == ".":
+		libConfig.OutputPath = "."
+	case filepath.IsAbs(filepath.FromSlash(c.Dir)):
+		libConfig.OutputPath = c.Dir
+	default:
+		libConfig.OutputPath = "./" + c.Dir
+	}
+	return libConfig
+}
+
+// printAdoptCandidate prints a detected library and how its files matched
+func printAdoptCandidate(c *adoptCandidate, cdn frontend_config.CDN) {
+	verified := 0
+	for _, f := range c.Files {
+		if f.Verified {
+			verified++
+		}
+	}
+
+	files := fmt.Sprintf("%d %s", len(c.Files), pluralize(len(c.Files), "file", "files"))
+	switch {
+	case adoptOffline:
+		fmt.Printf("• %s@%s (%s) - %s\n", c.Name, c.Version, c.Source, files)
+	case !c.OnCDN:
+		fmt.Printf("⚠ %s@%s (%s) - not found on %s: %v\n", c.Name, c.Version, c.Source, cdn, c.CDNErr)
+	case verified == len(c.Files):
+		fmt.Printf("✓ %s@%s (%s) - %s, all verified on %s\n", c.Name, c.Version, c.Source, files, cdn)
+	default:
+		fmt.Printf("⚠ %s@%s (%s) - %s, %d verified on %s\n", c.Name, c.Version, c.Source, files, verified, cdn)
+	}
+
+	for _, f := range c.Files {
+		switch {
+		case adoptOffline || !c.OnCDN:
+			fmt.Printf("    %s\n", f.Path)
+		case f.Verified:
+			fmt.Printf("    %s → %s ✓\n", f.Path, f.CDNPath)
+		case f.CDNPath != "":
+			fmt.Printf("    %s → %s (contents differ)\n", f.Path, f.CDNPath)
+		default:
+			fmt.Printf("    %s (not published on %s)\n", f.Path, cdn)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestScanVendorDir(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "jquery.min.js"), "/*! jQuery v3.7.1 | (c) OpenJS Foundation */\n!function(){}")
+	writeTestFile(t, filepath.Join(root, "css", "bootstrap.min.css"), "@charset \"UTF-8\";/*!\n * Bootstrap  v5.3.0 (https://getbootstrap.com/)\n */")
+	writeTestFile(t, filepath.Join(root, "htmx-1.9.12.min.js"), "(function(){})()")
+	writeTestFile(t, filepath.Join(root, "alpine", "package.json"), `{"name": "alpinejs", "version": "3.14.1"}`)
+	writeTestFile(t, filepath.Join(root, "alpine", "dist", "cdn.min.js"), "(()=>{})()")
+	writeTestFile(t, filepath.Join(root, "app.js"), "console.log('mine')")
+
+	candidates, unknown, err := scanVendorDir(root)
+	if err != nil {
+		t.Fatalf("scanVendorDir() error = %v", err)
+	}
+
+	var got []string
+	for _, c := range candidates {
+		got = append(got, c.Name+"@"+c.Version+" ("+c.Source+")")
+	}
+	want := []string{
+		"alpinejs@3.14.1 (package.json)",
+		"bootstrap@5.3.0 (banner)",
+		"htmx@1.9.12 (file name)",
+		"jquery@3.7.1 (banner)",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("candidates = %v, want %v", got, want)
+	}
+	if candidates[0].Dir != filepath.ToSlash(filepath.Join(root, "alpine")) {
+		t.Errorf("alpinejs dir = %s, want the package.json folder", candidates[0].Dir)
+	}
+	if len(unknown) != 1 || !strings.HasSuffix(unknown[0], "app.js") {
+		t.Errorf("unknown = %v, want app.js", unknown)
+	}
+}
+
+func TestVerifyAdoptCandidate(t *testing.T) {
+	origFetch := fetchAdoptFiles
+	defer func() { fetchAdoptFiles = origFetch }()
+
+	root := t.TempDir()
+	jqueryData := "/*! jQuery v3.7.1 */ jquery"
+	writeTestFile(t, filepath.Join(root, "jquery.min.js"), jqueryData)
+	writeTestFile(t, filepath.Join(root, "jquery.slim.min.js"), "/*! jQuery v3.7.1 */ locally patched")
+	writeTestFile(t, filepath.Join(root, "plugins.js"), "/*! jQuery v3.7.1 */ plugins")
+
+	fetchAdoptFiles = func(name, version string, cdn frontend_config.CDN) ([]CDNFile, error) {
+		if name != "jquery" {
+			return nil, errors.New("not found")
+		}
+		return []CDNFile{
+			{Path: "dist/jquery.min.js", Integrity: frontend_mgr.ComputeSRI([]byte(jqueryData))},
+			{Path: "dist/jquery.slim.min.js", Integrity: frontend_mgr.ComputeSRI([]byte("upstream"))},
+		}, nil
+	}
+
+	candidates, _, err := scanVendorDir(root)
+	if err != nil || len(candidates) != 1 {
+		t.Fatalf("scanVendorDir() = %v, %v; want one candidate", candidates, err)
+	}
+	c := candidates[0]
+	verifyAdoptCandidate(c, frontend_config.CDNUnpkg)
+
+	if !c.OnCDN {
+		t.Fatal("jquery should be found on the CDN")
+	}
+	byBase := make(map[string]adoptFile)
+	for _, f := range c.Files {
+		byBase[filepath.Base(f.Path)] = f
+	}
+	if f := byBase["jquery.min.js"]; !f.Verified || f.CDNPath != "dist/jquery.min.js" {
+		t.Errorf("jquery.min.js = %+v, want verified against dist/jquery.min.js", f)
+	}
+	if f := byBase["jquery.slim.min.js"]; f.Verified || f.CDNPath != "dist/jquery.slim.min.js" {
+		t.Errorf("jquery.slim.min.js = %+v, want matched but not verified", f)
+	}
+	if f := byBase["plugins.js"]; f.CDNPath != "" {
+		t.Errorf("plugins.js = %+v, want no CDN match", f)
+	}
+
+	libConfig := c.libraryConfig()
+	if libConfig.Version != "3.7.1" || strings.Join(libConfig.Files, ",") != "dist/jquery.min.js,dist/jquery.slim.min.js" {
+		t.Errorf("libraryConfig() = %+v", libConfig)
+	}
+
+	missing := &adoptCandidate{Name: "mylib", Version: "1.0.0"}
+	verifyAdoptCandidate(missing, frontend_config.CDNUnpkg)
+	if missing.OnCDN || missing.CDNErr == nil {
+		t.Errorf("mylib should not be found on the CDN, got %+v", missing)
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct{ a, b, want string }{
+		{"static/vendor", "static/vendor", "static/vendor"},
+		{"static/vendor/css", "static/vendor/js", "static/vendor"},
+		{"static/vendor", "static/vendor/js", "static/vendor"},
+		{"static", "public", "."},
+	}
+	for _, tt := range tests {
+		if got := commonDir(tt.a, tt.b); got != tt.want {
+			t.Errorf("commonDir(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
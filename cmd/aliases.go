@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// settingPackageAliases is the settings key holding the user's package
+// aliases, a map from a well-known name to the published package name
+const settingPackageAliases = "aliases"
+
+// builtinPackageAliases maps names people commonly type to the npm package
+// that is actually published under a different name
+var builtinPackageAliases = map[string]string{
+	"alpine":      "alpinejs",
+	"animate":     "animate.css",
+	"chartjs":     "chart.js",
+	"datatables":  "datatables.net",
+	"fontawesome": "@fortawesome/fontawesome-free",
+	"highlightjs": "highlight.js",
+	"htmx":        "htmx.org",
+	"hyperscript": "hyperscript.org",
+	"mdi":         "@mdi/font",
+	"normalize":   "normalize.css",
+	"popper":      "@popperjs/core",
+	"sortable":    "sortablejs",
+	"stimulus":    "@hotwired/stimulus",
+	"tailwind":    "tailwindcss",
+	"threejs":     "three",
+	"turbo":       "@hotwired/turbo",
+}
+
+// packageAliases returns the built-in aliases merged with the user's. A user
+// alias replaces the built-in one with the same name; an empty package name
+// removes it.
+func packageAliases() map[string]string {
+	aliases := make(map[string]string, len(builtinPackageAliases))
+	for name, pkg := range builtinPackageAliases {
+		aliases[name] = pkg
+	}
+	for name, pkg := range viper.GetStringMapString(settingPackageAliases) {
+		name = strings.ToLower(name)
+		if pkg == "" {
+			delete(aliases, name)
+			continue
+		}
+		aliases[name] = pkg
+	}
+	return aliases
+}
+
+// resolvePackageAlias returns the package published for an alias. Names are
+// matched case-insensitively; ok is false when name isn't an alias.
+func resolvePackageAlias(name string) (pkg string, ok bool) {
+	pkg, ok = packageAliases()[strings.ToLower(name)]
+	if !ok || pkg == name {
+		return name, false
+	}
+	return pkg, true
+}
+
+// aliasSearchHint returns a hint pointing at the package an aliased query
+// refers to, or "" when the query isn't an alias or the package is already
+// among the results
+func aliasSearchHint(query string, results []frontend_mgr.SearchResult) string {
+	pkg, ok := resolvePackageAlias(query)
+	if !ok {
+		return ""
+	}
+	for _, r := range results {
+		if r.Name == pkg {
+			return ""
+		}
+	}
+	return fmt.Sprintf("'%s' is published as '%s' (smfaman add %s installs it)", query, pkg, query)
+}
+
+// printAliasSearchHint prints the hint for an aliased query, to stderr when
+// stdout carries machine-readable output
+func printAliasSearchHint(query string, results []frontend_mgr.SearchResult, toStderr bool) {
+	hint := aliasSearchHint(query, results)
+	if hint == "" {
+		return
+	}
+	if toStderr {
+		fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+		return
+	}
+	fmt.Printf("• %s\n\n", hint)
+}
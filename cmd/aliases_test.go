@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestResolvePackageAlias(t *testing.T) {
+	defer viper.Set(settingPackageAliases, nil)
+	viper.Set(settingPackageAliases, map[string]any{
+		"mylib":    "@acme/mylib",
+		"tailwind": "@acme/tailwind-build",
+		"alpine":   "",
+	})
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"fontawesome", "@fortawesome/fontawesome-free", true},
+		{"FontAwesome", "@fortawesome/fontawesome-free", true},
+		{"mylib", "@acme/mylib", true},
+		{"tailwind", "@acme/tailwind-build", true},
+		{"alpine", "alpine", false},
+		{"react", "react", false},
+		{"tailwindcss", "tailwindcss", false},
+	}
+	for _, tt := range tests {
+		got, ok := resolvePackageAlias(tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("resolvePackageAlias(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestAliasSearchHint(t *testing.T) {
+	if hint := aliasSearchHint("tailwind", nil); hint == "" {
+		t.Error("expected a hint for tailwind when tailwindcss isn't in the results")
+	}
+	results := []frontend_mgr.SearchResult{{Name: "tailwindcss"}}
+	if hint := aliasSearchHint("tailwind", results); hint != "" {
+		t.Errorf("hint = %q, want none when tailwindcss is in the results", hint)
+	}
+	if hint := aliasSearchHint("react", nil); hint != "" {
+		t.Errorf("hint = %q, want none for a name that isn't an alias", hint)
+	}
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+var analyzeJSON bool
+
+// duplicateCopy is one occurrence of duplicated content
+type duplicateCopy struct {
+	Library string `json:"library"`
+	Path    string `json:"path"` // Path relative to the library destination
+}
+
+// duplicateGroup is a set of identical files found in more than one library
+type duplicateGroup struct {
+	Hash   string          `json:"sha256"`
+	Size   int64           `json:"size"`
+	Copies []duplicateCopy `json:"copies"`
+}
+
+// wastedBytes returns the bytes used by all but one copy
+func (g duplicateGroup) wastedBytes() int64 {
+	return g.Size * int64(len(g.Copies)-1)
+}
+
+// analyzeCmd represents the analyze command
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Find duplicate files vendored by more than one library",
+	Long: `Scan the vendored files of every library and report files with identical
+content that appear in more than one library.
+
+Some packages bundle copies of others (for example a plugin that ships its
+own jquery.min.js). For each duplicate, the report shows its size and where
+the copies live, and suggests which library's files filter could skip it.
+
+Examples:
+  smfaman analyze
+  smfaman analyze --json
+  smfaman -f myproject.yaml analyze`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runAnalyze(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.Flags().BoolVar(&analyzeJSON, "json", false, "Output duplicates as JSON")
+}
+
+// runAnalyze executes the analyze command
+func runAnalyze() error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(config.Libraries) == 0 {
+		fmt.Println("No libraries defined in configuration.")
+		return nil
+	}
+
+	groups, err := findDuplicateFiles(config)
+	if err != nil {
+		return err
+	}
+
+	if analyzeJSON {
+		if groups == nil {
+			groups = []duplicateGroup{}
+		}
+		data, err := json.MarshalIndent(groups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("✓ No duplicate files found across libraries.")
+		return nil
+	}
+
+	printDuplicateReport(groups)
+	return nil
+}
+
+// findDuplicateFiles hashes every vendored file and returns content that
+// appears in more than one library, largest waste first
+func findDuplicateFiles(config *frontend_config.FrontendConfig) ([]duplicateGroup, error) {
+	type hashedFile struct {
+		copy duplicateCopy
+		size int64
+	}
+
+	byHash := make(map[string][]hashedFile)
+	scanned := make(map[string]bool)
+
+	for _, libName := range sortedKeys(config.Libraries) {
+		destPath, err := config.GetLibraryDestination(libName, config.Libraries[libName])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get destination for %s: %w", libName, err)
+		}
+
+		if info, err := os.Stat(destPath); err != nil || !info.IsDir() {
+			continue
+		}
+
+		err = filepath.WalkDir(destPath, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Files in shared destinations are attributed to the first library only
+			if d.IsDir() || d.Name() == destinationMarkerName || scanned[filePath] {
+				return nil
+			}
+			scanned[filePath] = true
+
+			hash, size, err := hashFile(filePath)
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(destPath, filePath)
+			if err != nil {
+				return err
+			}
+
+			byHash[hash] = append(byHash[hash], hashedFile{
+				copy: duplicateCopy{Library: libName, Path: filepath.ToSlash(rel)},
+				size: size,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", libName, err)
+		}
+	}
+
+	var groups []duplicateGroup
+	for hash, files := range byHash {
+		libraries := make(map[string]bool)
+		for _, f := range files {
+			libraries[f.copy.Library] = true
+		}
+		// Skip empty files and content duplicated only within a single library
+		if len(libraries) < 2 || files[0].size == 0 {
+			continue
+		}
+
+		group := duplicateGroup{Hash: hash, Size: files[0].size}
+		for _, f := range files {
+			group.Copies = append(group.Copies, f.copy)
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].wastedBytes() != groups[j].wastedBytes() {
+			return groups[i].wastedBytes() > groups[j].wastedBytes()
+		}
+		return groups[i].Hash < groups[j].Hash
+	})
+
+	return groups, nil
+}
+
+// hashFile returns the hex SHA-256 and size of a file
+func hashFile(filePath string) (string, int64, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// duplicateOwner guesses which library a duplicated file belongs to: the
+// library whose name matches the file name, otherwise the first one listed
+func duplicateOwner(group duplicateGroup) string {
+	for _, c := range group.Copies {
+		base := strings.ToLower(path.Base(c.Path))
+		name := strings.ToLower(c.Library[strings.LastIndex(c.Library, "/")+1:])
+		if strings.HasPrefix(base, name+".") {
+			return c.Library
+		}
+	}
+	return group.Copies[0].Library
+}
+
+// printDuplicateReport prints duplicate groups and files filter suggestions
+func printDuplicateReport(groups []duplicateGroup) {
+	var totalWasted int64
+	suggestions := make(map[string][]string)
+
+	fmt.Printf("Found %d duplicated %s across libraries:\n\n", len(groups), pluralize(len(groups), "file", "files"))
+	for _, g := range groups {
+		totalWasted += g.wastedBytes()
+		owner := duplicateOwner(g)
+
+		fmt.Printf("  • %s × %d copies (%s wasted)\n", formatBytes(g.Size), len(g.Copies), formatBytes(g.wastedBytes()))
+		for _, c := range g.Copies {
+			marker := ""
+			if c.Library == owner {
+				marker = " (likely owner)"
+			} else {
+				suggestions[c.Library] = append(suggestions[c.Library], c.Path)
+			}
+			fmt.Printf("      %s: %s%s\n", c.Library, c.Path, marker)
+		}
+	}
+
+	fmt.Printf("\nPotential savings: %s\n", formatBytes(totalWasted))
+
+	fmt.Println("\nSuggestions:")
+	for _, libName := range sortedKeys(suggestions) {
+		paths := suggestions[libName]
+		sort.Strings(paths)
+		fmt.Printf("  • %s: set a files filter that leaves out %s\n", libName, strings.Join(paths, ", "))
+	}
+}
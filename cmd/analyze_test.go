@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func TestFindDuplicateFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	libs := filepath.Join(tmpDir, "libs")
+
+	jqueryContent := "/*! jQuery v3.7.1 */ window.jQuery = {};"
+	writeTestFile(t, filepath.Join(libs, "jquery", "dist", "jquery.min.js"), jqueryContent)
+	writeTestFile(t, filepath.Join(libs, "datatables", "vendor", "jquery.min.js"), jqueryContent)
+	writeTestFile(t, filepath.Join(libs, "datatables", "js", "datatables.min.js"), "datatables")
+	// Identical content within one library is not reported
+	writeTestFile(t, filepath.Join(libs, "bootstrap", "a.css"), "same")
+	writeTestFile(t, filepath.Join(libs, "bootstrap", "b.css"), "same")
+
+	config := &frontend_config.FrontendConfig{
+		Destination: filepath.Join(libs, "{library_name}"),
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":     {Version: "3.7.1"},
+			"datatables": {Version: "1.13.0"},
+			"bootstrap":  {Version: "5.3.0"},
+			"missing":    {Version: "1.0.0"},
+		},
+	}
+
+	groups, err := findDuplicateFiles(config)
+	if err != nil {
+		t.Fatalf("findDuplicateFiles failed: %v", err)
+	}
+
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 duplicate group, got %d: %+v", len(groups), groups)
+	}
+
+	group := groups[0]
+	if group.Size != int64(len(jqueryContent)) {
+		t.Errorf("expected size %d, got %d", len(jqueryContent), group.Size)
+	}
+	if group.wastedBytes() != int64(len(jqueryContent)) {
+		t.Errorf("expected %d wasted bytes, got %d", len(jqueryContent), group.wastedBytes())
+	}
+	if len(group.Copies) != 2 {
+		t.Fatalf("expected 2 copies, got %d", len(group.Copies))
+	}
+
+	if owner := duplicateOwner(group); owner != "jquery" {
+		t.Errorf("expected jquery to own the duplicate, got %s", owner)
+	}
+}
+
+func TestDuplicateOwnerFallsBackToFirstCopy(t *testing.T) {
+	group := duplicateGroup{Copies: []duplicateCopy{
+		{Library: "alpha", Path: "shared/util.js"},
+		{Library: "beta", Path: "lib/util.js"},
+	}}
+
+	if owner := duplicateOwner(group); owner != "alpha" {
+		t.Errorf("expected alpha, got %s", owner)
+	}
+
+	scoped := duplicateGroup{Copies: []duplicateCopy{
+		{Library: "other", Path: "vendor/core.js"},
+		{Library: "@acme/core", Path: "dist/core.js"},
+	}}
+	if owner := duplicateOwner(scoped); owner != "@acme/core" {
+		t.Errorf("expected @acme/core, got %s", owner)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply [plan.json]",
+	Short: "Download the files listed in a plan",
+	Long: `Execute a plan previously written by 'smfaman plan'.
+
+Exactly the files listed in the plan are downloaded to their recorded
+destinations; the configuration file is not consulted. If no plan file is
+given, smfaman-plan.json is used.
+
+Examples:
+  smfaman apply
+  smfaman apply release.plan.json
+  smfaman apply release.plan.json --json > sync-summary.json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		planFile := defaultPlanFile
+		if len(args) > 0 {
+			planFile = args[0]
+		}
+		if err := runApply(planFile); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().BoolVar(&syncNoPackageCache, "no-package-cache", false, "Disable package caching and download directly")
+	applyCmd.Flags().BoolVar(&syncJSON, "json", false, "Print the sync summary as JSON")
+	applyCmd.Flags().StringVar(&syncProgressJSON, "progress-json", "", "Stream JSON progress events to stdout, or to a Unix socket path")
+	applyCmd.Flags().Lookup("progress-json").NoOptDefVal = progressStdout
+	applyCmd.Flags().IntVar(&syncRetries, "retries", 3, "Number of retries for transient download failures")
+	applyCmd.Flags().DurationVar(&syncRetryBackoff, "retry-backoff", time.Second, "Initial delay between download retries")
+}
+
+// runApply executes the apply command
+func runApply(planFile string) error {
+	plan, err := loadSyncPlan(planFile)
+	if err != nil {
+		return err
+	}
+	if err := loadRequestOptions(plan.Config, plan.Tasks); err != nil {
+		return err
+	}
+
+	out, err := syncOutput()
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Tasks) == 0 {
+		fmt.Fprintln(out, "✓ Plan has nothing to download.")
+		return writeEmptySyncSummary()
+	}
+
+	fmt.Fprintf(out, "Applying plan %s (created %s)\n", planFile, plan.CreatedAt.Local().Format(time.DateTime))
+	fmt.Fprintf(out, "Files to download: %d\n\n", len(plan.Tasks))
+
+	return executeDownloadTasks(plan.Config, plan.Tasks, out)
+}
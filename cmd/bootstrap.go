@@ -1,31 +1,120 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
+var (
+	bootstrapDirectory string
+	bootstrapSHA256    string
+)
+
 // bootstrapCmd represents the bootstrap command
 var bootstrapCmd = &cobra.Command{
-	Use:   "bootstrap",
+	Use:   "bootstrap [kit]",
 	Short: "Bootstrap new projects from various frameworks",
 	Long: `Bootstrap new projects by downloading and setting up starter kits
 from various frontend frameworks and libraries.
 
-This command provides subcommands for different frameworks, each of which
-will download the appropriate starter kit, extract it, and set up a new
-project in your current directory.
+'smfaman bootstrap <kit>' downloads the named starter kit, verifies its
+checksum and extracts it into the target directory. Kits without a pinned
+checksum, like the built-in ones, are extracted with a warning showing the
+download's sha256; pass --sha256 to have it checked.
+Run 'smfaman bootstrap list' to see the available kits.
+
+Built-in kits:
+  xmlui          - XMLUI project (declarative XML-based UI framework)
+  htmx           - HTMX project (hypermedia-driven web applications)
+  bootstrap-site - Official Bootstrap examples
 
-Available frameworks:
-  xmlui - Bootstrap a new XMLUI project (declarative XML-based UI framework)
-  htmx  - Bootstrap a new HTMX project (hypermedia-driven web applications)
+More kits can be added (or built-in ones replaced) with a starter_kits list
+in the settings file:
 
-Example:
-  smfaman bootstrap xmlui
-  smfaman bootstrap xmlui --directory my-xmlui-app
+  starter_kits:
+    - name: alpine
+      title: Alpine.js
+      description: Alpine.js starter page
+      url: https://example.com/alpine-starter.zip
+      sha256: <checksum of the archive>
+      subdir: alpine-starter-main   # extract only this folder
+      start: npx serve .            # optional dev server command
+
+Examples:
+  smfaman bootstrap list
   smfaman bootstrap htmx
-  smfaman bootstrap htmx --directory my-htmx-app`,
+  smfaman bootstrap bootstrap-site --directory my-site
+  smfaman bootstrap alpine --sha256 <checksum>
+  smfaman bootstrap xmlui --directory my-xmlui-app`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Help()
+			return
+		}
+		if err := runBootstrapKit(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// bootstrapListCmd lists the available starter kits
+var bootstrapListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available starter kits",
+	Long: `List the built-in starter kits and those added through the starter_kits
+setting.
+
+Examples:
+  smfaman bootstrap list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := listStarterKits(); err != nil {
+			exitWithError(err)
+		}
+	},
 }
 
 func init() {
 	rootCmd.AddCommand(bootstrapCmd)
+	bootstrapCmd.AddCommand(bootstrapListCmd)
+	bootstrapCmd.Flags().StringVarP(&bootstrapDirectory, "directory", "d", ".", "Directory to extract the starter kit (default: current directory)")
+	bootstrapCmd.PersistentFlags().StringVar(&bootstrapSHA256, "sha256", "", "Expected SHA-256 of the kit archive (overrides the pinned checksum)")
+}
+
+// runBootstrapKit bootstraps a project from the named starter kit
+func runBootstrapKit(name string) error {
+	kit, err := findStarterKit(name)
+	if err != nil {
+		return err
+	}
+	return runStarterKit(kit, bootstrapDirectory, bootstrapSHA256)
+}
+
+// listStarterKits prints the available starter kits
+func listStarterKits() error {
+	kits, err := starterKits()
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, kit := range kits {
+		width = max(width, len(kit.Name))
+	}
+
+	fmt.Printf("Available starter kits (%d):\n\n", len(kits))
+	for _, kit := range kits {
+		source := "catalog"
+		if kit.builtin {
+			source = "built-in"
+		}
+		checksum := ", checksum pinned"
+		if kit.SHA256 == "" {
+			checksum = ", no checksum"
+		}
+		fmt.Printf("  %s  %s (%s%s)\n", padRight(kit.Name, width), kit.Description, source, checksum)
+	}
+	fmt.Println("\nRun 'smfaman bootstrap <kit>' to create a project.")
+	return nil
 }
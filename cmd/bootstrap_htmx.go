@@ -1,11 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
-	"runtime"
-
 	"github.com/spf13/cobra"
 )
 
@@ -38,8 +33,7 @@ Example:
   smfaman bootstrap htmx --directory my-htmx-app`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runBootstrapHtmx(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -57,47 +51,9 @@ const (
 
 // runBootstrapHtmx executes the HTMX bootstrap command
 func runBootstrapHtmx() error {
-	fmt.Println("🚀 Bootstrapping HTMX project...")
-	fmt.Printf("📦 Downloading HTMX starter kit from: %s\n", htmxStarterKitURL)
-
-	// Create target directory if it doesn't exist
-	if err := os.MkdirAll(htmxDirectory, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Download the starter kit
-	zipPath := filepath.Join(os.TempDir(), "htmx-starter.zip")
-	if err := downloadZipFile(htmxStarterKitURL, zipPath); err != nil {
-		return fmt.Errorf("failed to download starter kit: %w", err)
-	}
-	defer os.Remove(zipPath) // Clean up temp file
-
-	fmt.Println("📂 Extracting files...")
-
-	// Extract the zip file
-	if err := extractZip(zipPath, htmxDirectory); err != nil {
-		return fmt.Errorf("failed to extract starter kit: %w", err)
+	kit, err := findStarterKit("htmx")
+	if err != nil {
+		return err
 	}
-
-	fmt.Println("\n✅ HTMX project bootstrapped successfully!")
-	fmt.Printf("\n📁 Project location: %s\n", htmxDirectory)
-	fmt.Println("\n🎯 Next steps:")
-	fmt.Println("   1. Navigate to the project directory:")
-
-	if htmxDirectory != "." {
-		fmt.Printf("      cd %s\n", htmxDirectory)
-	}
-
-	fmt.Println("\n   2. Start the development server:")
-	if runtime.GOOS == "windows" {
-		fmt.Println("      start.bat")
-	} else {
-		fmt.Println("      ./start.sh")
-	}
-
-	fmt.Println("\n   3. Open your browser to the URL shown by the server (typically http://localhost:8080)")
-	fmt.Println("\n📚 Documentation: https://htmx.org/docs")
-	fmt.Println("🎨 Examples: https://htmx.org/examples")
-
-	return nil
+	return runStarterKit(kit, htmxDirectory, bootstrapSHA256)
 }
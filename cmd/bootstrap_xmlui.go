@@ -4,10 +4,9 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
-	"runtime"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -40,8 +39,7 @@ Example:
   smfaman bootstrap xmlui --directory my-xmlui-app`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runBootstrapXmlui(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -57,88 +55,46 @@ const (
 
 // runBootstrapXmlui executes the XMLUI bootstrap command
 func runBootstrapXmlui() error {
-	fmt.Println("🚀 Bootstrapping XMLUI project...")
-	fmt.Printf("📦 Downloading XMLUI starter kit from: %s\n", xmluiStarterKitURL)
-
-	// Create target directory if it doesn't exist
-	if err := os.MkdirAll(xmluiDirectory, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Download the starter kit
-	zipPath := filepath.Join(os.TempDir(), "xmlui-invoice.zip")
-	if err := downloadZipFile(xmluiStarterKitURL, zipPath); err != nil {
-		return fmt.Errorf("failed to download starter kit: %w", err)
-	}
-	defer os.Remove(zipPath) // Clean up temp file
-
-	fmt.Println("📂 Extracting files...")
-
-	// Extract the zip file
-	if err := extractZip(zipPath, xmluiDirectory); err != nil {
-		return fmt.Errorf("failed to extract starter kit: %w", err)
-	}
-
-	fmt.Println("\n✅ XMLUI project bootstrapped successfully!")
-	fmt.Printf("\n📁 Project location: %s\n", xmluiDirectory)
-	fmt.Println("\n🎯 Next steps:")
-	fmt.Println("   1. Navigate to the project directory:")
-
-	if xmluiDirectory != "." {
-		fmt.Printf("      cd %s\n", xmluiDirectory)
-	}
-
-	fmt.Println("\n   2. Start the development server:")
-	if runtime.GOOS == "windows" {
-		fmt.Println("      start.bat")
-	} else {
-		fmt.Println("      ./start.sh")
-	}
-
-	fmt.Println("\n   3. Open your browser to the URL shown by the server")
-	fmt.Println("\n📚 Documentation: https://docs.xmlui.org")
-	fmt.Println("🎨 Demo & Gallery: https://demo.xmlui.org")
-
-	return nil
-}
-
-// downloadZipFile downloads a file from a URL to a local path
-func downloadZipFile(url, filepath string) error {
-	// Create the file
-	out, err := os.Create(filepath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	// Download the file
-	resp, err := http.Get(url)
+	kit, err := findStarterKit("xmlui")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	// Check server response
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %s", resp.Status)
-	}
-
-	// Show download progress
-	fmt.Printf("⬇️  Downloading... ")
+	return runStarterKit(kit, xmluiDirectory, bootstrapSHA256)
+}
 
-	// Write the body to file
-	written, err := io.Copy(out, resp.Body)
-	if err != nil {
-		return err
+// downloadZipFile downloads a file from a URL to a local path, showing a
+// progress line on terminals. An interrupted download resumes from its
+// partial file on the next call.
+func downloadZipFile(url, filepath string) error {
+	if !isTerminal(os.Stdout) {
+		fmt.Printf("⬇️  Downloading... ")
+		if err := downloadToFile(url, filepath, nil); err != nil {
+			fmt.Println()
+			return err
+		}
+		info, err := os.Stat(filepath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%.2f MB downloaded\n", float64(info.Size())/(1024*1024))
+		return nil
 	}
 
-	fmt.Printf("%.2f MB downloaded\n", float64(written)/(1024*1024))
-
-	return nil
+	progress, finish := terminalProgress(os.Stdout)
+	err := downloadToFile(url, filepath, progress)
+	finish()
+	return err
 }
 
 // extractZip extracts a zip archive to a destination directory
 func extractZip(zipPath, destPath string) error {
+	return extractZipSubdir(zipPath, "", destPath)
+}
+
+// extractZipSubdir extracts the entries under subdir of a zip archive to a
+// destination directory, dropping the subdir prefix. An empty subdir
+// extracts everything.
+func extractZipSubdir(zipPath, subdir, destPath string) error {
 	// Open the zip file
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
@@ -146,15 +102,30 @@ func extractZip(zipPath, destPath string) error {
 	}
 	defer reader.Close()
 
+	prefix := ""
+	if subdir = strings.Trim(subdir, "/"); subdir != "" {
+		prefix = subdir + "/"
+	}
+
 	// Extract each file
+	extracted := 0
 	for _, file := range reader.File {
+		name := file.Name
+		if prefix != "" {
+			if !strings.HasPrefix(name, prefix) || name == prefix {
+				continue
+			}
+			name = strings.TrimPrefix(name, prefix)
+		}
+
 		// Construct the full path
-		path := filepath.Join(destPath, file.Name)
+		path := filepath.Join(destPath, name)
 
 		// Check for ZipSlip vulnerability
 		if !filepath.HasPrefix(path, filepath.Clean(destPath)+string(os.PathSeparator)) {
 			return fmt.Errorf("illegal file path: %s", path)
 		}
+		extracted++
 
 		if file.FileInfo().IsDir() {
 			// Create directory
@@ -192,5 +163,9 @@ func extractZip(zipPath, destPath string) error {
 		}
 	}
 
+	if prefix != "" && extracted == 0 {
+		return fmt.Errorf("archive has no folder '%s'", subdir)
+	}
+
 	return nil
 }
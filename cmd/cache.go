@@ -5,9 +5,15 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
 )
 
+var (
+	cacheVerifyRedownload bool
+	refreshCache          bool
+)
+
 // cacheCmd represents the cache command
 var cacheCmd = &cobra.Command{
 	Use:   "cache",
@@ -22,7 +28,8 @@ Subcommands:
   stats          - Show cache statistics
   clear          - Clear all cached data (metadata and packages)
   clear-packages - Clear only cached package files
-  clean          - Remove expired metadata cache entries`,
+  clean          - Remove expired metadata cache entries
+  verify         - Check cache entries and remove corrupt ones`,
 }
 
 // cacheStatsCmd shows cache statistics
@@ -75,6 +82,13 @@ var cacheStatsCmd = &cobra.Command{
 	},
 }
 
+// initCache applies the global --no-cache/--refresh flag and the cache_ttl
+// setting to the cache manager
+func initCache() {
+	frontend_mgr.CacheManager.SetRefresh(refreshCache)
+	frontend_mgr.CacheManager.SetTTL(settingsCacheTTL())
+}
+
 // formatBytes formats byte count to human-readable format
 func formatBytes(bytes int64) string {
 	const unit = 1024
@@ -177,10 +191,84 @@ Use 'clear-packages' to remove cached package files.`,
 	},
 }
 
+// cacheVerifyCmd checks cache integrity
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cache entries and remove corrupt ones",
+	Long: `Validate every metadata cache entry and cached package file.
+
+Metadata entries that can't be parsed (e.g., truncated by a full disk or a
+crash) are removed. Package files are checked against the SHA-256 hash
+recorded when they were cached; files that no longer match are removed, or
+downloaded again with --redownload.
+
+Examples:
+  smfaman cache verify
+  smfaman cache verify --redownload`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCacheVerify(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// runCacheVerify executes the cache verify command
+func runCacheVerify() error {
+	result, err := frontend_mgr.CacheManager.Verify()
+	if err != nil {
+		return fmt.Errorf("failed to verify cache: %w", err)
+	}
+
+	fmt.Printf("Metadata entries:   %d checked, %d removed\n", result.MetadataChecked, result.MetadataRemoved)
+	fmt.Printf("Package files:      %d checked, %d corrupt", result.PackagesChecked, len(result.PackagesCorrupt))
+	if result.PackagesUnverified > 0 {
+		fmt.Printf(", %d without a stored hash", result.PackagesUnverified)
+	}
+	fmt.Println()
+
+	if len(result.PackagesCorrupt) == 0 {
+		fmt.Println("\n✓ Cache is healthy")
+		return nil
+	}
+
+	fmt.Println()
+	failed := 0
+	for _, ref := range result.PackagesCorrupt {
+		name := fmt.Sprintf("%s %s@%s/%s", ref.CDN, ref.Library, ref.Version, ref.FilePath)
+		if !cacheVerifyRedownload {
+			fmt.Printf("  ✗ %s (removed)\n", name)
+			continue
+		}
+
+		url := cdnFileURL(ref.Library, ref.Version, frontend_config.CDN(ref.CDN), ref.FilePath)
+		data, err := downloadFileToMemory(url)
+		if err == nil {
+			err = frontend_mgr.CacheManager.SetPackageFile(ref.CDN, ref.Library, ref.Version, ref.FilePath, data)
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("  ✗ %s: %v\n", name, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s (re-downloaded)\n", name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to re-download %d %s", failed, pluralize(failed, "file", "files"))
+	}
+	if !cacheVerifyRedownload {
+		fmt.Println("\nCorrupt files will be downloaded again on the next sync.")
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(cacheCmd)
 	cacheCmd.AddCommand(cacheStatsCmd)
 	cacheCmd.AddCommand(cacheClearCmd)
 	cacheCmd.AddCommand(cacheClearPackagesCmd)
 	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+
+	cacheVerifyCmd.Flags().BoolVar(&cacheVerifyRedownload, "redownload", false, "Download corrupt package files again")
 }
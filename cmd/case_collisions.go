@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// caseCollision is a file whose local path differs only by case from one
+// kept earlier in the same library
+type caseCollision struct {
+	Path      string // Path on CDN
+	Collides  string // Path on CDN of the file that keeps its name
+	LocalPath string // Path it is saved under; "" when skipped
+}
+
+// resolveCaseCollisions finds files of a library whose local paths differ
+// only by case (or not at all, once strip_prefix is applied). Those
+// overwrite each other on case-insensitive filesystems (macOS, Windows), so
+// they are handled on every system to give the same tree everywhere: the
+// first local path in sorted order keeps its name and the others are
+// renamed or skipped by the strategy. localPaths maps each file's CDN path
+// to its path in the library folder and receives the new paths of renamed
+// files. It returns the files to download.
+func resolveCaseCollisions(files []CDNFile, localPaths map[string]string, strategy frontend_config.CaseCollisions) ([]CDNFile, []caseCollision) {
+	sorted := append([]CDNFile(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := localPaths[sorted[i].Path], localPaths[sorted[j].Path]
+		if a != b {
+			return a < b
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	taken := make(map[string]string, len(files)) // lowercase local path → CDN path
+	for _, file := range sorted {
+		if _, ok := taken[strings.ToLower(localPaths[file.Path])]; !ok {
+			taken[strings.ToLower(localPaths[file.Path])] = file.Path
+		}
+	}
+
+	var collisions []caseCollision
+	skipped := make(map[string]bool)
+	for _, file := range sorted {
+		first := taken[strings.ToLower(localPaths[file.Path])]
+		if first == file.Path {
+			continue
+		}
+
+		collision := caseCollision{Path: file.Path, Collides: first}
+		if strategy == frontend_config.CaseCollisionsSkip {
+			skipped[file.Path] = true
+		} else {
+			collision.LocalPath = numberedPath(localPaths[file.Path], taken)
+			taken[strings.ToLower(collision.LocalPath)] = file.Path
+			localPaths[file.Path] = collision.LocalPath
+		}
+		collisions = append(collisions, collision)
+	}
+
+	if len(skipped) == 0 {
+		return files, collisions
+	}
+	return slices.DeleteFunc(slices.Clone(files), func(file CDNFile) bool { return skipped[file.Path] }), collisions
+}
+
+// numberedPath returns the first of name~2.ext, name~3.ext, ... whose
+// lowercase form isn't taken
+func numberedPath(filePath string, taken map[string]string) string {
+	dir, base := path.Split(filePath)
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s%s~%d%s", dir, stem, n, ext)
+		if _, ok := taken[strings.ToLower(candidate)]; !ok {
+			return candidate
+		}
+	}
+}
+
+// reportCaseCollisions warns about a library's case collisions, or returns
+// an error for them with the error strategy
+func reportCaseCollisions(w io.Writer, libName string, collisions []caseCollision, strategy frontend_config.CaseCollisions) error {
+	if len(collisions) == 0 {
+		return nil
+	}
+	if strategy == frontend_config.CaseCollisionsError {
+		c := collisions[0]
+		return fmt.Errorf("%s: %s and %s would overwrite each other (set case_collisions to rename or skip to sync it)", libName, c.Collides, c.Path)
+	}
+
+	for _, c := range collisions {
+		if c.LocalPath == "" {
+			fmt.Fprintf(w, "⚠ %s: %s and %s would overwrite each other; skipping %s\n", libName, c.Collides, c.Path, c.Path)
+		} else {
+			fmt.Fprintf(w, "⚠ %s: %s and %s would overwrite each other; saving %s as %s\n", libName, c.Collides, c.Path, c.Path, c.LocalPath)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestResolveCaseCollisions(t *testing.T) {
+	files := []CDNFile{
+		{Path: "readme.md"},
+		{Path: "dist/app.js"},
+		{Path: "README.md"},
+		{Path: "Readme~2.md"},
+		{Path: "Dist/app.js"},
+	}
+
+	localPaths := localFilePaths(files, "")
+	kept, collisions := resolveCaseCollisions(files, localPaths, frontend_config.CaseCollisionsRename)
+	if len(kept) != len(files) || kept[0].Path != "readme.md" {
+		t.Errorf("rename should keep every file in order, got %v", kept)
+	}
+	want := map[string]string{
+		"readme.md":   "readme~3.md", // readme~2.md is taken by Readme~2.md
+		"dist/app.js": "dist/app~2.js",
+		"README.md":   "README.md",
+		"Readme~2.md": "Readme~2.md",
+		"Dist/app.js": "Dist/app.js",
+	}
+	for path, local := range want {
+		if localPaths[path] != local {
+			t.Errorf("%s saved as %q, want %q", path, localPaths[path], local)
+		}
+	}
+	if len(collisions) != 2 || collisions[0].Collides != "Dist/app.js" || collisions[1].Collides != "README.md" {
+		t.Errorf("collisions = %+v", collisions)
+	}
+
+	kept, collisions = resolveCaseCollisions(files, localFilePaths(files, ""), frontend_config.CaseCollisionsSkip)
+	if len(kept) != 3 || len(collisions) != 2 {
+		t.Errorf("skip kept %v with collisions %v", kept, collisions)
+	}
+
+	files = []CDNFile{{Path: "a.js"}, {Path: "b.js"}}
+	if _, collisions = resolveCaseCollisions(files, localFilePaths(files, ""), ""); len(collisions) != 0 {
+		t.Errorf("expected no collisions, got %v", collisions)
+	}
+}
+
+func TestResolveCaseCollisionsAfterStripPrefix(t *testing.T) {
+	files := []CDNFile{{Path: "dist/app.js"}, {Path: "app.js"}}
+	localPaths := localFilePaths(files, "dist")
+	if _, collisions := resolveCaseCollisions(files, localPaths, ""); len(collisions) != 1 {
+		t.Fatalf("expected one collision, got %v", collisions)
+	}
+	if localPaths["app.js"] != "app.js" || localPaths["dist/app.js"] != "app~2.js" {
+		t.Errorf("local paths = %v", localPaths)
+	}
+}
+
+func TestReportCaseCollisions(t *testing.T) {
+	collisions := []caseCollision{
+		{Path: "readme.md", Collides: "README.md", LocalPath: "readme~2.md"},
+		{Path: "LICENSE.txt", Collides: "License.txt"},
+	}
+
+	var out bytes.Buffer
+	if err := reportCaseCollisions(&out, "pkg", collisions, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"saving readme.md as readme~2.md", "skipping LICENSE.txt"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	err := reportCaseCollisions(&out, "pkg", collisions, frontend_config.CaseCollisionsError)
+	if err == nil || !strings.Contains(err.Error(), "README.md and readme.md would overwrite each other") {
+		t.Errorf("expected a collision error, got %v", err)
+	}
+}
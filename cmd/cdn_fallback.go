@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// versionFetcher fetches the sorted versions and latest version of a package from a CDN
+type versionFetcher func(packageName string, cdn frontend_config.CDN) ([]string, string, error)
+
+// probeCDNVersions is used to probe alternate CDNs (overridable in tests)
+var probeCDNVersions versionFetcher = fetchVersionsForUpgrade
+
+// fallbackCDNs is the order in which alternate CDNs are probed
+var fallbackCDNs = []frontend_config.CDN{
+	frontend_config.CDNUnpkg,
+	frontend_config.CDNJsdelivr,
+	frontend_config.CDNCdnjs,
+}
+
+// fetchVersionsWithFallback fetches versions from cdn. When the package (or
+// the wanted version, if given) isn't available there, the other CDNs are
+// probed and the user is offered the first one that has it; with auto set
+// it is used without asking. The CDN the versions came from is returned.
+func fetchVersionsWithFallback(packageName, version string, cdn frontend_config.CDN, fetch versionFetcher, auto bool) ([]string, string, frontend_config.CDN, error) {
+	versions, latest, err := fetch(packageName, cdn)
+	if err == nil && (version == "" || hasVersion(versions, version)) {
+		return versions, latest, cdn, nil
+	}
+	if err == nil {
+		err = notFoundError(fmt.Errorf("version '%s' not found for package '%s' on %s", version, packageName, cdn))
+	}
+
+	alt, altVersions, altLatest := findAlternateCDN(packageName, version, cdn)
+	if alt == "" {
+		return nil, "", cdn, err
+	}
+
+	fmt.Printf("⚠ %s is not available on %s, but was found on %s\n", packageSpecString(packageName, version), cdn, alt)
+	if !auto && !promptConfirmation(fmt.Sprintf("Use %s for %s instead?", alt, packageName)) {
+		return nil, "", cdn, err
+	}
+
+	fmt.Printf("✓ Using %s for %s\n", alt, packageName)
+	return altVersions, altLatest, alt, nil
+}
+
+// findAlternateCDN returns the first CDN other than current that has the
+// package (and version, if given), or "" if none does
+func findAlternateCDN(packageName, version string, current frontend_config.CDN) (frontend_config.CDN, []string, string) {
+	for _, cdn := range fallbackCDNs {
+		if cdn == current || (cdn == frontend_config.CDNCdnjs && frontend_mgr.IsScopedPackage(packageName)) {
+			continue
+		}
+
+		versions, latest, err := probeCDNVersions(packageName, cdn)
+		if err != nil || (version != "" && !hasVersion(versions, version)) {
+			continue
+		}
+		return cdn, versions, latest
+	}
+	return "", nil, ""
+}
+
+// hasVersion reports whether version is among versions, ignoring a "v" prefix
+func hasVersion(versions []string, version string) bool {
+	_, ok := frontend_mgr.FindVersion(versions, version)
+	return ok
+}
+
+// packageSpecString formats a package name with an optional version
+func packageSpecString(packageName, version string) string {
+	if version == "" {
+		return packageName
+	}
+	return packageName + "@" + version
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestFetchVersionsWithFallback(t *testing.T) {
+	// Package availability per CDN
+	available := map[frontend_config.CDN][]string{
+		frontend_config.CDNUnpkg:    {"1.0.0", "2.0.0"},
+		frontend_config.CDNJsdelivr: {"1.0.0", "2.0.0"},
+	}
+	fetch := func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		versions, ok := available[cdn]
+		if !ok {
+			return nil, "", errors.New("package not found")
+		}
+		return versions, versions[len(versions)-1], nil
+	}
+
+	origProbe := probeCDNVersions
+	origAssumeYes := assumeYes
+	origInput := confirmationInput
+	t.Setenv("SMFAMAN_ASSUME_YES", "")
+	defer func() {
+		probeCDNVersions = origProbe
+		assumeYes = origAssumeYes
+		confirmationInput = origInput
+	}()
+	probeCDNVersions = fetch
+	assumeYes = false
+
+	t.Run("available on selected CDN", func(t *testing.T) {
+		_, latest, cdn, err := fetchVersionsWithFallback("pkg", "", frontend_config.CDNJsdelivr, fetch, false)
+		if err != nil || cdn != frontend_config.CDNJsdelivr || latest != "2.0.0" {
+			t.Errorf("got cdn=%s latest=%s err=%v", cdn, latest, err)
+		}
+	})
+
+	t.Run("auto switches CDN", func(t *testing.T) {
+		_, latest, cdn, err := fetchVersionsWithFallback("pkg", "", frontend_config.CDNCdnjs, fetch, true)
+		if err != nil || cdn != frontend_config.CDNUnpkg || latest != "2.0.0" {
+			t.Errorf("got cdn=%s latest=%s err=%v", cdn, latest, err)
+		}
+	})
+
+	t.Run("prompt accepted", func(t *testing.T) {
+		confirmationInput = strings.NewReader("y\n")
+		_, _, cdn, err := fetchVersionsWithFallback("pkg", "1.0.0", frontend_config.CDNCdnjs, fetch, false)
+		if err != nil || cdn != frontend_config.CDNUnpkg {
+			t.Errorf("got cdn=%s err=%v", cdn, err)
+		}
+	})
+
+	t.Run("prompt declined keeps original error", func(t *testing.T) {
+		confirmationInput = strings.NewReader("n\n")
+		_, _, cdn, err := fetchVersionsWithFallback("pkg", "", frontend_config.CDNCdnjs, fetch, false)
+		if err == nil || !strings.Contains(err.Error(), "package not found") || cdn != frontend_config.CDNCdnjs {
+			t.Errorf("got cdn=%s err=%v", cdn, err)
+		}
+	})
+
+	t.Run("missing version falls back", func(t *testing.T) {
+		available[frontend_config.CDNCdnjs] = []string{"1.0.0"}
+		defer delete(available, frontend_config.CDNCdnjs)
+
+		_, _, cdn, err := fetchVersionsWithFallback("pkg", "2.0.0", frontend_config.CDNCdnjs, fetch, true)
+		if err != nil || cdn != frontend_config.CDNUnpkg {
+			t.Errorf("got cdn=%s err=%v", cdn, err)
+		}
+	})
+
+	t.Run("v prefixed version matches", func(t *testing.T) {
+		_, _, cdn, err := fetchVersionsWithFallback("pkg", "v2.0.0", frontend_config.CDNUnpkg, fetch, false)
+		if err != nil || cdn != frontend_config.CDNUnpkg {
+			t.Errorf("got cdn=%s err=%v", cdn, err)
+		}
+	})
+
+	t.Run("not available anywhere", func(t *testing.T) {
+		_, _, _, err := fetchVersionsWithFallback("pkg", "9.9.9", frontend_config.CDNUnpkg, fetch, true)
+		if err == nil || !strings.Contains(err.Error(), "version '9.9.9' not found") {
+			t.Errorf("expected version not found error, got %v", err)
+		}
+	})
+
+	t.Run("scoped packages skip cdnjs", func(t *testing.T) {
+		available[frontend_config.CDNCdnjs] = []string{"1.0.0"}
+		defer delete(available, frontend_config.CDNCdnjs)
+		delete(available, frontend_config.CDNJsdelivr)
+		defer func() { available[frontend_config.CDNJsdelivr] = []string{"1.0.0", "2.0.0"} }()
+
+		if cdn, _, _ := findAlternateCDN("@scope/pkg", "", frontend_config.CDNUnpkg); cdn != "" {
+			t.Errorf("expected no alternate CDN for scoped package, got %s", cdn)
+		}
+	})
+}
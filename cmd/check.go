@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that vendored files match the configuration",
+	Long: `Check for drift between the frontend configuration, its lockfile and the
+vendored files on disk, without contacting any CDN.
+
+The check fails when:
+  • A configured library has never been synced
+  • The lockfile records a different version than the configuration asks for
+  • A vendored file is missing or its contents changed since it was downloaded
+
+Files the lockfile still lists for libraries removed from the configuration
+are reported as warnings. Libraries under dev_libraries are only checked once
+they have been synced with 'smfaman sync --dev'.
+
+Exits with status 6 when any problem is found, so it can gate commits (see
+'smfaman hook install') or CI jobs.
+
+Examples:
+  smfaman check
+  smfaman -f myproject.yaml check`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCheck(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck() error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	manifestPath := manifestPathForConfig(FrontendConfig)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	problems, warnings := checkDrift(config, manifest, filepath.Dir(manifestPath))
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if len(problems) == 0 {
+		fmt.Printf("✓ %d %s in sync with %s\n", len(config.Libraries), pluralize(len(config.Libraries), "library", "libraries"), FrontendConfig)
+		return nil
+	}
+
+	for _, problem := range problems {
+		fmt.Printf("✗ %s\n", problem)
+	}
+	fmt.Println("\nRun 'smfaman sync' to bring the vendored files in line with the configuration.")
+	return driftError(fmt.Errorf("%d %s found", len(problems), pluralize(len(problems), "problem", "problems")))
+}
+
+// checkDrift compares the configuration and lockfile with the files on disk.
+// Lockfile keys are relative to baseDir. It returns one message per problem
+// and warnings for lockfile entries of libraries no longer configured.
+func checkDrift(config *frontend_config.FrontendConfig, manifest *fileManifest, baseDir string) (problems, warnings []string) {
+	byLibrary := make(map[string][]string)
+	for _, key := range sortedKeys(manifest.Files) {
+		entry := manifest.Files[key]
+		byLibrary[entry.Library] = append(byLibrary[entry.Library], key)
+	}
+
+	// Dev libraries are only checked once they have been synced with --dev
+	all, err := config.WithDevLibraries()
+	if err != nil {
+		all = config
+	}
+
+	for _, name := range sortedKeys(all.Libraries) {
+		spec := all.Libraries[name].Version
+		keys := byLibrary[name]
+		if len(keys) == 0 {
+			if _, dev := config.DevLibraries[name]; dev {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s@%s has not been synced", name, spec))
+			continue
+		}
+
+		reported := make(map[string]bool)
+		for _, key := range keys {
+			entry := manifest.Files[key]
+			if !versionMatchesSpec(entry.Version, spec) && !reported[entry.Version] {
+				reported[entry.Version] = true
+				problems = append(problems, fmt.Sprintf("%s is %s in the lockfile but %s in the configuration", name, entry.Version, spec))
+			}
+
+			data, err := os.ReadFile(filepath.Join(baseDir, filepath.FromSlash(key)))
+			switch {
+			case os.IsNotExist(err):
+				problems = append(problems, fmt.Sprintf("%s: %s is missing", name, key))
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("%s: %s could not be read: %v", name, key, err))
+			case entry.Integrity != "" && frontend_mgr.VerifySRI(data, entry.Integrity) != nil:
+				problems = append(problems, fmt.Sprintf("%s: %s was modified after download", name, key))
+			}
+		}
+	}
+
+	var removed []string
+	for library := range byLibrary {
+		if _, ok := all.Libraries[library]; !ok {
+			removed = append(removed, library)
+		}
+	}
+	sort.Strings(removed)
+	for _, library := range removed {
+		warnings = append(warnings, fmt.Sprintf("%s is no longer configured but the lockfile still lists %d %s",
+			library, len(byLibrary[library]), pluralize(len(byLibrary[library]), "file", "files")))
+	}
+
+	return problems, warnings
+}
+
+// versionMatchesSpec reports whether a recorded version satisfies a
+// configured version spec. Dist-tags can't be checked offline and match.
+func versionMatchesSpec(recorded, spec string) bool {
+	if frontend_mgr.SameVersion(recorded, spec) {
+		return true
+	}
+	if frontend_mgr.IsExactVersion(spec) {
+		return false
+	}
+	r, err := frontend_mgr.ParseVersionRange(spec)
+	if err != nil {
+		return true
+	}
+	return r.MaxSatisfying([]string{recorded}) != ""
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestCheckDrift(t *testing.T) {
+	base := t.TempDir()
+	writeTestFile(t, filepath.Join(base, "libs/jquery/jquery.min.js"), "jquery")
+	writeTestFile(t, filepath.Join(base, "libs/htmx/htmx.min.js"), "htmx edited locally")
+
+	config := &frontend_config.FrontendConfig{
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":    {Version: "3.7.1"},
+			"htmx":      {Version: "^2.0"},
+			"bootstrap": {Version: "5.3.0"},
+			"alpine":    {Version: "3.14.0"},
+		},
+		DevLibraries: map[string]frontend_config.LibraryConfig{
+			"livereload-js": {Version: "4.0.2"},
+			"eruda":         {Version: "3.4.0"},
+		},
+	}
+	manifest := &fileManifest{Files: map[string]manifestEntry{
+		"libs/jquery/jquery.min.js": {Library: "jquery", Version: "3.7.1", Integrity: frontend_mgr.ComputeSRI([]byte("jquery"))},
+		"libs/htmx/htmx.min.js":     {Library: "htmx", Version: "2.0.4", Integrity: frontend_mgr.ComputeSRI([]byte("htmx"))},
+		"libs/alpine/cdn.min.js":    {Library: "alpine", Version: "3.13.0"},
+		"libs/react/react.js":       {Library: "react", Version: "18.3.1"},
+		"libs/eruda/eruda.js":       {Library: "eruda", Version: "3.3.0"},
+	}}
+
+	problems, warnings := checkDrift(config, manifest, base)
+
+	want := []string{
+		"alpine is 3.13.0 in the lockfile but 3.14.0 in the configuration",
+		"alpine: libs/alpine/cdn.min.js is missing",
+		"bootstrap@5.3.0 has not been synced",
+		"eruda is 3.3.0 in the lockfile but 3.4.0 in the configuration",
+		"eruda: libs/eruda/eruda.js is missing",
+		"htmx: libs/htmx/htmx.min.js was modified after download",
+	}
+	if strings.Join(problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("checkDrift() problems =\n%s\nwant\n%s", strings.Join(problems, "\n"), strings.Join(want, "\n"))
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "react is no longer configured") {
+		t.Errorf("checkDrift() warnings = %v, want one for react", warnings)
+	}
+}
+
+func TestVersionMatchesSpec(t *testing.T) {
+	tests := []struct {
+		recorded, spec string
+		want           bool
+	}{
+		{"3.7.1", "3.7.1", true},
+		{"3.7.0", "3.7.1", false},
+		{"2.0.4", "^2.0", true},
+		{"1.9.12", "^2.0", false},
+		{"18.3.1", "latest", true},
+	}
+	for _, tt := range tests {
+		if got := versionMatchesSpec(tt.recorded, tt.spec); got != tt.want {
+			t.Errorf("versionMatchesSpec(%q, %q) = %v, want %v", tt.recorded, tt.spec, got, tt.want)
+		}
+	}
+}
@@ -1,19 +1,30 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
 )
 
 var (
-	cleanDryRun bool
-	cleanForce  bool
+	cleanDryRun       bool
+	cleanForce        bool
+	cleanIgnoreMarker bool
+	cleanGroups       []string
+	cleanAllowShared  bool
+	cleanTrash        bool
+	cleanRestore      string
 )
 
+// settingCleanTrash makes clean move folders to the trash by default
+const settingCleanTrash = "clean_trash"
+
 // cleanCmd represents the clean command
 var cleanCmd = &cobra.Command{
 	Use:     "clean",
@@ -33,19 +44,44 @@ The command will:
 
 Safety features:
   • Use --dry-run to see what would be deleted without actually deleting
-  • Use --force to skip confirmation prompt
+  • Only deletes folders that 'smfaman sync' created, recognised by the
+    .smfaman marker file it writes into them or by the lockfile recording
+    every file in them; other folders (e.g. a destination accidentally
+    pointing at ./src) are skipped unless --ignore-marker is given
+  • Use --yes (or --force) to skip the confirmation prompt
+  • Use --group to only remove libraries in a given group
+  • Refuses to run when the selected libraries share or nest destination
+    folders, unless --allow-shared is given (--dry-run only warns)
   • Only deletes directories that exist
+  • Use --trash to move folders into .smfaman-trash/<timestamp>/ next to
+    the config instead of deleting them (set clean_trash: true in the
+    settings to make this the default, --trash=false to delete anyway)
+  • Use --restore to move the most recently trashed folders back, or
+    --restore=<timestamp> for an older clean
   • Shows detailed output of operations
 
 Examples:
   smfaman clean                    # Remove all library folders (with prompt)
   smfaman clean --dry-run          # Show what would be deleted
-  smfaman clean --force            # Remove without confirmation
+  smfaman clean --yes              # Remove without confirmation
+  smfaman clean --ignore-marker    # Also remove folders sync didn't create
+  smfaman clean --group admin      # Remove only libraries in the admin group
+  smfaman clean --trash            # Move folders to .smfaman-trash instead
+  smfaman clean --restore          # Undo the last trashed clean
   smfaman clean -f smartfe.yaml    # Clean using specific config file`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := runClean(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+		if !cmd.Flags().Changed("trash") {
+			cleanTrash = viper.GetBool(settingCleanTrash)
+		}
+
+		var err error
+		if cleanRestore != "" {
+			err = restoreFromTrash(FrontendConfig, cleanRestore)
+		} else {
+			err = runClean()
+		}
+		if err != nil {
+			exitWithError(err)
 		}
 	},
 }
@@ -54,7 +90,13 @@ func init() {
 	rootCmd.AddCommand(cleanCmd)
 
 	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Show what would be deleted without actually deleting")
-	cleanCmd.Flags().BoolVar(&cleanForce, "force", false, "Skip confirmation prompt")
+	cleanCmd.Flags().BoolVar(&cleanForce, "force", false, "Skip confirmation prompt (same as --yes)")
+	cleanCmd.Flags().BoolVar(&cleanIgnoreMarker, "ignore-marker", false, "Also delete folders without a .smfaman marker")
+	cleanCmd.Flags().BoolVar(&cleanAllowShared, "allow-shared", false, "Proceed even if libraries share destination folders")
+	cleanCmd.Flags().BoolVar(&cleanTrash, "trash", false, "Move folders to .smfaman-trash instead of deleting them")
+	cleanCmd.Flags().StringVar(&cleanRestore, "restore", "", "Restore trashed folders: the latest clean, or a trash folder name")
+	cleanCmd.Flags().Lookup("restore").NoOptDefVal = restoreLatest
+	cleanCmd.Flags().StringArrayVarP(&cleanGroups, "group", "g", nil, "Only clean libraries in this group (repeatable)")
 }
 
 func runClean() error {
@@ -69,18 +111,58 @@ func runClean() error {
 		return nil
 	}
 
+	// Restrict to the selected groups
+	config, err = selectLibraryGroups(config, cleanGroups)
+	if err != nil {
+		return err
+	}
+
+	// Removing a shared folder would delete other libraries' files too
+	if err := checkSharedDestinations(config, cleanAllowShared, cleanDryRun); err != nil {
+		return err
+	}
+
 	// Get all library destinations
 	destinations, err := config.GetLibraryDestinations()
 	if err != nil {
 		return fmt.Errorf("failed to get library destinations: %w", err)
 	}
 
-	// Filter to only directories that exist
+	// Files listed in the ignore file are never deleted
+	ignore, err := loadIgnoreFile(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	manifestPath := manifestPathForConfig(FrontendConfig)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	// Filter to only directories that exist and smfaman created
 	existingDirs := make(map[string]string)
+	unmarkedDirs := make(map[string]string)
 	for libName, destPath := range destinations {
-		if info, err := os.Stat(destPath); err == nil && info.IsDir() {
-			existingDirs[libName] = destPath
+		info, err := os.Stat(destPath)
+		if err != nil || !info.IsDir() {
+			continue
 		}
+		owned := hasDestinationMarker(destPath) || syncedDestination(manifestPath, manifest, libName, destPath)
+		if !cleanIgnoreMarker && !owned {
+			unmarkedDirs[libName] = destPath
+			continue
+		}
+		existingDirs[libName] = destPath
+	}
+
+	if len(unmarkedDirs) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ Skipping %d director%s without a %s marker (not created by smfaman sync):\n",
+			len(unmarkedDirs), pluralize(len(unmarkedDirs), "y", "ies"), destinationMarkerName)
+		for _, libName := range sortedKeys(unmarkedDirs) {
+			fmt.Fprintf(os.Stderr, "  • %s → %s\n", libName, unmarkedDirs[libName])
+		}
+		fmt.Fprintf(os.Stderr, "Use --ignore-marker to delete them anyway.\n\n")
 	}
 
 	if len(existingDirs) == 0 {
@@ -90,9 +172,22 @@ func runClean() error {
 
 	// Show what will be deleted
 	fmt.Printf("Configuration file: %s\n\n", FrontendConfig)
-	fmt.Printf("The following directories will be %s:\n\n", getActionVerb(cleanDryRun))
-	for libName, destPath := range existingDirs {
-		fmt.Printf("  • %s → %s\n", libName, destPath)
+	verb := getActionVerb(cleanDryRun)
+	if cleanTrash {
+		verb = strings.Replace(verb, "deleted", "moved to the trash", 1)
+	}
+	fmt.Printf("The following directories will be %s:\n\n", verb)
+	keptFiles := make(map[string][]string)
+	for _, libName := range sortedKeys(existingDirs) {
+		destPath := existingDirs[libName]
+		if keptFiles[libName], err = ignore.ignoredFilesUnder(destPath); err != nil {
+			return err
+		}
+		if n := len(keptFiles[libName]); n > 0 {
+			fmt.Printf("  • %s → %s (keeping %d %s matched by %s)\n", libName, destPath, n, pluralize(n, "file", "files"), ignoreFileName)
+		} else {
+			fmt.Printf("  • %s → %s\n", libName, destPath)
+		}
 	}
 	fmt.Printf("\nTotal: %d director%s\n\n", len(existingDirs), pluralize(len(existingDirs), "y", "ies"))
 
@@ -102,7 +197,7 @@ func runClean() error {
 		return nil
 	}
 
-	// Prompt for confirmation unless --force
+	// Prompt for confirmation unless --force or --yes
 	if !cleanForce {
 		if !promptConfirmation("Do you want to proceed?") {
 			fmt.Println("Cancelled.")
@@ -110,22 +205,70 @@ func runClean() error {
 		}
 	}
 
-	// Delete directories
+	// Delete directories, or move them to the trash
+	var trash *trashIndex
+	var trashDir string
+	if cleanTrash {
+		now := time.Now()
+		if trashDir, err = newTrashFolder(FrontendConfig, now); err != nil {
+			return err
+		}
+		trash = &trashIndex{Config: filepath.Base(FrontendConfig), TrashedAt: now.UTC()}
+	}
+
 	deletedCount := 0
 	failedCount := 0
-	for libName, destPath := range existingDirs {
-		if err := os.RemoveAll(destPath); err != nil {
-			fmt.Printf("✗ Failed to remove %s (%s): %v\n", libName, destPath, err)
-			failedCount++
+	var removedPaths []string
+	for _, libName := range sortedKeys(existingDirs) {
+		destPath := existingDirs[libName]
+		keptNote := ""
+		if n := len(keptFiles[libName]); n > 0 {
+			keptNote = fmt.Sprintf(", kept %d ignored %s", n, pluralize(n, "file", "files"))
+		}
+
+		var removed []string
+		if trash != nil {
+			var entry trashEntry
+			entry, removed, err = moveToTrash(FrontendConfig, trashDir, libName, destPath, ignore)
+			if err != nil {
+				fmt.Printf("✗ Failed to move %s (%s) to the trash: %v\n", libName, destPath, err)
+				failedCount++
+				continue
+			}
+			trash.Entries = append(trash.Entries, entry)
+			fmt.Printf("✓ Trashed %s (%s%s)\n", libName, destPath, keptNote)
 		} else {
-			fmt.Printf("✓ Removed %s (%s)\n", libName, destPath)
-			deletedCount++
+			if removed, err = removeDestination(destPath, ignore); err != nil {
+				fmt.Printf("✗ Failed to remove %s (%s): %v\n", libName, destPath, err)
+				failedCount++
+				continue
+			}
+			fmt.Printf("✓ Removed %s (%s%s)\n", libName, destPath, keptNote)
+		}
+		deletedCount++
+		removedPaths = append(removedPaths, removed...)
+	}
+
+	if trash != nil {
+		if len(trash.Entries) == 0 {
+			os.RemoveAll(trashDir)
+		} else if err := writeTrashIndex(trashDir, *trash); err != nil {
+			return err
 		}
 	}
 
+	// Forget provenance of removed files
+	if err := pruneManifest(FrontendConfig, removedPaths); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	// Summary
 	fmt.Printf("\n")
-	fmt.Printf("Deleted: %d director%s\n", deletedCount, pluralize(deletedCount, "y", "ies"))
+	if trash != nil {
+		fmt.Printf("Trashed: %d director%s (restore with 'smfaman clean --restore')\n", deletedCount, pluralize(deletedCount, "y", "ies"))
+	} else {
+		fmt.Printf("Deleted: %d director%s\n", deletedCount, pluralize(deletedCount, "y", "ies"))
+	}
 	if failedCount > 0 {
 		fmt.Printf("Failed:  %d director%s\n", failedCount, pluralize(failedCount, "y", "ies"))
 		return fmt.Errorf("failed to remove %d director%s", failedCount, pluralize(failedCount, "y", "ies"))
@@ -134,18 +277,61 @@ func runClean() error {
 	return nil
 }
 
-// promptConfirmation prompts the user for yes/no confirmation
-func promptConfirmation(message string) bool {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf("%s (y/N): ", message)
+// removeDestination deletes a destination folder, or only its files not
+// matched by the ignore file when it has some. It returns the removed paths.
+func removeDestination(destPath string, ignore *ignoreMatcher) ([]string, error) {
+	kept, err := ignore.ignoredFilesUnder(destPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(kept) == 0 {
+		return []string{destPath}, os.RemoveAll(destPath)
+	}
 
-	response, err := reader.ReadString('\n')
+	files, err := unignoredFiles(destPath, ignore)
 	if err != nil {
-		return false
+		return nil, err
 	}
+	for i, file := range files {
+		if err := os.Remove(file); err != nil {
+			return files[:i], err
+		}
+	}
+	removeEmptyDirs(destPath)
+	return files, nil
+}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes"
+// checkSharedDestinations warns about libraries whose destination folders are
+// shared or nested, returning an error unless allowShared is set. Dry runs
+// only report the conflicts.
+func checkSharedDestinations(config *frontend_config.FrontendConfig, allowShared, dryRun bool) error {
+	conflicts, err := config.FindDestinationConflicts()
+	if err != nil {
+		return fmt.Errorf("failed to get library destinations: %w", err)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠ Warning: %d %s share destination folders:\n",
+		len(conflicts), pluralize(len(conflicts), "library pair", "library pairs"))
+	for _, c := range conflicts {
+		if c.IsSame() {
+			fmt.Fprintf(os.Stderr, "  • %s and %s → %s\n", c.Library, c.OtherLibrary, c.Path)
+		} else {
+			fmt.Fprintf(os.Stderr, "  • %s (%s) and %s (%s) are nested\n", c.Library, c.Path, c.OtherLibrary, c.OtherPath)
+		}
+	}
+	if dryRun && !allowShared {
+		fmt.Fprintf(os.Stderr, "A real run needs --allow-shared to proceed.\n\n")
+		return nil
+	}
+	fmt.Fprintln(os.Stderr)
+
+	if !allowShared {
+		return fmt.Errorf("libraries share destination folders (use --allow-shared to proceed anyway)")
+	}
+	return nil
 }
 
 // getActionVerb returns the appropriate verb based on dry-run mode
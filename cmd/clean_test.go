@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -71,9 +72,9 @@ func TestClean(t *testing.T) {
 	defer func() { FrontendConfig = oldConfig }()
 
 	// Set force flag to skip confirmation
-	oldForce := cleanForce
-	cleanForce = true
-	defer func() { cleanForce = oldForce }()
+	oldForce, oldIgnoreMarker := cleanForce, cleanIgnoreMarker
+	cleanForce, cleanIgnoreMarker = true, true
+	defer func() { cleanForce, cleanIgnoreMarker = oldForce, oldIgnoreMarker }()
 
 	// Run clean
 	if err := runClean(); err != nil {
@@ -212,6 +213,152 @@ func TestCleanNonExistentDirs(t *testing.T) {
 	}
 }
 
+func TestCleanGroup(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+	config := frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+		CDN:         "unpkg",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":   {Version: "3.7.1", Groups: []string{"marketing"}},
+			"chart.js": {Version: "4.4.0", Groups: []string{"admin"}},
+		},
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	jqueryDir := filepath.Join(tmpDir, "libs", "jquery")
+	chartDir := filepath.Join(tmpDir, "libs", "chart.js")
+	for _, dir := range []string{jqueryDir, chartDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory %s: %v", dir, err)
+		}
+	}
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig = oldConfig }()
+
+	oldForce, oldIgnoreMarker := cleanForce, cleanIgnoreMarker
+	cleanForce, cleanIgnoreMarker = true, true
+	defer func() { cleanForce, cleanIgnoreMarker = oldForce, oldIgnoreMarker }()
+
+	oldGroups := cleanGroups
+	defer func() { cleanGroups = oldGroups }()
+
+	// Unknown groups are rejected
+	cleanGroups = []string{"shop"}
+	if err := runClean(); err == nil {
+		t.Error("Expected error for unknown group")
+	}
+
+	cleanGroups = []string{"admin"}
+	if err := runClean(); err != nil {
+		t.Fatalf("runClean failed: %v", err)
+	}
+
+	if _, err := os.Stat(chartDir); !os.IsNotExist(err) {
+		t.Errorf("Directory in selected group should be removed: %s", chartDir)
+	}
+	if _, err := os.Stat(jqueryDir); err != nil {
+		t.Errorf("Directory outside selected group should remain: %s", jqueryDir)
+	}
+}
+
+func TestCleanRefusesSharedDestinations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+	sharedDir := filepath.Join(tmpDir, "vendor")
+	config := frontend_config.FrontendConfig{
+		Destination: sharedDir,
+		CDN:         "unpkg",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":    {Version: "3.7.1"},
+			"bootstrap": {Version: "5.3.0"},
+		},
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := os.MkdirAll(sharedDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig = oldConfig }()
+
+	oldForce, oldIgnoreMarker, oldAllowShared := cleanForce, cleanIgnoreMarker, cleanAllowShared
+	cleanForce, cleanIgnoreMarker = true, true
+	defer func() { cleanForce, cleanIgnoreMarker, cleanAllowShared = oldForce, oldIgnoreMarker, oldAllowShared }()
+
+	cleanAllowShared = false
+	if err := runClean(); err == nil {
+		t.Error("Expected error for shared destinations")
+	}
+	if _, err := os.Stat(sharedDir); err != nil {
+		t.Errorf("Shared directory should not be removed without --allow-shared: %v", err)
+	}
+
+	cleanAllowShared = true
+	if err := runClean(); err != nil {
+		t.Fatalf("runClean with --allow-shared failed: %v", err)
+	}
+	if _, err := os.Stat(sharedDir); !os.IsNotExist(err) {
+		t.Error("Shared directory should be removed with --allow-shared")
+	}
+}
+
+func TestSharedDestinationsOnlyInSelection(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+	config := frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "vendor"),
+		CDN:         "unpkg",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":    {Version: "3.7.1", Groups: []string{"legacy"}},
+			"bootstrap": {Version: "5.3.0", Groups: []string{"legacy"}},
+			"htmx.org":  {Version: "2.0.4", Groups: []string{"app"}, OutputPath: filepath.Join(tmpDir, "app", "htmx")},
+		},
+	}
+	data, _ := yaml.Marshal(config)
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	oldConfig, oldGroups, oldDryRun := FrontendConfig, cleanGroups, cleanDryRun
+	FrontendConfig = configPath
+	defer func() { FrontendConfig, cleanGroups, cleanDryRun = oldConfig, oldGroups, oldDryRun }()
+
+	// A conflict between libraries outside the selected group doesn't block it
+	cleanGroups = []string{"app"}
+	if err := runClean(); err != nil {
+		t.Errorf("runClean --group app failed: %v", err)
+	}
+
+	// Dry runs report the conflict instead of failing
+	cleanGroups, cleanDryRun = nil, true
+	if err := runClean(); err != nil {
+		t.Errorf("runClean --dry-run failed: %v", err)
+	}
+	if err := checkSharedDestinations(&config, false, false); err == nil {
+		t.Error("Expected error for shared destinations outside a dry run")
+	}
+}
+
 func TestPluralize(t *testing.T) {
 	tests := []struct {
 		count    int
@@ -249,3 +396,84 @@ func TestGetActionVerb(t *testing.T) {
 		}
 	}
 }
+
+func TestCleanSkipsUnmarkedDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+	config := frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+		ProjectName: "test-project",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":    {Version: "3.7.1"},
+			"bootstrap": {Version: "5.3.3"},
+			"react":     {Version: "18.2.0", OutputPath: filepath.Join(tmpDir, "src")},
+		},
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	// jquery was created by sync, src is user code
+	jqueryDir := filepath.Join(tmpDir, "libs", "jquery")
+	srcDir := filepath.Join(tmpDir, "src")
+	for _, dir := range []string{jqueryDir, srcDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory %s: %v", dir, err)
+		}
+	}
+	if err := writeDestinationMarker(jqueryDir, "jquery"); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(srcDir, "app.js"), "console.log('mine')")
+
+	// bootstrap was synced before sync wrote markers; the lockfile shows it
+	bootstrapDir := filepath.Join(tmpDir, "libs", "bootstrap")
+	writeTestFile(t, filepath.Join(bootstrapDir, "bootstrap.min.css"), "css")
+	manifest := &fileManifest{Files: map[string]manifestEntry{
+		"libs/bootstrap/bootstrap.min.css": {Library: "bootstrap"},
+	}}
+	if err := saveManifest(manifestPathForConfig(configPath), manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig = oldConfig }()
+
+	origInput := confirmationInput
+	confirmationInput = strings.NewReader("y\n")
+	defer func() { confirmationInput = origInput }()
+
+	if err := runClean(); err != nil {
+		t.Fatalf("runClean failed: %v", err)
+	}
+
+	if _, err := os.Stat(jqueryDir); !os.IsNotExist(err) {
+		t.Errorf("Marked directory should be deleted: %s", jqueryDir)
+	}
+	if _, err := os.Stat(bootstrapDir); !os.IsNotExist(err) {
+		t.Errorf("Directory recorded in the lockfile should be deleted: %s", bootstrapDir)
+	}
+	if _, err := os.Stat(filepath.Join(srcDir, "app.js")); err != nil {
+		t.Errorf("Unmarked directory should be kept: %v", err)
+	}
+
+	// --ignore-marker deletes it regardless
+	oldIgnoreMarker := cleanIgnoreMarker
+	cleanIgnoreMarker = true
+	defer func() { cleanIgnoreMarker = oldIgnoreMarker }()
+	confirmationInput = strings.NewReader("y\n")
+
+	if err := runClean(); err != nil {
+		t.Fatalf("runClean failed: %v", err)
+	}
+	if _, err := os.Stat(srcDir); !os.IsNotExist(err) {
+		t.Errorf("Directory should be deleted with --ignore-marker: %s", srcDir)
+	}
+}
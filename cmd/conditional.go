@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// httpValidators are the HTTP cache validators of a downloaded file
+type httpValidators struct {
+	ETag         string
+	LastModified string
+}
+
+// downloadFileConditional downloads a file to memory, retrying transient
+// failures. When validators are given, the request is conditional and
+// notModified reports a 304 response (with no data). opts adds the
+// library's own headers and query parameters.
+func downloadFileConditional(url string, since httpValidators, opts requestOptions) (data []byte, validators httpValidators, notModified bool, err error) {
+	err = withRetry(url, func() error {
+		var err error
+		data, validators, notModified, err = fetchConditional(url, since, opts)
+		return err
+	})
+	return data, validators, notModified, err
+}
+
+// fetchConditional performs a single, possibly conditional, download attempt
+func fetchConditional(url string, since httpValidators, opts requestOptions) ([]byte, httpValidators, bool, error) {
+	req, err := http.NewRequestWithContext(frontend_mgr.RequestContext, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, httpValidators{}, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := opts.apply(req); err != nil {
+		return nil, httpValidators{}, false, err
+	}
+	if since.ETag != "" {
+		req.Header.Set("If-None-Match", since.ETag)
+	}
+	if since.LastModified != "" {
+		req.Header.Set("If-Modified-Since", since.LastModified)
+	}
+
+	resp, err := opts.client().Do(req)
+	if err != nil {
+		return nil, httpValidators{}, false, fmt.Errorf("failed to download: %w", redactRequestError(err, url))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && (since.ETag != "" || since.LastModified != "") {
+		return nil, since, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpValidators{}, false, &httpStatusError{StatusCode: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, httpValidators{}, false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	validators := httpValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return data, validators, false, nil
+}
+
+// applyStoredValidators copies the validators recorded in the config's
+// manifest onto forced tasks, so unchanged files can be skipped. Validators
+// are only used when the local file still matches what was downloaded.
+func applyStoredValidators(configPath string, tasks []DownloadTask) error {
+	manifestPath := manifestPathForConfig(configPath)
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	for i, task := range tasks {
+		if task.Reason != "forced" {
+			continue
+		}
+
+		key, err := manifestKey(manifestPath, task.DestPath)
+		if err != nil {
+			return err
+		}
+		entry, ok := manifest.Files[key]
+		if !ok || entry.URL != task.URL || (entry.ETag == "" && entry.LastModified == "") {
+			continue
+		}
+
+		data, err := os.ReadFile(task.DestPath)
+		if err != nil || frontend_mgr.ComputeSRI(data) != entry.Integrity {
+			continue
+		}
+
+		tasks[i].ETag = entry.ETag
+		tasks[i].LastModified = entry.LastModified
+	}
+
+	return nil
+}
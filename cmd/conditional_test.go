@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// newETagServer serves content with an ETag and honors If-None-Match
+func newETagServer(t *testing.T, content, etag string) (*httptest.Server, *int) {
+	t.Helper()
+	fullResponses := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server, &fullResponses
+}
+
+func TestDownloadFileWithTaskSkipsUnchangedFiles(t *testing.T) {
+	server, fullResponses := newETagServer(t, "console.log('v1');", `"abc"`)
+
+	oldNoPackageCache := syncNoPackageCache
+	oldForce := syncForce
+	syncNoPackageCache = true
+	syncForce = true
+	defer func() {
+		syncNoPackageCache = oldNoPackageCache
+		syncForce = oldForce
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	tmpDir := t.TempDir()
+	task := DownloadTask{
+		FilePath: "app.js",
+		DestPath: filepath.Join(tmpDir, "app.js"),
+		URL:      server.URL,
+		Reason:   "missing",
+	}
+
+	first, err := downloadFileWithTask(task)
+	if err != nil {
+		t.Fatalf("downloadFileWithTask failed: %v", err)
+	}
+	if first.ETag != `"abc"` || first.LastModified == "" || first.Unchanged {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+
+	// Make any rewrite detectable
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(task.DestPath, past, past)
+
+	t.Run("not modified", func(t *testing.T) {
+		forced := task
+		forced.Reason = "forced"
+		forced.ETag = first.ETag
+
+		result, err := downloadFileWithTask(forced)
+		if err != nil {
+			t.Fatalf("downloadFileWithTask failed: %v", err)
+		}
+		if !result.Unchanged || result.Bytes != 0 || result.ETag != `"abc"` {
+			t.Errorf("expected unchanged result, got %+v", result)
+		}
+		if *fullResponses != 1 {
+			t.Errorf("expected a conditional request, got %d full responses", *fullResponses)
+		}
+		if info, _ := os.Stat(task.DestPath); !info.ModTime().Equal(past) {
+			t.Error("expected unchanged file not to be rewritten")
+		}
+	})
+
+	t.Run("identical content", func(t *testing.T) {
+		forced := task
+		forced.Reason = "forced"
+
+		result, err := downloadFileWithTask(forced)
+		if err != nil {
+			t.Fatalf("downloadFileWithTask failed: %v", err)
+		}
+		if !result.Unchanged {
+			t.Errorf("expected unchanged result, got %+v", result)
+		}
+		if info, _ := os.Stat(task.DestPath); !info.ModTime().Equal(past) {
+			t.Error("expected identical file not to be rewritten")
+		}
+	})
+}
+
+func TestApplyStoredValidators(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	destPath := filepath.Join(tmpDir, "libs", "app.js")
+	writeTestFile(t, destPath, "content")
+
+	task := DownloadTask{LibraryName: "app", Version: "1.0.0", FilePath: "app.js", DestPath: destPath, URL: "https://example.com/app.js", Reason: "forced"}
+	downloads := []downloadedFile{{
+		task:         task,
+		result:       fileDownloadResult{Integrity: frontend_mgr.ComputeSRI([]byte("content")), ETag: `"v1"`},
+		downloadedAt: time.Now(),
+	}}
+	if err := updateManifest(configPath, downloads); err != nil {
+		t.Fatalf("updateManifest failed: %v", err)
+	}
+
+	missing := task
+	missing.Reason = "missing"
+	tasks := []DownloadTask{task, missing}
+	if err := applyStoredValidators(configPath, tasks); err != nil {
+		t.Fatalf("applyStoredValidators failed: %v", err)
+	}
+	if tasks[0].ETag != `"v1"` {
+		t.Errorf("expected forced task to get stored ETag, got %q", tasks[0].ETag)
+	}
+	if tasks[1].ETag != "" {
+		t.Errorf("expected missing task to get no ETag, got %q", tasks[1].ETag)
+	}
+
+	// Local edits must be overwritten, so no validators are sent
+	writeTestFile(t, destPath, "edited")
+	tasks = []DownloadTask{task}
+	if err := applyStoredValidators(configPath, tasks); err != nil {
+		t.Fatalf("applyStoredValidators failed: %v", err)
+	}
+	if tasks[0].ETag != "" {
+		t.Errorf("expected no ETag for a locally modified file, got %q", tasks[0].ETag)
+	}
+}
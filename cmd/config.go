@@ -0,0 +1,546 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var configShowResolve bool
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit the frontend configuration",
+	Long: `Inspect and edit the frontend configuration file.
+
+Keys are dotted paths into the YAML document. Library settings are addressed
+as libraries.<name>.<field>, e.g. libraries.react.files.
+
+Subcommands:
+  show  - Print the parsed configuration, optionally with effective values
+  get   - Print the value of a key
+  set   - Set a key to a YAML value
+  unset - Remove a key`,
+}
+
+// configShowCmd prints the parsed configuration
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the parsed configuration",
+	Long: `Print the frontend configuration as smfaman parsed it.
+
+With --resolve, defaults are filled in and every library shows its effective
+CDN, destination, mirrors and file list, along with where each value came
+from. Use this to debug why a file was downloaded from a CDN or written to a
+folder.
+
+Examples:
+  smfaman config show
+  smfaman config show --resolve
+  smfaman -f myproject.yaml config show --resolve`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigShow(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// configGetCmd prints a single config value
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a config key",
+	Long: `Print the value of a config key. Scalars are printed as-is; lists and
+maps are printed as YAML.
+
+Examples:
+  smfaman config get destination
+  smfaman config get libraries.react.version
+  smfaman config get libraries.react.files`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigGet(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// configSetCmd sets a config value
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config key",
+	Long: `Set a config key to a value. The value is parsed as YAML, so lists can be
+given in flow style. The updated config is validated before it is saved.
+
+Examples:
+  smfaman config set cdn jsdelivr
+  smfaman config set libraries.react.version 18.3.1
+  smfaman config set libraries.react.files '["umd/react.production.min.js"]'`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigSet(args[0], args[1]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// configUnsetCmd removes a config value
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a config key",
+	Long: `Remove a config key, restoring its default. Unsetting a library's
+setting makes it fall back to the global value.
+
+Examples:
+  smfaman config unset cdn
+  smfaman config unset libraries.react.files`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigUnset(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowResolve, "resolve", false, "Show effective values with defaults applied")
+}
+
+// resolvedConfig is the configuration with defaults and per-library values applied
+type resolvedConfig struct {
+	ConfigFile     string                         `yaml:"config_file"`
+	ProjectName    string                         `yaml:"project_name"`
+	Destination    string                         `yaml:"destination"`
+	CDN            frontend_config.CDN            `yaml:"cdn"`
+	Profile        frontend_config.Profile        `yaml:"profile"`
+	FilesMode      frontend_config.FilesMode      `yaml:"files_mode"`
+	SourceMaps     frontend_config.SourceMaps     `yaml:"sourcemaps,omitempty"`
+	LinkMode       frontend_config.LinkMode       `yaml:"link_mode"`
+	CaseCollisions frontend_config.CaseCollisions `yaml:"case_collisions"`
+	Mirrors        []string                       `yaml:"mirrors,omitempty"`
+	Concurrency    int                            `yaml:"concurrency"`
+	Lockfile       bool                           `yaml:"lockfile"`
+	Libraries      map[string]resolvedLibrary     `yaml:"libraries"`
+}
+
+// resolvedLibrary holds the effective settings of a single library
+type resolvedLibrary struct {
+	Version         string                     `yaml:"version"`
+	CDN             frontend_config.CDN        `yaml:"cdn"`
+	CDNFrom         string                     `yaml:"cdn_from"` // "library", "global" or "default"
+	Destination     string                     `yaml:"destination"`
+	DestinationFrom string                     `yaml:"destination_from"` // "output_path" or "destination"
+	Mirrors         []string                   `yaml:"mirrors,omitempty"`
+	Files           []string                   `yaml:"files,omitempty"`
+	FilesFrom       string                     `yaml:"files_from"` // "files", "files_dev", "files_prod" or the files mode
+	SourceMaps      frontend_config.SourceMaps `yaml:"sourcemaps,omitempty"`
+	Groups          []string                   `yaml:"groups,omitempty"`
+	Tags            []string                   `yaml:"tags,omitempty"`
+	Notes           string                     `yaml:"notes,omitempty"`
+}
+
+// runConfigShow executes the config show command
+func runConfigShow() error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	var out any = config
+	if configShowResolve {
+		out, err = resolveConfig(FrontendConfig, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	fmt.Print(string(data))
+	return nil
+}
+
+// resolveConfig applies defaults and resolves the effective settings of every library
+func resolveConfig(configPath string, config *frontend_config.FrontendConfig) (*resolvedConfig, error) {
+	resolved := &resolvedConfig{
+		ConfigFile:     configPath,
+		ProjectName:    config.ProjectName,
+		Destination:    config.Destination,
+		CDN:            config.CDN,
+		Profile:        config.Profile,
+		FilesMode:      config.FilesMode,
+		SourceMaps:     config.SourceMaps,
+		LinkMode:       config.LinkMode,
+		CaseCollisions: config.CaseCollisions,
+		Mirrors:        config.Mirrors,
+		Concurrency:    projectConcurrency(config),
+		Lockfile:       config.LockfileEnabled(),
+		Libraries:      make(map[string]resolvedLibrary, len(config.Libraries)),
+	}
+	if resolved.CDN == "" {
+		resolved.CDN = settingsCDN()
+	}
+	if resolved.Profile == "" {
+		resolved.Profile = frontend_config.ProfileDev
+	}
+	if resolved.FilesMode == "" {
+		resolved.FilesMode = frontend_config.FilesModeEntrypoints
+	}
+	if resolved.LinkMode == "" {
+		resolved.LinkMode = frontend_config.LinkModeCopy
+	}
+	if resolved.CaseCollisions == "" {
+		resolved.CaseCollisions = frontend_config.CaseCollisionsRename
+	}
+
+	for name, libConfig := range config.Libraries {
+		lib := resolvedLibrary{
+			Version:         libConfig.Version,
+			CDN:             libraryCDN(config, libConfig),
+			CDNFrom:         "global",
+			DestinationFrom: "destination",
+			Files:           config.GetLibraryFiles(libConfig),
+			SourceMaps:      config.GetLibrarySourceMaps(libConfig),
+			Groups:          libConfig.Groups,
+			Tags:            libConfig.Tags,
+			Notes:           libConfig.Notes,
+		}
+
+		switch {
+		case libConfig.CDN != "":
+			lib.CDNFrom = "library"
+		case config.CDN == "":
+			lib.CDNFrom = "default"
+		}
+
+		if libConfig.OutputPath != "" {
+			lib.DestinationFrom = "output_path"
+		}
+		destination, err := config.GetLibraryDestination(name, libConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get destination for %s: %w", name, err)
+		}
+		lib.Destination = destination
+
+		mirrors, err := config.GetLibraryMirrors(name, libConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mirrors for %s: %w", name, err)
+		}
+		lib.Mirrors = mirrors
+
+		switch {
+		case len(lib.Files) == 0:
+			lib.FilesFrom = string(config.GetLibraryFilesMode(libConfig))
+		case config.Profile == frontend_config.ProfileProd && len(libConfig.FilesProd) > 0:
+			lib.FilesFrom = "files_prod"
+		case config.Profile != frontend_config.ProfileProd && len(libConfig.FilesDev) > 0:
+			lib.FilesFrom = "files_dev"
+		default:
+			lib.FilesFrom = "files"
+		}
+
+		resolved.Libraries[name] = lib
+	}
+
+	return resolved, nil
+}
+
+// runConfigGet executes the config get command
+func runConfigGet(key string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	value, err := getConfigValue(config, key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(value)
+	return nil
+}
+
+// runConfigSet executes the config set command
+func runConfigSet(key, value string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	updated, err := editConfig(config, key, &value)
+	if err != nil {
+		return err
+	}
+	if err := saveConfig(FrontendConfig, updated); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Set %s\n", key)
+	return nil
+}
+
+// runConfigUnset executes the config unset command
+func runConfigUnset(key string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	updated, err := editConfig(config, key, nil)
+	if err != nil {
+		return err
+	}
+	if err := saveConfig(FrontendConfig, updated); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Unset %s\n", key)
+	return nil
+}
+
+// getConfigValue returns the value at key, formatted for printing
+func getConfigValue(config *frontend_config.FrontendConfig, key string) (string, error) {
+	var doc yaml.Node
+	if err := doc.Encode(config); err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	parent, field, err := lookupConfigKey(&doc, config, key)
+	if err != nil {
+		return "", err
+	}
+	_, value := mappingValue(parent, field)
+	if value == nil {
+		return "", fmt.Errorf("key '%s' is not set", key)
+	}
+
+	if value.Kind == yaml.ScalarNode {
+		return value.Value + "\n", nil
+	}
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return string(data), nil
+}
+
+// editConfig returns a copy of config with key set to the YAML value, or
+// removed when value is nil. The result is validated before it is returned.
+func editConfig(config *frontend_config.FrontendConfig, key string, value *string) (*frontend_config.FrontendConfig, error) {
+	var doc yaml.Node
+	if err := doc.Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	parent, field, err := lookupConfigKey(&doc, config, key)
+	if err != nil {
+		return nil, err
+	}
+
+	index, _ := mappingValue(parent, field)
+	if value == nil {
+		if index < 0 {
+			return nil, fmt.Errorf("key '%s' is not set", key)
+		}
+		parent.Content = append(parent.Content[:index], parent.Content[index+2:]...)
+	} else {
+		var parsed yaml.Node
+		if err := yaml.Unmarshal([]byte(*value), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		newValue := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+		if len(parsed.Content) > 0 {
+			newValue = parsed.Content[0]
+		}
+
+		if index < 0 {
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: field}
+			parent.Content = append(parent.Content, keyNode, newValue)
+		} else {
+			parent.Content[index+1] = newValue
+		}
+	}
+
+	// Round-trip through a strict decoder to reject unknown keys and wrong types
+	data, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var updated frontend_config.FrontendConfig
+	if err := frontend_config.UnmarshalStrict(data, &updated); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	if updated.Libraries == nil {
+		updated.Libraries = make(map[string]frontend_config.LibraryConfig)
+	}
+
+	if err := validateConfig(&updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// lookupConfigKey resolves a dotted key to its parent mapping node and field
+// name. Library names may contain dots, so "libraries.chart.js.version" is
+// matched against the configured libraries.
+func lookupConfigKey(doc *yaml.Node, config *frontend_config.FrontendConfig, key string) (*yaml.Node, string, error) {
+	root := doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	parts := strings.Split(key, ".")
+	if key == "" || slices.Contains(parts, "") {
+		return nil, "", fmt.Errorf("invalid key '%s'", key)
+	}
+
+	if parts[0] != "libraries" || len(parts) == 1 {
+		if len(parts) > 1 {
+			return nil, "", notFoundError(fmt.Errorf("key '%s' not found", key))
+		}
+		return root, parts[0], nil
+	}
+
+	_, libraries := mappingValue(root, "libraries")
+	if libraries == nil {
+		return nil, "", fmt.Errorf("no libraries configured")
+	}
+
+	// libraries.<name>
+	libName := strings.Join(parts[1:], ".")
+	if _, ok := config.Libraries[libName]; ok {
+		return libraries, libName, nil
+	}
+
+	// libraries.<name>.<field>
+	libName = strings.Join(parts[1:len(parts)-1], ".")
+	if _, ok := config.Libraries[libName]; !ok || len(parts) < 3 {
+		return nil, "", notFoundError(fmt.Errorf("library '%s' not found in config", strings.Join(parts[1:], ".")))
+	}
+	_, library := mappingValue(libraries, libName)
+	return library, parts[len(parts)-1], nil
+}
+
+// mappingValue returns the index of key in a mapping node and its value,
+// or -1 and nil when the key is missing
+func mappingValue(mapping *yaml.Node, key string) (int, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return -1, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i, mapping.Content[i+1]
+		}
+	}
+	return -1, nil
+}
+
+// validateConfig checks the enumerated settings of the config and its libraries
+func validateConfig(config *frontend_config.FrontendConfig) error {
+	if config.Requires != "" {
+		if _, err := frontend_mgr.ParseVersionRange(config.Requires); err != nil {
+			return fmt.Errorf("invalid requires %q: %w", config.Requires, err)
+		}
+	}
+	if config.CDN != "" && !frontend_config.IsValidCDN(config.CDN) {
+		return fmt.Errorf("invalid cdn %q (must be %s, %s or %s)", config.CDN,
+			frontend_config.CDNUnpkg, frontend_config.CDNCdnjs, frontend_config.CDNJsdelivr)
+	}
+	if !frontend_config.IsValidProfile(config.Profile) {
+		return fmt.Errorf("invalid profile %q (must be %s or %s)", config.Profile, frontend_config.ProfileDev, frontend_config.ProfileProd)
+	}
+	if !frontend_config.IsValidFilesMode(config.FilesMode) {
+		return fmt.Errorf("invalid files_mode %q (must be %s, %s or %s)", config.FilesMode,
+			frontend_config.FilesModeEntrypoints, frontend_config.FilesModeAll, frontend_config.FilesModeMinified)
+	}
+	if config.Concurrency < 0 {
+		return fmt.Errorf("invalid concurrency %d (must be 1 or more)", config.Concurrency)
+	}
+	if !frontend_config.IsValidSourceMaps(config.SourceMaps) {
+		return fmt.Errorf("invalid sourcemaps setting %q (must be %s or %s)", config.SourceMaps,
+			frontend_config.SourceMapsInclude, frontend_config.SourceMapsExclude)
+	}
+	if !frontend_config.IsValidLinkMode(config.LinkMode) {
+		return fmt.Errorf("invalid link mode %q (must be %s, %s or %s)", config.LinkMode,
+			frontend_config.LinkModeCopy, frontend_config.LinkModeSymlink, frontend_config.LinkModeHardlink)
+	}
+	if !frontend_config.IsValidCaseCollisions(config.CaseCollisions) {
+		return fmt.Errorf("invalid case_collisions %q (must be %s, %s or %s)", config.CaseCollisions,
+			frontend_config.CaseCollisionsRename, frontend_config.CaseCollisionsSkip, frontend_config.CaseCollisionsError)
+	}
+
+	if err := validateRequestOptions("", config.Headers, config.Query); err != nil {
+		return err
+	}
+
+	// Development-only libraries follow the same rules
+	all, err := config.WithDevLibraries()
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(all.Libraries) {
+		libConfig := all.Libraries[name]
+		if libConfig.Version == "" {
+			return fmt.Errorf("library '%s' has no version", name)
+		}
+		if libConfig.CDN != "" && !frontend_config.IsValidCDN(libConfig.CDN) {
+			return fmt.Errorf("invalid cdn %q for %s (must be %s, %s or %s)", libConfig.CDN, name,
+				frontend_config.CDNUnpkg, frontend_config.CDNCdnjs, frontend_config.CDNJsdelivr)
+		}
+		if !frontend_config.IsValidFilesMode(libConfig.FilesMode) {
+			return fmt.Errorf("invalid files_mode %q for %s (must be %s, %s or %s)", libConfig.FilesMode, name,
+				frontend_config.FilesModeEntrypoints, frontend_config.FilesModeAll, frontend_config.FilesModeMinified)
+		}
+		if !frontend_config.IsValidSourceMaps(libConfig.SourceMaps) {
+			return fmt.Errorf("invalid sourcemaps setting %q for %s (must be %s or %s)", libConfig.SourceMaps, name,
+				frontend_config.SourceMapsInclude, frontend_config.SourceMapsExclude)
+		}
+		if err := validateRequestOptions(" for "+name, libConfig.Headers, libConfig.Query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRequestOptions checks the hosts and the header and query
+// parameter names of the headers and query keys; suffix names the library
+// they belong to
+func validateRequestOptions(suffix string, headers, query frontend_config.HostValues) error {
+	for _, values := range []frontend_config.HostValues{headers, query} {
+		for _, host := range sortedKeys(values) {
+			if !frontend_config.IsValidHost(host) {
+				return fmt.Errorf("invalid host %q%s (must be a host name such as mirror.example.com)", host, suffix)
+			}
+		}
+	}
+	for _, host := range sortedKeys(headers) {
+		for _, name := range sortedKeys(headers[host]) {
+			if !frontend_config.IsValidHeaderName(name) {
+				return fmt.Errorf("invalid header name %q%s", name, suffix)
+			}
+		}
+	}
+	for _, host := range sortedKeys(query) {
+		if _, ok := query[host][""]; ok {
+			return fmt.Errorf("empty query parameter name%s", suffix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// configDiffContext is the number of unchanged lines shown around changes
+const configDiffContext = 3
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle()
+	diffRemovedStyle = lipgloss.NewStyle()
+	diffContextStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// diffLine is one line of a line diff: op is ' ' (unchanged), '+' or '-'
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// reviewConfigSave shows how saving config would change the file at path
+// and asks before it is written. See reviewConfigChange.
+func reviewConfigSave(path string, config *frontend_config.FrontendConfig) (bool, error) {
+	newData, err := yaml.Marshal(config)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	oldData, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return reviewConfigChange(path, oldData, newData), nil
+}
+
+// reviewConfigChange prints a colorized diff of the pending change to the
+// config file at path and asks for confirmation. The prompt is skipped with
+// --yes, and when not running in a terminal so scripts keep working.
+func reviewConfigChange(path string, oldData, newData []byte) bool {
+	diff := formatConfigDiff(string(oldData), string(newData))
+	if diff == "" {
+		return true
+	}
+
+	fmt.Printf("\nChanges to %s:\n\n%s\n", path, diff)
+	if !interactiveTerminal() && !assumeYesEnabled() {
+		return true
+	}
+	return promptConfirmation(fmt.Sprintf("Write these changes to %s?", path))
+}
+
+// formatConfigDiff renders the changed lines between two versions of a file
+// with a few lines of context, or "" when they are identical
+func formatConfigDiff(oldText, newText string) string {
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	// Mark the unchanged lines close enough to a change to be shown
+	show := make([]bool, len(lines))
+	changed := false
+	for i, line := range lines {
+		if line.op == ' ' {
+			continue
+		}
+		changed = true
+		for j := i - configDiffContext; j <= i+configDiffContext; j++ {
+			if j >= 0 && j < len(lines) {
+				show[j] = true
+			}
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var b strings.Builder
+	skipped := false
+	for i, line := range lines {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped && b.Len() > 0 {
+			b.WriteString(diffContextStyle.Render("  ...") + "\n")
+		}
+		skipped = false
+
+		text := string(line.op) + " " + line.text
+		switch line.op {
+		case '+':
+			text = diffAddedStyle.Render(text)
+		case '-':
+			text = diffRemovedStyle.Render(text)
+		default:
+			text = diffContextStyle.Render(text)
+		}
+		b.WriteString(text + "\n")
+	}
+	return b.String()
+}
+
+// diffLines computes a line diff of a and b from their longest common
+// subsequence, listing removals before additions
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"libraries:", "  jquery:", "    version: 3.6.0", "  lodash:", "    version: 4.17.21"}
+	b := []string{"libraries:", "  jquery:", "    version: 3.7.1", "  lodash:", "    version: 4.17.21", "  htmx.org:", "    version: 2.0.4"}
+
+	var got []string
+	for _, line := range diffLines(a, b) {
+		got = append(got, string(line.op)+line.text)
+	}
+	want := []string{
+		" libraries:",
+		"   jquery:",
+		"-    version: 3.6.0",
+		"+    version: 3.7.1",
+		"   lodash:",
+		"     version: 4.17.21",
+		"+  htmx.org:",
+		"+    version: 2.0.4",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diffLines() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFormatConfigDiff(t *testing.T) {
+	if diff := formatConfigDiff("a: 1\n", "a: 1\n"); diff != "" {
+		t.Errorf("formatConfigDiff() of identical files = %q, want empty", diff)
+	}
+
+	var oldLines, newLines []string
+	for i := 0; i < 20; i++ {
+		line := "line " + string(rune('a'+i))
+		oldLines = append(oldLines, line)
+		newLines = append(newLines, line)
+	}
+	newLines[1] = "changed b"
+	newLines[18] = "changed s"
+
+	diff := formatConfigDiff(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	for _, want := range []string{"- line b", "+ changed b", "- line s", "+ changed s", "  line e", "  ..."} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff is missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "line j") {
+		t.Errorf("diff should not show lines far from a change:\n%s", diff)
+	}
+}
+
+func TestReviewConfigChange(t *testing.T) {
+	origYes := assumeYes
+	defer func() { assumeYes = origYes }()
+	t.Setenv("SMFAMAN_ASSUME_YES", "")
+
+	assumeYes = false
+	if !reviewConfigChange("smartfrontend.yaml", []byte("a: 1\n"), []byte("a: 1\n")) {
+		t.Error("an unchanged config should be written without asking")
+	}
+	// Not a terminal: the diff is shown but nothing is asked
+	if !reviewConfigChange("smartfrontend.yaml", []byte("a: 1\n"), []byte("a: 2\n")) {
+		t.Error("a non-interactive run should write the config")
+	}
+
+	assumeYes = true
+	if !reviewConfigChange("smartfrontend.yaml", []byte("a: 1\n"), []byte("a: 2\n")) {
+		t.Error("--yes should accept the change")
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestResolveConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := &frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+		CDN:         frontend_config.CDNJsdelivr,
+		Profile:     frontend_config.ProfileProd,
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "3.7.1"},
+			"react": {
+				Version:    "18.2.0",
+				CDN:        frontend_config.CDNUnpkg,
+				OutputPath: filepath.Join(tmpDir, "vendor", "react"),
+				Files:      []string{"umd/react.development.js"},
+				FilesProd:  []string{"umd/react.production.min.js"},
+			},
+		},
+	}
+
+	resolved, err := resolveConfig("smartfrontend.yaml", config)
+	if err != nil {
+		t.Fatalf("resolveConfig failed: %v", err)
+	}
+
+	if resolved.FilesMode != frontend_config.FilesModeEntrypoints || resolved.LinkMode != frontend_config.LinkModeCopy {
+		t.Errorf("expected defaults to be filled in, got %+v", resolved)
+	}
+
+	jquery := resolved.Libraries["jquery"]
+	if jquery.CDN != frontend_config.CDNJsdelivr || jquery.CDNFrom != "global" {
+		t.Errorf("unexpected jquery CDN: %s from %s", jquery.CDN, jquery.CDNFrom)
+	}
+	if jquery.Destination != filepath.Join(tmpDir, "libs", "jquery") || jquery.DestinationFrom != "destination" {
+		t.Errorf("unexpected jquery destination: %s from %s", jquery.Destination, jquery.DestinationFrom)
+	}
+	if jquery.FilesFrom != string(frontend_config.FilesModeEntrypoints) {
+		t.Errorf("expected jquery files from entrypoints, got %s", jquery.FilesFrom)
+	}
+
+	react := resolved.Libraries["react"]
+	if react.CDN != frontend_config.CDNUnpkg || react.CDNFrom != "library" {
+		t.Errorf("unexpected react CDN: %s from %s", react.CDN, react.CDNFrom)
+	}
+	if react.Destination != filepath.Join(tmpDir, "vendor", "react") || react.DestinationFrom != "output_path" {
+		t.Errorf("unexpected react destination: %s from %s", react.Destination, react.DestinationFrom)
+	}
+	if react.FilesFrom != "files_prod" || len(react.Files) != 1 || react.Files[0] != "umd/react.production.min.js" {
+		t.Errorf("unexpected react files: %v from %s", react.Files, react.FilesFrom)
+	}
+
+	// Without any CDN configured, sync falls back to unpkg
+	config.CDN = ""
+	resolved, err = resolveConfig("smartfrontend.yaml", config)
+	if err != nil {
+		t.Fatalf("resolveConfig failed: %v", err)
+	}
+	if lib := resolved.Libraries["jquery"]; lib.CDN != frontend_config.CDNUnpkg || lib.CDNFrom != "default" {
+		t.Errorf("expected default unpkg CDN, got %s from %s", lib.CDN, lib.CDNFrom)
+	}
+}
+
+func TestEditConfig(t *testing.T) {
+	newConfig := func() *frontend_config.FrontendConfig {
+		return &frontend_config.FrontendConfig{
+			Destination: "./frontend/{library_name}",
+			Libraries: map[string]frontend_config.LibraryConfig{
+				"react":    {Version: "18.2.0", Files: []string{"umd/react.development.js"}},
+				"chart.js": {Version: "4.4.0"},
+			},
+		}
+	}
+	set := func(value string) *string { return &value }
+
+	t.Run("set global scalar", func(t *testing.T) {
+		updated, err := editConfig(newConfig(), "cdn", set("jsdelivr"))
+		if err != nil {
+			t.Fatalf("editConfig failed: %v", err)
+		}
+		if updated.CDN != frontend_config.CDNJsdelivr {
+			t.Errorf("expected cdn jsdelivr, got %q", updated.CDN)
+		}
+	})
+
+	t.Run("set library list", func(t *testing.T) {
+		updated, err := editConfig(newConfig(), "libraries.react.files", set(`["umd/react.production.min.js"]`))
+		if err != nil {
+			t.Fatalf("editConfig failed: %v", err)
+		}
+		files := updated.Libraries["react"].Files
+		if len(files) != 1 || files[0] != "umd/react.production.min.js" {
+			t.Errorf("unexpected files: %v", files)
+		}
+	})
+
+	t.Run("numeric-looking version stays a string", func(t *testing.T) {
+		updated, err := editConfig(newConfig(), "libraries.chart.js.version", set("4.10"))
+		if err != nil {
+			t.Fatalf("editConfig failed: %v", err)
+		}
+		if v := updated.Libraries["chart.js"].Version; v != "4.10" {
+			t.Errorf("expected version 4.10, got %q", v)
+		}
+	})
+
+	t.Run("unset library field", func(t *testing.T) {
+		updated, err := editConfig(newConfig(), "libraries.react.files", nil)
+		if err != nil {
+			t.Fatalf("editConfig failed: %v", err)
+		}
+		if files := updated.Libraries["react"].Files; len(files) != 0 {
+			t.Errorf("expected files to be removed, got %v", files)
+		}
+	})
+
+	errorCases := []struct {
+		name  string
+		key   string
+		value *string
+	}{
+		{"invalid cdn", "cdn", set("bogus")},
+		{"invalid library cdn", "libraries.react.cdn", set("bogus")},
+		{"unknown key", "colour", set("red")},
+		{"unknown library field", "libraries.react.colour", set("red")},
+		{"wrong type", "libraries.react.files", set("{a: b}")},
+		{"missing library", "libraries.vue.version", set("3.0.0")},
+		{"unset required version", "libraries.react.version", nil},
+		{"unset missing key", "cdn", nil},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := editConfig(newConfig(), tc.key, tc.value); err == nil {
+				t.Errorf("expected an error for %s", tc.key)
+			}
+		})
+	}
+}
+
+func TestGetConfigValue(t *testing.T) {
+	config := &frontend_config.FrontendConfig{
+		Destination: "./frontend/{library_name}",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"react": {Version: "18.2.0", Files: []string{"a.js", "b.js"}},
+		},
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"destination", "./frontend/{library_name}\n"},
+		{"libraries.react.version", "18.2.0\n"},
+		{"libraries.react.files", "- a.js\n- b.js\n"},
+	}
+	for _, tt := range tests {
+		value, err := getConfigValue(config, tt.key)
+		if err != nil {
+			t.Fatalf("getConfigValue(%q) failed: %v", tt.key, err)
+		}
+		if value != tt.expected {
+			t.Errorf("getConfigValue(%q) = %q, want %q", tt.key, value, tt.expected)
+		}
+	}
+
+	if _, err := getConfigValue(config, "cdn"); err == nil {
+		t.Error("expected an error for an unset key")
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// assumeYes answers every confirmation prompt with yes when set via --yes
+var assumeYes bool
+
+// confirmationInput is the reader confirmation prompts read answers from
+var confirmationInput io.Reader = os.Stdin
+
+// assumeYesEnabled reports whether prompts should be skipped, either via
+// the --yes flag or a truthy SMFAMAN_ASSUME_YES environment variable
+func assumeYesEnabled() bool {
+	if assumeYes {
+		return true
+	}
+	enabled, err := strconv.ParseBool(os.Getenv("SMFAMAN_ASSUME_YES"))
+	return err == nil && enabled
+}
+
+// promptConfirmation prompts the user for yes/no confirmation
+func promptConfirmation(message string) bool {
+	if assumeYesEnabled() {
+		fmt.Printf("%s (y/N): y (assumed)\n", message)
+		return true
+	}
+
+	reader := bufio.NewReader(confirmationInput)
+	fmt.Printf("%s (y/N): ", message)
+
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// promptConfirmationDefaultYes prompts for yes/no confirmation, treating an
+// empty answer as yes
+func promptConfirmationDefaultYes(message string) bool {
+	if assumeYesEnabled() {
+		fmt.Printf("%s [Y/n]: y (assumed)\n", message)
+		return true
+	}
+
+	reader := bufio.NewReader(confirmationInput)
+	fmt.Printf("%s [Y/n]: ", message)
+
+	response, err := reader.ReadString('\n')
+	if err != nil && response == "" {
+		return false
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "" || response == "y" || response == "yes"
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptConfirmation(t *testing.T) {
+	origAssumeYes := assumeYes
+	origInput := confirmationInput
+	defer func() {
+		assumeYes = origAssumeYes
+		confirmationInput = origInput
+	}()
+
+	tests := []struct {
+		name      string
+		assumeYes bool
+		env       string
+		input     string
+		expected  bool
+	}{
+		{"answer yes", false, "", "yes\n", true},
+		{"answer y uppercase", false, "", "Y\n", true},
+		{"answer no", false, "", "n\n", false},
+		{"empty answer", false, "", "\n", false},
+		{"no input", false, "", "", false},
+		{"yes flag", true, "", "", true},
+		{"env true", false, "1", "", true},
+		{"env false", false, "false", "", false},
+		{"env invalid", false, "maybe", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SMFAMAN_ASSUME_YES", tt.env)
+			assumeYes = tt.assumeYes
+			confirmationInput = strings.NewReader(tt.input)
+
+			if got := promptConfirmation("Proceed?"); got != tt.expected {
+				t.Errorf("promptConfirmation() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
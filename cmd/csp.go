@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// Serving modes for --mode
+const (
+	cspModeLocal = "local"
+	cspModeCDN   = "cdn"
+)
+
+// CSP directives smfaman fills in
+const (
+	cspScriptSrc = "script-src"
+	cspStyleSrc  = "style-src"
+	cspFontSrc   = "font-src"
+)
+
+// cspDirectives lists the directives in output order
+var cspDirectives = []string{cspScriptSrc, cspStyleSrc, cspFontSrc}
+
+var (
+	cspMode   string
+	cspInline []string
+	cspHeader bool
+)
+
+// cspCmd represents the csp command
+var cspCmd = &cobra.Command{
+	Use:   "csp",
+	Short: "Print the Content-Security-Policy sources the libraries need",
+	Long: `Print the script-src, style-src and font-src values a Content-Security-Policy
+needs for the configured libraries.
+
+With --mode local (the default) the vendored files are served by your site,
+so 'self' is enough. With --mode cdn the pages load the files straight from the
+CDNs, so each library's CDN host is listed instead. Which directives a library
+needs is taken from the files recorded in the lockfile (JavaScript, CSS and
+fonts; CSS may load fonts too), or from its configured file list before the
+first sync.
+
+--inline adds 'sha384-...' sources for inline snippets, such as a loader
+script in a page template. For HTML files each inline <script> and <style>
+block is hashed; .js and .css files are hashed as a whole.
+
+Examples:
+  smfaman csp
+  smfaman csp --mode cdn
+  smfaman csp --mode cdn --header
+  smfaman csp --inline templates/base.html --inline static/loader.js`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCSP(cspMode, cspInline, cspHeader); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cspCmd)
+	cspCmd.Flags().StringVar(&cspMode, "mode", cspModeLocal, "How the files are served: local or cdn")
+	cspCmd.Flags().StringArrayVar(&cspInline, "inline", nil, "HTML, JS or CSS file with inline snippets to hash (repeatable)")
+	cspCmd.Flags().BoolVar(&cspHeader, "header", false, "Print a complete Content-Security-Policy header value")
+}
+
+// runCSP executes the csp command
+func runCSP(mode string, inline []string, header bool) error {
+	if mode != cspModeLocal && mode != cspModeCDN {
+		return fmt.Errorf("invalid --mode %q (must be %s or %s)", mode, cspModeLocal, cspModeCDN)
+	}
+
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+	manifest, err := loadManifest(manifestPathForConfig(FrontendConfig))
+	if err != nil {
+		return err
+	}
+
+	sources, err := cspSources(config, manifest, mode)
+	if err != nil {
+		return err
+	}
+	for _, file := range inline {
+		hashes, err := inlineSnippetHashes(file)
+		if err != nil {
+			return err
+		}
+		for directive, values := range hashes {
+			for _, value := range values {
+				sources[directive] = appendUnique(sources[directive], value)
+			}
+		}
+	}
+
+	if header {
+		parts := []string{"default-src 'self'"}
+		for _, directive := range cspDirectives {
+			if len(sources[directive]) > 0 {
+				parts = append(parts, directive+" "+strings.Join(sources[directive], " "))
+			}
+		}
+		fmt.Println(strings.Join(parts, "; "))
+		return nil
+	}
+
+	for _, directive := range cspDirectives {
+		if len(sources[directive]) > 0 {
+			fmt.Printf("%s %s;\n", directive, strings.Join(sources[directive], " "))
+		}
+	}
+	return nil
+}
+
+// cspSources returns the sources each directive needs for the libraries
+func cspSources(config *frontend_config.FrontendConfig, manifest *fileManifest, mode string) (map[string][]string, error) {
+	// File paths per library: synced ones first, then the configured lists
+	files := make(map[string][]string)
+	for _, entry := range manifest.Files {
+		if _, ok := config.Libraries[entry.Library]; ok {
+			files[entry.Library] = append(files[entry.Library], entry.File)
+		}
+	}
+
+	sources := make(map[string][]string)
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+
+		source := "'self'"
+		if mode == cspModeCDN {
+			cdn := libraryCDN(config, libConfig)
+			u, err := url.Parse(cdnPackageURL(name, libConfig.Version, cdn))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get CDN host for %s: %w", name, err)
+			}
+			source = u.Scheme + "://" + u.Host
+		}
+
+		paths := files[name]
+		if len(paths) == 0 {
+			paths = config.GetLibraryFiles(libConfig)
+		}
+		for _, directive := range cspDirectivesFor(paths) {
+			sources[directive] = appendUnique(sources[directive], source)
+		}
+	}
+	return sources, nil
+}
+
+// cspDirectivesFor returns the directives needed to load the files; without
+// any known files every directive is assumed
+func cspDirectivesFor(paths []string) []string {
+	needed := make(map[string]bool)
+	for _, p := range paths {
+		switch strings.ToLower(path.Ext(p)) {
+		case ".js", ".mjs", ".cjs":
+			needed[cspScriptSrc] = true
+		case ".css":
+			needed[cspStyleSrc] = true
+			needed[cspFontSrc] = true
+		case ".woff", ".woff2", ".ttf", ".otf", ".eot":
+			needed[cspFontSrc] = true
+		}
+	}
+
+	var directives []string
+	for _, directive := range cspDirectives {
+		if needed[directive] || len(needed) == 0 {
+			directives = append(directives, directive)
+		}
+	}
+	return directives
+}
+
+// inlineBlockPattern matches inline <script> and <style> blocks; scripts with
+// a src attribute aren't inline
+var inlineBlockPattern = regexp.MustCompile(`(?is)<(script|style)(\s[^>]*)?>(.*?)</(?:script|style)\s*>`)
+
+var scriptSrcAttrPattern = regexp.MustCompile(`(?i)\ssrc\s*=`)
+
+// inlineSnippetHashes returns the CSP hash sources for the inline snippets in
+// a file, by directive
+func inlineSnippetHashes(file string) (map[string][]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	hashes := make(map[string][]string)
+	add := func(directive string, snippet []byte) {
+		hashes[directive] = appendUnique(hashes[directive], "'"+frontend_mgr.ComputeSRI(snippet)+"'")
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".js", ".mjs":
+		add(cspScriptSrc, data)
+	case ".css":
+		add(cspStyleSrc, data)
+	default:
+		for _, m := range inlineBlockPattern.FindAllSubmatch(data, -1) {
+			tag, attrs, body := strings.ToLower(string(m[1])), m[2], m[3]
+			if tag == "script" {
+				if scriptSrcAttrPattern.Match(attrs) {
+					continue
+				}
+				add(cspScriptSrc, body)
+			} else {
+				add(cspStyleSrc, body)
+			}
+		}
+		if len(hashes) == 0 {
+			return nil, fmt.Errorf("no inline <script> or <style> blocks found in %s", file)
+		}
+	}
+	return hashes, nil
+}
+
+// appendUnique appends value to values unless it is already there
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
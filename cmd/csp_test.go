@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestCSPSources(t *testing.T) {
+	config := &frontend_config.FrontendConfig{
+		CDN: frontend_config.CDNJsdelivr,
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"htmx.org":     {Version: "1.9.10"},
+			"bootstrap":    {Version: "5.3.0", CDN: frontend_config.CDNCdnjs},
+			"font-awesome": {Version: "4.7.0", Files: []string{"fonts/*.woff2"}},
+		},
+	}
+	manifest := &fileManifest{Files: map[string]manifestEntry{
+		"libs/htmx.org/dist/htmx.min.js":        {Library: "htmx.org", File: "dist/htmx.min.js"},
+		"libs/bootstrap/css/bootstrap.min.css":  {Library: "bootstrap", File: "css/bootstrap.min.css"},
+		"libs/bootstrap/js/bootstrap.bundle.js": {Library: "bootstrap", File: "js/bootstrap.bundle.js"},
+		"libs/removed/removed.js":               {Library: "removed", File: "removed.js"},
+	}}
+
+	local, err := cspSources(config, manifest, cspModeLocal)
+	if err != nil {
+		t.Fatalf("cspSources failed: %v", err)
+	}
+	want := map[string][]string{
+		cspScriptSrc: {"'self'"},
+		cspStyleSrc:  {"'self'"},
+		cspFontSrc:   {"'self'"},
+	}
+	if !reflect.DeepEqual(local, want) {
+		t.Errorf("local sources = %v, want %v", local, want)
+	}
+
+	cdn, err := cspSources(config, manifest, cspModeCDN)
+	if err != nil {
+		t.Fatalf("cspSources failed: %v", err)
+	}
+	want = map[string][]string{
+		cspScriptSrc: {"https://cdnjs.cloudflare.com", "https://cdn.jsdelivr.net"},
+		cspStyleSrc:  {"https://cdnjs.cloudflare.com"},
+		cspFontSrc:   {"https://cdnjs.cloudflare.com", "https://cdn.jsdelivr.net"},
+	}
+	if !reflect.DeepEqual(cdn, want) {
+		t.Errorf("cdn sources = %v, want %v", cdn, want)
+	}
+}
+
+func TestCSPDirectivesFor(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  []string
+	}{
+		{[]string{"dist/app.min.js"}, []string{cspScriptSrc}},
+		{[]string{"dist/app.css"}, []string{cspStyleSrc, cspFontSrc}},
+		{[]string{"fonts/icons.woff2", "README.md"}, []string{cspFontSrc}},
+		{nil, []string{cspScriptSrc, cspStyleSrc, cspFontSrc}},
+	}
+	for _, tt := range tests {
+		if got := cspDirectivesFor(tt.paths); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cspDirectivesFor(%v) = %v, want %v", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestInlineSnippetHashes(t *testing.T) {
+	tmpDir := t.TempDir()
+	page := filepath.Join(tmpDir, "base.html")
+	writeTestFile(t, page, `<html><head>
+<script src="/libs/htmx.org/dist/htmx.min.js"></script>
+<script>window.loaded = true;</script>
+<STYLE type="text/css">body { margin: 0 }</STYLE>
+</head></html>`)
+
+	hashes, err := inlineSnippetHashes(page)
+	if err != nil {
+		t.Fatalf("inlineSnippetHashes failed: %v", err)
+	}
+	if len(hashes[cspScriptSrc]) != 1 || len(hashes[cspStyleSrc]) != 1 {
+		t.Fatalf("expected one script and one style hash, got %v", hashes)
+	}
+	if !strings.HasPrefix(hashes[cspScriptSrc][0], "'sha384-") {
+		t.Errorf("unexpected script hash %s", hashes[cspScriptSrc][0])
+	}
+
+	empty := filepath.Join(tmpDir, "empty.html")
+	writeTestFile(t, empty, `<script src="/app.js"></script>`)
+	if _, err := inlineSnippetHashes(empty); err == nil {
+		t.Errorf("expected an error for a file without inline blocks")
+	}
+}
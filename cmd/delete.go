@@ -16,8 +16,8 @@ var deleteCmd = &cobra.Command{
 	Short:   "Remove a library from the Smart Frontend Asset Manager Configuration",
 	Long: `Remove a library from your frontend configuration file.
 
-This command removes the specified library from the configuration file.
-It does NOT delete the actual downloaded files from your filesystem.
+This command removes the specified library from the configuration file,
+whether it is under libraries or dev_libraries. It does NOT delete the actual downloaded files from your filesystem.
 
 The library will be removed from the config file specified by the -f flag
 (default: smartfrontend.yaml).
@@ -32,8 +32,7 @@ Examples:
 		packageName := args[0]
 
 		if err := deleteLibraryFromConfig(packageName); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -50,20 +49,30 @@ func deleteLibraryFromConfig(packageName string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Check if library exists
-	libConfig, exists := config.Libraries[packageName]
+	// Check if library exists, as a runtime or a development-only library
+	libraries := config.Libraries
+	libConfig, exists := libraries[packageName]
 	if !exists {
-		return fmt.Errorf("library '%s' not found in config", packageName)
+		libraries = config.DevLibraries
+		libConfig, exists = libraries[packageName]
+	}
+	if !exists {
+		return notFoundError(fmt.Errorf("library '%s' not found in config", packageName))
 	}
 
 	// Remove library from config
-	delete(config.Libraries, packageName)
+	delete(libraries, packageName)
 
 	// Save config
 	if err := saveConfigForDelete(FrontendConfig, config); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
+	// Drop any recorded metadata snapshot
+	if err := forgetLibraryMetadata(FrontendConfig, packageName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	// Print success message
 	fmt.Printf("\n✓ Library removed successfully!\n\n")
 	fmt.Printf("Package:  %s@%s\n", packageName, libConfig.Version)
@@ -76,29 +85,7 @@ func deleteLibraryFromConfig(packageName string) error {
 
 // loadConfigForDelete loads a frontend config from a file
 func loadConfigForDelete(path string) (*frontend_config.FrontendConfig, error) {
-	// Check if file exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file '%s' does not exist. Run 'smfaman init' first", path)
-	}
-
-	// Read file
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	// Parse YAML
-	var config frontend_config.FrontendConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
-	}
-
-	// Ensure Libraries map is initialized
-	if config.Libraries == nil {
-		config.Libraries = make(map[string]frontend_config.LibraryConfig)
-	}
-
-	return &config, nil
+	return loadConfig(path)
 }
 
 // saveConfigForDelete saves a frontend config to a file
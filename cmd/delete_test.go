@@ -63,6 +63,36 @@ func TestDeleteLibraryFromConfig(t *testing.T) {
 	}
 }
 
+func TestDeleteDevLibrary(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "delete-dev.yaml")
+	testConfig := frontend_config.FrontendConfig{
+		Destination:  "./frontend",
+		Libraries:    map[string]frontend_config.LibraryConfig{"htmx.org": {Version: "2.0.4"}},
+		DevLibraries: map[string]frontend_config.LibraryConfig{"livereload-js": {Version: "4.0.2"}},
+	}
+	data, _ := yaml.Marshal(&testConfig)
+	os.WriteFile(configPath, data, 0644)
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig = oldConfig }()
+
+	if err := deleteLibraryFromConfig("livereload-js"); err != nil {
+		t.Fatalf("failed to delete dev library: %v", err)
+	}
+
+	config, err := loadConfigForDelete(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config after delete: %v", err)
+	}
+	if len(config.DevLibraries) != 0 {
+		t.Errorf("livereload-js should have been deleted, got %v", config.DevLibraries)
+	}
+	if _, exists := config.Libraries["htmx.org"]; !exists {
+		t.Error("htmx.org should still exist in config")
+	}
+}
+
 func TestDeleteLibraryFromConfigNonExistent(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "delete-nonexistent.yaml")
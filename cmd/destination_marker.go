@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// destinationMarkerName is the file sync writes into destination folders it
+// creates; clean only deletes folders that have one
+const destinationMarkerName = ".smfaman"
+
+// markNewDestinations creates the destination folders of tasks that don't
+// exist yet and writes a marker into each, so clean knows smfaman owns them.
+// Existing folders are marked when the config's lockfile shows sync wrote
+// every file in them, which covers folders synced before markers existed.
+func markNewDestinations(configPath string, tasks []DownloadTask) error {
+	var manifest *fileManifest
+	manifestPath := ""
+	if configPath != "" {
+		manifestPath = manifestPathForConfig(configPath)
+		var err error
+		if manifest, err = loadManifest(manifestPath); err != nil {
+			return err
+		}
+	}
+
+	marked := make(map[string]bool)
+	for _, task := range tasks {
+		root := task.DestRoot
+		if root == "" || marked[root] {
+			continue
+		}
+		marked[root] = true
+
+		if _, err := os.Stat(root); err == nil {
+			if manifest != nil && !hasDestinationMarker(root) && syncedDestination(manifestPath, manifest, task.LibraryName, root) {
+				if err := writeDestinationMarker(root, task.LibraryName); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", root, err)
+		}
+		if err := writeDestinationMarker(root, task.LibraryName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDestinationMarker writes the ownership marker for a library's folder
+func writeDestinationMarker(dir, libName string) error {
+	content := fmt.Sprintf("# Created by smfaman for %s. 'smfaman clean' only deletes folders with this file.\n", libName)
+	if err := os.WriteFile(filepath.Join(dir, destinationMarkerName), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write destination marker: %w", err)
+	}
+	return nil
+}
+
+// hasDestinationMarker reports whether smfaman created the folder
+func hasDestinationMarker(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, destinationMarkerName))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// syncedDestination reports whether the lockfile records files of the library
+// in the folder and every other file in it is recorded too, so a folder that
+// merely had a library synced into it alongside user files is not claimed
+func syncedDestination(manifestPath string, manifest *fileManifest, libName, dir string) bool {
+	prefix, err := manifestKey(manifestPath, dir)
+	if err != nil || prefix == "." || strings.HasPrefix(prefix, "../") {
+		return false
+	}
+
+	found := false
+	for key, entry := range manifest.Files {
+		if entry.Library == libName && strings.HasPrefix(key, prefix+"/") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+
+	unrecorded := false
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() == destinationMarkerName {
+			return err
+		}
+		key, err := manifestKey(manifestPath, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := manifest.Files[key]; !ok {
+			unrecorded = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return err == nil && !unrecorded
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkNewDestinations(t *testing.T) {
+	tmpDir := t.TempDir()
+	newDir := filepath.Join(tmpDir, "libs", "jquery")
+	existingDir := filepath.Join(tmpDir, "src")
+	if err := os.MkdirAll(existingDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := []DownloadTask{
+		{LibraryName: "jquery", DestPath: filepath.Join(newDir, "dist", "jquery.js"), DestRoot: newDir},
+		{LibraryName: "jquery", DestPath: filepath.Join(newDir, "dist", "jquery.min.js"), DestRoot: newDir},
+		{LibraryName: "app", DestPath: filepath.Join(existingDir, "app.js"), DestRoot: existingDir},
+	}
+	if err := markNewDestinations("", tasks); err != nil {
+		t.Fatalf("markNewDestinations failed: %v", err)
+	}
+
+	if !hasDestinationMarker(newDir) {
+		t.Errorf("expected a marker in the folder sync created")
+	}
+	if hasDestinationMarker(existingDir) {
+		t.Errorf("folders that already existed must not be claimed")
+	}
+}
+
+func TestMarkDestinationsSyncedBeforeMarkers(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	htmxDir := filepath.Join(tmpDir, "libs", "htmx.org")
+	srcDir := filepath.Join(tmpDir, "src")
+	writeTestFile(t, filepath.Join(htmxDir, "htmx.min.js"), "htmx")
+	writeTestFile(t, filepath.Join(srcDir, "alpine.js"), "alpine")
+	writeTestFile(t, filepath.Join(srcDir, "app.js"), "mine")
+
+	// Both folders were synced into before sync wrote markers, but src also
+	// holds a file the lockfile doesn't know about
+	manifest := &fileManifest{Files: map[string]manifestEntry{
+		"libs/htmx.org/htmx.min.js": {Library: "htmx.org"},
+		"src/alpine.js":             {Library: "alpinejs"},
+	}}
+	if err := saveManifest(manifestPathForConfig(configPath), manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := []DownloadTask{
+		{LibraryName: "htmx.org", DestRoot: htmxDir},
+		{LibraryName: "alpinejs", DestRoot: srcDir},
+	}
+	if err := markNewDestinations(configPath, tasks); err != nil {
+		t.Fatalf("markNewDestinations failed: %v", err)
+	}
+
+	if !hasDestinationMarker(htmxDir) {
+		t.Errorf("expected a marker in the folder the lockfile shows sync wrote")
+	}
+	if hasDestinationMarker(srcDir) {
+		t.Errorf("folders with files sync didn't write must not be claimed")
+	}
+}
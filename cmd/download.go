@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// partialSuffix is appended to a download's destination while it is in
+// progress; an interrupted download resumes from it
+const partialSuffix = ".part"
+
+// downloadProgressFunc reports the bytes received so far. total is -1 when
+// the server didn't send a Content-Length.
+type downloadProgressFunc func(received, total int64)
+
+// downloadToFile downloads url to destPath, retrying transient failures.
+// Data is written to destPath.part and renamed into place when complete;
+// if the part file already exists (after an interruption or a failed
+// attempt) the download resumes from it using a range request.
+func downloadToFile(url, destPath string, progress downloadProgressFunc) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return withRetry(url, func() error {
+		return fetchToFileResumable(url, destPath, progress)
+	})
+}
+
+// fetchToFileResumable performs a single download attempt, continuing a
+// partial download when the server supports it
+func fetchToFileResumable(url, destPath string, progress downloadProgressFunc) error {
+	partPath := destPath + partialSuffix
+
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(frontend_mgr.RequestContext, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && rangeStart(resp) == offset:
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range; start over
+		offset = 0
+		flags |= os.O_TRUNC
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The part file is stale (e.g. the remote file shrank); start over
+		os.Remove(partPath)
+		return fetchToFileResumable(url, destPath, progress)
+	default:
+		return &httpStatusError{StatusCode: resp.StatusCode}
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: resp.Body, received: offset, total: total, report: progress}
+		progress(offset, total)
+	}
+
+	_, err = io.Copy(out, body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Keep the part file so the next attempt can resume
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+	return nil
+}
+
+// rangeStart returns the first byte offset of a Content-Range header
+// ("bytes 100-199/200"), or -1 when it is missing or malformed
+func rangeStart(resp *http.Response) int64 {
+	value, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(value, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// progressReader reports the running byte count as it is read
+type progressReader struct {
+	r        io.Reader
+	received int64
+	total    int64
+	report   downloadProgressFunc
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.received += int64(n)
+	p.report(p.received, p.total)
+	return n, err
+}
+
+// terminalProgress returns a progress function that redraws a single
+// progress line on out with the percentage (when the size is known), the
+// amount downloaded and the transfer speed. Redraws are limited to ten a
+// second; call the returned finish function once the download ends.
+func terminalProgress(out io.Writer) (progress downloadProgressFunc, finish func()) {
+	start := time.Now()
+	var last time.Time
+	var resumedAt int64 = -1
+	var received, total int64
+
+	draw := func() {
+		if resumedAt < 0 {
+			resumedAt = received
+		}
+		speed := ""
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+			speed = fmt.Sprintf(" (%s/s)", formatBytes(int64(float64(received-resumedAt)/elapsed)))
+		}
+		if total > 0 {
+			fmt.Fprintf(out, "\r⬇️  Downloading... %3d%% %s / %s%s   ", received*100/total, formatBytes(received), formatBytes(total), speed)
+		} else {
+			fmt.Fprintf(out, "\r⬇️  Downloading... %s%s   ", formatBytes(received), speed)
+		}
+	}
+
+	progress = func(r, t int64) {
+		if resumedAt < 0 && r > 0 {
+			fmt.Fprintf(out, "↻ Resuming at %s\n", formatBytes(r))
+		}
+		received, total = r, t
+		if resumedAt < 0 || time.Since(last) >= 100*time.Millisecond {
+			draw()
+			last = time.Now()
+		}
+	}
+	finish = func() {
+		draw()
+		fmt.Fprintln(out)
+	}
+	return progress, finish
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadToFileResumes(t *testing.T) {
+	content := []byte(strings.Repeat("starter kit ", 1000))
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "kit.zip", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "kit.zip")
+	writeTestFile(t, dest+partialSuffix, string(content[:500]))
+
+	var received, total int64
+	err := downloadToFile(server.URL, dest, func(r, tot int64) { received, total = r, tot })
+	if err != nil {
+		t.Fatalf("downloadToFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(dest)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Fatalf("downloaded file does not match the source (err %v, %d bytes)", err, len(data))
+	}
+	if _, err := os.Stat(dest + partialSuffix); !os.IsNotExist(err) {
+		t.Error("part file should be renamed into place")
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=500-" {
+		t.Errorf("Range headers = %q, want one request resuming at 500", ranges)
+	}
+	if received != int64(len(content)) || total != int64(len(content)) {
+		t.Errorf("progress = %d/%d, want %d/%d", received, total, len(content), len(content))
+	}
+}
+
+func TestDownloadToFileRestartsWithoutRangeSupport(t *testing.T) {
+	content := []byte("full body from a server without range support")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "kit.zip")
+	writeTestFile(t, dest+partialSuffix, "stale partial data that is longer than nothing")
+
+	if err := downloadToFile(server.URL, dest, nil); err != nil {
+		t.Fatalf("downloadToFile() error = %v", err)
+	}
+	if data, _ := os.ReadFile(dest); !bytes.Equal(data, content) {
+		t.Errorf("downloaded file = %q, want the full body", data)
+	}
+}
+
+func TestDownloadToFileKeepsPartOnFailure(t *testing.T) {
+	origRetries := syncRetries
+	syncRetries = 0
+	defer func() { syncRetries = origRetries }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("only part of the body"))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "kit.zip")
+	if err := downloadToFile(server.URL, dest, nil); err == nil {
+		t.Fatal("downloadToFile() should fail on a truncated body")
+	}
+	if data, err := os.ReadFile(dest + partialSuffix); err != nil || string(data) != "only part of the body" {
+		t.Errorf("part file = %q, %v; want the received bytes kept for resuming", data, err)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Error("destination should not exist after a failed download")
+	}
+}
+
+func TestTerminalProgress(t *testing.T) {
+	var out bytes.Buffer
+	progress, finish := terminalProgress(&out)
+	progress(512, 2048)
+	progress(2048, 2048)
+	finish()
+
+	got := out.String()
+	for _, want := range []string{"Resuming at 512 B", "100%", "2.00 KB / 2.00 KB", "/s)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("progress output %q missing %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// fetchEntrypoints looks up a package's entry files (overridable in tests)
+var fetchEntrypoints = frontend_mgr.FetchJsdelivrEntrypoints
+
+// selectEntrypointFiles narrows files down to the package's recommended
+// browser entry files, falling back to all files when they can't be determined
+func selectEntrypointFiles(libName, version string, files []CDNFile) []CDNFile {
+	resp, err := fetchEntrypoints(libName, version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not determine entry files for %s, downloading all files: %v\n", libName, err)
+		return files
+	}
+
+	entrypoints := resp.Files()
+	var selected []CDNFile
+	for _, file := range files {
+		if slices.Contains(entrypoints, file.Path) {
+			selected = append(selected, file)
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: entry files for %s not found on this CDN, downloading all files\n", libName)
+		return files
+	}
+
+	return selected
+}
+
+// minifiedSuffixes are the file name endings of minified builds
+var minifiedSuffixes = []string{".min.js", ".min.mjs", ".min.css"}
+
+// selectMinifiedFiles narrows files down to minified JS and CSS builds,
+// falling back to all files when the package has none
+func selectMinifiedFiles(libName string, files []CDNFile) []CDNFile {
+	var selected []CDNFile
+	for _, file := range files {
+		if slices.ContainsFunc(minifiedSuffixes, func(suffix string) bool { return strings.HasSuffix(file.Path, suffix) }) {
+			selected = append(selected, file)
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: no minified files found for %s, downloading all files\n", libName)
+		return files
+	}
+
+	return selected
+}
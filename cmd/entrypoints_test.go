@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestSelectEntrypointFiles(t *testing.T) {
+	oldFetch := fetchEntrypoints
+	defer func() { fetchEntrypoints = oldFetch }()
+
+	files := []CDNFile{
+		{Path: "dist/jquery.js"},
+		{Path: "dist/jquery.min.js"},
+		{Path: "dist/jquery.min.js.map"},
+		{Path: "src/core.js"},
+	}
+
+	t.Run("selects entry files", func(t *testing.T) {
+		fetchEntrypoints = func(libName, version string) (*frontend_mgr.JsdelivrEntrypointsResponse, error) {
+			return &frontend_mgr.JsdelivrEntrypointsResponse{
+				Entrypoints: map[string]frontend_mgr.JsdelivrEntrypoint{
+					"js": {File: "/dist/jquery.min.js"},
+				},
+			}, nil
+		}
+
+		selected := selectEntrypointFiles("jquery", "3.7.1", files)
+		if len(selected) != 1 || selected[0].Path != "dist/jquery.min.js" {
+			t.Errorf("expected only dist/jquery.min.js, got %v", selected)
+		}
+	})
+
+	t.Run("falls back when lookup fails", func(t *testing.T) {
+		fetchEntrypoints = func(libName, version string) (*frontend_mgr.JsdelivrEntrypointsResponse, error) {
+			return nil, errors.New("unavailable")
+		}
+
+		if selected := selectEntrypointFiles("jquery", "3.7.1", files); len(selected) != len(files) {
+			t.Errorf("expected all %d files, got %d", len(files), len(selected))
+		}
+	})
+
+	t.Run("falls back when entry files are missing", func(t *testing.T) {
+		fetchEntrypoints = func(libName, version string) (*frontend_mgr.JsdelivrEntrypointsResponse, error) {
+			return &frontend_mgr.JsdelivrEntrypointsResponse{
+				Entrypoints: map[string]frontend_mgr.JsdelivrEntrypoint{
+					"js": {File: "/jquery.min.js"},
+				},
+			}, nil
+		}
+
+		if selected := selectEntrypointFiles("jquery", "3.7.1", files); len(selected) != len(files) {
+			t.Errorf("expected all %d files, got %d", len(files), len(selected))
+		}
+	})
+}
+
+func TestSelectMinifiedFiles(t *testing.T) {
+	files := []CDNFile{
+		{Path: "dist/css/bootstrap.css"},
+		{Path: "dist/css/bootstrap.min.css"},
+		{Path: "dist/js/bootstrap.min.js"},
+		{Path: "dist/js/bootstrap.min.js.map"},
+		{Path: "dist/js/bootstrap.esm.min.mjs"},
+	}
+
+	selected := selectMinifiedFiles("bootstrap", files)
+	if len(selected) != 3 {
+		t.Fatalf("expected the 3 minified builds, got %v", selected)
+	}
+	for _, file := range selected {
+		if file.Path == "dist/css/bootstrap.css" || file.Path == "dist/js/bootstrap.min.js.map" {
+			t.Errorf("unexpected file %s", file.Path)
+		}
+	}
+
+	if selected := selectMinifiedFiles("plain", files[:1]); len(selected) != 1 {
+		t.Errorf("expected a fall back to all files, got %v", selected)
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// Exit codes, so scripts can tell failures apart
+const (
+	exitFailure      = 1 // Any other failure, including invalid flags and arguments
+	exitConfig       = 2 // The config file is missing or can't be read or parsed
+	exitNetwork      = 3 // A CDN or registry couldn't be reached, returned an error or ran past --timeout
+	exitNotFound     = 4 // A library, package, version or other named item doesn't exist
+	exitVerification = 5 // Downloaded content failed integrity or checksum verification
+	exitDrift        = 6 // The vendored files don't match the config and lockfile
+)
+
+// exitError gives an error the exit code smfaman ends with when it is returned
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// configError marks err as a config error
+func configError(err error) error {
+	return &exitError{code: exitConfig, err: err}
+}
+
+// notFoundError marks err as a not found error
+func notFoundError(err error) error {
+	return &exitError{code: exitNotFound, err: err}
+}
+
+// verificationError marks err as a verification failure
+func verificationError(err error) error {
+	return &exitError{code: exitVerification, err: err}
+}
+
+// driftError marks err as drift between config, lockfile and files on disk
+func driftError(err error) error {
+	return &exitError{code: exitDrift, err: err}
+}
+
+// batchError marks err, which sums up several failures, with the exit code
+// the failures share. A verification failure among them decides the code;
+// failures of different kinds give exitFailure.
+func batchError(err error, failures []error) error {
+	code := 0
+	for _, failure := range failures {
+		switch c := exitCode(failure); {
+		case c == exitVerification:
+			return &exitError{code: exitVerification, err: err}
+		case code == 0:
+			code = c
+		case c != code:
+			code = exitFailure
+		}
+	}
+	if code == 0 {
+		return err
+	}
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code for an error. Errors marked with an exit
+// code keep it, even when wrapped; HTTP and network errors map to
+// exitNotFound for 404s and exitNetwork otherwise.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	var statusErr *frontend_mgr.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.NotFound() {
+			return exitNotFound
+		}
+		return exitNetwork
+	}
+	var downloadErr *httpStatusError
+	if errors.As(err, &downloadErr) {
+		if downloadErr.StatusCode == http.StatusNotFound {
+			return exitNotFound
+		}
+		return exitNetwork
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return exitNetwork
+	}
+	var netErr net.Error
+	var urlErr *url.Error
+	if errors.As(err, &netErr) || errors.As(err, &urlErr) {
+		return exitNetwork
+	}
+	return exitFailure
+}
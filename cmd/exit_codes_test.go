@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"plain", errors.New("boom"), exitFailure},
+		{"config", configError(errors.New("bad yaml")), exitConfig},
+		{"wrapped not found", fmt.Errorf("upgrade: %w", notFoundError(errors.New("no such library"))), exitNotFound},
+		{"verification", verificationError(errors.New("integrity mismatch")), exitVerification},
+		{"drift", driftError(errors.New("2 problems found")), exitDrift},
+		{"CDN 404", fmt.Errorf("failed to fetch: %w", &frontend_mgr.StatusError{Source: "UNPKG", StatusCode: 404}), exitNotFound},
+		{"CDN 500", &frontend_mgr.StatusError{Source: "UNPKG", StatusCode: 500}, exitNetwork},
+		{"download 404", &httpStatusError{StatusCode: 404}, exitNotFound},
+		{"download 503", &httpStatusError{StatusCode: 503}, exitNetwork},
+		{"connection", fmt.Errorf("failed to download: %w", &url.Error{Op: "Get", URL: "https://unpkg.com", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}), exitNetwork},
+	}
+
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	summary := errors.New("failed to download 2 files")
+	network := &httpStatusError{StatusCode: 503}
+	notFound := &httpStatusError{StatusCode: 404}
+	verify := verificationError(errors.New("integrity mismatch"))
+
+	tests := []struct {
+		name     string
+		failures []error
+		want     int
+	}{
+		{"same kind", []error{network, network}, exitNetwork},
+		{"mixed kinds", []error{network, notFound}, exitFailure},
+		{"verification wins", []error{network, verify, notFound}, exitVerification},
+		{"no failures", nil, exitFailure},
+	}
+
+	for _, tt := range tests {
+		err := batchError(summary, tt.failures)
+		if got := exitCode(err); got != tt.want {
+			t.Errorf("%s: exit code %d, want %d", tt.name, got, tt.want)
+		}
+		if err.Error() != summary.Error() {
+			t.Errorf("%s: message changed to %q", tt.name, err.Error())
+		}
+	}
+}
+
+func TestCommandErrorsCarryExitCodes(t *testing.T) {
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); exitCode(err) != exitConfig {
+		t.Errorf("missing config gave exit code %d (%v), want %d", exitCode(err), err, exitConfig)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	writeTestFile(t, configPath, "libraries: [not, a, map]\n")
+	if _, err := loadConfig(configPath); exitCode(err) != exitConfig {
+		t.Errorf("invalid config gave exit code %d (%v), want %d", exitCode(err), err, exitConfig)
+	}
+
+	if _, err := findStarterKit("no-such-kit"); exitCode(err) != exitNotFound {
+		t.Errorf("unknown starter kit gave exit code %d (%v), want %d", exitCode(err), err, exitNotFound)
+	}
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var filesLocal bool
+
+// filesCmd represents the files command
+var filesCmd = &cobra.Command{
+	Use:   "files <library> [pattern]",
+	Short: "List the files of a configured library",
+	Long: `List the files a configured library publishes on its CDN, with sizes.
+
+An optional glob pattern filters the list. Patterns without a slash match
+file names in any folder ("*.min.js"); patterns with a slash match the whole
+path ("dist/*.css"). Files selected by the library's current 'files' setting
+are marked with ✓, which helps when writing or debugging file filters.
+
+Use --local to list the files actually present in the library's destination
+folder instead.
+
+Examples:
+  smfaman files bootstrap
+  smfaman files bootstrap "*.min.css"
+  smfaman files react "umd/*"
+  smfaman files jquery --local`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		pattern := ""
+		if len(args) > 1 {
+			pattern = args[1]
+		}
+		if err := runFiles(args[0], pattern); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(filesCmd)
+	filesCmd.Flags().BoolVar(&filesLocal, "local", false, "List files on disk in the library's destination")
+}
+
+// runFiles executes the files command
+func runFiles(libName, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	libConfig, exists := config.Libraries[libName]
+	if !exists {
+		return notFoundError(fmt.Errorf("library '%s' not found in config", libName))
+	}
+
+	var files []CDNFile
+	var source string
+	if filesLocal {
+		destPath, err := config.GetLibraryDestination(libName, libConfig)
+		if err != nil {
+			return fmt.Errorf("failed to get destination for %s: %w", libName, err)
+		}
+		if files, err = listLocalFiles(destPath); err != nil {
+			return err
+		}
+		source = destPath
+	} else {
+		cdn := libraryCDN(config, libConfig)
+		if files, err = fetchFileList(libName, libConfig.Version, cdn); err != nil {
+			return fmt.Errorf("failed to fetch files for %s: %w", libName, err)
+		}
+		source = string(cdn)
+	}
+
+	files = filterByGlob(files, pattern)
+	selected := make(map[string]bool)
+	if patterns := config.GetLibraryFiles(libConfig); len(patterns) > 0 {
+		for _, file := range filterFiles(files, patterns) {
+			selected[file.Path] = true
+		}
+	}
+
+	printFileList(libName, libConfig.Version, source, files, selected)
+	return nil
+}
+
+// filterByGlob keeps files matching a glob pattern. Patterns without a slash
+// are matched against the file name, others against the whole path.
+func filterByGlob(files []CDNFile, pattern string) []CDNFile {
+	if pattern == "" {
+		return files
+	}
+
+	var filtered []CDNFile
+	for _, file := range files {
+		target := file.Path
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(file.Path)
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered
+}
+
+// listLocalFiles lists the files below a destination folder with slash paths
+func listLocalFiles(destPath string) ([]CDNFile, error) {
+	if _, err := os.Stat(destPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("destination %s does not exist (run 'smfaman sync' first)", destPath)
+	}
+
+	var files []CDNFile
+	err := filepath.WalkDir(destPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() == destinationMarkerName {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(destPath, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, CDNFile{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", destPath, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// printFileList prints files with sizes, marking the selected ones
+func printFileList(libName, version, source string, files []CDNFile, selected map[string]bool) {
+	fmt.Printf("Files for %s@%s (%s):\n\n", libName, version, source)
+	if len(files) == 0 {
+		fmt.Println("  No matching files.")
+		return
+	}
+
+	maxPath := 0
+	for _, file := range files {
+		maxPath = max(maxPath, len(file.Path))
+	}
+
+	var totalBytes int64
+	for _, file := range files {
+		marker := " "
+		if selected[file.Path] {
+			marker = "✓"
+		}
+		fmt.Printf("  %s %s  %10s\n", marker, padRight(file.Path, maxPath), formatBytes(file.Size))
+		totalBytes += file.Size
+	}
+
+	fmt.Printf("\n%d %s, %s", len(files), pluralize(len(files), "file", "files"), formatBytes(totalBytes))
+	if len(selected) > 0 {
+		fmt.Printf(" (%d selected by the files setting)", len(selected))
+	}
+	fmt.Println()
+}
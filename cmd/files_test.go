@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFilterByGlob(t *testing.T) {
+	files := []CDNFile{
+		{Path: "dist/css/bootstrap.css"},
+		{Path: "dist/css/bootstrap.min.css"},
+		{Path: "dist/js/bootstrap.min.js"},
+		{Path: "README.md"},
+	}
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"", []string{"dist/css/bootstrap.css", "dist/css/bootstrap.min.css", "dist/js/bootstrap.min.js", "README.md"}},
+		{"*.min.*", []string{"dist/css/bootstrap.min.css", "dist/js/bootstrap.min.js"}},
+		{"dist/css/*", []string{"dist/css/bootstrap.css", "dist/css/bootstrap.min.css"}},
+		{"dist/*", nil},
+		{"*.md", []string{"README.md"}},
+	}
+
+	for _, tt := range tests {
+		filtered := filterByGlob(files, tt.pattern)
+		if len(filtered) != len(tt.expected) {
+			t.Errorf("filterByGlob(%q) returned %d files, want %d", tt.pattern, len(filtered), len(tt.expected))
+			continue
+		}
+		for i, file := range filtered {
+			if file.Path != tt.expected[i] {
+				t.Errorf("filterByGlob(%q)[%d] = %s, want %s", tt.pattern, i, file.Path, tt.expected[i])
+			}
+		}
+	}
+}
+
+func TestListLocalFiles(t *testing.T) {
+	destPath := t.TempDir()
+	writeTestFile(t, filepath.Join(destPath, "dist", "app.min.js"), "console.log(1)")
+	writeTestFile(t, filepath.Join(destPath, "LICENSE"), "MIT")
+
+	files, err := listLocalFiles(destPath)
+	if err != nil {
+		t.Fatalf("listLocalFiles failed: %v", err)
+	}
+	if len(files) != 2 || files[0].Path != "LICENSE" || files[1].Path != "dist/app.min.js" {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+	if files[1].Size != int64(len("console.log(1)")) {
+		t.Errorf("expected size %d, got %d", len("console.log(1)"), files[1].Size)
+	}
+
+	if _, err := listLocalFiles(filepath.Join(destPath, "missing")); err == nil {
+		t.Error("expected an error for a missing destination")
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runInitFlow runs the interactive init flow for a config path (overridable in tests)
+var runInitFlow = func(path string) error {
+	if accessibleEnabled() {
+		return runAccessibleInit(confirmationInput, os.Stdout, path)
+	}
+	if _, err := tea.NewProgram(newInitModel(path)).Run(); err != nil {
+		return fmt.Errorf("error running init: %w", err)
+	}
+	return nil
+}
+
+// offerInit offers to create a missing config file with the init flow on
+// first use. It reports whether the config exists afterwards. Without a
+// terminal nothing is asked, so scripts still get the plain error.
+func offerInit(path string) bool {
+	if !interactiveTerminal() {
+		return false
+	}
+	if !promptConfirmationDefaultYes(fmt.Sprintf("No config found at %s — create one now?", path)) {
+		return false
+	}
+
+	if err := runInitFlow(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return false
+	}
+
+	// The init flow can be cancelled without writing anything
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigOffersInit(t *testing.T) {
+	origTerminal := interactiveTerminal
+	origInit := runInitFlow
+	origInput := confirmationInput
+	defer func() {
+		interactiveTerminal = origTerminal
+		runInitFlow = origInit
+		confirmationInput = origInput
+	}()
+	t.Setenv("SMFAMAN_ASSUME_YES", "")
+
+	initRuns := 0
+	runInitFlow = func(path string) error {
+		initRuns++
+		return os.WriteFile(path, []byte("destination: ./frontend\nlibraries: {}\n"), 0644)
+	}
+
+	t.Run("no terminal", func(t *testing.T) {
+		interactiveTerminal = func() bool { return false }
+		initRuns = 0
+
+		_, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+		if err == nil || !strings.Contains(err.Error(), "smfaman init") {
+			t.Errorf("loadConfig() error = %v, want the init hint", err)
+		}
+		if initRuns != 0 {
+			t.Error("init flow ran without a terminal")
+		}
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		interactiveTerminal = func() bool { return true }
+		confirmationInput = strings.NewReader("n\n")
+		initRuns = 0
+
+		if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("loadConfig() succeeded after declining init")
+		}
+		if initRuns != 0 {
+			t.Error("init flow ran after declining")
+		}
+	})
+
+	t.Run("accepted by default", func(t *testing.T) {
+		interactiveTerminal = func() bool { return true }
+		confirmationInput = strings.NewReader("\n")
+		initRuns = 0
+
+		config, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+		if err != nil {
+			t.Fatalf("loadConfig() error = %v", err)
+		}
+		if initRuns != 1 || config.Destination != "./frontend" {
+			t.Errorf("initRuns = %d, destination = %q, want the new config loaded", initRuns, config.Destination)
+		}
+	})
+
+	t.Run("init cancelled", func(t *testing.T) {
+		interactiveTerminal = func() bool { return true }
+		confirmationInput = strings.NewReader("y\n")
+		runInitFlow = func(path string) error { return nil }
+
+		if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("loadConfig() succeeded although init wrote no config")
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
 )
 
 var (
@@ -29,7 +30,9 @@ and saves it to the local directory. The file is saved using the name specified
 the -f flag (default: smartfrontend.yaml).
 
 The downloaded config is validated to ensure it's a valid frontend configuration
-before being saved. If the target file already exists, use --force to overwrite it.
+before being saved. If the target file already exists, use --force to overwrite it;
+the changes are then shown as a diff and, in a terminal, confirmed before the
+file is replaced (--yes skips the question).
 
 Example:
   smfaman get https://example.com/frontend.yaml
@@ -40,8 +43,7 @@ Example:
 		configURL := args[0]
 
 		if err := downloadAndSaveConfig(configURL, FrontendConfig); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -80,7 +82,11 @@ func downloadAndSaveConfig(configURL, targetPath string) error {
 	}
 
 	// Download the file
-	resp, err := client.Get(configURL)
+	req, err := http.NewRequestWithContext(frontend_mgr.RequestContext, http.MethodGet, configURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to download config: %w", err)
 	}
@@ -121,6 +127,15 @@ func downloadAndSaveConfig(configURL, targetPath string) error {
 	if config.Libraries == nil {
 		return fmt.Errorf("config validation failed: libraries field is required")
 	}
+	if err := checkRequiredVersion(configURL, &config); err != nil {
+		return err
+	}
+
+	// Show what overwriting an existing config changes before doing it
+	if existing, err := os.ReadFile(targetPath); err == nil && !reviewConfigChange(targetPath, existing, body) {
+		fmt.Println("No changes made to config file.")
+		return nil
+	}
 
 	// Save to file
 	if err := os.WriteFile(targetPath, body, 0644); err != nil {
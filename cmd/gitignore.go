@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+var (
+	gitignoreMode   string
+	gitignoreDryRun bool
+)
+
+// Modes for the gitignore command
+const (
+	gitignoreModeIgnore = "ignore"
+	gitignoreModeVendor = "vendor"
+)
+
+// Markers around the block smfaman manages in .gitignore/.gitattributes
+const (
+	gitBlockBegin = "# >>> smfaman vendored assets >>>"
+	gitBlockEnd   = "# <<< smfaman vendored assets <<<"
+)
+
+// gitignoreCmd represents the gitignore command
+var gitignoreCmd = &cobra.Command{
+	Use:   "gitignore",
+	Short: "Keep vendored library folders out of git diffs",
+	Long: `Add the resolved destination folders of all libraries (including mirrors)
+to .gitignore, or mark them as vendored in .gitattributes.
+
+The entries are written between marker comments, so running the command
+again (e.g. after adding a library or changing a destination) replaces the
+block instead of appending duplicates. Anything outside the markers is left
+untouched. Paths are written relative to the current directory; destinations
+outside it are skipped.
+
+Modes:
+  ignore  Add the folders to .gitignore (default). Run 'smfaman sync' after
+          cloning to restore them.
+  vendor  Mark the folders linguist-vendored in .gitattributes, so they stay
+          committed but are collapsed in GitHub diffs and excluded from
+          language statistics.
+
+Examples:
+  smfaman gitignore
+  smfaman gitignore --mode vendor
+  smfaman gitignore --dry-run
+  smfaman -f myproject.yaml gitignore`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runGitignore(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gitignoreCmd)
+
+	gitignoreCmd.Flags().StringVar(&gitignoreMode, "mode", gitignoreModeIgnore, "What to write: ignore (.gitignore) or vendor (.gitattributes)")
+	gitignoreCmd.Flags().BoolVar(&gitignoreDryRun, "dry-run", false, "Show the entries without writing the file")
+}
+
+func runGitignore() error {
+	var fileName string
+	switch gitignoreMode {
+	case gitignoreModeIgnore:
+		fileName = ".gitignore"
+	case gitignoreModeVendor:
+		fileName = ".gitattributes"
+	default:
+		return fmt.Errorf("invalid mode '%s' (use ignore or vendor)", gitignoreMode)
+	}
+
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	paths, skipped, err := vendoredPaths(config, baseDir)
+	if err != nil {
+		return err
+	}
+	for _, dir := range skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %s, it is outside the current directory\n", dir)
+	}
+	if len(paths) == 0 {
+		fmt.Println("No library destinations to add.")
+		return nil
+	}
+
+	block := gitBlockLines(gitignoreMode, paths)
+
+	existing, err := os.ReadFile(fileName)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+	updated := replaceMarkedBlock(string(existing), block)
+
+	fmt.Printf("%s entries (%d):\n", fileName, len(paths))
+	for _, line := range block[1 : len(block)-1] {
+		fmt.Printf("  %s\n", line)
+	}
+
+	if updated == string(existing) {
+		fmt.Printf("\n✓ %s is already up to date\n", fileName)
+		return nil
+	}
+
+	if gitignoreDryRun {
+		fmt.Printf("\n[DRY RUN] No changes made to %s.\n", fileName)
+		return nil
+	}
+
+	if err := os.WriteFile(fileName, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", fileName, err)
+	}
+	fmt.Printf("\n✓ Updated %s\n", fileName)
+	return nil
+}
+
+// vendoredPaths returns the library destination and mirror folders as
+// slash-separated paths relative to baseDir, sorted and without folders
+// nested in another entry. Folders outside baseDir are returned in skipped.
+func vendoredPaths(config *frontend_config.FrontendConfig, baseDir string) (paths, skipped []string, err error) {
+	dirs := make(map[string]bool)
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+
+		dest, err := config.GetLibraryDestination(name, libConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get destination for %s: %w", name, err)
+		}
+		mirrors, err := config.GetLibraryMirrors(name, libConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get mirrors for %s: %w", name, err)
+		}
+
+		for _, dir := range append([]string{dest}, mirrors...) {
+			rel, err := filepath.Rel(baseDir, dir)
+			if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				skipped = append(skipped, dir)
+				continue
+			}
+			dirs[filepath.ToSlash(rel)] = true
+		}
+	}
+
+	sorted := sortedKeys(dirs)
+	for _, dir := range sorted {
+		nested := false
+		for _, other := range sorted {
+			if other != dir && strings.HasPrefix(dir, other+"/") {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			paths = append(paths, dir)
+		}
+	}
+	sort.Strings(skipped)
+	return paths, skipped, nil
+}
+
+// gitBlockLines returns the marked block for the given mode: folder
+// patterns for .gitignore or linguist-vendored attributes for .gitattributes
+func gitBlockLines(mode string, paths []string) []string {
+	lines := []string{gitBlockBegin}
+	for _, p := range paths {
+		if mode == gitignoreModeVendor {
+			lines = append(lines, "/"+p+"/** linguist-vendored")
+		} else {
+			lines = append(lines, "/"+p+"/")
+		}
+	}
+	return append(lines, gitBlockEnd)
+}
+
+// replaceMarkedBlock replaces the marked block in content with block, or
+// appends it when content has no block yet. The first and last lines of
+// block are its begin and end markers.
+func replaceMarkedBlock(content string, block []string) string {
+	blockText := strings.Join(block, "\n") + "\n"
+
+	if start, end, ok := findMarkedBlock(content, block[0], block[len(block)-1]); ok {
+		return content[:start] + blockText + content[end:]
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + blockText
+}
+
+// removeMarkedBlock removes the block between the begin and end markers
+// (inclusive) from content, reporting whether one was found
+func removeMarkedBlock(content, begin, end string) (string, bool) {
+	start, stop, ok := findMarkedBlock(content, begin, end)
+	if !ok {
+		return content, false
+	}
+	return content[:start] + content[stop:], true
+}
+
+// findMarkedBlock returns the byte range of the block between the begin and
+// end markers, including the end marker's trailing newline
+func findMarkedBlock(content, begin, end string) (start, stop int, ok bool) {
+	start = strings.Index(content, begin)
+	if start < 0 {
+		return 0, 0, false
+	}
+	stop = strings.Index(content[start:], end)
+	if stop < 0 {
+		return 0, 0, false
+	}
+	stop += start + len(end)
+	if stop < len(content) && content[stop] == '\n' {
+		stop++
+	}
+	return start, stop, true
+}
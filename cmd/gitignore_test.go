@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestVendoredPaths(t *testing.T) {
+	base := t.TempDir()
+	config := &frontend_config.FrontendConfig{
+		Destination: filepath.Join(base, "public", "libs", "{library_name}"),
+		Mirrors:     []string{filepath.Join(base, "docs", "vendor", "{library_name}")},
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":    {Version: "3.7.1"},
+			"bootstrap": {Version: "5.3.0", OutputPath: filepath.Join(base, "public", "libs")},
+			"outside":   {Version: "1.0.0", OutputPath: filepath.Join(filepath.Dir(base), "elsewhere")},
+		},
+	}
+
+	paths, skipped, err := vendoredPaths(config, base)
+	if err != nil {
+		t.Fatalf("vendoredPaths() error = %v", err)
+	}
+
+	want := []string{"docs/vendor/bootstrap", "docs/vendor/jquery", "docs/vendor/outside", "public/libs"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("vendoredPaths() = %v, want %v", paths, want)
+	}
+	if len(skipped) != 1 || !strings.HasSuffix(skipped[0], "elsewhere") {
+		t.Errorf("skipped = %v, want the destination outside the base directory", skipped)
+	}
+}
+
+func TestReplaceMarkedBlock(t *testing.T) {
+	block := gitBlockLines(gitignoreModeIgnore, []string{"public/libs"})
+
+	content := replaceMarkedBlock("node_modules/\n", block)
+	want := "node_modules/\n\n" + gitBlockBegin + "\n/public/libs/\n" + gitBlockEnd + "\n"
+	if content != want {
+		t.Fatalf("first run = %q, want %q", content, want)
+	}
+
+	if again := replaceMarkedBlock(content, block); again != content {
+		t.Errorf("second run changed the file: %q", again)
+	}
+
+	content += "*.log\n"
+	updated := replaceMarkedBlock(content, gitBlockLines(gitignoreModeIgnore, []string{"static/vendor"}))
+	if strings.Contains(updated, "public/libs") || !strings.Contains(updated, "/static/vendor/") {
+		t.Errorf("block should be replaced, got %q", updated)
+	}
+	if !strings.HasPrefix(updated, "node_modules/\n") || !strings.HasSuffix(updated, "*.log\n") {
+		t.Errorf("content outside the markers should be kept, got %q", updated)
+	}
+
+	if got := replaceMarkedBlock("", block); got != strings.Join(block, "\n")+"\n" {
+		t.Errorf("empty file = %q", got)
+	}
+}
+
+func TestGitBlockLinesVendorMode(t *testing.T) {
+	lines := gitBlockLines(gitignoreModeVendor, []string{"public/libs"})
+	if lines[1] != "/public/libs/** linguist-vendored" {
+		t.Errorf("vendor line = %q", lines[1])
+	}
+}
@@ -0,0 +1,481 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var healthFormat string
+
+var (
+	healthGreenStyle   = lipgloss.NewStyle()
+	healthYellowStyle  = lipgloss.NewStyle()
+	healthRedStyle     = lipgloss.NewStyle()
+	healthUnknownStyle = lipgloss.NewStyle()
+)
+
+// Health lookups (overridable in tests)
+var (
+	fetchHealthVersions   versionFetcher = fetchVersionsForUpgrade
+	fetchHealthPackument                 = frontend_mgr.FetchNpmPackument
+	fetchHealthAdvisories                = frontend_mgr.FetchAdvisories
+)
+
+// Freshness and size thresholds between the green, yellow and red ratings
+const (
+	healthFreshDays  = 180 // Released less than this long before the latest is green
+	healthStaleDays  = 540 // and more than this long before it is red
+	healthSmallBytes = 2 << 20
+	healthLargeBytes = 10 << 20
+)
+
+// healthRating is the traffic-light rating of a library or a single check
+type healthRating int
+
+const (
+	ratingUnknown healthRating = iota // The check couldn't be made; doesn't count
+	ratingGreen
+	ratingYellow
+	ratingRed
+)
+
+// String returns the color name of the rating
+func (r healthRating) String() string {
+	switch r {
+	case ratingGreen:
+		return "green"
+	case ratingYellow:
+		return "yellow"
+	case ratingRed:
+		return "red"
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the rating as its color name in JSON output
+func (r healthRating) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// marker returns the symbol shown before a rated value, so ratings can be
+// told apart without color
+func (r healthRating) marker() string {
+	switch r {
+	case ratingGreen:
+		return "✓"
+	case ratingYellow:
+		return "!"
+	case ratingRed:
+		return "✗"
+	}
+	return "?"
+}
+
+// render styles text in the color of the rating
+func (r healthRating) render(text string) string {
+	switch r {
+	case ratingGreen:
+		return healthGreenStyle.Render(text)
+	case ratingYellow:
+		return healthYellowStyle.Render(text)
+	case ratingRed:
+		return healthRedStyle.Render(text)
+	}
+	return healthUnknownStyle.Render(text)
+}
+
+// score returns the points a library with the rating contributes to the
+// project score
+func (r healthRating) score() int {
+	switch r {
+	case ratingGreen:
+		return 100
+	case ratingYellow:
+		return 50
+	}
+	return 0
+}
+
+// healthCheck is the outcome of one health check of a library
+type healthCheck struct {
+	Rating healthRating `json:"rating"`
+	Detail string       `json:"detail"`
+}
+
+// libraryHealth is the health of one configured library
+type libraryHealth struct {
+	Library         string       `json:"library"`
+	Version         string       `json:"version"`
+	Resolved        string       `json:"resolved,omitempty"`
+	Latest          string       `json:"latest,omitempty"`
+	Freshness       healthCheck  `json:"freshness"`
+	Vulnerabilities healthCheck  `json:"vulnerabilities"`
+	Deprecation     healthCheck  `json:"deprecation"`
+	Size            healthCheck  `json:"size"`
+	Rating          healthRating `json:"rating"`
+}
+
+// checks returns the checks of the library in table order
+func (h libraryHealth) checks() []healthCheck {
+	return []healthCheck{h.Freshness, h.Vulnerabilities, h.Deprecation, h.Size}
+}
+
+// projectHealth is the health of every library and the overall score
+type projectHealth struct {
+	Libraries []libraryHealth `json:"libraries"`
+	Score     int             `json:"score"`
+	Rating    healthRating    `json:"rating"`
+}
+
+// healthCmd represents the health command
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Score the health of each configured library",
+	Long: `Score each configured library green, yellow or red on four checks and
+show an overall project score, for periodic reviews of what is vendored.
+
+Checks:
+  freshness        How long before the latest stable release the configured
+                   version came out: green under 6 months, yellow under 18
+                   months, red beyond (by major and minor version when the
+                   release dates aren't known)
+  vulnerabilities  Open npm security advisories for the configured version:
+                   yellow for low or moderate, red for high or critical
+  deprecated       Red when the configured version is deprecated on npm
+  size             Size of the synced files on disk: green under 2 MB,
+                   yellow under 10 MB, red beyond
+
+A library is rated by its worst check. The project score is the average of
+the libraries, counting green as 100, yellow as 50 and red as 0; libraries
+none of the checks could be made for are left out.
+
+Formats:
+  table  Colored table (default)
+  json   JSON document
+
+Examples:
+  smfaman health
+  smfaman health --format json > health.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runHealth(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+	healthCmd.Flags().StringVar(&healthFormat, "format", "table", "Output format (table, json)")
+}
+
+// runHealth executes the health command
+func runHealth() error {
+	if healthFormat != "table" && healthFormat != "json" {
+		return fmt.Errorf("unsupported health format %q (must be table or json)", healthFormat)
+	}
+
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	if healthFormat == "table" {
+		fmt.Printf("Checking %d %s...\n\n", len(config.Libraries), pluralize(len(config.Libraries), "library", "libraries"))
+	}
+	health, err := assessProjectHealth(config)
+	if err != nil {
+		return err
+	}
+
+	if healthFormat == "json" {
+		data, err := json.MarshalIndent(health, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(renderHealthTable(health))
+	return nil
+}
+
+// assessProjectHealth checks every library, sorted by name, and scores the project
+func assessProjectHealth(config *frontend_config.FrontendConfig) (*projectHealth, error) {
+	health := &projectHealth{Libraries: []libraryHealth{}}
+
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+		cdn := libraryCDN(config, libConfig)
+		destPath, err := config.GetLibraryDestination(name, libConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get destination for %s: %w", name, err)
+		}
+		health.Libraries = append(health.Libraries, assessLibraryHealth(name, libConfig.Version, cdn, destPath))
+	}
+
+	// Advisories for every library are looked up in one request
+	versions := make(map[string]string)
+	for _, h := range health.Libraries {
+		if h.Resolved != "" {
+			versions[h.Library] = h.Resolved
+		}
+	}
+	var advisories map[string][]frontend_mgr.Advisory
+	if len(versions) > 0 {
+		var err error
+		if advisories, err = fetchHealthAdvisories(versions); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: vulnerabilities not checked: %v\n", err)
+		}
+	}
+
+	total, rated := 0, 0
+	for i := range health.Libraries {
+		h := &health.Libraries[i]
+		if _, checked := versions[h.Library]; checked && advisories != nil {
+			h.Vulnerabilities = vulnerabilityCheck(advisories[h.Library])
+		} else {
+			h.Vulnerabilities = healthCheck{Rating: ratingUnknown, Detail: "not checked"}
+		}
+		if h.Rating = worstRating(h.checks()); h.Rating != ratingUnknown {
+			total += h.Rating.score()
+			rated++
+		}
+	}
+
+	if rated > 0 {
+		health.Score = total / rated
+	} else {
+		health.Score = 100
+	}
+	health.Rating = scoreRating(health.Score)
+	return health, nil
+}
+
+// assessLibraryHealth runs the freshness, deprecation and size checks of a
+// library; vulnerabilities are checked for all libraries together
+func assessLibraryHealth(name, spec string, cdn frontend_config.CDN, destPath string) libraryHealth {
+	h := libraryHealth{
+		Library:     name,
+		Version:     spec,
+		Freshness:   healthCheck{Rating: ratingUnknown, Detail: "unknown"},
+		Deprecation: healthCheck{Rating: ratingUnknown, Detail: "unknown"},
+		Size:        sizeCheck(destPath),
+	}
+
+	versions, tagged, err := fetchHealthVersions(name, cdn)
+	if err != nil {
+		h.Freshness.Detail = "lookup failed"
+		return h
+	}
+	h.Latest = frontend_mgr.LatestVersion(versions, tagged, false)
+	if h.Resolved, err = frontend_mgr.ResolveVersionSpec(spec, versions, map[string]string{"latest": h.Latest}); err != nil {
+		h.Freshness.Detail = "not published"
+		return h
+	}
+
+	// Release dates and deprecations come from the npm registry, which
+	// cdnjs-only libraries aren't on
+	packument, err := fetchHealthPackument(name)
+	if err != nil {
+		h.Freshness = freshnessCheck(h.Resolved, h.Latest, 0, false)
+		return h
+	}
+
+	pinnedAt, pinnedKnown := packument.PublishedAt(h.Resolved)
+	latestAt, latestKnown := packument.PublishedAt(h.Latest)
+	h.Freshness = freshnessCheck(h.Resolved, h.Latest, latestAt.Sub(pinnedAt), pinnedKnown && latestKnown)
+
+	if message := packument.DeprecationMessage(h.Resolved); message != "" {
+		h.Deprecation = healthCheck{Rating: ratingRed, Detail: "deprecated: " + message}
+	} else {
+		h.Deprecation = healthCheck{Rating: ratingGreen, Detail: "no"}
+	}
+	return h
+}
+
+// freshnessCheck rates how far the configured version is behind the latest,
+// by the time between their releases when known, else by version number
+func freshnessCheck(current, latest string, behind time.Duration, timeKnown bool) healthCheck {
+	cmp, err := frontend_mgr.CompareVersions(current, latest)
+	if err != nil {
+		return healthCheck{Rating: ratingUnknown, Detail: "unknown"}
+	}
+	if cmp >= 0 {
+		return healthCheck{Rating: ratingGreen, Detail: "up to date"}
+	}
+
+	if timeKnown {
+		days := int(behind.Hours() / 24)
+		detail := fmt.Sprintf("%s behind %s", formatAge(days), latest)
+		switch {
+		case days < healthFreshDays:
+			return healthCheck{Rating: ratingGreen, Detail: detail}
+		case days < healthStaleDays:
+			return healthCheck{Rating: ratingYellow, Detail: detail}
+		}
+		return healthCheck{Rating: ratingRed, Detail: detail}
+	}
+
+	detail := "behind " + latest
+	currentMajor, _ := frontend_mgr.MajorVersion(current)
+	latestMajor, _ := frontend_mgr.MajorVersion(latest)
+	switch {
+	case currentMajor != latestMajor:
+		return healthCheck{Rating: ratingRed, Detail: detail}
+	case minorVersion(current) != minorVersion(latest):
+		return healthCheck{Rating: ratingYellow, Detail: detail}
+	}
+	return healthCheck{Rating: ratingGreen, Detail: detail}
+}
+
+// minorVersion returns the major.minor part of a version
+func minorVersion(v string) string {
+	parts := strings.SplitN(frontend_mgr.NormalizeVersion(v), ".", 3)
+	if len(parts) < 2 {
+		return v
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// formatAge formats a number of days as days, months or years
+func formatAge(days int) string {
+	switch {
+	case days < 60:
+		return fmt.Sprintf("%d %s", days, pluralize(days, "day", "days"))
+	case days < 730:
+		return fmt.Sprintf("%d months", days/30)
+	}
+	return fmt.Sprintf("%d years", days/365)
+}
+
+// vulnerabilityCheck rates a library by its most severe advisory
+func vulnerabilityCheck(advisories []frontend_mgr.Advisory) healthCheck {
+	if len(advisories) == 0 {
+		return healthCheck{Rating: ratingGreen, Detail: "none"}
+	}
+
+	worst := advisories[0].Severity
+	for _, a := range advisories[1:] {
+		if frontend_mgr.SeverityRank(a.Severity) > frontend_mgr.SeverityRank(worst) {
+			worst = a.Severity
+		}
+	}
+
+	rating := ratingYellow
+	if frontend_mgr.SeverityRank(worst) >= frontend_mgr.SeverityRank("high") {
+		rating = ratingRed
+	}
+	return healthCheck{Rating: rating, Detail: fmt.Sprintf("%d (%s)", len(advisories), worst)}
+}
+
+// sizeCheck rates the size of a library's synced files on disk
+func sizeCheck(destPath string) healthCheck {
+	info, err := os.Stat(destPath)
+	if err != nil || !info.IsDir() {
+		return healthCheck{Rating: ratingUnknown, Detail: "not synced"}
+	}
+	_, bytes, err := directoryUsage(destPath)
+	if err != nil {
+		return healthCheck{Rating: ratingUnknown, Detail: "unknown"}
+	}
+
+	detail := formatBytes(bytes)
+	switch {
+	case bytes < healthSmallBytes:
+		return healthCheck{Rating: ratingGreen, Detail: detail}
+	case bytes < healthLargeBytes:
+		return healthCheck{Rating: ratingYellow, Detail: detail}
+	}
+	return healthCheck{Rating: ratingRed, Detail: detail}
+}
+
+// worstRating returns the worst known rating among checks, or unknown when
+// none could be made
+func worstRating(checks []healthCheck) healthRating {
+	worst := ratingUnknown
+	for _, c := range checks {
+		worst = max(worst, c.Rating)
+	}
+	return worst
+}
+
+// scoreRating rates a project score
+func scoreRating(score int) healthRating {
+	switch {
+	case score >= 80:
+		return ratingGreen
+	case score >= 50:
+		return ratingYellow
+	}
+	return ratingRed
+}
+
+// renderHealthTable renders the libraries as a table followed by the
+// project score
+func renderHealthTable(health *projectHealth) string {
+	var s strings.Builder
+	if len(health.Libraries) == 0 {
+		s.WriteString("No libraries are configured.\n")
+		return s.String()
+	}
+
+	headers := []string{"LIBRARY", "VERSION", "FRESHNESS", "VULNERABILITIES", "DEPRECATED", "SIZE", "HEALTH"}
+	rows := make([][]string, len(health.Libraries))
+	ratings := make([][]healthRating, len(health.Libraries))
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+
+	for i, h := range health.Libraries {
+		rows[i] = []string{h.Library, h.Version}
+		ratings[i] = []healthRating{ratingUnknown, ratingUnknown}
+		for _, c := range h.checks() {
+			rows[i] = append(rows[i], c.Rating.marker()+" "+truncate(c.Detail, 40))
+			ratings[i] = append(ratings[i], c.Rating)
+		}
+		rows[i] = append(rows[i], h.Rating.marker()+" "+h.Rating.String())
+		ratings[i] = append(ratings[i], h.Rating)
+
+		for col, cell := range rows[i] {
+			widths[col] = max(widths[col], lipgloss.Width(cell))
+		}
+	}
+
+	writeRow := func(cells []string, rated []healthRating) {
+		for col, cell := range cells {
+			padded := cell
+			if col < len(cells)-1 {
+				padded += strings.Repeat(" ", widths[col]-lipgloss.Width(cell)+2)
+			}
+			if rated != nil && col >= 2 {
+				padded = rated[col].render(padded)
+			}
+			s.WriteString(padded)
+		}
+		s.WriteString("\n")
+	}
+
+	writeRow(headers, nil)
+	rule := make([]string, len(headers))
+	for col, w := range widths {
+		rule[col] = strings.Repeat("─", w)
+	}
+	writeRow(rule, nil)
+	for i := range rows {
+		writeRow(rows[i], ratings[i])
+	}
+
+	s.WriteString(fmt.Sprintf("\nProject score: %s\n",
+		health.Rating.render(fmt.Sprintf("%d/100 (%s)", health.Score, health.Rating))))
+	return s.String()
+}
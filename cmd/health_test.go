@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestFreshnessCheck(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		name            string
+		current, latest string
+		behind          time.Duration
+		timeKnown       bool
+		want            healthRating
+	}{
+		{"up to date", "3.7.1", "3.7.1", 0, true, ratingGreen},
+		{"recent release behind", "3.7.0", "3.7.1", 30 * day, true, ratingGreen},
+		{"a year behind", "3.5.1", "3.7.1", 365 * day, true, ratingYellow},
+		{"years behind", "1.12.4", "3.7.1", 3000 * day, true, ratingRed},
+		{"patch behind without dates", "3.7.0", "3.7.1", 0, false, ratingGreen},
+		{"minor behind without dates", "3.5.1", "3.7.1", 0, false, ratingYellow},
+		{"major behind without dates", "2.2.4", "3.7.1", 0, false, ratingRed},
+		{"unparseable", "latest", "3.7.1", 0, false, ratingUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := freshnessCheck(tt.current, tt.latest, tt.behind, tt.timeKnown); got.Rating != tt.want {
+			t.Errorf("%s: freshnessCheck() = %v (%s), want %v", tt.name, got.Rating, got.Detail, tt.want)
+		}
+	}
+}
+
+func TestVulnerabilityCheck(t *testing.T) {
+	if got := vulnerabilityCheck(nil); got.Rating != ratingGreen {
+		t.Errorf("no advisories rated %v", got.Rating)
+	}
+	got := vulnerabilityCheck([]frontend_mgr.Advisory{{Severity: "low"}, {Severity: "high"}, {Severity: "moderate"}})
+	if got.Rating != ratingRed || got.Detail != "3 (high)" {
+		t.Errorf("vulnerabilityCheck() = %v %q, want red 3 (high)", got.Rating, got.Detail)
+	}
+	if got := vulnerabilityCheck([]frontend_mgr.Advisory{{Severity: "moderate"}}); got.Rating != ratingYellow {
+		t.Errorf("moderate advisory rated %v, want yellow", got.Rating)
+	}
+}
+
+func TestAssessProjectHealth(t *testing.T) {
+	origVersions, origPackument, origAdvisories := fetchHealthVersions, fetchHealthPackument, fetchHealthAdvisories
+	defer func() {
+		fetchHealthVersions, fetchHealthPackument, fetchHealthAdvisories = origVersions, origPackument, origAdvisories
+	}()
+
+	fetchHealthVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		switch packageName {
+		case "jquery":
+			return []string{"3.7.1", "3.5.1"}, "3.7.1", nil
+		case "htmx.org":
+			return []string{"2.0.4"}, "2.0.4", nil
+		}
+		return nil, "", errors.New("package not found")
+	}
+	fetchHealthPackument = func(name string) (*frontend_mgr.UnpkgPackageResponse, error) {
+		doc := &frontend_mgr.UnpkgPackageResponse{Time: map[string]string{
+			"3.5.1": "2020-05-04T00:00:00Z",
+			"3.7.1": "2023-08-28T00:00:00Z",
+			"2.0.4": "2024-12-13T00:00:00Z",
+		}}
+		return doc, nil
+	}
+	fetchHealthAdvisories = func(versions map[string]string) (map[string][]frontend_mgr.Advisory, error) {
+		if versions["jquery"] != "3.5.1" {
+			t.Errorf("expected advisories to be checked for jquery 3.5.1, got %v", versions)
+		}
+		return map[string][]frontend_mgr.Advisory{"jquery": {{Severity: "moderate"}}}, nil
+	}
+
+	dest := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dest, "htmx.org"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dest, "htmx.org", "htmx.min.js"), []byte("htmx"), 0644)
+
+	config := &frontend_config.FrontendConfig{
+		Destination: filepath.Join(dest, "{library_name}"),
+		CDN:         frontend_config.CDNUnpkg,
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":   {Version: "3.5.1"},
+			"htmx.org": {Version: "2.0.4"},
+			"missing":  {Version: "1.0.0"},
+		},
+	}
+
+	health, err := assessProjectHealth(config)
+	if err != nil {
+		t.Fatalf("assessProjectHealth() error = %v", err)
+	}
+	if len(health.Libraries) != 3 || health.Libraries[0].Library != "htmx.org" {
+		t.Fatalf("expected three libraries sorted by name, got %+v", health.Libraries)
+	}
+
+	htmx, jquery, missing := health.Libraries[0], health.Libraries[1], health.Libraries[2]
+	if htmx.Rating != ratingGreen || htmx.Size.Rating != ratingGreen {
+		t.Errorf("htmx.org rated %v (size %v), want green", htmx.Rating, htmx.Size.Rating)
+	}
+	if jquery.Freshness.Rating != ratingRed || jquery.Vulnerabilities.Rating != ratingYellow || jquery.Rating != ratingRed {
+		t.Errorf("jquery rated %v (freshness %v, vulnerabilities %v), want red", jquery.Rating, jquery.Freshness.Rating, jquery.Vulnerabilities.Rating)
+	}
+	if missing.Freshness.Rating != ratingUnknown || missing.Vulnerabilities.Rating != ratingUnknown {
+		t.Errorf("unknown library checks = %v/%v, want unknown", missing.Freshness.Rating, missing.Vulnerabilities.Rating)
+	}
+	if missing.Rating != ratingUnknown {
+		t.Errorf("unknown library rated %v, want unknown", missing.Rating)
+	}
+	if health.Score != 50 || health.Rating != ratingYellow {
+		t.Errorf("project score = %d (%v), want 50 (yellow)", health.Score, health.Rating)
+	}
+
+	table := renderHealthTable(health)
+	for _, want := range []string{"LIBRARY", "✗ red", "! 1 (moderate)", "Project score: 50/100 (yellow)"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table is missing %q:\n%s", want, table)
+		}
+	}
+}
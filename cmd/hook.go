@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var hookType string
+
+// Git hooks the hook command can manage
+var hookTypes = []string{"pre-commit", "pre-push"}
+
+// Markers around the lines smfaman adds to a git hook
+const (
+	hookBlockBegin = "# >>> smfaman check >>>"
+	hookBlockEnd   = "# <<< smfaman check <<<"
+)
+
+// hookCmd represents the hook command
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage git hooks that run 'smfaman check'",
+	Long: `Install or remove a git hook that runs 'smfaman check', so drift between
+the frontend configuration and the vendored files is caught before it is
+committed or pushed.
+
+Hooks are written to the repository's hooks directory, honouring
+core.hooksPath when it is set. An existing hook is kept: the smfaman lines
+are added between marker comments and 'hook uninstall' removes only them.
+
+Examples:
+  smfaman hook install
+  smfaman hook install --type pre-push
+  smfaman -f myproject.yaml hook install
+  smfaman hook uninstall`,
+}
+
+// hookInstallCmd installs the git hook
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a pre-commit or pre-push hook running 'smfaman check'",
+	Long: `Install a git hook that runs 'smfaman check' against the current frontend
+configuration and blocks the commit (or push) when it fails.
+
+Running it again updates the smfaman lines in place.
+
+Examples:
+  smfaman hook install
+  smfaman hook install --type pre-push`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runHookInstall(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+// hookUninstallCmd removes the git hook
+var hookUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the smfaman lines from the git hooks",
+	Long: `Remove the lines added by 'smfaman hook install' from the pre-commit and
+pre-push hooks. Hooks that contain nothing else are deleted.
+
+Examples:
+  smfaman hook uninstall`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runHookUninstall(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookUninstallCmd)
+
+	hookInstallCmd.Flags().StringVar(&hookType, "type", "pre-commit", "Hook to install: pre-commit or pre-push")
+}
+
+func runHookInstall() error {
+	if !isHookType(hookType) {
+		return fmt.Errorf("invalid hook type '%s' (use %s)", hookType, strings.Join(hookTypes, " or "))
+	}
+
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not inside a git repository: %w", err)
+	}
+	hooksDir, err := gitHooksDir(root)
+	if err != nil {
+		return err
+	}
+
+	configPath, err := filepath.Abs(FrontendConfig)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	if rel, err := filepath.Rel(root, configPath); err == nil && !strings.HasPrefix(rel, "..") {
+		configPath = filepath.ToSlash(rel)
+	}
+
+	hookPath := filepath.Join(hooksDir, hookType)
+	existing, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", hookPath, err)
+	}
+
+	updated := installHookBlock(string(existing), configPath)
+	if updated == string(existing) {
+		fmt.Printf("✓ %s hook is already installed (%s)\n", hookType, hookPath)
+		return nil
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hookPath, []byte(updated), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", hookPath, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return fmt.Errorf("failed to make %s executable: %w", hookPath, err)
+	}
+
+	fmt.Printf("✓ Installed %s hook: %s\n", hookType, hookPath)
+	if len(existing) > 0 {
+		fmt.Println("• Added to the existing hook; make sure it doesn't exit before the smfaman lines")
+	}
+	fmt.Printf("• It runs 'smfaman -f %s check'; smfaman must be on the PATH of git\n", configPath)
+	return nil
+}
+
+func runHookUninstall() error {
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return fmt.Errorf("not inside a git repository: %w", err)
+	}
+	hooksDir, err := gitHooksDir(root)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, name := range hookTypes {
+		hookPath := filepath.Join(hooksDir, name)
+		data, err := os.ReadFile(hookPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", hookPath, err)
+		}
+
+		remaining, found := removeMarkedBlock(string(data), hookBlockBegin, hookBlockEnd)
+		if !found {
+			continue
+		}
+		removed++
+
+		if hookIsEmpty(remaining) {
+			if err := os.Remove(hookPath); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", hookPath, err)
+			}
+			fmt.Printf("✓ Removed %s hook\n", name)
+			continue
+		}
+		if err := os.WriteFile(hookPath, []byte(remaining), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", hookPath, err)
+		}
+		fmt.Printf("✓ Removed smfaman lines from %s hook\n", name)
+	}
+
+	if removed == 0 {
+		fmt.Println("No smfaman hooks installed.")
+	}
+	return nil
+}
+
+// isHookType reports whether name is a hook the command manages
+func isHookType(name string) bool {
+	for _, t := range hookTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// gitHooksDir returns the hooks directory of the repository at root:
+// core.hooksPath (relative to root when not absolute) or .git/hooks
+func gitHooksDir(root string) (string, error) {
+	if hooksPath, err := gitOutput("config", "--get", "core.hooksPath"); err == nil && hooksPath != "" {
+		if strings.HasPrefix(hooksPath, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				hooksPath = filepath.Join(home, hooksPath[2:])
+			}
+		}
+		if !filepath.IsAbs(hooksPath) {
+			hooksPath = filepath.Join(root, hooksPath)
+		}
+		return hooksPath, nil
+	}
+
+	gitDir, err := gitOutput("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to find git directory: %w", err)
+	}
+	return filepath.Join(gitDir, "hooks"), nil
+}
+
+// gitOutput runs git with args and returns its trimmed output
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// installHookBlock adds (or updates) the smfaman block in a hook script,
+// creating a new shell script when the hook is empty
+func installHookBlock(content, configPath string) string {
+	block := []string{
+		hookBlockBegin,
+		fmt.Sprintf("smfaman -f %s check || exit 1", shellQuote(configPath)),
+		hookBlockEnd,
+	}
+	if strings.TrimSpace(content) == "" {
+		return "#!/bin/sh\n" + strings.Join(block, "\n") + "\n"
+	}
+	return replaceMarkedBlock(content, block)
+}
+
+// hookIsEmpty reports whether a hook has nothing left but a shebang
+func hookIsEmpty(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#!") {
+			return false
+		}
+	}
+	return true
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
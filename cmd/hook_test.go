@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallHookBlock(t *testing.T) {
+	script := installHookBlock("", "smartfrontend.yaml")
+	want := "#!/bin/sh\n" + hookBlockBegin + "\nsmfaman -f 'smartfrontend.yaml' check || exit 1\n" + hookBlockEnd + "\n"
+	if script != want {
+		t.Fatalf("new hook = %q, want %q", script, want)
+	}
+	if again := installHookBlock(script, "smartfrontend.yaml"); again != script {
+		t.Errorf("reinstalling changed the hook: %q", again)
+	}
+
+	existing := "#!/bin/sh\nnpm run lint\n"
+	script = installHookBlock(existing, "it's.yaml")
+	if !strings.HasPrefix(script, existing) || !strings.Contains(script, `-f 'it'\''s.yaml' check`) {
+		t.Errorf("existing hook = %q, want the block appended with a quoted path", script)
+	}
+
+	remaining, found := removeMarkedBlock(script, hookBlockBegin, hookBlockEnd)
+	if !found || strings.Contains(remaining, "smfaman") || !strings.Contains(remaining, "npm run lint") {
+		t.Errorf("removeMarkedBlock() = %q, %v", remaining, found)
+	}
+	if hookIsEmpty(remaining) {
+		t.Error("hook with other commands should not be empty")
+	}
+
+	remaining, _ = removeMarkedBlock(installHookBlock("", "smartfrontend.yaml"), hookBlockBegin, hookBlockEnd)
+	if !hookIsEmpty(remaining) {
+		t.Errorf("hook = %q, want it empty after removing the block", remaining)
+	}
+}
+
+func TestHookInstallAndUninstall(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	t.Chdir(repo)
+
+	origType, origConfig := hookType, FrontendConfig
+	defer func() { hookType, FrontendConfig = origType, origConfig }()
+	hookType, FrontendConfig = "pre-push", "smartfrontend.yaml"
+
+	t.Run("default hooks directory", func(t *testing.T) {
+		if err := runHookInstall(); err != nil {
+			t.Fatalf("runHookInstall() error = %v", err)
+		}
+		hookPath := filepath.Join(repo, ".git", "hooks", "pre-push")
+		info, err := os.Stat(hookPath)
+		if err != nil || info.Mode()&0111 == 0 {
+			t.Fatalf("expected an executable hook at %s: %v", hookPath, err)
+		}
+
+		if err := runHookUninstall(); err != nil {
+			t.Fatalf("runHookUninstall() error = %v", err)
+		}
+		if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+			t.Error("hook should be removed when it only held the smfaman lines")
+		}
+	})
+
+	t.Run("core.hooksPath", func(t *testing.T) {
+		if out, err := exec.Command("git", "config", "core.hooksPath", ".githooks").CombinedOutput(); err != nil {
+			t.Fatalf("git config failed: %v\n%s", err, out)
+		}
+		if err := runHookInstall(); err != nil {
+			t.Fatalf("runHookInstall() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(repo, ".githooks", "pre-push")); err != nil {
+			t.Errorf("hook should be written to core.hooksPath: %v", err)
+		}
+	})
+}
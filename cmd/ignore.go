@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileName lists files inside destinations that smfaman must never
+// delete or overwrite, e.g. locally patched files
+const ignoreFileName = ".smfamanignore"
+
+// ignoreRule is one pattern from an ignore file
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool // "!pattern" re-includes paths matched by earlier rules
+	dirOnly bool // "pattern/" only matches directories
+}
+
+// ignoreMatcher matches paths against the rules of an ignore file, with
+// .gitignore semantics: patterns are relative to the file's directory, a
+// pattern without a slash matches at any depth, a matched directory matches
+// everything inside it, and the last matching rule wins
+type ignoreMatcher struct {
+	baseDir string
+	rules   []ignoreRule
+}
+
+// loadIgnoreFile reads the ignore file next to a config file. A missing
+// file gives a matcher that matches nothing.
+func loadIgnoreFile(configPath string) (*ignoreMatcher, error) {
+	baseDir, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return nil, err
+	}
+	matcher := &ignoreMatcher{baseDir: baseDir}
+
+	f, err := os.Open(filepath.Join(baseDir, ignoreFileName))
+	if os.IsNotExist(err) {
+		return matcher, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ignoreFileName, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		rule, ok, err := parseIgnoreRule(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", ignoreFileName, line, err)
+		}
+		if ok {
+			matcher.rules = append(matcher.rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ignoreFileName, err)
+	}
+	return matcher, nil
+}
+
+// parseIgnoreRule parses a line of an ignore file, returning false for blank
+// lines and comments
+func parseIgnoreRule(line string) (ignoreRule, bool, error) {
+	var rule ignoreRule
+
+	pattern := strings.TrimSpace(line)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return rule, false, nil
+	}
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	// Patterns with a slash are anchored to the base directory
+	if strings.Contains(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else {
+		pattern = "**/" + pattern
+	}
+	if pattern == "" || pattern == "**/" {
+		return rule, false, nil
+	}
+
+	re, err := regexp.Compile("^" + globToRegexp(pattern) + "$")
+	if err != nil {
+		return rule, false, fmt.Errorf("invalid pattern %q: %w", line, err)
+	}
+	rule.re = re
+	return rule, true, nil
+}
+
+// globToRegexp translates a slash glob with *, ?, [...] and ** to a regexp
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether a file is ignored
+func (m *ignoreMatcher) Match(path string) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(m.baseDir, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	// Check the file and each of its parent directories
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.matches(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule matches the path or one of its parents
+func (r ignoreRule) matches(rel string) bool {
+	if !r.dirOnly && r.re.MatchString(rel) {
+		return true
+	}
+	for dir := rel; ; {
+		i := strings.LastIndexByte(dir, '/')
+		if i < 0 {
+			return false
+		}
+		dir = dir[:i]
+		if r.re.MatchString(dir) {
+			return true
+		}
+	}
+}
+
+// ignoredFilesUnder returns the ignored files inside dir
+func (m *ignoreMatcher) ignoredFilesUnder(dir string) ([]string, error) {
+	if m == nil || len(m.rules) == 0 {
+		return nil, nil
+	}
+
+	var ignored []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && m.Match(path) {
+			ignored = append(ignored, path)
+		}
+		return nil
+	})
+	return ignored, err
+}
+
+// unignoredFiles returns the files inside dir that the ignore file doesn't
+// protect, leaving out the destination marker
+func unignoredFiles(dir string, ignore *ignoreMatcher) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || ignore.Match(path) || path == filepath.Join(dir, destinationMarkerName) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files, err
+}
+
+// removeEmptyDirs removes the empty directories below dir, deepest first
+func removeEmptyDirs(dir string) {
+	var dirs []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && path != dir {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Remove(dirs[i]) // Fails for directories that aren't empty
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	writeTestFile(t, filepath.Join(tmpDir, ignoreFileName), `# Locally patched files
+public/libs/jquery/dist/jquery.js
+*.local.css
+/public/libs/htmx/ext/
+vendor/**/keep.txt
+*.map
+!public/libs/alpine/*.map
+bootstrap/dist/js/bootstrap.bundle.[jm]s
+`)
+
+	ignore, err := loadIgnoreFile(configPath)
+	if err != nil {
+		t.Fatalf("loadIgnoreFile failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"public/libs/jquery/dist/jquery.js", true},
+		{"public/libs/jquery/dist/jquery.min.js", false},
+		{"public/libs/bootstrap/theme.local.css", true},
+		{"public/libs/htmx/ext/sse.js", true},
+		{"public/libs/htmx/htmx.js", false},
+		{"vendor/keep.txt", true},
+		{"vendor/a/b/keep.txt", true},
+		{"public/libs/jquery/dist/jquery.min.map", true},
+		{"public/libs/alpine/alpine.min.map", false},
+		{"public/libs/bootstrap/dist/js/bootstrap.bundle.js", false},
+		{"bootstrap/dist/js/bootstrap.bundle.js", true},
+		{"../outside/jquery.local.css", false},
+	}
+	for _, tt := range tests {
+		if got := ignore.Match(filepath.Join(tmpDir, filepath.FromSlash(tt.path))); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadIgnoreFileMissing(t *testing.T) {
+	ignore, err := loadIgnoreFile(filepath.Join(t.TempDir(), "smartfrontend.yaml"))
+	if err != nil {
+		t.Fatalf("loadIgnoreFile failed: %v", err)
+	}
+	if ignore.Match("anything.js") {
+		t.Errorf("a missing ignore file should match nothing")
+	}
+}
+
+func TestCleanKeepsIgnoredFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+	config := frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+		ProjectName: "test-project",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "3.7.1"},
+		},
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	writeTestFile(t, filepath.Join(tmpDir, ignoreFileName), "libs/jquery/dist/jquery.js\n")
+
+	jqueryDir := filepath.Join(tmpDir, "libs", "jquery")
+	patched := filepath.Join(jqueryDir, "dist", "jquery.js")
+	writeTestFile(t, patched, "patched")
+	writeTestFile(t, filepath.Join(jqueryDir, "dist", "jquery.min.js"), "min")
+	writeTestFile(t, filepath.Join(jqueryDir, "src", "core.js"), "core")
+
+	manifestPath := manifestPathForConfig(configPath)
+	manifest := &fileManifest{
+		FormatVersion: manifestFormatVersion,
+		Files: map[string]manifestEntry{
+			"libs/jquery/dist/jquery.js":     {Library: "jquery", Version: "3.7.1"},
+			"libs/jquery/dist/jquery.min.js": {Library: "jquery", Version: "3.7.1"},
+		},
+	}
+	if err := saveManifest(manifestPath, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	oldConfig, oldForce, oldIgnoreMarker := FrontendConfig, cleanForce, cleanIgnoreMarker
+	FrontendConfig, cleanForce, cleanIgnoreMarker = configPath, true, true
+	defer func() { FrontendConfig, cleanForce, cleanIgnoreMarker = oldConfig, oldForce, oldIgnoreMarker }()
+
+	if err := runClean(); err != nil {
+		t.Fatalf("runClean failed: %v", err)
+	}
+
+	if _, err := os.Stat(patched); err != nil {
+		t.Errorf("ignored file should be kept: %v", err)
+	}
+	for _, path := range []string{filepath.Join(jqueryDir, "dist", "jquery.min.js"), filepath.Join(jqueryDir, "src")} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", path)
+		}
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Files["libs/jquery/dist/jquery.js"]; !ok || len(m.Files) != 1 {
+		t.Errorf("only the kept file should stay in the lockfile, got %v", m.Files)
+	}
+}
@@ -9,9 +9,34 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
 )
 
-var forceOverwrite bool
+var (
+	forceOverwrite  bool
+	initProjectName string
+	initDestination string
+	initCDN         string
+	initFilesMode   string
+	initConcurrency int
+	initLockfile    bool
+	initFromHTML    []string
+	initRewriteHTML bool
+)
+
+// initOptionFlags are the flags that create the config without the form
+var initOptionFlags = []string{"project-name", "destination", "cdn", "files-mode", "concurrency", "lockfile", "from-html"}
+
+// initOptions are the choices made in the init form or with init flags
+type initOptions struct {
+	ProjectName string
+	Destination string
+	CDN         frontend_config.CDN
+	FilesMode   frontend_config.FilesMode
+	Concurrency int // 0 leaves it to the concurrency setting
+	Lockfile    bool
+}
 
 // initCmd represents the init command
 var initCmd = &cobra.Command{
@@ -26,10 +51,34 @@ The configuration file defines which libraries to download from CDNs, their vers
 and where to store them locally. Once initialized, you can add libraries with the
 'add' command and download them with the 'sync' command.
 
+Besides the project name, destination and default CDN, the form asks for the
+files policy (entry files, the whole package, or minified builds only), the
+number of parallel CDN requests, and whether sync writes a lockfile.
+
+Passing any of --project-name, --destination, --cdn, --files-mode,
+--concurrency, --lockfile or --from-html creates the config from the flags
+without the form, so init also works in scripts.
+
+With --accessible (or SMFAMAN_ACCESSIBLE=1) the form is replaced by plain
+questions asked one line at a time, with numbered choices.
+
+--from-html migrates pages that load libraries straight from unpkg, jsdelivr
+or cdnjs: every <script src> and <link href> pointing at one of them becomes
+a library entry with the version from the URL ("latest" when there is none)
+and the loaded files as its file list. Without --cdn the CDN most tags use
+becomes the default; libraries loaded from another CDN keep theirs.
+--rewrite then points those tags at the files sync will vendor, as paths
+relative to the page.
+
 Example:
   smfaman init
   smfaman init -f myproject.yaml
-  smfaman init --force  # Overwrite existing config`,
+  smfaman init --force  # Overwrite existing config
+  smfaman init --accessible  # Plain prompts for screen readers
+  smfaman init --destination "./static/vendor/{library_name}" --files-mode minified
+  smfaman init --cdn jsdelivr --concurrency 8 --lockfile=false
+  smfaman init --from-html index.html --destination ./static/vendor/{library_name}
+  smfaman init --from-html index.html --from-html admin.html --rewrite`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Check if config file already exists
 		if _, err := os.Stat(FrontendConfig); err == nil && !forceOverwrite {
@@ -38,6 +87,24 @@ Example:
 			os.Exit(1)
 		}
 
+		if initFlagsGiven(cmd) {
+			if err := runInitWithFlags(FrontendConfig); err != nil {
+				exitWithError(err)
+			}
+			return
+		}
+
+		if err := requireTerminal("init", "pass --destination (and the other init flags) to create the config without the form"); err != nil {
+			exitWithError(err)
+		}
+
+		if accessibleEnabled() {
+			if err := runAccessibleInit(confirmationInput, os.Stdout, FrontendConfig); err != nil {
+				exitWithError(err)
+			}
+			return
+		}
+
 		// Create and run the Bubble Tea program
 		p := tea.NewProgram(newInitModel(FrontendConfig))
 		if _, err := p.Run(); err != nil {
@@ -53,13 +120,110 @@ func init() {
 	// Add force flag
 	initCmd.Flags().BoolVar(&forceOverwrite, "force", false, "Overwrite existing config file if it exists")
 
-	// Here you will define your flags and configuration settings.
+	initCmd.Flags().StringVar(&initProjectName, "project-name", "my-project", "Project name")
+	initCmd.Flags().StringVar(&initDestination, "destination", "./frontend/{library_name}", "Destination path template")
+	initCmd.Flags().StringVar(&initCDN, "cdn", "", "Default CDN (unpkg, cdnjs, jsdelivr; default from the cdn setting)")
+	initCmd.Flags().StringVar(&initFilesMode, "files-mode", string(frontend_config.FilesModeEntrypoints), "Files policy: entrypoints, all or minified")
+	initCmd.Flags().IntVar(&initConcurrency, "concurrency", 0, "Parallel CDN requests for the project (default from the concurrency setting)")
+	initCmd.Flags().BoolVar(&initLockfile, "lockfile", true, "Record synced files in a lockfile")
+	initCmd.Flags().StringArrayVar(&initFromHTML, "from-html", nil, "Create libraries from the CDN <script>/<link> tags of an HTML file (repeatable)")
+	initCmd.Flags().BoolVar(&initRewriteHTML, "rewrite", false, "With --from-html, point the tags at the vendored files")
+}
+
+// initFlagsGiven reports whether any flag that sets a config option was passed
+func initFlagsGiven(cmd *cobra.Command) bool {
+	for _, name := range initOptionFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runInitWithFlags creates the config at path from the init flags
+func runInitWithFlags(path string) error {
+	opts := initOptions{
+		ProjectName: initProjectName,
+		Destination: initDestination,
+		CDN:         frontend_config.CDN(initCDN),
+		FilesMode:   frontend_config.FilesMode(initFilesMode),
+		Concurrency: initConcurrency,
+		Lockfile:    initLockfile,
+	}
+	if initRewriteHTML && len(initFromHTML) == 0 {
+		return fmt.Errorf("--rewrite needs --from-html")
+	}
+
+	pages, err := scanHTMLPages(initFromHTML)
+	if err != nil {
+		return err
+	}
+	if opts.CDN == "" {
+		opts.CDN = mostUsedCDN(pages)
+	}
+	if opts.CDN == "" {
+		opts.CDN = settingsCDN()
+	}
+
+	config := newProjectConfig(opts)
+	notes := addHTMLLibraries(config, pages)
+	if err := validateConfig(config); err != nil {
+		return err
+	}
+	if err := writeNewConfig(path, config); err != nil {
+		return err
+	}
+
+	fmt.Println(initSuccessMessage(path, opts))
+	if len(initFromHTML) > 0 {
+		printHTMLLibraries(config, notes)
+	}
+	if initRewriteHTML {
+		if _, err := rewriteHTMLPages(config, pages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newProjectConfig builds the config init writes. The files policy and
+// lockfile choice are written out so they are visible in the new file.
+func newProjectConfig(opts initOptions) *frontend_config.FrontendConfig {
+	lockfile := opts.Lockfile
+	return &frontend_config.FrontendConfig{
+		ProjectName: opts.ProjectName,
+		Destination: opts.Destination,
+		CDN:         opts.CDN,
+		FilesMode:   opts.FilesMode,
+		Concurrency: opts.Concurrency,
+		Lockfile:    &lockfile,
+		Libraries:   make(map[string]frontend_config.LibraryConfig),
+	}
+}
+
+// writeNewConfig writes a config created by init
+func writeNewConfig(path string, config *frontend_config.FrontendConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// initCmd.PersistentFlags().String("foo", "", "A help for foo")
+// initSuccessMessage describes a config created by init and what to do next
+func initSuccessMessage(path string, opts initOptions) string {
+	concurrency := "from the concurrency setting"
+	if opts.Concurrency > 0 {
+		concurrency = fmt.Sprint(opts.Concurrency)
+	}
+	lockfile := "no"
+	if opts.Lockfile {
+		lockfile = manifestPathForConfig(path)
+	}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// initCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	return fmt.Sprintf("✓ Created %s successfully!\n\nProject: %s\nDestination: %s\nCDN: %s\nFiles: %s\nConcurrency: %s\nLockfile: %s\n\nNext steps:\n  • Add libraries: smfaman add <library>@<version>\n  • Sync libraries: smfaman sync",
+		path, opts.ProjectName, opts.Destination, opts.CDN, opts.FilesMode, concurrency, lockfile)
 }
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// htmlAssetTagPattern matches <script> and <link> start tags
+var htmlAssetTagPattern = regexp.MustCompile(`(?is)<(?:script|link)\b[^>]*>`)
+
+// htmlURLAttrPattern matches the src or href attribute of a tag
+var htmlURLAttrPattern = regexp.MustCompile(`(?is)\s(?:src|href)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// cdnTag is a <script> or <link> tag loading a file from a supported CDN
+type cdnTag struct {
+	URL     string // As written in the page
+	Library string
+	Version string // "" when the URL doesn't name one
+	CDN     frontend_config.CDN
+	File    string // Path inside the package; "" for the package's main file
+}
+
+// spec returns the version the tag's library is configured with
+func (t cdnTag) spec() string {
+	if t.Version == "" {
+		return "latest"
+	}
+	return t.Version
+}
+
+// htmlPage is an HTML file and the CDN tags found in it
+type htmlPage struct {
+	Path string
+	Text string
+	Tags []cdnTag
+}
+
+// scanHTMLPages reads HTML files and finds their CDN tags
+func scanHTMLPages(files []string) ([]htmlPage, error) {
+	var pages []htmlPage
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		pages = append(pages, htmlPage{Path: file, Text: string(data), Tags: findCDNTags(string(data))})
+	}
+	return pages, nil
+}
+
+// findCDNTags returns the script and stylesheet tags of a page that load
+// files from unpkg, jsdelivr or cdnjs
+func findCDNTags(html string) []cdnTag {
+	var tags []cdnTag
+	for _, tag := range htmlAssetTagPattern.FindAllString(html, -1) {
+		m := htmlURLAttrPattern.FindStringSubmatch(tag)
+		if m == nil {
+			continue
+		}
+		raw := m[1] + m[2] + m[3]
+		if t, ok := parseCDNURL(raw); ok {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// parseCDNURL recognizes a file URL on one of the supported CDNs:
+//
+//	https://unpkg.com/<package>[@<version>][/<file>]
+//	https://cdn.jsdelivr.net/npm/<package>[@<version>][/<file>]
+//	https://cdnjs.cloudflare.com/ajax/libs/<library>/<version>/<file>
+func parseCDNURL(raw string) (cdnTag, bool) {
+	tag := cdnTag{URL: raw}
+	if strings.HasPrefix(raw, "//") {
+		raw = "https:" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return tag, false
+	}
+
+	var rest string
+	switch strings.ToLower(u.Host) {
+	case "unpkg.com":
+		tag.CDN, rest = frontend_config.CDNUnpkg, u.Path
+	case "cdn.jsdelivr.net":
+		var ok bool
+		if rest, ok = strings.CutPrefix(u.Path, "/npm/"); !ok {
+			return tag, false // GitHub, WordPress and combined files aren't npm packages
+		}
+		tag.CDN = frontend_config.CDNJsdelivr
+	case "cdnjs.cloudflare.com":
+		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/ajax/libs/"), "/", 3)
+		if !strings.HasPrefix(u.Path, "/ajax/libs/") || len(parts) < 3 || parts[0] == "" || parts[1] == "" {
+			return tag, false
+		}
+		tag.CDN, tag.Library, tag.Version, tag.File = frontend_config.CDNCdnjs, parts[0], parts[1], parts[2]
+		return tag, tag.File != ""
+	default:
+		return tag, false
+	}
+
+	// <package>[@<version>], where scoped packages take two segments
+	rest = strings.TrimPrefix(rest, "/")
+	segments := 1
+	if strings.HasPrefix(rest, "@") {
+		segments = 2
+	}
+	parts := strings.SplitN(rest, "/", segments+1)
+	if len(parts) < segments {
+		return tag, false
+	}
+	tag.Library, tag.Version = parsePackageSpec(strings.Join(parts[:segments], "/"))
+	if len(parts) > segments {
+		tag.File = strings.TrimSuffix(parts[segments], "/")
+	}
+	if tag.Library == "" || (segments == 2 && !strings.Contains(tag.Library, "/")) {
+		return tag, false
+	}
+	return tag, true
+}
+
+// addHTMLLibraries adds a library for each package the pages load from a
+// CDN, listing the loaded files. Libraries on another CDN than the config's
+// get their own cdn setting. It returns notes about tags it couldn't use.
+func addHTMLLibraries(config *frontend_config.FrontendConfig, pages []htmlPage) []string {
+	var notes []string
+	for _, page := range pages {
+		for _, tag := range page.Tags {
+			version := tag.spec()
+			lib, exists := config.Libraries[tag.Library]
+			if !exists {
+				lib = frontend_config.LibraryConfig{Version: version}
+				if tag.CDN != config.CDN {
+					lib.CDN = tag.CDN
+				}
+			} else if lib.Version != version || libraryCDN(config, lib) != tag.CDN {
+				notes = append(notes, fmt.Sprintf("%s: %s loads %s@%s from %s; keeping %s from %s",
+					page.Path, tag.URL, tag.Library, version, tag.CDN, lib.Version, libraryCDN(config, lib)))
+				continue
+			}
+
+			if tag.File == "" {
+				notes = append(notes, fmt.Sprintf("%s: %s loads the package's main file; its files are picked by files_mode", page.Path, tag.URL))
+			} else if !slices.Contains(lib.Files, tag.File) {
+				lib.Files = append(lib.Files, tag.File)
+			}
+			config.Libraries[tag.Library] = lib
+		}
+	}
+	return notes
+}
+
+// mostUsedCDN returns the CDN most of the tags load from, preferring the
+// order of fallbackCDNs on a tie, or "" without tags
+func mostUsedCDN(pages []htmlPage) frontend_config.CDN {
+	counts := make(map[frontend_config.CDN]int)
+	for _, page := range pages {
+		for _, tag := range page.Tags {
+			counts[tag.CDN]++
+		}
+	}
+	var best frontend_config.CDN
+	for _, cdn := range fallbackCDNs {
+		if counts[cdn] > counts[best] {
+			best = cdn
+		}
+	}
+	return best
+}
+
+// rewriteHTMLPages points the CDN tags of the pages at the files sync will
+// vendor, as paths relative to each page. Tags loading a package's main file
+// are left alone, since which file that is isn't known before a sync.
+func rewriteHTMLPages(config *frontend_config.FrontendConfig, pages []htmlPage) (int, error) {
+	rewritten := 0
+	for _, page := range pages {
+		pageDir, err := filepath.Abs(filepath.Dir(page.Path))
+		if err != nil {
+			return rewritten, err
+		}
+
+		text := page.Text
+		changed := 0
+		for _, tag := range page.Tags {
+			lib, ok := config.Libraries[tag.Library]
+			if !ok || tag.File == "" || lib.Version != tag.spec() || libraryCDN(config, lib) != tag.CDN || !strings.Contains(text, tag.URL) {
+				continue
+			}
+			destPath, err := config.GetLibraryDestination(tag.Library, lib)
+			if err != nil {
+				return rewritten, fmt.Errorf("failed to get destination for %s: %w", tag.Library, err)
+			}
+			localName := localFilePaths([]CDNFile{{Path: tag.File}}, lib.StripPrefix)[tag.File]
+			local, err := filepath.Rel(pageDir, filepath.Join(destPath, filepath.FromSlash(localName)))
+			if err != nil {
+				return rewritten, err
+			}
+			text = strings.ReplaceAll(text, tag.URL, path.Clean(filepath.ToSlash(local)))
+			changed++
+		}
+		if changed == 0 {
+			continue
+		}
+
+		info, err := os.Stat(page.Path)
+		if err != nil {
+			return rewritten, err
+		}
+		if err := os.WriteFile(page.Path, []byte(text), info.Mode().Perm()); err != nil {
+			return rewritten, fmt.Errorf("failed to write %s: %w", page.Path, err)
+		}
+		fmt.Printf("✓ Rewrote %d %s in %s\n", changed, pluralize(changed, "tag", "tags"), page.Path)
+		rewritten += changed
+	}
+	return rewritten, nil
+}
+
+// printHTMLLibraries lists the libraries created from HTML pages
+func printHTMLLibraries(config *frontend_config.FrontendConfig, notes []string) {
+	if len(config.Libraries) == 0 {
+		fmt.Println("\nNo tags loading files from unpkg, jsdelivr or cdnjs were found.")
+		return
+	}
+
+	fmt.Printf("\nFound %d %s in the HTML:\n", len(config.Libraries), pluralize(len(config.Libraries), "library", "libraries"))
+	for _, name := range sortedKeys(config.Libraries) {
+		lib := config.Libraries[name]
+		fmt.Printf("  • %s@%s (%s)", name, lib.Version, libraryCDN(config, lib))
+		if len(lib.Files) > 0 {
+			fmt.Printf(": %s", strings.Join(lib.Files, ", "))
+		}
+		fmt.Println()
+	}
+	for _, note := range notes {
+		fmt.Printf("  ⚠ %s\n", note)
+	}
+}
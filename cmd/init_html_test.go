@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestParseCDNURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		ok   bool
+		want cdnTag
+	}{
+		{"https://unpkg.com/htmx.org@1.9.10/dist/htmx.min.js", true,
+			cdnTag{Library: "htmx.org", Version: "1.9.10", CDN: frontend_config.CDNUnpkg, File: "dist/htmx.min.js"}},
+		{"//unpkg.com/alpinejs", true,
+			cdnTag{Library: "alpinejs", CDN: frontend_config.CDNUnpkg}},
+		{"https://cdn.jsdelivr.net/npm/@popperjs/core@2.11.8/dist/umd/popper.min.js", true,
+			cdnTag{Library: "@popperjs/core", Version: "2.11.8", CDN: frontend_config.CDNJsdelivr, File: "dist/umd/popper.min.js"}},
+		{"https://cdn.jsdelivr.net/npm/bootstrap@5/dist/css/bootstrap.min.css?v=1", true,
+			cdnTag{Library: "bootstrap", Version: "5", CDN: frontend_config.CDNJsdelivr, File: "dist/css/bootstrap.min.css"}},
+		{"https://cdnjs.cloudflare.com/ajax/libs/jquery/3.7.1/jquery.min.js", true,
+			cdnTag{Library: "jquery", Version: "3.7.1", CDN: frontend_config.CDNCdnjs, File: "jquery.min.js"}},
+		{"https://cdn.jsdelivr.net/gh/user/repo@1.0/file.js", false, cdnTag{}},
+		{"https://cdnjs.cloudflare.com/ajax/libs/jquery/3.7.1/", false, cdnTag{}},
+		{"https://unpkg.com/@scope", false, cdnTag{}},
+		{"https://example.com/jquery.js", false, cdnTag{}},
+		{"/static/app.js", false, cdnTag{}},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCDNURL(tt.url)
+		if ok != tt.ok {
+			t.Errorf("parseCDNURL(%q) ok = %v, want %v", tt.url, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		tt.want.URL = tt.url
+		if got != tt.want {
+			t.Errorf("parseCDNURL(%q) = %+v, want %+v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRunInitFromHTML(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	page := `<!doctype html>
+<html>
+<head>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.2/dist/css/bootstrap.min.css" integrity="sha384-x" crossorigin="anonymous">
+  <link rel='icon' href='/favicon.ico'>
+  <script src="https://cdn.jsdelivr.net/npm/bootstrap@5.3.2/dist/js/bootstrap.bundle.min.js"></script>
+  <script src=https://cdnjs.cloudflare.com/ajax/libs/jquery/3.7.1/jquery.min.js></script>
+  <script defer src="https://cdn.jsdelivr.net/npm/alpinejs"></script>
+  <script src="/static/app.js"></script>
+</head>
+</html>
+`
+	writeTestFile(t, filepath.Join(dir, "templates", "index.html"), page)
+
+	initFromHTML, initRewriteHTML, initDestination = []string{"templates/index.html"}, true, "./static/vendor/{library_name}"
+	defer func() {
+		initFromHTML, initRewriteHTML, initDestination = nil, false, "./frontend/{library_name}"
+	}()
+
+	if err := runInitWithFlags("smartfrontend.yaml"); err != nil {
+		t.Fatalf("runInitWithFlags failed: %v", err)
+	}
+
+	data, err := os.ReadFile("smartfrontend.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config frontend_config.FrontendConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.CDN != frontend_config.CDNJsdelivr {
+		t.Errorf("default CDN = %q, want the most used one (jsdelivr)", config.CDN)
+	}
+	bootstrap := config.Libraries["bootstrap"]
+	if bootstrap.Version != "5.3.2" || bootstrap.CDN != "" ||
+		!slices.Equal(bootstrap.Files, []string{"dist/css/bootstrap.min.css", "dist/js/bootstrap.bundle.min.js"}) {
+		t.Errorf("bootstrap = %+v", bootstrap)
+	}
+	if jquery := config.Libraries["jquery"]; jquery.Version != "3.7.1" || jquery.CDN != frontend_config.CDNCdnjs {
+		t.Errorf("jquery = %+v, want 3.7.1 from cdnjs", jquery)
+	}
+	if alpine := config.Libraries["alpinejs"]; alpine.Version != "latest" || len(alpine.Files) != 0 {
+		t.Errorf("alpinejs = %+v, want latest with no file list", alpine)
+	}
+	if len(config.Libraries) != 3 {
+		t.Errorf("got %d libraries, want 3", len(config.Libraries))
+	}
+
+	rewritten, err := os.ReadFile(filepath.Join(dir, "templates", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`href="../static/vendor/bootstrap/dist/css/bootstrap.min.css" integrity="sha384-x"`,
+		`src="../static/vendor/bootstrap/dist/js/bootstrap.bundle.min.js"`,
+		`src=../static/vendor/jquery/jquery.min.js>`,
+		`src="https://cdn.jsdelivr.net/npm/alpinejs"`, // The main file isn't known yet
+		`src="/static/app.js"`,
+	} {
+		if !strings.Contains(string(rewritten), want) {
+			t.Errorf("rewritten page missing %s:\n%s", want, rewritten)
+		}
+	}
+}
@@ -2,13 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"gopkg.in/yaml.v3"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
 )
 
@@ -17,32 +16,32 @@ const (
 	fieldProjectName = iota
 	fieldDestination
 	fieldCDN
+	fieldFilesMode
+	fieldConcurrency
+	fieldLockfile
 	fieldCount
 )
 
 var (
-	focusedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	blurredStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	focusedStyle        = lipgloss.NewStyle()
+	blurredStyle        = lipgloss.NewStyle()
 	cursorStyle         = focusedStyle.Copy()
 	noStyle             = lipgloss.NewStyle()
 	helpStyle           = blurredStyle.Copy()
-	cursorModeHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	cursorModeHelpStyle = lipgloss.NewStyle()
 
 	focusedButton = focusedStyle.Copy().Render("[ Submit ]")
 	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Submit"))
 
 	titleStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("86")).
 			MarginBottom(1)
 
 	successStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("42"))
+			Bold(true)
 
 	errorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("196"))
+			Bold(true)
 )
 
 type initModel struct {
@@ -50,6 +49,10 @@ type initModel struct {
 	focusIndex  int
 	cdnChoice   int
 	cdnOptions  []string
+	filesModeChoice  int
+	filesModeOptions []string
+	lockfileChoice   int
+	lockfileOptions  []string
 	configFile  string
 	err         error
 	submitted   bool
@@ -65,7 +68,13 @@ func newInitModel(configFile string) initModel {
 	m := initModel{
 		inputs:     make([]textinput.Model, fieldCount),
 		cdnOptions: []string{"unpkg", "cdnjs", "jsdelivr"},
-		configFile: configFile,
+		filesModeOptions: []string{
+			string(frontend_config.FilesModeEntrypoints),
+			string(frontend_config.FilesModeAll),
+			string(frontend_config.FilesModeMinified),
+		},
+		lockfileOptions: []string{"yes", "no"},
+		configFile:      configFile,
 	}
 
 	var t textinput.Model
@@ -102,9 +111,38 @@ func newInitModel(configFile string) initModel {
 	m.inputs[fieldCDN] = t
 	m.inputs[fieldCDN].Blur()
 
+	// Files policy and lockfile (selected like the CDN)
+	m.inputs[fieldFilesMode] = t
+	m.inputs[fieldLockfile] = t
+
+	// Concurrency
+	t = textinput.New()
+	t.Placeholder = fmt.Sprint(settingsConcurrency())
+	t.CharLimit = 3
+	t.Width = 50
+	t.Prompt = "> "
+	t.PromptStyle = blurredStyle
+	t.TextStyle = noStyle
+	m.inputs[fieldConcurrency] = t
+
 	return m
 }
 
+// selection returns the current choice and the options of a select field,
+// or nil for text fields
+func (m *initModel) selection(field int) (*int, []string) {
+	switch field {
+	case fieldCDN:
+		return &m.cdnChoice, m.cdnOptions
+	case fieldFilesMode:
+		return &m.filesModeChoice, m.filesModeOptions
+	case fieldLockfile:
+		return &m.lockfileChoice, m.lockfileOptions
+	default:
+		return nil, nil
+	}
+}
+
 func (m initModel) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -134,18 +172,18 @@ func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.submitForm()
 			}
 
-			// Handle CDN selection
-			if m.focusIndex == fieldCDN {
+			// Handle CDN, files policy and lockfile selection
+			if choice, options := m.selection(m.focusIndex); choice != nil {
 				if s == "up" {
-					m.cdnChoice--
-					if m.cdnChoice < 0 {
-						m.cdnChoice = len(m.cdnOptions) - 1
+					*choice--
+					if *choice < 0 {
+						*choice = len(options) - 1
 					}
 					return m, nil
 				} else if s == "down" {
-					m.cdnChoice++
-					if m.cdnChoice >= len(m.cdnOptions) {
-						m.cdnChoice = 0
+					*choice++
+					if *choice >= len(options) {
+						*choice = 0
 					}
 					return m, nil
 				}
@@ -192,11 +230,16 @@ func (m initModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *initModel) updateInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.inputs))
 
-	// Only text input the focused field, and skip CDN field (it's managed with arrow keys)
+	// Only text input the focused field, and skip select fields (they're managed with arrow keys)
 	for i := range m.inputs {
-		if i == m.focusIndex && i != fieldCDN {
-			m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+		if choice, _ := m.selection(i); i != m.focusIndex || choice != nil {
+			continue
+		}
+		// Concurrency only takes digits
+		if key, ok := msg.(tea.KeyMsg); ok && i == fieldConcurrency && key.Type == tea.KeyRunes && !isDigits(string(key.Runes)) {
+			continue
 		}
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
 	}
 
 	return tea.Batch(cmds...)
@@ -236,42 +279,65 @@ func (m initModel) View() string {
 	b.WriteString(helpStyle.Render("  Use {library_name} as placeholder") + "\n\n")
 
 	// CDN Selection
-	if m.focusIndex == fieldCDN {
-		b.WriteString(focusedStyle.Render("Default CDN:") + "\n")
+	m.viewSelect(&b, fieldCDN, "Default CDN:")
+	b.WriteString("\n")
+
+	// Files policy
+	m.viewSelect(&b, fieldFilesMode, "Files Policy:")
+	b.WriteString(helpStyle.Render("  entry files only, the whole package, or minified .js/.css builds") + "\n\n")
+
+	// Concurrency
+	if m.focusIndex == fieldConcurrency {
+		b.WriteString(focusedStyle.Render("Parallel CDN Requests:") + "\n")
 	} else {
-		b.WriteString(blurredStyle.Render("Default CDN:") + "\n")
+		b.WriteString(blurredStyle.Render("Parallel CDN Requests:") + "\n")
+	}
+	b.WriteString(m.inputs[fieldConcurrency].View() + "\n")
+	b.WriteString(helpStyle.Render("  Leave empty to use the concurrency setting") + "\n\n")
+
+	// Lockfile
+	m.viewSelect(&b, fieldLockfile, "Write Lockfile:")
+	b.WriteString("\n")
+
+	// Submit button
+	button := &blurredButton
+	if m.focusIndex == fieldCount {
+		button = &focusedButton
 	}
+	fmt.Fprintf(&b, "\n%s\n\n", *button)
 
-	for i, option := range m.cdnOptions {
+	// Help
+	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select option • enter: submit • ctrl+c: quit"))
+
+	return b.String()
+}
+
+// viewSelect renders a select field with its options, marking the choice
+func (m initModel) viewSelect(b *strings.Builder, field int, label string) {
+	if m.focusIndex == field {
+		b.WriteString(focusedStyle.Render(label) + "\n")
+	} else {
+		b.WriteString(blurredStyle.Render(label) + "\n")
+	}
+
+	choice, options := m.selection(field)
+	for i, option := range options {
 		cursor := " "
-		if i == m.cdnChoice {
+		if i == *choice {
 			cursor = "●"
-			if m.focusIndex == fieldCDN {
+			if m.focusIndex == field {
 				b.WriteString(focusedStyle.Render(fmt.Sprintf("  %s %s\n", cursor, option)))
 			} else {
 				b.WriteString(fmt.Sprintf("  %s %s\n", cursor, option))
 			}
 		} else {
-			if m.focusIndex == fieldCDN {
+			if m.focusIndex == field {
 				b.WriteString(blurredStyle.Render(fmt.Sprintf("  %s %s\n", cursor, option)))
 			} else {
 				b.WriteString(helpStyle.Render(fmt.Sprintf("  %s %s\n", cursor, option)))
 			}
 		}
 	}
-	b.WriteString("\n")
-
-	// Submit button
-	button := &blurredButton
-	if m.focusIndex == fieldCount {
-		button = &focusedButton
-	}
-	fmt.Fprintf(&b, "\n%s\n\n", *button)
-
-	// Help
-	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: submit • ctrl+c: quit"))
-
-	return b.String()
 }
 
 func (m *initModel) submitForm() tea.Cmd {
@@ -286,38 +352,33 @@ func (m *initModel) submitForm() tea.Cmd {
 		destination = m.inputs[fieldDestination].Placeholder
 	}
 
-	cdn := frontend_config.CDN(m.cdnOptions[m.cdnChoice])
+	concurrency, _ := strconv.Atoi(m.inputs[fieldConcurrency].Value())
+	opts := initOptions{
+		ProjectName: projectName,
+		Destination: destination,
+		CDN:         frontend_config.CDN(m.cdnOptions[m.cdnChoice]),
+		FilesMode:   frontend_config.FilesMode(m.filesModeOptions[m.filesModeChoice]),
+		Concurrency: concurrency,
+		Lockfile:    m.lockfileOptions[m.lockfileChoice] == "yes",
+	}
 
 	return func() tea.Msg {
-		// Create config
-		config := frontend_config.FrontendConfig{
-			ProjectName: projectName,
-			Destination: destination,
-			CDN:         cdn,
-			Libraries:   make(map[string]frontend_config.LibraryConfig),
-		}
-
-		// Marshal to YAML
-		data, err := yaml.Marshal(&config)
-		if err != nil {
-			return submitCompleteMsg{
-				err: fmt.Errorf("failed to marshal config: %w", err),
-			}
+		if err := writeNewConfig(configFile, newProjectConfig(opts)); err != nil {
+			return submitCompleteMsg{err: err}
 		}
 
-		// Write to file
-		err = os.WriteFile(configFile, data, 0644)
-		if err != nil {
-			return submitCompleteMsg{
-				err: fmt.Errorf("failed to write config file: %w", err),
-			}
+		return submitCompleteMsg{
+			successMsg: initSuccessMessage(configFile, opts),
 		}
+	}
+}
 
-		successMsg := fmt.Sprintf("✓ Created %s successfully!\n\nProject: %s\nDestination: %s\nCDN: %s\n\nNext steps:\n  • Add libraries: smfaman add <library>@<version>\n  • Sync libraries: smfaman sync",
-			configFile, projectName, destination, cdn)
-
-		return submitCompleteMsg{
-			successMsg: successMsg,
+// isDigits reports whether s is made of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
 		}
 	}
+	return s != ""
 }
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"gopkg.in/yaml.v3"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
 )
@@ -165,3 +168,73 @@ func TestForceOverwrite(t *testing.T) {
 		t.Errorf("expected CDN %q, got %q", newCDN, readConfig.CDN)
 	}
 }
+
+func TestInitModelSelectFields(t *testing.T) {
+	var model tea.Model = newInitModel("test-config.yaml")
+	press := func(key tea.KeyMsg) {
+		model, _ = model.Update(key)
+	}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	// Move to the files policy and pick "minified"
+	for range fieldFilesMode {
+		press(tab)
+	}
+	press(down)
+	press(down)
+
+	// Concurrency only accepts digits
+	press(tab)
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("8")})
+
+	// Turn the lockfile off
+	press(tab)
+	press(down)
+
+	m := model.(initModel)
+	if got := m.filesModeOptions[m.filesModeChoice]; got != "minified" {
+		t.Errorf("files policy = %q, want minified", got)
+	}
+	if got := m.inputs[fieldConcurrency].Value(); got != "8" {
+		t.Errorf("concurrency input = %q, want 8", got)
+	}
+	if got := m.lockfileOptions[m.lockfileChoice]; got != "no" {
+		t.Errorf("lockfile = %q, want no", got)
+	}
+}
+
+func TestRunInitWithFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	initProjectName, initDestination, initCDN = "site", "./static/{library_name}", "jsdelivr"
+	initFilesMode, initConcurrency, initLockfile = "minified", 6, false
+	defer func() {
+		initProjectName, initDestination, initCDN = "my-project", "./frontend/{library_name}", ""
+		initFilesMode, initConcurrency, initLockfile = "entrypoints", 0, true
+	}()
+
+	if err := runInitWithFlags(path); err != nil {
+		t.Fatalf("runInitWithFlags failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config frontend_config.FrontendConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.FilesMode != frontend_config.FilesModeMinified || config.Concurrency != 6 || config.LockfileEnabled() {
+		t.Errorf("unexpected config written:\n%s", data)
+	}
+	if config.CDN != frontend_config.CDNJsdelivr || config.Destination != "./static/{library_name}" {
+		t.Errorf("unexpected config written:\n%s", data)
+	}
+
+	initFilesMode = "everything"
+	if err := runInitWithFlags(path); err == nil {
+		t.Error("expected an error for an invalid files mode")
+	}
+}
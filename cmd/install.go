@@ -12,7 +12,14 @@ import (
 )
 
 var (
-	installForce bool
+	installForce          bool
+	installPrefix         string
+	installSystem         bool
+	installGenerate       string
+	installDist           string
+	installRepo           string
+	installReleaseVersion string
+	installOutput         string
 )
 
 // installCmd represents the install command
@@ -28,6 +35,16 @@ This command will:
   • Add ~/bin to PATH if needed (persistent across sessions)
   • Modify the appropriate shell configuration file based on your shell
 
+The install directory is chosen in this order:
+  • --prefix DIR
+  • --system: /usr/local/bin (%ProgramFiles%\smfaman on Windows)
+  • $XDG_BIN_HOME, if set
+  • ~/bin
+
+System-wide and custom directories usually need root; you'll be told to
+re-run with sudo (or as administrator) if the directory isn't writable.
+Shell configuration files are only modified for per-user installs.
+
 Supported shells:
   • bash (Linux/Mac)
   • zsh (Mac/Linux)
@@ -39,13 +56,25 @@ The PATH modification is persistent and will survive system restarts.
 After installation, you may need to restart your terminal or run the
 suggested command to reload your shell configuration.
 
+For distribution maintainers, --generate brew|scoop writes a Homebrew
+formula or Scoop manifest for a release instead of installing. SHA-256
+checksums are computed from the release archives in --dist (goreleaser's
+output folder), and download URLs point at the GitHub releases of --repo.
+
 Examples:
   smfaman install              # Install to ~/bin
-  smfaman install --force      # Overwrite if already installed`,
+  smfaman install --force      # Overwrite if already installed
+  sudo smfaman install --system
+  smfaman install --prefix ~/.local/bin
+  smfaman install --generate brew --repo me/smfaman > smfaman.rb
+  smfaman install --generate scoop --repo me/smfaman --release-version 1.4.0 -o smfaman.json`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := runInstall(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+		run := runInstall
+		if installGenerate != "" {
+			run = func() error { return runGenerateManifest(installGenerate) }
+		}
+		if err := run(); err != nil {
+			exitWithError(err)
 		}
 	},
 }
@@ -53,6 +82,14 @@ Examples:
 func init() {
 	rootCmd.AddCommand(installCmd)
 	installCmd.Flags().BoolVar(&installForce, "force", false, "Overwrite existing installation")
+	installCmd.Flags().StringVar(&installPrefix, "prefix", "", "Install into this directory (e.g. /usr/local/bin)")
+	installCmd.Flags().BoolVar(&installSystem, "system", false, "Install system-wide (/usr/local/bin, or %ProgramFiles%\\smfaman on Windows)")
+	installCmd.MarkFlagsMutuallyExclusive("prefix", "system")
+	installCmd.Flags().StringVar(&installGenerate, "generate", "", "Write a package manager manifest instead of installing (brew, scoop)")
+	installCmd.Flags().StringVar(&installDist, "dist", "dist", "Folder with the release archives to checksum")
+	installCmd.Flags().StringVar(&installRepo, "repo", "", "GitHub repository (owner/name) hosting the release downloads")
+	installCmd.Flags().StringVar(&installReleaseVersion, "release-version", getBuildInfo().Version, "Release version the manifest describes")
+	installCmd.Flags().StringVarP(&installOutput, "output", "o", "", "Write the manifest to a file instead of stdout")
 }
 
 func runInstall() error {
@@ -68,14 +105,14 @@ func runInstall() error {
 		return fmt.Errorf("failed to resolve executable path: %w", err)
 	}
 
-	// Get user's home directory
-	homeDir, err := os.UserHomeDir()
+	// Determine bin directory
+	binDir, perUser, err := installBinDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
+	}
+	if perUser && os.Getenv("SUDO_USER") != "" {
+		fmt.Fprintf(os.Stderr, "Warning: running under sudo installs into root's %s; use --system or --prefix for a system-wide install\n", binDir)
 	}
-
-	// Determine bin directory based on OS
-	binDir := filepath.Join(homeDir, "bin")
 
 	fmt.Printf("Installing smfaman...\n\n")
 	fmt.Printf("Source:      %s\n", exePath)
@@ -94,23 +131,79 @@ func runInstall() error {
 		return err
 	}
 
-	// Check and update PATH
-	if err := ensureInPath(binDir); err != nil {
-		return err
+	// Check and update PATH. Shell configs are only edited for per-user
+	// installs; under sudo they would belong to root.
+	if perUser {
+		if err := ensureInPath(binDir); err != nil {
+			return err
+		}
+	} else if !isInPath(binDir) {
+		fmt.Printf("⚠ %s is not in PATH; add it to your shell configuration to run smfaman\n", binDir)
+	} else {
+		fmt.Printf("✓ %s is already in PATH\n", binDir)
 	}
 
 	fmt.Printf("\n✓ Installation complete!\n\n")
 
 	// Show reload instructions
-	showReloadInstructions()
+	if perUser {
+		showReloadInstructions()
+	}
 
 	return nil
 }
 
+// installBinDir returns the directory to install into and whether it is a
+// per-user directory: --prefix, --system, $XDG_BIN_HOME, then ~/bin
+func installBinDir() (string, bool, error) {
+	if installPrefix != "" {
+		dir, err := filepath.Abs(installPrefix)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid prefix %s: %w", installPrefix, err)
+		}
+		return dir, false, nil
+	}
+
+	if installSystem {
+		if runtime.GOOS == "windows" {
+			programFiles := os.Getenv("ProgramFiles")
+			if programFiles == "" {
+				return "", false, fmt.Errorf("ProgramFiles is not set, use --prefix instead")
+			}
+			return filepath.Join(programFiles, "smfaman"), false, nil
+		}
+		return "/usr/local/bin", false, nil
+	}
+
+	if xdgBin := os.Getenv("XDG_BIN_HOME"); xdgBin != "" {
+		return xdgBin, true, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, "bin"), true, nil
+}
+
+// permissionError explains how to install into a directory the user can't write to
+func permissionError(action, path string, err error) error {
+	hint := "re-run with sudo, or pick a directory you own with --prefix"
+	if runtime.GOOS == "windows" {
+		hint = "re-run from an administrator terminal, or pick a directory you own with --prefix"
+	} else if os.Geteuid() == 0 {
+		hint = "check the directory's permissions"
+	}
+	return fmt.Errorf("permission denied: cannot %s %s (%s): %w", action, path, hint, err)
+}
+
 func createBinDirectory(binDir string) error {
 	if _, err := os.Stat(binDir); os.IsNotExist(err) {
 		fmt.Printf("Creating directory: %s\n", binDir)
 		if err := os.MkdirAll(binDir, 0755); err != nil {
+			if os.IsPermission(err) {
+				return permissionError("create", binDir, err)
+			}
 			return fmt.Errorf("failed to create bin directory: %w", err)
 		}
 		fmt.Printf("✓ Directory created\n\n")
@@ -137,6 +230,9 @@ func copyBinary(src, dest string) error {
 
 	// Write to destination with executable permissions
 	if err := os.WriteFile(dest, data, 0755); err != nil {
+		if os.IsPermission(err) {
+			return permissionError("write", dest, err)
+		}
 		return fmt.Errorf("failed to write binary: %w", err)
 	}
 
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// releasePlatform is a platform smfaman release archives are built for
+// (see .goreleaser.yaml)
+type releasePlatform struct {
+	OS     string
+	Arch   string
+	Format string // Archive extension: tar.gz, or zip on Windows
+}
+
+// releasePlatforms lists the archives published with each release
+var releasePlatforms = []releasePlatform{
+	{OS: "darwin", Arch: "amd64", Format: "tar.gz"},
+	{OS: "darwin", Arch: "arm64", Format: "tar.gz"},
+	{OS: "linux", Arch: "amd64", Format: "tar.gz"},
+	{OS: "linux", Arch: "arm64", Format: "tar.gz"},
+	{OS: "windows", Arch: "amd64", Format: "zip"},
+}
+
+// releaseArchive is a release archive with its download URL and checksum
+type releaseArchive struct {
+	releasePlatform
+	Name   string
+	URL    string
+	SHA256 string
+}
+
+// releaseInfo describes a release for package manager manifests
+type releaseInfo struct {
+	Version  string
+	Homepage string
+	Archives []releaseArchive
+}
+
+// archive returns the archive for a platform, or nil if it wasn't found
+func (r releaseInfo) archive(goos, arch string) *releaseArchive {
+	for i := range r.Archives {
+		if r.Archives[i].OS == goos && r.Archives[i].Arch == arch {
+			return &r.Archives[i]
+		}
+	}
+	return nil
+}
+
+// collectReleaseArchives computes the checksums of the release archives in
+// distDir. Platforms without an archive are skipped with a warning.
+func collectReleaseArchives(distDir, repo, releaseVersion string) (releaseInfo, error) {
+	info := releaseInfo{
+		Version:  releaseVersion,
+		Homepage: "https://github.com/" + repo,
+	}
+
+	for _, platform := range releasePlatforms {
+		name := fmt.Sprintf("smfaman_%s_%s_%s.%s", releaseVersion, platform.OS, platform.Arch, platform.Format)
+		sum, _, err := hashFile(filepath.Join(distDir, name))
+		if os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: %s not found in %s, skipping %s/%s\n", name, distDir, platform.OS, platform.Arch)
+			continue
+		}
+		if err != nil {
+			return info, fmt.Errorf("failed to checksum %s: %w", name, err)
+		}
+
+		info.Archives = append(info.Archives, releaseArchive{
+			releasePlatform: platform,
+			Name:            name,
+			URL:             fmt.Sprintf("%s/releases/download/v%s/%s", info.Homepage, releaseVersion, name),
+			SHA256:          sum,
+		})
+	}
+
+	if len(info.Archives) == 0 {
+		return info, fmt.Errorf("no release archives for version %s found in %s (run goreleaser first)", releaseVersion, distDir)
+	}
+	return info, nil
+}
+
+// brewFormulaTemplate renders a Homebrew formula installing the prebuilt binaries
+var brewFormulaTemplate = template.Must(template.New("brew").Parse(`class Smfaman < Formula
+  desc "Smart Frontend Asset Manager"
+  homepage "{{.Homepage}}"
+  version "{{.Version}}"
+  license "MIT"
+{{range $os := .Systems}}
+  on_{{$os.Name}} do
+{{- range $os.Archives}}
+    on_{{.CPU}} do
+      url "{{.URL}}"
+      sha256 "{{.SHA256}}"
+    end
+{{- end}}
+  end
+{{end}}
+  def install
+    bin.install "smfaman"
+  end
+
+  test do
+    system "#{bin}/smfaman", "version"
+  end
+end
+`))
+
+// brewSystem groups the archives of one operating system in a formula
+type brewSystem struct {
+	Name     string // "macos" or "linux"
+	Archives []brewArchive
+}
+
+// brewArchive is an archive with its Homebrew CPU block name
+type brewArchive struct {
+	releaseArchive
+	CPU string // "intel" or "arm"
+}
+
+// writeBrewFormula writes a Homebrew formula for the macOS and Linux archives
+func writeBrewFormula(w io.Writer, info releaseInfo) error {
+	var systems []brewSystem
+	for _, goos := range []string{"darwin", "linux"} {
+		system := brewSystem{Name: goos}
+		if goos == "darwin" {
+			system.Name = "macos"
+		}
+		for _, arch := range []string{"arm64", "amd64"} {
+			if archive := info.archive(goos, arch); archive != nil {
+				cpu := "intel"
+				if arch == "arm64" {
+					cpu = "arm"
+				}
+				system.Archives = append(system.Archives, brewArchive{releaseArchive: *archive, CPU: cpu})
+			}
+		}
+		if len(system.Archives) > 0 {
+			systems = append(systems, system)
+		}
+	}
+	if len(systems) == 0 {
+		return fmt.Errorf("no macOS or Linux archives to build a Homebrew formula from")
+	}
+
+	return brewFormulaTemplate.Execute(w, struct {
+		releaseInfo
+		Systems []brewSystem
+	}{info, systems})
+}
+
+// scoopManifest is a Scoop app manifest
+type scoopManifest struct {
+	Version      string                       `json:"version"`
+	Description  string                       `json:"description"`
+	Homepage     string                       `json:"homepage"`
+	License      string                       `json:"license"`
+	Architecture map[string]scoopArchitecture `json:"architecture"`
+	Bin          string                       `json:"bin"`
+	Checkver     string                       `json:"checkver"`
+}
+
+// scoopArchitecture is the download for one Scoop architecture
+type scoopArchitecture struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash"`
+}
+
+// writeScoopManifest writes a Scoop manifest for the Windows archive
+func writeScoopManifest(w io.Writer, info releaseInfo) error {
+	archive := info.archive("windows", "amd64")
+	if archive == nil {
+		return fmt.Errorf("no Windows archive to build a Scoop manifest from")
+	}
+
+	manifest := scoopManifest{
+		Version:     info.Version,
+		Description: "Smart Frontend Asset Manager",
+		Homepage:    info.Homepage,
+		License:     "MIT",
+		Architecture: map[string]scoopArchitecture{
+			"64bit": {URL: archive.URL, Hash: archive.SHA256},
+		},
+		Bin:      "smfaman.exe",
+		Checkver: "github",
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("failed to encode Scoop manifest: %w", err)
+	}
+	return nil
+}
+
+// runGenerateManifest writes a package manager manifest for a release
+func runGenerateManifest(kind string) error {
+	var write func(io.Writer, releaseInfo) error
+	switch kind {
+	case "brew":
+		write = writeBrewFormula
+	case "scoop":
+		write = writeScoopManifest
+	default:
+		return fmt.Errorf("unsupported manifest type %q (use brew or scoop)", kind)
+	}
+
+	releaseVersion := strings.TrimPrefix(installReleaseVersion, "v")
+	if releaseVersion == "" || releaseVersion == "dev" {
+		return fmt.Errorf("this is a development build, use --release-version to name the release")
+	}
+
+	repo := installRepo
+	if repo == "" {
+		repo = os.Getenv("GITHUB_REPOSITORY")
+	}
+	if repo == "" {
+		return fmt.Errorf("use --repo owner/name (or set GITHUB_REPOSITORY) to locate release downloads")
+	}
+
+	info, err := collectReleaseArchives(installDist, repo, releaseVersion)
+	if err != nil {
+		return err
+	}
+
+	if installOutput == "" {
+		return write(os.Stdout, info)
+	}
+
+	f, err := os.Create(installOutput)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", installOutput, err)
+	}
+	defer f.Close()
+	if err := write(f, info); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✓ Wrote %s manifest for smfaman %s to %s\n", kind, releaseVersion, installOutput)
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeReleaseArchives creates fake release archives and returns their checksums by name
+func writeReleaseArchives(t *testing.T, distDir, releaseVersion string, platforms []releasePlatform) map[string]string {
+	t.Helper()
+
+	sums := make(map[string]string)
+	for _, p := range platforms {
+		name := "smfaman_" + releaseVersion + "_" + p.OS + "_" + p.Arch + "." + p.Format
+		content := "archive for " + p.OS + "/" + p.Arch
+		writeTestFile(t, filepath.Join(distDir, name), content)
+
+		sum := sha256.Sum256([]byte(content))
+		sums[name] = hex.EncodeToString(sum[:])
+	}
+	return sums
+}
+
+func TestWriteBrewFormula(t *testing.T) {
+	distDir := t.TempDir()
+	// No linux/arm64 archive: that platform is skipped
+	sums := writeReleaseArchives(t, distDir, "1.4.0", []releasePlatform{
+		releasePlatforms[0], releasePlatforms[1], releasePlatforms[2], releasePlatforms[4],
+	})
+
+	info, err := collectReleaseArchives(distDir, "me/smfaman", "1.4.0")
+	if err != nil {
+		t.Fatalf("collectReleaseArchives() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBrewFormula(&buf, info); err != nil {
+		t.Fatalf("writeBrewFormula() error = %v", err)
+	}
+	formula := buf.String()
+
+	for _, want := range []string{
+		`class Smfaman < Formula`,
+		`version "1.4.0"`,
+		`homepage "https://github.com/me/smfaman"`,
+		`url "https://github.com/me/smfaman/releases/download/v1.4.0/smfaman_1.4.0_darwin_arm64.tar.gz"`,
+		`sha256 "` + sums["smfaman_1.4.0_darwin_arm64.tar.gz"] + `"`,
+		`sha256 "` + sums["smfaman_1.4.0_linux_amd64.tar.gz"] + `"`,
+		`on_macos do`,
+		`on_linux do`,
+		`bin.install "smfaman"`,
+	} {
+		if !strings.Contains(formula, want) {
+			t.Errorf("formula missing %s:\n%s", want, formula)
+		}
+	}
+	if strings.Contains(formula, "linux_arm64") || strings.Contains(formula, "windows") {
+		t.Errorf("formula lists archives it shouldn't:\n%s", formula)
+	}
+}
+
+func TestWriteScoopManifest(t *testing.T) {
+	distDir := t.TempDir()
+	sums := writeReleaseArchives(t, distDir, "1.4.0", releasePlatforms)
+
+	info, err := collectReleaseArchives(distDir, "me/smfaman", "1.4.0")
+	if err != nil {
+		t.Fatalf("collectReleaseArchives() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeScoopManifest(&buf, info); err != nil {
+		t.Fatalf("writeScoopManifest() error = %v", err)
+	}
+
+	var manifest scoopManifest
+	if err := json.Unmarshal(buf.Bytes(), &manifest); err != nil {
+		t.Fatalf("manifest is not valid JSON: %v\n%s", err, buf.String())
+	}
+	arch := manifest.Architecture["64bit"]
+	if manifest.Version != "1.4.0" || manifest.Bin != "smfaman.exe" {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if arch.Hash != sums["smfaman_1.4.0_windows_amd64.zip"] || !strings.HasSuffix(arch.URL, "/v1.4.0/smfaman_1.4.0_windows_amd64.zip") {
+		t.Errorf("64bit download = %+v", arch)
+	}
+}
+
+func TestCollectReleaseArchivesMissing(t *testing.T) {
+	if _, err := collectReleaseArchives(t.TempDir(), "me/smfaman", "1.4.0"); err == nil {
+		t.Error("expected an error when no archives exist")
+	}
+
+	distDir := t.TempDir()
+	writeReleaseArchives(t, distDir, "1.4.0", releasePlatforms[:1])
+	info, err := collectReleaseArchives(distDir, "me/smfaman", "1.4.0")
+	if err != nil {
+		t.Fatalf("collectReleaseArchives() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeScoopManifest(&buf, info); err == nil {
+		t.Error("expected an error building a Scoop manifest without a Windows archive")
+	}
+}
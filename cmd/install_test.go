@@ -293,3 +293,70 @@ func TestCopyBinary(t *testing.T) {
 		t.Errorf("copyBinary should succeed with force=true: %v", err)
 	}
 }
+
+func TestInstallBinDir(t *testing.T) {
+	origPrefix, origSystem := installPrefix, installSystem
+	defer func() { installPrefix, installSystem = origPrefix, origSystem }()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	t.Run("default", func(t *testing.T) {
+		installPrefix, installSystem = "", false
+		t.Setenv("XDG_BIN_HOME", "")
+		dir, perUser, err := installBinDir()
+		if err != nil || dir != filepath.Join(home, "bin") || !perUser {
+			t.Errorf("installBinDir() = %s, %v, %v, want ~/bin per-user", dir, perUser, err)
+		}
+	})
+
+	t.Run("XDG_BIN_HOME", func(t *testing.T) {
+		installPrefix, installSystem = "", false
+		xdg := filepath.Join(home, ".local", "bin")
+		t.Setenv("XDG_BIN_HOME", xdg)
+		dir, perUser, err := installBinDir()
+		if err != nil || dir != xdg || !perUser {
+			t.Errorf("installBinDir() = %s, %v, %v, want %s per-user", dir, perUser, err, xdg)
+		}
+	})
+
+	t.Run("prefix wins", func(t *testing.T) {
+		prefix := filepath.Join(home, "opt", "bin")
+		installPrefix, installSystem = prefix, false
+		t.Setenv("XDG_BIN_HOME", filepath.Join(home, "xdg"))
+		dir, perUser, err := installBinDir()
+		if err != nil || dir != prefix || perUser {
+			t.Errorf("installBinDir() = %s, %v, %v, want %s system", dir, perUser, err, prefix)
+		}
+	})
+
+	t.Run("system", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("system directory differs on Windows")
+		}
+		installPrefix, installSystem = "", true
+		dir, perUser, err := installBinDir()
+		if err != nil || dir != "/usr/local/bin" || perUser {
+			t.Errorf("installBinDir() = %s, %v, %v, want /usr/local/bin", dir, perUser, err)
+		}
+	})
+}
+
+func TestCopyBinaryPermissionDenied(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("needs a non-root Unix user to deny writes")
+	}
+
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "smfaman")
+	os.WriteFile(src, []byte("binary"), 0755)
+
+	readOnly := filepath.Join(tmpDir, "readonly")
+	os.Mkdir(readOnly, 0555)
+
+	err := copyBinary(src, filepath.Join(readOnly, "smfaman"))
+	if err == nil || !strings.Contains(err.Error(), "permission denied") || !strings.Contains(err.Error(), "sudo") {
+		t.Errorf("copyBinary() error = %v, want a permission error mentioning sudo", err)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// linkFallbackWarned records whether the copy fallback warning was printed
+var linkFallbackWarned bool
+
+// linkFile is the link function used for the given mode (overridable in tests)
+var linkFile = func(mode frontend_config.LinkMode, storePath, destPath string) error {
+	switch mode {
+	case frontend_config.LinkModeSymlink:
+		return os.Symlink(storePath, destPath)
+	case frontend_config.LinkModeHardlink:
+		return os.Link(storePath, destPath)
+	default:
+		return fmt.Errorf("unsupported link mode %q", mode)
+	}
+}
+
+// placeFile writes data to destPath, or links destPath to the package store
+// copy at storePath when a link mode is active. Linking falls back to a copy
+// when the filesystem doesn't support it (e.g., symlinks on Windows without
+// developer mode, or hard links across devices).
+func placeFile(destPath string, data []byte, storePath string, mode frontend_config.LinkMode) error {
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Replace existing files so writes never go through a link into the store
+	if err := os.Remove(destPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	if storePath != "" && mode != "" && mode != frontend_config.LinkModeCopy {
+		err := linkFile(mode, storePath, destPath)
+		if err == nil {
+			return nil
+		}
+		if !linkFallbackWarned {
+			linkFallbackWarned = true
+			fmt.Fprintf(os.Stderr, "Warning: failed to %s files, copying instead: %v\n", mode, err)
+		}
+	}
+
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
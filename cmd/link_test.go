@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestPlaceFileLinksToStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "store", "app.js")
+	writeTestFile(t, storePath, "stored")
+
+	for _, mode := range []frontend_config.LinkMode{frontend_config.LinkModeSymlink, frontend_config.LinkModeHardlink} {
+		t.Run(string(mode), func(t *testing.T) {
+			destPath := filepath.Join(tmpDir, string(mode), "dist", "app.js")
+			if err := placeFile(destPath, []byte("stored"), storePath, mode); err != nil {
+				t.Fatalf("placeFile failed: %v", err)
+			}
+
+			info, err := os.Lstat(destPath)
+			if err != nil {
+				t.Fatalf("expected destination to exist: %v", err)
+			}
+			isSymlink := info.Mode()&os.ModeSymlink != 0
+			if isSymlink != (mode == frontend_config.LinkModeSymlink) {
+				t.Errorf("unexpected file mode %v for %s", info.Mode(), mode)
+			}
+
+			storeInfo, _ := os.Stat(storePath)
+			destInfo, _ := os.Stat(destPath)
+			if !os.SameFile(storeInfo, destInfo) {
+				t.Error("expected destination to share the store file")
+			}
+		})
+	}
+}
+
+func TestPlaceFileDoesNotWriteThroughLinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "store", "app.js")
+	writeTestFile(t, storePath, "stored")
+
+	destPath := filepath.Join(tmpDir, "public", "app.js")
+	if err := placeFile(destPath, []byte("stored"), storePath, frontend_config.LinkModeSymlink); err != nil {
+		t.Fatalf("placeFile failed: %v", err)
+	}
+
+	// Copying over a linked destination must replace the link
+	if err := placeFile(destPath, []byte("patched"), "", frontend_config.LinkModeCopy); err != nil {
+		t.Fatalf("placeFile failed: %v", err)
+	}
+
+	if data, _ := os.ReadFile(storePath); string(data) != "stored" {
+		t.Errorf("store file was modified: %q", data)
+	}
+	if data, _ := os.ReadFile(destPath); string(data) != "patched" {
+		t.Errorf("unexpected destination content: %q", data)
+	}
+}
+
+func TestPlaceFileFallsBackToCopy(t *testing.T) {
+	oldLinkFile := linkFile
+	oldWarned := linkFallbackWarned
+	linkFile = func(mode frontend_config.LinkMode, storePath, destPath string) error {
+		return errors.New("links not supported")
+	}
+	defer func() {
+		linkFile = oldLinkFile
+		linkFallbackWarned = oldWarned
+	}()
+
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "store", "app.js")
+	writeTestFile(t, storePath, "stored")
+
+	destPath := filepath.Join(tmpDir, "public", "app.js")
+	if err := placeFile(destPath, []byte("stored"), storePath, frontend_config.LinkModeSymlink); err != nil {
+		t.Fatalf("placeFile failed: %v", err)
+	}
+
+	info, err := os.Lstat(destPath)
+	if err != nil {
+		t.Fatalf("expected destination to exist: %v", err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Errorf("expected a regular file copy, got %v", info.Mode())
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var listLong bool
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List libraries in the configuration",
+	Long: `List the libraries defined in the frontend configuration file.
+
+Tags and notes recorded in the configuration are shown with each library.
+Development-only libraries (dev_libraries) are listed after the others.
+
+With --long, the description, homepage, and license recorded by
+'smfaman add --metadata' are shown as well. This information is read from the
+metadata file next to the configuration (e.g. smartfrontend.meta.yaml), so no
+network access is needed.
+
+Examples:
+  smfaman list
+  smfaman list --long
+  smfaman -f myproject.yaml ls`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runList(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().BoolVarP(&listLong, "long", "l", false, "Show recorded package metadata")
+}
+
+// runList executes the list command
+func runList() error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(config.Libraries) == 0 && len(config.DevLibraries) == 0 {
+		fmt.Println("No libraries defined in configuration.")
+		return nil
+	}
+
+	var metadata *libraryMetadataFile
+	if listLong {
+		metadata, err = loadLibraryMetadata(FrontendConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := sortedKeys(config.Libraries)
+	devNames := sortedKeys(config.DevLibraries)
+	libraries := maps.Clone(config.Libraries)
+	maps.Copy(libraries, config.DevLibraries)
+
+	// Calculate column widths; the tags column only appears when used
+	maxName := len("LIBRARY")
+	maxVersion := len("VERSION")
+	maxGroups := 0
+	hasTags := false
+	for name, libConfig := range libraries {
+		maxName = max(maxName, len(name))
+		maxVersion = max(maxVersion, len(versionLabel(libConfig.Version)))
+		maxGroups = max(maxGroups, len(strings.Join(libConfig.Groups, ", ")))
+		hasTags = hasTags || len(libConfig.Tags) > 0
+	}
+
+	rowFormat := fmt.Sprintf("%%-%ds  %%-%ds  %%-8s  %%s\n", maxName, maxVersion)
+	header := fmt.Sprintf(rowFormat, "LIBRARY", "VERSION", "CDN", "GROUPS")
+	rule := strings.Repeat("─", maxName) + "  " + strings.Repeat("─", maxVersion) + "  " +
+		strings.Repeat("─", 8) + "  " + strings.Repeat("─", len("GROUPS"))
+	if hasTags {
+		maxGroups = max(maxGroups, len("GROUPS"))
+		rowFormat = fmt.Sprintf("%%-%ds  %%-%ds  %%-8s  %%-%ds  %%s\n", maxName, maxVersion, maxGroups)
+		header = fmt.Sprintf(rowFormat, "LIBRARY", "VERSION", "CDN", "GROUPS", "TAGS")
+		rule = strings.Repeat("─", maxName) + "  " + strings.Repeat("─", maxVersion) + "  " +
+			strings.Repeat("─", 8) + "  " + strings.Repeat("─", maxGroups) + "  " + strings.Repeat("─", len("TAGS"))
+	}
+	fmt.Print(header)
+	fmt.Println(rule)
+
+	printRows := func(names []string) {
+		for _, name := range names {
+			libConfig := libraries[name]
+			cdn := libraryCDN(config, libConfig)
+			columns := []any{name, versionLabel(libConfig.Version), cdn, strings.Join(libConfig.Groups, ", ")}
+			if hasTags {
+				columns = append(columns, strings.Join(libConfig.Tags, ", "))
+			}
+			fmt.Printf(rowFormat, columns...)
+
+			if libConfig.Notes != "" {
+				fmt.Printf("%sNote: %s\n", strings.Repeat(" ", maxName+2), libConfig.Notes)
+			}
+
+			if listLong {
+				printLibraryMetadata(metadata, name, libConfig.Version, maxName)
+			}
+		}
+	}
+
+	printRows(names)
+	total := fmt.Sprintf("%d %s", len(names), pluralize(len(names), "library", "libraries"))
+	if len(devNames) > 0 {
+		fmt.Println("\nDevelopment only (synced with 'smfaman sync --dev'):")
+		printRows(devNames)
+		total += fmt.Sprintf(", %d dev", len(devNames))
+	}
+
+	fmt.Printf("\n%s\n", total)
+	return nil
+}
+
+// printLibraryMetadata prints the recorded metadata for a library, indented under its row
+func printLibraryMetadata(metadata *libraryMetadataFile, name, version string, indent int) {
+	prefix := strings.Repeat(" ", indent+2)
+
+	meta, ok := metadata.Libraries[name]
+	if !ok {
+		fmt.Printf("%s(no metadata recorded; run 'smfaman add %s@%s --metadata --force' to fetch it)\n", prefix, name, version)
+		return
+	}
+
+	if meta.Description != "" {
+		fmt.Printf("%s%s\n", prefix, meta.Description)
+	}
+	if meta.Homepage != "" {
+		fmt.Printf("%sHomepage:   %s\n", prefix, meta.Homepage)
+	}
+	if meta.Repository != "" {
+		fmt.Printf("%sRepository: %s\n", prefix, meta.Repository)
+	}
+	if meta.License != "" {
+		fmt.Printf("%sLicense:    %s\n", prefix, meta.License)
+	}
+}
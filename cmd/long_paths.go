@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"path"
+	"strings"
+)
+
+// windowsMaxPath is the longest path (MAX_PATH, including the terminating
+// NUL) Windows tools accept without long path support. smfaman itself is not
+// limited by it: Go's os package adds the \\?\ extended-length prefix to
+// long paths on Windows.
+const windowsMaxPath = 260
+
+// localFilePaths maps each file's CDN path to its path in the library
+// folder, removing stripPrefix (e.g. "dist/") from the files under it
+func localFilePaths(files []CDNFile, stripPrefix string) map[string]string {
+	prefix := strings.Trim(stripPrefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	local := make(map[string]string, len(files))
+	for _, file := range files {
+		local[file.Path] = file.Path
+		if rest, ok := strings.CutPrefix(file.Path, prefix); ok && prefix != "" && rest != "" {
+			local[file.Path] = path.Clean(rest)
+		}
+	}
+	return local
+}
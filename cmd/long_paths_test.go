@@ -0,0 +1,31 @@
+package cmd
+
+import "testing"
+
+func TestLocalFilePaths(t *testing.T) {
+	files := []CDNFile{
+		{Path: "dist/js/app.min.js"},
+		{Path: "dist/css/app.css"},
+		{Path: "distribution/readme.md"},
+		{Path: "LICENSE"},
+	}
+
+	for _, prefix := range []string{"dist", "dist/", "/dist/"} {
+		local := localFilePaths(files, prefix)
+		want := map[string]string{
+			"dist/js/app.min.js":     "js/app.min.js",
+			"dist/css/app.css":       "css/app.css",
+			"distribution/readme.md": "distribution/readme.md",
+			"LICENSE":                "LICENSE",
+		}
+		for path, w := range want {
+			if local[path] != w {
+				t.Errorf("strip_prefix %q: %s saved as %q, want %q", prefix, path, local[path], w)
+			}
+		}
+	}
+
+	if local := localFilePaths(files, ""); local["dist/css/app.css"] != "dist/css/app.css" {
+		t.Errorf("without strip_prefix paths should be kept, got %v", local)
+	}
+}
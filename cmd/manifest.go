@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// manifestFormatVersion is bumped when the manifest file format changes
+const manifestFormatVersion = 1
+
+// fileManifest records the provenance of every vendored file.
+// It is stored next to the frontend config as <config>.lock.json.
+type fileManifest struct {
+	FormatVersion int                      `json:"format_version"`
+	Files         map[string]manifestEntry `json:"files"` // Keyed by slash path relative to the manifest
+	Migrations    []versionMigration       `json:"migrations,omitempty"`
+}
+
+// manifestEntry describes where a single vendored file came from
+type manifestEntry struct {
+	Library          string    `json:"library"`
+	Version          string    `json:"version"`
+	CDN              string    `json:"cdn"`
+	File             string    `json:"file"` // Path on CDN
+	URL              string    `json:"url"`
+	Integrity        string    `json:"integrity"`                   // SRI hash of the written file
+	CDNIntegrity     string    `json:"cdn_integrity,omitempty"`     // SRI hash of the file as the CDN serves it, when sync changed it (e.g. stripped sourcemap comments)
+	TarballIntegrity string    `json:"tarball_integrity,omitempty"` // SRI hash of the verified npm tarball the file came from
+	ETag             string    `json:"etag,omitempty"`
+	LastModified     string    `json:"last_modified,omitempty"`
+	DownloadedAt     time.Time `json:"downloaded_at"`
+}
+
+// manifestPathForConfig returns the manifest path for a frontend config file
+func manifestPathForConfig(configPath string) string {
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".lock.json"
+}
+
+// loadManifest reads a manifest, returning an empty one if it does not exist
+func loadManifest(path string) (*fileManifest, error) {
+	manifest := &fileManifest{
+		FormatVersion: manifestFormatVersion,
+		Files:         make(map[string]manifestEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]manifestEntry)
+	}
+
+	return manifest, nil
+}
+
+// saveManifest writes a manifest to disk
+func saveManifest(path string, manifest *fileManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// manifestKey returns the manifest key for a local file path
+func manifestKey(manifestPath, localPath string) (string, error) {
+	baseDir, err := filepath.Abs(filepath.Dir(manifestPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve manifest directory: %w", err)
+	}
+
+	absPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", localPath, err)
+	}
+
+	rel, err := filepath.Rel(baseDir, absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", localPath, err)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// recordDownloads adds downloaded files to the manifest
+func (fm *fileManifest) recordDownloads(manifestPath string, downloads []downloadedFile) error {
+	for _, d := range downloads {
+		key, err := manifestKey(manifestPath, d.task.DestPath)
+		if err != nil {
+			return err
+		}
+
+		// A file the CDN reported unmodified keeps the served hash from before
+		cdnIntegrity := d.result.CDNIntegrity
+		if previous, ok := fm.Files[key]; ok && cdnIntegrity == "" && d.result.Unchanged && previous.URL == d.task.URL {
+			cdnIntegrity = previous.CDNIntegrity
+		}
+
+		fm.Files[key] = manifestEntry{
+			Library:          d.task.LibraryName,
+			Version:          d.task.Version,
+			CDN:              string(d.task.CDN),
+			File:             d.task.FilePath,
+			URL:              d.task.URL,
+			Integrity:        d.result.Integrity,
+			CDNIntegrity:     cdnIntegrity,
+			TarballIntegrity: d.task.tarballIntegrity,
+			ETag:             d.result.ETag,
+			LastModified:     d.result.LastModified,
+			DownloadedAt:     d.downloadedAt.UTC(),
+		}
+	}
+	return nil
+}
+
+// removeUnder drops all entries for files inside the given directory
+func (fm *fileManifest) removeUnder(manifestPath, dir string) error {
+	prefix, err := manifestKey(manifestPath, dir)
+	if err != nil {
+		return err
+	}
+
+	for key := range fm.Files {
+		if key == prefix || strings.HasPrefix(key, prefix+"/") {
+			delete(fm.Files, key)
+		}
+	}
+	return nil
+}
+
+// pruneManifest drops manifest entries for removed directories
+func pruneManifest(configPath string, dirs []string) error {
+	manifestPath := manifestPathForConfig(configPath)
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) || len(dirs) == 0 {
+		return nil
+	}
+
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := manifest.removeUnder(manifestPath, dir); err != nil {
+			return err
+		}
+	}
+
+	return saveManifest(manifestPath, manifest)
+}
+
+// updateManifest records downloaded files in the manifest for a config,
+// unless the config turns the lockfile off
+func updateManifest(configPath string, downloads []downloadedFile) error {
+	if configPath == "" || len(downloads) == 0 || !lockfileEnabled(configPath) {
+		return nil
+	}
+
+	manifestPath := manifestPathForConfig(configPath)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if err := manifest.recordDownloads(manifestPath, downloads); err != nil {
+		return err
+	}
+
+	return saveManifest(manifestPath, manifest)
+}
+
+// lockfileEnabled reports whether the config at configPath lets sync write
+// its lockfile. Unreadable configs keep the default of writing it.
+func lockfileEnabled(configPath string) bool {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return true
+	}
+	var config frontend_config.FrontendConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return true
+	}
+	return config.LockfileEnabled()
+}
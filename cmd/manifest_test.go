@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestManifestPathForConfig(t *testing.T) {
+	tests := []struct {
+		config   string
+		expected string
+	}{
+		{"smartfrontend.yaml", "smartfrontend.lock.json"},
+		{filepath.Join("site", "frontend.yml"), filepath.Join("site", "frontend.lock.json")},
+		{"config", "config.lock.json"},
+	}
+
+	for _, tt := range tests {
+		if got := manifestPathForConfig(tt.config); got != tt.expected {
+			t.Errorf("manifestPathForConfig(%q) = %q, want %q", tt.config, got, tt.expected)
+		}
+	}
+}
+
+func TestManifestRecordAndLookup(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	destPath := filepath.Join(tmpDir, "libs", "jquery", "dist", "jquery.min.js")
+
+	downloads := []downloadedFile{{
+		task: DownloadTask{
+			LibraryName: "jquery",
+			Version:     "3.7.1",
+			CDN:         "jsdelivr",
+			FilePath:    "dist/jquery.min.js",
+			DestPath:    destPath,
+			URL:         "https://cdn.jsdelivr.net/npm/jquery@3.7.1/dist/jquery.min.js",
+		},
+		result:       fileDownloadResult{Integrity: "sha384-abc"},
+		downloadedAt: time.Now(),
+	}}
+
+	if err := updateManifest(configPath, downloads); err != nil {
+		t.Fatalf("updateManifest failed: %v", err)
+	}
+
+	manifest, err := loadManifest(manifestPathForConfig(configPath))
+	if err != nil {
+		t.Fatalf("loadManifest failed: %v", err)
+	}
+	if _, ok := manifest.Files["libs/jquery/dist/jquery.min.js"]; !ok {
+		t.Errorf("expected entry keyed by relative slash path, got %v", manifest.Files)
+	}
+
+	entry, err := lookupProvenance(configPath, destPath)
+	if err != nil {
+		t.Fatalf("lookupProvenance failed: %v", err)
+	}
+	if entry.URL != downloads[0].task.URL || entry.CDN != "jsdelivr" || entry.Integrity != "sha384-abc" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	if _, err := lookupProvenance(configPath, filepath.Join(tmpDir, "other.js")); err == nil {
+		t.Error("expected error for file without provenance")
+	}
+
+	// Removing the library directory drops its entries
+	if err := pruneManifest(configPath, []string{filepath.Join(tmpDir, "libs", "jquery")}); err != nil {
+		t.Fatalf("pruneManifest failed: %v", err)
+	}
+	if _, err := lookupProvenance(configPath, destPath); err == nil {
+		t.Error("expected entry to be pruned")
+	}
+}
+
+func TestLookupProvenanceWithoutManifest(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	if _, err := lookupProvenance(configPath, "anything.js"); err == nil {
+		t.Error("expected error when no lockfile exists")
+	}
+}
+
+func TestRemoveUnderDoesNotMatchSiblingPrefixes(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifestPath := filepath.Join(tmpDir, "smartfrontend.lock.json")
+	manifest := &fileManifest{Files: map[string]manifestEntry{
+		"libs/react/index.js":     {},
+		"libs/react-dom/index.js": {},
+	}}
+
+	if err := manifest.removeUnder(manifestPath, filepath.Join(tmpDir, "libs", "react")); err != nil {
+		t.Fatalf("removeUnder failed: %v", err)
+	}
+
+	if _, ok := manifest.Files["libs/react/index.js"]; ok {
+		t.Error("expected react entry to be removed")
+	}
+	if _, ok := manifest.Files["libs/react-dom/index.js"]; !ok {
+		t.Error("expected react-dom entry to remain")
+	}
+}
+
+func TestUpdateManifestLockfileOff(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	writeTestFile(t, configPath, "destination: ./libs/{library_name}\nlockfile: false\nlibraries: {}\n")
+
+	downloads := []downloadedFile{{
+		task:         DownloadTask{LibraryName: "jquery", DestPath: filepath.Join(tmpDir, "libs", "jquery", "jquery.js")},
+		downloadedAt: time.Now(),
+	}}
+	if err := updateManifest(configPath, downloads); err != nil {
+		t.Fatalf("updateManifest failed: %v", err)
+	}
+	if _, err := os.Stat(manifestPathForConfig(configPath)); !os.IsNotExist(err) {
+		t.Errorf("expected no lockfile with lockfile: false, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// libraryMetadataFile is a sidecar next to the frontend config holding
+// package metadata snapshots, stored as <config>.meta.yaml
+type libraryMetadataFile struct {
+	Libraries map[string]libraryMetadata `yaml:"libraries"`
+}
+
+// libraryMetadata is a metadata snapshot for a single library
+type libraryMetadata struct {
+	frontend_mgr.PackageMetadata `yaml:",inline"`
+	FetchedAt                    time.Time `yaml:"fetched_at"`
+}
+
+// metadataPathForConfig returns the metadata sidecar path for a frontend config file
+func metadataPathForConfig(configPath string) string {
+	return strings.TrimSuffix(configPath, filepath.Ext(configPath)) + ".meta.yaml"
+}
+
+// loadLibraryMetadata reads the metadata sidecar, returning an empty one if it does not exist
+func loadLibraryMetadata(configPath string) (*libraryMetadataFile, error) {
+	metadata := &libraryMetadataFile{Libraries: make(map[string]libraryMetadata)}
+
+	data, err := os.ReadFile(metadataPathForConfig(configPath))
+	if os.IsNotExist(err) {
+		return metadata, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata file: %w", err)
+	}
+	if metadata.Libraries == nil {
+		metadata.Libraries = make(map[string]libraryMetadata)
+	}
+
+	return metadata, nil
+}
+
+// saveLibraryMetadata writes the metadata sidecar
+func saveLibraryMetadata(configPath string, metadata *libraryMetadataFile) error {
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	if err := os.WriteFile(metadataPathForConfig(configPath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata file: %w", err)
+	}
+
+	return nil
+}
+
+// recordLibraryMetadata fetches package metadata and stores it in the sidecar
+func recordLibraryMetadata(configPath, packageName string, cdn frontend_config.CDN) (*libraryMetadata, error) {
+	pkgMeta, err := frontend_mgr.FetchPackageMetadata(packageName, string(cdn))
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := loadLibraryMetadata(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := libraryMetadata{PackageMetadata: *pkgMeta, FetchedAt: time.Now().UTC()}
+	metadata.Libraries[packageName] = entry
+
+	if err := saveLibraryMetadata(configPath, metadata); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// forgetLibraryMetadata removes a library from the sidecar, if present
+func forgetLibraryMetadata(configPath, packageName string) error {
+	if _, err := os.Stat(metadataPathForConfig(configPath)); os.IsNotExist(err) {
+		return nil
+	}
+
+	metadata, err := loadLibraryMetadata(configPath)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := metadata.Libraries[packageName]; !ok {
+		return nil
+	}
+	delete(metadata.Libraries, packageName)
+
+	return saveLibraryMetadata(configPath, metadata)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestMetadataPathForConfig(t *testing.T) {
+	if got := metadataPathForConfig("smartfrontend.yaml"); got != "smartfrontend.meta.yaml" {
+		t.Errorf("expected smartfrontend.meta.yaml, got %s", got)
+	}
+}
+
+func TestLibraryMetadataRoundTrip(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+
+	// Missing sidecar loads as empty
+	metadata, err := loadLibraryMetadata(configPath)
+	if err != nil {
+		t.Fatalf("loadLibraryMetadata failed: %v", err)
+	}
+	if len(metadata.Libraries) != 0 {
+		t.Errorf("expected no libraries, got %d", len(metadata.Libraries))
+	}
+
+	fetchedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	metadata.Libraries["jquery"] = libraryMetadata{
+		PackageMetadata: frontend_mgr.PackageMetadata{
+			Description: "JavaScript library for DOM operations",
+			Homepage:    "https://jquery.com",
+			License:     "MIT",
+		},
+		FetchedAt: fetchedAt,
+	}
+	if err := saveLibraryMetadata(configPath, metadata); err != nil {
+		t.Fatalf("saveLibraryMetadata failed: %v", err)
+	}
+
+	// Metadata fields are inlined next to fetched_at
+	data, err := os.ReadFile(metadataPathForConfig(configPath))
+	if err != nil {
+		t.Fatalf("failed to read sidecar: %v", err)
+	}
+	if !strings.Contains(string(data), "license: MIT") {
+		t.Errorf("expected inlined license field, got:\n%s", data)
+	}
+
+	loaded, err := loadLibraryMetadata(configPath)
+	if err != nil {
+		t.Fatalf("loadLibraryMetadata failed: %v", err)
+	}
+	entry := loaded.Libraries["jquery"]
+	if entry.License != "MIT" || entry.Homepage != "https://jquery.com" || !entry.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	if err := forgetLibraryMetadata(configPath, "jquery"); err != nil {
+		t.Fatalf("forgetLibraryMetadata failed: %v", err)
+	}
+	loaded, err = loadLibraryMetadata(configPath)
+	if err != nil {
+		t.Fatalf("loadLibraryMetadata failed: %v", err)
+	}
+	if _, ok := loaded.Libraries["jquery"]; ok {
+		t.Error("expected jquery metadata to be removed")
+	}
+}
+
+func TestForgetLibraryMetadataWithoutSidecar(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+
+	if err := forgetLibraryMetadata(configPath, "jquery"); err != nil {
+		t.Fatalf("forgetLibraryMetadata failed: %v", err)
+	}
+	if _, err := os.Stat(metadataPathForConfig(configPath)); !os.IsNotExist(err) {
+		t.Error("expected no sidecar to be created")
+	}
+}
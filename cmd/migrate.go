@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+const (
+	// migrateRemove deletes previous version folders
+	migrateRemove = "remove"
+
+	// migrateArchive moves previous version folders into archiveDirName
+	migrateArchive = "archive"
+
+	// archiveDirName is the folder next to the config that holds archived versions
+	archiveDirName = ".smfaman-archive"
+)
+
+// versionMigration records a previous version folder replaced after an upgrade
+type versionMigration struct {
+	Library     string    `json:"library"`
+	FromVersion string    `json:"from_version"`
+	ToVersion   string    `json:"to_version"`
+	Path        string    `json:"path"`   // Previous folder, relative to the manifest
+	Action      string    `json:"action"` // "removed" or "archived"
+	ArchivePath string    `json:"archive_path,omitempty"`
+	MigratedAt  time.Time `json:"migrated_at"`
+}
+
+// staleVersionFolder is a destination folder left behind by a previous version
+type staleVersionFolder struct {
+	Library     string
+	FromVersion string
+	ToVersion   string
+	Path        string
+}
+
+// isValidMigrateMode checks if a --migrate value is supported
+func isValidMigrateMode(mode string) bool {
+	return mode == "" || mode == migrateRemove || mode == migrateArchive
+}
+
+// findStaleVersionFolders returns the folders of previously synced versions
+// that differ from the current destination (i.e., versioned destinations).
+// Folders overlapping any current destination are never returned.
+func findStaleVersionFolders(configPath string, config *frontend_config.FrontendConfig) ([]staleVersionFolder, error) {
+	manifest, err := loadManifest(manifestPathForConfig(configPath))
+	if err != nil {
+		return nil, err
+	}
+
+	// Protect every configured destination, not just the selected libraries
+	fullConfig, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	current, err := fullConfig.GetLibraryDestinations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get library destinations: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var stale []staleVersionFolder
+	for _, entry := range manifest.Files {
+		libConfig, ok := config.Libraries[entry.Library]
+		if !ok || entry.Version == libConfig.Version || seen[entry.Library+"@"+entry.Version] {
+			continue
+		}
+		seen[entry.Library+"@"+entry.Version] = true
+
+		oldConfig := libConfig
+		oldConfig.Version = entry.Version
+		oldPath, err := config.GetLibraryDestination(entry.Library, oldConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get destination for %s: %w", entry.Library, err)
+		}
+
+		if overlapsAny(oldPath, current) {
+			continue
+		}
+
+		stale = append(stale, staleVersionFolder{
+			Library:     entry.Library,
+			FromVersion: entry.Version,
+			ToVersion:   libConfig.Version,
+			Path:        oldPath,
+		})
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Library != stale[j].Library {
+			return stale[i].Library < stale[j].Library
+		}
+		return stale[i].FromVersion < stale[j].FromVersion
+	})
+
+	return stale, nil
+}
+
+// overlapsAny reports whether path equals, contains, or is inside any of the destinations
+func overlapsAny(path string, destinations map[string]string) bool {
+	for _, dest := range destinations {
+		if pathWithin(path, dest) || pathWithin(dest, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathWithin reports whether child is parent or inside it
+func pathWithin(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// migrateVersionFolders removes or archives the folders of previous versions
+// after a successful sync, recording each migration in the manifest
+func migrateVersionFolders(configPath string, config *frontend_config.FrontendConfig, mode string, dryRun bool, out io.Writer) error {
+	if mode == "" {
+		return nil
+	}
+
+	stale, err := findStaleVersionFolders(configPath, config)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	manifestPath := manifestPathForConfig(configPath)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	for _, folder := range stale {
+		name := fmt.Sprintf("%s@%s", folder.Library, folder.FromVersion)
+		archivePath := filepath.Join(filepath.Dir(manifestPath), archiveDirName, name)
+
+		_, statErr := os.Stat(folder.Path)
+		exists := statErr == nil
+
+		if dryRun {
+			switch {
+			case !exists:
+				fmt.Fprintf(out, "  • %s: %s already gone (would update lockfile)\n", name, folder.Path)
+			case mode == migrateArchive:
+				fmt.Fprintf(out, "  • %s: would archive %s → %s\n", name, folder.Path, archivePath)
+			default:
+				fmt.Fprintf(out, "  • %s: would remove %s\n", name, folder.Path)
+			}
+			continue
+		}
+
+		migration := versionMigration{
+			Library:     folder.Library,
+			FromVersion: folder.FromVersion,
+			ToVersion:   folder.ToVersion,
+			Action:      "removed",
+			MigratedAt:  time.Now().UTC(),
+		}
+		if migration.Path, err = manifestKey(manifestPath, folder.Path); err != nil {
+			return err
+		}
+
+		if exists {
+			if mode == migrateArchive {
+				if _, err := os.Stat(archivePath); err == nil {
+					return fmt.Errorf("archive %s already exists", archivePath)
+				}
+				if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+					return fmt.Errorf("failed to create archive directory: %w", err)
+				}
+				if err := os.Rename(folder.Path, archivePath); err != nil {
+					return fmt.Errorf("failed to archive %s: %w", folder.Path, err)
+				}
+				migration.Action = "archived"
+				if migration.ArchivePath, err = manifestKey(manifestPath, archivePath); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "✓ Archived %s: %s → %s\n", name, folder.Path, archivePath)
+			} else {
+				if err := os.RemoveAll(folder.Path); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", folder.Path, err)
+				}
+				fmt.Fprintf(out, "✓ Removed %s: %s\n", name, folder.Path)
+			}
+		}
+
+		if err := manifest.removeUnder(manifestPath, folder.Path); err != nil {
+			return err
+		}
+		manifest.Migrations = append(manifest.Migrations, migration)
+	}
+
+	if dryRun {
+		return nil
+	}
+	return saveManifest(manifestPath, manifest)
+}
+
+// syncUpgradedLibraries downloads the new versions of upgraded libraries
+// before their previous folders are migrated (overridable in tests)
+var syncUpgradedLibraries = func(config *frontend_config.FrontendConfig, out io.Writer) error {
+	tasks, err := buildDownloadTasks(config)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	if err := applyStoredValidators(FrontendConfig, tasks); err != nil {
+		return err
+	}
+	return executeDownloadTasks(FrontendConfig, tasks, out)
+}
+
+// syncAndMigrate syncs the upgraded libraries, then removes or archives the
+// folders of their previous versions (upgrade --migrate)
+func syncAndMigrate(configPath string, libraries []string, mode string, out io.Writer) error {
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	upgraded := *config
+	upgraded.Libraries = make(map[string]frontend_config.LibraryConfig)
+	for _, name := range libraries {
+		upgraded.Libraries[name] = config.Libraries[name]
+	}
+
+	fmt.Fprintf(out, "\nSyncing %d upgraded %s...\n", len(libraries), pluralize(len(libraries), "library", "libraries"))
+	if err := syncUpgradedLibraries(&upgraded, out); err != nil {
+		return fmt.Errorf("upgraded the config but sync failed, previous versions were kept: %w", err)
+	}
+
+	// Previous version folders are only touched once the new version is in place
+	return migrateVersionFolders(configPath, &upgraded, mode, false, out)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// setupVersionedProject writes a config with a versioned destination and a
+// manifest recording files from jquery 3.6.0 and 3.7.1
+func setupVersionedProject(t *testing.T, destination string) (string, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	writeTestFile(t, configPath, "destination: '"+destination+"'\nlibraries:\n  jquery:\n    version: 3.7.1\n")
+
+	var downloads []downloadedFile
+	for _, version := range []string{"3.6.0", "3.7.1"} {
+		destPath := filepath.Join(tmpDir, "libs", "jquery", version, "jquery.min.js")
+		writeTestFile(t, destPath, version)
+		downloads = append(downloads, downloadedFile{
+			task:         DownloadTask{LibraryName: "jquery", Version: version, FilePath: "jquery.min.js", DestPath: destPath},
+			downloadedAt: time.Now(),
+		})
+	}
+	if err := updateManifest(configPath, downloads); err != nil {
+		t.Fatalf("updateManifest failed: %v", err)
+	}
+
+	return tmpDir, configPath
+}
+
+func TestMigrateVersionFoldersRemove(t *testing.T) {
+	tmpDir, configPath := setupVersionedProject(t, filepath.Join("{library_name}", "{version}"))
+	t.Chdir(filepath.Join(tmpDir, "libs"))
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+
+	// Dry run leaves everything in place
+	if err := migrateVersionFolders(configPath, config, migrateRemove, true, io.Discard); err != nil {
+		t.Fatalf("migrateVersionFolders failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "libs", "jquery", "3.6.0")); err != nil {
+		t.Fatal("expected dry run to keep the old folder")
+	}
+
+	if err := migrateVersionFolders(configPath, config, migrateRemove, false, io.Discard); err != nil {
+		t.Fatalf("migrateVersionFolders failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "libs", "jquery", "3.6.0")); !os.IsNotExist(err) {
+		t.Error("expected old version folder to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "libs", "jquery", "3.7.1", "jquery.min.js")); err != nil {
+		t.Error("expected current version folder to be kept")
+	}
+
+	manifest, err := loadManifest(manifestPathForConfig(configPath))
+	if err != nil {
+		t.Fatalf("loadManifest failed: %v", err)
+	}
+	if _, ok := manifest.Files["libs/jquery/3.6.0/jquery.min.js"]; ok {
+		t.Error("expected old version entries to be pruned")
+	}
+	if _, ok := manifest.Files["libs/jquery/3.7.1/jquery.min.js"]; !ok {
+		t.Error("expected current version entries to be kept")
+	}
+	if len(manifest.Migrations) != 1 {
+		t.Fatalf("expected 1 migration, got %d", len(manifest.Migrations))
+	}
+	m := manifest.Migrations[0]
+	if m.FromVersion != "3.6.0" || m.ToVersion != "3.7.1" || m.Action != "removed" || m.Path != "libs/jquery/3.6.0" {
+		t.Errorf("unexpected migration: %+v", m)
+	}
+
+	// Nothing left to migrate
+	stale, err := findStaleVersionFolders(configPath, config)
+	if err != nil || len(stale) != 0 {
+		t.Errorf("expected no stale folders, got %v (err %v)", stale, err)
+	}
+}
+
+func TestMigrateVersionFoldersArchive(t *testing.T) {
+	tmpDir, configPath := setupVersionedProject(t, filepath.Join("{library_name}", "{version}"))
+	t.Chdir(filepath.Join(tmpDir, "libs"))
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+
+	if err := migrateVersionFolders(configPath, config, migrateArchive, false, io.Discard); err != nil {
+		t.Fatalf("migrateVersionFolders failed: %v", err)
+	}
+
+	archived := filepath.Join(tmpDir, archiveDirName, "jquery@3.6.0", "jquery.min.js")
+	if _, err := os.Stat(archived); err != nil {
+		t.Errorf("expected old version to be archived: %v", err)
+	}
+
+	manifest, err := loadManifest(manifestPathForConfig(configPath))
+	if err != nil {
+		t.Fatalf("loadManifest failed: %v", err)
+	}
+	if len(manifest.Migrations) != 1 || manifest.Migrations[0].Action != "archived" ||
+		manifest.Migrations[0].ArchivePath != archiveDirName+"/jquery@3.6.0" {
+		t.Errorf("unexpected migrations: %+v", manifest.Migrations)
+	}
+}
+
+func TestFindStaleVersionFoldersIgnoresUnversionedDestinations(t *testing.T) {
+	tmpDir, configPath := setupVersionedProject(t, "{library_name}")
+	t.Chdir(filepath.Join(tmpDir, "libs"))
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+
+	stale, err := findStaleVersionFolders(configPath, config)
+	if err != nil {
+		t.Fatalf("findStaleVersionFolders failed: %v", err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("expected old folders inside the current destination to be protected, got %v", stale)
+	}
+}
+
+func TestUpgradeMigrate(t *testing.T) {
+	tmpDir, configPath := setupVersionedProject(t, filepath.Join("{library_name}", "{version}"))
+	t.Chdir(filepath.Join(tmpDir, "libs"))
+	writeTestFile(t, configPath, "destination: '"+filepath.Join("{library_name}", "{version}")+"'\nlibraries:\n  jquery:\n    version: 3.6.0\n")
+
+	oldConfig, origFetch, origSync := FrontendConfig, fetchUpgradeVersions, syncUpgradedLibraries
+	FrontendConfig = configPath
+	upgradeMigrate = migrateArchive
+	t.Cleanup(func() {
+		FrontendConfig, fetchUpgradeVersions, syncUpgradedLibraries = oldConfig, origFetch, origSync
+		upgradeMigrate = ""
+	})
+	fetchUpgradeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		return []string{"3.7.1", "3.6.0"}, "3.7.1", nil
+	}
+	var synced []string
+	syncUpgradedLibraries = func(config *frontend_config.FrontendConfig, out io.Writer) error {
+		for name, lib := range config.Libraries {
+			synced = append(synced, name+"@"+lib.Version)
+		}
+		return nil
+	}
+
+	if err := upgradeSpecificLibrary("jquery"); err != nil {
+		t.Fatalf("upgradeSpecificLibrary failed: %v", err)
+	}
+	if len(synced) != 1 || synced[0] != "jquery@3.7.1" {
+		t.Errorf("synced %v, want the new jquery version", synced)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, archiveDirName, "jquery@3.6.0", "jquery.min.js")); err != nil {
+		t.Errorf("expected the previous version to be archived: %v", err)
+	}
+
+	// A failed sync keeps the previous version folders
+	syncUpgradedLibraries = func(config *frontend_config.FrontendConfig, out io.Writer) error {
+		return errors.New("offline")
+	}
+	if err := syncAndMigrate(configPath, []string{"jquery"}, migrateRemove, io.Discard); err == nil {
+		t.Error("expected the sync error")
+	}
+}
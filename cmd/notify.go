@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Notification setting keys
+const (
+	settingNotifyWebhook = "notify_webhook"
+	settingNotifyCommand = "notify_command"
+	settingNotifyDesktop = "notify_desktop"
+)
+
+// notifyTimeout bounds each notification so a slow receiver can't hold up
+// the command
+const notifyTimeout = 10 * time.Second
+
+// notification is the JSON payload sent after a sync or upgrade changed
+// something
+type notification struct {
+	Event   string           `json:"event"` // sync or upgrade
+	Text    string           `json:"text"`  // One-line summary, shown by Slack and similar webhooks
+	Project string           `json:"project,omitempty"`
+	Config  string           `json:"config"`
+	Time    time.Time        `json:"time"`
+	Success bool             `json:"success"`
+	Changes []notifiedChange `json:"changes"`
+	Files   int              `json:"files,omitempty"` // Files written by a sync
+	Bytes   int64            `json:"bytes,omitempty"`
+	Failed  []syncFailure    `json:"failed,omitempty"`
+}
+
+// notifiedChange is a library a sync wrote files for, or one an upgrade
+// moved to another version
+type notifiedChange struct {
+	Library string `json:"library"`
+	From    string `json:"from,omitempty"` // Previous version, for upgrades
+	Version string `json:"version"`
+	CDN     string `json:"cdn,omitempty"`
+	Files   int    `json:"files,omitempty"`
+}
+
+// notificationsEnabled reports whether any notification is configured
+func notificationsEnabled() bool {
+	return viper.GetString(settingNotifyWebhook) != "" || viper.GetString(settingNotifyCommand) != "" || viper.GetBool(settingNotifyDesktop)
+}
+
+// syncNotification describes the libraries a sync wrote files for. It
+// returns false when the sync neither changed nor failed anything.
+func syncNotification(configPath string, summary *syncSummary) (notification, bool) {
+	n := notification{
+		Event:   "sync",
+		Config:  configPath,
+		Success: len(summary.Failed) == 0,
+		Changes: []notifiedChange{},
+		Failed:  summary.Failed,
+	}
+	for _, stats := range summary.Libraries {
+		if written := stats.Files - stats.UnchangedFiles; written > 0 {
+			n.Changes = append(n.Changes, notifiedChange{Library: stats.Library, Version: stats.Version, Files: written})
+			n.Files += written
+		}
+	}
+	for _, d := range summary.downloaded {
+		if !d.result.Unchanged {
+			n.Bytes += d.result.Bytes
+		}
+	}
+	if len(n.Changes) == 0 && n.Success {
+		return n, false
+	}
+
+	var names []string
+	for _, c := range n.Changes {
+		names = append(names, c.Library+"@"+c.Version)
+	}
+	n.Text = "smfaman sync: no files written"
+	if len(names) > 0 {
+		n.Text = fmt.Sprintf("smfaman sync: wrote %d %s (%s) for %s", n.Files, pluralize(n.Files, "file", "files"), formatBytes(n.Bytes), strings.Join(names, ", "))
+	}
+	if !n.Success {
+		n.Text += fmt.Sprintf("; %d %s failed", len(n.Failed), pluralize(len(n.Failed), "file", "files"))
+	}
+	return n, true
+}
+
+// upgradeNotification describes the version changes an upgrade wrote
+func upgradeNotification(configPath, project string, changes []notifiedChange) notification {
+	var moves []string
+	for _, c := range changes {
+		moves = append(moves, fmt.Sprintf("%s %s → %s", c.Library, c.From, c.Version))
+	}
+	return notification{
+		Event:   "upgrade",
+		Text:    "smfaman upgrade: " + strings.Join(moves, ", "),
+		Project: project,
+		Config:  configPath,
+		Success: true,
+		Changes: changes,
+	}
+}
+
+// sendNotification delivers n to every configured notification. Failures
+// are warnings: the sync or upgrade itself has already succeeded.
+func sendNotification(n notification) {
+	if !notificationsEnabled() {
+		return
+	}
+	n.Time = time.Now().UTC()
+	if n.Project != "" {
+		n.Text += " in " + n.Project
+	}
+	payload, err := json.Marshal(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to encode notification: %v\n", err)
+		return
+	}
+
+	if webhook := viper.GetString(settingNotifyWebhook); webhook != "" {
+		if err := postWebhook(webhook, payload); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", settingNotifyWebhook, err)
+		}
+	}
+	if command := viper.GetString(settingNotifyCommand); command != "" {
+		if err := runNotifyCommand(command, n.Event, payload); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", settingNotifyCommand, err)
+		}
+	}
+	if viper.GetBool(settingNotifyDesktop) {
+		if err := showDesktopNotification("smfaman "+n.Event, strings.TrimPrefix(n.Text, "smfaman "+n.Event+": ")); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", settingNotifyDesktop, err)
+		}
+	}
+}
+
+// postWebhook posts the payload as JSON to a webhook URL (overridable in tests)
+var postWebhook = func(webhook string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// The URL may carry a secret token, so only the host is reported
+		return fmt.Errorf("failed to post to %s", req.URL.Host)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s answered with status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+// runNotifyCommand runs a shell command with the payload on its standard
+// input and the event in SMFAMAN_EVENT
+func runNotifyCommand(command, event string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "SMFAMAN_EVENT="+event)
+	return cmd.Run()
+}
+
+// showDesktopNotification shows a desktop notification with the tools the
+// system ships (overridable in tests)
+var showDesktopNotification = func(title, text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("notify-send", title, text)
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %s with title %s", appleScriptString(text), appleScriptString(title)))
+	case "windows":
+		script := `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, $env:SMFAMAN_TITLE, $env:SMFAMAN_TEXT, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+		cmd.Env = append(os.Environ(), "SMFAMAN_TITLE="+title, "SMFAMAN_TEXT="+text)
+		// The balloon stays up for a few seconds; smfaman doesn't wait for it
+		return cmd.Start()
+	default:
+		return fmt.Errorf("desktop notifications aren't supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %v %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// validNotifyWebhook reports whether value is an http or https URL
+func validNotifyWebhook(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
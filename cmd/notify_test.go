@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSyncNotification(t *testing.T) {
+	summary := newSyncSummary()
+	summary.record(DownloadTask{LibraryName: "jquery", Version: "3.7.1"}, fileDownloadResult{Bytes: 2048})
+	summary.record(DownloadTask{LibraryName: "jquery", Version: "3.7.1"}, fileDownloadResult{Bytes: 100, Unchanged: true})
+	summary.record(DownloadTask{LibraryName: "htmx.org", Version: "2.0.0"}, fileDownloadResult{Bytes: 50, Unchanged: true})
+
+	n, changed := syncNotification("smartfrontend.yaml", summary)
+	if !changed {
+		t.Fatal("expected a notification for written files")
+	}
+	if len(n.Changes) != 1 || n.Changes[0].Library != "jquery" || n.Changes[0].Files != 1 || n.Files != 1 || n.Bytes != 2048 {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+	if n.Text != "smfaman sync: wrote 1 file (2.00 KB) for jquery@3.7.1" {
+		t.Errorf("Text = %q", n.Text)
+	}
+
+	unchanged := newSyncSummary()
+	unchanged.record(DownloadTask{LibraryName: "htmx.org", Version: "2.0.0"}, fileDownloadResult{Unchanged: true})
+	if _, changed := syncNotification("smartfrontend.yaml", unchanged); changed {
+		t.Error("expected no notification when nothing changed")
+	}
+
+	unchanged.recordFailure(DownloadTask{LibraryName: "htmx.org", Version: "2.0.0", FilePath: "dist/htmx.js"}, errors.New("timeout"))
+	n, changed = syncNotification("smartfrontend.yaml", unchanged)
+	if !changed || n.Success || !strings.HasSuffix(n.Text, "; 1 file failed") {
+		t.Errorf("expected a failure notification, got %+v", n)
+	}
+}
+
+func TestSendNotification(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("notify_command test uses a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "payload.json")
+	viper.Set(settingNotifyWebhook, "https://hooks.example.com/T000/B000")
+	viper.Set(settingNotifyCommand, `cat > "$OUT"; echo "$SMFAMAN_EVENT" >> "$OUT.event"`)
+	t.Cleanup(func() {
+		viper.Set(settingNotifyWebhook, "")
+		viper.Set(settingNotifyCommand, "")
+	})
+	t.Setenv("OUT", out)
+
+	var posted []byte
+	orig := postWebhook
+	postWebhook = func(webhook string, payload []byte) error {
+		posted = payload
+		return nil
+	}
+	t.Cleanup(func() { postWebhook = orig })
+
+	sendNotification(upgradeNotification("smartfrontend.yaml", "shop", []notifiedChange{
+		{Library: "jquery", From: "3.6.0", Version: "3.7.1", CDN: "unpkg"},
+	}))
+
+	var n notification
+	if err := json.Unmarshal(posted, &n); err != nil {
+		t.Fatalf("webhook payload: %v", err)
+	}
+	if n.Event != "upgrade" || n.Text != "smfaman upgrade: jquery 3.6.0 → 3.7.1 in shop" || n.Time.IsZero() || len(n.Changes) != 1 {
+		t.Errorf("unexpected payload: %s", posted)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil || string(data) != string(posted) {
+		t.Errorf("command got %q (%v), want the webhook payload", data, err)
+	}
+	if event, _ := os.ReadFile(out + ".event"); strings.TrimSpace(string(event)) != "upgrade" {
+		t.Errorf("SMFAMAN_EVENT = %q", event)
+	}
+}
+
+func TestValidNotifyWebhook(t *testing.T) {
+	for value, want := range map[string]bool{
+		"https://hooks.slack.com/services/T/B/X": true,
+		"http://localhost:8080/hook":             true,
+		"hooks.slack.com/services":               false,
+		"ftp://example.com":                      false,
+	} {
+		if got := validNotifyWebhook(value); got != want {
+			t.Errorf("validNotifyWebhook(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
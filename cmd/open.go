@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var (
+	openHomepage bool
+	openFolder   bool
+	openCDN      bool
+	openPrint    bool
+)
+
+// openCmd represents the open command
+var openCmd = &cobra.Command{
+	Use:   "open <library>",
+	Short: "Open a library's folder, homepage or CDN page",
+	Long: `Open a configured library's destination folder in the file manager, its
+homepage in the browser, or its page on the CDN it is fetched from.
+
+The folder is the default. The homepage comes from the metadata recorded
+by 'smfaman add' (<config>.meta.yaml) and is looked up on the CDN
+otherwise; packages without a homepage open their repository. Only http
+and https URLs are opened. The CDN page shows the version recorded in the
+lockfile, or the configured one when it is exact.
+
+Folders and pages are opened with xdg-open on Linux and BSD, open on
+macOS and the default handler on Windows. Use --print to only print the
+path or URL, e.g. over SSH or in scripts.
+
+Examples:
+  smfaman open jquery                # Open jquery's destination folder
+  smfaman open htmx.org --homepage   # Open the htmx homepage
+  smfaman open @popperjs/core --cdn  # Browse the vendored version on its CDN
+  smfaman open react --cdn --print   # Print the CDN page URL`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runOpen(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().BoolVar(&openFolder, "folder", false, "Open the library's destination folder (default)")
+	openCmd.Flags().BoolVar(&openHomepage, "homepage", false, "Open the library's homepage")
+	openCmd.Flags().BoolVar(&openCDN, "cdn", false, "Open the library's page on its CDN")
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "Print the path or URL instead of opening it")
+	openCmd.MarkFlagsMutuallyExclusive("folder", "homepage", "cdn")
+}
+
+// runOpen opens the folder, homepage or CDN page of a configured library
+func runOpen(libName string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	libConfig, ok := config.Libraries[libName]
+	if !ok {
+		if libConfig, ok = config.DevLibraries[libName]; !ok {
+			return notFoundError(fmt.Errorf("library '%s' not found in config", libName))
+		}
+	}
+
+	var target string
+	switch {
+	case openHomepage:
+		target, err = libraryHomepage(config, libName, libConfig)
+	case openCDN:
+		target = libraryCDNPage(config, libName, libConfig)
+	default:
+		target, err = libraryFolder(config, libName, libConfig)
+	}
+	if err != nil {
+		return err
+	}
+
+	if openPrint {
+		fmt.Println(target)
+		return nil
+	}
+	if err := openWithSystem(target); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	fmt.Printf("Opened %s\n", target)
+	return nil
+}
+
+// libraryFolder returns the destination folder of a library, which sync
+// must have created
+func libraryFolder(config *frontend_config.FrontendConfig, libName string, libConfig frontend_config.LibraryConfig) (string, error) {
+	dir, err := config.GetLibraryDestination(libName, libConfig)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", notFoundError(fmt.Errorf("%s doesn't exist yet, run 'smfaman sync' first", dir))
+	}
+	return dir, nil
+}
+
+// fetchOpenMetadata looks up package metadata on a CDN (overridable in tests)
+var fetchOpenMetadata = frontend_mgr.FetchPackageMetadata
+
+// libraryHomepage returns the homepage of a library, or its repository when
+// it has none, preferring the recorded metadata over a lookup
+func libraryHomepage(config *frontend_config.FrontendConfig, libName string, libConfig frontend_config.LibraryConfig) (string, error) {
+	var meta *frontend_mgr.PackageMetadata
+	if recorded, err := loadLibraryMetadata(FrontendConfig); err == nil {
+		if entry, ok := recorded.Libraries[libName]; ok && (entry.Homepage != "" || entry.Repository != "") {
+			meta = &entry.PackageMetadata
+		}
+	}
+	if meta == nil {
+		var err error
+		if meta, err = fetchOpenMetadata(libName, string(libraryCDN(config, libConfig))); err != nil {
+			return "", err
+		}
+	}
+
+	// The URLs come from package metadata, so only web pages are opened
+	for _, page := range []string{meta.Homepage, meta.Repository} {
+		if page == "" {
+			continue
+		}
+		if !isWebURL(page) {
+			return "", fmt.Errorf("refusing to open %q for %s: not an http or https URL", page, libName)
+		}
+		return page, nil
+	}
+	return "", notFoundError(fmt.Errorf("%s has no homepage or repository", libName))
+}
+
+// isWebURL reports whether value is an http or https URL with a host
+func isWebURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// libraryCDNPage returns the page of the vendored version of a library on
+// its CDN
+func libraryCDNPage(config *frontend_config.FrontendConfig, libName string, libConfig frontend_config.LibraryConfig) string {
+	version := ""
+	if frontend_mgr.IsExactVersion(libConfig.Version) {
+		version = libConfig.Version
+	} else if manifest, err := loadManifest(manifestPathForConfig(FrontendConfig)); err == nil {
+		version = lockedVersion(manifest, libName, libConfig.Version)
+	}
+	return frontend_mgr.PackagePageURL(string(libraryCDN(config, libConfig)), libName, version)
+}
+
+// openWithSystem opens a folder or URL with the system's default handler
+// without waiting for it (overridable in tests)
+var openWithSystem = func(target string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	return cmd.Start()
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestRunOpen(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "smartfrontend.yaml")
+	data, _ := yaml.Marshal(&frontend_config.FrontendConfig{
+		Destination: filepath.Join(dir, "frontend", "{library_name}"),
+		CDN:         frontend_config.CDNUnpkg,
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":   {Version: "3.7.1", CDN: frontend_config.CDNCdnjs},
+			"htmx.org": {Version: "^2.0.0"},
+			"evil":     {Version: "1.0.0"},
+		},
+	})
+	os.WriteFile(configPath, data, 0644)
+
+	oldConfig, origOpen, origFetch := FrontendConfig, openWithSystem, fetchOpenMetadata
+	FrontendConfig = configPath
+	var opened []string
+	openWithSystem = func(target string) error {
+		opened = append(opened, target)
+		return nil
+	}
+	fetchOpenMetadata = func(packageName, cdn string) (*frontend_mgr.PackageMetadata, error) {
+		if packageName == "htmx.org" {
+			return &frontend_mgr.PackageMetadata{Repository: "https://github.com/bigskysoftware/htmx"}, nil
+		}
+		if packageName == "evil" {
+			return &frontend_mgr.PackageMetadata{Homepage: "file:///etc/passwd", Repository: "https://example.com"}, nil
+		}
+		return nil, errors.New("unexpected lookup")
+	}
+	t.Cleanup(func() {
+		FrontendConfig, openWithSystem, fetchOpenMetadata = oldConfig, origOpen, origFetch
+		openHomepage, openCDN = false, false
+	})
+
+	// The folder is only opened once sync created it
+	if err := runOpen("jquery"); err == nil {
+		t.Error("expected an error for a folder sync hasn't created")
+	}
+	folder := filepath.Join(dir, "frontend", "jquery")
+	os.MkdirAll(folder, 0755)
+	if err := runOpen("jquery"); err != nil || len(opened) != 1 || opened[0] != folder {
+		t.Fatalf("runOpen() error = %v, opened %v", err, opened)
+	}
+
+	// The recorded homepage wins; without one, the repository is looked up
+	saveLibraryMetadata(configPath, &libraryMetadataFile{Libraries: map[string]libraryMetadata{
+		"jquery": {PackageMetadata: frontend_mgr.PackageMetadata{Homepage: "https://jquery.com"}},
+	}})
+	openHomepage = true
+	if err := runOpen("jquery"); err != nil || opened[1] != "https://jquery.com" {
+		t.Errorf("homepage: error %v, opened %v", err, opened)
+	}
+	if err := runOpen("htmx.org"); err != nil || opened[2] != "https://github.com/bigskysoftware/htmx" {
+		t.Errorf("repository fallback: error %v, opened %v", err, opened)
+	}
+
+	// A range opens the version recorded in the lockfile
+	openHomepage, openCDN = false, true
+	saveManifest(manifestPathForConfig(configPath), &fileManifest{FormatVersion: manifestFormatVersion, Files: map[string]manifestEntry{
+		"frontend/htmx.org/htmx.min.js": {Library: "htmx.org", Version: "2.0.4", CDN: "unpkg"},
+	}})
+	if err := runOpen("htmx.org"); err != nil || opened[3] != "https://unpkg.com/browse/htmx.org@2.0.4/" {
+		t.Errorf("cdn page: error %v, opened %v", err, opened)
+	}
+	if err := runOpen("jquery"); err != nil || opened[4] != "https://cdnjs.com/libraries/jquery/3.7.1" {
+		t.Errorf("cdn page: error %v, opened %v", err, opened)
+	}
+
+	// Homepages that aren't web pages are never handed to the system
+	for _, homepage := range []string{"file:///etc/passwd", "javascript:alert(1)", "ms-settings:", "/usr/bin/calc", "https://"} {
+		if isWebURL(homepage) {
+			t.Errorf("isWebURL(%q) = true", homepage)
+		}
+	}
+	openHomepage, openCDN = true, false
+	if err := runOpen("evil"); err == nil || len(opened) != 5 {
+		t.Errorf("a file: homepage should be refused, error %v, opened %v", err, opened)
+	}
+
+	if err := runOpen("lodash"); err == nil {
+		t.Error("expected an error for a library that isn't configured")
+	}
+}
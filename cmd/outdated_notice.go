@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/cache"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// settingOutdatedNotice is the settings key that turns on the notice about
+// libraries with newer versions
+const settingOutdatedNotice = "outdated_notice"
+
+// outdatedNoticeInterval is how long the notice stays quiet once shown
+const outdatedNoticeInterval = 24 * time.Hour
+
+// outdatedNoticeSkipped lists commands that already report versions, or
+// where the notice would only get in the way
+var outdatedNoticeSkipped = map[string]bool{
+	"upgrade":    true,
+	"pkgmgr":     true,
+	"pkgver":     true,
+	"cache":      true,
+	"completion": true,
+	"help":       true,
+	"version":    true,
+}
+
+// printOutdatedNotice prints a one-line notice after cmd when the cached CDN
+// metadata shows libraries in the frontend config with newer versions. It is
+// opt-in (outdated_notice setting), never touches the network and is shown
+// at most once a day per config file.
+func printOutdatedNotice(cmd *cobra.Command) {
+	if !viper.GetBool(settingOutdatedNotice) || !frontend_mgr.CacheManager.Enabled() || !isTerminal(os.Stderr) {
+		return
+	}
+	for c := cmd; c != nil && c != rootCmd; c = c.Parent() {
+		if outdatedNoticeSkipped[c.Name()] {
+			return
+		}
+	}
+	if _, err := os.Stat(FrontendConfig); err != nil {
+		return
+	}
+
+	key := outdatedNoticeKey(FrontendConfig)
+	var shown time.Time
+	if found, _ := frontend_mgr.CacheManager.Get(key, &shown); found {
+		return
+	}
+
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return
+	}
+	if writeOutdatedNotice(os.Stderr, cachedOutdatedLibraries(config)) {
+		frontend_mgr.CacheManager.SetWithTTL(key, time.Now(), outdatedNoticeInterval)
+	}
+}
+
+// outdatedNoticeKey is the cache key recording when the notice was last
+// shown for a config file
+func outdatedNoticeKey(configPath string) string {
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+	return cache.GenerateKey("notice", "outdated", configPath)
+}
+
+// cachedOutdatedLibraries returns the configured libraries whose cached
+// latest version is newer than what their version allows. Libraries with no
+// cached versions are skipped.
+func cachedOutdatedLibraries(config *frontend_config.FrontendConfig) []string {
+	var outdated []string
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+		cdn := libraryCDN(config, libConfig)
+		latest, ok := frontend_mgr.CachedLatestVersion(string(cdn), name)
+		if ok && libraryOutdated(libConfig.Version, latest) {
+			outdated = append(outdated, name)
+		}
+	}
+	return outdated
+}
+
+// writeOutdatedNotice writes the notice for the outdated libraries and
+// reports whether there was anything to say
+func writeOutdatedNotice(w io.Writer, outdated []string) bool {
+	switch len(outdated) {
+	case 0:
+		return false
+	case 1:
+		fmt.Fprintf(w, "\n• %s has a newer version — run 'smfaman upgrade --dry-run' to see it\n", outdated[0])
+	default:
+		fmt.Fprintf(w, "\n• %d libraries have newer versions — run 'smfaman upgrade --dry-run' to see them\n", len(outdated))
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/cache"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestCachedOutdatedLibraries(t *testing.T) {
+	origCache := frontend_mgr.CacheManager
+	t.Cleanup(func() { frontend_mgr.CacheManager = origCache })
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	if err := frontend_mgr.SetCacheEnabled(true); err != nil {
+		t.Fatalf("SetCacheEnabled() error = %v", err)
+	}
+	useSettingsEnv(t, nil)
+
+	cached := map[string]any{
+		cache.GenerateKey("unpkg", "versions", "jquery"):   frontend_mgr.UnpkgPackageResponse{DistTags: map[string]string{"latest": "4.0.0"}},
+		cache.GenerateKey("unpkg", "versions", "htmx.org"): frontend_mgr.UnpkgPackageResponse{DistTags: map[string]string{"latest": "2.0.4"}},
+		cache.GenerateKey("cdnjs", "versions", "lodash"):   frontend_mgr.CdnjsLibraryResponse{Version: "4.17.21"},
+	}
+	for key, data := range cached {
+		if err := frontend_mgr.CacheManager.Set(key, data); err != nil {
+			t.Fatalf("failed to seed cache: %v", err)
+		}
+	}
+
+	config := &frontend_config.FrontendConfig{
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":   {Version: "3.7.1"},
+			"htmx.org": {Version: "^2.0"},
+			"lodash":   {Version: "4.17.20", CDN: frontend_config.CDNCdnjs},
+			"react":    {Version: "17.0.0"},
+		},
+	}
+	got := cachedOutdatedLibraries(config)
+	if strings.Join(got, ",") != "jquery,lodash" {
+		t.Errorf("cachedOutdatedLibraries() = %v, want [jquery lodash]", got)
+	}
+}
+
+func TestWriteOutdatedNotice(t *testing.T) {
+	var buf bytes.Buffer
+	if writeOutdatedNotice(&buf, nil) || buf.Len() != 0 {
+		t.Errorf("expected no notice without outdated libraries, got %q", buf.String())
+	}
+	if !writeOutdatedNotice(&buf, []string{"jquery"}) || !strings.Contains(buf.String(), "jquery has a newer version") {
+		t.Errorf("notice = %q", buf.String())
+	}
+	buf.Reset()
+	writeOutdatedNotice(&buf, []string{"jquery", "lodash", "react"})
+	if !strings.Contains(buf.String(), "3 libraries have newer versions") {
+		t.Errorf("notice = %q", buf.String())
+	}
+}
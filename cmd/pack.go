@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/cobra"
+)
+
+// defaultPackFile is the archive written by pack and read by unpack
+const defaultPackFile = "smfaman-pack.tar.zst"
+
+// packManifestName is the entry describing the archive, written first
+const packManifestName = "smfaman-pack.json"
+
+// packFormatVersion is bumped when the archive layout changes
+const packFormatVersion = 1
+
+var packOutput string
+
+// packManifest describes what a pack archive holds
+type packManifest struct {
+	FormatVersion int               `json:"format_version"`
+	Smfaman       string            `json:"smfaman"`   // Version of smfaman that wrote the archive
+	Config        string            `json:"config"`    // Slash path of the frontend config in the archive
+	Libraries     map[string]string `json:"libraries"` // Library name to configured version
+	Files         int               `json:"files"`
+}
+
+// packCmd represents the pack command
+var packCmd = &cobra.Command{
+	Use:   "pack",
+	Short: "Bundle the config, lockfile and vendored files into one archive",
+	Long: `Bundle the frontend config, its lockfile and metadata file, and every
+vendored file in the library destinations into a single tar.zst archive.
+Restore it elsewhere with 'smfaman unpack', e.g. on an air-gapped machine or
+when promoting a reviewed set of assets between environments.
+
+Paths are stored relative to the current directory, so destinations must be
+inside it. Entries are sorted and carry no timestamps or owners, so packing
+the same files twice gives an identical archive.
+
+Examples:
+  smfaman pack
+  smfaman pack -o release-assets.tar.zst
+  smfaman -f site.yaml pack -o site.tar.zst`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runPack(packOutput); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+	packCmd.Flags().StringVarP(&packOutput, "output", "o", defaultPackFile, "Archive file to write")
+}
+
+// runPack executes the pack command
+func runPack(output string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	manifest := packManifest{
+		FormatVersion: packFormatVersion,
+		Smfaman:       version,
+		Libraries:     make(map[string]string),
+	}
+	if manifest.Config, err = packPath(root, FrontendConfig); err != nil {
+		return err
+	}
+
+	// The config and the files that live next to it
+	files := map[string]string{manifest.Config: FrontendConfig}
+	for _, path := range []string{manifestPathForConfig(FrontendConfig), metadataPathForConfig(FrontendConfig)} {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		name, err := packPath(root, path)
+		if err != nil {
+			return err
+		}
+		files[name] = path
+	}
+
+	// Everything in the library destinations, except an earlier archive
+	// written there
+	outputEntry, _ := packPath(root, output)
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+		manifest.Libraries[name] = libConfig.Version
+
+		destPath, err := config.GetLibraryDestination(name, libConfig)
+		if err != nil {
+			return fmt.Errorf("failed to get destination for %s: %w", name, err)
+		}
+		if info, err := os.Stat(destPath); err != nil || !info.IsDir() {
+			fmt.Printf("⚠ %s is not synced, skipping it\n", name)
+			continue
+		}
+
+		err = filepath.WalkDir(destPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			entry, err := packPath(root, path)
+			if err != nil || entry == outputEntry {
+				return err
+			}
+			files[entry] = path
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to collect files for %s: %w", name, err)
+		}
+	}
+	manifest.Files = len(files)
+
+	if err := writePackArchive(output, manifest, files); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(output)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Packed %d %s from %d %s into %s (%s)\n",
+		len(files), pluralize(len(files), "file", "files"),
+		len(manifest.Libraries), pluralize(len(manifest.Libraries), "library", "libraries"),
+		output, formatBytes(info.Size()))
+	return nil
+}
+
+// packPath returns the slash path of a file relative to root, failing for
+// files outside it
+func packPath(root, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s is outside the current directory and can't be packed", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// writePackArchive writes the manifest and files (archive name to path on
+// disk) as a zstd-compressed tar, in sorted order
+func writePackArchive(output string, manifest packManifest, files map[string]string) error {
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pack manifest: %w", err)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(zw)
+
+	if err := writePackEntry(tw, packManifestName, int64(len(manifestData)), bytes.NewReader(manifestData)); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := packFile(tw, name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return f.Close()
+}
+
+// packFile adds a file on disk to the archive under name
+func packFile(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return writePackEntry(tw, name, info.Size(), f)
+}
+
+// writePackEntry writes a regular file entry with a fixed mode and time, so
+// archives of the same files are identical
+func writePackEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0644,
+		ModTime:  time.Unix(0, 0),
+		Format:   tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	if _, err := io.Copy(tw, r); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+
+	writeTestFile(t, "smartfrontend.yaml", "destination: ./vendor/{library_name}\nlibraries:\n  htmx.org:\n    version: 2.0.4\n  jquery:\n    version: 3.7.1\n")
+	writeTestFile(t, "smartfrontend.lock.json", `{"format_version": 1, "files": {}}`)
+	writeTestFile(t, "vendor/htmx.org/htmx.min.js", "htmx")
+	writeTestFile(t, "vendor/htmx.org/ext/sse.js", "sse")
+
+	oldConfig := FrontendConfig
+	FrontendConfig = "smartfrontend.yaml"
+	defer func() { FrontendConfig = oldConfig }()
+
+	if err := runPack("assets.tar.zst"); err != nil {
+		t.Fatalf("runPack() error = %v", err)
+	}
+	first, _ := os.ReadFile("assets.tar.zst")
+
+	// Packing the same files again gives an identical archive
+	if err := runPack("assets.tar.zst"); err != nil {
+		t.Fatalf("second runPack() error = %v", err)
+	}
+	if second, _ := os.ReadFile("assets.tar.zst"); !bytes.Equal(first, second) {
+		t.Error("packing the same files twice gave different archives")
+	}
+
+	restore := filepath.Join(t.TempDir(), "restore")
+	if err := runUnpack("assets.tar.zst", restore, false); err != nil {
+		t.Fatalf("runUnpack() error = %v", err)
+	}
+	for name, want := range map[string]string{
+		"smartfrontend.yaml":          "",
+		"smartfrontend.lock.json":     `{"format_version": 1, "files": {}}`,
+		"vendor/htmx.org/htmx.min.js": "htmx",
+		"vendor/htmx.org/ext/sse.js":  "sse",
+	} {
+		data, err := os.ReadFile(filepath.Join(restore, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%s was not restored: %v", name, err)
+		} else if want != "" && string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+
+	// Existing files are only overwritten with force
+	err := runUnpack("assets.tar.zst", restore, false)
+	if err == nil || !strings.Contains(err.Error(), "already exist") {
+		t.Errorf("expected an error about existing files, got %v", err)
+	}
+
+	// Forced writes replace links instead of writing into the shared store
+	store := filepath.Join(t.TempDir(), "htmx.min.js")
+	writeTestFile(t, store, "stored")
+	linked := filepath.Join(restore, "vendor", "htmx.org", "htmx.min.js")
+	os.Remove(linked)
+	if err := os.Symlink(store, linked); err != nil {
+		t.Fatal(err)
+	}
+	if err := runUnpack("assets.tar.zst", restore, true); err != nil {
+		t.Errorf("runUnpack() with force error = %v", err)
+	}
+	if data, _ := os.ReadFile(store); string(data) != "stored" {
+		t.Errorf("store file = %q, unpack wrote through the link", data)
+	}
+	if data, _ := os.ReadFile(linked); string(data) != "htmx" {
+		t.Errorf("unpacked file = %q, want %q", data, "htmx")
+	}
+}
+
+func TestPackRejectsDestinationOutsideProject(t *testing.T) {
+	project := t.TempDir()
+	t.Chdir(project)
+	writeTestFile(t, "smartfrontend.yaml", "destination: ../elsewhere/{library_name}\nlibraries:\n  jquery:\n    version: 3.7.1\n")
+	writeTestFile(t, "../elsewhere/jquery/jquery.min.js", "jquery")
+
+	oldConfig := FrontendConfig
+	FrontendConfig = "smartfrontend.yaml"
+	defer func() { FrontendConfig = oldConfig }()
+
+	if err := runPack("assets.tar.zst"); err == nil || !strings.Contains(err.Error(), "outside the current directory") {
+		t.Errorf("expected an error for a destination outside the project, got %v", err)
+	}
+}
+
+func TestUnpackRejectsUnsafePaths(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tar.zst")
+	var buf bytes.Buffer
+	zw, _ := zstd.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	writePackEntry(tw, packManifestName, 2, strings.NewReader("{}"))
+	writePackEntry(tw, "../escape.txt", 4, strings.NewReader("evil"))
+	tw.Close()
+	zw.Close()
+	if err := os.WriteFile(archive, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	err := runUnpack(archive, dir, false)
+	if err == nil || !strings.Contains(err.Error(), "illegal file path") {
+		t.Errorf("expected an illegal path error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.txt")); err == nil {
+		t.Error("a file was written outside the destination")
+	}
+}
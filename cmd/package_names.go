@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// packageSuggestionLimit is the number of search results checked for a
+// did-you-mean suggestion
+const packageSuggestionLimit = 5
+
+// normalizePackageName lowercases a package name for add and rejects names
+// no CDN can have, suggesting a close match. CDNJS library names keep their
+// case, since some are listed capitalized (e.g. Chart.js).
+func normalizePackageName(name string, cdn frontend_config.CDN) (string, error) {
+	name = strings.TrimSpace(name)
+	normalized := name
+	if cdn != frontend_config.CDNCdnjs {
+		normalized = frontend_mgr.NormalizePackageName(name)
+	}
+
+	if err := frontend_mgr.ValidatePackageName(strings.ToLower(normalized)); err != nil {
+		return "", suggestPackage(name, cdn, err)
+	}
+	if normalized != name {
+		fmt.Printf("• Using %s (npm package names are lowercase)\n", normalized)
+	}
+	return normalized, nil
+}
+
+// suggestPackage adds a did-you-mean suggestion from a quick search on the
+// CDN to an invalid or unknown package name error. Other errors are
+// returned unchanged.
+func suggestPackage(name string, cdn frontend_config.CDN, err error) error {
+	var statusErr *frontend_mgr.StatusError
+	if !errors.Is(err, frontend_mgr.ErrInvalidPackageName) && !(errors.As(err, &statusErr) && statusErr.NotFound()) {
+		return err
+	}
+
+	if suggestion := packageSuggestion(name, cdn); suggestion != "" {
+		return fmt.Errorf("%w (did you mean %s?)", err, suggestion)
+	}
+	return err
+}
+
+// packageSuggestion searches the CDN for a package close to name, returning
+// "" when the search fails or finds nothing else
+func packageSuggestion(name string, cdn frontend_config.CDN) string {
+	provider, err := frontend_mgr.GetProvider(string(cdn))
+	if err != nil {
+		return ""
+	}
+
+	query := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	results, err := provider.Search(query, packageSuggestionLimit)
+	if err != nil {
+		return ""
+	}
+	for _, result := range results {
+		if result.Name != name && frontend_mgr.ValidatePackageName(strings.ToLower(result.Name)) == nil {
+			return result.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// searchOnlyProvider stands in for unpkg with fixed search results
+type searchOnlyProvider struct {
+	results []frontend_mgr.SearchResult
+}
+
+func (searchOnlyProvider) Name() string { return "unpkg" }
+func (searchOnlyProvider) Versions(string) (*frontend_mgr.VersionList, error) {
+	return nil, &frontend_mgr.StatusError{Source: "npm registry API", StatusCode: 404}
+}
+func (searchOnlyProvider) Manifest(string, string) ([]frontend_mgr.PackageFile, error) {
+	return nil, nil
+}
+func (searchOnlyProvider) FileURL(string, string, string) string { return "" }
+func (p searchOnlyProvider) Search(string, int) ([]frontend_mgr.SearchResult, error) {
+	return p.results, nil
+}
+
+// useSearchResults replaces the unpkg provider for the rest of the test
+func useSearchResults(t *testing.T, names ...string) {
+	t.Helper()
+	orig, err := frontend_mgr.GetProvider("unpkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { frontend_mgr.RegisterProvider(orig) })
+
+	var results []frontend_mgr.SearchResult
+	for _, name := range names {
+		results = append(results, frontend_mgr.SearchResult{Name: name})
+	}
+	frontend_mgr.RegisterProvider(searchOnlyProvider{results: results})
+}
+
+func TestNormalizePackageName(t *testing.T) {
+	useSearchResults(t, "Chart Js", "chart.js")
+
+	got, err := normalizePackageName(" JQuery ", frontend_config.CDNUnpkg)
+	if err != nil || got != "jquery" {
+		t.Errorf("normalizePackageName() = %q, %v; want jquery", got, err)
+	}
+	got, err = normalizePackageName("Chart.js", frontend_config.CDNCdnjs)
+	if err != nil || got != "Chart.js" {
+		t.Errorf("normalizePackageName() on cdnjs = %q, %v; want the case kept", got, err)
+	}
+
+	_, err = normalizePackageName("chart js", frontend_config.CDNUnpkg)
+	if !errors.Is(err, frontend_mgr.ErrInvalidPackageName) {
+		t.Fatalf("normalizePackageName() error = %v, want ErrInvalidPackageName", err)
+	}
+	if !strings.Contains(err.Error(), "did you mean chart.js?") {
+		t.Errorf("error %q should suggest the first valid search result", err)
+	}
+}
+
+func TestSuggestPackage(t *testing.T) {
+	useSearchResults(t, "bootstrap")
+
+	notFound := &frontend_mgr.StatusError{Source: "npm registry API", StatusCode: 404}
+	if err := suggestPackage("bootstarp", frontend_config.CDNUnpkg, notFound); !strings.Contains(err.Error(), "did you mean bootstrap?") {
+		t.Errorf("suggestPackage() = %q, want a suggestion for a 404", err)
+	}
+
+	other := errors.New("connection refused")
+	if err := suggestPackage("bootstarp", frontend_config.CDNUnpkg, other); err != other {
+		t.Errorf("suggestPackage() = %v, want other errors unchanged", err)
+	}
+
+	useSearchResults(t)
+	if err := suggestPackage("bootstarp", frontend_config.CDNUnpkg, notFound); err != notFound {
+		t.Errorf("suggestPackage() = %v, want the error unchanged without results", err)
+	}
+}
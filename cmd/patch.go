@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// patchesDirName is the folder next to the config that holds library patches
+const patchesDirName = "patches"
+
+// patchCmd represents the patch command
+var patchCmd = &cobra.Command{
+	Use:   "patch",
+	Short: "Keep local fixes to vendored files across syncs and upgrades",
+	Long: `Keep small local fixes to vendored files as patches.
+
+Each library's fixes live in patches/<library>.patch next to the config, as a
+unified diff with paths relative to the library's destination (scoped names
+use + instead of /, e.g. patches/@scope+pkg.patch). After 'smfaman sync'
+writes a file, the matching part of the patch is applied to it, so fixes come
+back after a forced re-download or an upgrade. A patch that no longer applies
+is reported and the file is left as downloaded.
+
+Subcommands:
+  create - Record the local changes to a vendored file in its library's patch`,
+}
+
+// patchCreateCmd records local changes to a file as a patch
+var patchCreateCmd = &cobra.Command{
+	Use:   "create <library> <file>",
+	Short: "Record local changes to a vendored file as a patch",
+	Long: `Compare a vendored file with the version published on the CDN and write the
+differences to patches/<library>.patch. The file path is relative to the
+library's destination, as shown by 'smfaman files --local' (for libraries
+with strip_prefix, give the path in the package). An existing patch for the
+same file is replaced; patches for the library's other files are kept.
+
+Examples:
+  smfaman patch create jquery dist/jquery.js
+  smfaman patch create @popperjs/core dist/umd/popper.min.js`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runPatchCreate(args[0], args[1]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(patchCmd)
+	patchCmd.AddCommand(patchCreateCmd)
+}
+
+// fetchPristineFile returns a library file as published on the CDN,
+// preferring the package cache (overridable in tests)
+var fetchPristineFile = func(libName, version string, cdn frontend_config.CDN, filePath string, opts requestOptions) ([]byte, error) {
+	data, cached, _ := frontend_mgr.CacheManager.GetPackageFile(string(cdn), libName, version, filePath)
+	if cached {
+		return data, nil
+	}
+	data, _, _, err := downloadFileConditional(cdnFileURL(libName, version, cdn, filePath), httpValidators{}, opts)
+	return data, err
+}
+
+// runPatchCreate executes the patch create command
+func runPatchCreate(libName, filePath string) error {
+	filePath = path.Clean(filepath.ToSlash(filePath))
+	if !filepath.IsLocal(filepath.FromSlash(filePath)) {
+		return fmt.Errorf("%s must be a path inside the library's destination", filePath)
+	}
+
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+	libConfig, exists := config.Libraries[libName]
+	if !exists {
+		return notFoundError(fmt.Errorf("library '%s' not found in config", libName))
+	}
+
+	destPath, err := config.GetLibraryDestination(libName, libConfig)
+	if err != nil {
+		return fmt.Errorf("failed to get destination for %s: %w", libName, err)
+	}
+	localName := localFilePaths([]CDNFile{{Path: filePath}}, libConfig.StripPrefix)[filePath]
+	local, err := os.ReadFile(filepath.Join(destPath, filepath.FromSlash(localName)))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	cdn := libraryCDN(config, libConfig)
+	opts := requestOptions{Headers: config.GetLibraryHeaders(libConfig), Query: config.GetLibraryQuery(libConfig)}
+	pristine, err := fetchPristineFile(libName, libConfig.Version, cdn, filePath, opts)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s@%s/%s: %w", libName, libConfig.Version, filePath, err)
+	}
+	if config.GetLibrarySourceMaps(libConfig) == frontend_config.SourceMapsExclude && hasSourceMapComment(filePath) {
+		pristine = stripSourceMappingURL(pristine)
+	}
+
+	hunks, err := diffFile(string(pristine), string(local))
+	if err != nil {
+		return err
+	}
+	if len(hunks) == 0 {
+		return fmt.Errorf("%s is identical to %s@%s on %s, nothing to record", filePath, libName, libConfig.Version, cdn)
+	}
+
+	patchPath := libraryPatchPath(FrontendConfig, libName)
+	patches, err := loadLibraryPatch(patchPath)
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range patches {
+		if patches[i].Path == filePath {
+			patches[i].Hunks = hunks
+			replaced = true
+		}
+	}
+	if !replaced {
+		patches = append(patches, filePatch{Path: filePath, Hunks: hunks})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(patchPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", patchesDirName, err)
+	}
+	if err := os.WriteFile(patchPath, []byte(formatPatch(patches)), 0644); err != nil {
+		return fmt.Errorf("failed to write patch: %w", err)
+	}
+
+	fmt.Printf("✓ Recorded %d %s for %s in %s\n", len(hunks), pluralize(len(hunks), "change", "changes"), filePath, patchPath)
+	fmt.Println("  It is applied again whenever sync downloads the file.")
+	return nil
+}
+
+// libraryPatchPath returns the patch file for a library
+func libraryPatchPath(configPath, libName string) string {
+	return filepath.Join(filepath.Dir(configPath), patchesDirName, strings.ReplaceAll(libName, "/", "+")+".patch")
+}
+
+// loadLibraryPatch reads a library's patch file, returning nothing if it
+// does not exist
+func loadLibraryPatch(patchPath string) ([]filePatch, error) {
+	data, err := os.ReadFile(patchPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patch: %w", err)
+	}
+	patches, err := parsePatch(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", patchPath, err)
+	}
+	return patches, nil
+}
+
+// applyLibraryPatches applies the library patches to the files written by a
+// sync, updating their recorded integrity. Patches that don't apply are
+// reported and leave the downloaded file as it is.
+func applyLibraryPatches(configPath string, downloads []downloadedFile, out io.Writer) {
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), patchesDirName)); err != nil {
+		return
+	}
+
+	loaded := make(map[string][]filePatch)
+	applied := 0
+	for i := range downloads {
+		d := &downloads[i]
+		if d.result.Unchanged {
+			continue
+		}
+
+		patches, ok := loaded[d.task.LibraryName]
+		if !ok {
+			var err error
+			patchPath := libraryPatchPath(configPath, d.task.LibraryName)
+			if patches, err = loadLibraryPatch(patchPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			loaded[d.task.LibraryName] = patches
+		}
+
+		for _, patch := range patches {
+			if patch.Path != d.task.FilePath {
+				continue
+			}
+			if err := applyPatchToFile(d, patch); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ %s: %v; left as downloaded (recreate it with 'smfaman patch create %s %s')\n",
+					libraryPatchPath(configPath, d.task.LibraryName), err, d.task.LibraryName, patch.Path)
+				continue
+			}
+			applied++
+		}
+	}
+
+	if applied > 0 {
+		fmt.Fprintf(out, "✓ Applied local patches to %d %s\n", applied, pluralize(applied, "file", "files"))
+	}
+}
+
+// applyPatchToFile patches a downloaded file and its mirrors
+func applyPatchToFile(d *downloadedFile, patch filePatch) error {
+	data, err := os.ReadFile(d.task.DestPath)
+	if err != nil {
+		return err
+	}
+	patched, err := applyFilePatch(string(data), patch)
+	if err != nil {
+		return err
+	}
+
+	// Written as copies so linked files never change the package store
+	for _, destPath := range append([]string{d.task.DestPath}, d.task.MirrorPaths...) {
+		if err := placeFile(destPath, []byte(patched), "", ""); err != nil {
+			return err
+		}
+	}
+	d.result.Integrity = frontend_mgr.ComputeSRI([]byte(patched))
+	return nil
+}
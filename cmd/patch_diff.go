@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// patchContext is the number of unchanged lines around each hunk
+const patchContext = 3
+
+// maxPatchDiffCells limits the size of the line table diffLines builds for
+// the changed part of a file
+const maxPatchDiffCells = 4_000_000
+
+// filePatch is the part of a unified diff that changes one file
+type filePatch struct {
+	Path  string // Slash path inside the library destination
+	Hunks []patchHunk
+}
+
+// patchHunk is one "@@ -a,b +c,d @@" block of a unified diff
+type patchHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []diffLine
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// diffFile returns the hunks turning oldText into newText. Only the lines
+// between the common prefix and suffix are diffed, so small edits to large
+// vendored files stay cheap.
+func diffFile(oldText, newText string) ([]patchHunk, error) {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(middleA)*len(middleB) > maxPatchDiffCells {
+		return nil, fmt.Errorf("the changes span too much of the file to diff (%d and %d lines); keep local changes small", len(middleA), len(middleB))
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(middleB))
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	lines = append(lines, diffLines(middleA, middleB)...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	return groupHunks(lines), nil
+}
+
+// groupHunks splits a line diff into hunks with patchContext lines of context
+func groupHunks(lines []diffLine) []patchHunk {
+	var hunks []patchHunk
+	oldLine, newLine := 1, 1 // Line numbers of lines[i]
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Start the hunk patchContext lines before the first change
+		start := max(i-patchContext, 0)
+		for j := start; j < i; j++ {
+			oldLine--
+			newLine--
+		}
+		hunk := patchHunk{OldStart: oldLine, NewStart: newLine}
+
+		// Extend it until patchContext*2 unchanged lines separate two changes
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != ' ' {
+				end = j
+			} else if j-end > patchContext*2 {
+				break
+			}
+		}
+		end += patchContext + 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		for _, line := range lines[start:end] {
+			hunk.Lines = append(hunk.Lines, line)
+			if line.op != '+' {
+				hunk.OldLines++
+				oldLine++
+			}
+			if line.op != '-' {
+				hunk.NewLines++
+				newLine++
+			}
+		}
+		hunks = append(hunks, hunk)
+		i = end
+	}
+	return hunks
+}
+
+// formatPatch renders file patches as a unified diff, sorted by path
+func formatPatch(patches []filePatch) string {
+	sort.Slice(patches, func(i, j int) bool { return patches[i].Path < patches[j].Path })
+
+	var b strings.Builder
+	for _, patch := range patches {
+		fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", patch.Path, patch.Path)
+		for _, hunk := range patch.Hunks {
+			fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines)
+			for _, line := range hunk.Lines {
+				b.WriteByte(line.op)
+				b.WriteString(line.text)
+				b.WriteByte('\n')
+			}
+		}
+	}
+	return b.String()
+}
+
+// parsePatch reads a unified diff into file patches. Text before the first
+// "---" line is ignored, as are "\ No newline at end of file" markers.
+func parsePatch(text string) ([]filePatch, error) {
+	var patches []filePatch
+	var current *filePatch
+	var hunk *patchHunk
+
+	lines := splitLines(text)
+	for n := 0; n < len(lines); n++ {
+		line := lines[n]
+		switch {
+		case strings.HasPrefix(line, "--- ") && n+1 < len(lines) && strings.HasPrefix(lines[n+1], "+++ "):
+			path := patchFilePath(lines[n+1][4:])
+			if path == "" {
+				return nil, fmt.Errorf("line %d: missing file name", n+2)
+			}
+			patches = append(patches, filePatch{Path: path})
+			current, hunk = &patches[len(patches)-1], nil
+			n++
+		case strings.HasPrefix(line, "@@"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk before any file header", n+1)
+			}
+			m := hunkHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("line %d: invalid hunk header %q", n+1, line)
+			}
+			current.Hunks = append(current.Hunks, patchHunk{
+				OldStart: atoiDefault(m[1], 0), OldLines: atoiDefault(m[2], 1),
+				NewStart: atoiDefault(m[3], 0), NewLines: atoiDefault(m[4], 1),
+			})
+			hunk = &current.Hunks[len(current.Hunks)-1]
+		case hunk != nil && line != "" && strings.ContainsRune(" +-", rune(line[0])):
+			hunk.Lines = append(hunk.Lines, diffLine{line[0], line[1:]})
+		case hunk != nil && line == "":
+			// Some editors strip the space of empty context lines
+			hunk.Lines = append(hunk.Lines, diffLine{' ', ""})
+		case strings.HasPrefix(line, `\`):
+			continue
+		default:
+			hunk = nil
+		}
+	}
+	return patches, nil
+}
+
+// patchFilePath strips the "b/" prefix and any timestamp from a "+++" path
+func patchFilePath(path string) string {
+	if i := strings.IndexByte(path, '\t'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.TrimPrefix(strings.TrimSpace(path), "b/")
+}
+
+// atoiDefault parses a hunk header number, using def when it is omitted
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// applyFilePatch applies the hunks to text. Each hunk is looked for at its
+// recorded line first and then anywhere after the previous hunk, so patches
+// survive lines being added or removed elsewhere, e.g. after an upgrade.
+func applyFilePatch(text string, patch filePatch) (string, error) {
+	lines := splitLines(text)
+	trailingNewline := strings.HasSuffix(text, "\n")
+
+	var out []string
+	pos := 0   // Next unconsumed line of lines
+	drift := 0 // How far the previous hunk was from its recorded line
+	for i, hunk := range patch.Hunks {
+		var old, replacement []string
+		for _, line := range hunk.Lines {
+			if line.op != '+' {
+				old = append(old, line.text)
+			}
+			if line.op != '-' {
+				replacement = append(replacement, line.text)
+			}
+		}
+
+		hint := hunk.OldStart - 1 + drift
+		if hunk.OldLines == 0 {
+			hint++ // Pure insertions record the line they follow
+		}
+		at := findLines(lines, old, pos, max(hint, pos))
+		if at < 0 {
+			return "", fmt.Errorf("hunk %d (@@ -%d,%d) does not apply to %s", i+1, hunk.OldStart, hunk.OldLines, patch.Path)
+		}
+		out = append(out, lines[pos:at]...)
+		out = append(out, replacement...)
+		drift = at - (hunk.OldStart - 1)
+		pos = at + len(old)
+	}
+	out = append(out, lines[pos:]...)
+
+	result := strings.Join(out, "\n")
+	if trailingNewline && len(out) > 0 {
+		result += "\n"
+	}
+	return result, nil
+}
+
+// findLines returns the index of want in lines at or after from, trying
+// hint first and then the closest matches around it, or -1
+func findLines(lines, want []string, from, hint int) int {
+	matches := func(at int) bool {
+		if at < from || at+len(want) > len(lines) {
+			return false
+		}
+		for i, line := range want {
+			if lines[at+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+
+	for d := 0; hint-d >= from || hint+d <= len(lines); d++ {
+		if matches(hint + d) {
+			return hint + d
+		}
+		if d > 0 && matches(hint-d) {
+			return hint - d
+		}
+	}
+	return -1
+}
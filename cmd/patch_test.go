@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// numberedLines returns "line 1\n" to "line n\n"
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func TestDiffAndApplyPatch(t *testing.T) {
+	original := numberedLines(40)
+	modified := strings.Replace(original, "line 5\n", "line 5 fixed\n", 1)
+	modified = strings.Replace(modified, "line 30\n", "line 30\nadded line\n", 1)
+
+	hunks, err := diffFile(original, modified)
+	if err != nil {
+		t.Fatalf("diffFile failed: %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %d", len(hunks))
+	}
+
+	text := formatPatch([]filePatch{{Path: "dist/app.js", Hunks: hunks}})
+	if !strings.Contains(text, "@@ -2,7 +2,7 @@\n") || !strings.Contains(text, "+line 5 fixed\n") {
+		t.Errorf("unexpected patch:\n%s", text)
+	}
+
+	patches, err := parsePatch(text)
+	if err != nil {
+		t.Fatalf("parsePatch failed: %v", err)
+	}
+	if len(patches) != 1 || patches[0].Path != "dist/app.js" {
+		t.Fatalf("unexpected parsed patches: %+v", patches)
+	}
+
+	patched, err := applyFilePatch(original, patches[0])
+	if err != nil {
+		t.Fatalf("applyFilePatch failed: %v", err)
+	}
+	if patched != modified {
+		t.Errorf("patched text differs:\n%s", patched)
+	}
+
+	// A newer release with lines added before both changes
+	upgraded := "new header\nnew header 2\n" + original
+	patched, err = applyFilePatch(upgraded, patches[0])
+	if err != nil {
+		t.Fatalf("applyFilePatch on shifted file failed: %v", err)
+	}
+	if patched != "new header\nnew header 2\n"+modified {
+		t.Errorf("patched shifted text differs:\n%s", patched)
+	}
+
+	// A release where the patched lines changed
+	if _, err := applyFilePatch(strings.Replace(original, "line 5\n", "line five\n", 1), patches[0]); err == nil {
+		t.Errorf("expected an error when the context no longer matches")
+	}
+}
+
+func TestDiffFileIdentical(t *testing.T) {
+	hunks, err := diffFile("a\nb\n", "a\nb\n")
+	if err != nil || len(hunks) != 0 {
+		t.Errorf("diffFile of identical text = %v, %v; want no hunks", hunks, err)
+	}
+}
+
+func TestParsePatchErrors(t *testing.T) {
+	if _, err := parsePatch("@@ -1 +1 @@\n-a\n+b\n"); err == nil {
+		t.Errorf("expected an error for a hunk without a file header")
+	}
+	if _, err := parsePatch("--- a/x.js\n+++ b/x.js\n@@ nonsense @@\n"); err == nil {
+		t.Errorf("expected an error for an invalid hunk header")
+	}
+}
+
+func TestPatchCreateAndApply(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	config := frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"@scope/lib": {Version: "1.0.0"},
+		},
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	original := numberedLines(20)
+	modified := strings.Replace(original, "line 10\n", "line 10 // local fix\n", 1)
+	filePath := filepath.Join(tmpDir, "libs", "@scope", "lib", "dist", "lib.js")
+	writeTestFile(t, filePath, modified)
+
+	origConfig, origFetch := FrontendConfig, fetchPristineFile
+	FrontendConfig = configPath
+	fetchPristineFile = func(libName, version string, cdn frontend_config.CDN, path string, opts requestOptions) ([]byte, error) {
+		return []byte(original), nil
+	}
+	defer func() { FrontendConfig, fetchPristineFile = origConfig, origFetch }()
+
+	if err := runPatchCreate("@scope/lib", "dist/lib.js"); err != nil {
+		t.Fatalf("runPatchCreate failed: %v", err)
+	}
+	patchPath := filepath.Join(tmpDir, "patches", "@scope+lib.patch")
+	if _, err := os.Stat(patchPath); err != nil {
+		t.Fatalf("expected %s: %v", patchPath, err)
+	}
+
+	// Sync writes the pristine file again; the patch brings the fix back
+	writeTestFile(t, filePath, original)
+	downloads := []downloadedFile{{task: DownloadTask{LibraryName: "@scope/lib", FilePath: "dist/lib.js", DestPath: filePath}}}
+	applyLibraryPatches(configPath, downloads, io.Discard)
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != modified {
+		t.Errorf("patch was not applied, got:\n%s", got)
+	}
+	if downloads[0].result.Integrity != frontend_mgr.ComputeSRI([]byte(modified)) {
+		t.Errorf("integrity should describe the patched file")
+	}
+
+	// Nothing left to record once the file matches the CDN
+	writeTestFile(t, filePath, original)
+	if err := runPatchCreate("@scope/lib", "dist/lib.js"); err == nil {
+		t.Errorf("expected an error for an unmodified file")
+	}
+}
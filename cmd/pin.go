@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var pinDryRun bool
+
+// pinCmd represents the pin command
+var pinCmd = &cobra.Command{
+	Use:   "pin [library...]",
+	Short: "Rewrite version ranges and dist-tags to exact versions",
+	Long: `Resolve every version range (^18, 5.x, ~1.2.3) and dist-tag (latest, next)
+in the configuration to an exact version and rewrite the config with it.
+
+A version already recorded in the lockfile that satisfies the range is kept,
+so the pin matches the files currently on disk. Otherwise the highest
+matching version published on the library's CDN is used. Lockfile entries
+for pinned libraries are updated to the exact version.
+
+Useful before a release freeze. Libraries that are already pinned are left
+untouched.
+
+Examples:
+  smfaman pin
+  smfaman pin react vue
+  smfaman pin --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runPin(args); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	pinCmd.Flags().BoolVar(&pinDryRun, "dry-run", false, "Show the pins without changing the config")
+}
+
+// versionPin is the exact version a library's version spec resolves to
+type versionPin struct {
+	Library string
+	Spec    string
+	Version string
+	Source  string // "lockfile" or the CDN
+}
+
+// fetchPinVersions fetches the published versions and dist-tags of a package (overridable in tests)
+var fetchPinVersions = func(packageName string, cdn frontend_config.CDN) ([]string, map[string]string, error) {
+	provider, err := frontend_mgr.GetProvider(string(cdn))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := provider.Versions(packageName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch versions from %s: %w", cdn, err)
+	}
+	return result.Versions, result.DistTags, nil
+}
+
+// runPin executes the pin command
+func runPin(libraries []string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range libraries {
+		if _, ok := config.Libraries[name]; !ok {
+			return notFoundError(fmt.Errorf("library '%s' not found in config", name))
+		}
+	}
+
+	manifestPath := manifestPathForConfig(FrontendConfig)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	pins, errs := resolvePins(config, manifest, libraries)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+	}
+
+	if len(pins) == 0 {
+		if len(errs) > 0 {
+			return batchError(fmt.Errorf("failed to pin %d %s", len(errs), pluralize(len(errs), "library", "libraries")), errs)
+		}
+		fmt.Println("✓ All libraries are already pinned to exact versions")
+		return nil
+	}
+
+	fmt.Printf("Pinning %d %s:\n\n", len(pins), pluralize(len(pins), "library", "libraries"))
+	for _, pin := range pins {
+		fmt.Printf("  • %s: %s → %s (from %s)\n", pin.Library, pin.Spec, pin.Version, pin.Source)
+	}
+
+	if pinDryRun {
+		fmt.Println("\n[DRY RUN] No changes made to config file.")
+		return nil
+	}
+
+	applyPins(config, manifest, pins)
+
+	if err := saveConfig(FrontendConfig, config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if _, err := os.Stat(manifestPath); err == nil {
+		if err := saveManifest(manifestPath, manifest); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("\n✓ Config updated: %s\n", FrontendConfig)
+	if len(errs) > 0 {
+		return batchError(fmt.Errorf("failed to pin %d %s", len(errs), pluralize(len(errs), "library", "libraries")), errs)
+	}
+	return nil
+}
+
+// resolvePins resolves the version spec of every selected library that isn't
+// already an exact version
+func resolvePins(config *frontend_config.FrontendConfig, manifest *fileManifest, libraries []string) ([]versionPin, []error) {
+	if len(libraries) == 0 {
+		libraries = sortedKeys(config.Libraries)
+	}
+
+	var pins []versionPin
+	var errs []error
+	for _, name := range libraries {
+		libConfig := config.Libraries[name]
+		spec := libConfig.Version
+		if frontend_mgr.IsExactVersion(spec) {
+			continue
+		}
+
+		if locked := lockedVersion(manifest, name, spec); locked != "" {
+			pins = append(pins, versionPin{Library: name, Spec: spec, Version: locked, Source: "lockfile"})
+			continue
+		}
+
+		cdn := libraryCDN(config, libConfig)
+		versions, distTags, err := fetchPinVersions(name, cdn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		resolved, err := frontend_mgr.ResolveVersionSpec(spec, versions, distTags)
+		if err != nil {
+			errs = append(errs, notFoundError(fmt.Errorf("%s: %w", name, err)))
+			continue
+		}
+		pins = append(pins, versionPin{Library: name, Spec: spec, Version: resolved, Source: string(cdn)})
+	}
+
+	return pins, errs
+}
+
+// lockedVersion returns the highest exact version recorded in the lockfile
+// for a library that satisfies spec, or "" if there is none
+func lockedVersion(manifest *fileManifest, library, spec string) string {
+	r, err := frontend_mgr.ParseVersionRange(spec)
+	if err != nil {
+		return "" // Dist-tags can't be checked against recorded versions
+	}
+
+	var recorded []string
+	for _, entry := range manifest.Files {
+		if entry.Library == library && frontend_mgr.IsExactVersion(entry.Version) {
+			recorded = append(recorded, entry.Version)
+		}
+	}
+	return r.MaxSatisfying(recorded)
+}
+
+// applyPins writes the pinned versions into the config and lockfile entries
+func applyPins(config *frontend_config.FrontendConfig, manifest *fileManifest, pins []versionPin) {
+	for _, pin := range pins {
+		libConfig := config.Libraries[pin.Library]
+		libConfig.Version = pin.Version
+		config.Libraries[pin.Library] = libConfig
+
+		for key, entry := range manifest.Files {
+			if entry.Library == pin.Library && entry.Version == pin.Spec {
+				entry.Version = pin.Version
+				manifest.Files[key] = entry
+			}
+		}
+	}
+}
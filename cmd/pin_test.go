@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestResolvePins(t *testing.T) {
+	origFetch := fetchPinVersions
+	defer func() { fetchPinVersions = origFetch }()
+
+	var fetched []string
+	fetchPinVersions = func(packageName string, cdn frontend_config.CDN) ([]string, map[string]string, error) {
+		fetched = append(fetched, packageName)
+		switch packageName {
+		case "react":
+			return []string{"18.3.1", "18.2.0", "17.0.2"}, map[string]string{"latest": "18.3.1"}, nil
+		case "vue":
+			return []string{"3.4.21", "3.5.0"}, map[string]string{"latest": "3.5.0", "next": "3.5.0"}, nil
+		default:
+			return nil, nil, errors.New("package not found")
+		}
+	}
+
+	config := &frontend_config.FrontendConfig{
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":  {Version: "3.7.1"},
+			"react":   {Version: "^18"},
+			"vue":     {Version: "latest"},
+			"alpine":  {Version: "3.x"},
+			"missing": {Version: "^1"},
+		},
+	}
+	manifest := &fileManifest{
+		Files: map[string]manifestEntry{
+			"libs/alpine/cdn.min.js": {Library: "alpine", Version: "3.13.5"},
+			"libs/react/react.js":    {Library: "react", Version: "^18"},
+		},
+	}
+
+	pins, errs := resolvePins(config, manifest, nil)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for the missing package, got %v", errs)
+	}
+
+	expected := map[string]versionPin{
+		"alpine": {Library: "alpine", Spec: "3.x", Version: "3.13.5", Source: "lockfile"},
+		"react":  {Library: "react", Spec: "^18", Version: "18.3.1", Source: "unpkg"},
+		"vue":    {Library: "vue", Spec: "latest", Version: "3.5.0", Source: "unpkg"},
+	}
+	if len(pins) != len(expected) {
+		t.Fatalf("expected %d pins, got %v", len(expected), pins)
+	}
+	for _, pin := range pins {
+		if pin != expected[pin.Library] {
+			t.Errorf("unexpected pin %+v, want %+v", pin, expected[pin.Library])
+		}
+	}
+	for _, name := range fetched {
+		if name == "jquery" || name == "alpine" {
+			t.Errorf("%s should not be fetched from the CDN", name)
+		}
+	}
+
+	applyPins(config, manifest, pins)
+	if v := config.Libraries["react"].Version; v != "18.3.1" {
+		t.Errorf("expected react pinned to 18.3.1, got %s", v)
+	}
+	if v := manifest.Files["libs/react/react.js"].Version; v != "18.3.1" {
+		t.Errorf("expected lockfile entry updated to 18.3.1, got %s", v)
+	}
+	if v := config.Libraries["missing"].Version; v != "^1" {
+		t.Errorf("expected unresolved library to keep its range, got %s", v)
+	}
+}
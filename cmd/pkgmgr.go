@@ -18,30 +18,36 @@ var pkgmgrCmd = &cobra.Command{
 configuration file.
 
 The package manager TUI allows you to:
-  • View all libraries in your configuration
+  • View all libraries in your configuration, with their latest versions
+    and descriptions loaded in the background
   • Add new libraries to the configuration
   • Edit existing library configurations (version, CDN, files, output path)
   • Delete libraries from the configuration
-  • Edit global settings (project name, destination, default CDN)
+  • Edit global settings (project name, destination, default CDN), with a
+    check that the destination folder exists and how much is vendored in it
   • Save changes back to the configuration file
 
 Navigation:
   • Use arrow keys or tab/shift+tab to navigate
+  • Scroll lists with the mouse wheel and click a row to select it
   • Press 'enter' to edit a selected library
   • Press 'a' to add a new library
   • Press 'v' or 'i' on version field to select version interactively
+  • If fetching versions fails, press 'r' to retry or 'c' to retry on
+    the next CDN
   • Press 'd' to delete the selected library
   • Press 'g' to edit global settings
   • Press 's' to save and quit
   • Press 'q' or 'esc' to quit without saving
   • Press 'ctrl+c' to force quit
+  • Press '?' (F1 while typing in a field) to list every key of the
+    current screen
 
 Examples:
   smfaman pkgmgr`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runPkgmgr(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -51,14 +57,19 @@ func init() {
 }
 
 func runPkgmgr() error {
+	if err := requireTerminal("pkgmgr", "use 'smfaman config set' or the add/remove commands instead"); err != nil {
+		return err
+	}
+
 	// Load existing config
 	config, err := loadConfigForPkgmgr(FrontendConfig)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	libraryInfoSlots = make(chan struct{}, projectConcurrency(config))
 
 	// Run TUI
-	p := tea.NewProgram(newPkgmgrModel(config, FrontendConfig))
+	p := tea.NewProgram(newPkgmgrModel(config, FrontendConfig), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	finalModel, err := p.Run()
 	if err != nil {
 		return fmt.Errorf("TUI error: %w", err)
@@ -71,6 +82,7 @@ func runPkgmgr() error {
 			if err := saveConfigForPkgmgr(FrontendConfig, config); err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
+			fmt.Println(successStyle.Render("✓ Config saved successfully!"))
 		}
 	}
 
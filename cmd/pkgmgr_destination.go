@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// destinationStatus describes the global destination being edited in
+// pkgmgr: whether its root directory exists and what is vendored under it
+type destinationStatus struct {
+	root       string // Directory the destination template starts in
+	exists     bool
+	notDir     bool // root exists but is a file
+	files      int
+	bytes      int64
+	libraries  int // Libraries using the global destination
+	downloaded int // ... of which have a folder on disk
+}
+
+// destinationStatusMsg carries the status of a destination template
+type destinationStatusMsg struct {
+	template string
+	status   destinationStatus
+}
+
+// destinationRoot returns the directory a destination template starts in:
+// everything before the first placeholder, e.g. "wwwroot/lib" for
+// "wwwroot/lib/{library_name}"
+func destinationRoot(template string) string {
+	prefix, _, hasPlaceholder := strings.Cut(template, "{")
+	switch {
+	case prefix == "":
+		return "."
+	case !hasPlaceholder, strings.HasSuffix(prefix, "/"), strings.HasSuffix(prefix, string(filepath.Separator)):
+		return filepath.Clean(prefix)
+	default:
+		// The placeholder is part of the last name, e.g. "lib/js-{library_name}"
+		return filepath.Dir(prefix)
+	}
+}
+
+// inspectDestination checks the root of a destination template and adds up
+// the files vendored for the libraries that use it
+func inspectDestination(config *frontend_config.FrontendConfig, template string) destinationStatus {
+	status := destinationStatus{root: destinationRoot(template)}
+
+	info, err := os.Stat(status.root)
+	if err != nil {
+		return status
+	}
+	status.exists = true
+	if !info.IsDir() {
+		status.notDir = true
+		return status
+	}
+
+	probe := frontend_config.FrontendConfig{Destination: template}
+	seen := make(map[string]bool)
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+		if libConfig.OutputPath != "" {
+			continue
+		}
+		status.libraries++
+
+		dest, err := probe.GetLibraryDestination(name, libConfig)
+		if err != nil || seen[dest] {
+			continue
+		}
+		seen[dest] = true
+		files, bytes, err := directoryUsage(dest)
+		if err != nil {
+			continue
+		}
+		status.downloaded++
+		status.files += files
+		status.bytes += bytes
+	}
+	return status
+}
+
+// inspectDestinationCmd inspects a destination template in the background
+func inspectDestinationCmd(config *frontend_config.FrontendConfig, template string) tea.Cmd {
+	return func() tea.Msg {
+		return destinationStatusMsg{template: template, status: inspectDestination(config, template)}
+	}
+}
+
+// createDestinationRoot creates the root directory of a destination template
+func createDestinationRoot(template string) error {
+	root := destinationRoot(template)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", root, err)
+	}
+	return nil
+}
+
+// describe renders the status for the global settings form
+func (s destinationStatus) describe() string {
+	switch {
+	case s.notDir:
+		return fmt.Sprintf("✗ %s is a file, not a directory", s.root)
+	case !s.exists:
+		return fmt.Sprintf("✗ %s doesn't exist yet (%s creates it now)", s.root, pkgmgrFormKeys.CreateDestination.Help().Key)
+	case s.libraries == 0:
+		return fmt.Sprintf("✓ %s exists", s.root)
+	}
+	return fmt.Sprintf("✓ %s exists • %d files, %s vendored for %d of %d libraries",
+		s.root, s.files, formatBytes(s.bytes), s.downloaded, s.libraries)
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestDestinationRoot(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"./frontend/{library_name}", "frontend"},
+		{"wwwroot/lib/{library_name}/{version}", filepath.Join("wwwroot", "lib")},
+		{"static/js-{library_name}", "static"},
+		{"{library_name}", "."},
+		{"vendor", "vendor"},
+		{"", "."},
+	}
+
+	for _, tt := range tests {
+		if got := destinationRoot(tt.template); got != tt.want {
+			t.Errorf("destinationRoot(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestInspectDestination(t *testing.T) {
+	t.Chdir(t.TempDir())
+	config := &frontend_config.FrontendConfig{
+		Destination: "lib/{library_name}",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "3.7.1"},
+			"vue":    {Version: "3.4.0"},
+			"htmx":   {Version: "2.0.0", OutputPath: "static/htmx"},
+		},
+	}
+
+	status := inspectDestination(config, config.Destination)
+	if status.exists || status.root != "lib" {
+		t.Fatalf("status = %+v, want a missing lib root", status)
+	}
+	if !strings.Contains(status.describe(), "doesn't exist") {
+		t.Errorf("describe() = %q", status.describe())
+	}
+
+	writeTestFile(t, filepath.Join("lib", "jquery", "jquery.min.js"), strings.Repeat("x", 1000))
+	writeTestFile(t, filepath.Join("lib", "jquery", "jquery.js"), strings.Repeat("x", 2000))
+	writeTestFile(t, filepath.Join("static", "htmx", "htmx.js"), "x")
+
+	status = inspectDestination(config, config.Destination)
+	if !status.exists || status.notDir {
+		t.Fatalf("status = %+v, want an existing directory", status)
+	}
+	if status.files != 2 || status.bytes != 3000 || status.downloaded != 1 || status.libraries != 2 {
+		t.Errorf("status = %+v, want 2 files, 3000 bytes, 1 of 2 libraries (htmx has its own output path)", status)
+	}
+
+	writeTestFile(t, "file-root", "x")
+	if status := inspectDestination(config, "file-root/{library_name}"); !status.notDir {
+		t.Errorf("status = %+v, want notDir for a file", status)
+	}
+}
+
+func TestPkgmgrCreateDestination(t *testing.T) {
+	t.Chdir(t.TempDir())
+	config := &frontend_config.FrontendConfig{
+		Destination: "public/vendor/{library_name}",
+		Libraries:   map[string]frontend_config.LibraryConfig{},
+	}
+
+	var model tea.Model = newPkgmgrModel(config, "frontend.yaml")
+	send := func(msg tea.Msg) pkgmgrModel {
+		t.Helper()
+		model, _ = model.Update(msg)
+		return model.(pkgmgrModel)
+	}
+	ctrlN := tea.KeyMsg{Type: tea.KeyCtrlN}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	m := send(destinationStatusMsg{template: config.Destination, status: inspectDestination(config, config.Destination)})
+	if m.destStatus == nil || m.destStatus.exists {
+		t.Fatalf("destStatus = %+v, want a missing destination", m.destStatus)
+	}
+	if view := m.View(); !strings.Contains(view, "doesn't exist yet") {
+		t.Errorf("global settings don't warn about the missing destination:\n%s", view)
+	}
+
+	// Results for an older template are ignored
+	m = send(destinationStatusMsg{template: "elsewhere/{library_name}", status: destinationStatus{exists: true}})
+	if m.destStatus.exists {
+		t.Error("a stale destination status replaced the current one")
+	}
+
+	model, _ = model.Update(ctrlN)
+	if info, err := os.Stat(filepath.Join("public", "vendor")); err != nil || !info.IsDir() {
+		t.Fatalf("ctrl+n didn't create the destination root: %v", err)
+	}
+}
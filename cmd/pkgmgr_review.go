@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// pkgmgrReviewKeyMap holds the keys of the review screen shown before saving
+type pkgmgrReviewKeyMap struct {
+	Save key.Binding
+	Back key.Binding
+}
+
+var pkgmgrReviewKeys = pkgmgrReviewKeyMap{
+	Save: key.NewBinding(key.WithKeys("enter", "y"), key.WithHelp("enter/y", "save & quit")),
+	Back: key.NewBinding(key.WithKeys("esc", "n"), key.WithHelp("esc/n", "back to the list")),
+}
+
+// settingChange is a config key whose value changed
+type settingChange struct {
+	key, from, to string
+}
+
+// libraryChange is a library whose settings changed
+type libraryChange struct {
+	name    string
+	changes []settingChange
+}
+
+// configChangeSummary lists what a pkgmgr session changed compared to the
+// config file
+type configChangeSummary struct {
+	added   []string
+	removed []string
+	edited  []libraryChange
+	global  []settingChange
+}
+
+func (s configChangeSummary) empty() bool {
+	return len(s.added) == 0 && len(s.removed) == 0 && len(s.edited) == 0 && len(s.global) == 0
+}
+
+// pkgmgrReview is the pending save shown on the review screen
+type pkgmgrReview struct {
+	summary configChangeSummary
+	diff    string // Colorized YAML diff, "" when the file wouldn't change
+	err     error  // Failure reading or encoding the config
+	view    viewport.Model
+}
+
+// newPkgmgrReview compares the edited config with the file at path
+func newPkgmgrReview(path string, config *frontend_config.FrontendConfig) *pkgmgrReview {
+	review := &pkgmgrReview{view: viewport.New(0, 0)}
+
+	newData, err := yaml.Marshal(config)
+	if err != nil {
+		review.err = fmt.Errorf("failed to marshal config: %w", err)
+		return review
+	}
+	oldData, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		review.err = fmt.Errorf("failed to read %s: %w", path, err)
+		return review
+	}
+
+	var old frontend_config.FrontendConfig
+	if err := yaml.Unmarshal(oldData, &old); err != nil {
+		review.err = fmt.Errorf("failed to parse %s: %w", path, err)
+		return review
+	}
+	review.summary = summarizeConfigChanges(&old, config)
+	review.diff = formatConfigDiff(string(oldData), string(newData))
+	return review
+}
+
+// summarizeConfigChanges lists the libraries added, removed and edited
+// between two configs, and the changed global settings
+func summarizeConfigChanges(old, new *frontend_config.FrontendConfig) configChangeSummary {
+	var summary configChangeSummary
+	for _, name := range sortedKeys(new.Libraries) {
+		oldLib, ok := old.Libraries[name]
+		switch {
+		case !ok:
+			summary.added = append(summary.added, name)
+		case !reflect.DeepEqual(oldLib, new.Libraries[name]):
+			summary.edited = append(summary.edited, libraryChange{name: name, changes: settingChanges(oldLib, new.Libraries[name])})
+		}
+	}
+	for _, name := range sortedKeys(old.Libraries) {
+		if _, ok := new.Libraries[name]; !ok {
+			summary.removed = append(summary.removed, name)
+		}
+	}
+
+	oldGlobal, newGlobal := *old, *new
+	oldGlobal.Libraries, newGlobal.Libraries = nil, nil
+	summary.global = settingChanges(oldGlobal, newGlobal)
+	return summary
+}
+
+// settingChanges compares the YAML keys of two values of the same type
+func settingChanges(old, new any) []settingChange {
+	oldFields, newFields := yamlFields(old), yamlFields(new)
+	keys := make(map[string]bool)
+	for k := range oldFields {
+		keys[k] = true
+	}
+	for k := range newFields {
+		keys[k] = true
+	}
+
+	var changes []settingChange
+	for _, k := range sortedKeys(keys) {
+		if !reflect.DeepEqual(oldFields[k], newFields[k]) {
+			changes = append(changes, settingChange{key: k, from: formatSettingValue(oldFields[k]), to: formatSettingValue(newFields[k])})
+		}
+	}
+	return changes
+}
+
+// yamlFields returns the top-level keys v is written with
+func yamlFields(v any) map[string]any {
+	fields := make(map[string]any)
+	if data, err := yaml.Marshal(v); err == nil {
+		yaml.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// formatSettingValue renders a value decoded from YAML on one line
+func formatSettingValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "(unset)"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatSettingValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = k + ": " + formatSettingValue(v[k])
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return fmt.Sprint(v)
+}
+
+// render lists the changes one per line
+func (s configChangeSummary) render() string {
+	if s.empty() {
+		return "No libraries or settings changed."
+	}
+
+	var b strings.Builder
+	names := func(label string, libs []string) {
+		if len(libs) > 0 {
+			fmt.Fprintf(&b, "%s (%d): %s\n", label, len(libs), strings.Join(libs, ", "))
+		}
+	}
+	names("Added", s.added)
+	names("Removed", s.removed)
+	if len(s.edited) > 0 {
+		fmt.Fprintf(&b, "Edited (%d):\n", len(s.edited))
+		for _, lib := range s.edited {
+			b.WriteString("  " + lib.name + "\n")
+			for _, c := range lib.changes {
+				fmt.Fprintf(&b, "    %s: %s → %s\n", c.key, c.from, c.to)
+			}
+		}
+	}
+	if len(s.global) > 0 {
+		b.WriteString("Global settings:\n")
+		for _, c := range s.global {
+			fmt.Fprintf(&b, "  %s: %s → %s\n", c.key, c.from, c.to)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// openReview shows the pending changes before saving
+func (m pkgmgrModel) openReview() (tea.Model, tea.Cmd) {
+	m.review = newPkgmgrReview(m.configPath, m.config)
+	m.setReviewContent()
+	m.view = viewReviewChanges
+	return m, nil
+}
+
+// setReviewContent sizes the review viewport to the window and fills it
+func (m *pkgmgrModel) setReviewContent() {
+	if m.review == nil {
+		return
+	}
+	m.review.view.Width = max(m.list.Width()-4, 20)
+	m.review.view.Height = max(m.list.Height()-2, 5)
+
+	content := m.review.summary.render()
+	switch {
+	case m.review.err != nil:
+		content = pkgmgrErrorStyle.Render("✗ " + m.review.err.Error())
+	case m.review.diff == "":
+		content += "\n\n" + fmt.Sprintf("%s is already up to date.", m.configPath)
+	default:
+		content += "\n\n" + pkgmgrLabelStyle.Render(m.configPath) + "\n" + m.review.diff
+	}
+	m.review.view.SetContent(content)
+}
+
+func (m pkgmgrModel) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, pkgmgrReviewKeys.Save):
+		if m.review.err != nil {
+			return m, nil
+		}
+		m.saved = true
+		m.quitting = true
+		return m, tea.Quit
+	case key.Matches(msg, pkgmgrReviewKeys.Back):
+		m.review = nil
+		m.view = viewLibraryList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.review.view, cmd = m.review.view.Update(msg)
+	return m, cmd
+}
+
+func (m pkgmgrModel) viewReviewRender() string {
+	var b strings.Builder
+	b.WriteString(pkgmgrHeaderStyle.Render("Review changes before saving") + "\n\n")
+	b.WriteString(m.review.view.View() + "\n\n")
+
+	action := "enter: save & quit"
+	if m.review.err != nil {
+		action = "can't save"
+	}
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%s • esc: back • ↑/↓: scroll (%3.f%%) • ?: help", action, m.review.view.ScrollPercent()*100)))
+	return b.String()
+}
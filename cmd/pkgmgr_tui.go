@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -12,6 +15,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+	"nexus-sds.com/smfaman/pkgs/tui"
 )
 
 // View modes
@@ -21,6 +25,9 @@ const (
 	viewAddLibrary
 	viewEditGlobal
 	viewVersionSelection
+	viewVersionError
+	viewPackageSearch
+	viewReviewChanges
 )
 
 // Edit fields for library
@@ -29,9 +36,22 @@ const (
 	editFieldCDN
 	editFieldFiles
 	editFieldOutputPath
+	editFieldTags
+	editFieldNotes
 	editFieldCount
 )
 
+// addFieldCount is the number of inputs in the add library form: name,
+// version, CDN, files and output path
+const addFieldCount = 5
+
+// Add form fields with their own keys
+const (
+	addFieldName    = 0
+	addFieldVersion = 1
+	addFieldCDN     = 2
+)
+
 // Global edit fields
 const (
 	globalFieldProjectName = iota
@@ -43,48 +63,60 @@ const (
 var (
 	pkgmgrTitleStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("205")).
 				MarginLeft(2)
 
 	pkgmgrItemStyle = lipgloss.NewStyle().
 			PaddingLeft(4)
 
 	pkgmgrSelectedItemStyle = lipgloss.NewStyle().
-				PaddingLeft(2).
-				Foreground(lipgloss.Color("170"))
+				PaddingLeft(2)
 
 	pkgmgrHelpStyle = lipgloss.NewStyle().
 			PaddingLeft(4).
-			PaddingBottom(1).
-			Foreground(lipgloss.Color("240"))
+			PaddingBottom(1)
 
 	pkgmgrHeaderStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("86")).
 				MarginBottom(1)
 
-	pkgmgrLabelStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("205"))
+	pkgmgrLabelStyle = lipgloss.NewStyle()
+
+	pkgmgrValueStyle = lipgloss.NewStyle()
 
-	pkgmgrValueStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("86"))
+	pkgmgrDescriptionStyle = lipgloss.NewStyle()
+
+	pkgmgrErrorStyle = lipgloss.NewStyle()
 )
 
 // Message types for async operations
 type versionsFetchedMsg struct {
+	cdn      string
 	versions []string
 	latest   string
 	err      error
 }
 
-type versionSelectedMsg struct {
-	version string
+// libraryInfoMsg carries the latest version and description of a library,
+// loaded in the background after the list is shown
+type libraryInfoMsg struct {
+	name string
+	info libraryInfo
+	err  error
+}
+
+// libraryInfo is the registry information shown next to a library in the list
+type libraryInfo struct {
+	latest      string
+	description string
 }
 
 type libraryItem struct {
 	name    string
 	version string
 	cdn     frontend_config.CDN
+	tags    []string
+	notes   string
+	info    libraryInfo
 }
 
 func (i libraryItem) FilterValue() string { return i.name }
@@ -100,10 +132,22 @@ func (d libraryItemDelegate) Render(w io.Writer, m list.Model, index int, listIt
 		return
 	}
 
-	str := fmt.Sprintf("%s@%s", i.name, i.version)
+	str := fmt.Sprintf("%s@%s", i.name, versionLabel(i.version))
 	if i.cdn != "" {
 		str = fmt.Sprintf("%s (%s)", str, i.cdn)
 	}
+	if libraryOutdated(i.version, i.info.latest) {
+		str = fmt.Sprintf("%s ⬆ %s", str, i.info.latest)
+	}
+	if len(i.tags) > 0 {
+		str = fmt.Sprintf("%s [%s]", str, strings.Join(i.tags, ", "))
+	}
+	// The team's own note says more than the registry description
+	if i.notes != "" {
+		str = fmt.Sprintf("%s  %s", str, pkgmgrDescriptionStyle.Render("📝 "+truncate(i.notes, 60)))
+	} else if i.info.description != "" {
+		str = fmt.Sprintf("%s  %s", str, pkgmgrDescriptionStyle.Render(truncate(i.info.description, 60)))
+	}
 
 	fn := pkgmgrItemStyle.Render
 	if index == m.Index() {
@@ -119,6 +163,70 @@ func (d libraryItemDelegate) Render(w io.Writer, m list.Model, index int, listIt
 	fmt.Fprint(w, fn(str))
 }
 
+// pkgmgrListKeyMap holds the library list's keys; list navigation keys come
+// from the list itself
+type pkgmgrListKeyMap struct {
+	Edit   key.Binding
+	Add    key.Binding
+	Delete key.Binding
+	Global key.Binding
+	Save   key.Binding
+	Quit   key.Binding
+}
+
+var pkgmgrListKeys = pkgmgrListKeyMap{
+	Edit:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "edit")),
+	Add:    key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
+	Delete: key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+	Global: key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "global settings")),
+	Save:   key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "review & save")),
+	Quit:   key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q/esc", "quit without saving")),
+}
+
+// pkgmgrFormKeyMap holds the keys of the edit library, add library and
+// global settings forms
+type pkgmgrFormKeyMap struct {
+	NextField   key.Binding
+	PrevField   key.Binding
+	NextCDN     key.Binding
+	PrevCDN     key.Binding
+	PickVersion key.Binding
+	FindPackage key.Binding
+	Submit      key.Binding
+	Cancel      key.Binding
+
+	CreateDestination key.Binding
+}
+
+var pkgmgrFormKeys = pkgmgrFormKeyMap{
+	NextField:   key.NewBinding(key.WithKeys("tab", "down", "enter"), key.WithHelp("tab/↓/enter", "next field")),
+	PrevField:   key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab/↑", "previous field")),
+	NextCDN:     key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next CDN (on the CDN field)")),
+	PrevCDN:     key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous CDN (on the CDN field)")),
+	PickVersion: key.NewBinding(key.WithKeys("v", "i"), key.WithHelp("v/i", "pick a version (on the version field)")),
+	FindPackage: key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "search for the package (on the name field)")),
+	Submit:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit (on the Submit button)")),
+	Cancel:      key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back to the list without saving")),
+
+	CreateDestination: key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "create the destination folder now")),
+}
+
+// pkgmgrVersionErrorKeyMap holds the keys of the version fetch error screen
+type pkgmgrVersionErrorKeyMap struct {
+	Retry        key.Binding
+	RetryNextCDN key.Binding
+	Back         key.Binding
+}
+
+var pkgmgrVersionErrorKeys = pkgmgrVersionErrorKeyMap{
+	Retry:        key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry")),
+	RetryNextCDN: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "retry on the next CDN")),
+	Back:         key.NewBinding(key.WithKeys("esc", "q", "enter"), key.WithHelp("esc/q/enter", "back to the form")),
+}
+
+// pkgmgrForceQuitKey quits pkgmgr from any view
+var pkgmgrForceQuitKey = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit without saving"))
+
 type pkgmgrModel struct {
 	config          *frontend_config.FrontendConfig
 	configPath      string
@@ -133,9 +241,17 @@ type pkgmgrModel struct {
 	saved           bool
 	successMsg      string
 	quitting        bool
-	versionSelector *pkgverModel
+	versionSelector *versionSelectorModel
+	packagePicker   *tui.PackagePicker // Package search opened from the add form
 	fetchingVersions bool
 	versionError    string
+	versionFetchErr error // Last version fetch failure, shown in the error view
+	libraryInfo     map[string]libraryInfo // Loaded in the background by Init
+	showHelp        bool
+	destStatus      *destinationStatus // Destination root being edited in global settings, nil until inspected
+	destError       string             // Last failure creating the destination root
+	review          *pkgmgrReview      // Pending save shown by the review screen
+	width, height   int                // Last window size, passed on to the package picker
 }
 
 func newPkgmgrModel(config *frontend_config.FrontendConfig, configPath string) pkgmgrModel {
@@ -145,6 +261,8 @@ func newPkgmgrModel(config *frontend_config.FrontendConfig, configPath string) p
 			name:    name,
 			version: libConfig.Version,
 			cdn:     libConfig.CDN,
+			tags:    libConfig.Tags,
+			notes:   libConfig.Notes,
 		})
 	}
 
@@ -161,28 +279,14 @@ func newPkgmgrModel(config *frontend_config.FrontendConfig, configPath string) p
 	// Set custom keybindings
 	l.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
-			key.NewBinding(
-				key.WithKeys("enter"),
-				key.WithHelp("enter", "edit"),
-			),
-			key.NewBinding(
-				key.WithKeys("a"),
-				key.WithHelp("a", "add"),
-			),
-			key.NewBinding(
-				key.WithKeys("d"),
-				key.WithHelp("d", "delete"),
-			),
-			key.NewBinding(
-				key.WithKeys("g"),
-				key.WithHelp("g", "global settings"),
-			),
-			key.NewBinding(
-				key.WithKeys("s"),
-				key.WithHelp("s", "save & quit"),
-			),
+			pkgmgrListKeys.Edit,
+			pkgmgrListKeys.Add,
+			pkgmgrListKeys.Delete,
+			pkgmgrListKeys.Global,
+			pkgmgrListKeys.Save,
 		}
 	}
+	useHelpOverlay(&l)
 
 	m := pkgmgrModel{
 		config:     config,
@@ -190,13 +294,82 @@ func newPkgmgrModel(config *frontend_config.FrontendConfig, configPath string) p
 		list:       l,
 		view:       viewLibraryList,
 		cdnOptions: []string{"", "unpkg", "cdnjs", "jsdelivr"},
+		libraryInfo: make(map[string]libraryInfo),
 	}
 
 	return m
 }
 
+// Init starts loading library information in the background so the list is
+// usable immediately and fills in as results arrive
 func (m pkgmgrModel) Init() tea.Cmd {
-	return nil
+	cmds := make([]tea.Cmd, 0, len(m.config.Libraries))
+	for _, name := range sortedKeys(m.config.Libraries) {
+		cmds = append(cmds, m.loadLibraryInfoCmd(name))
+	}
+	return tea.Batch(cmds...)
+}
+
+// libraryInfoSlots limits how many library lookups run at once (the
+// concurrency setting)
+var libraryInfoSlots = make(chan struct{}, defaultConcurrency)
+
+// fetchLibraryInfo looks up the latest version and description of a library
+// through the cached CDN APIs (overridable in tests)
+var fetchLibraryInfo = func(name string, cdn frontend_config.CDN) (libraryInfo, error) {
+	provider, err := frontend_mgr.GetProvider(string(cdn))
+	if err != nil {
+		return libraryInfo{}, err
+	}
+	versions, err := provider.Versions(name)
+	if err != nil {
+		return libraryInfo{}, err
+	}
+
+	info := libraryInfo{latest: frontend_mgr.LatestVersion(versions.Versions, versions.Latest(), false)}
+	if meta, err := frontend_mgr.FetchPackageMetadata(name, string(cdn)); err == nil {
+		info.description = meta.Description
+	}
+	return info, nil
+}
+
+// loadLibraryInfoCmd loads the information for a library asynchronously
+func (m pkgmgrModel) loadLibraryInfoCmd(name string) tea.Cmd {
+	libConfig, ok := m.config.Libraries[name]
+	if !ok {
+		return nil
+	}
+	cdn := libraryCDN(m.config, libConfig)
+
+	return func() tea.Msg {
+		libraryInfoSlots <- struct{}{}
+		defer func() { <-libraryInfoSlots }()
+
+		info, err := fetchLibraryInfo(name, cdn)
+		return libraryInfoMsg{name: name, info: info, err: err}
+	}
+}
+
+// libraryOutdated reports whether latest is newer than what spec allows.
+// Dist-tags, unparseable versions and prereleases tagged latest are never
+// reported as outdated.
+func libraryOutdated(spec, latest string) bool {
+	if !frontend_mgr.IsExactVersion(latest) || frontend_mgr.IsPrerelease(latest) {
+		return false
+	}
+
+	// An exact version is outdated when latest is above it; a range when
+	// latest falls outside it
+	newer := frontend_mgr.IsExactVersion(spec)
+	if newer {
+		spec = ">" + spec
+	}
+	r, err := frontend_mgr.ParseVersionRange(spec)
+	if err != nil {
+		return false
+	}
+	matched := r.MaxSatisfying([]string{latest}) != ""
+	return matched == newer
 }
 
 // fetchVersionsCmd fetches versions for a package asynchronously
@@ -204,51 +377,18 @@ func fetchVersionsCmd(packageName string, cdn frontend_config.CDN) tea.Cmd {
 	return func() tea.Msg {
 		var versions []string
 		var latest string
-		var err error
 
-		switch cdn {
-		case frontend_config.CDNUnpkg:
-			result, fetchErr := frontend_mgr.FetchUnpkgVersions(packageName)
-			if fetchErr != nil {
-				err = fetchErr
-			} else {
-				versions = make([]string, 0, len(result.Versions))
-				for ver := range result.Versions {
-					versions = append(versions, ver)
-				}
-				latest = result.DistTags["latest"]
-			}
-
-		case frontend_config.CDNCdnjs:
-			result, fetchErr := frontend_mgr.FetchCdnjsVersions(packageName)
-			if fetchErr != nil {
-				err = fetchErr
-			} else {
+		provider, err := frontend_mgr.GetProvider(string(cdn))
+		if err == nil {
+			var result *frontend_mgr.VersionList
+			if result, err = provider.Versions(packageName); err == nil {
 				versions = result.Versions
-				latest = result.Version
+				latest = result.Latest()
 			}
-
-		case frontend_config.CDNJsdelivr:
-			result, fetchErr := frontend_mgr.FetchJsdelivrVersions(packageName)
-			if fetchErr != nil {
-				err = fetchErr
-			} else {
-				versions = make([]string, 0, len(result.Versions))
-				for _, vInfo := range result.Versions {
-					versions = append(versions, vInfo.Version)
-				}
-				latest = result.Tags["latest"]
-			}
-
-		default:
-			err = fmt.Errorf("unsupported CDN: %s", cdn)
-		}
-
-		if err == nil && len(versions) > 0 {
-			versions = frontend_mgr.SortVersions(versions)
 		}
 
 		return versionsFetchedMsg{
+			cdn:      string(cdn),
 			versions: versions,
 			latest:   latest,
 			err:      err,
@@ -256,55 +396,146 @@ func fetchVersionsCmd(packageName string, cdn frontend_config.CDN) tea.Cmd {
 	}
 }
 
+// fetchErrorKind classifies why a CDN request failed
+type fetchErrorKind int
+
+const (
+	fetchErrorOther       fetchErrorKind = iota
+	fetchErrorNotFound                   // The package isn't published on the CDN
+	fetchErrorUnavailable                // The CDN is rate limiting or failing (429, 5xx)
+	fetchErrorRejected                   // Any other unexpected status
+	fetchErrorNetwork                    // The CDN couldn't be reached
+)
+
+// classifyFetchError determines the kind of a CDN request failure
+func classifyFetchError(err error) fetchErrorKind {
+	var statusErr *frontend_mgr.StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusNotFound:
+			return fetchErrorNotFound
+		case statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500:
+			return fetchErrorUnavailable
+		default:
+			return fetchErrorRejected
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return fetchErrorNetwork
+	}
+	return fetchErrorOther
+}
+
+// title is a short description of the failure
+func (k fetchErrorKind) title() string {
+	switch k {
+	case fetchErrorNotFound:
+		return "Package not found"
+	case fetchErrorUnavailable:
+		return "CDN unavailable"
+	case fetchErrorRejected:
+		return "Request rejected"
+	case fetchErrorNetwork:
+		return "Network error"
+	default:
+		return "Fetch failed"
+	}
+}
+
+// hint suggests what to do about the failure
+func (k fetchErrorKind) hint() string {
+	switch k {
+	case fetchErrorNotFound:
+		return "Check the package name, or try another CDN."
+	case fetchErrorUnavailable:
+		return "The CDN is busy or having problems. Retry in a moment."
+	case fetchErrorNetwork:
+		return "Check your internet connection and proxy settings, then retry."
+	default:
+		return "Retry, or try another CDN."
+	}
+}
+
 func (m pkgmgrModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.view == viewPackageSearch && m.packagePicker != nil {
+		return m.updatePackageSearch(msg)
+	}
+	if m.view == viewVersionSelection && m.versionSelector != nil {
+		return m.updateVersionSelection(msg)
+	}
+
 	switch msg := msg.(type) {
+	case destinationStatusMsg:
+		// Ignore results for a destination that has been edited since
+		if m.view == viewEditGlobal && msg.template == m.editInputs[globalFieldDestination].Value() {
+			m.destStatus = &msg.status
+		}
+		return m, nil
+
+	case libraryInfoMsg:
+		if msg.err != nil {
+			return m, nil // The list stays usable without the extra information
+		}
+		m.libraryInfo[msg.name] = msg.info
+		for index, listItem := range m.list.Items() {
+			if item, ok := listItem.(libraryItem); ok && item.name == msg.name {
+				item.info = msg.info
+				return m, m.list.SetItem(index, item)
+			}
+		}
+		return m, nil
+
 	case versionsFetchedMsg:
 		m.fetchingVersions = false
 		if msg.err != nil {
-			m.versionError = fmt.Sprintf("Error fetching versions: %v", msg.err)
-			m.view = viewAddLibrary
+			m.versionFetchErr = msg.err
+			m.view = viewVersionError
 			return m, nil
 		}
-		// Get package name from first input
-		packageName := m.editInputs[0].Value()
-		cdn := m.cdnOptions[m.cdnChoice]
-		if cdn == "" {
-			cdn = string(m.config.CDN)
-		}
-		if cdn == "" {
-			cdn = "unpkg"
-		}
-		selector := newPkgverModel(packageName, cdn, msg.latest, msg.versions)
+		// Preselect the version already typed into the form
+		selector := newVersionSelector(m.editInputs[addFieldName].Value(), msg.cdn, msg.latest, msg.versions, versionSelectorOptions{
+			Current: strings.TrimSpace(m.editInputs[addFieldVersion].Value()),
+		})
+		selector.embedded = true
+		selector.list.SetSize(m.list.Width(), m.list.Height())
 		m.versionSelector = &selector
 		m.view = viewVersionSelection
 		return m, nil
 
-	case versionSelectedMsg:
-		// Set the selected version in the version input field
-		if msg.version != "" {
-			m.editInputs[1].SetValue(msg.version)
-		}
-		m.view = viewAddLibrary
-		m.versionSelector = nil
-		m.focusIndex = 1 // Focus back on version field
-		return m, textinput.Blink
-
 	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
 		m.list.SetWidth(msg.Width)
 		m.list.SetHeight(msg.Height - 4)
-		if m.versionSelector != nil {
-			m.versionSelector.list.SetWidth(msg.Width)
-			m.versionSelector.list.SetHeight(msg.Height - 4)
+		m.setReviewContent()
+		return m, nil
+
+	case tea.MouseMsg:
+		switch {
+		case m.view == viewLibraryList:
+			updateListMouse(&m.list, libraryItemDelegate{}, listViewTop, msg)
+		case m.view == viewReviewChanges && m.review != nil:
+			m.review.view, _ = m.review.view.Update(msg)
 		}
 		return m, nil
 
 	case tea.KeyMsg:
 		// Global quit
-		if msg.String() == "ctrl+c" {
+		if key.Matches(msg, pkgmgrForceQuitKey) {
 			m.quitting = true
 			return m, tea.Quit
 		}
 
+		if m.showHelp {
+			m.showHelp = !helpClosed(msg)
+			return m, nil
+		}
+		if helpRequested(msg, m.typing()) {
+			m.showHelp = true
+			return m, nil
+		}
+
 		switch m.view {
 		case viewLibraryList:
 			return m.updateLibraryList(msg)
@@ -314,8 +545,10 @@ func (m pkgmgrModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateAddLibrary(msg)
 		case viewEditGlobal:
 			return m.updateEditGlobal(msg)
-		case viewVersionSelection:
-			return m.updateVersionSelection(msg)
+		case viewVersionError:
+			return m.updateVersionError(msg)
+		case viewReviewChanges:
+			return m.updateReview(msg)
 		}
 	}
 
@@ -325,18 +558,16 @@ func (m pkgmgrModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m pkgmgrModel) updateLibraryList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "esc":
+	switch {
+	case key.Matches(msg, pkgmgrListKeys.Quit):
 		m.quitting = true
 		return m, tea.Quit
 
-	case "s":
-		// Save and quit
-		m.saved = true
-		m.quitting = true
-		return m, tea.Quit
+	case key.Matches(msg, pkgmgrListKeys.Save):
+		// Review the changes, then save and quit
+		return m.openReview()
 
-	case "enter":
+	case key.Matches(msg, pkgmgrListKeys.Edit):
 		// Edit selected library
 		if item, ok := m.list.SelectedItem().(libraryItem); ok {
 			m.editingLib = item.name
@@ -346,26 +577,28 @@ func (m pkgmgrModel) updateLibraryList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, textinput.Blink
 		}
 
-	case "a":
+	case key.Matches(msg, pkgmgrListKeys.Add):
 		// Add new library
 		m.view = viewAddLibrary
 		m.focusIndex = 0
 		m.initAddLibraryInputs()
 		return m, textinput.Blink
 
-	case "d":
+	case key.Matches(msg, pkgmgrListKeys.Delete):
 		// Delete selected library
 		if item, ok := m.list.SelectedItem().(libraryItem); ok {
 			delete(m.config.Libraries, item.name)
 			m.refreshList()
 		}
 
-	case "g":
+	case key.Matches(msg, pkgmgrListKeys.Global):
 		// Edit global settings
 		m.view = viewEditGlobal
 		m.focusIndex = 0
 		m.initEditGlobalInputs()
-		return m, textinput.Blink
+		m.destStatus = nil
+		m.destError = ""
+		return m, tea.Batch(textinput.Blink, inspectDestinationCmd(m.config, m.config.Destination))
 	}
 
 	var cmd tea.Cmd
@@ -427,10 +660,30 @@ func (m *pkgmgrModel) initEditLibraryInputs(item libraryItem) {
 	t.Width = 50
 	t.Prompt = "> "
 	m.editInputs[editFieldOutputPath] = t
+
+	// Tags (comma-separated)
+	t = textinput.New()
+	t.Placeholder = "Tags (comma-separated)"
+	t.SetValue(strings.Join(libConfig.Tags, ", "))
+	t.Blur()
+	t.CharLimit = 200
+	t.Width = 50
+	t.Prompt = "> "
+	m.editInputs[editFieldTags] = t
+
+	// Notes
+	t = textinput.New()
+	t.Placeholder = "Why the library is configured this way"
+	t.SetValue(libConfig.Notes)
+	t.Blur()
+	t.CharLimit = 500
+	t.Width = 50
+	t.Prompt = "> "
+	m.editInputs[editFieldNotes] = t
 }
 
 func (m *pkgmgrModel) initAddLibraryInputs() {
-	m.editInputs = make([]textinput.Model, editFieldCount+1) // +1 for name field
+	m.editInputs = make([]textinput.Model, addFieldCount)
 
 	// Name
 	t := textinput.New()
@@ -526,258 +779,328 @@ func (m *pkgmgrModel) initEditGlobalInputs() {
 }
 
 func (m pkgmgrModel) updateEditLibrary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	switch {
+	case key.Matches(msg, pkgmgrFormKeys.Cancel):
 		m.view = viewLibraryList
 		return m, nil
 
-	case "tab", "shift+tab", "enter", "up", "down":
-		s := msg.String()
+	case key.Matches(msg, pkgmgrFormKeys.Submit) && m.focusIndex == editFieldCount:
+		m.saveLibraryEdit()
+		m.view = viewLibraryList
+		m.refreshList()
+		return m, m.loadLibraryInfoCmd(m.editingLib)
 
-		// Handle save
-		if s == "enter" && m.focusIndex == editFieldCount {
-			m.saveLibraryEdit()
-			m.view = viewLibraryList
-			m.refreshList()
-			return m, nil
-		}
+	case key.Matches(msg, pkgmgrFormKeys.NextField, pkgmgrFormKeys.PrevField):
+		return m, m.navigateForm(msg, editFieldCDN, editFieldCount)
+	}
 
-		// Handle CDN selection
-		if m.focusIndex == editFieldCDN {
-			if s == "up" {
-				m.cdnChoice--
-				if m.cdnChoice < 0 {
-					m.cdnChoice = len(m.cdnOptions) - 1
-				}
-				return m, nil
-			} else if s == "down" {
-				m.cdnChoice++
-				if m.cdnChoice >= len(m.cdnOptions) {
-					m.cdnChoice = 0
-				}
-				return m, nil
-			}
-		}
+	cmd := m.updateEditInputs(msg)
+	return m, cmd
+}
 
-		// Navigate fields
-		if s == "up" || s == "shift+tab" {
-			m.focusIndex--
-		} else {
-			m.focusIndex++
+func (m pkgmgrModel) updateAddLibrary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, pkgmgrFormKeys.Cancel):
+		m.view = viewLibraryList
+		m.versionError = ""
+		return m, nil
+
+	case key.Matches(msg, pkgmgrFormKeys.FindPackage) && m.focusIndex == addFieldName:
+		// Search for the package, starting with what was typed
+		var picker tea.Model = tui.NewPackagePicker(packagePickerOptions(m.editInputs[addFieldName].Value()))
+		if m.width > 0 {
+			// The picker only learns the window size on the next resize
+			picker, _ = picker.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
 		}
+		p := picker.(tui.PackagePicker)
+		m.packagePicker = &p
+		m.view = viewPackageSearch
+		m.versionError = ""
+		return m, p.Init()
 
-		if m.focusIndex > editFieldCount {
-			m.focusIndex = 0
-		} else if m.focusIndex < 0 {
-			m.focusIndex = editFieldCount
+	case key.Matches(msg, pkgmgrFormKeys.PickVersion) && m.focusIndex == addFieldVersion:
+		// Trigger interactive version selection when on version field
+		if m.editInputs[0].Value() == "" {
+			m.versionError = "Please enter a package name first"
+			return m, nil
 		}
+		return m, m.fetchVersions()
 
-		cmds := make([]tea.Cmd, len(m.editInputs))
-		for i := 0; i < len(m.editInputs); i++ {
-			if i == m.focusIndex {
-				cmds[i] = m.editInputs[i].Focus()
-				m.editInputs[i].PromptStyle = focusedStyle
-				m.editInputs[i].TextStyle = focusedStyle
-			} else {
-				m.editInputs[i].Blur()
-				m.editInputs[i].PromptStyle = blurredStyle
-				m.editInputs[i].TextStyle = noStyle
-			}
+	case key.Matches(msg, pkgmgrFormKeys.Submit) && m.focusIndex == addFieldCount:
+		if m.saveNewLibrary() {
+			m.view = viewLibraryList
+			m.refreshList()
+			return m, m.loadLibraryInfoCmd(m.editInputs[0].Value())
 		}
+		return m, nil
 
-		return m, tea.Batch(cmds...)
+	case key.Matches(msg, pkgmgrFormKeys.NextField, pkgmgrFormKeys.PrevField):
+		return m, m.navigateForm(msg, addFieldCDN, addFieldCount)
 	}
 
 	cmd := m.updateEditInputs(msg)
 	return m, cmd
 }
 
-func (m pkgmgrModel) updateAddLibrary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
-		m.view = viewLibraryList
-		m.versionError = ""
-		return m, nil
+// updatePackageSearch passes messages to the package search opened from
+// the add form, and fills in the form with the package picked there
+func (m pkgmgrModel) updatePackageSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tui.PickedMsg:
+		m.view = viewAddLibrary
+		m.packagePicker = nil
+		if msg.Result == nil {
+			return m, textinput.Blink
+		}
 
-	case "v", "i":
-		// Trigger interactive version selection when on version field
-		if m.focusIndex == 1 {
-			packageName := m.editInputs[0].Value()
-			if packageName == "" {
-				m.versionError = "Please enter a package name first"
-				return m, nil
+		m.editInputs[addFieldName].SetValue(msg.Result.Name)
+		m.editInputs[addFieldVersion].SetValue(msg.Result.Version)
+		for i, option := range m.cdnOptions {
+			if option != "" && option == msg.Result.CDN {
+				m.cdnChoice = i
 			}
+		}
+		// Continue with the version, which can still be changed
+		return m, m.focusField(addFieldVersion)
 
-			// Determine CDN
-			cdn := m.cdnOptions[m.cdnChoice]
-			if cdn == "" {
-				cdn = string(m.config.CDN)
-			}
-			if cdn == "" {
-				cdn = "unpkg"
-			}
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
 
-			m.fetchingVersions = true
-			m.versionError = ""
-			return m, fetchVersionsCmd(packageName, frontend_config.CDN(cdn))
+	case tea.KeyMsg:
+		if key.Matches(msg, pkgmgrForceQuitKey) {
+			m.quitting = true
+			return m, tea.Quit
 		}
+	}
 
-	case "tab", "shift+tab", "enter", "up", "down":
-		s := msg.String()
+	model, cmd := m.packagePicker.Update(msg)
+	picker := model.(tui.PackagePicker)
+	m.packagePicker = &picker
+	return m, cmd
+}
 
-		// Handle save
-		if s == "enter" && m.focusIndex == editFieldCount+1 {
-			if m.saveNewLibrary() {
-				m.view = viewLibraryList
-				m.refreshList()
-			}
-			return m, nil
+// navigateForm moves the focus between the fields of a form with fieldCount
+// inputs and a Submit button, or changes the CDN while the CDN field
+// (cdnField) has the focus
+func (m *pkgmgrModel) navigateForm(msg tea.KeyMsg, cdnField, fieldCount int) tea.Cmd {
+	if m.focusIndex == cdnField {
+		switch {
+		case key.Matches(msg, pkgmgrFormKeys.PrevCDN):
+			m.cdnChoice = (m.cdnChoice + len(m.cdnOptions) - 1) % len(m.cdnOptions)
+			return nil
+		case key.Matches(msg, pkgmgrFormKeys.NextCDN):
+			m.cdnChoice = (m.cdnChoice + 1) % len(m.cdnOptions)
+			return nil
 		}
+	}
 
-		// Handle CDN selection (field index 2)
-		if m.focusIndex == 2 {
-			if s == "up" {
-				m.cdnChoice--
-				if m.cdnChoice < 0 {
-					m.cdnChoice = len(m.cdnOptions) - 1
-				}
-				return m, nil
-			} else if s == "down" {
-				m.cdnChoice++
-				if m.cdnChoice >= len(m.cdnOptions) {
-					m.cdnChoice = 0
-				}
-				return m, nil
-			}
-		}
+	if key.Matches(msg, pkgmgrFormKeys.PrevField) {
+		m.focusIndex--
+	} else {
+		m.focusIndex++
+	}
 
-		// Navigate fields
-		if s == "up" || s == "shift+tab" {
-			m.focusIndex--
+	if m.focusIndex > fieldCount {
+		m.focusIndex = 0
+	} else if m.focusIndex < 0 {
+		m.focusIndex = fieldCount
+	}
+	return m.focusField(m.focusIndex)
+}
+
+// focusField moves the focus to a form field (or the Submit button past the
+// last input)
+func (m *pkgmgrModel) focusField(index int) tea.Cmd {
+	m.focusIndex = index
+	cmds := make([]tea.Cmd, len(m.editInputs))
+	for i := 0; i < len(m.editInputs); i++ {
+		if i == m.focusIndex {
+			cmds[i] = m.editInputs[i].Focus()
+			m.editInputs[i].PromptStyle = focusedStyle
+			m.editInputs[i].TextStyle = focusedStyle
 		} else {
-			m.focusIndex++
+			m.editInputs[i].Blur()
+			m.editInputs[i].PromptStyle = blurredStyle
+			m.editInputs[i].TextStyle = noStyle
 		}
+	}
 
-		if m.focusIndex > editFieldCount+1 {
-			m.focusIndex = 0
-		} else if m.focusIndex < 0 {
-			m.focusIndex = editFieldCount + 1
-		}
+	return tea.Batch(cmds...)
+}
 
-		cmds := make([]tea.Cmd, len(m.editInputs))
-		for i := 0; i < len(m.editInputs); i++ {
-			if i == m.focusIndex {
-				cmds[i] = m.editInputs[i].Focus()
-				m.editInputs[i].PromptStyle = focusedStyle
-				m.editInputs[i].TextStyle = focusedStyle
-			} else {
-				m.editInputs[i].Blur()
-				m.editInputs[i].PromptStyle = blurredStyle
-				m.editInputs[i].TextStyle = noStyle
-			}
+// versionCDN returns the CDN versions are fetched from for a new library:
+// the selected CDN, else the global default, else unpkg
+func (m pkgmgrModel) versionCDN() string {
+	cdn := m.cdnOptions[m.cdnChoice]
+	if cdn == "" {
+		cdn = string(m.config.CDN)
+	}
+	if cdn == "" {
+		cdn = "unpkg"
+	}
+	return cdn
+}
+
+// fetchVersions starts fetching versions of the package being added
+func (m *pkgmgrModel) fetchVersions() tea.Cmd {
+	m.fetchingVersions = true
+	m.versionError = ""
+	m.versionFetchErr = nil
+	return fetchVersionsCmd(m.editInputs[0].Value(), frontend_config.CDN(m.versionCDN()))
+}
+
+// nextCDNChoice returns the CDN option after the one versions are currently
+// fetched from
+func (m pkgmgrModel) nextCDNChoice() int {
+	current := m.versionCDN()
+	for i := 1; i < len(m.cdnOptions); i++ {
+		choice := (m.cdnChoice + i) % len(m.cdnOptions)
+		if option := m.cdnOptions[choice]; option != "" && option != current {
+			return choice
 		}
+	}
+	return m.cdnChoice
+}
 
-		return m, tea.Batch(cmds...)
+func (m pkgmgrModel) updateVersionError(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, pkgmgrVersionErrorKeys.Retry):
+		m.view = viewAddLibrary
+		return m, m.fetchVersions()
+
+	case key.Matches(msg, pkgmgrVersionErrorKeys.RetryNextCDN):
+		m.cdnChoice = m.nextCDNChoice()
+		m.view = viewAddLibrary
+		return m, m.fetchVersions()
+
+	case key.Matches(msg, pkgmgrVersionErrorKeys.Back):
+		m.view = viewAddLibrary
+		m.versionFetchErr = nil
+		return m, nil
 	}
 
-	cmd := m.updateEditInputs(msg)
-	return m, cmd
+	return m, nil
 }
 
-func (m pkgmgrModel) updateVersionSelection(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "q":
-		// Cancel version selection
+// updateVersionSelection passes messages to the version selector until it
+// sends the picked version back
+func (m pkgmgrModel) updateVersionSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case versionSelectedMsg:
+		// Set the selected version in the version input field
+		if msg.version != "" {
+			m.editInputs[addFieldVersion].SetValue(msg.version)
+		}
 		m.view = viewAddLibrary
 		m.versionSelector = nil
-		return m, nil
+		return m, m.focusField(addFieldVersion)
 
-	case "enter":
-		// Select version
-		if m.versionSelector != nil {
-			if item, ok := m.versionSelector.list.SelectedItem().(versionItem); ok {
-				return m, func() tea.Msg {
-					return versionSelectedMsg{version: item.version}
-				}
-			}
-		}
-		return m, nil
-	}
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
 
-	// Update the version selector
-	if m.versionSelector != nil {
-		var cmd tea.Cmd
-		m.versionSelector.list, cmd = m.versionSelector.list.Update(msg)
-		return m, cmd
+	case tea.KeyMsg:
+		if key.Matches(msg, pkgmgrForceQuitKey) {
+			m.quitting = true
+			return m, tea.Quit
+		}
 	}
 
-	return m, nil
+	model, cmd := m.versionSelector.Update(msg)
+	selector := model.(versionSelectorModel)
+	m.versionSelector = &selector
+	return m, cmd
 }
 
 func (m pkgmgrModel) updateEditGlobal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	switch {
+	case key.Matches(msg, pkgmgrFormKeys.Cancel):
 		m.view = viewLibraryList
 		return m, nil
 
-	case "tab", "shift+tab", "enter", "up", "down":
-		s := msg.String()
+	case key.Matches(msg, pkgmgrFormKeys.Submit) && m.focusIndex == globalFieldCount:
+		m.saveGlobalEdit()
+		m.view = viewLibraryList
+		return m, nil
 
-		// Handle save
-		if s == "enter" && m.focusIndex == globalFieldCount {
-			m.saveGlobalEdit()
-			m.view = viewLibraryList
+	case key.Matches(msg, pkgmgrFormKeys.CreateDestination):
+		if m.destStatus == nil || m.destStatus.exists {
 			return m, nil
 		}
-
-		// Handle CDN selection
-		if m.focusIndex == globalFieldCDN {
-			if s == "up" {
-				m.cdnChoice--
-				if m.cdnChoice < 0 {
-					m.cdnChoice = len(m.cdnOptions) - 1
-				}
-				return m, nil
-			} else if s == "down" {
-				m.cdnChoice++
-				if m.cdnChoice >= len(m.cdnOptions) {
-					m.cdnChoice = 0
-				}
-				return m, nil
-			}
+		template := m.editInputs[globalFieldDestination].Value()
+		m.destError = ""
+		if err := createDestinationRoot(template); err != nil {
+			m.destError = err.Error()
+			return m, nil
 		}
+		return m, inspectDestinationCmd(m.config, template)
 
-		// Navigate fields
-		if s == "up" || s == "shift+tab" {
-			m.focusIndex--
-		} else {
-			m.focusIndex++
-		}
+	case key.Matches(msg, pkgmgrFormKeys.NextField, pkgmgrFormKeys.PrevField):
+		return m, m.navigateForm(msg, globalFieldCDN, globalFieldCount)
+	}
 
-		if m.focusIndex > globalFieldCount {
-			m.focusIndex = 0
-		} else if m.focusIndex < 0 {
-			m.focusIndex = globalFieldCount
-		}
+	destination := m.editInputs[globalFieldDestination].Value()
+	cmd := m.updateEditInputs(msg)
+	if template := m.editInputs[globalFieldDestination].Value(); template != destination {
+		m.destStatus = nil
+		m.destError = ""
+		cmd = tea.Batch(cmd, inspectDestinationCmd(m.config, template))
+	}
+	return m, cmd
+}
 
-		cmds := make([]tea.Cmd, len(m.editInputs))
-		for i := 0; i < len(m.editInputs); i++ {
-			if i == m.focusIndex {
-				cmds[i] = m.editInputs[i].Focus()
-				m.editInputs[i].PromptStyle = focusedStyle
-				m.editInputs[i].TextStyle = focusedStyle
-			} else {
-				m.editInputs[i].Blur()
-				m.editInputs[i].PromptStyle = blurredStyle
-				m.editInputs[i].TextStyle = noStyle
-			}
-		}
+// typing reports whether keys go to a text field, where '?' is typed
+// rather than opening the help overlay
+func (m pkgmgrModel) typing() bool {
+	switch m.view {
+	case viewLibraryList:
+		return m.list.FilterState() == list.Filtering
+	case viewEditLibrary:
+		return m.focusIndex < editFieldCount && m.focusIndex != editFieldCDN
+	case viewAddLibrary:
+		return m.focusIndex < addFieldCount && m.focusIndex != addFieldCDN
+	case viewEditGlobal:
+		return m.focusIndex < globalFieldCount && m.focusIndex != globalFieldCDN
+	}
+	return false
+}
 
-		return m, tea.Batch(cmds...)
+// helpView renders the help overlay for the current view
+func (m pkgmgrModel) helpView() string {
+	general := generalHelpSection(pkgmgrForceQuitKey)
+	form := func(title string, extra ...key.Binding) string {
+		bindings := []key.Binding{pkgmgrFormKeys.NextField, pkgmgrFormKeys.PrevField, pkgmgrFormKeys.NextCDN, pkgmgrFormKeys.PrevCDN}
+		bindings = append(bindings, extra...)
+		bindings = append(bindings, pkgmgrFormKeys.Submit, pkgmgrFormKeys.Cancel)
+		return renderHelpOverlay(title, helpSection{title: "Form", bindings: bindings}, general)
 	}
 
-	cmd := m.updateEditInputs(msg)
-	return m, cmd
+	switch m.view {
+	case viewEditLibrary:
+		return form("edit library")
+	case viewAddLibrary:
+		return form("add library", pkgmgrFormKeys.FindPackage, pkgmgrFormKeys.PickVersion)
+	case viewEditGlobal:
+		return form("global settings", pkgmgrFormKeys.CreateDestination)
+	case viewVersionError:
+		keys := pkgmgrVersionErrorKeys
+		return renderHelpOverlay("version fetch error", helpSection{title: "Error", bindings: []key.Binding{keys.Retry, keys.RetryNextCDN, keys.Back}}, general)
+	case viewReviewChanges:
+		scroll := m.review.view.KeyMap
+		return renderHelpOverlay("review changes",
+			helpSection{title: "Review", bindings: []key.Binding{pkgmgrReviewKeys.Save, pkgmgrReviewKeys.Back}},
+			helpSection{title: "Scrolling", bindings: []key.Binding{scroll.Up, scroll.Down, scroll.PageUp, scroll.PageDown}},
+			general)
+	}
+
+	keys := pkgmgrListKeys
+	return renderHelpOverlay("package manager", helpSection{
+		title:    "Libraries",
+		bindings: []key.Binding{keys.Edit, keys.Add, keys.Delete, keys.Global, keys.Save, keys.Quit},
+	}, listHelpSection(m.list), general)
 }
 
 func (m *pkgmgrModel) updateEditInputs(msg tea.Msg) tea.Cmd {
@@ -785,7 +1108,7 @@ func (m *pkgmgrModel) updateEditInputs(msg tea.Msg) tea.Cmd {
 
 	for i := range m.editInputs {
 		if i == m.focusIndex && (m.view == viewEditLibrary && i != editFieldCDN ||
-			m.view == viewAddLibrary && i != 2 ||
+			m.view == viewAddLibrary && i != addFieldCDN ||
 			m.view == viewEditGlobal && i != globalFieldCDN) {
 			m.editInputs[i], cmds[i] = m.editInputs[i].Update(msg)
 		}
@@ -814,10 +1137,23 @@ func (m *pkgmgrModel) saveLibraryEdit() {
 	}
 
 	libConfig.OutputPath = m.editInputs[editFieldOutputPath].Value()
+	libConfig.Tags = splitTags(m.editInputs[editFieldTags].Value())
+	libConfig.Notes = strings.TrimSpace(m.editInputs[editFieldNotes].Value())
 
 	m.config.Libraries[m.editingLib] = libConfig
 }
 
+// splitTags splits a comma-separated tag list, dropping empty entries
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 func (m *pkgmgrModel) saveNewLibrary() bool {
 	name := m.editInputs[0].Value()
 	if name == "" {
@@ -868,6 +1204,9 @@ func (m *pkgmgrModel) refreshList() {
 			name:    name,
 			version: libConfig.Version,
 			cdn:     libConfig.CDN,
+			tags:    libConfig.Tags,
+			notes:   libConfig.Notes,
+			info:    m.libraryInfo[name],
 		})
 	}
 	m.list.SetItems(items)
@@ -875,11 +1214,12 @@ func (m *pkgmgrModel) refreshList() {
 
 func (m pkgmgrModel) View() string {
 	if m.quitting {
-		if m.saved {
-			return successStyle.Render("✓ Config saved successfully!\n")
-		}
+		// runPkgmgr reports the save once the alt screen is gone
 		return ""
 	}
+	if m.showHelp {
+		return m.helpView()
+	}
 
 	switch m.view {
 	case viewLibraryList:
@@ -892,6 +1232,14 @@ func (m pkgmgrModel) View() string {
 		return m.viewEditGlobalRender()
 	case viewVersionSelection:
 		return m.viewVersionSelectionRender()
+	case viewVersionError:
+		return m.viewVersionErrorRender()
+	case viewReviewChanges:
+		return m.viewReviewRender()
+	case viewPackageSearch:
+		if m.packagePicker != nil {
+			return m.packagePicker.View()
+		}
 	}
 
 	return ""
@@ -960,6 +1308,22 @@ func (m pkgmgrModel) viewEditLibraryRender() string {
 	}
 	b.WriteString(m.editInputs[editFieldOutputPath].View() + "\n\n")
 
+	// Tags
+	if m.focusIndex == editFieldTags {
+		b.WriteString(focusedStyle.Render("Tags:") + "\n")
+	} else {
+		b.WriteString(blurredStyle.Render("Tags:") + "\n")
+	}
+	b.WriteString(m.editInputs[editFieldTags].View() + "\n\n")
+
+	// Notes
+	if m.focusIndex == editFieldNotes {
+		b.WriteString(focusedStyle.Render("Notes:") + "\n")
+	} else {
+		b.WriteString(blurredStyle.Render("Notes:") + "\n")
+	}
+	b.WriteString(m.editInputs[editFieldNotes].View() + "\n\n")
+
 	// Save button
 	button := blurredButton
 	if m.focusIndex == editFieldCount {
@@ -967,7 +1331,7 @@ func (m pkgmgrModel) viewEditLibraryRender() string {
 	}
 	b.WriteString(button + "\n\n")
 
-	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: save • esc: cancel"))
+	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: save • esc: cancel • f1: help"))
 
 	return b.String()
 }
@@ -984,7 +1348,11 @@ func (m pkgmgrModel) viewAddLibraryRender() string {
 	} else {
 		b.WriteString(blurredStyle.Render("Package Name:") + "\n")
 	}
-	b.WriteString(m.editInputs[0].View() + "\n\n")
+	b.WriteString(m.editInputs[0].View() + "\n")
+	if m.focusIndex == 0 {
+		b.WriteString(helpStyle.Render("  Press ctrl+f to search for the package") + "\n")
+	}
+	b.WriteString("\n")
 
 	// Version
 	if m.focusIndex == 1 {
@@ -1000,7 +1368,7 @@ func (m pkgmgrModel) viewAddLibraryRender() string {
 		b.WriteString(helpStyle.Render("  Fetching versions...") + "\n")
 	}
 	if m.versionError != "" {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  "+m.versionError) + "\n")
+		b.WriteString(pkgmgrErrorStyle.Render("  "+m.versionError) + "\n")
 	}
 	b.WriteString("\n")
 
@@ -1051,12 +1419,12 @@ func (m pkgmgrModel) viewAddLibraryRender() string {
 
 	// Add button
 	button := blurredButton
-	if m.focusIndex == editFieldCount+1 {
+	if m.focusIndex == addFieldCount {
 		button = focusedButton
 	}
 	b.WriteString(button + "\n\n")
 
-	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: add • esc: cancel"))
+	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: add • esc: cancel • f1: help"))
 
 	return b.String()
 }
@@ -1082,7 +1450,18 @@ func (m pkgmgrModel) viewEditGlobalRender() string {
 		b.WriteString(blurredStyle.Render("Destination Path:") + "\n")
 	}
 	b.WriteString(m.editInputs[globalFieldDestination].View() + "\n")
-	b.WriteString(helpStyle.Render("  Use {library_name} as placeholder") + "\n\n")
+	b.WriteString(helpStyle.Render("  Use {library_name} as placeholder") + "\n")
+	switch {
+	case m.destError != "":
+		b.WriteString(errorStyle.Render("  ✗ "+m.destError) + "\n")
+	case m.destStatus == nil:
+		b.WriteString(helpStyle.Render("  Checking destination...") + "\n")
+	case m.destStatus.exists && !m.destStatus.notDir:
+		b.WriteString(successStyle.Render("  "+m.destStatus.describe()) + "\n")
+	default:
+		b.WriteString(errorStyle.Render("  "+m.destStatus.describe()) + "\n")
+	}
+	b.WriteString("\n")
 
 	// CDN
 	if m.focusIndex == globalFieldCDN {
@@ -1120,14 +1499,33 @@ func (m pkgmgrModel) viewEditGlobalRender() string {
 	}
 	b.WriteString(button + "\n\n")
 
-	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: save • esc: cancel"))
+	b.WriteString(helpStyle.Render("tab/shift+tab: navigate • up/down: select CDN • enter: save • esc: cancel • f1: help"))
+
+	return b.String()
+}
+
+func (m pkgmgrModel) viewVersionErrorRender() string {
+	var b strings.Builder
+
+	cdn := m.versionCDN()
+	b.WriteString(pkgmgrHeaderStyle.Render(fmt.Sprintf("Couldn't fetch versions of %s", m.editInputs[0].Value())) + "\n")
+
+	kind := classifyFetchError(m.versionFetchErr)
+	b.WriteString(errorStyle.Render(fmt.Sprintf("✗ %s (%s)", kind.title(), cdn)) + "\n\n")
+	b.WriteString("  " + kind.hint() + "\n\n")
+	if m.versionFetchErr != nil {
+		b.WriteString(helpStyle.Render("  "+m.versionFetchErr.Error()) + "\n\n")
+	}
+
+	next := m.cdnOptions[m.nextCDNChoice()]
+	b.WriteString(helpStyle.Render(fmt.Sprintf("r: retry • c: retry on %s • esc: back • ?: help", next)))
 
 	return b.String()
 }
 
 func (m pkgmgrModel) viewVersionSelectionRender() string {
 	if m.versionSelector != nil {
-		return "\n" + m.versionSelector.list.View()
+		return m.versionSelector.View()
 	}
 	return "\nLoading versions..."
 }
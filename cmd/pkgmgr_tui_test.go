@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+	"nexus-sds.com/smfaman/pkgs/tui"
+)
+
+func TestLibraryOutdated(t *testing.T) {
+	tests := []struct {
+		spec, latest string
+		want         bool
+	}{
+		{"3.7.1", "3.7.1", false},
+		{"3.7.0", "3.7.1", true},
+		{"4.0.0-beta.1", "3.7.1", false},
+		{"^3.6.0", "3.7.1", false},
+		{"^2.0.0", "3.7.1", true},
+		{"latest", "3.7.1", false},
+		{"3.7.0", "", false},
+		{"3.7.1", "4.0.0-rc.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := libraryOutdated(tt.spec, tt.latest); got != tt.want {
+			t.Errorf("libraryOutdated(%q, %q) = %v, want %v", tt.spec, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestPkgmgrLibraryInfo(t *testing.T) {
+	config := &frontend_config.FrontendConfig{
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "3.6.0"},
+			"vue":    {Version: "3.4.0", CDN: frontend_config.CDNJsdelivr},
+		},
+	}
+
+	origFetch := fetchLibraryInfo
+	defer func() { fetchLibraryInfo = origFetch }()
+
+	var requested []string
+	fetchLibraryInfo = func(name string, cdn frontend_config.CDN) (libraryInfo, error) {
+		requested = append(requested, name+"@"+string(cdn))
+		if name == "vue" {
+			return libraryInfo{}, errors.New("offline")
+		}
+		return libraryInfo{latest: "3.7.1", description: "JavaScript library for DOM operations"}, nil
+	}
+
+	m := newPkgmgrModel(config, "frontend.yaml")
+	cmd := m.Init()
+	if cmd == nil {
+		t.Fatal("Init() returned no command")
+	}
+
+	// Run each library lookup and feed the results back into the model
+	msgs := []libraryInfoMsg{
+		m.loadLibraryInfoCmd("jquery")().(libraryInfoMsg),
+		m.loadLibraryInfoCmd("vue")().(libraryInfoMsg),
+	}
+	if requested[0] != "jquery@unpkg" || requested[1] != "vue@jsdelivr" {
+		t.Errorf("requested = %v, want jquery@unpkg and vue@jsdelivr", requested)
+	}
+
+	model := m
+	for _, msg := range msgs {
+		updated, _ := model.Update(msg)
+		model = updated.(pkgmgrModel)
+	}
+
+	for _, listItem := range model.list.Items() {
+		item := listItem.(libraryItem)
+		switch item.name {
+		case "jquery":
+			if item.info.latest != "3.7.1" || item.info.description == "" {
+				t.Errorf("jquery info = %+v, want latest 3.7.1 with description", item.info)
+			}
+		case "vue":
+			if item.info != (libraryInfo{}) {
+				t.Errorf("vue info = %+v, want none after a failed lookup", item.info)
+			}
+		}
+	}
+
+	// Information survives the list being rebuilt after an edit
+	model.refreshList()
+	for _, listItem := range model.list.Items() {
+		if item := listItem.(libraryItem); item.name == "jquery" && item.info.latest != "3.7.1" {
+			t.Errorf("jquery info lost after refreshList: %+v", item.info)
+		}
+	}
+}
+
+func TestClassifyFetchError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want fetchErrorKind
+	}{
+		{"not found", &frontend_mgr.StatusError{Source: "npm registry API", StatusCode: 404}, fetchErrorNotFound},
+		{"wrapped not found", fmt.Errorf("failed: %w", &frontend_mgr.StatusError{StatusCode: 404}), fetchErrorNotFound},
+		{"rate limited", &frontend_mgr.StatusError{StatusCode: 429}, fetchErrorUnavailable},
+		{"server error", &frontend_mgr.StatusError{StatusCode: 503}, fetchErrorUnavailable},
+		{"forbidden", &frontend_mgr.StatusError{StatusCode: 403}, fetchErrorRejected},
+		{"offline", &url.Error{Op: "Get", URL: "https://registry.npmjs.org/x", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, fetchErrorNetwork},
+		{"other", errors.New("failed to decode npm registry response"), fetchErrorOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFetchError(tt.err); got != tt.want {
+				t.Errorf("classifyFetchError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPkgmgrVersionFetchError(t *testing.T) {
+	config := &frontend_config.FrontendConfig{Libraries: map[string]frontend_config.LibraryConfig{}}
+	m := newPkgmgrModel(config, "frontend.yaml")
+	m.view = viewAddLibrary
+	m.initAddLibraryInputs()
+	m.editInputs[0].SetValue("left-pad")
+
+	updated, _ := m.Update(versionsFetchedMsg{cdn: "unpkg", err: &frontend_mgr.StatusError{StatusCode: 404}})
+	m = updated.(pkgmgrModel)
+	if m.view != viewVersionError {
+		t.Fatalf("view = %d, want the version error view", m.view)
+	}
+	if !strings.Contains(m.View(), "Package not found") {
+		t.Errorf("error view doesn't explain the failure:\n%s", m.View())
+	}
+
+	// "c" moves on to the next CDN and retries
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(pkgmgrModel)
+	if cmd == nil || !m.fetchingVersions || m.view != viewAddLibrary {
+		t.Errorf("c didn't retry: view=%d fetching=%v", m.view, m.fetchingVersions)
+	}
+	if got := m.versionCDN(); got != "cdnjs" {
+		t.Errorf("versionCDN() after c = %q, want cdnjs", got)
+	}
+
+	// "r" retries on the same CDN
+	m.view = viewVersionError
+	m.fetchingVersions = false
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(pkgmgrModel)
+	if cmd == nil || !m.fetchingVersions || m.versionCDN() != "cdnjs" {
+		t.Errorf("r didn't retry on cdnjs: fetching=%v cdn=%s", m.fetchingVersions, m.versionCDN())
+	}
+}
+
+func TestPkgmgrTagsAndNotes(t *testing.T) {
+	config := &frontend_config.FrontendConfig{
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "1.12.4", Tags: []string{"ie11"}, Notes: "pinned for IE11 support", Groups: []string{"legacy"}},
+		},
+	}
+
+	m := newPkgmgrModel(config, "frontend.yaml")
+	item := m.list.Items()[0].(libraryItem)
+
+	var b strings.Builder
+	libraryItemDelegate{}.Render(&b, m.list, 0, item)
+	if !strings.Contains(b.String(), "[ie11]") || !strings.Contains(b.String(), "pinned for IE11 support") {
+		t.Errorf("rendered item = %q, want tags and notes", b.String())
+	}
+
+	m.editingLib = "jquery"
+	m.initEditLibraryInputs(item)
+	m.editInputs[editFieldVersion].SetValue("1.12.5")
+	m.editInputs[editFieldTags].SetValue("ie11, , legacy-browsers")
+	m.saveLibraryEdit()
+
+	got := m.config.Libraries["jquery"]
+	if got.Version != "1.12.5" || strings.Join(got.Tags, ",") != "ie11,legacy-browsers" || got.Notes != "pinned for IE11 support" {
+		t.Errorf("edited library = %+v", got)
+	}
+	if len(got.Groups) != 1 {
+		t.Errorf("editing should keep the groups, got %v", got.Groups)
+	}
+}
+
+func TestPkgmgrFindPackage(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	origSearch := pickerSearch
+	pickerSearch = func(query, cdn string, limit int, filter frontend_mgr.SearchFilter, exact bool) ([]frontend_mgr.SearchResult, error) {
+		results := make([]frontend_mgr.SearchResult, 30)
+		for i := range results {
+			results[i] = frontend_mgr.SearchResult{Name: fmt.Sprintf("react-%d", i), Version: "1.0.0", CDN: "npm"}
+		}
+		return results, nil
+	}
+	defer func() { pickerSearch = origSearch }()
+
+	m := newPkgmgrModel(&frontend_config.FrontendConfig{Libraries: map[string]frontend_config.LibraryConfig{}}, "frontend.yaml")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 15})
+	m = updated.(pkgmgrModel)
+	m.initAddLibraryInputs()
+	m.view = viewAddLibrary
+	m.editInputs[addFieldName].SetValue("reac")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updated.(pkgmgrModel)
+	if m.view != viewPackageSearch || m.packagePicker == nil || m.packagePicker.Query() != "reac" {
+		t.Fatalf("ctrl+f should open the search with the typed name, view %d", m.view)
+	}
+
+	// The picker is sized to the window pkgmgr already knows about
+	for _, c := range cmd().(tea.BatchMsg) {
+		updated, _ = m.Update(c())
+		m = updated.(pkgmgrModel)
+	}
+	if lines := strings.Count(m.View(), "\n") + 1; lines > 15 {
+		t.Errorf("picker rendered %d lines in a 15 line window", lines)
+	}
+
+	updated, _ = m.Update(tui.PickedMsg{Result: &frontend_mgr.SearchResult{Name: "react", Version: "18.2.0", CDN: "cdnjs"}})
+	m = updated.(pkgmgrModel)
+	if m.view != viewAddLibrary || m.packagePicker != nil {
+		t.Fatalf("picking should return to the add form, view %d", m.view)
+	}
+	if m.editInputs[addFieldName].Value() != "react" || m.editInputs[addFieldVersion].Value() != "18.2.0" ||
+		m.cdnOptions[m.cdnChoice] != "cdnjs" || m.focusIndex != addFieldVersion {
+		t.Errorf("form not filled in: name %q, version %q, cdn %q, focus %d", m.editInputs[addFieldName].Value(),
+			m.editInputs[addFieldVersion].Value(), m.cdnOptions[m.cdnChoice], m.focusIndex)
+	}
+
+	// Cancelling the search keeps what was typed
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	updated, _ = updated.(pkgmgrModel).Update(tui.PickedMsg{})
+	if m = updated.(pkgmgrModel); m.view != viewAddLibrary || m.editInputs[addFieldName].Value() != "react" {
+		t.Errorf("cancel changed the form: view %d, name %q", m.view, m.editInputs[addFieldName].Value())
+	}
+}
+
+func TestPkgmgrReviewBeforeSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	os.WriteFile(path, []byte("destination: ./frontend\nproject_name: shop\nlibraries:\n  jquery:\n    version: 3.6.0\n  lodash:\n    version: 4.17.21\n"), 0644)
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newPkgmgrModel(config, path)
+	config.ProjectName = "storefront"
+	config.Libraries["jquery"] = frontend_config.LibraryConfig{Version: "3.7.1", CDN: frontend_config.CDNUnpkg}
+	config.Libraries["htmx.org"] = frontend_config.LibraryConfig{Version: "2.0.0"}
+	delete(config.Libraries, "lodash")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(pkgmgrModel)
+	if m.view != viewReviewChanges || m.saved || m.quitting {
+		t.Fatalf("s should open the review, view %d, saved %v", m.view, m.saved)
+	}
+	view := m.View()
+	for _, want := range []string{"Added (1): htmx.org", "Removed (1): lodash", "version: 3.6.0 → 3.7.1", "cdn: (unset) → unpkg",
+		"project_name: shop → storefront", "- project_name: shop", "+ project_name: storefront"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("review missing %q:\n%s", want, view)
+		}
+	}
+
+	// Going back keeps editing; confirming saves
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(pkgmgrModel); m.view != viewLibraryList || m.saved {
+		t.Fatalf("esc should go back to the list, view %d", m.view)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	updated, cmd := updated.(pkgmgrModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(pkgmgrModel); !m.saved || cmd == nil {
+		t.Error("enter on the review should save and quit")
+	}
+}
+
+func TestSummarizeConfigChangesUnchanged(t *testing.T) {
+	config := &frontend_config.FrontendConfig{Destination: "./frontend", Libraries: map[string]frontend_config.LibraryConfig{"jquery": {Version: "3.7.1"}}}
+	if summary := summarizeConfigChanges(config, config); !summary.empty() || summary.render() != "No libraries or settings changed." {
+		t.Errorf("unexpected changes: %+v", summary)
+	}
+}
@@ -13,7 +13,6 @@ import (
 var (
 	pkgverCDN         string
 	pkgverLimit       int
-	pkgverNoCache     bool
 	pkgverInteractive bool
 )
 
@@ -45,19 +44,12 @@ Example:
 	Run: func(cmd *cobra.Command, args []string) {
 		packageName := args[0]
 
-		// Handle cache flag
-		if pkgverNoCache {
-			frontend_mgr.SetCacheEnabled(false)
-			defer frontend_mgr.SetCacheEnabled(true)
-		}
-
 		// Determine which CDN to use
 		cdn := determineCDN()
 
 		// Fetch and display versions
 		if err := fetchAndDisplayVersions(packageName, cdn); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -67,7 +59,6 @@ func init() {
 
 	pkgverCmd.Flags().StringVar(&pkgverCDN, "cdn", "", "CDN to query (unpkg, cdnjs, jsdelivr)")
 	pkgverCmd.Flags().IntVar(&pkgverLimit, "limit", 20, "Maximum number of versions to display (non-interactive mode)")
-	pkgverCmd.Flags().BoolVar(&pkgverNoCache, "no-cache", false, "Bypass cache and fetch fresh data")
 	pkgverCmd.Flags().BoolVarP(&pkgverInteractive, "interactive", "i", false, "Launch interactive version selector")
 }
 
@@ -77,78 +68,42 @@ func determineCDN() frontend_config.CDN {
 	if pkgverCDN != "" {
 		cdn := frontend_config.CDN(pkgverCDN)
 		if !frontend_config.IsValidCDN(cdn) {
-			fmt.Fprintf(os.Stderr, "Warning: Invalid CDN '%s', using 'unpkg' as default\n", pkgverCDN)
-			return frontend_config.CDNUnpkg
+			fmt.Fprintf(os.Stderr, "Warning: Invalid CDN '%s', using '%s' as default\n", pkgverCDN, settingsCDN())
+			return settingsCDN()
 		}
 		return cdn
 	}
 
-	// Try to read from config file if it exists
-	// (This is a simplified version - in production you'd properly load the config)
-	// For now, default to unpkg
-	return frontend_config.CDNUnpkg
+	// Otherwise use the cdn setting, or unpkg
+	return settingsCDN()
 }
 
 // fetchAndDisplayVersions fetches versions from the specified CDN and displays them
 func fetchAndDisplayVersions(packageName string, cdn frontend_config.CDN) error {
-	var versions []string
-	var latestVersion string
-	var err error
-
 	fmt.Printf("Fetching versions for '%s' from %s...\n\n", packageName, cdn)
 
-	switch cdn {
-	case frontend_config.CDNUnpkg:
-		result, err := frontend_mgr.FetchUnpkgVersions(packageName)
-		if err != nil {
-			return fmt.Errorf("failed to fetch versions from unpkg: %w", err)
-		}
-		// Extract version numbers from the map
-		versions = make([]string, 0, len(result.Versions))
-		for ver := range result.Versions {
-			versions = append(versions, ver)
-		}
-		latestVersion = result.DistTags["latest"]
-
-	case frontend_config.CDNCdnjs:
-		result, err := frontend_mgr.FetchCdnjsVersions(packageName)
-		if err != nil {
-			return fmt.Errorf("failed to fetch versions from cdnjs: %w", err)
-		}
-		versions = result.Versions
-		latestVersion = result.Version
-
-	case frontend_config.CDNJsdelivr:
-		result, err := frontend_mgr.FetchJsdelivrVersions(packageName)
-		if err != nil {
-			return fmt.Errorf("failed to fetch versions from jsdelivr: %w", err)
-		}
-		// Extract version numbers from version info
-		versions = make([]string, 0, len(result.Versions))
-		for _, vInfo := range result.Versions {
-			versions = append(versions, vInfo.Version)
-		}
-		latestVersion = result.Tags["latest"]
-
-	default:
-		return fmt.Errorf("unsupported CDN: %s", cdn)
+	provider, err := frontend_mgr.GetProvider(string(cdn))
+	if err != nil {
+		return err
 	}
 
+	result, err := provider.Versions(packageName)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to fetch versions from %s: %w", cdn, err)
 	}
 
-	if len(versions) == 0 {
+	if len(result.Versions) == 0 {
 		fmt.Println("No versions found for this package.")
 		return nil
 	}
 
-	// Sort versions (newest first)
-	sortedVersions := frontend_mgr.SortVersions(versions)
+	// Versions are sorted newest first
+	sortedVersions := result.Versions
+	latestVersion := frontend_mgr.LatestVersion(sortedVersions, result.Latest(), false)
 
 	// If interactive mode is enabled, launch the TUI
-	if pkgverInteractive {
-		selectedVersion, err := runInteractive(packageName, string(cdn), latestVersion, sortedVersions)
+	if pkgverInteractive && interactiveAvailable() {
+		selectedVersion, err := runVersionSelector(packageName, string(cdn), latestVersion, sortedVersions, versionSelectorOptions{ShowPrereleases: true})
 		if err != nil {
 			return fmt.Errorf("interactive mode error: %w", err)
 		}
@@ -164,6 +119,9 @@ func fetchAndDisplayVersions(packageName string, cdn frontend_config.CDN) error
 	fmt.Printf("Package: %s\n", packageName)
 	fmt.Printf("CDN: %s\n", cdn)
 	fmt.Printf("Latest: %s\n", latestVersion)
+	if prerelease := frontend_mgr.NewerPrerelease(sortedVersions, latestVersion); prerelease != "" {
+		fmt.Printf("Latest prerelease: %s\n", prerelease)
+	}
 	fmt.Printf("Total versions: %d\n\n", len(sortedVersions))
 
 	// Limit the number of displayed versions
@@ -178,10 +136,10 @@ func fetchAndDisplayVersions(packageName string, cdn frontend_config.CDN) error
 	for i := 0; i < displayCount; i++ {
 		ver := sortedVersions[i]
 		prefix := "  "
-		if ver == latestVersion {
+		if frontend_mgr.SameVersion(ver, latestVersion) {
 			prefix = "→ "
 		}
-		fmt.Printf("%s%s\n", prefix, ver)
+		fmt.Printf("%s%s\n", prefix, versionLabel(ver))
 	}
 
 	if len(sortedVersions) > displayCount {
@@ -191,3 +149,11 @@ func fetchAndDisplayVersions(packageName string, cdn frontend_config.CDN) error
 
 	return nil
 }
+
+// versionLabel returns v, marked when it is a prerelease
+func versionLabel(v string) string {
+	if frontend_mgr.IsPrerelease(v) {
+		return v + " (prerelease)"
+	}
+	return v
+}
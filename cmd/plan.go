@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultPlanFile is the plan file written by plan and read by apply
+const defaultPlanFile = "smfaman-plan.json"
+
+// planFormatVersion is bumped when the plan file format changes
+const planFormatVersion = 1
+
+var planOutput string
+
+// syncPlan is a reviewable list of downloads produced by the plan command
+type syncPlan struct {
+	FormatVersion int            `json:"format_version"`
+	Config        string         `json:"config"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Files         int            `json:"files"`
+	Bytes         int64          `json:"bytes"`
+	Tasks         []DownloadTask `json:"tasks"`
+}
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Write a JSON plan of the files sync would download",
+	Long: `Compute the files that sync would download and write them to a JSON plan
+without downloading anything.
+
+The plan lists each file's URL, local destination, size, and the reason it is
+downloaded ("missing" or "forced"). Review it, then execute it with
+'smfaman apply' to download exactly those files.
+
+Examples:
+  smfaman plan                          # Write smfaman-plan.json
+  smfaman plan -o release.plan.json     # Write to a custom file
+  smfaman plan -o -                     # Print the plan to stdout
+  smfaman plan --group admin --prod     # Plan a subset with production files`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runPlan(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.Flags().StringVarP(&planOutput, "output", "o", defaultPlanFile, "Plan file to write (use - for stdout)")
+	planCmd.Flags().BoolVar(&syncForce, "force", false, "Plan re-downloading files that already exist")
+	planCmd.Flags().BoolVar(&syncAllowShared, "allow-shared", false, "Proceed even if libraries share destination folders")
+	planCmd.Flags().BoolVar(&syncProd, "prod", false, "Use production file lists (files_prod)")
+	planCmd.Flags().StringArrayVarP(&syncGroups, "group", "g", nil, "Only plan libraries in this group (repeatable)")
+	planCmd.Flags().StringSliceVar(&syncOnlyExt, "only-ext", nil, "Only plan files with these extensions (e.g. js,css,woff2)")
+	planCmd.Flags().StringSliceVar(&syncExcludeExt, "exclude-ext", nil, "Skip files with these extensions (e.g. map,ts)")
+	planCmd.Flags().StringVar(&syncLinkMode, "link-mode", "", "How files are placed: copy, symlink or hardlink")
+}
+
+// runPlan executes the plan command
+func runPlan() error {
+	config, err := loadSyncConfig()
+	if err != nil {
+		return err
+	}
+
+	tasks, err := buildDownloadTasks(config)
+	if err != nil {
+		return err
+	}
+	if err := applyStoredValidators(FrontendConfig, tasks); err != nil {
+		return err
+	}
+
+	plan := newSyncPlan(FrontendConfig, tasks)
+	data, err := marshalSyncPlan(plan)
+	if err != nil {
+		return err
+	}
+
+	if planOutput == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(planOutput, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+
+	fmt.Printf("✓ Plan written to %s\n", planOutput)
+	fmt.Printf("Files to download: %d (%s)\n", plan.Files, formatBytes(plan.Bytes))
+	if plan.Files > 0 {
+		fmt.Printf("\nRun 'smfaman apply %s' to download them.\n", planOutput)
+	}
+
+	return nil
+}
+
+// newSyncPlan creates a plan for the given tasks
+func newSyncPlan(configPath string, tasks []DownloadTask) *syncPlan {
+	if tasks == nil {
+		tasks = []DownloadTask{}
+	}
+
+	plan := &syncPlan{
+		FormatVersion: planFormatVersion,
+		Config:        configPath,
+		CreatedAt:     time.Now().UTC(),
+		Files:         len(tasks),
+		Tasks:         tasks,
+	}
+	// Copies of identical files aren't downloaded
+	plan.Bytes, _ = downloadEstimate(tasks)
+
+	return plan
+}
+
+// marshalSyncPlan encodes a plan as indented JSON
+func marshalSyncPlan(plan *syncPlan) ([]byte, error) {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plan: %w", err)
+	}
+	return data, nil
+}
+
+// loadSyncPlan reads and validates a plan file
+func loadSyncPlan(path string) (*syncPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var plan syncPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+
+	if plan.FormatVersion != planFormatVersion {
+		return nil, fmt.Errorf("unsupported plan format version %d (expected %d)", plan.FormatVersion, planFormatVersion)
+	}
+
+	for i, task := range plan.Tasks {
+		if task.URL == "" || task.DestPath == "" {
+			return nil, fmt.Errorf("invalid plan: task %d is missing a url or destination", i+1)
+		}
+	}
+
+	return &plan, nil
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func writePlanFile(t *testing.T, plan *syncPlan) string {
+	t.Helper()
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("Failed to marshal plan: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+	return path
+}
+
+func TestNewSyncPlan(t *testing.T) {
+	tasks := []DownloadTask{
+		{LibraryName: "jquery", Version: "3.7.1", FilePath: "dist/jquery.min.js", Size: 1000, Reason: "missing"},
+		{LibraryName: "jquery", Version: "3.7.1", FilePath: "dist/jquery.js", Size: 3000, Reason: "forced"},
+	}
+
+	plan := newSyncPlan("smartfrontend.yaml", tasks)
+
+	if plan.FormatVersion != planFormatVersion {
+		t.Errorf("expected format version %d, got %d", planFormatVersion, plan.FormatVersion)
+	}
+	if plan.Files != 2 || plan.Bytes != 4000 {
+		t.Errorf("expected 2 files and 4000 bytes, got %d and %d", plan.Files, plan.Bytes)
+	}
+
+	empty := newSyncPlan("smartfrontend.yaml", nil)
+	if empty.Tasks == nil {
+		t.Error("expected empty plan to have a non-nil task list")
+	}
+}
+
+func TestLoadSyncPlan(t *testing.T) {
+	valid := newSyncPlan("smartfrontend.yaml", []DownloadTask{
+		{LibraryName: "jquery", Version: "3.7.1", FilePath: "a.js", URL: "https://example.com/a.js", DestPath: "/tmp/a.js"},
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		plan, err := loadSyncPlan(writePlanFile(t, valid))
+		if err != nil {
+			t.Fatalf("loadSyncPlan failed: %v", err)
+		}
+		if len(plan.Tasks) != 1 || plan.Tasks[0].URL != "https://example.com/a.js" {
+			t.Errorf("unexpected tasks: %+v", plan.Tasks)
+		}
+	})
+
+	t.Run("unsupported format version", func(t *testing.T) {
+		bad := *valid
+		bad.FormatVersion = planFormatVersion + 1
+		if _, err := loadSyncPlan(writePlanFile(t, &bad)); err == nil {
+			t.Error("expected error for unsupported format version")
+		}
+	})
+
+	t.Run("task without destination", func(t *testing.T) {
+		bad := newSyncPlan("smartfrontend.yaml", []DownloadTask{{URL: "https://example.com/a.js"}})
+		if _, err := loadSyncPlan(writePlanFile(t, bad)); err == nil {
+			t.Error("expected error for task without destination")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := loadSyncPlan(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Error("expected error for missing plan file")
+		}
+	})
+}
+
+func TestRunApplyDownloadsPlannedFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("planned content"))
+	}))
+	defer server.Close()
+
+	oldJSON, oldNoPackageCache := syncJSON, syncNoPackageCache
+	syncJSON, syncNoPackageCache = true, true
+	defer func() {
+		syncJSON, syncNoPackageCache = oldJSON, oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	destPath := filepath.Join(tmpDir, "lib", "app.js")
+	planFile := writePlanFile(t, newSyncPlan(configPath, []DownloadTask{
+		{LibraryName: "lib", Version: "1.0.0", FilePath: "app.js", URL: server.URL + "/app.js", DestPath: destPath, Reason: "missing"},
+	}))
+
+	// Silence the JSON summary written to stdout
+	oldStdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	os.Stdout = devNull
+	defer func() {
+		os.Stdout = oldStdout
+		devNull.Close()
+	}()
+
+	if err := runApply(planFile); err != nil {
+		t.Fatalf("runApply failed: %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("expected planned file to be downloaded: %v", err)
+	}
+	if string(data) != "planned content" {
+		t.Errorf("unexpected file content %q", string(data))
+	}
+
+	// Applied files are recorded in the config's lockfile
+	if _, err := lookupProvenance(configPath, destPath); err != nil {
+		t.Errorf("expected provenance to be recorded: %v", err)
+	}
+}
+
+func TestPrintDryRunPlan(t *testing.T) {
+	oldConfig, oldStdout := FrontendConfig, os.Stdout
+	FrontendConfig = "smartfrontend.yaml"
+	out, err := os.Create(filepath.Join(t.TempDir(), "plan.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = out
+	defer func() {
+		FrontendConfig, os.Stdout = oldConfig, oldStdout
+		out.Close()
+	}()
+
+	tasks := []DownloadTask{
+		{LibraryName: "jquery", Version: "3.7.1", CDN: "unpkg", FilePath: "dist/jquery.min.js", URL: "https://unpkg.com/jquery@3.7.1/dist/jquery.min.js",
+			DestPath: filepath.Join("frontend", "jquery", "dist", "jquery.min.js"), Size: 1000, Reason: "changed"},
+	}
+	if err := printDryRunPlan(tasks); err != nil {
+		t.Fatalf("printDryRunPlan failed: %v", err)
+	}
+
+	// The output is a plan apply can run
+	plan, err := loadSyncPlan(out.Name())
+	if err != nil {
+		t.Fatalf("loadSyncPlan failed: %v", err)
+	}
+	if plan.Config != "smartfrontend.yaml" || plan.Files != 1 || plan.Bytes != 1000 || plan.Tasks[0].Reason != "changed" || plan.Tasks[0].CDN != "unpkg" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+}
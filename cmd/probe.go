@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var probeFormat string
+
+// Probe lookups (overridable in tests)
+var (
+	fetchProbeVersions versionFetcher = fetchVersionsForUpgrade
+	fetchProbeFiles                   = fetchFileList
+)
+
+// probeCmd represents the probe command
+var probeCmd = &cobra.Command{
+	Use:   "probe <package>[@version]",
+	Short: "Compare how each CDN serves a package",
+	Long: `Check every supported CDN (unpkg, jsdelivr and cdnjs) for a package at the
+same time and report, per CDN, whether the version is available, how long the
+lookups took, and how many files the version has and their total size.
+
+Without a version the latest release on each CDN is probed; a range or
+dist-tag is resolved on each CDN. The CDN cache is bypassed, so the latencies
+are those of live requests. Use the result to choose a library's 'cdn'
+setting.
+
+Examples:
+  smfaman probe jquery
+  smfaman probe bootstrap@5.3.2
+  smfaman probe @popperjs/core@^2 --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runProbe(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(probeCmd)
+	probeCmd.Flags().StringVar(&probeFormat, "format", "table", "Output format (table, json)")
+}
+
+// cdnProbe is the result of probing one CDN for a package
+type cdnProbe struct {
+	CDN       frontend_config.CDN `json:"cdn"`
+	Available bool                `json:"available"`
+	Version   string              `json:"version,omitempty"`
+	LatencyMS int64               `json:"latency_ms"`
+	Files     int                 `json:"files"`
+	Size      int64               `json:"size"`
+	SizeKnown bool                `json:"size_known"` // False when the CDN doesn't list every file's size
+	Error     string              `json:"error,omitempty"`
+}
+
+// runProbe executes the probe command
+func runProbe(spec string) error {
+	if probeFormat != "table" && probeFormat != "json" {
+		return fmt.Errorf("unsupported probe format %q (must be table or json)", probeFormat)
+	}
+	name, version := parsePackageSpec(spec)
+	if name == "" {
+		return fmt.Errorf("invalid package %q", spec)
+	}
+
+	frontend_mgr.CacheManager.SetRefresh(true)
+	probes := probeCDNs(name, version, fallbackCDNs)
+
+	if probeFormat == "json" {
+		data, err := json.MarshalIndent(probes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(renderProbeTable(probes))
+	}
+
+	if !probes[0].Available {
+		return notFoundError(fmt.Errorf("%s is not available on any CDN", spec))
+	}
+	if probeFormat == "table" {
+		fmt.Printf("\nFastest: %s (set 'cdn: %s' on the library to use it)\n", probes[0].CDN, probes[0].CDN)
+	}
+	return nil
+}
+
+// probeCDNs probes the CDNs concurrently. The results are sorted with the
+// available CDNs first, fastest first.
+func probeCDNs(name, version string, cdns []frontend_config.CDN) []cdnProbe {
+	probes := make([]cdnProbe, len(cdns))
+	var wg sync.WaitGroup
+	for i, cdn := range cdns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probes[i] = probeCDN(name, version, cdn)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(probes, func(i, j int) bool {
+		if probes[i].Available != probes[j].Available {
+			return probes[i].Available
+		}
+		return probes[i].LatencyMS < probes[j].LatencyMS
+	})
+	return probes
+}
+
+// probeCDN looks up a package version and its file list on one CDN
+func probeCDN(name, version string, cdn frontend_config.CDN) cdnProbe {
+	probe := cdnProbe{CDN: cdn}
+	start := time.Now()
+	defer func() { probe.LatencyMS = time.Since(start).Milliseconds() }()
+
+	versions, latest, err := fetchProbeVersions(name, cdn)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	probe.Version = latest
+	if version != "" {
+		if probe.Version, err = frontend_mgr.ResolveVersionSpec(version, versions, map[string]string{"latest": latest}); err != nil {
+			probe.Version = ""
+			probe.Error = fmt.Sprintf("version '%s' not found", version)
+			return probe
+		}
+	}
+
+	files, err := fetchProbeFiles(name, probe.Version, cdn)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	probe.Available = true
+	probe.Files = len(files)
+	probe.SizeKnown = true
+	for _, file := range files {
+		if file.Size == 0 {
+			probe.SizeKnown = false
+		}
+		probe.Size += file.Size
+	}
+	return probe
+}
+
+// renderProbeTable renders the probe results as a table
+func renderProbeTable(probes []cdnProbe) string {
+	headers := []string{"CDN", "STATUS", "VERSION", "LATENCY", "FILES", "SIZE"}
+	rows := make([][]string, len(probes))
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+
+	for i, p := range probes {
+		latency := fmt.Sprintf("%d ms", p.LatencyMS)
+		if !p.Available {
+			rows[i] = []string{string(p.CDN), "✗ " + truncate(p.Error, 40), "-", latency, "-", "-"}
+		} else {
+			size := formatBytes(p.Size)
+			if !p.SizeKnown {
+				size = "unknown"
+			}
+			rows[i] = []string{string(p.CDN), "✓ available", p.Version, latency, fmt.Sprint(p.Files), size}
+		}
+		for col, cell := range rows[i] {
+			widths[col] = max(widths[col], lipgloss.Width(cell))
+		}
+	}
+
+	var s strings.Builder
+	writeRow := func(cells []string) {
+		for col, cell := range cells {
+			s.WriteString(cell)
+			if col < len(cells)-1 {
+				s.WriteString(strings.Repeat(" ", widths[col]-lipgloss.Width(cell)+2))
+			}
+		}
+		s.WriteString("\n")
+	}
+
+	writeRow(headers)
+	rule := make([]string, len(headers))
+	for col, w := range widths {
+		rule[col] = strings.Repeat("─", w)
+	}
+	writeRow(rule)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	return s.String()
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestProbeCDNs(t *testing.T) {
+	origVersions, origFiles := fetchProbeVersions, fetchProbeFiles
+	defer func() { fetchProbeVersions, fetchProbeFiles = origVersions, origFiles }()
+
+	fetchProbeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		switch cdn {
+		case frontend_config.CDNCdnjs:
+			return nil, "", errors.New("no versions found for package 'jquery'")
+		case frontend_config.CDNJsdelivr:
+			return []string{"3.6.0", "3.7.0", "3.7.1"}, "3.7.1", nil
+		}
+		return []string{"3.6.0", "3.7.1"}, "3.7.1", nil
+	}
+	fetchProbeFiles = func(libName, version string, cdn frontend_config.CDN) ([]CDNFile, error) {
+		files := []CDNFile{{Path: "dist/jquery.js", Size: 2048}, {Path: "dist/jquery.min.js", Size: 1024}}
+		if cdn == frontend_config.CDNJsdelivr {
+			files[1].Size = 0
+		}
+		return files, nil
+	}
+
+	probes := probeCDNs("jquery", "", fallbackCDNs)
+	if len(probes) != 3 {
+		t.Fatalf("got %d probes, want 3", len(probes))
+	}
+	if last := probes[2]; last.CDN != frontend_config.CDNCdnjs || last.Available || last.Error == "" {
+		t.Errorf("unavailable CDN not sorted last with its error: %+v", last)
+	}
+	for _, p := range probes[:2] {
+		if !p.Available || p.Version != "3.7.1" || p.Files != 2 {
+			t.Errorf("probe = %+v, want 3.7.1 available with 2 files", p)
+		}
+		if p.CDN == frontend_config.CDNUnpkg && (!p.SizeKnown || p.Size != 3072) {
+			t.Errorf("unpkg size = %d (known %v), want 3072", p.Size, p.SizeKnown)
+		}
+		if p.CDN == frontend_config.CDNJsdelivr && p.SizeKnown {
+			t.Errorf("jsdelivr size known despite a file without a size")
+		}
+	}
+
+	// A version missing from one CDN is reported as unavailable there
+	probes = probeCDNs("jquery", "3.7.0", fallbackCDNs)
+	for _, p := range probes {
+		if p.Available != (p.CDN == frontend_config.CDNJsdelivr) {
+			t.Errorf("%s available = %v for 3.7.0 (%s)", p.CDN, p.Available, p.Error)
+		}
+	}
+	if probes[0].CDN != frontend_config.CDNJsdelivr {
+		t.Errorf("available CDN not sorted first: %s", probes[0].CDN)
+	}
+}
+
+func TestRenderProbeTable(t *testing.T) {
+	table := renderProbeTable([]cdnProbe{
+		{CDN: frontend_config.CDNUnpkg, Available: true, Version: "3.7.1", LatencyMS: 120, Files: 2, Size: 3072, SizeKnown: true},
+		{CDN: frontend_config.CDNCdnjs, LatencyMS: 80, Error: "not found"},
+	})
+
+	lines := strings.Split(strings.TrimSuffix(table, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("table has %d lines, want 4:\n%s", len(lines), table)
+	}
+	for _, want := range []string{"unpkg", "3.7.1", "120 ms", "3.00 KB"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("row %q missing %q", lines[2], want)
+		}
+	}
+	if !strings.Contains(lines[3], "✗ not found") {
+		t.Errorf("row %q missing the error", lines[3])
+	}
+	column := func(line, cell string) int { return utf8.RuneCountInString(line[:strings.Index(line, cell)]) }
+	if column(lines[0], "VERSION") != column(lines[2], "3.7.1") {
+		t.Errorf("columns not aligned:\n%s", table)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+// progressStdout is the --progress-json value that streams events to stdout
+const progressStdout = "-"
+
+// progressEvent is a single newline-delimited JSON progress event
+type progressEvent struct {
+	Event string    `json:"event"` // "start", "task_start", "bytes", "done", "error" or "summary"
+	Time  time.Time `json:"time"`
+
+	// Position of the task in the run (1-based) and the number of tasks
+	Index int `json:"index,omitempty"`
+	Total int `json:"total,omitempty"`
+
+	Library     string `json:"library,omitempty"`
+	Version     string `json:"version,omitempty"`
+	File        string `json:"file,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Size        int64  `json:"size,omitempty"` // Size published by the CDN
+
+	// Bytes is the size of the file just fetched; TotalBytes and
+	// ExpectedBytes are the running and expected totals for the run
+	Bytes         int64 `json:"bytes,omitempty"`
+	TotalBytes    int64 `json:"total_bytes,omitempty"`
+	ExpectedBytes int64 `json:"expected_bytes,omitempty"`
+
+	FromCache  bool    `json:"from_cache,omitempty"`
+	Copied     bool    `json:"copied,omitempty"` // Copied from an identical file written earlier
+	Unchanged  bool    `json:"unchanged,omitempty"`
+	DurationMs float64 `json:"duration_ms,omitempty"`
+	Error      string  `json:"error,omitempty"`
+
+	Summary *syncSummary `json:"summary,omitempty"`
+}
+
+// progressEmitter writes progress events as newline-delimited JSON
+type progressEmitter struct {
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// openProgressEmitter opens the event stream: stdout for "-", otherwise the
+// Unix socket at the given path
+func openProgressEmitter(target string) (*progressEmitter, error) {
+	if target == progressStdout {
+		return &progressEmitter{encoder: json.NewEncoder(os.Stdout)}, nil
+	}
+
+	conn, err := net.Dial("unix", target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to progress socket %s: %w", target, err)
+	}
+	return &progressEmitter{encoder: json.NewEncoder(conn), closer: conn}, nil
+}
+
+// emit writes an event, stamping it with the current time. Write errors are
+// ignored so a disconnected listener never fails the sync.
+func (e *progressEmitter) emit(event progressEvent) {
+	event.Time = time.Now().UTC()
+	_ = e.encoder.Encode(event)
+}
+
+// Close closes the underlying socket, if any
+func (e *progressEmitter) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// runProgressJSONDownload downloads the tasks, emitting progress events
+// instead of printing human-readable progress
+func runProgressJSONDownload(tasks []DownloadTask, events *progressEmitter) (*syncSummary, error) {
+	var expectedBytes int64
+	for _, task := range tasks {
+		expectedBytes += task.Size
+	}
+	events.emit(progressEvent{Event: "start", Total: len(tasks), ExpectedBytes: expectedBytes})
+
+	summary := newSyncSummary()
+	var totalBytes int64
+	for i, task := range tasks {
+		base := progressEvent{
+			Index:       i + 1,
+			Total:       len(tasks),
+			Library:     task.LibraryName,
+			Version:     task.Version,
+			File:        task.FilePath,
+			Destination: task.DestPath,
+		}
+
+		started := base
+		started.Event = "task_start"
+		started.URL = task.URL
+		started.Size = task.Size
+		events.emit(started)
+
+		result, err := downloadFileWithTask(task)
+		if err != nil {
+			failed := base
+			failed.Event = "error"
+			failed.Error = err.Error()
+			events.emit(failed)
+			summary.recordFailure(task, err)
+			continue
+		}
+		summary.record(task, result)
+		totalBytes += result.Bytes
+
+		progress := base
+		progress.Event = "bytes"
+		progress.Bytes = result.Bytes
+		progress.TotalBytes = totalBytes
+		progress.ExpectedBytes = expectedBytes
+		events.emit(progress)
+
+		done := base
+		done.Event = "done"
+		done.Bytes = result.Bytes
+		done.FromCache = result.FromCache
+		done.Copied = result.Copied
+		done.Unchanged = result.Unchanged
+		done.DurationMs = durationMs(result.Duration)
+		events.emit(done)
+	}
+
+	summary.finish()
+	return summary, summary.err()
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestRunProgressJSONDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.js" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("console.log(1);"))
+	}))
+	defer server.Close()
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	defer func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	tmpDir := t.TempDir()
+	tasks := []DownloadTask{
+		{LibraryName: "app", Version: "1.0.0", FilePath: "app.js", DestPath: filepath.Join(tmpDir, "app.js"), URL: server.URL + "/app.js", Size: 15},
+		{LibraryName: "app", Version: "1.0.0", FilePath: "missing.js", DestPath: filepath.Join(tmpDir, "missing.js"), URL: server.URL + "/missing.js"},
+	}
+
+	var buf bytes.Buffer
+	events := &progressEmitter{encoder: json.NewEncoder(&buf)}
+	summary, err := runProgressJSONDownload(tasks, events)
+	if err == nil {
+		t.Error("expected an error for the missing file")
+	}
+	if summary.Files != 1 || len(summary.Failed) != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	var kinds []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event progressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid event line %q: %v", scanner.Text(), err)
+		}
+		if event.Time.IsZero() {
+			t.Errorf("event %s has no time", event.Event)
+		}
+		if event.Event == "bytes" && (event.Bytes != 15 || event.TotalBytes != 15 || event.ExpectedBytes != 15) {
+			t.Errorf("unexpected bytes event: %+v", event)
+		}
+		if event.Event == "error" && (event.File != "missing.js" || event.Error == "") {
+			t.Errorf("unexpected error event: %+v", event)
+		}
+		kinds = append(kinds, event.Event)
+	}
+
+	expected := []string{"start", "task_start", "bytes", "done", "task_start", "error"}
+	if len(kinds) != len(expected) {
+		t.Fatalf("got events %v, want %v", kinds, expected)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Errorf("event %d = %s, want %s", i, kinds[i], expected[i])
+		}
+	}
+}
+
+func TestOpenProgressEmitterSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "progress.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- ""
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	events, err := openProgressEmitter(socketPath)
+	if err != nil {
+		t.Fatalf("openProgressEmitter failed: %v", err)
+	}
+	events.emit(progressEvent{Event: "start", Total: 3})
+	events.Close()
+
+	var event progressEvent
+	if err := json.Unmarshal([]byte(<-received), &event); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if event.Event != "start" || event.Total != 3 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if _, err := openProgressEmitter(filepath.Join(t.TempDir(), "none.sock")); err == nil {
+		t.Error("expected an error for a missing socket")
+	}
+}
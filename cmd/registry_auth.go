@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// npm registry setting keys
+const (
+	// settingNpmToken holds the npm registry token (SMFAMAN_NPM_TOKEN as an
+	// environment variable)
+	settingNpmToken = "npm_token"
+
+	// settingNpmFullMetadata makes version lookups download the full registry
+	// document instead of the abbreviated one
+	settingNpmFullMetadata = "npm_full_metadata"
+)
+
+// initRegistry applies the npm registry settings: the token from
+// registryTokenSource and the npm_full_metadata setting
+func initRegistry() {
+	token, _ := registryTokenSource()
+	frontend_mgr.SetRegistryToken(token)
+	frontend_mgr.FullRegistryDocuments = viper.GetBool(settingNpmFullMetadata)
+}
+
+// registryTokenSource returns the npm registry token and where it came
+// from: the npm_token setting, the NPM_TOKEN environment variable, or the
+// registry.npmjs.org _authToken of ./.npmrc or ~/.npmrc. It returns "" when
+// none is set.
+func registryTokenSource() (token, source string) {
+	if token := viper.GetString(settingNpmToken); token != "" {
+		return token, settingNpmToken + " setting"
+	}
+	if token := os.Getenv("NPM_TOKEN"); token != "" {
+		return token, "NPM_TOKEN"
+	}
+
+	npmrcs := []string{".npmrc"}
+	if home, err := os.UserHomeDir(); err == nil {
+		npmrcs = append(npmrcs, filepath.Join(home, ".npmrc"))
+	}
+	for _, path := range npmrcs {
+		token, err := frontend_mgr.ReadNpmrcToken(path)
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if token != "" {
+			return token, path
+		}
+	}
+	return "", ""
+}
+
+// privatePackageError explains a package the CDN doesn't have but the
+// authenticated npm registry does: its versions resolve, but its files
+// can't come from a public CDN. Other errors are returned unchanged.
+func privatePackageError(libName, version string, cdn frontend_config.CDN, err error) error {
+	var statusErr *frontend_mgr.StatusError
+	if !frontend_mgr.RegistryAuthenticated() || !errors.As(err, &statusErr) || !statusErr.NotFound() {
+		return err
+	}
+
+	pkg, regErr := frontend_mgr.FetchUnpkgVersions(libName)
+	if regErr != nil {
+		return err
+	}
+	_, published := pkg.Versions[version]
+	_, tagged := pkg.DistTags[version]
+	if !published && !tagged {
+		return err
+	}
+	return fmt.Errorf("%s@%s: %w: %s returned 404, but the authenticated npm registry has it, so it is probably private; "+
+		"download its files from your private registry or host instead", libName, version, frontend_mgr.ErrPrivatePackage, cdn)
+}
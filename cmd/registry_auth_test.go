@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestRegistryTokenSource(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("NPM_TOKEN", "")
+	t.Chdir(t.TempDir())
+	defer viper.Set(settingNpmToken, nil)
+
+	if token, source := registryTokenSource(); token != "" {
+		t.Errorf("registryTokenSource() = %q from %s, want none", token, source)
+	}
+
+	writeTestFile(t, filepath.Join(home, ".npmrc"), "//registry.npmjs.org/:_authToken=home-token\n")
+	if token, _ := registryTokenSource(); token != "home-token" {
+		t.Errorf("token = %q, want the one from ~/.npmrc", token)
+	}
+
+	if err := os.WriteFile(".npmrc", []byte("//registry.npmjs.org/:_authToken=project-token\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if token, source := registryTokenSource(); token != "project-token" || source != ".npmrc" {
+		t.Errorf("registryTokenSource() = %q, %q; want the project .npmrc first", token, source)
+	}
+
+	t.Setenv("NPM_TOKEN", "env-token")
+	if token, _ := registryTokenSource(); token != "env-token" {
+		t.Errorf("token = %q, want NPM_TOKEN over .npmrc", token)
+	}
+
+	viper.Set(settingNpmToken, "setting-token")
+	if token, _ := registryTokenSource(); token != "setting-token" {
+		t.Errorf("token = %q, want the npm_token setting first", token)
+	}
+}
+
+func TestPrivatePackageErrorWithoutToken(t *testing.T) {
+	frontend_mgr.SetRegistryToken("")
+
+	notFound := &frontend_mgr.StatusError{Source: "UNPKG", StatusCode: 404}
+	if err := privatePackageError("@acme/private", "1.0.0", frontend_config.CDNUnpkg, notFound); err != notFound {
+		t.Errorf("privatePackageError() = %v, want the CDN error unchanged without a token", err)
+	}
+
+	frontend_mgr.SetRegistryToken("secret")
+	defer frontend_mgr.SetRegistryToken("")
+	other := errors.New("connection refused")
+	if err := privatePackageError("@acme/private", "1.0.0", frontend_config.CDNUnpkg, other); err != other {
+		t.Errorf("privatePackageError() = %v, want errors other than 404 unchanged", err)
+	}
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+var (
+	reportFormat string
+	reportOutput string
+)
+
+// vendorReportEntry describes one vendored library in the report
+type vendorReportEntry struct {
+	Library     string   `json:"library"`
+	Version     string   `json:"version"`
+	CDN         string   `json:"cdn"`
+	Source      string   `json:"source"`
+	License     string   `json:"license,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+	Synced      bool     `json:"synced"`
+	Files       int      `json:"files"`
+	Bytes       int64    `json:"bytes"`
+}
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a report of vendored libraries",
+	Long: `Generate a report listing each library with its version, license, CDN
+source, file count, and total size on disk.
+
+The Markdown report (VENDORED.md by default) is meant to be committed so
+reviewers and auditors can see what is vendored at a glance. Licenses come
+from the metadata file written by 'smfaman add --metadata'.
+
+Formats:
+  md    Markdown table (default, written to VENDORED.md)
+  json  JSON array (written to stdout)
+
+Examples:
+  smfaman report
+  smfaman report --format md -o docs/VENDORED.md
+  smfaman report --format json > vendored.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runReport(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportFormat, "format", "md", "Report format (md, json)")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file (default VENDORED.md for md, stdout for json; use - for stdout)")
+}
+
+// runReport executes the report command
+func runReport() error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	entries, err := buildVendorReport(config, FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	output := reportOutput
+	switch reportFormat {
+	case "md", "markdown":
+		content = renderVendorReportMarkdown(entries, FrontendConfig)
+		if output == "" {
+			output = "VENDORED.md"
+		}
+	case "json":
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		content = string(data) + "\n"
+		if output == "" {
+			output = "-"
+		}
+	default:
+		return fmt.Errorf("unsupported report format %q (must be md or json)", reportFormat)
+	}
+
+	if output == "-" {
+		fmt.Print(content)
+		return nil
+	}
+
+	if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	fmt.Printf("✓ Report written to %s (%d %s)\n", output, len(entries), pluralize(len(entries), "library", "libraries"))
+	return nil
+}
+
+// buildVendorReport collects report entries for all libraries, sorted by name
+func buildVendorReport(config *frontend_config.FrontendConfig, configPath string) ([]vendorReportEntry, error) {
+	metadata, err := loadLibraryMetadata(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []vendorReportEntry{}
+	for _, name := range sortedKeys(config.Libraries) {
+		libConfig := config.Libraries[name]
+		cdn := libraryCDN(config, libConfig)
+
+		entry := vendorReportEntry{
+			Library: name,
+			Version: libConfig.Version,
+			CDN:     string(cdn),
+			Source:  cdnPackageURL(name, libConfig.Version, cdn),
+			Groups:  libConfig.Groups,
+		}
+
+		if meta, ok := metadata.Libraries[name]; ok {
+			entry.License = meta.License
+			entry.Description = meta.Description
+		}
+
+		destPath, err := config.GetLibraryDestination(name, libConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get destination for %s: %w", name, err)
+		}
+		if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+			entry.Synced = true
+			entry.Files, entry.Bytes, err = directoryUsage(destPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan %s: %w", name, err)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// directoryUsage returns the number of files and total bytes in a directory tree
+func directoryUsage(dir string) (int, int64, error) {
+	var files int
+	var bytes int64
+
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	})
+
+	return files, bytes, err
+}
+
+// renderVendorReportMarkdown renders the report as a Markdown document
+func renderVendorReportMarkdown(entries []vendorReportEntry, configPath string) string {
+	var s strings.Builder
+
+	s.WriteString("# Vendored Frontend Libraries\n\n")
+	s.WriteString(fmt.Sprintf("Generated by `smfaman report` from `%s`. Do not edit by hand.\n\n", filepath.ToSlash(configPath)))
+
+	if len(entries) == 0 {
+		s.WriteString("No libraries are configured.\n")
+		return s.String()
+	}
+
+	s.WriteString("| Library | Version | License | Source | Files | Size |\n")
+	s.WriteString("|---------|---------|---------|--------|------:|-----:|\n")
+
+	var totalFiles int
+	var totalBytes int64
+	for _, e := range entries {
+		license := e.License
+		if license == "" {
+			license = "unknown"
+		}
+
+		files, size := "not synced", "-"
+		if e.Synced {
+			files, size = fmt.Sprint(e.Files), formatBytes(e.Bytes)
+		}
+
+		s.WriteString(fmt.Sprintf("| %s | %s | %s | [%s](%s) | %s | %s |\n",
+			markdownCell(e.Library), markdownCell(e.Version), markdownCell(license),
+			e.CDN, e.Source, files, size))
+
+		totalFiles += e.Files
+		totalBytes += e.Bytes
+	}
+
+	s.WriteString(fmt.Sprintf("\n**Total:** %d %s, %d %s, %s\n",
+		len(entries), pluralize(len(entries), "library", "libraries"),
+		totalFiles, pluralize(totalFiles, "file", "files"), formatBytes(totalBytes)))
+
+	return s.String()
+}
+
+// markdownCell escapes characters that would break a Markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
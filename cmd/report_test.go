@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestBuildVendorReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	libs := filepath.Join(tmpDir, "libs")
+
+	writeTestFile(t, filepath.Join(libs, "jquery", "dist", "jquery.min.js"), "0123456789")
+	writeTestFile(t, filepath.Join(libs, "jquery", "dist", "jquery.js"), "01234")
+
+	metadata := &libraryMetadataFile{Libraries: map[string]libraryMetadata{
+		"jquery": {PackageMetadata: frontend_mgr.PackageMetadata{License: "MIT"}, FetchedAt: time.Now()},
+	}}
+	if err := saveLibraryMetadata(configPath, metadata); err != nil {
+		t.Fatalf("saveLibraryMetadata failed: %v", err)
+	}
+
+	config := &frontend_config.FrontendConfig{
+		Destination: filepath.Join(libs, "{library_name}"),
+		CDN:         frontend_config.CDNJsdelivr,
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":    {Version: "3.7.1"},
+			"bootstrap": {Version: "5.3.0", CDN: frontend_config.CDNCdnjs},
+		},
+	}
+
+	entries, err := buildVendorReport(config, configPath)
+	if err != nil {
+		t.Fatalf("buildVendorReport failed: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	// Entries are sorted by library name
+	bootstrap, jquery := entries[0], entries[1]
+	if bootstrap.Library != "bootstrap" || jquery.Library != "jquery" {
+		t.Fatalf("unexpected order: %s, %s", bootstrap.Library, jquery.Library)
+	}
+
+	if bootstrap.Synced || bootstrap.CDN != "cdnjs" || bootstrap.License != "" {
+		t.Errorf("unexpected bootstrap entry: %+v", bootstrap)
+	}
+	if bootstrap.Source != "https://cdnjs.cloudflare.com/ajax/libs/bootstrap/5.3.0/" {
+		t.Errorf("unexpected bootstrap source: %s", bootstrap.Source)
+	}
+
+	if !jquery.Synced || jquery.Files != 2 || jquery.Bytes != 15 || jquery.License != "MIT" {
+		t.Errorf("unexpected jquery entry: %+v", jquery)
+	}
+	if jquery.Source != "https://cdn.jsdelivr.net/npm/jquery@3.7.1/" {
+		t.Errorf("unexpected jquery source: %s", jquery.Source)
+	}
+
+	markdown := renderVendorReportMarkdown(entries, "smartfrontend.yaml")
+	for _, want := range []string{
+		"# Vendored Frontend Libraries",
+		"| jquery | 3.7.1 | MIT | [jsdelivr](https://cdn.jsdelivr.net/npm/jquery@3.7.1/) | 2 | 15 B |",
+		"| bootstrap | 5.3.0 | unknown |",
+		"not synced",
+		"**Total:** 2 libraries, 2 files, 15 B",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if got := markdownCell("MIT | Apache-2.0"); got != `MIT \| Apache-2.0` {
+		t.Errorf("unexpected escaped cell: %s", got)
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// requestOptions are the extra headers and query parameters of a library's
+// file downloads (the headers and query config keys), keyed by the host
+// they are sent to. Values may refer to environment variables as $VAR or
+// ${VAR}; they are expanded only when the request is built, so the secrets
+// never reach plans, the lockfile or output.
+type requestOptions struct {
+	Headers frontend_config.HostValues
+	Query   frontend_config.HostValues
+}
+
+// requestOptions returns the extra headers and query parameters of a task
+func (t DownloadTask) requestOptions() requestOptions {
+	return requestOptions{Headers: t.Headers, Query: t.Query}
+}
+
+// loadRequestOptions fills in the headers and query parameters of tasks
+// from the config, as plans leave them out. A config that no longer exists
+// adds none.
+func loadRequestOptions(configPath string, tasks []DownloadTask) error {
+	if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	all, err := config.WithDevLibraries()
+	if err != nil {
+		return err
+	}
+
+	for i := range tasks {
+		libConfig := all.Libraries[tasks[i].LibraryName]
+		tasks[i].Headers = config.GetLibraryHeaders(libConfig)
+		tasks[i].Query = config.GetLibraryQuery(libConfig)
+	}
+	return nil
+}
+
+// apply adds the headers and query parameters for the host of req,
+// expanding environment variable references. Errors name the setting,
+// never a value.
+func (o requestOptions) apply(req *http.Request) error {
+	if err := o.applyHeaders(req); err != nil {
+		return err
+	}
+
+	params := o.Query.ForHost(req.URL.Host)
+	if len(params) == 0 {
+		return nil
+	}
+	query := req.URL.Query()
+	for _, name := range sortedKeys(params) {
+		value, err := expandRequestValue("query."+name, req.URL.Host, params[name])
+		if err != nil {
+			return err
+		}
+		query.Set(name, value)
+	}
+	req.URL.RawQuery = query.Encode()
+	return nil
+}
+
+// applyHeaders adds the headers for the host of req
+func (o requestOptions) applyHeaders(req *http.Request) error {
+	headers := o.Headers.ForHost(req.URL.Host)
+	for _, name := range sortedKeys(headers) {
+		value, err := expandRequestValue("headers."+name, req.URL.Host, headers[name])
+		if err != nil {
+			return err
+		}
+		req.Header.Set(name, value)
+	}
+	return nil
+}
+
+// client returns the HTTP client for requests with these options. net/http
+// carries custom headers over to redirects, so on a redirect the headers of
+// the original host are swapped for those of the new one.
+func (o requestOptions) client() *http.Client {
+	if len(o.Headers) == 0 {
+		return http.DefaultClient
+	}
+
+	client := *http.DefaultClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		for _, headers := range o.Headers {
+			for name := range headers {
+				req.Header.Del(name)
+			}
+		}
+		return o.applyHeaders(req)
+	}
+	return &client
+}
+
+// expandRequestValue expands the environment variables value refers to,
+// failing when one of them isn't set
+func expandRequestValue(setting, host, value string) (string, error) {
+	var missing []string
+	expanded := os.Expand(value, func(name string) string {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("%s for %s refers to %s, which %s not set", setting, host, strings.Join(missing, ", "),
+			pluralize(len(missing), "is", "are"))
+	}
+	return expanded, nil
+}
+
+// redactRequestError replaces the request URL in a client error, which
+// carries the added query parameters, with the URL as configured
+func redactRequestError(err error, publicURL string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		redacted := *urlErr
+		redacted.URL = publicURL
+		return &redacted
+	}
+	return err
+}
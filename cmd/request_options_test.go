@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestDownloadWithRequestOptions(t *testing.T) {
+	t.Setenv("MIRROR_KEY", "s3cret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "s3cret" || r.URL.Query().Get("token") != "t-s3cret" || r.URL.Query().Get("v") != "1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("console.log('mirror');"))
+	}))
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	t.Cleanup(func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	})
+
+	task := DownloadTask{
+		FilePath: "app.js",
+		DestPath: filepath.Join(t.TempDir(), "app.js"),
+		URL:      server.URL + "/app.js?v=1",
+		Reason:   "missing",
+		Headers:  frontend_config.HostValues{host: {"X-Api-Key": "${MIRROR_KEY}"}},
+		Query:    frontend_config.HostValues{host: {"token": "t-$MIRROR_KEY"}},
+	}
+	if _, err := downloadFileWithTask(task); err != nil {
+		t.Fatalf("downloadFileWithTask() error = %v", err)
+	}
+
+	// Plans leave the headers and query out entirely
+	data, _ := json.Marshal(task)
+	if strings.Contains(string(data), "MIRROR_KEY") || strings.Contains(string(data), "s3cret") {
+		t.Errorf("task JSON = %s", data)
+	}
+
+	// A missing variable fails before anything is sent
+	task.Headers = frontend_config.HostValues{host: {"X-Api-Key": "${UNSET_MIRROR_KEY}"}}
+	_, err := downloadFileWithTask(task)
+	if err == nil || err.Error() != "headers.X-Api-Key for "+host+" refers to UNSET_MIRROR_KEY, which is not set" {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestRequestOptionsStayOnTheirHost(t *testing.T) {
+	t.Setenv("MIRROR_KEY", "s3cret")
+	var leaked []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "" || r.URL.Query().Get("key") != "" {
+			leaked = append(leaked, r.URL.Path)
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(other.Close)
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/redirected.js", http.StatusFound)
+	}))
+	t.Cleanup(mirror.Close)
+
+	mirrorHost := strings.TrimPrefix(mirror.URL, "http://")
+	opts := requestOptions{
+		Headers: frontend_config.HostValues{mirrorHost: {"X-Api-Key": "$MIRROR_KEY"}},
+		Query:   frontend_config.HostValues{mirrorHost: {"key": "$MIRROR_KEY"}},
+	}
+
+	// Another host gets nothing, whether requested directly or redirected to
+	for _, url := range []string{other.URL + "/direct.js", mirror.URL + "/app.js"} {
+		if _, _, _, err := fetchConditional(url, httpValidators{}, opts); err != nil {
+			t.Fatalf("fetchConditional(%s) error = %v", url, err)
+		}
+	}
+	if len(leaked) > 0 {
+		t.Errorf("request options sent to another host for %v", leaked)
+	}
+}
+
+func TestApplyLoadsRequestOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	os.WriteFile(path, []byte(`destination: ./frontend
+headers:
+  mirror.example.com:
+    X-Api-Key: ${MIRROR_KEY}
+libraries:
+  widgets:
+    version: 2.1.0
+    query:
+      mirror.example.com:
+        token: ${WIDGETS_TOKEN}
+`), 0644)
+
+	tasks := []DownloadTask{{LibraryName: "widgets"}}
+	if err := loadRequestOptions(path, tasks); err != nil {
+		t.Fatalf("loadRequestOptions() error = %v", err)
+	}
+	if tasks[0].Headers.ForHost("mirror.example.com")["X-Api-Key"] != "${MIRROR_KEY}" ||
+		tasks[0].Query.ForHost("mirror.example.com")["token"] != "${WIDGETS_TOKEN}" {
+		t.Errorf("task options = %v, %v", tasks[0].Headers, tasks[0].Query)
+	}
+
+	// A plan whose config is gone still applies, without extra options
+	if err := loadRequestOptions(filepath.Join(t.TempDir(), "missing.yaml"), tasks); err != nil {
+		t.Errorf("loadRequestOptions() error = %v", err)
+	}
+}
+
+func TestValidateRequestOptions(t *testing.T) {
+	config := &frontend_config.FrontendConfig{
+		Headers: frontend_config.HostValues{"mirror.example.com": {"X-Api-Key": "$KEY"}},
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "3.7.1", Headers: frontend_config.HostValues{"mirror.example.com": {"Bad Header": "x"}}},
+		},
+	}
+	if err := validateConfig(config); err == nil || err.Error() != `invalid header name "Bad Header" for jquery` {
+		t.Errorf("validateConfig() = %v", err)
+	}
+
+	config.Libraries["jquery"] = frontend_config.LibraryConfig{Version: "3.7.1", Query: frontend_config.HostValues{"mirror.example.com": {"": "x"}}}
+	if err := validateConfig(config); err == nil || err.Error() != "empty query parameter name for jquery" {
+		t.Errorf("validateConfig() = %v", err)
+	}
+
+	// The keys are hosts, not URLs
+	config.Libraries["jquery"] = frontend_config.LibraryConfig{Version: "3.7.1"}
+	config.Headers = frontend_config.HostValues{"https://mirror.example.com": {"X-Api-Key": "$KEY"}}
+	if err := validateConfig(config); err == nil || !strings.Contains(err.Error(), `invalid host "https://mirror.example.com"`) {
+		t.Errorf("validateConfig() = %v", err)
+	}
+}
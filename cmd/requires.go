@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// pseudoVersionPattern matches the pseudo-versions Go stamps on builds of
+// untagged commits (e.g. "v0.0.0-20250101120000-0123456789ab+dirty")
+var pseudoVersionPattern = regexp.MustCompile(`[-.]\d{14}-[0-9a-f]{12}(\+.*)?$`)
+
+// isDevelopmentBuild reports whether version comes from a development build
+// rather than a release
+func isDevelopmentBuild(version string) bool {
+	switch version {
+	case "", "dev", "(devel)":
+		return true
+	}
+	return pseudoVersionPattern.MatchString(version)
+}
+
+// checkRequiredVersion fails when the running smfaman doesn't satisfy the
+// config's requires range. Development builds, which have no release
+// version, are not checked.
+func checkRequiredVersion(path string, config *frontend_config.FrontendConfig) error {
+	if config.Requires == "" {
+		return nil
+	}
+	r, err := frontend_mgr.ParseVersionRange(config.Requires)
+	if err != nil {
+		return configError(fmt.Errorf("invalid requires %q in %s: %w", config.Requires, path, err))
+	}
+
+	built := getBuildInfo().Version
+	if isDevelopmentBuild(built) {
+		return nil
+	}
+	running := frontend_mgr.NormalizeVersion(built)
+	if !frontend_mgr.IsExactVersion(running) {
+		return nil
+	}
+	// A prerelease of a newer version still has that version's features
+	release, _, _ := strings.Cut(running, "+")
+	release, _, _ = strings.Cut(release, "-")
+	if r.MaxSatisfying([]string{release}) != "" {
+		return nil
+	}
+	return configError(fmt.Errorf("%s requires smfaman %s, but this is smfaman %s; please upgrade smfaman to use this config",
+		path, config.Requires, running))
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckRequiredVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	os.WriteFile(path, []byte("requires: \">=0.5.0\"\ndestination: ./frontend\nlibraries: {}\n"), 0644)
+
+	oldVersion := version
+	t.Cleanup(func() { version = oldVersion })
+
+	for _, running := range []string{"0.5.0", "v1.2.3", "0.6.0-rc.1"} {
+		version = running
+		if _, err := loadConfig(path); err != nil {
+			t.Errorf("smfaman %s: loadConfig() error = %v", running, err)
+		}
+	}
+
+	version = "0.4.2"
+	_, err := loadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "requires smfaman >=0.5.0, but this is smfaman 0.4.2; please upgrade smfaman") {
+		t.Errorf("loadConfig() error = %v", err)
+	}
+	if exitCode(err) != exitConfig {
+		t.Errorf("exit code %d, want %d", exitCode(err), exitConfig)
+	}
+
+	// Local go builds are stamped with a pseudo-version and aren't checked
+	for _, running := range []string{"v0.0.0-20261015120000-0123456789ab", "v0.0.0-20261015120000-0123456789ab+dirty", "v0.4.3-0.20261015120000-0123456789ab", "(devel)"} {
+		version = running
+		if _, err := loadConfig(path); err != nil {
+			t.Errorf("smfaman %s: loadConfig() error = %v", running, err)
+		}
+	}
+
+	// An invalid range is reported even by builds that skip the check
+	os.WriteFile(path, []byte("requires: \">=banana\"\ndestination: ./frontend\nlibraries: {}\n"), 0644)
+	version = "dev"
+	if _, err := loadConfig(path); err == nil || !strings.Contains(err.Error(), `invalid requires ">=banana"`) {
+		t.Errorf("loadConfig() error = %v", err)
+	}
+}
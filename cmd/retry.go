@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// retrySleep waits between download attempts (replaced in tests)
+var retrySleep = time.Sleep
+
+// httpStatusError reports an unexpected HTTP status from a download
+type httpStatusError struct {
+	StatusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("server returned status %d", e.StatusCode)
+}
+
+// isRetryableError reports whether a download error is likely transient
+func isRetryableError(err error) bool {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// withRetry runs fn, retrying transient failures up to syncRetries times
+// with exponential backoff starting at syncRetryBackoff
+func withRetry(url string, fn func() error) error {
+	backoff := syncRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= syncRetries || !isRetryableError(err) || frontend_mgr.RequestContext.Err() != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v (retry %d/%d in %s)\n", url, err, attempt+1, syncRetries, backoff)
+		retrySleep(backoff)
+		backoff *= 2
+	}
+}
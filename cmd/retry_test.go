@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// withTestRetries configures retries without sleeping for the duration of a test
+func withTestRetries(t *testing.T, retries int) *[]time.Duration {
+	oldRetries, oldBackoff, oldSleep := syncRetries, syncRetryBackoff, retrySleep
+	t.Cleanup(func() {
+		syncRetries, syncRetryBackoff, retrySleep = oldRetries, oldBackoff, oldSleep
+	})
+
+	var sleeps []time.Duration
+	syncRetries = retries
+	syncRetryBackoff = 100 * time.Millisecond
+	retrySleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return &sleeps
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"server error", &httpStatusError{StatusCode: 503}, true},
+		{"rate limited", &httpStatusError{StatusCode: 429}, true},
+		{"not found", &httpStatusError{StatusCode: 404}, false},
+		{"connection reset", fmt.Errorf("failed to download: %w", syscall.ECONNRESET), true},
+		{"unexpected EOF", fmt.Errorf("failed to read response: %w", io.ErrUnexpectedEOF), true},
+		{"other error", errors.New("no such host"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.expected {
+				t.Errorf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDownloadFileToMemoryRetriesTransientFailures(t *testing.T) {
+	sleeps := withTestRetries(t, 3)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	data, err := downloadFileToMemory(server.URL)
+	if err != nil {
+		t.Fatalf("downloadFileToMemory failed: %v", err)
+	}
+	if string(data) != "ok" {
+		t.Errorf("expected body %q, got %q", "ok", string(data))
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 requests, got %d", requests.Load())
+	}
+
+	// Backoff doubles after each attempt
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+	if len(*sleeps) != len(expected) {
+		t.Fatalf("expected sleeps %v, got %v", expected, *sleeps)
+	}
+	for i := range expected {
+		if (*sleeps)[i] != expected[i] {
+			t.Errorf("expected sleeps %v, got %v", expected, *sleeps)
+		}
+	}
+}
+
+func TestDownloadFileToMemoryGivesUp(t *testing.T) {
+	withTestRetries(t, 2)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	if _, err := downloadFileToMemory(server.URL); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if requests.Load() != 3 {
+		t.Errorf("expected 3 requests (1 attempt + 2 retries), got %d", requests.Load())
+	}
+}
+
+func TestDownloadFileToMemoryDoesNotRetryClientErrors(t *testing.T) {
+	withTestRetries(t, 3)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := downloadFileToMemory(server.URL); err == nil {
+		t.Fatal("expected error for 404")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected 1 request, got %d", requests.Load())
+	}
+}
+
+func TestDownloadStopsAtDeadline(t *testing.T) {
+	sleeps := withTestRetries(t, 3)
+
+	oldContext := frontend_mgr.RequestContext
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	frontend_mgr.RequestContext = ctx
+	defer func() {
+		cancel()
+		frontend_mgr.RequestContext = oldContext
+	}()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-r.Context().Done() // A stuck CDN
+	}))
+	defer server.Close()
+
+	_, err := downloadFileToMemory(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to stop the download, got %v", err)
+	}
+	if exitCode(err) != exitNetwork {
+		t.Errorf("exit code %d, want %d", exitCode(err), exitNetwork)
+	}
+	if requests.Load() != 1 || len(*sleeps) != 0 {
+		t.Errorf("retried after the deadline: %d requests, sleeps %v", requests.Load(), *sleeps)
+	}
+}
+
+func TestRunSimpleDownloadIsolatesFailures(t *testing.T) {
+	withTestRetries(t, 0)
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	defer func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.js" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	tasks := []DownloadTask{
+		{LibraryName: "lib", Version: "1.0.0", FilePath: "missing.js", URL: server.URL + "/missing.js", DestPath: filepath.Join(tmpDir, "missing.js")},
+		{LibraryName: "lib", Version: "1.0.0", FilePath: "ok.js", URL: server.URL + "/ok.js", DestPath: filepath.Join(tmpDir, "ok.js")},
+	}
+
+	summary, err := runSimpleDownload(tasks, io.Discard)
+	if err == nil {
+		t.Error("expected error reporting the failed file")
+	}
+	if summary == nil {
+		t.Fatal("expected summary even when files fail")
+	}
+	if summary.Files != 1 {
+		t.Errorf("expected 1 downloaded file, got %d", summary.Files)
+	}
+	if len(summary.Failed) != 1 || summary.Failed[0].File != "missing.js" {
+		t.Errorf("expected missing.js to be reported as failed, got %+v", summary.Failed)
+	}
+}
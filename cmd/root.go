@@ -22,8 +22,11 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -47,7 +50,13 @@ smfaman helps you:
   - Work with frontend libraries without npm/yarn overhead
 
 Use the --frontend-config flag to specify your configuration file (default: smartfrontend.yaml).
-The global configuration file is stored at $HOME/.smfaman.yaml.`,
+Tool-level settings (cdn, cache_ttl, concurrency, color, theme, timeout, frontend_config) are
+read from smfaman/settings.yaml in the user config directory ($XDG_CONFIG_HOME,
+~/Library/Application Support or %AppData%), falling back to the legacy
+$HOME/.smfaman.yaml. SMFAMAN_<SETTING> environment variables override the file
+and flags override both. The CDN cache lives in
+smfaman/ under the user cache directory ($XDG_CACHE_HOME, ~/Library/Caches
+or %LocalAppData%); an existing ~/.smfaman-cache is moved there on first use.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -57,24 +66,69 @@ The global configuration file is stored at $HOME/.smfaman.yaml.`,
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
+	cancelTimeout()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
+// exitWithError prints a command's error and exits with the code for its
+// kind (see exit_codes.go). Every command's Run ends through it on failure.
+func exitWithError(err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("%w (the --timeout of %s ran out)", err, settingsTimeout())
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	os.Exit(exitCode(err))
+}
+
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, initSettings, initColor, initTheme, initCache, initRegistry)
+	rootCmd.PersistentPostRun = func(cmd *cobra.Command, args []string) {
+		printOutdatedNotice(cmd)
+	}
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.smfaman.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "settings file (default is $XDG_CONFIG_HOME/smfaman/settings.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&FrontendConfig, "frontend-config", "f", "smartfrontend.yaml", "frontend configuration file")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored and styled output")
+	rootCmd.PersistentFlags().String("theme", "", "TUI color theme: default, light, high-contrast or monochrome")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "no-cache", false, "bypass cached CDN metadata and fetch fresh data (still updates the cache)")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "same as --no-cache")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "give up on CDN and registry requests once the command has run this long, e.g. 2m (0 for no limit)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "answer yes to all confirmation prompts (or set SMFAMAN_ASSUME_YES)")
+	rootCmd.PersistentFlags().BoolVar(&accessibleMode, "accessible", false, "use plain line-by-line prompts instead of full-screen TUIs, e.g. for screen readers (or set SMFAMAN_ACCESSIBLE)")
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	rootCmd.Version = fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
+	viper.BindPFlag(settingFrontendConfig, rootCmd.PersistentFlags().Lookup("frontend-config"))
+	viper.BindPFlag(settingTheme, rootCmd.PersistentFlags().Lookup("theme"))
+	viper.BindPFlag(settingTimeout, rootCmd.PersistentFlags().Lookup("timeout"))
+	rootCmd.Version = getBuildInfo().Version
+	rootCmd.SetVersionTemplate(getBuildInfo().String())
+}
+
+// settingsFilePath returns the tool settings file: smfaman/settings.yaml in
+// the user config directory, or the legacy ~/.smfaman.yaml when only that
+// exists. It returns "" when there is neither.
+func settingsFilePath() string {
+	if configDir, err := os.UserConfigDir(); err == nil {
+		path := filepath.Join(configDir, "smfaman", "settings.yaml")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		legacy := filepath.Join(home, ".smfaman.yaml")
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return ""
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -82,18 +136,13 @@ func initConfig() {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		// Search config in home directory with name ".smfaman" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".smfaman")
+	} else if path := settingsFilePath(); path != "" {
+		viper.SetConfigFile(path)
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// read in SMFAMAN_* environment variables that match
+	viper.SetEnvPrefix("smfaman")
+	viper.AutomaticEnv()
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSettingsFilePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configHome := filepath.Join(home, "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CONFIG_HOME is only honoured on Linux")
+	}
+
+	if got := settingsFilePath(); got != "" {
+		t.Errorf("settingsFilePath() = %q with no settings file, want empty", got)
+	}
+
+	legacy := filepath.Join(home, ".smfaman.yaml")
+	writeTestFile(t, legacy, "cache: true\n")
+	if got := settingsFilePath(); got != legacy {
+		t.Errorf("settingsFilePath() = %q, want legacy %q", got, legacy)
+	}
+
+	current := filepath.Join(configHome, "smfaman", "settings.yaml")
+	writeTestFile(t, current, "cache: true\n")
+	if got := settingsFilePath(); got != current {
+		t.Errorf("settingsFilePath() = %q, want %q", got, current)
+	}
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
 )
 
@@ -14,6 +16,10 @@ var (
 	searchLimit       int
 	searchCDN         string
 	searchJSON        bool
+	searchKeywords    []string
+	searchScope       string
+	searchExact       bool
+	searchShowHistory bool
 )
 
 // searchCmd represents the search command
@@ -31,6 +37,9 @@ Examples:
   smfaman search react --interactive
   smfaman search react -i
 
+  # In interactive mode, mark packages with space and press A to add
+  # all marked packages (latest versions) to the config in one go
+
   # Interactive mode with query prompt
   smfaman search --interactive
   smfaman search -i
@@ -45,6 +54,25 @@ Examples:
   # Output as JSON (for automation)
   smfaman search lodash --json
 
+  # Filter by keyword or restrict to an npm scope
+  smfaman search icons --keyword svg
+  smfaman search --scope @fortawesome
+  smfaman search icons --scope '@fortawesome/*'
+
+  # Look up a single package by its exact name
+  smfaman search react --exact
+
+  # List recent searches and packages picked from results
+  smfaman search --history
+
+Well-known names that differ from the published package (fontawesome,
+tailwind, htmx, ...) point at the real package; with --exact they are looked
+up under it directly.
+
+In interactive mode, filters can also be typed into the query as
+'scope:@fortawesome' or 'keyword:svg', and Ctrl+E toggles exact-name mode.
+Up and down in the query prompt recall recent searches.
+
 Supported CDN values:
   all      - Search all CDNs (default)
   cdnjs    - Search only CDNJS
@@ -60,34 +88,63 @@ func init() {
 	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 20, "Maximum number of results to return")
 	searchCmd.Flags().StringVarP(&searchCDN, "cdn", "c", "all", "Which CDN to search (all, cdnjs, npm)")
 	searchCmd.Flags().BoolVarP(&searchJSON, "json", "j", false, "Output results as JSON")
+	searchCmd.Flags().StringArrayVarP(&searchKeywords, "keyword", "k", nil, "Only show packages with this keyword (can be specified multiple times)")
+	searchCmd.Flags().StringVar(&searchScope, "scope", "", "Only show packages in this npm scope (e.g. @fortawesome)")
+	searchCmd.Flags().BoolVarP(&searchExact, "exact", "e", false, "Look up a single package by exact name instead of searching")
+	searchCmd.Flags().BoolVar(&searchShowHistory, "history", false, "List recent searches and packages picked from results")
 }
 
 func runSearch(cmd *cobra.Command, args []string) {
+	if searchShowHistory {
+		if err := printSearchHistory(searchJSON); err != nil {
+			exitWithError(err)
+		}
+		return
+	}
+
 	var query string
 	if len(args) > 0 {
 		query = args[0]
 	}
 
 	if searchInteractive {
-		// Run interactive TUI
-		runSearchTUI(query)
-		return
+		if query == "" {
+			if err := requireTerminal("search --interactive", "pass a query to search without it"); err != nil {
+				exitWithError(err)
+			}
+		}
+		if interactiveAvailable() {
+			// Run interactive TUI
+			runSearchTUI(query)
+			return
+		}
 	}
 
-	// CLI mode requires a query
-	if query == "" {
+	filter := searchFilterFromFlags()
+
+	// CLI mode requires a query (a scope alone is enough for npm searches)
+	if query == "" && (searchExact || filter.Scope == "") {
 		fmt.Println("Error: query argument is required in CLI mode")
 		fmt.Println("Use --interactive or -i flag to search interactively without providing a query")
 		return
 	}
 
+	if pkg, ok := resolvePackageAlias(query); ok && searchExact {
+		if !searchJSON {
+			fmt.Printf("• %s is published as %s\n\n", query, pkg)
+		}
+		query = pkg
+	}
+
 	// Run CLI mode
-	results, err := performSearch(query, searchCDN, searchLimit)
+	results, err := performSearch(query, searchCDN, searchLimit, filter, searchExact)
 	if err != nil {
 		fmt.Printf("Error searching for packages: %v\n", err)
 		return
 	}
+	recordSearchQuery(query)
 
+	printAliasSearchHint(query, results, searchJSON)
 	if len(results) == 0 {
 		fmt.Printf("No packages found matching '%s'\n", query)
 		return
@@ -102,20 +159,188 @@ func runSearch(cmd *cobra.Command, args []string) {
 	}
 }
 
-// performSearch executes the search based on CDN selection
-func performSearch(query, cdn string, limit int) ([]frontend_mgr.SearchResult, error) {
+// searchFilterFromFlags builds the result filter from the --keyword and --scope flags
+func searchFilterFromFlags() frontend_mgr.SearchFilter {
+	return frontend_mgr.SearchFilter{
+		Keywords: searchKeywords,
+		Scope:    searchScope,
+	}
+}
+
+// performSearch executes the search based on CDN selection. In exact mode the
+// query is treated as a package name and looked up directly.
+func performSearch(query, cdn string, limit int, filter frontend_mgr.SearchFilter, exact bool) ([]frontend_mgr.SearchResult, error) {
+	if exact {
+		results, err := lookupExactPackage(query, cdn)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Apply(results), nil
+	}
+
 	switch strings.ToLower(cdn) {
 	case "cdnjs":
-		return frontend_mgr.SearchCdnjs(query, limit)
+		results, err := frontend_mgr.SearchCdnjs(query, limit)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Apply(results), nil
 	case "npm":
-		return frontend_mgr.SearchNpm(query, limit)
+		results, err := frontend_mgr.SearchNpm(filter.NpmQuery(query), limit)
+		if err != nil {
+			return nil, err
+		}
+		return filter.Apply(results), nil
 	case "all":
-		return frontend_mgr.SearchAllCDNs(query, limit)
+		return frontend_mgr.SearchAllCDNsFiltered(query, limit, filter)
 	default:
 		return nil, fmt.Errorf("unsupported CDN: %s (supported: all, cdnjs, npm)", cdn)
 	}
 }
 
+// lookupExactPackage looks up a single package by name on the selected CDN
+func lookupExactPackage(name, cdn string) ([]frontend_mgr.SearchResult, error) {
+	var result *frontend_mgr.SearchResult
+	var err error
+
+	switch strings.ToLower(cdn) {
+	case "cdnjs":
+		result, err = frontend_mgr.LookupCdnjsPackage(name)
+	case "npm":
+		result, err = frontend_mgr.LookupNpmPackage(name)
+	case "all":
+		return frontend_mgr.LookupAllCDNs(name)
+	default:
+		return nil, fmt.Errorf("unsupported CDN: %s (supported: all, cdnjs, npm)", cdn)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if result.Name == "" {
+		return nil, nil
+	}
+	return []frontend_mgr.SearchResult{*result}, nil
+}
+
+// parseSearchQuery extracts inline filter qualifiers from an interactive query.
+// Supported qualifiers: "scope:@name" and "keyword:a,b" (or "keywords:").
+func parseSearchQuery(input string) (string, frontend_mgr.SearchFilter) {
+	var filter frontend_mgr.SearchFilter
+	var terms []string
+
+	for _, field := range strings.Fields(input) {
+		switch {
+		case strings.HasPrefix(field, "scope:"):
+			filter.Scope = strings.TrimPrefix(field, "scope:")
+		case strings.HasPrefix(field, "keywords:"), strings.HasPrefix(field, "keyword:"):
+			value := field[strings.Index(field, ":")+1:]
+			for _, k := range strings.Split(value, ",") {
+				if k = strings.TrimSpace(k); k != "" {
+					filter.Keywords = append(filter.Keywords, k)
+				}
+			}
+		default:
+			terms = append(terms, field)
+		}
+	}
+
+	return strings.Join(terms, " "), filter
+}
+
+// bulkAddedPackage describes a package written to the config by a bulk add
+type bulkAddedPackage struct {
+	Name    string
+	Version string
+	CDN     frontend_config.CDN
+}
+
+// bulkAddSummary reports the outcome of adding several search results at once
+type bulkAddSummary struct {
+	Added   []bulkAddedPackage
+	Skipped []string         // Packages already present in the config
+	Failed  map[string]error // Packages whose latest version could not be resolved
+}
+
+// addSearchResultsToConfig resolves the latest version of each search result and
+// writes all of them into the config file with a single save
+func addSearchResultsToConfig(configPath string, results []frontend_mgr.SearchResult) (bulkAddSummary, error) {
+	summary := bulkAddSummary{Failed: make(map[string]error)}
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return summary, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for _, result := range results {
+		if _, exists := config.Libraries[result.Name]; exists {
+			summary.Skipped = append(summary.Skipped, result.Name)
+			continue
+		}
+
+		cdn := cdnForSearchResult(config, result)
+		versions, latest, err := fetchVersionsForUpgrade(result.Name, cdn)
+		if err != nil {
+			summary.Failed[result.Name] = err
+			continue
+		}
+		if latest == "" {
+			latest = versions[0]
+		}
+
+		libConfig := frontend_config.LibraryConfig{Version: latest}
+		if cdn != config.CDN {
+			libConfig.CDN = cdn
+		}
+		config.Libraries[result.Name] = libConfig
+
+		summary.Added = append(summary.Added, bulkAddedPackage{
+			Name:    result.Name,
+			Version: latest,
+			CDN:     cdn,
+		})
+	}
+
+	if len(summary.Added) == 0 {
+		return summary, nil
+	}
+
+	if err := saveConfig(configPath, config); err != nil {
+		return summary, fmt.Errorf("failed to save config: %w", err)
+	}
+
+	return summary, nil
+}
+
+// cdnForSearchResult picks the CDN to use for a search result. CDNJS results
+// are pinned to cdnjs, npm results use the config default (or unpkg when the
+// default is cdnjs, since npm packages are not necessarily mirrored there).
+func cdnForSearchResult(config *frontend_config.FrontendConfig, result frontend_mgr.SearchResult) frontend_config.CDN {
+	defaultCDN := config.CDN
+	if !frontend_config.IsValidCDN(defaultCDN) {
+		defaultCDN = settingsCDN()
+	}
+
+	if result.CDN == string(frontend_config.CDNCdnjs) {
+		return frontend_config.CDNCdnjs
+	}
+
+	if defaultCDN == frontend_config.CDNCdnjs {
+		return frontend_config.CDNUnpkg
+	}
+	return defaultCDN
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // outputJSON outputs results as JSON
 func outputJSON(results []frontend_mgr.SearchResult) {
 	data, err := json.MarshalIndent(results, "", "  ")
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// searchHistoryLimit is the number of queries and packages kept in the
+// search history
+const searchHistoryLimit = 50
+
+// searchHistory holds recent search queries and the packages picked from
+// search results, most recent first
+type searchHistory struct {
+	Queries  []string `json:"queries"`
+	Packages []string `json:"packages"`
+}
+
+// searchHistoryPath returns the search history file, smfaman/search_history.json
+// in the user config directory next to settings.yaml
+func searchHistoryPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "smfaman", "search_history.json"), nil
+}
+
+// loadSearchHistory reads the search history. A missing or unreadable file
+// is an empty history; the history is a convenience, never a reason to fail.
+func loadSearchHistory() searchHistory {
+	var history searchHistory
+	path, err := searchHistoryPath()
+	if err != nil {
+		return history
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return history
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return searchHistory{}
+	}
+	return history
+}
+
+// save writes the search history file
+func (h searchHistory) save() error {
+	path, err := searchHistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// rememberRecent moves value to the front of entries, dropping duplicates and
+// anything past searchHistoryLimit
+func rememberRecent(entries []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return entries
+	}
+
+	recent := []string{value}
+	for _, entry := range entries {
+		if entry != value && len(recent) < searchHistoryLimit {
+			recent = append(recent, entry)
+		}
+	}
+	return recent
+}
+
+// recordSearchQuery adds a query to the search history. Errors are ignored
+// so a read-only config directory doesn't break searching.
+func recordSearchQuery(query string) {
+	if strings.TrimSpace(query) == "" {
+		return
+	}
+	history := loadSearchHistory()
+	history.Queries = rememberRecent(history.Queries, query)
+	_ = history.save()
+}
+
+// recordSearchPackages adds packages picked from search results to the
+// search history, the first one ending up most recent
+func recordSearchPackages(names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	history := loadSearchHistory()
+	for i := len(names) - 1; i >= 0; i-- {
+		history.Packages = rememberRecent(history.Packages, names[i])
+	}
+	_ = history.save()
+}
+
+// printSearchHistory lists recent queries and packages for search --history
+func printSearchHistory(asJSON bool) error {
+	history := loadSearchHistory()
+
+	if asJSON {
+		if history.Queries == nil {
+			history.Queries = []string{}
+		}
+		if history.Packages == nil {
+			history.Packages = []string{}
+		}
+		data, err := json.MarshalIndent(history, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(history.Queries) == 0 && len(history.Packages) == 0 {
+		fmt.Println("No search history yet")
+		return nil
+	}
+
+	if len(history.Queries) > 0 {
+		fmt.Println("Recent searches:")
+		for _, query := range history.Queries {
+			fmt.Printf("  • %s\n", query)
+		}
+	}
+	if len(history.Packages) > 0 {
+		if len(history.Queries) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("Recent packages:")
+		for _, name := range history.Packages {
+			fmt.Printf("  • %s\n", name)
+		}
+	}
+	return nil
+}
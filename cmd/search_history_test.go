@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// useSearchHistoryDir points the search history at a temporary config
+// directory
+func useSearchHistoryDir(t *testing.T) string {
+	t.Helper()
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG_CONFIG_HOME is only honoured on Linux")
+	}
+	return configHome
+}
+
+func TestRememberRecent(t *testing.T) {
+	got := rememberRecent([]string{"vue", "react", "lodash"}, "react")
+	if fmt.Sprint(got) != "[react vue lodash]" {
+		t.Errorf("rememberRecent moved react to %v", got)
+	}
+	if got := rememberRecent([]string{"vue"}, "  "); fmt.Sprint(got) != "[vue]" {
+		t.Errorf("rememberRecent added a blank entry: %v", got)
+	}
+
+	var many []string
+	for i := range searchHistoryLimit + 10 {
+		many = rememberRecent(many, fmt.Sprintf("q%d", i))
+	}
+	if len(many) != searchHistoryLimit || many[0] != fmt.Sprintf("q%d", searchHistoryLimit+9) {
+		t.Errorf("history has %d entries starting at %s, want %d starting with the newest", len(many), many[0], searchHistoryLimit)
+	}
+}
+
+func TestSearchHistoryPersistence(t *testing.T) {
+	configHome := useSearchHistoryDir(t)
+
+	if history := loadSearchHistory(); len(history.Queries) != 0 || len(history.Packages) != 0 {
+		t.Fatalf("expected an empty history without a file, got %+v", history)
+	}
+
+	recordSearchQuery("react")
+	recordSearchQuery("vue")
+	recordSearchQuery("react")
+	recordSearchPackages("vue", "pinia")
+
+	history := loadSearchHistory()
+	if fmt.Sprint(history.Queries) != "[react vue]" {
+		t.Errorf("Queries = %v, want [react vue]", history.Queries)
+	}
+	if fmt.Sprint(history.Packages) != "[vue pinia]" {
+		t.Errorf("Packages = %v, want [vue pinia]", history.Packages)
+	}
+
+	// A damaged file is an empty history rather than an error
+	path := filepath.Join(configHome, "smfaman", "search_history.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if history := loadSearchHistory(); len(history.Queries) != 0 {
+		t.Errorf("expected an empty history from a damaged file, got %+v", history)
+	}
+}
+
+func TestSearchTUIHistoryRecall(t *testing.T) {
+	useSearchHistoryDir(t)
+	recordSearchQuery("lodash")
+	recordSearchQuery("react")
+
+	var model tea.Model = newSearchTUIModel("")
+	press := func(key tea.KeyType) searchTUIModel {
+		t.Helper()
+		model, _ = model.Update(tea.KeyMsg{Type: key})
+		return model.(searchTUIModel)
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("vu")})
+
+	steps := []struct {
+		key  tea.KeyType
+		want string
+	}{
+		{tea.KeyUp, "react"},
+		{tea.KeyUp, "lodash"},
+		{tea.KeyUp, "lodash"}, // Oldest entry stays put
+		{tea.KeyDown, "react"},
+		{tea.KeyDown, "vu"}, // Back to the draft
+		{tea.KeyDown, "vu"},
+	}
+	for i, step := range steps {
+		if got := press(step.key).queryInput.Value(); got != step.want {
+			t.Errorf("step %d: query = %q, want %q", i, got, step.want)
+		}
+	}
+
+	// A new search becomes the most recent entry straight away
+	press(tea.KeyUp)
+	m := press(tea.KeyEnter)
+	if m.state != viewLoading || m.query != "react" {
+		t.Fatalf("enter didn't start a search for react (state %v, query %q)", m.state, m.query)
+	}
+	if fmt.Sprint(m.history.Queries) != "[react lodash]" || m.recallIndex != -1 {
+		t.Errorf("history after searching = %v (recall %d)", m.history.Queries, m.recallIndex)
+	}
+	if saved := loadSearchHistory(); fmt.Sprint(saved.Queries) != "[react lodash]" {
+		t.Errorf("saved history = %v", saved.Queries)
+	}
+}
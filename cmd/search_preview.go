@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// detailTab is a tab of the search package details
+type detailTab int
+
+const (
+	detailTabInfo detailTab = iota
+	detailTabReadme
+	detailTabFiles
+	detailTabCount
+)
+
+// detailTabNames are the tab labels, in tab order
+var detailTabNames = []string{"Info", "README", "Files"}
+
+// packagePreview holds the README and file list fetched for the package
+// shown in the details, so switching tabs doesn't fetch them again
+type packagePreview struct {
+	pkg          string // name@version the preview belongs to
+	readme       string
+	readmeErr    error
+	readmeLoaded bool
+	files        []CDNFile
+	filesErr     error
+	filesLoaded  bool
+	fetching     map[detailTab]bool
+}
+
+// Messages with fetched previews; pkg tells which package they belong to, in
+// case another package was opened in the meantime
+type packageReadmeMsg struct {
+	pkg    string
+	readme string
+	err    error
+}
+
+type packageFilesMsg struct {
+	pkg   string
+	files []CDNFile
+	err   error
+}
+
+// fetchPackageReadme downloads a package's README.md from unpkg (overridable
+// in tests)
+var fetchPackageReadme = func(name, version string) (string, error) {
+	data, err := downloadFileToMemory(cdnFileURL(name, version, frontend_config.CDNUnpkg, "README.md"))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// fetchPackageFiles lists the files of a package version (overridable in tests)
+var fetchPackageFiles = fetchFileList
+
+// fetchReadmeCmd fetches the README of name@version in the background
+func fetchReadmeCmd(name, version string) tea.Cmd {
+	return func() tea.Msg {
+		readme, err := fetchPackageReadme(name, version)
+		return packageReadmeMsg{pkg: name + "@" + version, readme: readme, err: err}
+	}
+}
+
+// fetchFilesCmd lists the files of name@version on cdn in the background
+func fetchFilesCmd(name, version, cdn string) tea.Cmd {
+	if cdn == "" {
+		cdn = string(frontend_config.CDNUnpkg)
+	}
+	return func() tea.Msg {
+		files, err := fetchPackageFiles(name, version, frontend_config.CDN(cdn))
+		return packageFilesMsg{pkg: name + "@" + version, files: files, err: err}
+	}
+}
+
+// wrapText wraps each line of text to width, keeping blank lines and
+// line breaks
+func wrapText(text string, width int) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if len(line) > width {
+			lines[i] = wordWrap(line, width)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fileTreeNode is a folder or file of a package's file tree
+type fileTreeNode struct {
+	name     string
+	size     int64
+	isDir    bool
+	children map[string]*fileTreeNode
+}
+
+// fileTree renders files as a tree with folders first and file sizes
+func fileTree(files []CDNFile) string {
+	root := &fileTreeNode{isDir: true, children: map[string]*fileTreeNode{}}
+	var total int64
+	for _, file := range files {
+		total += file.Size
+		node := root
+		parts := strings.Split(strings.Trim(path.Clean("/"+file.Path), "/"), "/")
+		for i, part := range parts {
+			child, ok := node.children[part]
+			if !ok {
+				child = &fileTreeNode{name: part, isDir: i < len(parts)-1, children: map[string]*fileTreeNode{}}
+				node.children[part] = child
+			}
+			if i == len(parts)-1 {
+				child.size = file.Size
+			}
+			node = child
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s, %s\n\n", len(files), pluralize(len(files), "file", "files"), formatBytes(total))
+	writeFileTree(&b, root, "")
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writeFileTree writes the children of node, each line starting with prefix
+func writeFileTree(b *strings.Builder, node *fileTreeNode, prefix string) {
+	children := make([]*fileTreeNode, 0, len(node.children))
+	for _, child := range node.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].isDir != children[j].isDir {
+			return children[i].isDir
+		}
+		return children[i].name < children[j].name
+	})
+
+	for i, child := range children {
+		connector, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		if child.isDir {
+			fmt.Fprintf(b, "%s%s%s/\n", prefix, connector, child.name)
+			writeFileTree(b, child, prefix+indent)
+		} else {
+			fmt.Fprintf(b, "%s%s%s  %s\n", prefix, connector, child.name, formatBytes(child.size))
+		}
+	}
+}
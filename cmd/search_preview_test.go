@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestFileTree(t *testing.T) {
+	got := fileTree([]CDNFile{
+		{Path: "/package.json", Size: 512},
+		{Path: "/dist/js/app.min.js", Size: 2048},
+		{Path: "/dist/css/app.min.css", Size: 1024},
+		{Path: "/README.md", Size: 100},
+	})
+	want := `4 files, 3.60 KB
+
+├── dist/
+│   ├── css/
+│   │   └── app.min.css  1.00 KB
+│   └── js/
+│       └── app.min.js  2.00 KB
+├── README.md  100 B
+└── package.json  512 B`
+	if got != want {
+		t.Errorf("fileTree() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSearchDetailTabs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	origReadme, origFiles := fetchPackageReadme, fetchPackageFiles
+	t.Cleanup(func() { fetchPackageReadme, fetchPackageFiles = origReadme, origFiles })
+	readmeFetches := 0
+	fetchPackageReadme = func(name, version string) (string, error) {
+		readmeFetches++
+		return "# " + name + "\n\nA library at " + version + ".", nil
+	}
+	fetchPackageFiles = func(name, version string, cdn frontend_config.CDN) ([]CDNFile, error) {
+		if cdn != "jsdelivr" {
+			t.Errorf("files listed from %s, want the result's CDN", cdn)
+		}
+		return nil, errors.New("offline")
+	}
+
+	var model tea.Model = newSearchTUIModel("")
+	send := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		var cmd tea.Cmd
+		model, cmd = model.Update(msg)
+		return cmd
+	}
+	// run delivers the messages of a fetch command back to the model
+	run := func(cmd tea.Cmd) {
+		t.Helper()
+		if cmd == nil {
+			t.Fatal("expected a fetch command")
+		}
+		send(cmd())
+	}
+	view := func() string { return model.(searchTUIModel).View() }
+
+	send(searchCompletedMsg{results: []frontend_mgr.SearchResult{{Name: "alpinejs", Version: "3.14.1", CDN: "jsdelivr"}}})
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(view(), "1 Info") || !strings.Contains(view(), "smfaman add alpinejs@3.14.1") {
+		t.Fatalf("details should open on the Info tab:\n%s", view())
+	}
+
+	cmd := send(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(view(), "Loading README...") {
+		t.Errorf("expected a loading message:\n%s", view())
+	}
+	run(cmd)
+	if !strings.Contains(view(), "A library at 3.14.1.") {
+		t.Errorf("README tab doesn't show the README:\n%s", view())
+	}
+
+	run(send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}}))
+	if !strings.Contains(view(), "Couldn't list the files: offline") {
+		t.Errorf("Files tab doesn't show the error:\n%s", view())
+	}
+
+	// Reopening the same package keeps what was fetched
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd := send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}}); cmd != nil || readmeFetches != 1 {
+		t.Errorf("README fetched %d times, want once", readmeFetches)
+	}
+
+	// Results for a package no longer shown are dropped
+	send(packageReadmeMsg{pkg: "htmx.org@2.0.0", readme: "htmx"})
+	if strings.Contains(view(), "htmx") {
+		t.Error("README of another package was shown")
+	}
+}
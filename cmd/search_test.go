@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
 )
 
@@ -12,11 +17,11 @@ func TestPerformSearch(t *testing.T) {
 	}
 
 	tests := []struct {
-		name      string
-		query     string
-		cdn       string
-		limit     int
-		wantError bool
+		name       string
+		query      string
+		cdn        string
+		limit      int
+		wantError  bool
 		minResults int
 	}{
 		{
@@ -55,7 +60,7 @@ func TestPerformSearch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, err := performSearch(tt.query, tt.cdn, tt.limit)
+			results, err := performSearch(tt.query, tt.cdn, tt.limit, frontend_mgr.SearchFilter{}, false)
 
 			if tt.wantError {
 				if err == nil {
@@ -253,6 +258,115 @@ func TestSearchResultItem(t *testing.T) {
 	}
 }
 
+func TestParseSearchQuery(t *testing.T) {
+	query, filter := parseSearchQuery("icons scope:@fortawesome keyword:svg,font keywords:free")
+
+	if query != "icons" {
+		t.Errorf("Expected query 'icons', got %q", query)
+	}
+	if filter.Scope != "@fortawesome" {
+		t.Errorf("Expected scope '@fortawesome', got %q", filter.Scope)
+	}
+	want := []string{"svg", "font", "free"}
+	if len(filter.Keywords) != len(want) {
+		t.Fatalf("Expected keywords %v, got %v", want, filter.Keywords)
+	}
+	for i, k := range want {
+		if filter.Keywords[i] != k {
+			t.Errorf("Keyword %d = %q, want %q", i, filter.Keywords[i], k)
+		}
+	}
+}
+
+func TestCdnForSearchResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		defaultCDN frontend_config.CDN
+		resultCDN  string
+		want       frontend_config.CDN
+	}{
+		{"CDNJS result", frontend_config.CDNUnpkg, "cdnjs", frontend_config.CDNCdnjs},
+		{"npm result with jsdelivr default", frontend_config.CDNJsdelivr, "unpkg, jsdelivr", frontend_config.CDNJsdelivr},
+		{"npm result with cdnjs default", frontend_config.CDNCdnjs, "npm", frontend_config.CDNUnpkg},
+		{"npm result without default", "", "npm", frontend_config.CDNUnpkg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &frontend_config.FrontendConfig{CDN: tt.defaultCDN}
+			got := cdnForSearchResult(config, frontend_mgr.SearchResult{Name: "pkg", CDN: tt.resultCDN})
+			if got != tt.want {
+				t.Errorf("cdnForSearchResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSearchResultsToConfigSkipsExisting(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+
+	config := frontend_config.FrontendConfig{
+		Destination: "./libs/{library_name}",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery": {Version: "3.7.1"},
+		},
+	}
+	data, _ := yaml.Marshal(&config)
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	before, _ := os.ReadFile(configPath)
+
+	summary, err := addSearchResultsToConfig(configPath, []frontend_mgr.SearchResult{
+		{Name: "jquery", CDN: "npm"},
+	})
+	if err != nil {
+		t.Fatalf("addSearchResultsToConfig failed: %v", err)
+	}
+
+	if len(summary.Skipped) != 1 || summary.Skipped[0] != "jquery" {
+		t.Errorf("Expected jquery to be skipped, got %v", summary.Skipped)
+	}
+	if len(summary.Added) != 0 {
+		t.Errorf("Expected nothing to be added, got %v", summary.Added)
+	}
+
+	// Config should not be rewritten when nothing was added
+	after, _ := os.ReadFile(configPath)
+	if string(before) != string(after) {
+		t.Error("Config file should be unchanged when nothing was added")
+	}
+}
+
+func TestSearchTUIMarkToggle(t *testing.T) {
+	m := newSearchTUIModel("")
+	updated, _ := m.Update(searchCompletedMsg{results: []frontend_mgr.SearchResult{
+		{Name: "react", Version: "18.2.0", CDN: "npm"},
+		{Name: "vue", Version: "3.4.0", CDN: "npm"},
+	}})
+	m = updated.(searchTUIModel)
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	updated, _ = m.Update(space)
+	m = updated.(searchTUIModel)
+	if !m.marked["react"] {
+		t.Fatal("Expected react to be marked after pressing space")
+	}
+
+	selected := m.markedResults()
+	if len(selected) != 1 || selected[0].Name != "react" {
+		t.Errorf("Expected only react to be selected, got %v", selected)
+	}
+
+	updated, _ = m.Update(space)
+	m = updated.(searchTUIModel)
+	if m.marked["react"] {
+		t.Error("Expected react to be unmarked after pressing space twice")
+	}
+}
+
 // Helper function
 func contains(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) >= len(substr) &&
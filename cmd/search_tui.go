@@ -8,22 +8,22 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+	"nexus-sds.com/smfaman/pkgs/tui"
 )
 
 // Styles
 var (
 	searchTitleStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("205")).
 				MarginLeft(2).
 				MarginBottom(1)
 
 	searchTableHeaderStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("99")).
 				PaddingLeft(2)
 
 	searchItemStyle = lipgloss.NewStyle().
@@ -31,7 +31,6 @@ var (
 
 	searchSelectedItemStyle = lipgloss.NewStyle().
 				PaddingLeft(1).
-				Foreground(lipgloss.Color("170")).
 				Bold(true)
 
 	searchPaginationStyle = list.DefaultStyles().
@@ -48,26 +47,29 @@ var (
 
 	detailTitleStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("205")).
 				MarginBottom(1)
 
 	detailLabelStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("99")).
 				Width(15)
 
-	detailValueStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("252"))
+	detailValueStyle = lipgloss.NewStyle()
 
 	detailBoxStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("99")).
 			Padding(1, 2).
 			MarginTop(1).
 			MarginLeft(2)
 
+	detailTabStyle = lipgloss.NewStyle().
+			Padding(0, 1)
+
+	detailActiveTabStyle = lipgloss.NewStyle().
+				Padding(0, 1).
+				Bold(true).
+				Underline(true)
+
 	inputPromptStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("205")).
 				Bold(true).
 				MarginLeft(2).
 				MarginBottom(1)
@@ -81,6 +83,8 @@ const (
 	viewSearchResults
 	viewPackageDetail
 	viewLoading
+	viewBulkAdding
+	viewBulkAddSummary
 )
 
 // Messages
@@ -89,6 +93,11 @@ type searchCompletedMsg struct {
 	err     error
 }
 
+type bulkAddCompletedMsg struct {
+	summary bulkAddSummary
+	err     error
+}
+
 // Search result item for the list
 type searchResultItem struct {
 	result frontend_mgr.SearchResult
@@ -105,6 +114,7 @@ type searchResultDelegate struct {
 	maxNameWidth    int
 	maxVersionWidth int
 	maxCDNWidth     int
+	marked          map[string]bool
 }
 
 func (d searchResultDelegate) Height() int                             { return 1 }
@@ -122,7 +132,12 @@ func (d searchResultDelegate) Render(w io.Writer, m list.Model, index int, listI
 	cdn := padRight(truncate(i.result.CDN, d.maxCDNWidth), d.maxCDNWidth)
 	desc := truncate(i.result.Description, 50)
 
-	line := fmt.Sprintf("%s  %s  %s  %s", name, version, cdn, desc)
+	mark := "[ ]"
+	if d.marked[i.result.Name] {
+		mark = "[x]"
+	}
+
+	line := fmt.Sprintf("%s %s  %s  %s  %s", mark, name, version, cdn, desc)
 
 	if index == m.Index() {
 		fmt.Fprint(w, searchSelectedItemStyle.Render("→ "+line))
@@ -132,18 +147,97 @@ func (d searchResultDelegate) Render(w io.Writer, m list.Model, index int, listI
 }
 
 // Main TUI model
+// searchQueryKeyMap holds the keys of the query prompt
+type searchQueryKeyMap struct {
+	Search      key.Binding
+	Older       key.Binding
+	Newer       key.Binding
+	ToggleExact key.Binding
+	Quit        key.Binding
+}
+
+var searchQueryKeys = searchQueryKeyMap{
+	Search:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "search")),
+	Older:       key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "recall an older search")),
+	Newer:       key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "recall a newer search")),
+	ToggleExact: key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "toggle exact-name lookup")),
+	Quit:        key.NewBinding(key.WithKeys("ctrl+c", "esc"), key.WithHelp("esc/ctrl+c", "quit")),
+}
+
+// searchResultsKeyMap holds the keys of the results list; list navigation
+// keys come from the list itself
+type searchResultsKeyMap struct {
+	Details   key.Binding
+	Mark      key.Binding
+	AddMarked key.Binding
+	NewSearch key.Binding
+	Back      key.Binding
+}
+
+var searchResultsKeys = searchResultsKeyMap{
+	Details:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "view details")),
+	Mark:      key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "mark")),
+	AddMarked: key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "add selected")),
+	NewSearch: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new search")),
+	Back:      key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q/esc", "back to the search prompt")),
+}
+
+// searchDetailBackKey leaves the package details
+var searchDetailBackKey = key.NewBinding(key.WithKeys("q", "esc", "enter"), key.WithHelp("q/esc/enter", "back to the results"))
+
+// searchDetailTabKeyMap holds the keys switching between the Info, README
+// and Files tabs of the package details
+type searchDetailTabKeyMap struct {
+	Next key.Binding
+	Prev key.Binding
+	Jump key.Binding
+}
+
+var searchDetailTabKeys = searchDetailTabKeyMap{
+	Next: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next tab")),
+	Prev: key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous tab")),
+	Jump: key.NewBinding(key.WithKeys("1", "2", "3"), key.WithHelp("1/2/3", "info, README, files")),
+}
+
+// searchSummaryKeyMap holds the keys of the bulk add summary
+type searchSummaryKeyMap struct {
+	Back key.Binding
+	Quit key.Binding
+}
+
+var searchSummaryKeys = searchSummaryKeyMap{
+	Back: key.NewBinding(key.WithKeys("esc", "enter"), key.WithHelp("esc/enter", "back to the results")),
+	Quit: key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+}
+
+// searchForceQuitKey quits the search TUI from any view
+var searchForceQuitKey = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+
 type searchTUIModel struct {
-	state         viewState
-	queryInput    textinput.Model
-	list          list.Model
-	delegate      searchResultDelegate
-	results       []frontend_mgr.SearchResult
-	selectedPkg   *frontend_mgr.SearchResult
-	query         string
-	err           error
-	quitting      bool
-	width         int
-	height        int
+	state       viewState
+	queryInput  textinput.Model
+	list        list.Model
+	delegate    searchResultDelegate
+	results     []frontend_mgr.SearchResult
+	selectedPkg *frontend_mgr.SearchResult
+	marked      map[string]bool
+	bulkSummary *bulkAddSummary
+	query       string
+	filter      frontend_mgr.SearchFilter
+	exact       bool
+	history     searchHistory
+	recallIndex int    // Position in history.Queries while recalling, -1 when not
+	recallDraft string // What was typed before recalling started
+	showHelp    bool
+	err         error
+	quitting    bool
+	width       int
+	height      int
+
+	// The package details show the README and files on their own tabs
+	detailTab  detailTab
+	preview    packagePreview
+	detailView viewport.Model
 }
 
 func newSearchTUIModel(initialQuery string) searchTUIModel {
@@ -153,15 +247,50 @@ func newSearchTUIModel(initialQuery string) searchTUIModel {
 	ti.CharLimit = 100
 	ti.Width = 60
 
+	m := searchTUIModel{
+		state:       viewQueryInput,
+		queryInput:  ti,
+		marked:      make(map[string]bool),
+		filter:      searchFilterFromFlags(),
+		exact:       searchExact,
+		history:     loadSearchHistory(),
+		recallIndex: -1,
+		detailView:  viewport.New(0, 0),
+	}
+
 	// If we have an initial query, start with that
 	if initialQuery != "" {
-		ti.SetValue(initialQuery)
+		m.queryInput.SetValue(initialQuery)
+		m.applyQueryInput()
+	}
+
+	return m
+}
+
+// quit ends the search
+func (m searchTUIModel) quit() (tea.Model, tea.Cmd) {
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// applyQueryInput sets the search query and filters from the query input,
+// merging inline qualifiers with the filters given on the command line
+func (m *searchTUIModel) applyQueryInput() bool {
+	query, inline := parseSearchQuery(m.queryInput.Value())
+
+	filter := searchFilterFromFlags()
+	filter.Keywords = append(append([]string{}, filter.Keywords...), inline.Keywords...)
+	if inline.Scope != "" {
+		filter.Scope = inline.Scope
 	}
 
-	return searchTUIModel{
-		state:      viewQueryInput,
-		queryInput: ti,
+	if query == "" && (m.exact || filter.Scope == "") {
+		return false
 	}
+
+	m.query = query
+	m.filter = filter
+	return true
 }
 
 func (m searchTUIModel) Init() tea.Cmd {
@@ -185,9 +314,49 @@ func (m searchTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.list.SetWidth(msg.Width)
 			m.list.SetHeight(msg.Height - 6)
 		}
+		if m.state == viewPackageDetail {
+			m.setDetailContent()
+		}
+		return m, nil
+
+	case tea.MouseMsg:
+		if m.state == viewSearchResults {
+			updateListMouse(&m.list, m.delegate, listViewTop+searchTableHeaderRows, msg)
+		}
+		if m.state == viewPackageDetail && m.detailTab != detailTabInfo {
+			m.detailView, _ = m.detailView.Update(msg)
+		}
+		return m, nil
+
+	case packageReadmeMsg:
+		if msg.pkg == m.preview.pkg {
+			m.preview.readme, m.preview.readmeErr, m.preview.readmeLoaded = msg.readme, msg.err, true
+			delete(m.preview.fetching, detailTabReadme)
+			m.setDetailContent()
+		}
+		return m, nil
+
+	case packageFilesMsg:
+		if msg.pkg == m.preview.pkg {
+			m.preview.files, m.preview.filesErr, m.preview.filesLoaded = msg.files, msg.err, true
+			delete(m.preview.fetching, detailTabFiles)
+			m.setDetailContent()
+		}
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.showHelp {
+			if key.Matches(msg, searchForceQuitKey) {
+				return m.quit()
+			}
+			m.showHelp = !helpClosed(msg)
+			return m, nil
+		}
+		if m.state != viewLoading && m.state != viewBulkAdding && helpRequested(msg, m.typing()) {
+			m.showHelp = true
+			return m, nil
+		}
+
 		switch m.state {
 		case viewQueryInput:
 			return m.updateQueryInput(msg)
@@ -195,17 +364,34 @@ func (m searchTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSearchResults(msg)
 		case viewPackageDetail:
 			return m.updatePackageDetail(msg)
+		case viewBulkAddSummary:
+			return m.updateBulkAddSummary(msg)
 		}
 
+	case bulkAddCompletedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.bulkSummary = &msg.summary
+		m.marked = make(map[string]bool)
+		m.delegate.marked = m.marked
+		m.list.SetDelegate(m.delegate)
+		m.state = viewBulkAddSummary
+		return m, nil
+
 	case searchCompletedMsg:
 		if msg.err != nil {
 			m.err = msg.err
 			m.quitting = true
 			return m, tea.Quit
 		}
+		m.err = nil
 
 		m.results = msg.results
 		m.state = viewSearchResults
+		m.marked = make(map[string]bool)
 
 		// Create list with results
 		items := make([]list.Item, len(msg.results))
@@ -243,6 +429,7 @@ func (m searchTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			maxNameWidth:    maxName,
 			maxVersionWidth: maxVersion,
 			maxCDNWidth:     maxCDN,
+			marked:          m.marked,
 		}
 
 		width := m.width
@@ -263,17 +450,10 @@ func (m searchTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		l.Styles.HelpStyle = searchHelpStyle
 
 		l.AdditionalShortHelpKeys = func() []key.Binding {
-			return []key.Binding{
-				key.NewBinding(
-					key.WithKeys("enter"),
-					key.WithHelp("enter", "view details"),
-				),
-				key.NewBinding(
-					key.WithKeys("n"),
-					key.WithHelp("n", "new search"),
-				),
-			}
+			keys := searchResultsKeys
+			return []key.Binding{keys.Details, keys.Mark, keys.AddMarked, keys.NewSearch}
 		}
+		useHelpOverlay(&l)
 
 		m.list = l
 		return m, nil
@@ -291,17 +471,45 @@ func (m searchTUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m searchTUIModel) updateQueryInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "esc":
-		m.quitting = true
-		return m, tea.Quit
+	switch {
+	case key.Matches(msg, searchQueryKeys.Quit):
+		return m.quit()
+
+	case key.Matches(msg, searchQueryKeys.ToggleExact):
+		// Toggle exact-name lookup
+		m.exact = !m.exact
+		return m, nil
+
+	case key.Matches(msg, searchQueryKeys.Older):
+		// Recall an older search
+		if m.recallIndex+1 < len(m.history.Queries) {
+			if m.recallIndex == -1 {
+				m.recallDraft = m.queryInput.Value()
+			}
+			m.recallIndex++
+			m.queryInput.SetValue(m.history.Queries[m.recallIndex])
+			m.queryInput.CursorEnd()
+		}
+		return m, nil
+
+	case key.Matches(msg, searchQueryKeys.Newer):
+		// Back towards the newest search and finally the draft
+		if m.recallIndex >= 0 {
+			m.recallIndex--
+			if m.recallIndex == -1 {
+				m.queryInput.SetValue(m.recallDraft)
+			} else {
+				m.queryInput.SetValue(m.history.Queries[m.recallIndex])
+			}
+			m.queryInput.CursorEnd()
+		}
+		return m, nil
 
-	case "enter":
-		query := strings.TrimSpace(m.queryInput.Value())
-		if query == "" {
+	case key.Matches(msg, searchQueryKeys.Search):
+		if !m.applyQueryInput() {
 			return m, nil
 		}
-		m.query = query
+		m.rememberQuery()
 		m.state = viewLoading
 		return m, m.performSearch
 	}
@@ -312,31 +520,65 @@ func (m searchTUIModel) updateQueryInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m searchTUIModel) updateSearchResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c":
-		m.quitting = true
-		return m, tea.Quit
+	// Let the list handle keys while the user is typing a filter
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
 
-	case "q", "esc":
+	switch {
+	case key.Matches(msg, searchForceQuitKey):
+		return m.quit()
+
+	case key.Matches(msg, searchResultsKeys.Back):
 		// Go back to query input
 		m.state = viewQueryInput
 		m.queryInput.SetValue("")
 		m.queryInput.Focus()
 		return m, textinput.Blink
 
-	case "n":
+	case key.Matches(msg, searchResultsKeys.NewSearch):
 		// New search
 		m.state = viewQueryInput
 		m.queryInput.SetValue("")
 		m.queryInput.Focus()
 		return m, textinput.Blink
 
-	case "enter":
+	case key.Matches(msg, searchResultsKeys.Mark):
+		// Toggle mark on the highlighted package
+		if i, ok := m.list.SelectedItem().(searchResultItem); ok {
+			if m.marked[i.result.Name] {
+				delete(m.marked, i.result.Name)
+			} else {
+				m.marked[i.result.Name] = true
+			}
+		}
+		return m, nil
+
+	case key.Matches(msg, searchResultsKeys.AddMarked):
+		// Add all marked packages to the config
+		selected := m.markedResults()
+		if len(selected) == 0 {
+			return m, nil
+		}
+		names := make([]string, len(selected))
+		for i, r := range selected {
+			names[i] = r.Name
+		}
+		recordSearchPackages(names...)
+		m.state = viewBulkAdding
+		return m, func() tea.Msg {
+			summary, err := addSearchResultsToConfig(FrontendConfig, selected)
+			return bulkAddCompletedMsg{summary: summary, err: err}
+		}
+
+	case key.Matches(msg, searchResultsKeys.Details):
 		// View package details
 		i, ok := m.list.SelectedItem().(searchResultItem)
 		if ok {
-			m.selectedPkg = &i.result
-			m.state = viewPackageDetail
+			m.openDetails(i.result)
+			recordSearchPackages(i.result.Name)
 		}
 		return m, nil
 	}
@@ -347,20 +589,173 @@ func (m searchTUIModel) updateSearchResults(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 }
 
 func (m searchTUIModel) updatePackageDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c":
+	switch {
+	case key.Matches(msg, searchForceQuitKey):
+		return m.quit()
+
+	case key.Matches(msg, searchDetailBackKey):
+		// Go back to search results
+		m.state = viewSearchResults
+		m.selectedPkg = nil
+		return m, nil
+
+	case key.Matches(msg, searchDetailTabKeys.Next):
+		return m, m.showDetailTab((m.detailTab + 1) % detailTabCount)
+
+	case key.Matches(msg, searchDetailTabKeys.Prev):
+		return m, m.showDetailTab((m.detailTab + detailTabCount - 1) % detailTabCount)
+
+	case key.Matches(msg, searchDetailTabKeys.Jump):
+		return m, m.showDetailTab(detailTab(msg.String()[0] - '1'))
+	}
+
+	// The README and Files tabs scroll
+	if m.detailTab != detailTabInfo {
+		var cmd tea.Cmd
+		m.detailView, cmd = m.detailView.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// openDetails shows the details of result, starting on the Info tab
+func (m *searchTUIModel) openDetails(result frontend_mgr.SearchResult) {
+	m.selectedPkg = &result
+	m.state = viewPackageDetail
+	m.detailTab = detailTabInfo
+	if pkg := result.Name + "@" + result.Version; m.preview.pkg != pkg {
+		m.preview = packagePreview{pkg: pkg, fetching: map[detailTab]bool{}}
+	}
+}
+
+// showDetailTab switches the details to tab, fetching the README or file
+// list the first time its tab is shown
+func (m *searchTUIModel) showDetailTab(tab detailTab) tea.Cmd {
+	m.detailTab = tab
+	pkg := m.selectedPkg
+	var cmd tea.Cmd
+	switch {
+	case pkg == nil || m.preview.fetching[tab]:
+	case tab == detailTabReadme && !m.preview.readmeLoaded:
+		cmd = fetchReadmeCmd(pkg.Name, pkg.Version)
+	case tab == detailTabFiles && !m.preview.filesLoaded:
+		cmd = fetchFilesCmd(pkg.Name, pkg.Version, pkg.CDN)
+	}
+	if cmd != nil {
+		m.preview.fetching[tab] = true
+	}
+	m.setDetailContent()
+	m.detailView.GotoTop()
+	return cmd
+}
+
+// setDetailContent sizes the README and Files viewport to the window and
+// fills it for the current tab
+func (m *searchTUIModel) setDetailContent() {
+	width, height := m.width-6, m.height-8
+	if m.width == 0 {
+		width, height = 94, 20
+	}
+	m.detailView.Width, m.detailView.Height = max(width, 20), max(height, 5)
+
+	var content string
+	switch {
+	case m.detailTab == detailTabReadme && m.preview.fetching[detailTabReadme]:
+		content = "Loading README..."
+	case m.detailTab == detailTabReadme && m.preview.readmeErr != nil:
+		content = fmt.Sprintf("No README.md on unpkg: %v", m.preview.readmeErr)
+	case m.detailTab == detailTabReadme:
+		content = wrapText(m.preview.readme, m.detailView.Width)
+	case m.detailTab == detailTabFiles && m.preview.fetching[detailTabFiles]:
+		content = "Loading files..."
+	case m.detailTab == detailTabFiles && m.preview.filesErr != nil:
+		content = fmt.Sprintf("Couldn't list the files: %v", m.preview.filesErr)
+	case m.detailTab == detailTabFiles:
+		content = fileTree(m.preview.files)
+	}
+	m.detailView.SetContent(content)
+}
+
+func (m searchTUIModel) updateBulkAddSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, searchForceQuitKey, searchSummaryKeys.Quit):
 		m.quitting = true
 		return m, tea.Quit
 
-	case "q", "esc", "enter":
+	case key.Matches(msg, searchSummaryKeys.Back):
 		// Go back to search results
 		m.state = viewSearchResults
-		m.selectedPkg = nil
+		m.bulkSummary = nil
 		return m, nil
 	}
 	return m, nil
 }
 
+// rememberQuery adds the query input to the search history, both on disk and
+// for recall later in this session
+func (m *searchTUIModel) rememberQuery() {
+	query := m.queryInput.Value()
+	recordSearchQuery(query)
+	m.history.Queries = rememberRecent(m.history.Queries, query)
+	m.recallIndex = -1
+	m.recallDraft = ""
+}
+
+// typing reports whether keys go to a text field, where '?' is typed
+// rather than opening the help overlay
+func (m searchTUIModel) typing() bool {
+	switch m.state {
+	case viewQueryInput:
+		return true
+	case viewSearchResults:
+		return m.list.FilterState() == list.Filtering
+	}
+	return false
+}
+
+// helpView renders the help overlay for the current view
+func (m searchTUIModel) helpView() string {
+	general := generalHelpSection(searchForceQuitKey)
+
+	switch m.state {
+	case viewSearchResults:
+		keys := searchResultsKeys
+		return renderHelpOverlay("search results", helpSection{
+			title:    "Results",
+			bindings: []key.Binding{keys.Details, keys.Mark, keys.AddMarked, keys.NewSearch, keys.Back},
+		}, listHelpSection(m.list), general)
+	case viewPackageDetail:
+		tabs := searchDetailTabKeys
+		keys := m.detailView.KeyMap
+		return renderHelpOverlay("package details",
+			helpSection{title: "Details", bindings: []key.Binding{tabs.Next, tabs.Prev, tabs.Jump, searchDetailBackKey}},
+			helpSection{title: "README and files", bindings: []key.Binding{keys.Up, keys.Down, keys.PageUp, keys.PageDown, keys.HalfPageUp, keys.HalfPageDown}},
+			general)
+	case viewBulkAddSummary:
+		return renderHelpOverlay("bulk add summary", helpSection{
+			title:    "Summary",
+			bindings: []key.Binding{searchSummaryKeys.Back, searchSummaryKeys.Quit},
+		}, general)
+	}
+
+	keys := searchQueryKeys
+	return renderHelpOverlay("search", helpSection{
+		title:    "Query",
+		bindings: []key.Binding{keys.Search, keys.Older, keys.Newer, keys.ToggleExact, keys.Quit},
+	}, general)
+}
+
+// markedResults returns the marked search results in list order
+func (m searchTUIModel) markedResults() []frontend_mgr.SearchResult {
+	var selected []frontend_mgr.SearchResult
+	for _, r := range m.results {
+		if m.marked[r.Name] {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
 func (m searchTUIModel) View() string {
 	if m.quitting {
 		if m.err != nil {
@@ -368,6 +763,9 @@ func (m searchTUIModel) View() string {
 		}
 		return searchQuitTextStyle.Render("Cancelled.\n")
 	}
+	if m.showHelp {
+		return m.helpView()
+	}
 
 	switch m.state {
 	case viewQueryInput:
@@ -378,6 +776,10 @@ func (m searchTUIModel) View() string {
 		return m.viewPackageDetail()
 	case viewLoading:
 		return m.viewLoading()
+	case viewBulkAdding:
+		return searchQuitTextStyle.Render(fmt.Sprintf("📦 Adding %d package(s) to %s...\n", len(m.marked), FrontendConfig))
+	case viewBulkAddSummary:
+		return m.viewBulkAddSummary()
 	}
 
 	return ""
@@ -391,12 +793,44 @@ func (m searchTUIModel) viewQueryInput() string {
 	b.WriteString("\n\n")
 	b.WriteString(searchItemStyle.Render("  " + m.queryInput.View()))
 	b.WriteString("\n\n")
-	b.WriteString(searchHelpStyle.Render("  Press Enter to search • Esc to cancel"))
+	if m.err != nil {
+		b.WriteString(searchItemStyle.Render(fmt.Sprintf("  Search failed: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(searchItemStyle.Render("  " + m.filterSummary()))
+	b.WriteString("\n\n")
+	if recent := m.recentPackages(); recent != "" {
+		b.WriteString(searchItemStyle.Render("  Recent packages: " + recent))
+		b.WriteString("\n\n")
+	}
+	help := "  Press Enter to search • Ctrl+E to toggle exact match • scope:@name keyword:a,b to filter • F1 for help • Esc to cancel"
+	if len(m.history.Queries) > 0 {
+		help = "  Press Enter to search • ↑/↓ recent searches • Ctrl+E to toggle exact match • scope:@name keyword:a,b to filter • F1 for help • Esc to cancel"
+	}
+	b.WriteString(searchHelpStyle.Render(help))
 	b.WriteString("\n")
 
 	return b.String()
 }
 
+// recentPackagesShown is the number of recent packages listed under the query
+// prompt
+const recentPackagesShown = 5
+
+// recentPackages lists the most recently picked packages for the query
+// prompt, or ""
+func (m searchTUIModel) recentPackages() string {
+	recent := m.history.Packages
+	if len(recent) > recentPackagesShown {
+		recent = recent[:recentPackagesShown]
+	}
+	return strings.Join(recent, ", ")
+}
+
+// searchTableHeaderRows is the number of table header rows viewSearchResults
+// inserts above the results
+const searchTableHeaderRows = 2
+
 func (m searchTUIModel) viewSearchResults() string {
 	var b strings.Builder
 
@@ -406,7 +840,7 @@ func (m searchTUIModel) viewSearchResults() string {
 	cdnHeader := padRight("CDN", m.delegate.maxCDNWidth)
 	descHeader := "DESCRIPTION"
 
-	header := fmt.Sprintf("  %s  %s  %s  %s", nameHeader, versionHeader, cdnHeader, descHeader)
+	header := fmt.Sprintf("      %s  %s  %s  %s", nameHeader, versionHeader, cdnHeader, descHeader)
 
 	b.WriteString("\n")
 	b.WriteString(m.list.View())
@@ -437,6 +871,16 @@ func (m searchTUIModel) viewPackageDetail() string {
 	b.WriteString("\n\n")
 	b.WriteString(detailTitleStyle.Render(fmt.Sprintf("  📦 %s", pkg.Name)))
 	b.WriteString("\n")
+	b.WriteString(m.detailTabBar())
+	b.WriteString("\n")
+
+	if m.detailTab != detailTabInfo {
+		b.WriteString(detailBoxStyle.Render(m.detailView.View()))
+		b.WriteString("\n\n")
+		b.WriteString(searchHelpStyle.Render(fmt.Sprintf("  ↑/↓ PgUp/PgDn to scroll (%3.f%%) • Tab for the next tab • Esc to go back • ? for help", m.detailView.ScrollPercent()*100)))
+		b.WriteString("\n")
+		return b.String()
+	}
 
 	// Build detail box content
 	var details strings.Builder
@@ -467,18 +911,100 @@ func (m searchTUIModel) viewPackageDetail() string {
 
 	b.WriteString(detailBoxStyle.Render(details.String()))
 	b.WriteString("\n\n")
-	b.WriteString(searchHelpStyle.Render("  Press Enter/Esc to go back • Ctrl+C to quit"))
+	b.WriteString(searchHelpStyle.Render("  Press Tab for the README and files • Enter/Esc to go back • ? for help • Ctrl+C to quit"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// detailTabBar renders the names of the detail tabs, highlighting the
+// current one
+func (m searchTUIModel) detailTabBar() string {
+	tabs := make([]string, len(detailTabNames))
+	for i, name := range detailTabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if detailTab(i) == m.detailTab {
+			tabs[i] = detailActiveTabStyle.Render(label)
+		} else {
+			tabs[i] = detailTabStyle.Render(label)
+		}
+	}
+	return "  " + strings.Join(tabs, "│")
+}
+
+func (m searchTUIModel) viewBulkAddSummary() string {
+	if m.bulkSummary == nil {
+		return ""
+	}
+
+	summary := m.bulkSummary
+	var b strings.Builder
+
+	b.WriteString("\n\n")
+	b.WriteString(detailTitleStyle.Render("  📦 Bulk add summary"))
+	b.WriteString("\n")
+
+	var details strings.Builder
+	if len(summary.Added) > 0 {
+		details.WriteString(detailLabelStyle.Render("Added:") + "\n")
+		for _, a := range summary.Added {
+			details.WriteString(detailValueStyle.Render(fmt.Sprintf("  ✓ %s@%s (%s)", a.Name, a.Version, a.CDN)) + "\n")
+		}
+	}
+
+	if len(summary.Skipped) > 0 {
+		details.WriteString("\n")
+		details.WriteString(detailLabelStyle.Render("Skipped:") + "\n")
+		for _, name := range summary.Skipped {
+			details.WriteString(detailValueStyle.Render(fmt.Sprintf("  • %s (already in config)", name)) + "\n")
+		}
+	}
+
+	if len(summary.Failed) > 0 {
+		details.WriteString("\n")
+		details.WriteString(detailLabelStyle.Render("Failed:") + "\n")
+		for _, name := range sortedKeys(summary.Failed) {
+			details.WriteString(detailValueStyle.Render(fmt.Sprintf("  ✗ %s: %v", name, summary.Failed[name])) + "\n")
+		}
+	}
+
+	details.WriteString("\n")
+	if len(summary.Added) > 0 {
+		details.WriteString(detailValueStyle.Render(fmt.Sprintf("Config updated: %s", FrontendConfig)) + "\n")
+		details.WriteString(detailValueStyle.Render("Next: smfaman sync") + "\n")
+	} else {
+		details.WriteString(detailValueStyle.Render("No changes were written to the config.") + "\n")
+	}
+
+	b.WriteString(detailBoxStyle.Render(details.String()))
+	b.WriteString("\n\n")
+	b.WriteString(searchHelpStyle.Render("  Press Enter/Esc to go back • ? for help • q to quit"))
 	b.WriteString("\n")
 
 	return b.String()
 }
 
+// filterSummary describes the active search mode and command-line filters
+func (m searchTUIModel) filterSummary() string {
+	parts := []string{"Mode: fuzzy search"}
+	if m.exact {
+		parts[0] = "Mode: exact name"
+	}
+	if scope := frontend_mgr.NormalizeScope(searchScope); scope != "" {
+		parts = append(parts, "scope "+scope)
+	}
+	if len(searchKeywords) > 0 {
+		parts = append(parts, "keywords "+strings.Join(searchKeywords, ", "))
+	}
+	return strings.Join(parts, " • ")
+}
+
 func (m searchTUIModel) viewLoading() string {
 	return searchQuitTextStyle.Render(fmt.Sprintf("🔍 Searching for '%s'...\n", m.query))
 }
 
 func (m searchTUIModel) performSearch() tea.Msg {
-	results, err := performSearch(m.query, searchCDN, searchLimit)
+	results, err := performSearch(m.query, searchCDN, searchLimit, m.filter, m.exact)
 	return searchCompletedMsg{
 		results: results,
 		err:     err,
@@ -488,13 +1014,44 @@ func (m searchTUIModel) performSearch() tea.Msg {
 // runSearchTUI starts the interactive search interface
 func runSearchTUI(initialQuery string) {
 	m := newSearchTUIModel(initialQuery)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	if m.query != "" || m.filter.Scope != "" {
+		m.rememberQuery()
+	}
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running interactive mode: %v\n", err)
 	}
 }
 
+// pickerSearch runs the package picker's searches
+var pickerSearch = performSearch
+
+// packagePickerOptions configures the package picker used by the pkgmgr add
+// form and add --interactive, starting with initialQuery when it isn't
+// empty. It searches like the search command and shares its history.
+func packagePickerOptions(initialQuery string) tui.PickerOptions {
+	history := loadSearchHistory()
+	recent := history.Packages
+	if len(recent) > recentPackagesShown {
+		recent = recent[:recentPackagesShown]
+	}
+
+	return tui.PickerOptions{
+		InitialQuery: initialQuery,
+		History:      history.Queries,
+		Recent:       recent,
+		Search: func(input string, exact bool) ([]frontend_mgr.SearchResult, error) {
+			query, filter := parseSearchQuery(input)
+			return pickerSearch(query, searchCDN, searchLimit, filter, exact)
+		},
+		OnSearch: recordSearchQuery,
+		OnPick: func(result frontend_mgr.SearchResult) {
+			recordSearchPackages(result.Name)
+		},
+	}
+}
+
 // Helper functions
 func padRight(s string, width int) string {
 	if len(s) >= width {
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/cache"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// Tool-level setting keys. Each can be set in the settings file or as an
+// SMFAMAN_<KEY> environment variable (e.g. SMFAMAN_CACHE_TTL); global flags
+// take precedence over both.
+const (
+	settingCDN            = "cdn"
+	settingCacheTTL       = "cache_ttl"
+	settingConcurrency    = "concurrency"
+	settingColor          = "color"
+	settingFrontendConfig = "frontend_config"
+	settingMaxResponseMB  = "max_response_mb"
+	settingTimeout        = "timeout"
+)
+
+// Color modes for the color setting
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// cancelTimeout releases the timeout deadline once the command is done
+var cancelTimeout context.CancelFunc = func() {}
+
+// defaultConcurrency is the number of parallel CDN requests when the
+// concurrency setting is unset
+const defaultConcurrency = 4
+
+// settingsCDN returns the CDN to use when neither a flag nor the project
+// config names one: the cdn setting, or unpkg
+func settingsCDN() frontend_config.CDN {
+	cdn := frontend_config.CDN(viper.GetString(settingCDN))
+	if frontend_config.IsValidCDN(cdn) {
+		return cdn
+	}
+	return frontend_config.CDNUnpkg
+}
+
+// libraryCDN returns the CDN a library is fetched from: its own cdn, then
+// the config's, then the cdn setting
+func libraryCDN(config *frontend_config.FrontendConfig, libConfig frontend_config.LibraryConfig) frontend_config.CDN {
+	if cdn := config.GetLibraryCDN(libConfig); cdn != "" {
+		return cdn
+	}
+	return settingsCDN()
+}
+
+// settingsConcurrency returns the maximum number of parallel CDN requests
+func settingsConcurrency() int {
+	if n := viper.GetInt(settingConcurrency); n > 0 {
+		return n
+	}
+	return defaultConcurrency
+}
+
+// projectConcurrency returns the maximum number of parallel CDN requests
+// for a project: its concurrency field, or the concurrency setting
+func projectConcurrency(config *frontend_config.FrontendConfig) int {
+	if config.Concurrency > 0 {
+		return config.Concurrency
+	}
+	return settingsConcurrency()
+}
+
+// settingsMaxResponseSize returns the largest CDN or registry API response
+// to accept, in bytes
+func settingsMaxResponseSize() int64 {
+	if mb := viper.GetInt64(settingMaxResponseMB); mb > 0 {
+		return mb << 20
+	}
+	return frontend_mgr.DefaultMaxResponseSize
+}
+
+// settingsTimeout returns how long a command may spend on CDN and registry
+// requests in total, or 0 for no limit
+func settingsTimeout() time.Duration {
+	timeout, err := time.ParseDuration(viper.GetString(settingTimeout))
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// validDuration reports whether value is a duration of zero or more
+func validDuration(value string) bool {
+	d, err := time.ParseDuration(value)
+	return err == nil && d >= 0
+}
+
+// settingsColorMode returns the color setting: auto, always or never
+func settingsColorMode() string {
+	switch mode := viper.GetString(settingColor); mode {
+	case colorAlways, colorNever:
+		return mode
+	default:
+		return colorAuto
+	}
+}
+
+// settingsCacheTTL returns the metadata cache TTL, or 0 to keep the default
+func settingsCacheTTL() time.Duration {
+	if !viper.IsSet(settingCacheTTL) {
+		return 0
+	}
+	ttl, err := time.ParseDuration(viper.GetString(settingCacheTTL))
+	if err != nil || ttl <= 0 {
+		return 0
+	}
+	return ttl
+}
+
+// validateSettings returns a warning for each setting with an unusable
+// value; those settings fall back to their defaults
+func validateSettings() []string {
+	var warnings []string
+
+	if cdn := viper.GetString(settingCDN); cdn != "" && !frontend_config.IsValidCDN(frontend_config.CDN(cdn)) {
+		warnings = append(warnings, fmt.Sprintf("invalid cdn setting %q, using %s", cdn, frontend_config.CDNUnpkg))
+	}
+	if viper.IsSet(settingCacheTTL) && settingsCacheTTL() == 0 {
+		warnings = append(warnings, fmt.Sprintf("invalid cache_ttl setting %q (want a duration like 12h), using %v",
+			viper.GetString(settingCacheTTL), cache.DefaultTTL))
+	}
+	if viper.IsSet(settingConcurrency) && viper.GetInt(settingConcurrency) <= 0 {
+		warnings = append(warnings, fmt.Sprintf("invalid concurrency setting %q, using %d",
+			viper.GetString(settingConcurrency), defaultConcurrency))
+	}
+	if viper.IsSet(settingMaxResponseMB) && viper.GetInt64(settingMaxResponseMB) <= 0 {
+		warnings = append(warnings, fmt.Sprintf("invalid max_response_mb setting %q, using %d",
+			viper.GetString(settingMaxResponseMB), frontend_mgr.DefaultMaxResponseSize>>20))
+	}
+	if value := viper.GetString(settingTimeout); value != "" && value != "0" && !validDuration(value) {
+		warnings = append(warnings, fmt.Sprintf("invalid timeout setting %q (want a duration like 2m), using no timeout", value))
+	}
+	if mode := viper.GetString(settingColor); mode != "" && mode != settingsColorMode() {
+		warnings = append(warnings, fmt.Sprintf("invalid color setting %q (want auto, always or never), using auto", mode))
+	}
+	if webhook := viper.GetString(settingNotifyWebhook); webhook != "" && !validNotifyWebhook(webhook) {
+		warnings = append(warnings, fmt.Sprintf("invalid %s setting (want an http or https URL), not sending webhooks", settingNotifyWebhook))
+	}
+	if warning := invalidThemeWarning(); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	return warnings
+}
+
+// initSettings applies the tool settings to global state after the settings
+// file has been read
+func initSettings() {
+	for _, warning := range validateSettings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
+	FrontendConfig = viper.GetString(settingFrontendConfig)
+	libraryInfoSlots = make(chan struct{}, settingsConcurrency())
+	frontend_mgr.MaxResponseSize = settingsMaxResponseSize()
+	if timeout := settingsTimeout(); timeout > 0 {
+		frontend_mgr.RequestContext, cancelTimeout = context.WithTimeout(context.Background(), timeout)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// useSettingsEnv reads settings from SMFAMAN_* variables, as initConfig does
+func useSettingsEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	viper.SetEnvPrefix("smfaman")
+	viper.AutomaticEnv()
+	for _, key := range []string{"CDN", "CACHE_TTL", "CONCURRENCY", "MAX_RESPONSE_MB", "COLOR", "THEME", "TIMEOUT"} {
+		t.Setenv("SMFAMAN_"+key, env[key])
+	}
+}
+
+func TestSettingsDefaults(t *testing.T) {
+	useSettingsEnv(t, nil)
+
+	if got := settingsCDN(); got != frontend_config.CDNUnpkg {
+		t.Errorf("settingsCDN() = %q, want unpkg", got)
+	}
+	if got := settingsConcurrency(); got != defaultConcurrency {
+		t.Errorf("settingsConcurrency() = %d, want %d", got, defaultConcurrency)
+	}
+	if got := settingsColorMode(); got != colorAuto {
+		t.Errorf("settingsColorMode() = %q, want auto", got)
+	}
+	if got := settingsCacheTTL(); got != 0 {
+		t.Errorf("settingsCacheTTL() = %v, want 0", got)
+	}
+	if got := settingsTimeout(); got != 0 {
+		t.Errorf("settingsTimeout() = %v, want 0", got)
+	}
+	if warnings := validateSettings(); len(warnings) != 0 {
+		t.Errorf("validateSettings() = %v, want none", warnings)
+	}
+}
+
+func TestSettingsFromEnvironment(t *testing.T) {
+	useSettingsEnv(t, map[string]string{
+		"CDN":         "jsdelivr",
+		"CACHE_TTL":   "2h",
+		"CONCURRENCY": "8",
+		"COLOR":       "never",
+		"TIMEOUT":     "2m",
+	})
+
+	if got := settingsCDN(); got != frontend_config.CDNJsdelivr {
+		t.Errorf("settingsCDN() = %q, want jsdelivr", got)
+	}
+	if got := settingsCacheTTL(); got != 2*time.Hour {
+		t.Errorf("settingsCacheTTL() = %v, want 2h", got)
+	}
+	if got := settingsTimeout(); got != 2*time.Minute {
+		t.Errorf("settingsTimeout() = %v, want 2m", got)
+	}
+	if got := settingsConcurrency(); got != 8 {
+		t.Errorf("settingsConcurrency() = %d, want 8", got)
+	}
+	if colorEnabled() {
+		t.Error("colorEnabled() = true with color: never")
+	}
+
+	config := &frontend_config.FrontendConfig{}
+	if got := determineCDNForAdd(config); got != frontend_config.CDNJsdelivr {
+		t.Errorf("determineCDNForAdd() = %q, want the cdn setting", got)
+	}
+	config.CDN = frontend_config.CDNCdnjs
+	if got := determineCDNForAdd(config); got != frontend_config.CDNCdnjs {
+		t.Errorf("determineCDNForAdd() = %q, want the project CDN over the setting", got)
+	}
+}
+
+func TestValidateSettings(t *testing.T) {
+	useSettingsEnv(t, map[string]string{
+		"CDN":             "bogus",
+		"CACHE_TTL":       "soon",
+		"CONCURRENCY":     "0",
+		"MAX_RESPONSE_MB": "-1",
+		"COLOR":           "rainbow",
+		"TIMEOUT":         "forever",
+	})
+
+	warnings := validateSettings()
+	if len(warnings) != 6 {
+		t.Fatalf("validateSettings() = %v, want 6 warnings", warnings)
+	}
+	for i, key := range []string{"cdn", "cache_ttl", "concurrency", "max_response_mb", "timeout", "color"} {
+		if !strings.Contains(warnings[i], key) {
+			t.Errorf("warning %d = %q, want it to mention %s", i, warnings[i], key)
+		}
+	}
+
+	if got := settingsCDN(); got != frontend_config.CDNUnpkg {
+		t.Errorf("settingsCDN() = %q, want unpkg fallback", got)
+	}
+	if got := settingsConcurrency(); got != defaultConcurrency {
+		t.Errorf("settingsConcurrency() = %d, want %d", got, defaultConcurrency)
+	}
+	if got := settingsMaxResponseSize(); got != frontend_mgr.DefaultMaxResponseSize {
+		t.Errorf("settingsMaxResponseSize() = %d, want %d", got, frontend_mgr.DefaultMaxResponseSize)
+	}
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var slimApply bool
+
+// slimCmd represents the slim command
+var slimCmd = &cobra.Command{
+	Use:   "slim <library>",
+	Short: "Suggest a minimal file list for a library",
+	Long: `Analyze a library's package.json and file listing and propose a minimal
+files list for the configuration, showing how much download size it saves.
+
+Entry files are taken from the package's unpkg, jsdelivr, browser, module,
+main, exports and style fields, preferring minified builds when the package
+ships them. When the manifest names no usable files, top-level minified files
+in the dist folder are suggested instead.
+
+Use --apply to write the suggested list into the library's 'files' setting.
+
+Examples:
+  smfaman slim jquery
+  smfaman slim bootstrap --apply`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runSlim(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(slimCmd)
+	slimCmd.Flags().BoolVar(&slimApply, "apply", false, "Write the suggested files into the config")
+}
+
+// runSlim executes the slim command
+func runSlim(libName string) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+
+	libConfig, exists := config.Libraries[libName]
+	if !exists {
+		return notFoundError(fmt.Errorf("library '%s' not found in config", libName))
+	}
+
+	cdn := libraryCDN(config, libConfig)
+
+	files, err := fetchFileList(libName, libConfig.Version, cdn)
+	if err != nil {
+		return fmt.Errorf("failed to fetch files for %s: %w", libName, err)
+	}
+
+	manifest, err := frontend_mgr.FetchPackageManifest(libName, libConfig.Version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read package.json, using dist heuristics: %v\n", err)
+		manifest = &frontend_mgr.PackageManifest{}
+	}
+
+	suggested := suggestSlimFiles(manifest, files)
+	if len(suggested) == 0 {
+		return fmt.Errorf("could not determine entry files for %s", libName)
+	}
+
+	printSlimSuggestion(libName, libConfig, files, suggested)
+
+	if !slimApply {
+		fmt.Printf("\nRun 'smfaman slim %s --apply' to write this list into the config.\n", libName)
+		return nil
+	}
+
+	libConfig.Files = suggested
+	config.Libraries[libName] = libConfig
+	if err := saveConfig(FrontendConfig, config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("\n✓ Config updated: %s\n", FrontendConfig)
+	return nil
+}
+
+// printSlimSuggestion prints the suggested files and the size saved
+func printSlimSuggestion(libName string, libConfig frontend_config.LibraryConfig, files []CDNFile, suggested []string) {
+	sizes := make(map[string]int64, len(files))
+	var totalBytes int64
+	for _, file := range files {
+		sizes[file.Path] = file.Size
+		totalBytes += file.Size
+	}
+
+	maxPath := 0
+	for _, file := range suggested {
+		maxPath = max(maxPath, len(file))
+	}
+
+	fmt.Printf("\nSuggested files for %s@%s:\n\n", libName, libConfig.Version)
+	var selectedBytes int64
+	for _, file := range suggested {
+		selectedBytes += sizes[file]
+		fmt.Printf("  %s  %s\n", padRight(file, maxPath), formatBytes(sizes[file]))
+	}
+
+	fmt.Printf("\nSelected: %d %s (%s) of %d %s (%s)\n",
+		len(suggested), pluralize(len(suggested), "file", "files"), formatBytes(selectedBytes),
+		len(files), pluralize(len(files), "file", "files"), formatBytes(totalBytes))
+	if totalBytes > 0 {
+		saved := totalBytes - selectedBytes
+		fmt.Printf("Saves:    %s (%.0f%%)\n", formatBytes(saved), float64(saved)*100/float64(totalBytes))
+	}
+
+	if len(libConfig.Files) > 0 {
+		fmt.Printf("\nCurrent files: %s\n", strings.Join(libConfig.Files, ", "))
+	}
+}
+
+// suggestSlimFiles proposes a minimal file list from the package manifest,
+// falling back to minified files in the dist folder
+func suggestSlimFiles(manifest *frontend_mgr.PackageManifest, files []CDNFile) []string {
+	available := make(map[string]bool, len(files))
+	for _, file := range files {
+		available[file.Path] = true
+	}
+
+	var suggested []string
+
+	// Script entry, in order of how browser-ready each field usually is
+	candidates := []string{manifest.Unpkg, manifest.Jsdelivr, browserEntry(manifest.Browser), manifest.Module, manifest.Main}
+	candidates = append(candidates, exportEntries(manifest.Exports)...)
+	for _, candidate := range candidates {
+		if file := resolveEntryFile(candidate, available); file != "" {
+			suggested = append(suggested, file)
+			break
+		}
+	}
+
+	// Stylesheet entry
+	if file := resolveEntryFile(manifest.Style, available); file != "" {
+		suggested = append(suggested, file)
+	}
+
+	if len(suggested) == 0 {
+		suggested = distMinifiedFiles(files)
+	}
+
+	sort.Strings(suggested)
+	return slices.Compact(suggested)
+}
+
+// resolveEntryFile maps a manifest path onto the package's files, adding a
+// missing .js extension and preferring a minified sibling when one exists
+func resolveEntryFile(entry string, available map[string]bool) string {
+	entry = strings.TrimPrefix(strings.TrimPrefix(entry, "./"), "/")
+	if entry == "" {
+		return ""
+	}
+
+	if !available[entry] && available[entry+".js"] {
+		entry += ".js"
+	}
+	if !available[entry] {
+		return ""
+	}
+
+	ext := path.Ext(entry)
+	base := strings.TrimSuffix(entry, ext)
+	if !strings.HasSuffix(base, ".min") && available[base+".min"+ext] {
+		return base + ".min" + ext
+	}
+	return entry
+}
+
+// browserEntry returns the browser field when it names a single file
+func browserEntry(raw json.RawMessage) string {
+	var entry string
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return ""
+	}
+	return entry
+}
+
+// exportEntries returns the files named by the root export, preferring
+// browser and ES module conditions and skipping Node-only ones
+func exportEntries(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+
+	// Subpath exports: only the package root is an entry point
+	if conditions, ok := value.(map[string]any); ok {
+		if root, ok := conditions["."]; ok {
+			value = root
+		}
+	}
+
+	return collectExportPaths(value)
+}
+
+// collectExportPaths walks an exports value in condition preference order
+func collectExportPaths(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var paths []string
+		for _, item := range v {
+			paths = append(paths, collectExportPaths(item)...)
+		}
+		return paths
+	case map[string]any:
+		preferred := []string{"browser", "import", "module", "default"}
+		skipped := []string{"node", "require", "types", "deno"}
+
+		var paths []string
+		for _, condition := range preferred {
+			if item, ok := v[condition]; ok {
+				paths = append(paths, collectExportPaths(item)...)
+			}
+		}
+		for _, condition := range sortedKeys(v) {
+			if slices.Contains(preferred, condition) || slices.Contains(skipped, condition) || strings.HasPrefix(condition, ".") {
+				continue
+			}
+			paths = append(paths, collectExportPaths(v[condition])...)
+		}
+		return paths
+	default:
+		return nil
+	}
+}
+
+// distMinifiedFiles returns the minified JS and CSS files at the top of the
+// dist folder, or at the package root when there is none
+func distMinifiedFiles(files []CDNFile) []string {
+	var dist, root []string
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, ".min.js") && !strings.HasSuffix(file.Path, ".min.css") {
+			continue
+		}
+		switch path.Dir(file.Path) {
+		case "dist":
+			dist = append(dist, file.Path)
+		case ".":
+			root = append(root, file.Path)
+		}
+	}
+
+	if len(dist) > 0 {
+		return dist
+	}
+	return root
+}
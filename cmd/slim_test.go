@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestSuggestSlimFiles(t *testing.T) {
+	files := []CDNFile{
+		{Path: "package.json"},
+		{Path: "dist/lib.js"},
+		{Path: "dist/lib.min.js"},
+		{Path: "dist/lib.esm.js"},
+		{Path: "dist/lib.cjs"},
+		{Path: "dist/lib.min.css"},
+		{Path: "src/index.js"},
+	}
+
+	tests := []struct {
+		name     string
+		manifest frontend_mgr.PackageManifest
+		expected []string
+	}{
+		{
+			name:     "cdn field preferred and minified",
+			manifest: frontend_mgr.PackageManifest{Unpkg: "dist/lib.js", Main: "dist/lib.cjs"},
+			expected: []string{"dist/lib.min.js"},
+		},
+		{
+			name:     "main without extension",
+			manifest: frontend_mgr.PackageManifest{Main: "./src/index"},
+			expected: []string{"src/index.js"},
+		},
+		{
+			name:     "browser string and style",
+			manifest: frontend_mgr.PackageManifest{Browser: json.RawMessage(`"dist/lib.js"`), Style: "dist/lib.min.css"},
+			expected: []string{"dist/lib.min.css", "dist/lib.min.js"},
+		},
+		{
+			name:     "browser map is ignored",
+			manifest: frontend_mgr.PackageManifest{Browser: json.RawMessage(`{"fs": false}`), Module: "dist/lib.esm.js"},
+			expected: []string{"dist/lib.esm.js"},
+		},
+		{
+			name: "exports prefers import over require",
+			manifest: frontend_mgr.PackageManifest{Exports: json.RawMessage(
+				`{".": {"require": "./dist/lib.cjs", "import": "./dist/lib.esm.js"}, "./package.json": "./package.json"}`)},
+			expected: []string{"dist/lib.esm.js"},
+		},
+		{
+			name:     "dist heuristics",
+			manifest: frontend_mgr.PackageManifest{Main: "missing.js"},
+			expected: []string{"dist/lib.min.css", "dist/lib.min.js"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := suggestSlimFiles(&tt.manifest, files)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDistMinifiedFilesFallsBackToRoot(t *testing.T) {
+	files := []CDNFile{
+		{Path: "lib.min.js"},
+		{Path: "lib.js"},
+		{Path: "locales/de.min.js"},
+	}
+
+	got := distMinifiedFiles(files)
+	if !reflect.DeepEqual(got, []string{"lib.min.js"}) {
+		t.Errorf("expected [lib.min.js], got %v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+// sourceMappingURLPattern matches a sourceMappingURL comment line in JS or CSS
+var sourceMappingURLPattern = regexp.MustCompile(`(?m)^[ \t]*(?://[#@][ \t]*sourceMappingURL=[^\r\n]*|/\*[#@][ \t]*sourceMappingURL=.*?\*/)[ \t]*(?:\r?\n)?`)
+
+// isSourceMap reports whether a file path is a sourcemap
+func isSourceMap(filePath string) bool {
+	return strings.HasSuffix(filePath, ".map")
+}
+
+// hasSourceMapComment reports whether a file type may carry a sourceMappingURL comment
+func hasSourceMapComment(filePath string) bool {
+	switch path.Ext(filePath) {
+	case ".js", ".mjs", ".cjs", ".css":
+		return true
+	default:
+		return false
+	}
+}
+
+// sourceMapCandidates returns the likely sourcemap paths for a JS/CSS file
+// (e.g., "dist/app.min.js.map" and "dist/app.min.map")
+func sourceMapCandidates(filePath string) []string {
+	return []string{
+		filePath + ".map",
+		strings.TrimSuffix(filePath, path.Ext(filePath)) + ".map",
+	}
+}
+
+// applySourceMaps adds or removes .map files from the selected files
+// according to the sourcemap setting
+func applySourceMaps(selected, available []CDNFile, mode frontend_config.SourceMaps) []CDNFile {
+	switch mode {
+	case frontend_config.SourceMapsExclude:
+		var result []CDNFile
+		for _, file := range selected {
+			if !isSourceMap(file.Path) {
+				result = append(result, file)
+			}
+		}
+		return result
+
+	case frontend_config.SourceMapsInclude:
+		byPath := make(map[string]CDNFile, len(available))
+		for _, file := range available {
+			byPath[file.Path] = file
+		}
+
+		seen := make(map[string]bool, len(selected))
+		for _, file := range selected {
+			seen[file.Path] = true
+		}
+
+		result := append([]CDNFile(nil), selected...)
+		for _, file := range selected {
+			if !hasSourceMapComment(file.Path) {
+				continue
+			}
+			for _, candidate := range sourceMapCandidates(file.Path) {
+				if mapFile, ok := byPath[candidate]; ok && !seen[candidate] {
+					seen[candidate] = true
+					result = append(result, mapFile)
+					break
+				}
+			}
+		}
+		return result
+
+	default:
+		return selected
+	}
+}
+
+// stripSourceMappingURL removes sourceMappingURL comments so browsers do not
+// request sourcemaps that were not downloaded
+func stripSourceMappingURL(data []byte) []byte {
+	return sourceMappingURLPattern.ReplaceAll(data, nil)
+}
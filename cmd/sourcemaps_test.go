@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestApplySourceMaps(t *testing.T) {
+	available := []CDNFile{
+		{Path: "dist/jquery.js"},
+		{Path: "dist/jquery.min.js"},
+		{Path: "dist/jquery.min.map"},
+		{Path: "dist/app.css"},
+		{Path: "dist/app.css.map"},
+		{Path: "README.md"},
+	}
+
+	tests := []struct {
+		name     string
+		selected []string
+		mode     frontend_config.SourceMaps
+		expected []string
+	}{
+		{
+			name:     "default keeps selection",
+			selected: []string{"dist/jquery.min.js", "dist/jquery.min.map"},
+			mode:     "",
+			expected: []string{"dist/jquery.min.js", "dist/jquery.min.map"},
+		},
+		{
+			name:     "exclude drops maps",
+			selected: []string{"dist/jquery.min.js", "dist/jquery.min.map", "dist/app.css.map"},
+			mode:     frontend_config.SourceMapsExclude,
+			expected: []string{"dist/jquery.min.js"},
+		},
+		{
+			name:     "include adds matching maps",
+			selected: []string{"dist/jquery.min.js", "dist/app.css", "README.md"},
+			mode:     frontend_config.SourceMapsInclude,
+			expected: []string{"dist/jquery.min.js", "dist/app.css", "README.md", "dist/jquery.min.map", "dist/app.css.map"},
+		},
+		{
+			name:     "include does not duplicate selected maps",
+			selected: []string{"dist/app.css", "dist/app.css.map"},
+			mode:     frontend_config.SourceMapsInclude,
+			expected: []string{"dist/app.css", "dist/app.css.map"},
+		},
+		{
+			name:     "include skips files without maps",
+			selected: []string{"dist/jquery.js"},
+			mode:     frontend_config.SourceMapsInclude,
+			expected: []string{"dist/jquery.js"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selected []CDNFile
+			for _, p := range tt.selected {
+				selected = append(selected, CDNFile{Path: p})
+			}
+
+			result := applySourceMaps(selected, available, tt.mode)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %d files, got %d: %v", len(tt.expected), len(result), result)
+			}
+			for i, p := range tt.expected {
+				if result[i].Path != p {
+					t.Errorf("file %d: expected %q, got %q", i, p, result[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestStripSourceMappingURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "js comment",
+			input:    "var a=1;\n//# sourceMappingURL=jquery.min.map\n",
+			expected: "var a=1;\n",
+		},
+		{
+			name:     "legacy js comment without trailing newline",
+			input:    "var a=1;\n//@ sourceMappingURL=jquery.min.map",
+			expected: "var a=1;\n",
+		},
+		{
+			name:     "css comment",
+			input:    ".a{color:red}\n/*# sourceMappingURL=app.css.map */\n",
+			expected: ".a{color:red}\n",
+		},
+		{
+			name:     "no comment",
+			input:    "var a=1;\n",
+			expected: "var a=1;\n",
+		},
+		{
+			name:     "inline mention is kept",
+			input:    "var s=\"//# sourceMappingURL=x\";\n",
+			expected: "var s=\"//# sourceMappingURL=x\";\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := string(stripSourceMappingURL([]byte(tt.input)))
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// settingStarterKits is the settings key holding the user's starter-kit
+// catalog, a list of kits in the same shape as the built-in ones
+const settingStarterKits = "starter_kits"
+
+// starterKit describes a project starter kit that bootstrap can download
+type starterKit struct {
+	Name        string `mapstructure:"name"`
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+	URL         string `mapstructure:"url"`
+	// SHA256 is the expected checksum of the archive. Kits without one are
+	// extracted unverified, with a warning, unless --sha256 is given.
+	SHA256 string `mapstructure:"sha256"`
+	// Subdir extracts only this folder of the archive (e.g. the top-level
+	// folder GitHub adds to source archives)
+	Subdir       string   `mapstructure:"subdir"`
+	Start        string   `mapstructure:"start"`
+	StartWindows string   `mapstructure:"start_windows"`
+	Links        []string `mapstructure:"links"`
+
+	builtin bool
+}
+
+// builtinStarterKits are the kits smfaman knows about without any catalog
+var builtinStarterKits = []starterKit{
+	{
+		Name:         "xmlui",
+		Title:        "XMLUI",
+		Description:  "XMLUI invoice app with the XMLUI engine and a test server",
+		URL:          xmluiStarterKitURL,
+		Start:        "./start.sh",
+		StartWindows: "start.bat",
+		Links: []string{
+			"📚 Documentation: https://docs.xmlui.org",
+			"🎨 Demo & Gallery: https://demo.xmlui.org",
+		},
+	},
+	{
+		Name:         "htmx",
+		Title:        "HTMX",
+		Description:  "HTMX app with sample templates and a Go development server",
+		URL:          htmxStarterKitURL,
+		Start:        "./start.sh",
+		StartWindows: "start.bat",
+		Links: []string{
+			"📚 Documentation: https://htmx.org/docs",
+			"🎨 Examples: https://htmx.org/examples",
+		},
+	},
+	{
+		Name:        "bootstrap-site",
+		Title:       "Bootstrap",
+		Description: "Official Bootstrap examples (starter, Sass, Vite, webpack and more)",
+		URL:         "https://github.com/twbs/examples/archive/refs/heads/main.zip",
+		Subdir:      "examples-main",
+		Links: []string{
+			"📚 Documentation: https://getbootstrap.com/docs/",
+		},
+	},
+}
+
+// starterKits returns the built-in kits merged with the user's catalog,
+// sorted by name. Catalog entries replace built-in kits with the same name.
+func starterKits() ([]starterKit, error) {
+	kits := make(map[string]starterKit, len(builtinStarterKits))
+	for _, kit := range builtinStarterKits {
+		kit.builtin = true
+		kits[kit.Name] = kit
+	}
+
+	var catalog []starterKit
+	if err := viper.UnmarshalKey(settingStarterKits, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to read %s setting: %w", settingStarterKits, err)
+	}
+	for i, kit := range catalog {
+		if kit.Name == "" || kit.URL == "" {
+			return nil, fmt.Errorf("%s entry %d needs a name and a url", settingStarterKits, i+1)
+		}
+		kits[kit.Name] = kit
+	}
+
+	result := make([]starterKit, 0, len(kits))
+	for _, name := range sortedKeys(kits) {
+		result = append(result, kits[name])
+	}
+	return result, nil
+}
+
+// findStarterKit looks up a kit by name
+func findStarterKit(name string) (starterKit, error) {
+	kits, err := starterKits()
+	if err != nil {
+		return starterKit{}, err
+	}
+
+	names := make([]string, 0, len(kits))
+	for _, kit := range kits {
+		if kit.Name == name {
+			return kit, nil
+		}
+		names = append(names, kit.Name)
+	}
+	return starterKit{}, notFoundError(fmt.Errorf("unknown starter kit '%s' (available: %s)", name, strings.Join(names, ", ")))
+}
+
+// displayTitle returns the kit's title, or its name when it has none
+func (k starterKit) displayTitle() string {
+	if k.Title != "" {
+		return k.Title
+	}
+	return k.Name
+}
+
+// starterKitDownloadPath returns the temp file a kit is downloaded to
+func starterKitDownloadPath(kit starterKit) string {
+	sum := sha256.Sum256([]byte(kit.URL))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("smfaman-%s-%s.zip", kit.Name, hex.EncodeToString(sum[:6])))
+}
+
+// runStarterKit downloads a starter kit, verifies its checksum and extracts
+// it into dir. expectedSHA overrides the kit's pinned checksum when set; a
+// kit with neither is extracted after a warning showing the download's
+// checksum.
+func runStarterKit(kit starterKit, dir, expectedSHA string) error {
+	if expectedSHA == "" {
+		expectedSHA = kit.SHA256
+	}
+
+	fmt.Printf("🚀 Bootstrapping %s project...\n", kit.displayTitle())
+	fmt.Printf("📦 Downloading %s starter kit from: %s\n", kit.displayTitle(), kit.URL)
+
+	// Create target directory if it doesn't exist
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Download the starter kit. The temp path is stable per URL so an
+	// interrupted download resumes on the next run.
+	zipPath := starterKitDownloadPath(kit)
+	if err := downloadZipFile(kit.URL, zipPath); err != nil {
+		return fmt.Errorf("failed to download starter kit (run the command again to resume): %w", err)
+	}
+	defer os.Remove(zipPath) // Clean up temp file
+
+	sum, _, err := hashFile(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to checksum starter kit: %w", err)
+	}
+	switch {
+	case expectedSHA == "":
+		fmt.Printf("⚠ No checksum is pinned for the %s starter kit, so the download isn't verified.\n", kit.Name)
+		fmt.Printf("  Its sha256 is %s; pass it with --sha256 to check future downloads.\n", sum)
+	case !strings.EqualFold(sum, expectedSHA):
+		return verificationError(fmt.Errorf("checksum mismatch for %s starter kit: expected %s, got %s", kit.Name, expectedSHA, sum))
+	default:
+		fmt.Printf("✓ Checksum verified (sha256 %s)\n", sum)
+	}
+
+	fmt.Println("📂 Extracting files...")
+
+	// Extract the zip file
+	if err := extractZipSubdir(zipPath, kit.Subdir, dir); err != nil {
+		return fmt.Errorf("failed to extract starter kit: %w", err)
+	}
+
+	fmt.Printf("\n✅ %s project bootstrapped successfully!\n", kit.displayTitle())
+	fmt.Printf("\n📁 Project location: %s\n", dir)
+	fmt.Println("\n🎯 Next steps:")
+	fmt.Println("   1. Navigate to the project directory:")
+
+	if dir != "." {
+		fmt.Printf("      cd %s\n", dir)
+	}
+
+	start := kit.Start
+	if runtime.GOOS == "windows" && kit.StartWindows != "" {
+		start = kit.StartWindows
+	}
+	if start != "" {
+		fmt.Println("\n   2. Start the development server:")
+		fmt.Printf("      %s\n", start)
+		fmt.Println("\n   3. Open your browser to the URL shown by the server")
+	} else {
+		fmt.Println("\n   2. Follow the README in the project for setup instructions")
+	}
+
+	if len(kit.Links) > 0 {
+		fmt.Println()
+		for _, link := range kit.Links {
+			fmt.Println(link)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// buildTestZip returns a zip archive holding the given files
+func buildTestZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kit.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	w := zip.NewWriter(f)
+	for _, name := range sortedKeys(files) {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		fw.Write([]byte(files[name]))
+	}
+	w.Close()
+	f.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read zip: %v", err)
+	}
+	return data
+}
+
+func TestStarterKitsCatalog(t *testing.T) {
+	defer viper.Set(settingStarterKits, nil)
+
+	viper.Set(settingStarterKits, []map[string]any{
+		{"name": "alpine", "description": "Alpine starter", "url": "https://example.com/alpine.zip", "sha256": "abc"},
+		{"name": "htmx", "description": "Company HTMX kit", "url": "https://example.com/htmx.zip"},
+	})
+
+	kits, err := starterKits()
+	if err != nil {
+		t.Fatalf("starterKits() error = %v", err)
+	}
+
+	var names []string
+	for _, kit := range kits {
+		names = append(names, kit.Name)
+	}
+	if got := strings.Join(names, ","); got != "alpine,bootstrap-site,htmx,xmlui" {
+		t.Errorf("starterKits() names = %s", got)
+	}
+
+	htmx, err := findStarterKit("htmx")
+	if err != nil {
+		t.Fatalf("findStarterKit(htmx) error = %v", err)
+	}
+	if htmx.URL != "https://example.com/htmx.zip" || htmx.builtin {
+		t.Errorf("catalog entry should replace the built-in htmx kit, got %+v", htmx)
+	}
+
+	if _, err := findStarterKit("vue"); err == nil || !strings.Contains(err.Error(), "alpine") {
+		t.Errorf("findStarterKit(vue) error = %v, want the available kits listed", err)
+	}
+
+	viper.Set(settingStarterKits, []map[string]any{{"name": "broken"}})
+	if _, err := starterKits(); err == nil {
+		t.Error("starterKits() should reject a catalog entry without a url")
+	}
+}
+
+func TestExtractZipSubdir(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "kit.zip")
+	os.WriteFile(zipPath, buildTestZip(t, map[string]string{
+		"examples-main/README.md":          "readme",
+		"examples-main/starter/index.html": "<html>",
+		"other/skip.txt":                   "skip",
+	}), 0644)
+
+	dest := t.TempDir()
+	if err := extractZipSubdir(zipPath, "examples-main", dest); err != nil {
+		t.Fatalf("extractZipSubdir() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "starter", "index.html")); err != nil {
+		t.Errorf("expected starter/index.html to be extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "other")); !os.IsNotExist(err) {
+		t.Error("files outside the subdir should not be extracted")
+	}
+
+	if err := extractZipSubdir(zipPath, "missing", t.TempDir()); err == nil {
+		t.Error("extractZipSubdir() should fail when the subdir is not in the archive")
+	}
+}
+
+func TestRunStarterKitChecksum(t *testing.T) {
+	archive := buildTestZip(t, map[string]string{"index.html": "<html>"})
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	kit := starterKit{Name: "test", URL: server.URL + "/kit.zip", SHA256: checksum}
+
+	dir := t.TempDir()
+	if err := runStarterKit(kit, dir, ""); err != nil {
+		t.Fatalf("runStarterKit() with a matching checksum error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
+		t.Errorf("expected index.html to be extracted: %v", err)
+	}
+
+	dir = t.TempDir()
+	err := runStarterKit(kit, dir, strings.Repeat("0", 64))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("runStarterKit() with a wrong checksum error = %v, want a checksum mismatch", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); !os.IsNotExist(err) {
+		t.Error("nothing should be extracted when the checksum does not match")
+	}
+}
+
+func TestRunStarterKitWithoutChecksum(t *testing.T) {
+	archive := buildTestZip(t, map[string]string{"index.html": "<html>"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	// Built-in kits have no pinned checksum and still bootstrap
+	kit, err := findStarterKit("htmx")
+	if err != nil {
+		t.Fatalf("findStarterKit(htmx) error = %v", err)
+	}
+	kit.URL = server.URL + "/htmx-starter.zip"
+
+	dir := t.TempDir()
+	if err := runStarterKit(kit, dir, ""); err != nil {
+		t.Fatalf("runStarterKit() without --sha256 error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
+		t.Errorf("expected index.html to be extracted: %v", err)
+	}
+}
@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
 	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
@@ -19,6 +24,19 @@ var (
 	syncForce          bool
 	syncDryRun         bool
 	syncNoPackageCache bool
+	syncJSON           bool
+	syncGroups         []string
+	syncProd           bool
+	syncDev            bool
+	syncRetries        int
+	syncRetryBackoff   time.Duration
+	syncAllowShared    bool
+	syncLinkMode       string
+	syncMigrate        string
+	syncOnlyExt        []string
+	syncExcludeExt     []string
+	syncProgressJSON   string
+	syncTarball        bool
 )
 
 // syncCmd represents the sync command
@@ -39,16 +57,58 @@ This is useful after cloning a project or updating the configuration file.
 Flags:
   --force: Re-download all files even if they exist locally
   --dry-run: Show what would be downloaded without actually downloading
+  --json: Print the final summary as JSON (progress is written to stderr);
+          with --dry-run, print the files that would be downloaded in the
+          plan format instead (see 'smfaman plan')
+  --progress-json: Stream newline-delimited JSON progress events (task_start,
+                   bytes, done, error, summary) to stdout, or to the Unix
+                   socket given as --progress-json=<path>
+  --group: Only sync libraries in the given group (repeatable)
+  --prod: Use each library's files_prod list instead of the configured profile
+  --dev: Also sync the libraries under dev_libraries (not allowed with the
+         prod profile)
+  --retries: Retry transient download failures this many times (default 3)
+  --retry-backoff: Initial delay between retries, doubled after each attempt
+  --allow-shared: Proceed even if libraries share destination folders
+  --link-mode: Place files as copy, symlink or hardlink to the package cache
+               store (overrides link_mode in the config)
+  --only-ext: Only download files with the given extensions (comma-separated),
+              on top of the configured file lists
+  --exclude-ext: Skip files with the given extensions (comma-separated)
+  --migrate: With {version} in the destination, remove the previous version's
+             folder after the new one is downloaded (--migrate=archive moves it
+             to .smfaman-archive/ instead)
+  --tarball: Download each unpkg or jsDelivr library as its npm tarball, verified
+             against the registry's dist.integrity (or shasum) before any file
+             is extracted; the tarball hash is recorded in the lockfile
+
+Files that still fail after retrying are reported at the end; the remaining
+files are downloaded regardless.
+
+Before downloading, sync shows how many files and roughly how many bytes it
+will fetch and, in a terminal, asks to continue (--yes skips the question).
 
 Example:
   smfaman sync
   smfaman sync -f myproject.yaml
   smfaman sync --force
-  smfaman sync --dry-run`,
+  smfaman sync --dry-run
+  smfaman sync --group admin
+  smfaman sync --prod
+  smfaman sync --dev
+  smfaman sync --link-mode symlink
+  smfaman sync --only-ext js,css,woff2
+  smfaman sync --exclude-ext map,ts
+  smfaman sync --migrate
+  smfaman sync --migrate=archive
+  smfaman sync --tarball
+  smfaman sync --json > sync-summary.json
+  smfaman sync --dry-run --json | jq '.tasks[] | select(.reason == "changed")'
+  smfaman sync --progress-json | my-dashboard
+  smfaman sync --progress-json=/tmp/smfaman.sock`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runSync(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -58,23 +118,74 @@ func init() {
 	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Re-download all files even if they exist")
 	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would be downloaded without downloading")
 	syncCmd.Flags().BoolVar(&syncNoPackageCache, "no-package-cache", false, "Disable package caching and download directly")
+	syncCmd.Flags().BoolVar(&syncJSON, "json", false, "Print the sync summary as JSON")
+	syncCmd.Flags().BoolVar(&syncProd, "prod", false, "Use production file lists (files_prod)")
+	syncCmd.Flags().BoolVar(&syncDev, "dev", false, "Also sync development-only libraries (dev_libraries)")
+	syncCmd.Flags().BoolVar(&syncAllowShared, "allow-shared", false, "Proceed even if libraries share destination folders")
+	syncCmd.Flags().IntVar(&syncRetries, "retries", 3, "Number of retries for transient download failures")
+	syncCmd.Flags().DurationVar(&syncRetryBackoff, "retry-backoff", time.Second, "Initial delay between download retries")
+	syncCmd.Flags().StringArrayVarP(&syncGroups, "group", "g", nil, "Only sync libraries in this group (repeatable)")
+	syncCmd.Flags().StringVar(&syncLinkMode, "link-mode", "", "How files are placed: copy, symlink or hardlink")
+	syncCmd.Flags().StringSliceVar(&syncOnlyExt, "only-ext", nil, "Only download files with these extensions (e.g. js,css,woff2)")
+	syncCmd.Flags().StringSliceVar(&syncExcludeExt, "exclude-ext", nil, "Skip files with these extensions (e.g. map,ts)")
+	syncCmd.Flags().StringVar(&syncMigrate, "migrate", "", "Remove (or archive) previous version folders after upgrading: remove or archive")
+	syncCmd.Flags().Lookup("migrate").NoOptDefVal = migrateRemove
+	syncCmd.Flags().StringVar(&syncProgressJSON, "progress-json", "", "Stream JSON progress events to stdout, or to a Unix socket path")
+	syncCmd.Flags().Lookup("progress-json").NoOptDefVal = progressStdout
+	syncCmd.Flags().BoolVar(&syncTarball, "tarball", false, "Download npm packages as tarballs verified against the registry's integrity")
 }
 
 // DownloadTask represents a file to download
 type DownloadTask struct {
-	LibraryName string
-	Version     string
-	CDN         frontend_config.CDN
-	FilePath    string // Path on CDN
-	DestPath    string // Local destination path
-	URL         string
-	Size        int64
+	LibraryName string              `json:"library"`
+	Version     string              `json:"version"`
+	CDN         frontend_config.CDN `json:"cdn"`
+	FilePath    string              `json:"file"`                       // Path on CDN
+	DestPath    string              `json:"destination"`                // Local destination path
+	DestRoot    string              `json:"destination_root,omitempty"` // Library destination folder
+	URL         string              `json:"url"`
+	Size        int64               `json:"size"`
+
+	// Reason explains why the file is downloaded ("missing", "forced",
+	// "mirror" or "changed")
+	Reason string `json:"reason"`
+
+	// MirrorPaths are additional local paths the file is copied to
+	MirrorPaths []string `json:"mirrors,omitempty"`
+
+	// LinkMode places the file by linking to the package store instead of copying
+	LinkMode frontend_config.LinkMode `json:"link_mode,omitempty"`
+
+	// ETag and LastModified are the validators from the previous download,
+	// used to skip forced re-downloads of unchanged files
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// StripSourceMap removes sourceMappingURL comments from the file
+	StripSourceMap bool `json:"strip_source_map,omitempty"`
+
+	// Integrity is the expected SRI hash published by the CDN, if any
+	Integrity string `json:"integrity,omitempty"`
+
+	// CopyOf is the destination of an earlier task in the same run with the
+	// same integrity; the file is copied from there instead of downloaded
+	CopyOf string `json:"copy_of,omitempty"`
+
+	// Headers and Query are added to download requests on their hosts.
+	// They are kept as configured, with environment variable references
+	// unexpanded, and left out of plans; apply reads them from the config.
+	Headers frontend_config.HostValues `json:"-"`
+	Query   frontend_config.HostValues `json:"-"`
+
+	// tarballData is the file's contents from the package tarball verified
+	// by --tarball, and tarballIntegrity the tarball's hash
+	tarballData      []byte
+	tarballIntegrity string
 }
 
 // runSync executes the sync command
 func runSync() error {
-	// Load config
-	config, err := loadConfig(FrontendConfig)
+	config, err := loadSyncConfig()
 	if err != nil {
 		return err
 	}
@@ -89,38 +200,284 @@ func runSync() error {
 	if err != nil {
 		return err
 	}
+	if err := applyStoredValidators(FrontendConfig, tasks); err != nil {
+		return err
+	}
+
+	out, err := syncOutput()
+	if err != nil {
+		return err
+	}
 
 	if len(tasks) == 0 {
-		fmt.Println("✓ All libraries are up to date!")
-		return nil
+		fmt.Fprintln(out, "✓ All libraries are up to date!")
+		if err := migrateVersionFolders(FrontendConfig, config, syncMigrate, syncDryRun, out); err != nil {
+			return err
+		}
+		if syncDryRun && syncJSON {
+			return printDryRunPlan(nil)
+		}
+		return writeEmptySyncSummary()
 	}
 
 	// Show summary
-	fmt.Printf("\nLibraries to sync: %d\n", len(config.Libraries))
-	fmt.Printf("Files to download: %d\n\n", len(tasks))
+	fmt.Fprintf(out, "\nLibraries to sync: %d\n", len(config.Libraries))
+	fmt.Fprintf(out, "Files to download: %d\n\n", len(tasks))
 
 	if syncDryRun {
-		fmt.Println("Dry run - would download:")
+		fmt.Fprintln(out, "Dry run - would download:")
 		for _, task := range tasks {
-			fmt.Printf("  • %s@%s: %s → %s\n", task.LibraryName, task.Version, task.FilePath, task.DestPath)
+			fmt.Fprintf(out, "  • %s@%s: %s → %s\n", task.LibraryName, task.Version, task.FilePath, task.DestPath)
+		}
+		fmt.Fprintf(out, "\nWould download %s.\n", formatDownloadEstimate(tasks))
+		if err := migrateVersionFolders(FrontendConfig, config, syncMigrate, syncDryRun, out); err != nil {
+			return err
 		}
+		if syncJSON {
+			return printDryRunPlan(tasks)
+		}
+		return nil
+	}
+
+	if !confirmSyncDownload(tasks, out) {
+		fmt.Fprintln(out, "Sync cancelled.")
 		return nil
 	}
 
-	// Run interactive download with progress (fallback to simple mode if no TTY)
-	return runDownloadWithProgress(tasks)
+	if err := executeDownloadTasks(FrontendConfig, tasks, out); err != nil {
+		return err
+	}
+
+	// Previous version folders are only touched once the new version is in place
+	return migrateVersionFolders(FrontendConfig, config, syncMigrate, syncDryRun, out)
+}
+
+// printDryRunPlan prints the tasks of a dry run as a plan, which scripts can
+// inspect or pass to apply
+func printDryRunPlan(tasks []DownloadTask) error {
+	data, err := marshalSyncPlan(newSyncPlan(FrontendConfig, tasks))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// loadSyncConfig loads the frontend config and applies the --dev, --group and --prod selections
+func loadSyncConfig() (*frontend_config.FrontendConfig, error) {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Development-only libraries never end up in a production vendor folder
+	if syncDev {
+		if syncProd || config.Profile == frontend_config.ProfileProd {
+			return nil, fmt.Errorf("--dev can't be used with the prod profile; dev_libraries are never synced for production")
+		}
+		if config, err = config.WithDevLibraries(); err != nil {
+			return nil, configError(err)
+		}
+	}
+
+	if len(config.Libraries) == 0 {
+		return config, nil
+	}
+
+	// Restrict to the selected groups
+	config, err = selectLibraryGroups(config, syncGroups)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files from libraries sharing a folder would overwrite each other
+	if err := checkSharedDestinations(config, syncAllowShared, syncDryRun); err != nil {
+		return nil, err
+	}
+
+	// Select file variants
+	if syncProd {
+		config.Profile = frontend_config.ProfileProd
+	}
+	if !frontend_config.IsValidProfile(config.Profile) {
+		return nil, fmt.Errorf("invalid profile %q (must be %s or %s)", config.Profile, frontend_config.ProfileDev, frontend_config.ProfileProd)
+	}
+
+	if !isValidMigrateMode(syncMigrate) {
+		return nil, fmt.Errorf("invalid --migrate value %q (must be %s or %s)", syncMigrate, migrateRemove, migrateArchive)
+	}
+
+	// Select how files are placed into destinations
+	if syncLinkMode != "" {
+		config.LinkMode = frontend_config.LinkMode(syncLinkMode)
+	}
+	if !frontend_config.IsValidLinkMode(config.LinkMode) {
+		return nil, fmt.Errorf("invalid link mode %q (must be %s, %s or %s)", config.LinkMode,
+			frontend_config.LinkModeCopy, frontend_config.LinkModeSymlink, frontend_config.LinkModeHardlink)
+	}
+
+	return config, nil
+}
+
+// executeDownloadTasks downloads the tasks, records them in the config's
+// manifest, and prints the summary
+func executeDownloadTasks(configPath string, tasks []DownloadTask, out io.Writer) error {
+	_, err := downloadAndRecord(configPath, tasks, out)
+	return err
+}
+
+// downloadAndRecord is executeDownloadTasks returning the summary of what
+// was written, which is nil when nothing could be started
+func downloadAndRecord(configPath string, tasks []DownloadTask, out io.Writer) (*syncSummary, error) {
+	if err := markNewDestinations(configPath, tasks); err != nil {
+		return nil, err
+	}
+
+	// Verify whole package tarballs before any file is written
+	if syncTarball {
+		if err := extractPackageTarballs(tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	var events *progressEmitter
+	if syncProgressJSON != "" {
+		var err error
+		if events, err = openProgressEmitter(syncProgressJSON); err != nil {
+			return nil, err
+		}
+		defer events.Close()
+	}
+
+	var summary *syncSummary
+	var err error
+	if events != nil {
+		summary, err = runProgressJSONDownload(tasks, events)
+	} else if syncJSON {
+		summary, err = runSimpleDownload(tasks, out)
+	} else {
+		// Run interactive download with progress (fallback to simple mode if no TTY)
+		summary, err = runDownloadWithProgress(tasks)
+	}
+	if summary == nil {
+		return nil, err
+	}
+
+	// Re-apply local fixes before recording what was written
+	applyLibraryPatches(configPath, summary.downloaded, out)
+
+	// Record provenance of downloaded files
+	if manifestErr := updateManifest(configPath, summary.downloaded); manifestErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", manifestErr)
+	}
+
+	// Let webhooks, scripts or the desktop know what changed
+	if n, changed := syncNotification(configPath, summary); changed && notificationsEnabled() {
+		if config, err := loadConfig(configPath); err == nil {
+			n.Project = config.ProjectName
+		}
+		sendNotification(n)
+	}
+
+	if events != nil {
+		events.emit(progressEvent{Event: "summary", Summary: summary})
+	}
+	if syncJSON {
+		if jsonErr := writeSyncSummaryJSON(os.Stdout, summary); jsonErr != nil {
+			return summary, jsonErr
+		}
+		return summary, err
+	}
+	if events != nil && syncProgressJSON == progressStdout {
+		return summary, err
+	}
+
+	if err == nil {
+		fmt.Printf("\n✓ Sync complete!\n\n")
+	} else {
+		fmt.Printf("\n✗ Sync finished with errors\n\n")
+	}
+	printSyncSummary(os.Stdout, summary)
+	return summary, err
+}
+
+// writeEmptySyncSummary reports a run with nothing to download on the JSON outputs
+func writeEmptySyncSummary() error {
+	summary := newSyncSummary()
+	summary.finish()
+
+	if syncProgressJSON != "" {
+		events, err := openProgressEmitter(syncProgressJSON)
+		if err != nil {
+			return err
+		}
+		defer events.Close()
+		events.emit(progressEvent{Event: "start"})
+		events.emit(progressEvent{Event: "summary", Summary: summary})
+	}
+	if syncJSON {
+		return writeSyncSummaryJSON(os.Stdout, summary)
+	}
+	return nil
+}
+
+// syncOutput returns where human-readable output goes, keeping stdout clean
+// when it carries the JSON summary or progress events
+func syncOutput() (io.Writer, error) {
+	if syncJSON && syncProgressJSON == progressStdout {
+		return nil, fmt.Errorf("--json and --progress-json can't both write to stdout (pass a socket path to --progress-json)")
+	}
+	if syncJSON || syncProgressJSON == progressStdout {
+		return os.Stderr, nil
+	}
+	return os.Stdout, nil
+}
+
+// selectLibraryGroups restricts the config to libraries in the given groups,
+// returning an error if a group is not used by any library
+func selectLibraryGroups(config *frontend_config.FrontendConfig, groups []string) (*frontend_config.FrontendConfig, error) {
+	if len(groups) == 0 {
+		return config, nil
+	}
+
+	known := config.GetGroups()
+	for _, group := range groups {
+		if !slices.Contains(known, group) {
+			if len(known) == 0 {
+				return nil, fmt.Errorf("unknown group %q: no libraries have groups configured", group)
+			}
+			return nil, fmt.Errorf("unknown group %q (available: %s)", group, strings.Join(known, ", "))
+		}
+	}
+
+	return config.FilterByGroups(groups), nil
 }
 
 // buildDownloadTasks creates a list of files to download
 func buildDownloadTasks(config *frontend_config.FrontendConfig) ([]DownloadTask, error) {
 	var tasks []DownloadTask
 
+	// Existing files listed in the ignore file are never overwritten
+	ignore, err := loadIgnoreFile(FrontendConfig)
+	if err != nil {
+		return nil, err
+	}
+	ignored := 0
+
+	// Existing files are compared with what the lockfile recorded for them
+	manifestPath := manifestPathForConfig(FrontendConfig)
+	manifest := &fileManifest{Files: map[string]manifestEntry{}}
+	if config.LockfileEnabled() {
+		if manifest, err = loadManifest(manifestPath); err != nil {
+			return nil, err
+		}
+	}
+	changed := 0
+	longPaths := 0
+
 	for libName, libConfig := range config.Libraries {
 		// Determine CDN
-		cdn := config.GetLibraryCDN(libConfig)
-		if cdn == "" {
-			cdn = frontend_config.CDNUnpkg
-		}
+		cdn := libraryCDN(config, libConfig)
 
 		// Get destination path
 		destPath, err := config.GetLibraryDestination(libName, libConfig)
@@ -128,24 +485,101 @@ func buildDownloadTasks(config *frontend_config.FrontendConfig) ([]DownloadTask,
 			return nil, fmt.Errorf("failed to get destination for %s: %w", libName, err)
 		}
 
+		// Get mirror paths
+		mirrors, err := config.GetLibraryMirrors(libName, libConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mirrors for %s: %w", libName, err)
+		}
+
 		// Fetch file list from CDN (uses caching)
 		files, err := fetchFileList(libName, libConfig.Version, cdn)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch files for %s: %w", libName, err)
 		}
 
-		// Filter files if specific files are configured
-		if len(libConfig.Files) > 0 {
-			files = filterFiles(files, libConfig.Files)
+		// Filter files if specific files are configured, otherwise apply the files mode
+		filesMode := config.GetLibraryFilesMode(libConfig)
+		if !frontend_config.IsValidFilesMode(filesMode) {
+			return nil, fmt.Errorf("invalid files_mode %q for %s (must be %s, %s or %s)", filesMode, libName,
+				frontend_config.FilesModeEntrypoints, frontend_config.FilesModeAll, frontend_config.FilesModeMinified)
+		}
+		allFiles := files
+		if patterns := config.GetLibraryFiles(libConfig); len(patterns) > 0 {
+			files = filterFiles(files, patterns)
+		} else if filesMode == frontend_config.FilesModeEntrypoints {
+			files = selectEntrypointFiles(libName, libConfig.Version, files)
+		} else if filesMode == frontend_config.FilesModeMinified {
+			files = selectMinifiedFiles(libName, files)
+		}
+
+		// Apply sourcemap handling
+		sourceMaps := config.GetLibrarySourceMaps(libConfig)
+		if !frontend_config.IsValidSourceMaps(sourceMaps) {
+			return nil, fmt.Errorf("invalid sourcemaps setting %q for %s (must be %s or %s)",
+				sourceMaps, libName, frontend_config.SourceMapsInclude, frontend_config.SourceMapsExclude)
+		}
+		files = applySourceMaps(files, allFiles, sourceMaps)
+
+		// Apply one-off extension filters
+		files = filterByExtension(files, syncOnlyExt, syncExcludeExt)
+
+		// Files differing only by case would overwrite each other on macOS and Windows
+		if !frontend_config.IsValidCaseCollisions(config.CaseCollisions) {
+			return nil, fmt.Errorf("invalid case_collisions %q (must be %s, %s or %s)", config.CaseCollisions,
+				frontend_config.CaseCollisionsRename, frontend_config.CaseCollisionsSkip, frontend_config.CaseCollisionsError)
+		}
+		localPaths := localFilePaths(files, libConfig.StripPrefix)
+		files, collisions := resolveCaseCollisions(files, localPaths, config.CaseCollisions)
+		if err := reportCaseCollisions(os.Stderr, libName, collisions, config.CaseCollisions); err != nil {
+			return nil, err
+		}
+
+		// Patched files no longer match the CDN's hashes
+		patches, err := loadLibraryPatch(libraryPatchPath(FrontendConfig, libName))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		}
 
 		// Create download tasks
 		for _, file := range files {
-			localPath := filepath.Join(destPath, file.Path)
+			localName := localPaths[file.Path]
+			localPath := filepath.Join(destPath, filepath.FromSlash(localName))
+			if len(localPath) >= windowsMaxPath {
+				longPaths++
+			}
 
-			// Skip if file exists and not forcing
-			if !syncForce {
-				if _, err := os.Stat(localPath); err == nil {
+			var mirrorPaths []string
+			for _, mirror := range mirrors {
+				mirrorPaths = append(mirrorPaths, filepath.Join(mirror, filepath.FromSlash(localName)))
+			}
+
+			stripSourceMap := sourceMaps == frontend_config.SourceMapsExclude && hasSourceMapComment(file.Path)
+
+			// Skip if file exists (including in every mirror), is intact and not forcing
+			reason := "missing"
+			if _, err := os.Stat(localPath); err == nil {
+				key, err := manifestKey(manifestPath, localPath)
+				if err != nil {
+					return nil, err
+				}
+				recorded, ok := manifest.Files[key]
+				ok = ok && recorded.URL == file.URL
+				rewritten := stripSourceMap || slices.ContainsFunc(patches, func(p filePatch) bool { return p.Path == file.Path })
+
+				switch {
+				case ignore.Match(localPath):
+					if syncForce || !allFilesExist(mirrorPaths) {
+						ignored++
+					}
+					continue
+				case syncForce:
+					reason = "forced"
+				case localFileDiffers(localPath, file, recorded, ok, rewritten):
+					reason = "changed"
+					changed++
+				case !allFilesExist(mirrorPaths):
+					reason = "mirror"
+				default:
 					continue
 				}
 			}
@@ -156,90 +590,146 @@ func buildDownloadTasks(config *frontend_config.FrontendConfig) ([]DownloadTask,
 				CDN:         cdn,
 				FilePath:    file.Path,
 				DestPath:    localPath,
+				DestRoot:    destPath,
 				URL:         file.URL,
 				Size:        file.Size,
+				Integrity:   file.Integrity,
+				Reason:      reason,
+				MirrorPaths: mirrorPaths,
+				LinkMode:    config.LinkMode,
+
+				StripSourceMap: stripSourceMap,
+				Headers:        config.GetLibraryHeaders(libConfig),
+				Query:          config.GetLibraryQuery(libConfig),
 			}
+
 			tasks = append(tasks, task)
 		}
 	}
 
+	if ignored > 0 {
+		fmt.Fprintf(os.Stderr, "Keeping %d %s matched by %s\n", ignored, pluralize(ignored, "file", "files"), ignoreFileName)
+	}
+	if longPaths > 0 && runtime.GOOS == "windows" {
+		fmt.Fprintf(os.Stderr, "Warning: %d %s longer than %d characters; sync writes them, but tools without long path support (Explorer, git without core.longpaths) may fail on them. Use strip_prefix or a shorter destination to shorten them.\n",
+			longPaths, pluralize(longPaths, "file path is", "file paths are"), windowsMaxPath-1)
+	}
+	if changed > 0 {
+		fmt.Fprintf(os.Stderr, "Re-downloading %d %s that changed since download\n", changed, pluralize(changed, "file", "files"))
+	}
+
+	// Download files shared by several libraries only once
+	dedupeTasks(tasks)
+
+	// Look up sizes the CDN metadata doesn't provide
+	fillTaskSizes(tasks, projectConcurrency(config))
+
 	return tasks, nil
 }
 
-// CDNFile represents a file available on a CDN
-type CDNFile struct {
-	Path string
-	URL  string
-	Size int64
+// fetchContentLength looks up the size of a file on a CDN (overridable in tests)
+var fetchContentLength = frontend_mgr.FetchContentLength
+
+// fillTaskSizes looks up the size of tasks without one, running up to
+// concurrency requests at a time
+func fillTaskSizes(tasks []DownloadTask, concurrency int) {
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range tasks {
+		if tasks[i].Size != 0 || tasks[i].CopyOf != "" {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(task *DownloadTask) {
+			defer func() { <-slots; wg.Done() }()
+			if size, err := fetchContentLength(task.URL); err == nil {
+				task.Size = size
+			}
+		}(&tasks[i])
+	}
+	wg.Wait()
 }
 
-// fetchFileList fetches the list of files for a library from the CDN
-func fetchFileList(libName, version string, cdn frontend_config.CDN) ([]CDNFile, error) {
-	var files []CDNFile
-
-	switch cdn {
-	case frontend_config.CDNUnpkg:
-		meta, err := frontend_mgr.FetchUnpkgMeta(libName, version)
-		if err != nil {
-			return nil, err
+// localFileDiffers reports whether an existing file no longer matches what
+// sync wrote: the integrity the lockfile recorded for it or, for files the
+// lockfile doesn't list (or lists for another URL), the size and integrity
+// the CDN publishes. Files sync rewrites (stripped sourcemap comments, local
+// patches) are only compared with the lockfile.
+func localFileDiffers(localPath string, file CDNFile, recorded manifestEntry, isRecorded, rewritten bool) bool {
+	integrity := recorded.Integrity
+	if !isRecorded {
+		if rewritten {
+			return false
 		}
-		// UNPKG returns all files (no directories), Type field contains MIME type
-		for _, file := range meta.Files {
-			files = append(files, CDNFile{
-				Path: strings.TrimPrefix(file.Path, "/"),
-				URL:  fmt.Sprintf("https://unpkg.com/%s@%s%s", libName, version, file.Path),
-				Size: int64(file.Size),
-			})
+		if info, err := os.Stat(localPath); err == nil && file.Size > 0 && info.Size() != file.Size {
+			return true
 		}
+		integrity = file.Integrity
+	}
+	if integrity == "" {
+		return false
+	}
 
-	case frontend_config.CDNCdnjs:
-		resp, err := frontend_mgr.FetchCdnjsVersion(libName, version)
-		if err != nil {
-			return nil, err
-		}
-		for _, file := range resp.Files {
-			files = append(files, CDNFile{
-				Path: file,
-				URL:  fmt.Sprintf("https://cdnjs.cloudflare.com/ajax/libs/%s/%s/%s", libName, version, file),
-				Size: 0, // CDNJS doesn't provide size in metadata
-			})
-		}
+	data, err := os.ReadFile(localPath)
+	return err == nil && frontend_mgr.VerifySRI(data, integrity) != nil
+}
 
-	case frontend_config.CDNJsdelivr:
-		resp, err := frontend_mgr.FetchJsdelivrPackage(libName, version)
-		if err != nil {
-			return nil, err
+// allFilesExist reports whether every path exists
+func allFilesExist(paths []string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return false
 		}
-		// Recursively collect files from jsDelivr tree
-		files = collectJsdelivrFiles(libName, version, resp.Files, "")
-
-	default:
-		return nil, fmt.Errorf("unsupported CDN: %s", cdn)
 	}
+	return true
+}
 
-	return files, nil
+// CDNFile represents a file available on a CDN
+type CDNFile struct {
+	Path      string
+	URL       string
+	Size      int64
+	Integrity string // SRI hash, if published by the CDN
 }
 
-// collectJsdelivrFiles recursively collects files from jsDelivr file tree
-func collectJsdelivrFiles(libName, version string, jsFiles []frontend_mgr.JsdelivrFile, basePath string) []CDNFile {
-	var files []CDNFile
+// cdnPackageURL returns the base URL of a library version on a CDN
+func cdnPackageURL(libName, version string, cdn frontend_config.CDN) string {
+	return cdnFileURL(libName, version, cdn, "")
+}
 
-	for _, f := range jsFiles {
-		path := filepath.Join(basePath, f.Name)
+// cdnFileURL returns the URL of a file in a library version on a CDN,
+// falling back to unpkg for unknown CDNs
+func cdnFileURL(libName, version string, cdn frontend_config.CDN, filePath string) string {
+	provider, err := frontend_mgr.GetProvider(string(cdn))
+	if err != nil {
+		provider, _ = frontend_mgr.GetProvider(string(frontend_config.CDNUnpkg))
+	}
+	return provider.FileURL(libName, version, filePath)
+}
 
-		if f.Type == "file" {
-			files = append(files, CDNFile{
-				Path: path,
-				URL:  fmt.Sprintf("https://cdn.jsdelivr.net/npm/%s@%s/%s", libName, version, path),
-				Size: int64(f.Size),
-			})
-		} else if f.Type == "directory" && len(f.Files) > 0 {
-			// Recursively collect files from subdirectories
-			files = append(files, collectJsdelivrFiles(libName, version, f.Files, path)...)
-		}
+// fetchFileList fetches the list of files for a library from the CDN
+func fetchFileList(libName, version string, cdn frontend_config.CDN) ([]CDNFile, error) {
+	provider, err := frontend_mgr.GetProvider(string(cdn))
+	if err != nil {
+		return nil, err
 	}
 
-	return files
+	published, err := provider.Manifest(libName, version)
+	if err != nil {
+		return nil, privatePackageError(libName, version, cdn, err)
+	}
+
+	files := make([]CDNFile, 0, len(published))
+	for _, file := range published {
+		files = append(files, CDNFile{
+			Path:      file.Path,
+			URL:       provider.FileURL(libName, version, file.Path),
+			Size:      file.Size,
+			Integrity: file.Integrity,
+		})
+	}
+	return files, nil
 }
 
 // filterFiles filters file list based on configured files
@@ -259,6 +749,40 @@ func filterFiles(files []CDNFile, patterns []string) []CDNFile {
 	return filtered
 }
 
+// filterByExtension keeps files matching one of the only extensions (if any)
+// and drops files matching one of the exclude extensions
+func filterByExtension(files []CDNFile, only, exclude []string) []CDNFile {
+	if len(only) == 0 && len(exclude) == 0 {
+		return files
+	}
+
+	var filtered []CDNFile
+	for _, file := range files {
+		if len(only) > 0 && !hasExtension(file.Path, only) {
+			continue
+		}
+		if hasExtension(file.Path, exclude) {
+			continue
+		}
+		filtered = append(filtered, file)
+	}
+
+	return filtered
+}
+
+// hasExtension reports whether path ends with one of the extensions
+// (case-insensitive, with or without a leading dot, e.g. "js", ".d.ts")
+func hasExtension(path string, extensions []string) bool {
+	lowerPath := strings.ToLower(path)
+	for _, ext := range extensions {
+		ext = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ext), "."))
+		if ext != "" && strings.HasSuffix(lowerPath, "."+ext) {
+			return true
+		}
+	}
+	return false
+}
+
 // downloadFile downloads a file from URL to destination
 func downloadFile(url, destPath string) error {
 	// This is a wrapper that will be replaced by downloadFileWithTask
@@ -267,7 +791,9 @@ func downloadFile(url, destPath string) error {
 }
 
 // downloadFileWithTask downloads a file with package caching support
-func downloadFileWithTask(task DownloadTask) error {
+func downloadFileWithTask(task DownloadTask) (fileDownloadResult, error) {
+	start := time.Now()
+
 	// Set package cache enabled/disabled based on flag
 	if syncNoPackageCache {
 		frontend_mgr.CacheManager.SetPackageCacheEnabled(false)
@@ -276,9 +802,12 @@ func downloadFileWithTask(task DownloadTask) error {
 	var fileData []byte
 	var err error
 	cached := false
+	notModified := false
+	validators := httpValidators{ETag: task.ETag, LastModified: task.LastModified}
 
-	// Try to get from package cache first
-	if !syncNoPackageCache && !syncForce {
+	// Try to get from package cache first, unless the file comes from a
+	// verified tarball
+	if !syncNoPackageCache && !syncForce && task.tarballData == nil {
 		fileData, cached, err = frontend_mgr.CacheManager.GetPackageFile(
 			string(task.CDN),
 			task.LibraryName,
@@ -289,13 +818,48 @@ func downloadFileWithTask(task DownloadTask) error {
 			// Log warning but continue with download
 			fmt.Fprintf(os.Stderr, "Warning: cache read failed: %v\n", err)
 		}
+
+		// Discard cached copies that fail verification
+		if cached && task.Integrity != "" {
+			if err := frontend_mgr.VerifySRI(fileData, task.Integrity); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: cached %s failed verification, re-downloading\n", task.FilePath)
+				cached = false
+			}
+		}
 	}
 
-	// If not cached, download from CDN
-	if !cached {
-		fileData, err = downloadFileToMemory(task.URL)
+	// Files identical to one written earlier in this sync are copied from it
+	copied := false
+	if !cached && task.CopyOf != "" {
+		if data, err := os.ReadFile(task.CopyOf); err == nil && frontend_mgr.VerifySRI(data, task.Integrity) == nil {
+			fileData, copied = data, true
+		}
+	}
+
+	// If not cached or copied, take it from the tarball or download it from the CDN
+	if !cached && !copied && task.tarballData != nil {
+		fileData = task.tarballData
+	} else if !cached && !copied {
+		fileData, validators, notModified, err = downloadFileConditional(task.URL, validators, task.requestOptions())
 		if err != nil {
-			return err
+			return fileDownloadResult{}, err
+		}
+	}
+
+	// The remote file hasn't changed since it was last written; reuse the local copy
+	if notModified {
+		fileData, err = os.ReadFile(task.DestPath)
+		if err != nil {
+			return fileDownloadResult{}, fmt.Errorf("failed to read unchanged file: %w", err)
+		}
+	}
+
+	if !cached && !notModified {
+		// Verify against the CDN's published hash before caching
+		if task.Integrity != "" {
+			if err := frontend_mgr.VerifySRI(fileData, task.Integrity); err != nil {
+				return fileDownloadResult{}, verificationError(fmt.Errorf("failed to verify %s: %w", task.FilePath, err))
+			}
 		}
 
 		// Save to package cache
@@ -313,129 +877,136 @@ func downloadFileWithTask(task DownloadTask) error {
 		}
 	}
 
-	// Create destination directory
-	dir := filepath.Dir(task.DestPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+	// Remove sourcemap references when maps are excluded, keeping the hash
+	// of the file as served for CDN tags
+	cdnIntegrity := ""
+	if task.StripSourceMap && !notModified {
+		served := fileData
+		fileData = stripSourceMappingURL(fileData)
+		if !bytes.Equal(served, fileData) {
+			cdnIntegrity = frontend_mgr.ComputeSRI(served)
+		}
 	}
 
-	// Write to destination
-	if err := os.WriteFile(task.DestPath, fileData, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	// Leave byte-identical files untouched when forcing
+	unchanged := notModified
+	if !unchanged && task.Reason == "forced" {
+		if existing, err := os.ReadFile(task.DestPath); err == nil && bytes.Equal(existing, fileData) {
+			unchanged = true
+		}
 	}
 
-	return nil
-}
-
-// downloadFileToMemory downloads a file to memory
-func downloadFileToMemory(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download: %w", err)
+	// Link to the package store copy when the file is stored unmodified
+	storePath := ""
+	if task.LinkMode != "" && !task.StripSourceMap && !syncNoPackageCache {
+		storePath, _ = frontend_mgr.CacheManager.PackageFilePath(
+			string(task.CDN),
+			task.LibraryName,
+			task.Version,
+			task.FilePath,
+		)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	// Write to destination and keep mirrors in lockstep with it
+	destPaths := append([]string{task.DestPath}, task.MirrorPaths...)
+	if unchanged {
+		destPaths = task.MirrorPaths
+	}
+	for _, destPath := range destPaths {
+		if err := placeFile(destPath, fileData, storePath, task.LinkMode); err != nil {
+			return fileDownloadResult{}, err
+		}
 	}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	result := fileDownloadResult{
+		Bytes:        int64(len(fileData)),
+		FromCache:    cached,
+		Copied:       copied,
+		Unchanged:    unchanged,
+		Duration:     time.Since(start),
+		Integrity:    frontend_mgr.ComputeSRI(fileData),
+		CDNIntegrity: cdnIntegrity,
+		ETag:         validators.ETag,
+		LastModified: validators.LastModified,
+	}
+	if notModified {
+		result.Bytes = 0
 	}
+	return result, nil
+}
 
-	return data, nil
+// downloadFileToMemory downloads a file to memory, retrying transient failures
+func downloadFileToMemory(url string) ([]byte, error) {
+	data, _, _, err := downloadFileConditional(url, httpValidators{}, requestOptions{})
+	return data, err
 }
 
 // downloadFileDirectly downloads a file directly without caching
 func downloadFileDirectly(url, destPath string) error {
-	// Create destination directory
-	dir := filepath.Dir(destPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Download file
-	resp, err := http.Get(url)
-	if err != nil {
-		return fmt.Errorf("failed to download: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("server returned status %d", resp.StatusCode)
-	}
-
-	// Create file
-	out, err := os.Create(destPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer out.Close()
-
-	// Copy data
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
-	}
-
-	return nil
+	return downloadToFile(url, destPath, nil)
 }
 
 // runDownloadWithProgress runs the download with progress UI if TTY available, otherwise simple mode
-func runDownloadWithProgress(tasks []DownloadTask) error {
-	// Try interactive mode first
+func runDownloadWithProgress(tasks []DownloadTask) (*syncSummary, error) {
+	if !interactiveTerminal() {
+		return runSimpleDownload(tasks, os.Stdout)
+	}
+
 	m := newSyncModel(tasks)
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
 	if err != nil {
-		// If TTY error, fall back to simple mode
-		if strings.Contains(err.Error(), "TTY") || strings.Contains(err.Error(), "tty") {
-			return runSimpleDownload(tasks)
-		}
-		return fmt.Errorf("error running interactive download: %w", err)
+		return nil, fmt.Errorf("error running interactive download: %w", err)
 	}
 
-	if sm, ok := finalModel.(syncModel); ok {
-		if sm.err != nil {
-			return sm.err
-		}
-
-		fmt.Printf("\n✓ Sync complete!\n")
-		fmt.Printf("Downloaded %d files\n", len(tasks))
+	sm, ok := finalModel.(syncModel)
+	if !ok {
+		return nil, fmt.Errorf("unexpected model type %T", finalModel)
 	}
 
-	return nil
+	sm.summary.finish()
+	return sm.summary, sm.summary.err()
 }
 
 // runSimpleDownload runs the download with simple text progress (no TTY required)
-func runSimpleDownload(tasks []DownloadTask) error {
-	fmt.Println("Downloading files...")
+func runSimpleDownload(tasks []DownloadTask, out io.Writer) (*syncSummary, error) {
+	fmt.Fprintln(out, "Downloading files...")
 
+	summary := newSyncSummary()
 	for i, task := range tasks {
-		fmt.Printf("[%d/%d] %s@%s: %s\n", i+1, len(tasks), task.LibraryName, task.Version, task.FilePath)
+		fmt.Fprintf(out, "[%d/%d] %s@%s: %s\n", i+1, len(tasks), task.LibraryName, task.Version, task.FilePath)
 
-		err := downloadFileWithTask(task)
+		result, err := downloadFileWithTask(task)
 		if err != nil {
-			return fmt.Errorf("failed to download %s: %w", task.FilePath, err)
+			fmt.Fprintf(out, "  ✗ %v\n", err)
+			summary.recordFailure(task, err)
+			continue
 		}
+		summary.record(task, result)
 	}
 
-	fmt.Printf("\n✓ Sync complete!\n")
-	fmt.Printf("Downloaded %d files\n", len(tasks))
-
-	return nil
+	summary.finish()
+	return summary, summary.err()
 }
 
 // Messages for the sync model
 type downloadStartMsg struct{ task DownloadTask }
 type downloadProgressMsg struct{ percent float64 }
-type downloadCompleteMsg struct{ task DownloadTask }
-type downloadErrorMsg struct{ err error }
+type downloadCompleteMsg struct {
+	task   DownloadTask
+	result fileDownloadResult
+}
+type downloadErrorMsg struct {
+	task DownloadTask
+	err  error
+}
 type allCompleteMsg struct{}
 type tickMsg time.Time
 
+// syncCancelKey stops the sync progress view
+var syncCancelKey = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "cancel the sync"))
+
 // syncModel is the Bubble Tea model for sync progress
 type syncModel struct {
 	tasks        []DownloadTask
@@ -443,9 +1014,10 @@ type syncModel struct {
 	currentTask  *DownloadTask
 	progress     float64
 	completed    int
-	err          error
 	downloading  bool
 	startTime    time.Time
+	summary      *syncSummary
+	showHelp     bool
 }
 
 func newSyncModel(tasks []DownloadTask) syncModel {
@@ -453,6 +1025,7 @@ func newSyncModel(tasks []DownloadTask) syncModel {
 		tasks:        tasks,
 		currentIndex: 0,
 		completed:    0,
+		summary:      newSyncSummary(),
 	}
 }
 
@@ -466,8 +1039,13 @@ func (m syncModel) Init() tea.Cmd {
 func (m syncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
+		switch {
+		case key.Matches(msg, syncCancelKey):
 			return m, tea.Quit
+		case m.showHelp:
+			m.showHelp = !helpClosed(msg)
+		case helpRequested(msg, false):
+			m.showHelp = true
 		}
 
 	case downloadStartMsg:
@@ -503,6 +1081,7 @@ func (m syncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.progress = 1.0
 		m.completed++
 		m.currentIndex++
+		m.summary.record(msg.task, msg.result)
 
 		if m.currentIndex >= len(m.tasks) {
 			return m, func() tea.Msg { return allCompleteMsg{} }
@@ -511,21 +1090,37 @@ func (m syncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.startDownload()
 
 	case downloadErrorMsg:
-		m.err = msg.err
-		return m, tea.Quit
+		// Record the failure and keep going with the remaining files
+		m.downloading = false
+		m.currentIndex++
+		m.summary.recordFailure(msg.task, msg.err)
+
+		if m.currentIndex >= len(m.tasks) {
+			return m, func() tea.Msg { return allCompleteMsg{} }
+		}
+
+		return m, m.startDownload()
 
 	case allCompleteMsg:
+		// Leave the progress, not the help, on screen
+		m.showHelp = false
 		return m, tea.Quit
 	}
 
 	return m, nil
 }
 
+// Styles of the sync progress view (colored by the theme)
+var (
+	syncHeaderStyle = lipgloss.NewStyle().Bold(true)
+	syncBarStyle    = lipgloss.NewStyle()
+)
+
 func (m syncModel) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n", m.err)
+	if m.showHelp {
+		return renderHelpOverlay("sync (downloads continue in the background)",
+			helpSection{title: "Sync", bindings: []key.Binding{syncCancelKey}}, generalHelpSection())
 	}
-
 	if m.currentTask == nil {
 		return "Preparing to download...\n"
 	}
@@ -533,11 +1128,19 @@ func (m syncModel) View() string {
 	var s strings.Builder
 
 	// Overall progress
-	s.WriteString(fmt.Sprintf("Syncing libraries... [%d/%d files]\n\n", m.completed, len(m.tasks)))
+	s.WriteString(syncHeaderStyle.Render(fmt.Sprintf("Syncing libraries... [%d/%d files]", m.completed, len(m.tasks))))
+	if failed := len(m.summary.Failed); failed > 0 {
+		s.WriteString(fmt.Sprintf(" (%d failed)", failed))
+	}
+	s.WriteString("\n\n")
 
 	// Current file
 	s.WriteString(fmt.Sprintf("Library: %s@%s\n", m.currentTask.LibraryName, m.currentTask.Version))
-	s.WriteString(fmt.Sprintf("File:    %s\n", m.currentTask.FilePath))
+	if m.currentTask.Size > 0 {
+		s.WriteString(fmt.Sprintf("File:    %s (%s)\n", m.currentTask.FilePath, formatBytes(m.currentTask.Size)))
+	} else {
+		s.WriteString(fmt.Sprintf("File:    %s\n", m.currentTask.FilePath))
+	}
 
 	// Progress bar
 	barWidth := 40
@@ -546,8 +1149,11 @@ func (m syncModel) View() string {
 		filled = barWidth
 	}
 
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+	bar := syncBarStyle.Render(strings.Repeat("█", filled)) + strings.Repeat("░", barWidth-filled)
 	s.WriteString(fmt.Sprintf("\n[%s] %.1f%%\n", bar, m.progress*100))
+	if m.currentIndex < len(m.tasks) {
+		s.WriteString(helpStyle.Render("? help • ctrl+c cancel") + "\n")
+	}
 
 	return s.String()
 }
@@ -562,15 +1168,15 @@ func (m syncModel) downloadNext() tea.Cmd {
 
 		// Download the file (this happens in background)
 		// Use downloadFileWithTask for package caching support
-		err := downloadFileWithTask(task)
+		result, err := downloadFileWithTask(task)
 		if err != nil {
-			return downloadErrorMsg{err: fmt.Errorf("failed to download %s: %w", task.FilePath, err)}
+			return downloadErrorMsg{task: task, err: err}
 		}
 
 		// Add a small delay to ensure progress is visible
 		time.Sleep(100 * time.Millisecond)
 
-		return downloadCompleteMsg{task: task}
+		return downloadCompleteMsg{task: task, result: result}
 	}
 }
 
@@ -0,0 +1,25 @@
+package cmd
+
+// dedupeTasks points every task whose file the CDN publishes with the same
+// integrity as an earlier task's at that task's destination (CopyOf), so
+// files shared by several libraries, such as icons and fonts bundled by
+// different themes, are downloaded once per sync. Tasks run in order, so
+// the earlier file is in place when a copy is made; downloadFileWithTask
+// still verifies it and downloads the file itself if it doesn't match.
+func dedupeTasks(tasks []DownloadTask) {
+	sources := make(map[string]string)
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Integrity == "" {
+			continue
+		}
+		if source, ok := sources[task.Integrity]; ok {
+			task.CopyOf = source
+			continue
+		}
+		// Files with stripped sourcemap comments differ from the CDN's copy
+		if !task.StripSourceMap {
+			sources[task.Integrity] = task.DestPath
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestDedupeTasks(t *testing.T) {
+	icon := frontend_mgr.ComputeSRI([]byte("<svg/>"))
+	tasks := []DownloadTask{
+		{LibraryName: "theme-a", DestPath: "a/icons.svg", Integrity: icon, Size: 6},
+		{LibraryName: "theme-a", DestPath: "a/app.js", Integrity: frontend_mgr.ComputeSRI([]byte("a"))},
+		{LibraryName: "theme-b", DestPath: "b/icons.svg", Integrity: icon, Size: 6},
+		{LibraryName: "theme-c", DestPath: "c/icons.svg", Integrity: icon, Size: 6},
+		{LibraryName: "cdnjs-lib", DestPath: "d/icons.svg"},
+	}
+	dedupeTasks(tasks)
+
+	for i, want := range []string{"", "", "a/icons.svg", "a/icons.svg", ""} {
+		if tasks[i].CopyOf != want {
+			t.Errorf("tasks[%d].CopyOf = %q, want %q", i, tasks[i].CopyOf, want)
+		}
+	}
+	if got := formatDownloadEstimate(tasks); got != "5 files (2 copied from identical files), ~6 B (size unknown for 2)" {
+		t.Errorf("formatDownloadEstimate() = %q", got)
+	}
+
+	// A file rewritten without its sourcemap comment can't be copied from
+	stripped := []DownloadTask{
+		{DestPath: "a/app.js", Integrity: icon, StripSourceMap: true},
+		{DestPath: "b/app.js", Integrity: icon},
+		{DestPath: "c/app.js", Integrity: icon},
+	}
+	dedupeTasks(stripped)
+	if stripped[1].CopyOf != "" || stripped[2].CopyOf != "b/app.js" {
+		t.Errorf("CopyOf = %q, %q, want \"\", b/app.js", stripped[1].CopyOf, stripped[2].CopyOf)
+	}
+}
+
+// manifestProvider stands in for a CDN with a fixed file listing
+type manifestProvider struct {
+	name    string
+	files   []frontend_mgr.PackageFile
+	fileURL func(name, version, filePath string) string
+}
+
+func (p manifestProvider) Name() string { return p.name }
+func (manifestProvider) Versions(string) (*frontend_mgr.VersionList, error) {
+	return &frontend_mgr.VersionList{}, nil
+}
+func (p manifestProvider) Manifest(string, string) ([]frontend_mgr.PackageFile, error) {
+	return p.files, nil
+}
+func (p manifestProvider) FileURL(name, version, filePath string) string {
+	return p.fileURL(name, version, filePath)
+}
+func (manifestProvider) Search(string, int) ([]frontend_mgr.SearchResult, error) { return nil, nil }
+
+func TestBuildDownloadTasksDedupesUnpkgAndJsdelivrFiles(t *testing.T) {
+	icon := []byte("<svg/>")
+	// unpkg publishes an SRI and jsDelivr a base64 sha256 the provider turns into one
+	sum := sha256.Sum256(icon)
+	jsdelivrIntegrity := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+	for _, p := range []manifestProvider{
+		{name: "unpkg", fileURL: frontend_mgr.UnpkgFileURL, files: []frontend_mgr.PackageFile{
+			{Path: "icons.svg", Size: int64(len(icon)), Integrity: frontend_mgr.ComputeSRI(icon)},
+		}},
+		{name: "jsdelivr", fileURL: frontend_mgr.JsdelivrFileURL, files: []frontend_mgr.PackageFile{
+			{Path: "icons.svg", Size: int64(len(icon)), Integrity: jsdelivrIntegrity},
+		}},
+	} {
+		orig, err := frontend_mgr.GetProvider(p.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { frontend_mgr.RegisterProvider(orig) })
+		frontend_mgr.RegisterProvider(p)
+	}
+
+	tmpDir := t.TempDir()
+	oldConfig := FrontendConfig
+	FrontendConfig = filepath.Join(tmpDir, "smartfrontend.yaml")
+	defer func() { FrontendConfig = oldConfig }()
+
+	config := &frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "{library_name}"),
+		FilesMode:   frontend_config.FilesModeAll,
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"theme-a": {Version: "1.0.0", CDN: frontend_config.CDNUnpkg},
+			"theme-b": {Version: "1.0.0", CDN: frontend_config.CDNUnpkg},
+			"theme-c": {Version: "1.0.0", CDN: frontend_config.CDNJsdelivr},
+			"theme-d": {Version: "1.0.0", CDN: frontend_config.CDNJsdelivr},
+		},
+	}
+	tasks, err := buildDownloadTasks(config)
+	if err != nil {
+		t.Fatalf("buildDownloadTasks failed: %v", err)
+	}
+
+	// Each CDN's copy is downloaded once; the hashes use different algorithms,
+	// so files aren't shared across CDNs
+	copies := map[frontend_config.CDN][]string{}
+	for _, task := range tasks {
+		if task.Integrity == "" {
+			t.Errorf("%s has no integrity", task.DestPath)
+		}
+		if task.CopyOf != "" {
+			copies[task.CDN] = append(copies[task.CDN], task.LibraryName)
+		}
+	}
+	if len(tasks) != 4 || len(copies[frontend_config.CDNUnpkg]) != 1 || len(copies[frontend_config.CDNJsdelivr]) != 1 {
+		t.Errorf("got %d tasks with copies %v, want one copy per CDN", len(tasks), copies)
+	}
+}
+
+func TestDownloadFileWithTaskCopiesIdenticalFiles(t *testing.T) {
+	content := []byte("@font-face{}")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	defer func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "a", "fonts.css")
+	if err := os.MkdirAll(filepath.Dir(source), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(source, content, 0644)
+
+	task := DownloadTask{
+		FilePath:  "fonts.css",
+		DestPath:  filepath.Join(tmpDir, "b", "fonts.css"),
+		URL:       server.URL,
+		Integrity: frontend_mgr.ComputeSRI(content),
+		CopyOf:    source,
+	}
+	result, err := downloadFileWithTask(task)
+	if err != nil {
+		t.Fatalf("downloadFileWithTask failed: %v", err)
+	}
+	if !result.Copied || requests != 0 {
+		t.Errorf("Copied = %v after %d requests, want a copy without downloading", result.Copied, requests)
+	}
+	if data, _ := os.ReadFile(task.DestPath); string(data) != string(content) {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	// A source that no longer matches is downloaded instead
+	os.WriteFile(source, []byte("patched"), 0644)
+	task.DestPath = filepath.Join(tmpDir, "c", "fonts.css")
+	if result, err = downloadFileWithTask(task); err != nil || result.Copied || requests != 1 {
+		t.Errorf("Copied = %v, %d requests, err %v; want a download", result.Copied, requests, err)
+	}
+
+	summary := newSyncSummary()
+	summary.record(task, fileDownloadResult{Copied: true})
+	if summary.CopiedFiles != 1 || summary.NetworkFiles != 0 {
+		t.Errorf("CopiedFiles = %d, NetworkFiles = %d", summary.CopiedFiles, summary.NetworkFiles)
+	}
+}
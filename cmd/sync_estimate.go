@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+)
+
+// downloadEstimate adds up the known sizes of tasks, returning the total
+// and how many tasks have no known size (e.g. CDNJS files whose size
+// lookup failed). Copies of identical files aren't downloaded.
+func downloadEstimate(tasks []DownloadTask) (int64, int) {
+	var total int64
+	unknown := 0
+	for _, task := range tasks {
+		if task.CopyOf != "" {
+			continue
+		}
+		if task.Size > 0 {
+			total += task.Size
+		} else {
+			unknown++
+		}
+	}
+	return total, unknown
+}
+
+// formatDownloadEstimate describes what a sync will download, e.g.
+// "143 files, ~4.21 MB"
+func formatDownloadEstimate(tasks []DownloadTask) string {
+	files := fmt.Sprintf("%d files", len(tasks))
+	if len(tasks) == 1 {
+		files = "1 file"
+	}
+
+	copies := 0
+	for _, task := range tasks {
+		if task.CopyOf != "" {
+			copies++
+		}
+	}
+	if copies > 0 {
+		files += fmt.Sprintf(" (%d copied from identical files)", copies)
+	}
+
+	total, unknown := downloadEstimate(tasks)
+	switch {
+	case unknown == len(tasks)-copies:
+		return files + " (size unknown)"
+	case unknown > 0:
+		return fmt.Sprintf("%s, ~%s (size unknown for %d)", files, formatBytes(total), unknown)
+	}
+	return fmt.Sprintf("%s, ~%s", files, formatBytes(total))
+}
+
+// confirmSyncDownload shows the download estimate and, in a terminal, asks
+// before starting. --yes (or SMFAMAN_ASSUME_YES) and non-interactive runs
+// continue without asking.
+func confirmSyncDownload(tasks []DownloadTask, out io.Writer) bool {
+	estimate := formatDownloadEstimate(tasks)
+	if syncJSON || syncProgressJSON != "" || !interactiveTerminal() {
+		fmt.Fprintf(out, "Will download %s.\n", estimate)
+		return true
+	}
+	return promptConfirmationDefaultYes(fmt.Sprintf("Will download %s. Continue?", estimate))
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFormatDownloadEstimate(t *testing.T) {
+	tests := []struct {
+		sizes []int64
+		want  string
+	}{
+		{[]int64{1024, 3072}, "2 files, ~4.00 KB"},
+		{[]int64{1536}, "1 file, ~1.50 KB"},
+		{[]int64{2048, 0}, "2 files, ~2.00 KB (size unknown for 1)"},
+		{[]int64{0, 0}, "2 files (size unknown)"},
+	}
+
+	for _, tt := range tests {
+		tasks := make([]DownloadTask, len(tt.sizes))
+		for i, size := range tt.sizes {
+			tasks[i].Size = size
+		}
+		if got := formatDownloadEstimate(tasks); got != tt.want {
+			t.Errorf("formatDownloadEstimate(%v) = %q, want %q", tt.sizes, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmSyncDownload(t *testing.T) {
+	origTerminal := interactiveTerminal
+	origAssumeYes := assumeYes
+	origInput := confirmationInput
+	t.Setenv("SMFAMAN_ASSUME_YES", "")
+	defer func() {
+		interactiveTerminal = origTerminal
+		assumeYes = origAssumeYes
+		confirmationInput = origInput
+	}()
+	assumeYes = false
+	tasks := []DownloadTask{{Size: 2048}, {Size: 2048}}
+
+	t.Run("non-interactive runs continue", func(t *testing.T) {
+		interactiveTerminal = func() bool { return false }
+		var out bytes.Buffer
+		if !confirmSyncDownload(tasks, &out) {
+			t.Error("confirmSyncDownload() = false without a terminal")
+		}
+		if !strings.Contains(out.String(), "Will download 2 files, ~4.00 KB.") {
+			t.Errorf("output %q doesn't show the estimate", out.String())
+		}
+	})
+
+	interactiveTerminal = func() bool { return true }
+	for _, tt := range []struct {
+		answer string
+		want   bool
+	}{
+		{"\n", true},
+		{"y\n", true},
+		{"n\n", false},
+	} {
+		confirmationInput = strings.NewReader(tt.answer)
+		if got := confirmSyncDownload(tasks, &bytes.Buffer{}); got != tt.want {
+			t.Errorf("answer %q: confirmSyncDownload() = %v, want %v", tt.answer, got, tt.want)
+		}
+	}
+
+	t.Run("--yes skips the question", func(t *testing.T) {
+		assumeYes = true
+		confirmationInput = strings.NewReader("n\n")
+		if !confirmSyncDownload(tasks, &bytes.Buffer{}) {
+			t.Error("confirmSyncDownload() = false with --yes")
+		}
+	})
+}
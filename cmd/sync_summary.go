@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// fileDownloadResult describes how a single file was fetched
+type fileDownloadResult struct {
+	Bytes        int64
+	FromCache    bool
+	Copied       bool // Copied from an identical file written earlier in the run
+	Unchanged    bool // Identical to the existing local file, which was left untouched
+	Duration     time.Duration
+	Integrity    string // SRI hash of the written file
+	CDNIntegrity string // SRI hash of the file as served, when sync changed it before writing
+	ETag         string // HTTP validators returned by the CDN
+	LastModified string
+}
+
+// downloadedFile pairs a completed task with its result
+type downloadedFile struct {
+	task         DownloadTask
+	result       fileDownloadResult
+	downloadedAt time.Time
+}
+
+// librarySyncStats holds the per-library totals for a sync run
+type librarySyncStats struct {
+	Library        string  `json:"library"`
+	Version        string  `json:"version"`
+	Files          int     `json:"files"`
+	CachedFiles    int     `json:"cached_files"`
+	CopiedFiles    int     `json:"copied_files"`
+	NetworkFiles   int     `json:"network_files"`
+	UnchangedFiles int     `json:"unchanged_files"`
+	Bytes          int64   `json:"bytes"`
+	ElapsedMs      float64 `json:"elapsed_ms"`
+}
+
+// syncFailure describes a file that could not be downloaded
+type syncFailure struct {
+	Library string `json:"library"`
+	Version string `json:"version"`
+	File    string `json:"file"`
+	Error   string `json:"error"`
+	cause   error  // Decides the exit code
+}
+
+// syncSummary accumulates statistics across a sync run
+type syncSummary struct {
+	Libraries      []*librarySyncStats `json:"libraries"`
+	Files          int                 `json:"files"`
+	CachedFiles    int                 `json:"cached_files"`
+	CopiedFiles    int                 `json:"copied_files"`
+	NetworkFiles   int                 `json:"network_files"`
+	UnchangedFiles int                 `json:"unchanged_files"`
+	Bytes          int64               `json:"bytes"`
+	ElapsedMs      float64             `json:"elapsed_ms"`
+	Failed         []syncFailure       `json:"failed"`
+
+	index      map[string]*librarySyncStats
+	downloaded []downloadedFile
+	startTime  time.Time
+}
+
+// newSyncSummary creates an empty summary and starts its clock
+func newSyncSummary() *syncSummary {
+	return &syncSummary{
+		Libraries: []*librarySyncStats{},
+		Failed:    []syncFailure{},
+		index:     make(map[string]*librarySyncStats),
+		startTime: time.Now(),
+	}
+}
+
+// record adds the result of a downloaded file to the summary
+func (s *syncSummary) record(task DownloadTask, result fileDownloadResult) {
+	key := task.LibraryName + "@" + task.Version
+	stats, ok := s.index[key]
+	if !ok {
+		stats = &librarySyncStats{Library: task.LibraryName, Version: task.Version}
+		s.index[key] = stats
+		s.Libraries = append(s.Libraries, stats)
+	}
+
+	s.downloaded = append(s.downloaded, downloadedFile{task: task, result: result, downloadedAt: time.Now()})
+
+	stats.Files++
+	stats.Bytes += result.Bytes
+	stats.ElapsedMs += durationMs(result.Duration)
+	s.Files++
+	s.Bytes += result.Bytes
+
+	if result.FromCache {
+		stats.CachedFiles++
+		s.CachedFiles++
+	} else if result.Copied {
+		stats.CopiedFiles++
+		s.CopiedFiles++
+	} else {
+		stats.NetworkFiles++
+		s.NetworkFiles++
+	}
+
+	if result.Unchanged {
+		stats.UnchangedFiles++
+		s.UnchangedFiles++
+	}
+}
+
+// recordFailure adds a file that failed to download to the summary
+func (s *syncSummary) recordFailure(task DownloadTask, err error) {
+	s.Failed = append(s.Failed, syncFailure{
+		Library: task.LibraryName,
+		Version: task.Version,
+		File:    task.FilePath,
+		Error:   err.Error(),
+		cause:   err,
+	})
+}
+
+// err returns an error if any file failed to download
+func (s *syncSummary) err() error {
+	if len(s.Failed) == 0 {
+		return nil
+	}
+	causes := make([]error, len(s.Failed))
+	for i, f := range s.Failed {
+		causes[i] = f.cause
+	}
+	return batchError(fmt.Errorf("failed to download %d %s", len(s.Failed), pluralize(len(s.Failed), "file", "files")), causes)
+}
+
+// finish stops the summary clock
+func (s *syncSummary) finish() {
+	s.ElapsedMs = durationMs(time.Since(s.startTime))
+}
+
+// durationMs converts a duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// formatDurationMs formats milliseconds for display
+func formatDurationMs(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(time.Millisecond).String()
+}
+
+// printSyncSummary writes the summary as a formatted table
+func printSyncSummary(w io.Writer, s *syncSummary) {
+	maxName := len("LIBRARY")
+	for _, lib := range s.Libraries {
+		if n := len(lib.Library) + 1 + len(lib.Version); n > maxName {
+			maxName = n
+		}
+	}
+
+	format := fmt.Sprintf("%%-%ds  %%6s  %%6s  %%7s  %%10s  %%8s\n", maxName)
+	fmt.Fprintf(w, format, "LIBRARY", "FILES", "CACHED", "NETWORK", "SIZE", "TIME")
+	fmt.Fprintln(w, strings.Repeat("─", maxName)+"  "+strings.Repeat("─", 6)+"  "+
+		strings.Repeat("─", 6)+"  "+strings.Repeat("─", 7)+"  "+
+		strings.Repeat("─", 10)+"  "+strings.Repeat("─", 8))
+
+	for _, lib := range s.Libraries {
+		fmt.Fprintf(w, format,
+			lib.Library+"@"+lib.Version,
+			fmt.Sprint(lib.Files),
+			fmt.Sprint(lib.CachedFiles),
+			fmt.Sprint(lib.NetworkFiles),
+			formatBytes(lib.Bytes),
+			formatDurationMs(lib.ElapsedMs),
+		)
+	}
+
+	fmt.Fprintf(w, "\nDownloaded %d %s (%s) in %s — %d from cache, %d from network",
+		s.Files, pluralize(s.Files, "file", "files"), formatBytes(s.Bytes),
+		formatDurationMs(s.ElapsedMs), s.CachedFiles, s.NetworkFiles)
+	if s.CopiedFiles > 0 {
+		fmt.Fprintf(w, ", %d copied from identical files", s.CopiedFiles)
+	}
+	fmt.Fprintln(w)
+	if s.UnchangedFiles > 0 {
+		fmt.Fprintf(w, "%d %s unchanged on the CDN and left as is\n",
+			s.UnchangedFiles, pluralize(s.UnchangedFiles, "file", "files"))
+	}
+
+	if len(s.Failed) > 0 {
+		fmt.Fprintf(w, "\nFailed %d %s:\n", len(s.Failed), pluralize(len(s.Failed), "file", "files"))
+		for _, f := range s.Failed {
+			fmt.Fprintf(w, "  ✗ %s@%s: %s: %s\n", f.Library, f.Version, f.File, f.Error)
+		}
+	}
+}
+
+// writeSyncSummaryJSON writes the summary as JSON
+func writeSyncSummaryJSON(w io.Writer, s *syncSummary) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyncSummaryRecord(t *testing.T) {
+	summary := newSyncSummary()
+
+	jquery := DownloadTask{LibraryName: "jquery", Version: "3.7.1"}
+	bootstrap := DownloadTask{LibraryName: "bootstrap", Version: "5.3.0"}
+
+	summary.record(jquery, fileDownloadResult{Bytes: 1000, FromCache: true, Duration: 2 * time.Millisecond})
+	summary.record(bootstrap, fileDownloadResult{Bytes: 500, Duration: 10 * time.Millisecond})
+	summary.record(jquery, fileDownloadResult{Bytes: 3000, Duration: 5 * time.Millisecond})
+	summary.finish()
+
+	if summary.Files != 3 {
+		t.Errorf("expected 3 files, got %d", summary.Files)
+	}
+	if summary.Bytes != 4500 {
+		t.Errorf("expected 4500 bytes, got %d", summary.Bytes)
+	}
+	if summary.CachedFiles != 1 || summary.NetworkFiles != 2 {
+		t.Errorf("expected 1 cached and 2 network files, got %d and %d", summary.CachedFiles, summary.NetworkFiles)
+	}
+
+	if len(summary.Libraries) != 2 {
+		t.Fatalf("expected 2 libraries, got %d", len(summary.Libraries))
+	}
+
+	// Libraries keep the order they were first seen in
+	lib := summary.Libraries[0]
+	if lib.Library != "jquery" || lib.Version != "3.7.1" {
+		t.Errorf("expected jquery@3.7.1 first, got %s@%s", lib.Library, lib.Version)
+	}
+	if lib.Files != 2 || lib.Bytes != 4000 || lib.CachedFiles != 1 || lib.NetworkFiles != 1 {
+		t.Errorf("unexpected jquery stats: %+v", lib)
+	}
+	if lib.ElapsedMs != 7 {
+		t.Errorf("expected 7ms elapsed for jquery, got %v", lib.ElapsedMs)
+	}
+}
+
+func TestPrintSyncSummary(t *testing.T) {
+	summary := newSyncSummary()
+	summary.record(DownloadTask{LibraryName: "jquery", Version: "3.7.1"}, fileDownloadResult{Bytes: 2048})
+	summary.finish()
+
+	var buf bytes.Buffer
+	printSyncSummary(&buf, summary)
+	output := buf.String()
+
+	for _, want := range []string{"LIBRARY", "jquery@3.7.1", "2.00 KB", "Downloaded 1 file", "0 from cache, 1 from network"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestWriteSyncSummaryJSON(t *testing.T) {
+	summary := newSyncSummary()
+	summary.record(DownloadTask{LibraryName: "jquery", Version: "3.7.1"}, fileDownloadResult{Bytes: 100, FromCache: true})
+	summary.finish()
+
+	var buf bytes.Buffer
+	if err := writeSyncSummaryJSON(&buf, summary); err != nil {
+		t.Fatalf("writeSyncSummaryJSON failed: %v", err)
+	}
+
+	var decoded struct {
+		Libraries []librarySyncStats `json:"libraries"`
+		Files     int                `json:"files"`
+		Bytes     int64              `json:"bytes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode summary JSON: %v", err)
+	}
+
+	if decoded.Files != 1 || decoded.Bytes != 100 {
+		t.Errorf("unexpected totals: files=%d bytes=%d", decoded.Files, decoded.Bytes)
+	}
+	if len(decoded.Libraries) != 1 || decoded.Libraries[0].CachedFiles != 1 {
+		t.Errorf("unexpected libraries: %+v", decoded.Libraries)
+	}
+}
+
+func TestWriteSyncSummaryJSONEmpty(t *testing.T) {
+	summary := newSyncSummary()
+	summary.finish()
+
+	var buf bytes.Buffer
+	if err := writeSyncSummaryJSON(&buf, summary); err != nil {
+		t.Fatalf("writeSyncSummaryJSON failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"libraries": []`) {
+		t.Errorf("expected empty libraries array, got:\n%s", buf.String())
+	}
+}
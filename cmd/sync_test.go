@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -55,45 +58,36 @@ func TestFilterFiles(t *testing.T) {
 	}
 }
 
-func TestCollectJsdelivrFiles(t *testing.T) {
-	// Mock jsDelivr file structure
-	jsFiles := []frontend_mgr.JsdelivrFile{
-		{
-			Name: "dist",
-			Type: "directory",
-			Files: []frontend_mgr.JsdelivrFile{
-				{Name: "jquery.min.js", Type: "file", Size: 1000},
-				{Name: "jquery.js", Type: "file", Size: 5000},
-			},
-		},
-		{
-			Name: "package.json",
-			Type: "file",
-			Size: 500,
-		},
+func TestFilterByExtension(t *testing.T) {
+	files := []CDNFile{
+		{Path: "dist/app.min.js"},
+		{Path: "dist/app.min.js.map"},
+		{Path: "dist/app.css"},
+		{Path: "dist/app.d.ts"},
+		{Path: "fonts/icons.WOFF2"},
+		{Path: "package.json"},
 	}
 
-	files := collectJsdelivrFiles("jquery", "3.7.1", jsFiles, "")
-
-	// Should have 3 files total (2 in dist/ + 1 at root)
-	if len(files) != 3 {
-		t.Errorf("expected 3 files, got %d", len(files))
+	tests := []struct {
+		name     string
+		only     []string
+		exclude  []string
+		expected int
+	}{
+		{name: "no filters", expected: 6},
+		{name: "only", only: []string{"js", ".css", "woff2"}, expected: 3},
+		{name: "exclude", exclude: []string{"map", "ts"}, expected: 4},
+		{name: "only and exclude", only: []string{"js", "map"}, exclude: []string{"map"}, expected: 1},
+		{name: "compound extension", only: []string{"d.ts"}, expected: 1},
 	}
 
-	// Check that dist files have correct paths
-	foundDistFile := false
-	for _, f := range files {
-		if f.Path == filepath.Join("dist", "jquery.min.js") {
-			foundDistFile = true
-			expectedURL := "https://cdn.jsdelivr.net/npm/jquery@3.7.1/dist/jquery.min.js"
-			if f.URL != expectedURL {
-				t.Errorf("expected URL %s, got %s", expectedURL, f.URL)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := filterByExtension(files, tt.only, tt.exclude)
+			if len(filtered) != tt.expected {
+				t.Errorf("expected %d files, got %d: %v", tt.expected, len(filtered), filtered)
 			}
-		}
-	}
-
-	if !foundDistFile {
-		t.Error("expected to find dist/jquery.min.js in collected files")
+		})
 	}
 }
 
@@ -208,6 +202,106 @@ func TestDownloadFileCreatesDirectories(t *testing.T) {
 	}
 }
 
+func TestDownloadFileWithTaskVerifiesIntegrity(t *testing.T) {
+	content := []byte("console.log('hello');")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	defer func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	tmpDir := t.TempDir()
+
+	t.Run("matching hash", func(t *testing.T) {
+		task := DownloadTask{
+			FilePath:  "hello.js",
+			DestPath:  filepath.Join(tmpDir, "ok", "hello.js"),
+			URL:       server.URL,
+			Integrity: frontend_mgr.ComputeSRI(content),
+		}
+
+		result, err := downloadFileWithTask(task)
+		if err != nil {
+			t.Fatalf("downloadFileWithTask failed: %v", err)
+		}
+		if result.Bytes != int64(len(content)) {
+			t.Errorf("expected %d bytes, got %d", len(content), result.Bytes)
+		}
+		if _, err := os.Stat(task.DestPath); err != nil {
+			t.Errorf("expected file to be written: %v", err)
+		}
+	})
+
+	t.Run("mismatched hash", func(t *testing.T) {
+		task := DownloadTask{
+			FilePath:  "hello.js",
+			DestPath:  filepath.Join(tmpDir, "bad", "hello.js"),
+			URL:       server.URL,
+			Integrity: frontend_mgr.ComputeSRI([]byte("something else")),
+		}
+
+		if _, err := downloadFileWithTask(task); err == nil {
+			t.Fatal("expected integrity error")
+		}
+		if _, err := os.Stat(task.DestPath); !os.IsNotExist(err) {
+			t.Error("expected file not to be written on integrity failure")
+		}
+	})
+}
+
+func TestDownloadFileWithTaskWritesMirrors(t *testing.T) {
+	content := []byte("console.log('mirrored');")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	defer func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	tmpDir := t.TempDir()
+	task := DownloadTask{
+		FilePath: "dist/app.js",
+		DestPath: filepath.Join(tmpDir, "public", "dist", "app.js"),
+		URL:      server.URL,
+		MirrorPaths: []string{
+			filepath.Join(tmpDir, "docs", "dist", "app.js"),
+			filepath.Join(tmpDir, "site", "dist", "app.js"),
+		},
+	}
+
+	if _, err := downloadFileWithTask(task); err != nil {
+		t.Fatalf("downloadFileWithTask failed: %v", err)
+	}
+
+	for _, path := range append([]string{task.DestPath}, task.MirrorPaths...) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", path, err)
+		}
+		if string(data) != string(content) {
+			t.Errorf("unexpected content in %s: %q", path, data)
+		}
+	}
+
+	if !allFilesExist(task.MirrorPaths) {
+		t.Error("expected allFilesExist to report mirrors present")
+	}
+	if allFilesExist([]string{filepath.Join(tmpDir, "missing.js")}) {
+		t.Error("expected allFilesExist to report missing file")
+	}
+}
+
 func TestBuildDownloadTasksEmptyLibraries(t *testing.T) {
 	config := &frontend_config.FrontendConfig{
 		Destination: "./test",
@@ -224,6 +318,43 @@ func TestBuildDownloadTasksEmptyLibraries(t *testing.T) {
 	}
 }
 
+func TestLoadSyncConfigDevLibraries(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	writeTestFile(t, configPath, `destination: ./vendor/{library_name}
+libraries:
+  htmx.org:
+    version: 2.0.4
+dev_libraries:
+  livereload-js:
+    version: 4.0.2
+`)
+	previous := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig, syncDev, syncProd = previous, false, false }()
+
+	config, err := loadSyncConfig()
+	if err != nil {
+		t.Fatalf("loadSyncConfig failed: %v", err)
+	}
+	if _, ok := config.Libraries["livereload-js"]; ok || len(config.Libraries) != 1 {
+		t.Errorf("dev libraries synced without --dev: %v", config.Libraries)
+	}
+
+	syncDev = true
+	config, err = loadSyncConfig()
+	if err != nil {
+		t.Fatalf("loadSyncConfig --dev failed: %v", err)
+	}
+	if _, ok := config.Libraries["livereload-js"]; !ok || len(config.Libraries) != 2 {
+		t.Errorf("expected dev libraries with --dev, got %v", config.Libraries)
+	}
+
+	syncProd = true
+	if _, err := loadSyncConfig(); err == nil {
+		t.Error("expected --dev with --prod to fail")
+	}
+}
+
 func TestCDNFileStructure(t *testing.T) {
 	file := CDNFile{
 		Path: "dist/test.js",
@@ -321,3 +452,60 @@ func TestFetchFileListUnpkg(t *testing.T) {
 		t.Error("expected to find dist/jquery.min.js in UNPKG files")
 	}
 }
+
+func TestFillTaskSizes(t *testing.T) {
+	orig := fetchContentLength
+	defer func() { fetchContentLength = orig }()
+
+	var mu sync.Mutex
+	requested := 0
+	fetchContentLength = func(url string) (int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		requested++
+		return int64(len(url)), nil
+	}
+
+	tasks := []DownloadTask{{URL: "https://cdn.example/a.js"}, {URL: "https://cdn.example/b.css", Size: 7}, {URL: "https://cdn.example/long.js"}}
+	fillTaskSizes(tasks, 2)
+
+	if requested != 2 {
+		t.Errorf("expected lookups only for the 2 tasks without a size, got %d", requested)
+	}
+	for i, want := range []int64{24, 7, 27} {
+		if tasks[i].Size != want {
+			t.Errorf("task %d size = %d, want %d", i, tasks[i].Size, want)
+		}
+	}
+}
+
+func TestLocalFileDiffers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "htmx.min.js")
+	writeTestFile(t, path, "htmx")
+	sri := frontend_mgr.ComputeSRI([]byte("htmx"))
+	other := frontend_mgr.ComputeSRI([]byte("htmx 2"))
+
+	tests := []struct {
+		name       string
+		file       CDNFile
+		recorded   manifestEntry
+		isRecorded bool
+		rewritten  bool
+		want       bool
+	}{
+		{"matches lockfile", CDNFile{Integrity: other}, manifestEntry{Integrity: sri}, true, false, false},
+		{"differs from lockfile", CDNFile{Integrity: sri}, manifestEntry{Integrity: other}, true, false, true},
+		{"patched file checked against lockfile", CDNFile{}, manifestEntry{Integrity: other}, true, true, true},
+		{"matches CDN", CDNFile{Size: 4, Integrity: sri}, manifestEntry{}, false, false, false},
+		{"truncated", CDNFile{Size: 40}, manifestEntry{}, false, false, true},
+		{"differs from CDN hash", CDNFile{Integrity: other}, manifestEntry{}, false, false, true},
+		{"rewritten file not in lockfile", CDNFile{Size: 40, Integrity: other}, manifestEntry{}, false, true, false},
+		{"nothing to compare", CDNFile{}, manifestEntry{}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := localFileDiffers(path, tt.file, tt.recorded, tt.isRecorded, tt.rewritten); got != tt.want {
+			t.Errorf("%s: localFileDiffers() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
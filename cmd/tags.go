@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var (
+	tagsMode string
+	tagsBase string
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags [library...]",
+	Short: "Print <script> and <link> tags for the vendored files",
+	Long: `Print the HTML tags that load the JavaScript and CSS files recorded in the
+lockfile, stylesheets first. Pass library names to limit the output to them.
+
+With --mode cdn (the default) the tags load the files from the exact CDN URLs
+they were downloaded from, with the integrity hash recorded in the lockfile
+and crossorigin="anonymous", so browsers refuse a file the CDN changed. Files
+recorded without a hash are fetched once and their hash is saved in the
+lockfile. Files written without their sourcemap comment use the hash of the
+file the CDN serves. Files changed by a local patch are skipped, as the CDN
+serves them unpatched.
+
+With --mode local the tags load the vendored copies, prefixed with --base.
+
+Examples:
+  smfaman tags                        # CDN tags with integrity attributes
+  smfaman tags jquery bootstrap       # Only these libraries
+  smfaman tags --mode local --base /static/`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runTags(args, tagsMode, tagsBase); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.Flags().StringVar(&tagsMode, "mode", cspModeCDN, "Where the tags load the files from: cdn or local")
+	tagsCmd.Flags().StringVar(&tagsBase, "base", "/", "URL prefix of the vendored files with --mode local")
+}
+
+// fetchTagFile downloads a file whose hash the lockfile is missing
+// (overridable in tests)
+var fetchTagFile = func(url string) ([]byte, error) {
+	data, _, _, err := downloadFileConditional(url, httpValidators{}, requestOptions{})
+	return data, err
+}
+
+// runTags executes the tags command
+func runTags(libs []string, mode, base string) error {
+	if mode != cspModeLocal && mode != cspModeCDN {
+		return fmt.Errorf("invalid --mode %q (must be %s or %s)", mode, cspModeCDN, cspModeLocal)
+	}
+
+	manifestPath := manifestPathForConfig(FrontendConfig)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	tags, hashed, err := vendoredFileTags(manifest, libs, mode, base)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return notFoundError(fmt.Errorf("no JavaScript or CSS files recorded in %s; run 'smfaman sync' first", manifestPath))
+	}
+	if hashed > 0 && lockfileEnabled(FrontendConfig) {
+		if err := saveManifest(manifestPath, manifest); err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range tags {
+		fmt.Println(tag)
+	}
+	return nil
+}
+
+// vendoredFileTags returns the tags loading the JavaScript and CSS files in
+// the manifest, stylesheets first, and how many missing hashes it added
+func vendoredFileTags(manifest *fileManifest, libs []string, mode, base string) ([]string, int, error) {
+	patched, err := patchedFiles(manifest)
+	if err != nil {
+		return nil, 0, err
+	}
+	stripping := sourceMapStrippingLibraries(FrontendConfig)
+
+	var links, scripts []string
+	hashed := 0
+	for _, key := range sortedKeys(manifest.Files) {
+		entry := manifest.Files[key]
+		if len(libs) > 0 && !slices.Contains(libs, entry.Library) {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(key))
+		if ext != ".js" && ext != ".mjs" && ext != ".css" {
+			continue
+		}
+
+		src, integrity, crossorigin := entry.URL, entry.Integrity, "anonymous"
+		// Files sync changed are recorded with the CDN's hash too. Older
+		// lockfiles lack it for stripped files, so it is fetched.
+		servedHash := false
+		if mode == cspModeLocal {
+			src, crossorigin = strings.TrimSuffix(base, "/")+"/"+key, ""
+		} else if patched[entry.Library+"\x00"+entry.File] {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s, it is patched locally and the CDN serves it unpatched\n", key)
+			continue
+		} else if entry.CDNIntegrity != "" {
+			integrity = entry.CDNIntegrity
+		} else if stripping[entry.Library] {
+			integrity, servedHash = "", true
+		}
+
+		if integrity == "" {
+			var data []byte
+			if mode == cspModeLocal {
+				data, err = os.ReadFile(filepath.Join(filepath.Dir(manifestPathForConfig(FrontendConfig)), filepath.FromSlash(key)))
+			} else {
+				data, err = fetchTagFile(entry.URL)
+			}
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to hash %s: %w", key, err)
+			}
+			integrity = frontend_mgr.ComputeSRI(data)
+			if mode == cspModeCDN {
+				if servedHash {
+					entry.CDNIntegrity = integrity
+				} else {
+					entry.Integrity = integrity
+				}
+				manifest.Files[key] = entry
+				hashed++
+			}
+		}
+
+		if ext == ".css" {
+			links = append(links, renderTag("link", ext, src, integrity, crossorigin))
+		} else {
+			scripts = append(scripts, renderTag("script", ext, src, integrity, crossorigin))
+		}
+	}
+	return append(links, scripts...), hashed, nil
+}
+
+// sourceMapStrippingLibraries returns the libraries whose sourceMappingURL
+// comments sync removes, so their vendored files can differ from the CDN's.
+// It is empty when the config can't be read.
+func sourceMapStrippingLibraries(configPath string) map[string]bool {
+	stripping := make(map[string]bool)
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return stripping
+	}
+	var config frontend_config.FrontendConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return stripping
+	}
+	all, err := config.WithDevLibraries()
+	if err != nil {
+		return stripping
+	}
+	for name, libConfig := range all.Libraries {
+		if config.GetLibrarySourceMaps(libConfig) == frontend_config.SourceMapsExclude {
+			stripping[name] = true
+		}
+	}
+	return stripping
+}
+
+// renderTag renders a <link> or <script> tag for a file
+func renderTag(tag, ext, src, integrity, crossorigin string) string {
+	var attrs []string
+	if tag == "link" {
+		attrs = append(attrs, `rel="stylesheet"`, fmt.Sprintf(`href="%s"`, html.EscapeString(src)))
+	} else {
+		if ext == ".mjs" {
+			attrs = append(attrs, `type="module"`)
+		}
+		attrs = append(attrs, fmt.Sprintf(`src="%s"`, html.EscapeString(src)))
+	}
+	if integrity != "" {
+		attrs = append(attrs, fmt.Sprintf(`integrity="%s"`, integrity))
+	}
+	if crossorigin != "" {
+		attrs = append(attrs, fmt.Sprintf(`crossorigin="%s"`, crossorigin))
+	}
+
+	if tag == "link" {
+		return "<link " + strings.Join(attrs, " ") + ">"
+	}
+	return "<script " + strings.Join(attrs, " ") + "></script>"
+}
+
+// patchedFiles returns the library files changed by local patches, keyed by
+// library and file path
+func patchedFiles(manifest *fileManifest) (map[string]bool, error) {
+	patched := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, entry := range manifest.Files {
+		if seen[entry.Library] {
+			continue
+		}
+		seen[entry.Library] = true
+
+		patches, err := loadLibraryPatch(libraryPatchPath(FrontendConfig, entry.Library))
+		if err != nil {
+			return nil, err
+		}
+		for _, patch := range patches {
+			patched[entry.Library+"\x00"+patch.Path] = true
+		}
+	}
+	return patched, nil
+}
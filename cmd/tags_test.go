@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestVendoredFileTags(t *testing.T) {
+	dir := t.TempDir()
+	oldConfig, origFetch := FrontendConfig, fetchTagFile
+	FrontendConfig = filepath.Join(dir, "smartfrontend.yaml")
+	fetched := 0
+	fetchTagFile = func(url string) ([]byte, error) {
+		fetched++
+		return []byte("htmx"), nil
+	}
+	t.Cleanup(func() { FrontendConfig, fetchTagFile = oldConfig, origFetch })
+
+	jqueryURL := "https://cdnjs.cloudflare.com/ajax/libs/jquery/3.7.1/jquery.min.js"
+	htmxURL := "https://cdn.jsdelivr.net/npm/htmx.org@2.0.4/dist/htmx.min.js"
+	manifest := &fileManifest{Files: map[string]manifestEntry{
+		"libs/jquery/jquery.min.js":        {Library: "jquery", File: "jquery.min.js", URL: jqueryURL, Integrity: "sha384-jq"},
+		"libs/jquery/jquery.min.map":       {Library: "jquery", File: "jquery.min.map", Integrity: "sha384-map"},
+		"libs/bootstrap/bootstrap.min.css": {Library: "bootstrap", File: "css/bootstrap.min.css", URL: "https://cdn/b.css?x=1&y=2", Integrity: "sha384-bs"},
+		"libs/bootstrap/bootstrap.min.js":  {Library: "bootstrap", File: "js/bootstrap.min.js", URL: "https://cdn/b.js", Integrity: "sha384-patched"},
+		"libs/htmx.org/htmx.min.js":        {Library: "htmx.org", File: "dist/htmx.min.js", URL: htmxURL},
+	}}
+	os.MkdirAll(filepath.Join(dir, patchesDirName), 0755)
+	os.WriteFile(libraryPatchPath(FrontendConfig, "bootstrap"), []byte(formatPatch([]filePatch{{Path: "js/bootstrap.min.js"}})), 0644)
+
+	// CDN tags use the lockfile hashes; a missing one is fetched and recorded
+	tags, hashed, err := vendoredFileTags(manifest, nil, cspModeCDN, "/")
+	if err != nil {
+		t.Fatalf("vendoredFileTags() error = %v", err)
+	}
+	htmxSRI := frontend_mgr.ComputeSRI([]byte("htmx"))
+	want := []string{
+		`<link rel="stylesheet" href="https://cdn/b.css?x=1&amp;y=2" integrity="sha384-bs" crossorigin="anonymous">`,
+		`<script src="` + htmxURL + `" integrity="` + htmxSRI + `" crossorigin="anonymous"></script>`,
+		`<script src="` + jqueryURL + `" integrity="sha384-jq" crossorigin="anonymous"></script>`,
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("cdn tags =\n%v\nwant\n%v", tags, want)
+	}
+	if hashed != 1 || fetched != 1 || manifest.Files["libs/htmx.org/htmx.min.js"].Integrity != htmxSRI {
+		t.Errorf("hashed %d, fetched %d; want the htmx hash recorded", hashed, fetched)
+	}
+
+	// Local tags load the vendored copies, patched ones included
+	tags, _, err = vendoredFileTags(manifest, []string{"bootstrap"}, cspModeLocal, "/static/")
+	if err != nil {
+		t.Fatalf("vendoredFileTags() error = %v", err)
+	}
+	want = []string{
+		`<link rel="stylesheet" href="/static/libs/bootstrap/bootstrap.min.css" integrity="sha384-bs">`,
+		`<script src="/static/libs/bootstrap/bootstrap.min.js" integrity="sha384-patched"></script>`,
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("local tags =\n%v\nwant\n%v", tags, want)
+	}
+}
+
+func TestVendoredFileTagsStrippedFile(t *testing.T) {
+	served := []byte("console.log(1);\n//# sourceMappingURL=app.min.js.map\n")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(served)
+	}))
+	defer server.Close()
+
+	oldNoPackageCache := syncNoPackageCache
+	syncNoPackageCache = true
+	t.Cleanup(func() {
+		syncNoPackageCache = oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	})
+
+	dir := t.TempDir()
+	oldConfig, origFetch := FrontendConfig, fetchTagFile
+	FrontendConfig = filepath.Join(dir, "smartfrontend.yaml")
+	fetchTagFile = func(url string) ([]byte, error) { return served, nil }
+	t.Cleanup(func() { FrontendConfig, fetchTagFile = oldConfig, origFetch })
+	os.WriteFile(FrontendConfig, []byte("destination: ./libs\nlibraries:\n  app:\n    version: 1.0.0\n    sourcemaps: exclude\n"), 0644)
+
+	// Sync writes the file without its sourcemap comment
+	task := DownloadTask{
+		LibraryName:    "app",
+		FilePath:       "dist/app.min.js",
+		DestPath:       filepath.Join(dir, "libs", "app", "app.min.js"),
+		URL:            server.URL + "/app.min.js",
+		Reason:         "missing",
+		StripSourceMap: true,
+	}
+	result, err := downloadFileWithTask(task)
+	if err != nil {
+		t.Fatalf("downloadFileWithTask() error = %v", err)
+	}
+	manifestPath := manifestPathForConfig(FrontendConfig)
+	manifest, _ := loadManifest(manifestPath)
+	if err := manifest.recordDownloads(manifestPath, []downloadedFile{{task: task, result: result}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// CDN tags carry the hash of the file the CDN serves, not the local copy
+	servedSRI := frontend_mgr.ComputeSRI(served)
+	entry := manifest.Files["libs/app/app.min.js"]
+	if entry.CDNIntegrity != servedSRI || entry.Integrity == servedSRI {
+		t.Fatalf("entry = %+v, want the served hash recorded separately", entry)
+	}
+	tags, hashed, err := vendoredFileTags(manifest, nil, cspModeCDN, "/")
+	want := `<script src="` + task.URL + `" integrity="` + servedSRI + `" crossorigin="anonymous"></script>`
+	if err != nil || hashed != 0 || len(tags) != 1 || tags[0] != want {
+		t.Errorf("cdn tags = %v, %v; want %s", tags, err, want)
+	}
+
+	// Older lockfiles have no served hash for stripped files, so it's fetched
+	entry.CDNIntegrity = ""
+	manifest.Files["libs/app/app.min.js"] = entry
+	tags, hashed, err = vendoredFileTags(manifest, nil, cspModeCDN, "/")
+	if err != nil || hashed != 1 || len(tags) != 1 || tags[0] != want {
+		t.Errorf("cdn tags = %v, %v; want %s", tags, err, want)
+	}
+	if got := manifest.Files["libs/app/app.min.js"]; got.CDNIntegrity != servedSRI || got.Integrity != entry.Integrity {
+		t.Errorf("entry = %+v, want the fetched hash as the served hash", got)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// fetchPackageTarball downloads and verifies a package version's npm
+// tarball (overridable in tests)
+var fetchPackageTarball = frontend_mgr.FetchPackageTarball
+
+// extractPackageTarballs fetches the npm tarball of each library version the
+// tasks download from unpkg or jsDelivr, verifies it against the registry's
+// dist hashes and attaches the tasks' files to them. cdnjs builds its own
+// file set, so its tasks are downloaded file by file as usual.
+func extractPackageTarballs(tasks []DownloadTask) error {
+	tarballs := make(map[string]*frontend_mgr.PackageTarball)
+	missing := 0
+	for i := range tasks {
+		task := &tasks[i]
+		if task.CDN == frontend_config.CDNCdnjs {
+			continue
+		}
+
+		spec := task.LibraryName + "@" + task.Version
+		tarball, ok := tarballs[spec]
+		if !ok {
+			var err error
+			if tarball, err = fetchPackageTarball(task.LibraryName, task.Version); err != nil {
+				if errors.Is(err, frontend_mgr.ErrTarballIntegrity) {
+					return verificationError(err)
+				}
+				return fmt.Errorf("failed to fetch the tarball of %s: %w", spec, err)
+			}
+			tarballs[spec] = tarball
+		}
+
+		data, ok := tarball.Files[task.FilePath]
+		if !ok {
+			missing++
+			continue
+		}
+		task.tarballData = data
+		task.tarballIntegrity = tarball.Integrity
+	}
+
+	if missing > 0 {
+		fmt.Fprintf(os.Stderr, "Downloading %d %s not in the package tarballs from the CDN\n", missing, pluralize(missing, "file", "files"))
+	}
+	return nil
+}
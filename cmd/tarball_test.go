@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestSyncFromVerifiedTarball(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected CDN request for %s", r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer cdn.Close()
+
+	tarball := &frontend_mgr.PackageTarball{
+		Integrity: "sha512-tarball",
+		Files:     map[string][]byte{"dist/htmx.min.js": []byte("htmx")},
+	}
+	var fetchErr error
+	oldFetch, oldTarball, oldJSON, oldNoPackageCache := fetchPackageTarball, syncTarball, syncJSON, syncNoPackageCache
+	fetchPackageTarball = func(name, version string) (*frontend_mgr.PackageTarball, error) {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return tarball, nil
+	}
+	syncTarball, syncJSON, syncNoPackageCache = true, true, true
+	defer func() {
+		fetchPackageTarball, syncTarball, syncJSON, syncNoPackageCache = oldFetch, oldTarball, oldJSON, oldNoPackageCache
+		frontend_mgr.CacheManager.SetPackageCacheEnabled(true)
+	}()
+
+	// Silence the JSON summary written to stdout
+	oldStdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	os.Stdout = devNull
+	defer func() {
+		os.Stdout = oldStdout
+		devNull.Close()
+	}()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	destRoot := filepath.Join(tmpDir, "libs", "htmx.org")
+	destPath := filepath.Join(destRoot, "htmx.min.js")
+	tasks := func() []DownloadTask {
+		return []DownloadTask{{LibraryName: "htmx.org", Version: "2.0.4", CDN: "jsdelivr", FilePath: "dist/htmx.min.js",
+			URL: cdn.URL + "/dist/htmx.min.js", DestPath: destPath, DestRoot: destRoot, Reason: "missing"}}
+	}
+
+	// A tarball failing verification stops the sync before anything is written
+	fetchErr = fmt.Errorf("htmx.org@2.0.4: %w", frontend_mgr.ErrTarballIntegrity)
+	if _, err := downloadAndRecord(configPath, tasks(), io.Discard); exitCode(err) != exitVerification {
+		t.Errorf("downloadAndRecord() error = %v, want a verification failure", err)
+	}
+	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+		t.Errorf("nothing should be written from a tampered tarball")
+	}
+
+	// Files come from the verified tarball and the lockfile records its hash
+	fetchErr = nil
+	if _, err := downloadAndRecord(configPath, tasks(), io.Discard); err != nil {
+		t.Fatalf("downloadAndRecord() error = %v", err)
+	}
+	if data, _ := os.ReadFile(destPath); string(data) != "htmx" {
+		t.Errorf("file = %q, want the tarball's contents", data)
+	}
+	entry, err := lookupProvenance(configPath, destPath)
+	if err != nil {
+		t.Fatalf("expected provenance to be recorded: %v", err)
+	}
+	if entry.TarballIntegrity != "sha512-tarball" {
+		t.Errorf("TarballIntegrity = %q, want the tarball's hash", entry.TarballIntegrity)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// noColor disables styled output when set via --no-color
+var noColor bool
+
+// isTerminal reports whether the given file is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// interactiveTerminal reports whether both stdin and stdout are attached to
+// a terminal, as the TUIs need (overridable in tests)
+var interactiveTerminal = func() bool {
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// requireTerminal fails fast when a command that only works interactively
+// runs without a terminal, e.g. in CI or with piped input
+func requireTerminal(command, alternative string) error {
+	if interactiveTerminal() {
+		return nil
+	}
+	return fmt.Errorf("%s needs an interactive terminal (stdin and stdout must not be redirected); %s", command, alternative)
+}
+
+// interactiveAvailable reports whether an --interactive flag can be honoured.
+// Without a terminal it warns and the caller falls back to non-interactive mode.
+func interactiveAvailable() bool {
+	if interactiveTerminal() {
+		return true
+	}
+	fmt.Fprintln(os.Stderr, "Warning: --interactive needs a terminal; continuing without it")
+	return false
+}
+
+// colorEnabled reports whether styled output should be used.
+// Styling is disabled by --no-color, a non-empty NO_COLOR environment
+// variable (https://no-color.org), or when stdout is not a terminal,
+// unless the color setting is "always" or "never".
+func colorEnabled() bool {
+	if noColor {
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch settingsColorMode() {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// initColor applies the terminal color settings to all lipgloss styles
+func initColor() {
+	if !colorEnabled() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else if settingsColorMode() == colorAlways && !isTerminal(os.Stdout) {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	origNoColor := noColor
+	defer func() { noColor = origNoColor }()
+
+	t.Run("no-color flag", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		noColor = true
+		if colorEnabled() {
+			t.Error("Expected color to be disabled by --no-color")
+		}
+	})
+
+	t.Run("NO_COLOR environment variable", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		noColor = false
+		if colorEnabled() {
+			t.Error("Expected color to be disabled by NO_COLOR")
+		}
+	})
+
+	t.Run("follows stdout terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		noColor = false
+		if got, want := colorEnabled(), isTerminal(os.Stdout); got != want {
+			t.Errorf("colorEnabled() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestIsTerminalWithRegularFile(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	defer f.Close()
+
+	if isTerminal(f) {
+		t.Error("Expected regular file not to be a terminal")
+	}
+}
+
+func TestRequireTerminal(t *testing.T) {
+	origTerminal := interactiveTerminal
+	defer func() { interactiveTerminal = origTerminal }()
+
+	interactiveTerminal = func() bool { return true }
+	if err := requireTerminal("pkgmgr", "use 'smfaman config set' instead"); err != nil {
+		t.Errorf("requireTerminal() with a terminal = %v, want nil", err)
+	}
+	if !interactiveAvailable() {
+		t.Error("interactiveAvailable() = false with a terminal")
+	}
+
+	interactiveTerminal = func() bool { return false }
+	err := requireTerminal("pkgmgr", "use 'smfaman config set' instead")
+	if err == nil || !strings.Contains(err.Error(), "pkgmgr needs an interactive terminal") || !strings.Contains(err.Error(), "config set") {
+		t.Errorf("requireTerminal() without a terminal = %v, want a clear error", err)
+	}
+	if interactiveAvailable() {
+		t.Error("interactiveAvailable() = true without a terminal")
+	}
+}
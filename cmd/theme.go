@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+	"nexus-sds.com/smfaman/pkgs/tui"
+)
+
+// settingTheme selects the TUI color theme
+const settingTheme = "theme"
+
+// Theme names for the theme setting and --theme
+const (
+	themeDefault      = "default"
+	themeLight        = "light"
+	themeHighContrast = "high-contrast"
+	themeMonochrome   = "monochrome"
+)
+
+// theme is the set of colors the TUIs and styled output are drawn with
+type theme struct {
+	Primary   lipgloss.TerminalColor // Titles, labels and focused fields
+	Accent    lipgloss.TerminalColor // Headers, values and progress
+	Selected  lipgloss.TerminalColor // The selected list item
+	Secondary lipgloss.TerminalColor // Table headers and borders
+	Text      lipgloss.TerminalColor // Detail values
+	Muted     lipgloss.TerminalColor // Help text, descriptions and blurred fields
+	Success   lipgloss.TerminalColor
+	Warning   lipgloss.TerminalColor
+	Error     lipgloss.TerminalColor
+	Added     lipgloss.TerminalColor // Added lines in diffs
+	Removed   lipgloss.TerminalColor // Removed lines in diffs
+}
+
+// themes are the built-in themes. high-contrast avoids telling states apart
+// by red and green alone (blue and orange instead), and monochrome relies
+// on bold text and markers only.
+var themes = map[string]theme{
+	themeDefault: {
+		Primary:   lipgloss.Color("205"),
+		Accent:    lipgloss.Color("86"),
+		Selected:  lipgloss.Color("170"),
+		Secondary: lipgloss.Color("99"),
+		Text:      lipgloss.Color("252"),
+		Muted:     lipgloss.Color("240"),
+		Success:   lipgloss.Color("42"),
+		Warning:   lipgloss.Color("214"),
+		Error:     lipgloss.Color("196"),
+		Added:     lipgloss.Color("2"),
+		Removed:   lipgloss.Color("1"),
+	},
+	themeLight: {
+		Primary:   lipgloss.Color("125"),
+		Accent:    lipgloss.Color("24"),
+		Selected:  lipgloss.Color("90"),
+		Secondary: lipgloss.Color("55"),
+		Text:      lipgloss.Color("235"),
+		Muted:     lipgloss.Color("244"),
+		Success:   lipgloss.Color("28"),
+		Warning:   lipgloss.Color("136"),
+		Error:     lipgloss.Color("160"),
+		Added:     lipgloss.Color("28"),
+		Removed:   lipgloss.Color("160"),
+	},
+	themeHighContrast: {
+		Primary:   lipgloss.Color("226"),
+		Accent:    lipgloss.Color("51"),
+		Selected:  lipgloss.Color("214"),
+		Secondary: lipgloss.Color("45"),
+		Text:      lipgloss.Color("15"),
+		Muted:     lipgloss.Color("250"),
+		Success:   lipgloss.Color("33"),
+		Warning:   lipgloss.Color("226"),
+		Error:     lipgloss.Color("208"),
+		Added:     lipgloss.Color("33"),
+		Removed:   lipgloss.Color("208"),
+	},
+	themeMonochrome: {
+		Primary:   lipgloss.NoColor{},
+		Accent:    lipgloss.NoColor{},
+		Selected:  lipgloss.NoColor{},
+		Secondary: lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Muted:     lipgloss.NoColor{},
+		Success:   lipgloss.NoColor{},
+		Warning:   lipgloss.NoColor{},
+		Error:     lipgloss.NoColor{},
+		Added:     lipgloss.NoColor{},
+		Removed:   lipgloss.NoColor{},
+	},
+}
+
+// themeNames lists the built-in themes in the order they are documented
+var themeNames = []string{themeDefault, themeLight, themeHighContrast, themeMonochrome}
+
+func init() {
+	applyTheme(themes[themeDefault])
+}
+
+// settingsTheme returns the theme setting (or --theme), or default when it
+// names no built-in theme
+func settingsTheme() string {
+	name := strings.ToLower(viper.GetString(settingTheme))
+	if _, ok := themes[name]; ok {
+		return name
+	}
+	return themeDefault
+}
+
+// invalidThemeWarning returns a warning for a theme setting that names no
+// built-in theme, or ""
+func invalidThemeWarning() string {
+	name := viper.GetString(settingTheme)
+	if name == "" || settingsTheme() != themeDefault || strings.EqualFold(name, themeDefault) {
+		return ""
+	}
+	return fmt.Sprintf("invalid theme setting %q (want %s), using %s", name, strings.Join(themeNames, ", "), themeDefault)
+}
+
+// initTheme applies the selected theme once the settings have been read
+func initTheme() {
+	applyTheme(themes[settingsTheme()])
+}
+
+// applyTheme sets the colors of every TUI and styled output from t. Only the
+// colors change; layout (padding, margins, bold) stays with each style.
+func applyTheme(t theme) {
+	// init
+	focusedStyle = focusedStyle.Foreground(t.Primary)
+	blurredStyle = blurredStyle.Foreground(t.Muted)
+	cursorStyle = cursorStyle.Foreground(t.Primary)
+	helpStyle = helpStyle.Foreground(t.Muted)
+	cursorModeHelpStyle = cursorModeHelpStyle.Foreground(t.Muted)
+	focusedButton = focusedStyle.Render("[ Submit ]")
+	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Submit"))
+	titleStyle = titleStyle.Foreground(t.Accent)
+	successStyle = successStyle.Foreground(t.Success)
+	errorStyle = errorStyle.Foreground(t.Error)
+
+	// pkgmgr
+	pkgmgrTitleStyle = pkgmgrTitleStyle.Foreground(t.Primary)
+	pkgmgrSelectedItemStyle = pkgmgrSelectedItemStyle.Foreground(t.Selected)
+	pkgmgrHelpStyle = pkgmgrHelpStyle.Foreground(t.Muted)
+	pkgmgrHeaderStyle = pkgmgrHeaderStyle.Foreground(t.Accent)
+	pkgmgrLabelStyle = pkgmgrLabelStyle.Foreground(t.Primary)
+	pkgmgrValueStyle = pkgmgrValueStyle.Foreground(t.Accent)
+	pkgmgrDescriptionStyle = pkgmgrDescriptionStyle.Foreground(t.Muted)
+	pkgmgrErrorStyle = pkgmgrErrorStyle.Foreground(t.Error)
+
+	// version selector
+	versionTitleStyle = versionTitleStyle.Foreground(t.Primary)
+	versionSelectedItemStyle = versionSelectedItemStyle.Foreground(t.Selected)
+	versionLatestItemStyle = versionLatestItemStyle.Foreground(t.Success)
+
+	// search
+	searchTitleStyle = searchTitleStyle.Foreground(t.Primary)
+	searchTableHeaderStyle = searchTableHeaderStyle.Foreground(t.Secondary)
+	searchSelectedItemStyle = searchSelectedItemStyle.Foreground(t.Selected)
+	detailTitleStyle = detailTitleStyle.Foreground(t.Primary)
+	detailLabelStyle = detailLabelStyle.Foreground(t.Secondary)
+	detailValueStyle = detailValueStyle.Foreground(t.Text)
+	detailBoxStyle = detailBoxStyle.BorderForeground(t.Secondary)
+	detailTabStyle = detailTabStyle.Foreground(t.Muted)
+	detailActiveTabStyle = detailActiveTabStyle.Foreground(t.Primary)
+	inputPromptStyle = inputPromptStyle.Foreground(t.Primary)
+
+	// package picker
+	tui.TitleStyle = tui.TitleStyle.Foreground(t.Primary)
+	tui.HeaderStyle = tui.HeaderStyle.Foreground(t.Secondary)
+	tui.SelectedItemStyle = tui.SelectedItemStyle.Foreground(t.Selected)
+	tui.DetailBoxStyle = tui.DetailBoxStyle.BorderForeground(t.Secondary)
+
+	// sync
+	syncHeaderStyle = syncHeaderStyle.Foreground(t.Primary)
+	syncBarStyle = syncBarStyle.Foreground(t.Accent)
+
+	// help overlay
+	helpOverlayTitleStyle = helpOverlayTitleStyle.Foreground(t.Primary)
+	helpOverlaySectionStyle = helpOverlaySectionStyle.Foreground(t.Secondary)
+	helpOverlayKeyStyle = helpOverlayKeyStyle.Foreground(t.Accent)
+	helpOverlayDescStyle = helpOverlayDescStyle.Foreground(t.Text)
+
+	// config diffs
+	diffAddedStyle = diffAddedStyle.Foreground(t.Added)
+	diffRemovedStyle = diffRemovedStyle.Foreground(t.Removed)
+
+	// health
+	healthGreenStyle = healthGreenStyle.Foreground(t.Success)
+	healthYellowStyle = healthYellowStyle.Foreground(t.Warning)
+	healthRedStyle = healthRedStyle.Foreground(t.Error)
+	healthUnknownStyle = healthUnknownStyle.Foreground(t.Muted)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSettingsTheme(t *testing.T) {
+	useSettingsEnv(t, map[string]string{"THEME": "High-Contrast"})
+	if got := settingsTheme(); got != themeHighContrast {
+		t.Errorf("settingsTheme() = %q, want %s", got, themeHighContrast)
+	}
+	if warning := invalidThemeWarning(); warning != "" {
+		t.Errorf("unexpected warning %q", warning)
+	}
+
+	useSettingsEnv(t, map[string]string{"THEME": "solarized"})
+	if got := settingsTheme(); got != themeDefault {
+		t.Errorf("settingsTheme() = %q, want the default fallback", got)
+	}
+	if warning := invalidThemeWarning(); !strings.Contains(warning, "solarized") || !strings.Contains(warning, themeMonochrome) {
+		t.Errorf("invalidThemeWarning() = %q, want it to name the value and the themes", warning)
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	defer applyTheme(themes[themeDefault])
+
+	for _, name := range themeNames {
+		if _, ok := themes[name]; !ok {
+			t.Errorf("theme %s is documented but not defined", name)
+		}
+	}
+
+	applyTheme(themes[themeHighContrast])
+	if got := pkgmgrSelectedItemStyle.GetForeground(); got != lipgloss.Color("214") {
+		t.Errorf("selected item color = %v, want the high-contrast color", got)
+	}
+	if got := diffRemovedStyle.GetForeground(); got != lipgloss.Color("208") {
+		t.Errorf("removed line color = %v, want orange rather than red", got)
+	}
+
+	applyTheme(themes[themeMonochrome])
+	for name, style := range map[string]lipgloss.Style{
+		"focused": focusedStyle, "pkgmgr title": pkgmgrTitleStyle, "search header": searchTableHeaderStyle,
+		"sync bar": syncBarStyle, "diff added": diffAddedStyle,
+	} {
+		if _, ok := style.GetForeground().(lipgloss.NoColor); !ok {
+			t.Errorf("%s style has color %v in the monochrome theme", name, style.GetForeground())
+		}
+	}
+
+	// Layout is kept when the colors change
+	if pkgmgrTitleStyle.GetMarginLeft() != 2 || !pkgmgrTitleStyle.GetBold() {
+		t.Error("applyTheme changed the layout of the pkgmgr title")
+	}
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// trashDirName is the folder next to the config that holds trashed destinations
+	trashDirName = ".smfaman-trash"
+
+	// trashIndexName describes the contents of one trash folder
+	trashIndexName = "trash.json"
+
+	// trashTimeFormat names trash folders, so they sort by time
+	trashTimeFormat = "20060102-150405"
+
+	// restoreLatest is the --restore value for the most recent trash folder
+	restoreLatest = "latest"
+)
+
+// trashIndex records what a clean moved into one trash folder
+type trashIndex struct {
+	Config    string       `json:"config"`
+	TrashedAt time.Time    `json:"trashed_at"`
+	Entries   []trashEntry `json:"entries"`
+}
+
+// trashEntry is a destination folder moved to the trash
+type trashEntry struct {
+	Library string `json:"library"`
+	Path    string `json:"path"`   // Original folder, relative to the config
+	Folder  string `json:"folder"` // Folder inside the trash folder
+
+	// Lockfile holds the folder's lockfile entries, put back on restore
+	Lockfile map[string]manifestEntry `json:"lockfile,omitempty"`
+}
+
+// trashRoot returns the trash directory for a config file
+func trashRoot(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), trashDirName)
+}
+
+// newTrashFolder creates a timestamped folder in the config's trash
+func newTrashFolder(configPath string, now time.Time) (string, error) {
+	root := trashRoot(configPath)
+	name := now.Format(trashTimeFormat)
+	dir := filepath.Join(root, name)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			break
+		}
+		dir = filepath.Join(root, fmt.Sprintf("%s-%d", name, n))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash folder: %w", err)
+	}
+	return dir, nil
+}
+
+// moveToTrash moves a library's destination folder into a trash folder,
+// keeping its lockfile entries so a restore can put them back. Files matched
+// by the ignore file stay where they are. It returns the moved paths.
+func moveToTrash(configPath, trashDir, libName, destPath string, ignore *ignoreMatcher) (trashEntry, []string, error) {
+	entry := trashEntry{Library: libName, Folder: filepath.ToSlash(libName)}
+
+	var err error
+	if entry.Path, err = manifestKey(configPath, destPath); err != nil {
+		return entry, nil, err
+	}
+	target := filepath.Join(trashDir, filepath.FromSlash(entry.Folder))
+
+	kept, err := ignore.ignoredFilesUnder(destPath)
+	if err != nil {
+		return entry, nil, err
+	}
+	moved := []string{destPath}
+	if len(kept) > 0 {
+		if moved, err = unignoredFiles(destPath, ignore); err != nil {
+			return entry, nil, err
+		}
+	}
+
+	entry.Lockfile = make(map[string]manifestEntry)
+	for _, path := range moved {
+		entries, err := manifestEntriesUnder(configPath, path)
+		if err != nil {
+			return entry, nil, err
+		}
+		for key, e := range entries {
+			entry.Lockfile[key] = e
+		}
+	}
+	if len(entry.Lockfile) == 0 {
+		entry.Lockfile = nil
+	}
+
+	if len(kept) == 0 {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return entry, nil, err
+		}
+		if err := os.Rename(destPath, target); err != nil {
+			return entry, nil, err
+		}
+		return entry, moved, nil
+	}
+
+	for _, path := range moved {
+		rel, err := filepath.Rel(destPath, path)
+		if err != nil {
+			return entry, nil, err
+		}
+		if err := moveFile(path, filepath.Join(target, rel)); err != nil {
+			return entry, nil, err
+		}
+	}
+	removeEmptyDirs(destPath)
+	return entry, moved, nil
+}
+
+// moveFile renames a file, creating the target's parent directories
+func moveFile(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+// manifestEntriesUnder returns the lockfile entries for files inside dir
+func manifestEntriesUnder(configPath, dir string) (map[string]manifestEntry, error) {
+	manifestPath := manifestPathForConfig(configPath)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := manifestKey(manifestPath, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]manifestEntry)
+	for key, entry := range manifest.Files {
+		if key == prefix || strings.HasPrefix(key, prefix+"/") {
+			entries[key] = entry
+		}
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return entries, nil
+}
+
+// writeTrashIndex records the trashed folders in a trash folder
+func writeTrashIndex(trashDir string, index trashIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(trashDir, trashIndexName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write trash index: %w", err)
+	}
+	return nil
+}
+
+// readTrashIndex reads the index of a trash folder
+func readTrashIndex(trashDir string) (*trashIndex, error) {
+	data, err := os.ReadFile(filepath.Join(trashDir, trashIndexName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash index: %w", err)
+	}
+	var index trashIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse trash index: %w", err)
+	}
+	return &index, nil
+}
+
+// listTrashFolders returns the names of the config's trash folders, oldest first
+func listTrashFolders(configPath string) ([]string, error) {
+	entries, err := os.ReadDir(trashRoot(configPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// restoreFromTrash moves the folders in a trash folder (a name from
+// listTrashFolders, or restoreLatest) back to their destinations and puts
+// their lockfile entries back. Nothing is moved if a destination exists.
+func restoreFromTrash(configPath, name string) error {
+	names, err := listTrashFolders(configPath)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("the trash is empty (%s)", trashRoot(configPath))
+	}
+	if name == restoreLatest {
+		name = names[len(names)-1]
+	} else if !slices.Contains(names, name) {
+		return fmt.Errorf("no trash folder %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	trashDir := filepath.Join(trashRoot(configPath), name)
+	index, err := readTrashIndex(trashDir)
+	if err != nil {
+		return err
+	}
+
+	baseDir := filepath.Dir(configPath)
+	for _, entry := range index.Entries {
+		target := filepath.Join(baseDir, filepath.FromSlash(entry.Path))
+		if conflict := restoreConflict(filepath.Join(trashDir, filepath.FromSlash(entry.Folder)), target); conflict != "" {
+			return fmt.Errorf("%s already exists; remove it before restoring %s", conflict, entry.Library)
+		}
+	}
+
+	manifestPath := manifestPathForConfig(configPath)
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	restoredLockfile := false
+	for _, entry := range index.Entries {
+		target := filepath.Join(baseDir, filepath.FromSlash(entry.Path))
+		if err := restoreFolder(filepath.Join(trashDir, filepath.FromSlash(entry.Folder)), target); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", entry.Library, err)
+		}
+		for key, e := range entry.Lockfile {
+			manifest.Files[key] = e
+			restoredLockfile = true
+		}
+		fmt.Printf("✓ Restored %s (%s)\n", entry.Library, target)
+	}
+
+	if restoredLockfile && lockfileEnabled(configPath) {
+		if err := saveManifest(manifestPath, manifest); err != nil {
+			return err
+		}
+	}
+
+	if err := os.RemoveAll(trashDir); err != nil {
+		return fmt.Errorf("failed to remove trash folder: %w", err)
+	}
+
+	fmt.Printf("\nRestored %d director%s from %s (trashed %s)\n",
+		len(index.Entries), pluralize(len(index.Entries), "y", "ies"), name, index.TrashedAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// restoreConflict returns the first path a restore of folder to target
+// would overwrite, or "" if there is none. Folders cleaned around ignored
+// files still exist and are merged file by file.
+func restoreConflict(folder, target string) string {
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		return ""
+	}
+
+	conflict := ""
+	filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(folder, path)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(target, rel)); err == nil {
+			conflict = filepath.Join(target, rel)
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return conflict
+}
+
+// restoreFolder moves a trashed folder back to target, merging its files
+// into target if it exists
+func restoreFolder(folder, target string) error {
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		return moveFile(folder, target)
+	}
+
+	return filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(folder, path)
+		if err != nil {
+			return err
+		}
+		return moveFile(path, filepath.Join(target, rel))
+	})
+}
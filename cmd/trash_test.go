@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestCleanTrashAndRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := filepath.Join(tmpDir, "test-config.yaml")
+	config := frontend_config.FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+		ProjectName: "test-project",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"jquery":       {Version: "3.7.1"},
+			"@scope/icons": {Version: "1.0.0"},
+		},
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	jqueryFile := filepath.Join(tmpDir, "libs", "jquery", "dist", "jquery.js")
+	iconsFile := filepath.Join(tmpDir, "libs", "@scope", "icons", "icons.css")
+	writeTestFile(t, jqueryFile, "jquery")
+	writeTestFile(t, iconsFile, "icons")
+
+	manifestPath := manifestPathForConfig(configPath)
+	manifest := &fileManifest{
+		FormatVersion: manifestFormatVersion,
+		Files: map[string]manifestEntry{
+			"libs/jquery/dist/jquery.js": {Library: "jquery", Version: "3.7.1"},
+		},
+	}
+	if err := saveManifest(manifestPath, manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	oldConfig, oldForce, oldIgnoreMarker, oldTrash := FrontendConfig, cleanForce, cleanIgnoreMarker, cleanTrash
+	FrontendConfig, cleanForce, cleanIgnoreMarker, cleanTrash = configPath, true, true, true
+	defer func() {
+		FrontendConfig, cleanForce, cleanIgnoreMarker, cleanTrash = oldConfig, oldForce, oldIgnoreMarker, oldTrash
+	}()
+
+	if err := runClean(); err != nil {
+		t.Fatalf("runClean failed: %v", err)
+	}
+
+	if _, err := os.Stat(jqueryFile); !os.IsNotExist(err) {
+		t.Fatalf("jquery should have been moved to the trash")
+	}
+	names, err := listTrashFolders(configPath)
+	if err != nil || len(names) != 1 {
+		t.Fatalf("expected one trash folder, got %v (%v)", names, err)
+	}
+	trashed := filepath.Join(trashRoot(configPath), names[0], "@scope", "icons", "icons.css")
+	if _, err := os.Stat(trashed); err != nil {
+		t.Errorf("expected the scoped library in the trash: %v", err)
+	}
+	if m, _ := loadManifest(manifestPath); len(m.Files) != 0 {
+		t.Errorf("lockfile entries should be pruned, got %v", m.Files)
+	}
+
+	if err := restoreFromTrash(configPath, restoreLatest); err != nil {
+		t.Fatalf("restoreFromTrash failed: %v", err)
+	}
+
+	for _, path := range []string{jqueryFile, iconsFile} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be restored: %v", path, err)
+		}
+	}
+	if m, _ := loadManifest(manifestPath); m.Files["libs/jquery/dist/jquery.js"].Version != "3.7.1" {
+		t.Errorf("lockfile entries should be restored, got %v", m.Files)
+	}
+	if names, _ := listTrashFolders(configPath); len(names) != 0 {
+		t.Errorf("restored trash folder should be removed, got %v", names)
+	}
+}
+
+func TestRestoreFromTrashErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+
+	if err := restoreFromTrash(configPath, restoreLatest); err == nil {
+		t.Errorf("expected an error for an empty trash")
+	}
+
+	// A destination that exists again is never overwritten
+	trashDir, err := newTrashFolder(configPath, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(trashDir, "jquery", "jquery.js"), "old")
+	writeTestFile(t, filepath.Join(tmpDir, "libs", "jquery", "jquery.js"), "new")
+	index := trashIndex{Entries: []trashEntry{{Library: "jquery", Path: "libs/jquery", Folder: "jquery"}}}
+	if err := writeTrashIndex(trashDir, index); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restoreFromTrash(configPath, "20260102-000000"); err == nil {
+		t.Errorf("expected an error for an unknown trash folder")
+	}
+	if err := restoreFromTrash(configPath, "20260102-030405"); err == nil {
+		t.Errorf("expected an error when the destination exists")
+	}
+	if _, err := os.Stat(filepath.Join(trashDir, "jquery", "jquery.js")); err != nil {
+		t.Errorf("trashed files should be kept after a failed restore: %v", err)
+	}
+}
+
+func TestTrashKeepsIgnoredFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "smartfrontend.yaml")
+	writeTestFile(t, filepath.Join(tmpDir, ignoreFileName), "*.patched.js\n")
+
+	destPath := filepath.Join(tmpDir, "libs", "jquery")
+	writeTestFile(t, filepath.Join(destPath, "jquery.patched.js"), "patched")
+	writeTestFile(t, filepath.Join(destPath, "dist", "jquery.js"), "jquery")
+
+	ignore, err := loadIgnoreFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashDir, err := newTrashFolder(configPath, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, moved, err := moveToTrash(configPath, trashDir, "jquery", destPath, ignore)
+	if err != nil {
+		t.Fatalf("moveToTrash failed: %v", err)
+	}
+	if len(moved) != 1 {
+		t.Errorf("expected one moved file, got %v", moved)
+	}
+	if _, err := os.Stat(filepath.Join(destPath, "jquery.patched.js")); err != nil {
+		t.Errorf("ignored file should stay in place: %v", err)
+	}
+	if err := writeTrashIndex(trashDir, trashIndex{Entries: []trashEntry{entry}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The folder still exists, so the restore merges into it
+	if err := restoreFromTrash(configPath, restoreLatest); err != nil {
+		t.Fatalf("restoreFromTrash failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destPath, "dist", "jquery.js")); err != nil {
+		t.Errorf("expected the trashed file to be restored: %v", err)
+	}
+}
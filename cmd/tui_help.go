@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpKey toggles the help overlay in every TUI. F1 works too, also while
+// typing in a text field, where '?' is just a character.
+var helpKey = key.NewBinding(
+	key.WithKeys("?", "f1"),
+	key.WithHelp("?/f1", "toggle this help"),
+)
+
+// closeHelpKey also closes the help overlay
+var closeHelpKey = key.NewBinding(
+	key.WithKeys("esc", "q"),
+	key.WithHelp("esc/q", "close this help"),
+)
+
+// Styles of the help overlay (colored by the theme)
+var (
+	helpOverlayTitleStyle   = lipgloss.NewStyle().Bold(true).MarginLeft(2)
+	helpOverlaySectionStyle = lipgloss.NewStyle().Bold(true).MarginLeft(2)
+	helpOverlayKeyStyle     = lipgloss.NewStyle().MarginLeft(4)
+	helpOverlayDescStyle    = lipgloss.NewStyle()
+)
+
+// helpSection is a group of keybindings in the help overlay
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// helpRequested reports whether msg opens the help overlay. While typing in
+// a text field only F1 does, so '?' can still be typed.
+func helpRequested(msg tea.KeyMsg, typing bool) bool {
+	if typing {
+		return msg.String() == "f1"
+	}
+	return key.Matches(msg, helpKey)
+}
+
+// helpClosed reports whether msg closes an open help overlay
+func helpClosed(msg tea.KeyMsg) bool {
+	return key.Matches(msg, helpKey, closeHelpKey)
+}
+
+// generalHelpSection lists the help keys themselves and any other keys that
+// work everywhere in a TUI
+func generalHelpSection(bindings ...key.Binding) helpSection {
+	return helpSection{title: "General", bindings: append(bindings, helpKey, closeHelpKey)}
+}
+
+// listHelpSection lists the navigation and filter keys of a bubbles list,
+// taken from the list's own key map
+func listHelpSection(l list.Model) helpSection {
+	keys := l.KeyMap
+	return helpSection{
+		title: "Navigation",
+		bindings: []key.Binding{
+			keys.CursorUp, keys.CursorDown, keys.PrevPage, keys.NextPage,
+			keys.GoToStart, keys.GoToEnd, keys.Filter, keys.ClearFilter,
+		},
+	}
+}
+
+// useHelpOverlay replaces a list's own '?' full-help toggle with the help
+// overlay and mentions the overlay in the list's short help
+func useHelpOverlay(l *list.Model) {
+	l.KeyMap.ShowFullHelp.Unbind()
+	l.KeyMap.CloseFullHelp.Unbind()
+
+	shortHelp := l.AdditionalShortHelpKeys
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		var bindings []key.Binding
+		if shortHelp != nil {
+			bindings = shortHelp()
+		}
+		return append(bindings, key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")))
+	}
+}
+
+// renderHelpOverlay renders the full-screen help for the current view of a
+// TUI, one line per enabled keybinding
+func renderHelpOverlay(title string, sections ...helpSection) string {
+	keyWidth := 0
+	for _, section := range sections {
+		for _, binding := range section.bindings {
+			if binding.Enabled() {
+				keyWidth = max(keyWidth, lipgloss.Width(binding.Help().Key))
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(helpOverlayTitleStyle.Render("Keyboard shortcuts: "+title) + "\n")
+
+	for _, section := range sections {
+		var lines []string
+		for _, binding := range section.bindings {
+			if !binding.Enabled() {
+				continue
+			}
+			help := binding.Help()
+			lines = append(lines, helpOverlayKeyStyle.Width(keyWidth).Render(help.Key)+"  "+helpOverlayDescStyle.Render(help.Desc))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		b.WriteString("\n" + helpOverlaySectionStyle.Render(section.title) + "\n")
+		b.WriteString(strings.Join(lines, "\n") + "\n")
+	}
+
+	return b.String()
+}
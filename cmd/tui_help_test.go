@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+var (
+	questionKey = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}}
+	f1Key       = tea.KeyMsg{Type: tea.KeyF1}
+	escKey      = tea.KeyMsg{Type: tea.KeyEsc}
+)
+
+func TestRenderHelpOverlay(t *testing.T) {
+	disabled := key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "hidden action"), key.WithDisabled())
+	view := renderHelpOverlay("demo",
+		helpSection{title: "Actions", bindings: []key.Binding{versionSelectorKeys.Select, disabled}},
+		helpSection{title: "Empty", bindings: []key.Binding{disabled}},
+		generalHelpSection(),
+	)
+
+	for _, want := range []string{"demo", "Actions", "enter", "select", "General", "toggle this help"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("overlay is missing %q:\n%s", want, view)
+		}
+	}
+	for _, unwanted := range []string{"hidden action", "Empty"} {
+		if strings.Contains(view, unwanted) {
+			t.Errorf("overlay shows %q from disabled bindings:\n%s", unwanted, view)
+		}
+	}
+}
+
+func TestPkgverHelpOverlay(t *testing.T) {
+	var model tea.Model = newVersionSelector("jquery", "unpkg", "3.7.1", []string{"3.7.1", "3.7.0"}, versionSelectorOptions{})
+	send := func(msg tea.Msg) versionSelectorModel {
+		t.Helper()
+		model, _ = model.Update(msg)
+		return model.(versionSelectorModel)
+	}
+
+	m := send(questionKey)
+	if !m.showHelp {
+		t.Fatal("? didn't open the help overlay")
+	}
+	view := m.View()
+	for _, want := range []string{"version selector", "select", "cancel", "filter"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("help overlay is missing %q:\n%s", want, view)
+		}
+	}
+
+	// Esc closes the overlay instead of cancelling the selector
+	m = send(escKey)
+	if m.showHelp || m.quitting {
+		t.Errorf("esc in the overlay: showHelp=%v quitting=%v, want the overlay closed only", m.showHelp, m.quitting)
+	}
+
+	// While typing a filter, '?' is part of the filter
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = send(questionKey)
+	if m.showHelp {
+		t.Error("? opened the help overlay while typing a filter")
+	}
+}
+
+func TestPkgmgrHelpOverlay(t *testing.T) {
+	config := &frontend_config.FrontendConfig{Libraries: map[string]frontend_config.LibraryConfig{}}
+	var model tea.Model = newPkgmgrModel(config, "frontend.yaml")
+	send := func(msg tea.Msg) pkgmgrModel {
+		t.Helper()
+		model, _ = model.Update(msg)
+		return model.(pkgmgrModel)
+	}
+
+	m := send(questionKey)
+	if !m.showHelp || !strings.Contains(m.View(), "global settings") {
+		t.Fatalf("? didn't open the library list help:\n%s", m.View())
+	}
+	send(questionKey)
+
+	// In the add form '?' is typed into the name field and F1 opens help
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = send(questionKey)
+	if m.showHelp || m.editInputs[0].Value() != "?" {
+		t.Errorf("? in the name field: showHelp=%v value=%q, want it typed", m.showHelp, m.editInputs[0].Value())
+	}
+	m = send(f1Key)
+	if !m.showHelp || !strings.Contains(m.View(), "pick a version") {
+		t.Errorf("F1 didn't open the add form help:\n%s", m.View())
+	}
+	m = send(f1Key)
+	if m.showHelp || m.view != viewAddLibrary {
+		t.Errorf("F1 didn't close the help back to the add form (view %d)", m.view)
+	}
+}
+
+func TestSearchHelpOverlay(t *testing.T) {
+	useSearchHistoryDir(t)
+	var model tea.Model = newSearchTUIModel("")
+	send := func(msg tea.Msg) searchTUIModel {
+		t.Helper()
+		model, _ = model.Update(msg)
+		return model.(searchTUIModel)
+	}
+
+	m := send(questionKey)
+	if m.showHelp || m.queryInput.Value() != "?" {
+		t.Errorf("? in the query prompt: showHelp=%v value=%q, want it typed", m.showHelp, m.queryInput.Value())
+	}
+	m = send(f1Key)
+	if !m.showHelp || !strings.Contains(m.View(), "toggle exact-name lookup") {
+		t.Errorf("F1 didn't open the query help:\n%s", m.View())
+	}
+	m = send(escKey)
+	if m.showHelp || m.quitting {
+		t.Errorf("esc in the overlay: showHelp=%v quitting=%v, want the overlay closed only", m.showHelp, m.quitting)
+	}
+}
+
+func TestSyncHelpOverlay(t *testing.T) {
+	var model tea.Model = newSyncModel([]DownloadTask{{LibraryName: "jquery", Version: "3.7.1", FilePath: "dist/jquery.js"}})
+	model, _ = model.Update(questionKey)
+	m := model.(syncModel)
+	if !m.showHelp || !strings.Contains(m.View(), "cancel the sync") {
+		t.Fatalf("? didn't open the sync help:\n%s", m.View())
+	}
+
+	model, _ = model.Update(allCompleteMsg{})
+	if model.(syncModel).showHelp {
+		t.Error("the help overlay stayed open after the sync finished")
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// listViewTop is the screen row the list views start on, below the blank
+// line each of them begins with
+const listViewTop = 1
+
+// updateListMouse scrolls l with the mouse wheel and selects the row under a
+// left click. top is the screen row the list's view starts on, d the
+// list's delegate. Mouse events are ignored while a filter is being typed.
+func updateListMouse(l *list.Model, d list.ItemDelegate, top int, msg tea.MouseMsg) {
+	if l.FilterState() == list.Filtering {
+		return
+	}
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		l.CursorUp()
+	case msg.Button == tea.MouseButtonWheelDown:
+		l.CursorDown()
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		if index, ok := listItemAt(*l, d, top, msg.Y); ok {
+			l.Select(index)
+		}
+	}
+}
+
+// listItemAt returns the index (among the visible items) of the item shown
+// on screen row y, or false when the row holds no item
+func listItemAt(l list.Model, d list.ItemDelegate, top, y int) (int, bool) {
+	row := y - top - listHeaderHeight(l)
+	rowsPerItem := d.Height() + d.Spacing()
+	if row < 0 || rowsPerItem <= 0 || row%rowsPerItem >= d.Height() {
+		return 0, false
+	}
+
+	start, end := l.Paginator.GetSliceBounds(len(l.VisibleItems()))
+	index := start + row/rowsPerItem
+	if index >= end {
+		return 0, false
+	}
+	return index, true
+}
+
+// listHeaderHeight is the number of rows a list draws above its items: the
+// title (or filter input) and the status bar
+func listHeaderHeight(l list.Model) int {
+	height := 0
+	if l.ShowTitle() || (l.ShowFilter() && l.FilteringEnabled()) {
+		height += lipgloss.Height(l.Styles.TitleBar.Render(l.Title))
+	}
+	if l.ShowStatusBar() {
+		height += lipgloss.Height(l.Styles.StatusBar.Render(""))
+	}
+	return height
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPkgverMouse(t *testing.T) {
+	versions := []string{"3.7.1", "3.7.0", "3.6.4", "3.6.3", "3.6.2"}
+	var model tea.Model = newVersionSelector("jquery", "unpkg", "3.7.1", versions, versionSelectorOptions{})
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	send := func(msg tea.MouseMsg) versionSelectorModel {
+		t.Helper()
+		model, _ = model.Update(msg)
+		return model.(versionSelectorModel)
+	}
+
+	m := send(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if got := m.list.Index(); got != 1 {
+		t.Errorf("after wheel down, index = %d, want 1", got)
+	}
+	m = send(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("after wheel up, index = %d, want 0", got)
+	}
+
+	firstRow := listViewTop + listHeaderHeight(m.list)
+	m = send(tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionPress, Y: firstRow + 3})
+	if got := m.list.Index(); got != 3 {
+		t.Errorf("after clicking the fourth row, index = %d, want 3", got)
+	}
+
+	// Clicks on the title or below the last item leave the selection alone
+	for _, y := range []int{0, firstRow - 1, firstRow + len(versions)} {
+		m = send(tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionPress, Y: y})
+		if got := m.list.Index(); got != 3 {
+			t.Errorf("after clicking row %d, index = %d, want 3", y, got)
+		}
+	}
+
+	// Releasing the button doesn't select again
+	m = send(tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease, Y: firstRow})
+	if got := m.list.Index(); got != 3 {
+		t.Errorf("after a release, index = %d, want 3", got)
+	}
+	if m.choice != "" {
+		t.Errorf("a click chose version %s, want it only selected", m.choice)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	unpackDir   string
+	unpackForce bool
+)
+
+// unpackCmd represents the unpack command
+var unpackCmd = &cobra.Command{
+	Use:   "unpack [archive.tar.zst]",
+	Short: "Restore an archive written by pack",
+	Long: `Restore the config, lockfile and vendored files from an archive written by
+'smfaman pack'. If no archive is given, smfaman-pack.tar.zst is used.
+
+Files are written below --dir (default: the current directory). Nothing is
+written when a file in the archive already exists there, unless --force is
+given to overwrite it.
+
+Examples:
+  smfaman unpack
+  smfaman unpack release-assets.tar.zst --dir /srv/site
+  smfaman unpack release-assets.tar.zst --force`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		archive := defaultPackFile
+		if len(args) > 0 {
+			archive = args[0]
+		}
+		if err := runUnpack(archive, unpackDir, unpackForce); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unpackCmd)
+	unpackCmd.Flags().StringVar(&unpackDir, "dir", ".", "Directory to restore the files into")
+	unpackCmd.Flags().BoolVar(&unpackForce, "force", false, "Overwrite files that already exist")
+}
+
+// runUnpack executes the unpack command
+func runUnpack(archive, dir string, force bool) error {
+	// First pass: check the archive and look for files in the way
+	var manifest *packManifest
+	var conflicts []string
+	err := readPackArchive(archive, func(name string, r io.Reader) error {
+		if name == packManifestName {
+			manifest = &packManifest{}
+			if err := json.NewDecoder(r).Decode(manifest); err != nil {
+				return fmt.Errorf("failed to parse pack manifest: %w", err)
+			}
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err == nil {
+			conflicts = append(conflicts, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if manifest == nil {
+		return fmt.Errorf("%s is not a smfaman pack archive (no %s)", archive, packManifestName)
+	}
+	if manifest.FormatVersion > packFormatVersion {
+		return fmt.Errorf("%s was written by a newer smfaman (format %d), upgrade smfaman to unpack it", archive, manifest.FormatVersion)
+	}
+	if len(conflicts) > 0 && !force {
+		shown := conflicts
+		if len(shown) > 5 {
+			shown = append(shown[:5:5], fmt.Sprintf("and %d more", len(conflicts)-5))
+		}
+		return fmt.Errorf("%d %s already exist (use --force to overwrite): %s",
+			len(conflicts), pluralize(len(conflicts), "file", "files"), strings.Join(shown, ", "))
+	}
+
+	// Second pass: write the files
+	written := 0
+	err = readPackArchive(archive, func(name string, r io.Reader) error {
+		if name == packManifestName {
+			return nil
+		}
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		// Replace existing files so writes never go through a link into the store
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written++
+		return f.Close()
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Unpacked %d %s for %d %s into %s\n",
+		written, pluralize(written, "file", "files"),
+		len(manifest.Libraries), pluralize(len(manifest.Libraries), "library", "libraries"), dir)
+	if len(conflicts) > 0 {
+		fmt.Printf("  Overwrote %d existing %s\n", len(conflicts), pluralize(len(conflicts), "file", "files"))
+	}
+	fmt.Printf("\nConfig: %s\n", filepath.Join(dir, filepath.FromSlash(manifest.Config)))
+	fmt.Printf("Run 'smfaman -f %s check' in %s to verify the files\n", manifest.Config, dir)
+	return nil
+}
+
+// readPackArchive calls fn with the name and contents of each file in a pack
+// archive, rejecting entries that aren't regular files or would be written
+// outside the destination directory
+func readPackArchive(archive string, fn func(name string, r io.Reader) error) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return fmt.Errorf("unsupported archive entry %s (only regular files are allowed)", header.Name)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(header.Name)) {
+			return fmt.Errorf("illegal file path in archive: %s", header.Name)
+		}
+		if err := fn(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
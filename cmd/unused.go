@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultUnusedExtensions are the source files scanned for references
+var defaultUnusedExtensions = []string{
+	"html", "htm", "js", "mjs", "cjs", "ts", "tsx", "jsx", "vue", "svelte", "astro",
+	"css", "scss", "sass", "less", "php", "erb", "tmpl", "tpl", "gohtml", "templ",
+	"jinja", "jinja2", "j2", "njk", "hbs", "twig", "cshtml", "razor", "py", "rb", "go",
+}
+
+// unusedSkipDirs are folders never scanned
+var unusedSkipDirs = []string{".git", "node_modules", trashDirName, archiveDirName, patchesDirName}
+
+// unusedAssetExtensions are the vendored files pages refer to directly
+var unusedAssetExtensions = []string{
+	".js", ".mjs", ".cjs", ".css", ".woff", ".woff2", ".ttf", ".otf", ".eot",
+	".svg", ".png", ".gif", ".jpg", ".jpeg", ".webp",
+}
+
+var (
+	unusedRoots      []string
+	unusedExtensions []string
+	unusedVerbose    bool
+)
+
+// unusedCmd represents the unused command
+var unusedCmd = &cobra.Command{
+	Use:   "unused",
+	Short: "Find configured libraries the project's source never refers to",
+	Long: `Search the project's HTML, JavaScript, CSS and template files for references to
+each configured library and list the libraries nothing refers to, as
+candidates for removal from the config.
+
+A library counts as referenced when a source file mentions one of its
+vendored JavaScript, CSS, font or image files by its path inside the
+destination (e.g. dist/jquery.min.js), or loads it from a CDN as
+<library>@<version>. The library destinations themselves, .git, node_modules
+and smfaman's own folders are not searched.
+
+Files are recorded in the lockfile by sync; libraries that were never synced
+are listed separately.
+
+Examples:
+  smfaman unused
+  smfaman unused --root templates --root static/js
+  smfaman unused --ext html,js,jinja --verbose`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runUnused(unusedRoots, unusedExtensions, unusedVerbose); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unusedCmd)
+	unusedCmd.Flags().StringArrayVar(&unusedRoots, "root", []string{"."}, "Folder to search (repeatable)")
+	unusedCmd.Flags().StringSliceVar(&unusedExtensions, "ext", defaultUnusedExtensions, "File extensions to search (comma-separated)")
+	unusedCmd.Flags().BoolVarP(&unusedVerbose, "verbose", "v", false, "Also show where each used library is referenced")
+}
+
+// libraryUsage is where a library is first referenced, if anywhere
+type libraryUsage struct {
+	Library string
+	File    string // Source file with the first reference
+	Needle  string // The reference found
+}
+
+// runUnused executes the unused command
+func runUnused(roots, extensions []string, verbose bool) error {
+	config, err := loadConfig(FrontendConfig)
+	if err != nil {
+		return err
+	}
+	if len(config.Libraries) == 0 {
+		fmt.Println("No libraries configured.")
+		return nil
+	}
+
+	manifest, err := loadManifest(manifestPathForConfig(FrontendConfig))
+	if err != nil {
+		return err
+	}
+
+	// What to look for, per library
+	needles := make(map[string][]string)
+	var skip []string
+	for _, name := range sortedKeys(config.Libraries) {
+		destPath, err := config.GetLibraryDestination(name, config.Libraries[name])
+		if err != nil {
+			return fmt.Errorf("failed to get destination for %s: %w", name, err)
+		}
+		skip = append(skip, destPath)
+
+		var files []string
+		for _, entry := range manifest.Files {
+			if entry.Library == name {
+				files = append(files, entry.File)
+			}
+		}
+		if len(files) == 0 {
+			local, _ := listLocalFiles(destPath)
+			for _, file := range local {
+				files = append(files, file.Path)
+			}
+		}
+		if len(files) > 0 {
+			needles[name] = libraryNeedles(name, files)
+		}
+	}
+
+	usages, scanned, err := findLibraryUsages(roots, extensions, skip, needles)
+	if err != nil {
+		return err
+	}
+
+	var unused, unsynced []string
+	for _, name := range sortedKeys(config.Libraries) {
+		usage := usages[name]
+		switch {
+		case needles[name] == nil:
+			unsynced = append(unsynced, name)
+		case usage.File == "":
+			unused = append(unused, name)
+		}
+	}
+
+	fmt.Printf("Searched %d %s in %s\n\n", scanned, pluralize(scanned, "file", "files"), strings.Join(roots, ", "))
+
+	if verbose {
+		for _, name := range sortedKeys(usages) {
+			if usage := usages[name]; usage.File != "" {
+				fmt.Printf("  ✓ %s: %s (%s)\n", name, usage.File, usage.Needle)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(unused) == 0 {
+		fmt.Println("✓ Every synced library is referenced.")
+	} else {
+		fmt.Printf("%d %s not referenced anywhere:\n", len(unused), pluralize(len(unused), "library is", "libraries are"))
+		for _, name := range unused {
+			fmt.Printf("  • %s@%s\n", name, config.Libraries[name].Version)
+		}
+		fmt.Println("\nCheck them and remove the unneeded ones with 'smfaman delete <library>'.")
+	}
+	if len(unsynced) > 0 {
+		fmt.Printf("\nNot checked (not synced, run 'smfaman sync' first): %s\n", strings.Join(unsynced, ", "))
+	}
+	return nil
+}
+
+// libraryNeedles returns the strings whose presence in a source file marks a
+// library as used: its vendored asset paths and its CDN package reference
+func libraryNeedles(libName string, files []string) []string {
+	needles := []string{libName + "@"}
+	for _, file := range files {
+		if slices.Contains(unusedAssetExtensions, strings.ToLower(path.Ext(file))) {
+			needles = append(needles, strings.TrimPrefix(file, "/"))
+		}
+	}
+	slices.Sort(needles[1:])
+	return slices.Compact(needles)
+}
+
+// findLibraryUsages searches the files below roots with the given extensions
+// for each library's needles, skipping the folders in skip. It returns the
+// first reference per library and the number of files searched.
+func findLibraryUsages(roots, extensions, skip []string, needles map[string][]string) (map[string]libraryUsage, int, error) {
+	exts := make(map[string]bool)
+	for _, ext := range extensions {
+		exts["."+strings.ToLower(strings.TrimPrefix(ext, "."))] = true
+	}
+	var skipAbs []string
+	for _, dir := range skip {
+		if abs, err := filepath.Abs(dir); err == nil {
+			skipAbs = append(skipAbs, abs)
+		}
+	}
+
+	usages := make(map[string]libraryUsage)
+	for name := range needles {
+		usages[name] = libraryUsage{Library: name}
+	}
+
+	scanned := 0
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				abs, _ := filepath.Abs(p)
+				if p != root && (slices.Contains(unusedSkipDirs, d.Name()) || slices.Contains(skipAbs, abs)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !exts[strings.ToLower(filepath.Ext(p))] {
+				return nil
+			}
+
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			scanned++
+			text := string(data)
+			for name, libNeedles := range needles {
+				if usages[name].File != "" {
+					continue
+				}
+				for _, needle := range libNeedles {
+					if strings.Contains(text, needle) {
+						usages[name] = libraryUsage{Library: name, File: p, Needle: needle}
+						break
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to search %s: %w", root, err)
+		}
+	}
+	return usages, scanned, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLibraryNeedles(t *testing.T) {
+	got := libraryNeedles("jquery", []string{"dist/jquery.min.js", "dist/jquery.min.map", "README.md", "dist/jquery.js"})
+	want := []string{"jquery@", "dist/jquery.js", "dist/jquery.min.js"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("libraryNeedles = %v, want %v", got, want)
+	}
+}
+
+func TestFindLibraryUsages(t *testing.T) {
+	tmpDir := t.TempDir()
+	libs := filepath.Join(tmpDir, "static", "libs")
+
+	writeTestFile(t, filepath.Join(tmpDir, "templates", "base.html"),
+		`<script src="{{ static 'libs/htmx/dist/htmx.min.js' }}"></script>`)
+	writeTestFile(t, filepath.Join(tmpDir, "src", "app.js"),
+		`import confetti from "https://cdn.jsdelivr.net/npm/canvas-confetti@1.9.2/+esm";`)
+	writeTestFile(t, filepath.Join(tmpDir, "README.md"), "Uses dist/alpine.min.js")
+	writeTestFile(t, filepath.Join(tmpDir, "node_modules", "x", "index.js"), "dist/alpine.min.js")
+	// A library's own files don't count as references
+	writeTestFile(t, filepath.Join(libs, "bootstrap", "dist", "bootstrap.js"), "require('dist/alpine.min.js')")
+
+	needles := map[string][]string{
+		"htmx":            libraryNeedles("htmx", []string{"dist/htmx.min.js"}),
+		"canvas-confetti": libraryNeedles("canvas-confetti", []string{"dist/confetti.browser.js"}),
+		"alpine":          libraryNeedles("alpine", []string{"dist/alpine.min.js"}),
+	}
+	usages, scanned, err := findLibraryUsages([]string{tmpDir}, defaultUnusedExtensions, []string{libs}, needles)
+	if err != nil {
+		t.Fatalf("findLibraryUsages failed: %v", err)
+	}
+
+	if scanned != 2 {
+		t.Errorf("scanned %d files, want 2", scanned)
+	}
+	if usages["htmx"].Needle != "dist/htmx.min.js" {
+		t.Errorf("htmx usage = %+v", usages["htmx"])
+	}
+	if usages["canvas-confetti"].Needle != "canvas-confetti@" {
+		t.Errorf("canvas-confetti usage = %+v", usages["canvas-confetti"])
+	}
+	if usages["alpine"].File != "" {
+		t.Errorf("alpine should be unused, found in %s", usages["alpine"].File)
+	}
+}
@@ -10,13 +10,21 @@ import (
 )
 
 var (
-	upgradeDryRun     bool
-	upgradeInteractive bool
+	upgradeDryRun            bool
+	upgradeInteractive       bool
+	upgradeAutoCDN           bool
+	upgradeAllowDowngrade    bool
+	upgradeIncludePrerelease bool
+	upgradePR                string
+	upgradeMigrate           string
 )
 
+// fetchUpgradeVersions fetches the versions libraries are upgraded to (overridable in tests)
+var fetchUpgradeVersions versionFetcher = fetchVersionsForUpgrade
+
 // upgradeCmd represents the upgrade command
 var upgradeCmd = &cobra.Command{
-	Use:     "upgrade [package-name[@version]]",
+	Use:     "upgrade [package-name[@version]...]",
 	Aliases: []string{"u"},
 	Short:   "Upgrade library version(s) in the Smart Frontend Asset Manager Configuration",
 	Long: `Upgrade one or more libraries to newer versions.
@@ -29,35 +37,78 @@ This command can be used in three ways:
 2. Upgrade a specific library to the latest version:
    smfaman upgrade react
 
-3. Upgrade all libraries to their latest versions:
+3. Upgrade several libraries at once, each to the latest or a given version:
+   smfaman upgrade react vue jquery@3.7.1
+
+4. Upgrade all libraries to their latest versions:
    smfaman upgrade
 
+When several libraries are upgraded, a combined summary is shown and the
+config file is written once.
+
 The command will fetch the latest available versions from the configured CDN
 for each library and update the configuration file accordingly.
 
 Use --dry-run to preview changes without modifying the config file.
 Use --interactive to select versions interactively.
 
+With versioned destinations (a {version} placeholder), --migrate syncs the
+upgraded libraries right away and then removes the previous version folders
+(--migrate=archive moves them into .smfaman-archive/ instead), recording
+both in the lockfile, like 'smfaman sync --migrate'. With --pr the migrated
+folders are listed among the changed files.
+
+The latest version is the newest stable release, even when the CDN tags a
+prerelease as latest. Use --include-prerelease to upgrade to a newer
+prerelease (19.0.0-rc.1) when there is one.
+
+Downgrades (react 18.2.0 → 17.0.0) and major version jumps (18.x → 19.x)
+are flagged with a warning and need confirmation before the config is
+written. Use --allow-downgrade (or --yes) to accept them without asking.
+
+When upgrading several libraries, the change to the config file is shown as
+a diff and, in a terminal, confirmed before it is written (--yes skips the
+question).
+
+--pr <file> is for update bots (Renovate-lite for CDN assets): it applies
+the upgrades without asking, syncs so the vendored files and lockfile match,
+and writes a JSON summary with a suggested branch name, a pull request title,
+a markdown body and the list of changed files. Downgrades and major version
+jumps are left out and listed as skipped unless --allow-downgrade is given.
+
+If a library (or the requested version) isn't available on its CDN, the other
+CDNs are checked and you are offered one that has it. Use --auto-cdn to
+switch without asking.
+
 Examples:
   smfaman upgrade react@18.3.0
   smfaman upgrade react
+  smfaman upgrade react vue jquery@3.7.1
   smfaman upgrade --dry-run
   smfaman u bootstrap --interactive
+  smfaman upgrade htmx.org --auto-cdn
+  smfaman upgrade react@17.0.2 --allow-downgrade
+  smfaman upgrade react --include-prerelease
+  smfaman upgrade --pr pr.json
+  smfaman upgrade bootstrap --migrate=archive
   smfaman u`,
-	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
-		if len(args) == 0 {
-			// Upgrade all libraries
-			err = upgradeAllLibraries()
-		} else {
+		if !isValidMigrateMode(upgradeMigrate) {
+			err = fmt.Errorf("invalid --migrate value %q (must be %s or %s)", upgradeMigrate, migrateRemove, migrateArchive)
+		} else if upgradePR != "" {
+			// Unattended upgrade and sync for update bots
+			err = runUpgradePR(args, upgradePR)
+		} else if len(args) == 1 {
 			// Upgrade specific library
 			err = upgradeSpecificLibrary(args[0])
+		} else {
+			// Upgrade the named libraries, or all of them
+			err = upgradeLibraries(args)
 		}
 
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exitWithError(err)
 		}
 	},
 }
@@ -67,6 +118,12 @@ func init() {
 
 	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Show what would be upgraded without making changes")
 	upgradeCmd.Flags().BoolVarP(&upgradeInteractive, "interactive", "i", false, "Interactively select version")
+	upgradeCmd.Flags().BoolVar(&upgradeAutoCDN, "auto-cdn", false, "Switch to another CDN without asking if the library isn't on its CDN")
+	upgradeCmd.Flags().BoolVar(&upgradeIncludePrerelease, "include-prerelease", false, "Upgrade to a prerelease when one is newer than the latest stable version")
+	upgradeCmd.Flags().BoolVar(&upgradeAllowDowngrade, "allow-downgrade", false, "Allow downgrades and major version jumps without confirmation")
+	upgradeCmd.Flags().StringVar(&upgradePR, "pr", "", "Upgrade and sync without prompts, then write a pull request summary (JSON) to this file")
+	upgradeCmd.Flags().StringVar(&upgradeMigrate, "migrate", "", "Sync the upgraded libraries, then remove (or archive) their previous version folders: remove or archive")
+	upgradeCmd.Flags().Lookup("migrate").NoOptDefVal = migrateRemove
 }
 
 // upgradeSpecificLibrary upgrades a specific library to a specified or latest version
@@ -83,24 +140,30 @@ func upgradeSpecificLibrary(packageSpec string) error {
 	// Check if library exists in config
 	libConfig, exists := config.Libraries[packageName]
 	if !exists {
-		return fmt.Errorf("library '%s' not found in config. Use 'smfaman add' to add it first", packageName)
+		return notFoundError(fmt.Errorf("library '%s' not found in config. Use 'smfaman add' to add it first", packageName))
 	}
 
 	currentVersion := libConfig.Version
 
 	// Determine CDN to use
-	cdn := config.GetLibraryCDN(libConfig)
+	cdn := libraryCDN(config, libConfig)
+	configuredCDN := cdn
 
 	var newVersion string
 
-	if upgradeInteractive {
+	if upgradeInteractive && interactiveAvailable() {
 		// Interactive mode
-		versions, latestVersion, err := fetchVersionsForUpgrade(packageName, cdn)
+		versions, latestVersion, usedCDN, err := fetchVersionsWithFallback(packageName, "", cdn, fetchUpgradeVersions, upgradeAutoCDN)
 		if err != nil {
 			return err
 		}
+		cdn = usedCDN
+		latestVersion = frontend_mgr.LatestVersion(versions, latestVersion, upgradeIncludePrerelease)
 
-		newVersion, err = runInteractive(packageName, string(cdn), latestVersion, versions)
+		newVersion, err = runVersionSelector(packageName, string(cdn), latestVersion, versions, versionSelectorOptions{
+			ShowPrereleases: upgradeIncludePrerelease,
+			Current:         currentVersion,
+		})
 		if err != nil {
 			return fmt.Errorf("interactive mode error: %w", err)
 		}
@@ -109,37 +172,45 @@ func upgradeSpecificLibrary(packageSpec string) error {
 			fmt.Println("Cancelled.")
 			return nil
 		}
-	} else if specifiedVersion != "" {
-		// Validate specified version
-		if err := validateVersionForUpgrade(packageName, specifiedVersion, cdn); err != nil {
-			return err
-		}
-		newVersion = specifiedVersion
 	} else {
-		// Get latest version
-		_, latestVersion, err := fetchVersionsForUpgrade(packageName, cdn)
+		// The specified version, or the latest
+		_, _, target, usedCDN, err := fetchUpgradeTarget(packageName, specifiedVersion, cdn)
 		if err != nil {
 			return err
 		}
-		newVersion = latestVersion
+		cdn = usedCDN
+		newVersion = target
 	}
 
 	// Check if already up to date
-	if currentVersion == newVersion {
+	if frontend_mgr.SameVersion(currentVersion, newVersion) && cdn == configuredCDN {
 		fmt.Printf("✓ Library '%s' is already at version %s\n", packageName, currentVersion)
 		return nil
 	}
 
 	// Show upgrade info
-	fmt.Printf("\nUpgrading '%s': %s → %s\n", packageName, currentVersion, newVersion)
+	fmt.Printf("\nUpgrading '%s': %s → %s\n", packageName, currentVersion, versionLabel(newVersion))
+
+	warning := versionChangeWarning(packageName, currentVersion, newVersion)
+	if warning != "" {
+		fmt.Printf("\n%s\n", warning)
+	}
 
 	if upgradeDryRun {
 		fmt.Println("\n[DRY RUN] No changes made to config file.")
 		return nil
 	}
 
+	if warning != "" && !confirmRiskyUpgrade() {
+		fmt.Println("Cancelled. No changes made to config file.")
+		return nil
+	}
+
 	// Update version
 	libConfig.Version = newVersion
+	if cdn != configuredCDN {
+		libConfig.CDN = cdn
+	}
 	config.Libraries[packageName] = libConfig
 
 	// Save config
@@ -154,14 +225,64 @@ func upgradeSpecificLibrary(packageSpec string) error {
 	fmt.Printf("New:      %s\n", newVersion)
 	fmt.Printf("CDN:      %s\n", cdn)
 	fmt.Printf("\nConfig updated: %s\n", FrontendConfig)
-	fmt.Printf("\nNext steps:\n")
-	fmt.Printf("  • Sync libraries: smfaman sync\n")
+	if upgradeMigrate == "" {
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("  • Sync libraries: smfaman sync\n")
+	}
+
+	sendNotification(upgradeNotification(FrontendConfig, config.ProjectName, []notifiedChange{
+		{Library: packageName, From: currentVersion, Version: newVersion, CDN: string(cdn)},
+	}))
 
+	if upgradeMigrate != "" {
+		return syncAndMigrate(FrontendConfig, []string{packageName}, upgradeMigrate, os.Stdout)
+	}
 	return nil
 }
 
 // upgradeAllLibraries upgrades all libraries to their latest versions
 func upgradeAllLibraries() error {
+	return upgradeLibraries(nil)
+}
+
+// fetchUpgradeTarget fetches the versions of a library and picks the one to
+// upgrade to: the latest (stable, unless --include-prerelease) when wanted is empty, an exact version (which must
+// be published), or the highest release matching a prefix such as "17" or
+// "3.4", a range or a dist-tag
+func fetchUpgradeTarget(packageName, wanted string, cdn frontend_config.CDN) ([]string, string, string, frontend_config.CDN, error) {
+	exact := wanted == "" || frontend_mgr.IsExactVersion(wanted)
+	probe := wanted
+	if !exact {
+		probe = ""
+	}
+
+	versions, latest, usedCDN, err := fetchVersionsWithFallback(packageName, probe, cdn, fetchUpgradeVersions, upgradeAutoCDN)
+	if err != nil {
+		return nil, "", "", cdn, err
+	}
+	latest = frontend_mgr.LatestVersion(versions, latest, upgradeIncludePrerelease)
+
+	switch {
+	case wanted == "":
+		return versions, latest, latest, usedCDN, nil
+	case exact:
+		// Keep the version as the CDN published it, with or without a "v"
+		published, _ := frontend_mgr.FindVersion(versions, wanted)
+		return versions, latest, published, usedCDN, nil
+	}
+
+	target, err := resolveRequestedVersion(packageName, wanted, versions, latest, usedCDN)
+	if err != nil {
+		return nil, "", "", usedCDN, err
+	}
+	fmt.Printf("✓ %s@%s resolves to %s\n", packageName, wanted, target)
+	return versions, latest, target, usedCDN, nil
+}
+
+// upgradeLibraries upgrades the libraries named by specs (all libraries when
+// empty) to the version in the spec or the latest version, and writes the
+// config once
+func upgradeLibraries(specs []string) error {
 	// Load existing config
 	config, err := loadConfigForUpgrade(FrontendConfig)
 	if err != nil {
@@ -173,7 +294,15 @@ func upgradeAllLibraries() error {
 		return nil
 	}
 
-	fmt.Printf("Checking for updates for %d library(ies)...\n\n", len(config.Libraries))
+	libNames, requested, err := selectUpgradeLibraries(config, specs)
+	if err != nil {
+		return err
+	}
+
+	// Interactive selection only applies to explicitly named libraries
+	interactive := upgradeInteractive && len(specs) > 0 && interactiveAvailable()
+
+	fmt.Printf("Checking for updates for %d library(ies)...\n\n", len(libNames))
 
 	type upgradeInfo struct {
 		name           string
@@ -187,31 +316,52 @@ func upgradeAllLibraries() error {
 	var errors []string
 
 	// Check each library for updates
-	for libName, libConfig := range config.Libraries {
+	for _, libName := range libNames {
+		libConfig := config.Libraries[libName]
 		currentVersion := libConfig.Version
-		cdn := config.GetLibraryCDN(libConfig)
+		cdn := libraryCDN(config, libConfig)
+		wanted := requested[libName]
 
-		// Fetch latest version
-		_, latestVersion, err := fetchVersionsForUpgrade(libName, cdn)
+		// Fetch versions, checking or resolving the requested version
+		versions, latestVersion, newVersion, usedCDN, err := fetchUpgradeTarget(libName, wanted, cdn)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", libName, err))
 			continue
 		}
 
-		if currentVersion == latestVersion {
+		if wanted == "" && interactive {
+			options := versionSelectorOptions{ShowPrereleases: upgradeIncludePrerelease, Current: currentVersion}
+			if newVersion, err = runVersionSelector(libName, string(usedCDN), latestVersion, versions, options); err != nil {
+				errors = append(errors, fmt.Sprintf("%s: interactive mode error: %v", libName, err))
+				continue
+			}
+			if newVersion == "" {
+				fmt.Printf("Skipped %s.\n", libName)
+				continue
+			}
+		}
+
+		if frontend_mgr.SameVersion(currentVersion, newVersion) && usedCDN == cdn {
 			upToDate = append(upToDate, fmt.Sprintf("%s@%s", libName, currentVersion))
 		} else {
 			upgrades = append(upgrades, upgradeInfo{
 				name:           libName,
 				currentVersion: currentVersion,
-				newVersion:     latestVersion,
-				cdn:            cdn,
+				newVersion:     newVersion,
+				cdn:            usedCDN,
 			})
 		}
 	}
 
 	// Display summary
 	if len(upgrades) == 0 {
+		if len(errors) > 0 {
+			fmt.Printf("Errors (%d):\n", len(errors))
+			for _, errMsg := range errors {
+				fmt.Printf("  • %s\n", errMsg)
+			}
+			return fmt.Errorf("failed to check %d library(ies)", len(errors))
+		}
 		fmt.Println("✓ All libraries are up to date!")
 		if len(upToDate) > 0 {
 			fmt.Println("\nCurrent versions:")
@@ -224,7 +374,7 @@ func upgradeAllLibraries() error {
 
 	fmt.Printf("Found %d upgrade(s) available:\n\n", len(upgrades))
 	for _, u := range upgrades {
-		fmt.Printf("  • %s: %s → %s (from %s)\n", u.name, u.currentVersion, u.newVersion, u.cdn)
+		fmt.Printf("  • %s: %s → %s (from %s)\n", u.name, u.currentVersion, versionLabel(u.newVersion), u.cdn)
 	}
 
 	if len(upToDate) > 0 {
@@ -241,19 +391,63 @@ func upgradeAllLibraries() error {
 		}
 	}
 
+	var warnings []string
+	risky := make(map[string]bool)
+	for _, u := range upgrades {
+		if warning := versionChangeWarning(u.name, u.currentVersion, u.newVersion); warning != "" {
+			warnings = append(warnings, warning)
+			risky[u.name] = true
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Println()
+		for _, warning := range warnings {
+			fmt.Println(warning)
+		}
+	}
+
 	if upgradeDryRun {
 		fmt.Println("\n[DRY RUN] No changes made to config file.")
 		return nil
 	}
 
+	// Leave the flagged libraries alone unless the user accepts them
+	if len(warnings) > 0 && !confirmRiskyUpgrade() {
+		var safe []upgradeInfo
+		for _, u := range upgrades {
+			if !risky[u.name] {
+				safe = append(safe, u)
+			}
+		}
+		fmt.Printf("Skipping %d flagged library(ies).\n", len(upgrades)-len(safe))
+		upgrades = safe
+		if len(upgrades) == 0 {
+			fmt.Println("No changes made to config file.")
+			return nil
+		}
+	}
+
 	// Apply upgrades
 	fmt.Println("\nApplying upgrades...")
 	for _, u := range upgrades {
 		libConfig := config.Libraries[u.name]
 		libConfig.Version = u.newVersion
+		if u.cdn != libraryCDN(config, libConfig) {
+			libConfig.CDN = u.cdn
+		}
 		config.Libraries[u.name] = libConfig
 	}
 
+	// Show the pending change to the config file before writing it
+	write, err := reviewConfigSave(FrontendConfig, config)
+	if err != nil {
+		return err
+	}
+	if !write {
+		fmt.Println("No changes made to config file.")
+		return nil
+	}
+
 	// Save config
 	if err := saveConfigForUpgrade(FrontendConfig, config); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
@@ -261,73 +455,92 @@ func upgradeAllLibraries() error {
 
 	fmt.Printf("\n✓ Successfully upgraded %d library(ies)!\n", len(upgrades))
 	fmt.Printf("\nConfig updated: %s\n", FrontendConfig)
-	fmt.Printf("\nNext steps:\n")
-	fmt.Printf("  • Sync libraries: smfaman sync\n")
+	if upgradeMigrate == "" {
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("  • Sync libraries: smfaman sync\n")
+	}
 
+	changes := make([]notifiedChange, len(upgrades))
+	names := make([]string, len(upgrades))
+	for i, u := range upgrades {
+		changes[i] = notifiedChange{Library: u.name, From: u.currentVersion, Version: u.newVersion, CDN: string(u.cdn)}
+		names[i] = u.name
+	}
+	sendNotification(upgradeNotification(FrontendConfig, config.ProjectName, changes))
+
+	if upgradeMigrate != "" {
+		return syncAndMigrate(FrontendConfig, names, upgradeMigrate, os.Stdout)
+	}
 	return nil
 }
 
-// fetchVersionsForUpgrade fetches versions from the appropriate CDN
-func fetchVersionsForUpgrade(packageName string, cdn frontend_config.CDN) (versions []string, latest string, err error) {
-	switch cdn {
-	case frontend_config.CDNUnpkg:
-		result, err := frontend_mgr.FetchUnpkgVersions(packageName)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to fetch versions from unpkg: %w", err)
-		}
-		versions = make([]string, 0, len(result.Versions))
-		for ver := range result.Versions {
-			versions = append(versions, ver)
-		}
-		latest = result.DistTags["latest"]
-
-	case frontend_config.CDNCdnjs:
-		result, err := frontend_mgr.FetchCdnjsVersions(packageName)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to fetch versions from cdnjs: %w", err)
-		}
-		versions = result.Versions
-		latest = result.Version
-
-	case frontend_config.CDNJsdelivr:
-		result, err := frontend_mgr.FetchJsdelivrVersions(packageName)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to fetch versions from jsdelivr: %w", err)
+// selectUpgradeLibraries returns the libraries named by specs (all of them
+// when there are none) and maps each to its requested version ("" for latest)
+func selectUpgradeLibraries(config *frontend_config.FrontendConfig, specs []string) ([]string, map[string]string, error) {
+	requested := make(map[string]string)
+	var libNames []string
+	if len(specs) == 0 {
+		libNames = sortedKeys(config.Libraries)
+	}
+	for _, spec := range specs {
+		name, wanted := parsePackageSpec(spec)
+		if _, exists := config.Libraries[name]; !exists {
+			return nil, nil, notFoundError(fmt.Errorf("library '%s' not found in config. Use 'smfaman add' to add it first", name))
 		}
-		versions = make([]string, 0, len(result.Versions))
-		for _, vInfo := range result.Versions {
-			versions = append(versions, vInfo.Version)
+		if _, seen := requested[name]; !seen {
+			libNames = append(libNames, name)
 		}
-		latest = result.Tags["latest"]
+		requested[name] = wanted
+	}
+	return libNames, requested, nil
+}
 
-	default:
-		return nil, "", fmt.Errorf("unsupported CDN: %s", cdn)
+// versionChangeWarning returns a warning when moving from current to next is
+// a downgrade or crosses a major version, or "" when it is neither. Versions
+// that aren't plain semver (ranges, dist-tags) are not checked.
+func versionChangeWarning(name, current, next string) string {
+	cmp, err := frontend_mgr.CompareVersions(next, current)
+	if err != nil {
+		return ""
+	}
+	if cmp < 0 {
+		return fmt.Sprintf("⚠ DOWNGRADE: %s %s → %s", name, current, next)
 	}
 
-	if len(versions) == 0 {
-		return nil, "", fmt.Errorf("no versions found for package '%s'", packageName)
+	fromMajor, _ := frontend_mgr.MajorVersion(current)
+	toMajor, _ := frontend_mgr.MajorVersion(next)
+	if toMajor != fromMajor {
+		return fmt.Sprintf("⚠ MAJOR VERSION: %s %s → %s may include breaking changes", name, current, next)
 	}
+	return ""
+}
 
-	// Sort versions
-	sortedVersions := frontend_mgr.SortVersions(versions)
-	return sortedVersions, latest, nil
+// confirmRiskyUpgrade asks before writing downgrades or major version jumps,
+// unless --allow-downgrade was given
+func confirmRiskyUpgrade() bool {
+	if upgradeAllowDowngrade {
+		return true
+	}
+	return promptConfirmation("\nApply the flagged version changes? (use --allow-downgrade to skip this check)")
 }
 
-// validateVersionForUpgrade checks if a version exists for a package on a CDN
-func validateVersionForUpgrade(packageName, version string, cdn frontend_config.CDN) error {
-	versions, _, err := fetchVersionsForUpgrade(packageName, cdn)
+// fetchVersionsForUpgrade fetches versions from the appropriate CDN
+func fetchVersionsForUpgrade(packageName string, cdn frontend_config.CDN) (versions []string, latest string, err error) {
+	provider, err := frontend_mgr.GetProvider(string(cdn))
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	// Check if version exists
-	for _, v := range versions {
-		if v == version {
-			return nil
-		}
+	result, err := provider.Versions(packageName)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch versions from %s: %w", cdn, err)
+	}
+
+	if len(result.Versions) == 0 {
+		return nil, "", notFoundError(fmt.Errorf("no versions found for package '%s'", packageName))
 	}
 
-	return fmt.Errorf("version '%s' not found for package '%s' on %s", version, packageName, cdn)
+	return result.Versions, result.Latest(), nil
 }
 
 // loadConfigForUpgrade loads a frontend config from a file
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// prSummary is what upgrade --pr writes for a bot that opens the pull request
+type prSummary struct {
+	Title   string     `json:"title"`
+	Branch  string     `json:"branch"` // Same updates give the same branch, so bots can reuse open PRs
+	Body    string     `json:"body"`   // Markdown
+	Updates []prUpdate `json:"updates"`
+	Skipped []prUpdate `json:"skipped"`
+	Files   []string   `json:"files"` // Changed files, relative to the working directory
+}
+
+// prUpdate is a version change applied (or left out) by upgrade --pr
+type prUpdate struct {
+	Library string `json:"library"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	CDN     string `json:"cdn"`
+	Reason  string `json:"reason,omitempty"` // Why a skipped update was left out
+}
+
+// branchUnsafe matches the characters left out of suggested branch names
+var branchUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// runUpgradePR upgrades the libraries named by specs (or all of them)
+// without prompts, syncs them and writes the pull request summary to
+// summaryPath
+func runUpgradePR(specs []string, summaryPath string) error {
+	if upgradeInteractive || upgradeDryRun {
+		return fmt.Errorf("--pr can't be combined with --interactive or --dry-run")
+	}
+
+	config, err := loadConfigForUpgrade(FrontendConfig)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	libNames, requested, err := selectUpgradeLibraries(config, specs)
+	if err != nil {
+		return err
+	}
+
+	var updates, skipped []prUpdate
+	for _, libName := range libNames {
+		libConfig := config.Libraries[libName]
+		cdn := libraryCDN(config, libConfig)
+
+		_, _, newVersion, usedCDN, err := fetchUpgradeTarget(libName, requested[libName], cdn)
+		if err != nil {
+			// One unreachable library shouldn't hold back the others
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", libName, err)
+			continue
+		}
+		if frontend_mgr.SameVersion(libConfig.Version, newVersion) && usedCDN == cdn {
+			continue
+		}
+
+		update := prUpdate{Library: libName, From: libConfig.Version, To: newVersion, CDN: string(usedCDN)}
+		if warning := versionChangeWarning(libName, libConfig.Version, newVersion); warning != "" && !upgradeAllowDowngrade {
+			update.Reason = strings.TrimPrefix(warning, "⚠ ")
+			skipped = append(skipped, update)
+			continue
+		}
+		updates = append(updates, update)
+
+		libConfig.Version = newVersion
+		if usedCDN != cdn {
+			libConfig.CDN = usedCDN
+		}
+		config.Libraries[libName] = libConfig
+	}
+
+	if len(updates) == 0 {
+		fmt.Println("✓ All libraries are up to date!")
+		return writePRSummary(summaryPath, newPRSummary(nil, skipped, nil))
+	}
+
+	if err := saveConfigForUpgrade(FrontendConfig, config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Printf("✓ Upgraded %d %s in %s\n", len(updates), pluralize(len(updates), "library", "libraries"), FrontendConfig)
+
+	// Bring the vendored files and the lockfile in line with the new versions
+	files, err := syncForPR()
+	if err != nil {
+		return fmt.Errorf("upgraded the config but sync failed, no PR summary written: %w", err)
+	}
+
+	// Previous version folders are removed or archived in the same PR
+	if upgradeMigrate != "" {
+		stale, err := findStaleVersionFolders(FrontendConfig, config)
+		if err != nil {
+			return err
+		}
+		if err := migrateVersionFolders(FrontendConfig, config, upgradeMigrate, false, os.Stdout); err != nil {
+			return err
+		}
+		for _, folder := range stale {
+			files = append(files, folder.Path)
+		}
+		if len(stale) > 0 && lockfileEnabled(FrontendConfig) {
+			files = append(files, manifestPathForConfig(FrontendConfig))
+		}
+	}
+
+	changes := make([]notifiedChange, len(updates))
+	for i, u := range updates {
+		changes[i] = notifiedChange{Library: u.Library, From: u.From, Version: u.To, CDN: u.CDN}
+	}
+	sendNotification(upgradeNotification(FrontendConfig, config.ProjectName, changes))
+
+	return writePRSummary(summaryPath, newPRSummary(updates, skipped, append(files, FrontendConfig)))
+}
+
+// syncForPR syncs the upgraded config without asking and returns the files
+// it wrote, including the lockfile
+func syncForPR() ([]string, error) {
+	config, err := loadSyncConfig()
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := buildDownloadTasks(config)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	if err := applyStoredValidators(FrontendConfig, tasks); err != nil {
+		return nil, err
+	}
+
+	summary, err := downloadAndRecord(FrontendConfig, tasks, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, d := range summary.downloaded {
+		if !d.result.Unchanged {
+			files = append(files, d.task.DestPath)
+			files = append(files, d.task.MirrorPaths...)
+		}
+	}
+	if len(files) > 0 && lockfileEnabled(FrontendConfig) {
+		files = append(files, manifestPathForConfig(FrontendConfig))
+	}
+	return files, nil
+}
+
+// newPRSummary builds the pull request title, branch and body for the
+// applied updates
+func newPRSummary(updates, skipped []prUpdate, files []string) prSummary {
+	summary := prSummary{
+		Updates: append([]prUpdate{}, updates...),
+		Skipped: append([]prUpdate{}, skipped...),
+		Files:   workDirPaths(files),
+	}
+	if len(updates) == 0 {
+		return summary
+	}
+
+	if len(updates) == 1 {
+		u := updates[0]
+		summary.Title = fmt.Sprintf("Update %s to %s", u.Library, u.To)
+		summary.Branch = "smfaman/" + strings.Trim(branchUnsafe.ReplaceAllString(u.Library+"-"+u.To, "-"), "-")
+	} else {
+		summary.Title = fmt.Sprintf("Update %d frontend libraries", len(updates))
+		hash := sha256.New()
+		for _, u := range updates {
+			fmt.Fprintf(hash, "%s@%s\n", u.Library, u.To)
+		}
+		summary.Branch = fmt.Sprintf("smfaman/update-%d-libraries-%s", len(updates), hex.EncodeToString(hash.Sum(nil))[:8])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "This PR updates %d %s managed by smfaman.\n\n", len(updates), pluralize(len(updates), "frontend library", "frontend libraries"))
+	b.WriteString("| Library | From | To | CDN |\n|---|---|---|---|\n")
+	for _, u := range updates {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", u.Library, u.From, u.To, u.CDN)
+	}
+	if len(skipped) > 0 {
+		b.WriteString("\n**Not included** (run `smfaman upgrade --allow-downgrade` to apply):\n\n")
+		for _, u := range skipped {
+			fmt.Fprintf(&b, "- %s\n", u.Reason)
+		}
+	}
+	if len(summary.Files) > 0 {
+		fmt.Fprintf(&b, "\n<details>\n<summary>Changed files (%d)</summary>\n\n", len(summary.Files))
+		for _, f := range summary.Files {
+			fmt.Fprintf(&b, "- `%s`\n", f)
+		}
+		b.WriteString("\n</details>\n")
+	}
+	summary.Body = b.String()
+	return summary
+}
+
+// workDirPaths returns the paths relative to the working directory with
+// forward slashes, sorted and without duplicates
+func workDirPaths(paths []string) []string {
+	wd, _ := os.Getwd()
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				p = rel
+			}
+		}
+		p = filepath.ToSlash(p)
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// writePRSummary writes the summary as indented JSON
+func writePRSummary(path string, summary prSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode PR summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write PR summary: %w", err)
+	}
+	fmt.Printf("✓ Wrote PR summary to %s\n", path)
+	return nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"nexus-sds.com/smfaman/pkgs/frontend_config"
+)
+
+func TestNewPRSummary(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	updates := []prUpdate{{Library: "@popperjs/core", From: "2.11.6", To: "2.11.8", CDN: "unpkg"}}
+	skipped := []prUpdate{{Library: "react", From: "18.2.0", To: "19.0.0", Reason: "MAJOR VERSION: react 18.2.0 → 19.0.0 may include breaking changes"}}
+	files := []string{"smartfrontend.yaml", filepath.Join("frontend", "popper", "popper.min.js"), "smartfrontend.yaml"}
+
+	summary := newPRSummary(updates, skipped, files)
+	if summary.Title != "Update @popperjs/core to 2.11.8" || summary.Branch != "smfaman/popperjs-core-2.11.8" {
+		t.Errorf("title %q, branch %q", summary.Title, summary.Branch)
+	}
+	if len(summary.Files) != 2 || summary.Files[0] != "frontend/popper/popper.min.js" {
+		t.Errorf("Files = %v", summary.Files)
+	}
+	for _, want := range []string{"| `@popperjs/core` | 2.11.6 | 2.11.8 | unpkg |", "- MAJOR VERSION: react", "Changed files (2)"} {
+		if !strings.Contains(summary.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, summary.Body)
+		}
+	}
+
+	// The same set of updates always suggests the same branch
+	many := append(updates, prUpdate{Library: "jquery", From: "3.6.0", To: "3.7.1"})
+	first, second := newPRSummary(many, nil, nil), newPRSummary(many, nil, nil)
+	if first.Branch != second.Branch || !strings.HasPrefix(first.Branch, "smfaman/update-2-libraries-") || first.Title != "Update 2 frontend libraries" {
+		t.Errorf("title %q, branch %q", first.Title, first.Branch)
+	}
+}
+
+func TestRunUpgradePRWithoutUpdates(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "smartfrontend.yaml")
+	data, _ := yaml.Marshal(&frontend_config.FrontendConfig{
+		Destination: "./frontend",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"react":  {Version: "18.2.0"},
+			"jquery": {Version: "3.7.1"},
+		},
+	})
+	os.WriteFile(configPath, data, 0644)
+
+	oldConfig, origFetch := FrontendConfig, fetchUpgradeVersions
+	FrontendConfig = configPath
+	t.Cleanup(func() { FrontendConfig, fetchUpgradeVersions = oldConfig, origFetch })
+	published := map[string][]string{
+		"react":  {"19.0.0", "18.2.0"},
+		"jquery": {"3.7.1", "3.6.0"},
+	}
+	fetchUpgradeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		return published[packageName], published[packageName][0], nil
+	}
+
+	summaryPath := filepath.Join(dir, "pr.json")
+	if err := runUpgradePR(nil, summaryPath); err != nil {
+		t.Fatalf("runUpgradePR() error = %v", err)
+	}
+
+	var summary prSummary
+	raw, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Updates) != 0 || len(summary.Skipped) != 1 || summary.Skipped[0].Library != "react" || summary.Title != "" {
+		t.Errorf("unexpected summary: %s", raw)
+	}
+	if config, _ := loadConfig(configPath); config.Libraries["react"].Version != "18.2.0" {
+		t.Error("the major upgrade was written without --allow-downgrade")
+	}
+}
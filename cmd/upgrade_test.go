@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 	"nexus-sds.com/smfaman/pkgs/frontend_config"
 )
@@ -433,3 +436,211 @@ func TestUpgradeMultipleLibraries(t *testing.T) {
 		t.Errorf("expected 4 libraries, got %d", len(reloadedConfig.Libraries))
 	}
 }
+
+func TestUpgradeLibrariesSubset(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "upgrade-subset.yaml")
+
+	testConfig := frontend_config.FrontendConfig{
+		Destination: "./frontend",
+		Libraries: map[string]frontend_config.LibraryConfig{
+			"react":  {Version: "18.2.0"},
+			"vue":    {Version: "3.3.0"},
+			"jquery": {Version: "3.5.1"},
+		},
+	}
+	data, _ := yaml.Marshal(&testConfig)
+	os.WriteFile(configPath, data, 0644)
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	defer func() { FrontendConfig = oldConfig }()
+
+	origFetch := fetchUpgradeVersions
+	defer func() { fetchUpgradeVersions = origFetch }()
+	published := map[string][]string{
+		"react":  {"18.3.1", "18.2.0"},
+		"vue":    {"3.4.0", "3.3.0"},
+		"jquery": {"3.7.1", "3.6.0", "3.5.1"},
+	}
+	fetchUpgradeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		return published[packageName], published[packageName][0], nil
+	}
+
+	t.Run("unknown library", func(t *testing.T) {
+		if err := upgradeLibraries([]string{"react", "lodash"}); err == nil {
+			t.Error("expected error for a library not in config")
+		}
+		config, _ := loadConfigForUpgrade(configPath)
+		if config.Libraries["react"].Version != "18.2.0" {
+			t.Error("config was written despite the error")
+		}
+	})
+
+	t.Run("latest and specific versions", func(t *testing.T) {
+		if err := upgradeLibraries([]string{"react", "jquery@3.6.0"}); err != nil {
+			t.Fatalf("upgradeLibraries() error = %v", err)
+		}
+
+		config, err := loadConfigForUpgrade(configPath)
+		if err != nil {
+			t.Fatalf("failed to load config: %v", err)
+		}
+		want := map[string]string{"react": "18.3.1", "jquery": "3.6.0", "vue": "3.3.0"}
+		for name, version := range want {
+			if got := config.Libraries[name].Version; got != version {
+				t.Errorf("%s version = %s, want %s", name, got, version)
+			}
+		}
+	})
+}
+
+func TestUpgradeUsesSettingsCDN(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "smartfrontend.yaml")
+	os.WriteFile(configPath, []byte("destination: ./frontend\nlibraries:\n  react:\n    version: 18.2.0\n"), 0644)
+
+	oldConfig, origFetch, origDryRun := FrontendConfig, fetchUpgradeVersions, upgradeDryRun
+	FrontendConfig, upgradeDryRun = configPath, true
+	viper.Set(settingCDN, "jsdelivr")
+	t.Cleanup(func() {
+		FrontendConfig, fetchUpgradeVersions, upgradeDryRun = oldConfig, origFetch, origDryRun
+		viper.Set(settingCDN, nil)
+	})
+
+	// Neither the library nor the config names a CDN, so the setting is used
+	var asked []frontend_config.CDN
+	fetchUpgradeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		asked = append(asked, cdn)
+		if cdn != frontend_config.CDNJsdelivr {
+			return nil, "", fmt.Errorf("unsupported CDN: %s", cdn)
+		}
+		return []string{"18.3.1", "18.2.0"}, "18.3.1", nil
+	}
+
+	if err := upgradeSpecificLibrary("react"); err != nil {
+		t.Errorf("upgradeSpecificLibrary() error = %v", err)
+	}
+	if err := upgradeLibraries(nil); err != nil {
+		t.Errorf("upgradeLibraries() error = %v", err)
+	}
+	for _, cdn := range asked {
+		if cdn != frontend_config.CDNJsdelivr {
+			t.Errorf("versions fetched from %q, want the cdn setting", cdn)
+		}
+	}
+	if len(asked) < 2 {
+		t.Errorf("fetched versions %d times, want once per upgrade", len(asked))
+	}
+}
+
+func TestVersionChangeWarning(t *testing.T) {
+	tests := []struct {
+		current, next string
+		want          string
+	}{
+		{"18.2.0", "18.3.1", ""},
+		{"18.2.0", "17.0.2", "DOWNGRADE"},
+		{"18.3.1", "19.0.0", "MAJOR VERSION"},
+		{"3.0.0-beta.1", "3.0.0", ""},
+		{"^18", "17.0.2", ""},
+		{"18.2.0", "latest", ""},
+	}
+
+	for _, tt := range tests {
+		got := versionChangeWarning("react", tt.current, tt.next)
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("versionChangeWarning(%q, %q) = %q, want it to contain %q", tt.current, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestUpgradeLibrariesDowngradeConfirmation(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "upgrade-downgrade.yaml")
+
+	writeConfig := func() {
+		testConfig := frontend_config.FrontendConfig{
+			Destination: "./frontend",
+			Libraries: map[string]frontend_config.LibraryConfig{
+				"react":  {Version: "18.2.0"},
+				"jquery": {Version: "3.5.1"},
+			},
+		}
+		data, _ := yaml.Marshal(&testConfig)
+		os.WriteFile(configPath, data, 0644)
+	}
+
+	oldConfig := FrontendConfig
+	FrontendConfig = configPath
+	origFetch := fetchUpgradeVersions
+	origInput := confirmationInput
+	origAllow := upgradeAllowDowngrade
+	defer func() {
+		FrontendConfig = oldConfig
+		fetchUpgradeVersions = origFetch
+		confirmationInput = origInput
+		upgradeAllowDowngrade = origAllow
+	}()
+	t.Setenv("SMFAMAN_ASSUME_YES", "")
+
+	published := map[string][]string{
+		"react":  {"18.3.1", "18.2.0", "17.0.2"},
+		"jquery": {"3.7.1", "3.5.1"},
+	}
+	fetchUpgradeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		return published[packageName], published[packageName][0], nil
+	}
+
+	t.Run("declined", func(t *testing.T) {
+		writeConfig()
+		upgradeAllowDowngrade = false
+		confirmationInput = strings.NewReader("n\n")
+
+		if err := upgradeLibraries([]string{"react@17.0.2", "jquery"}); err != nil {
+			t.Fatalf("upgradeLibraries() error = %v", err)
+		}
+		config, _ := loadConfigForUpgrade(configPath)
+		if got := config.Libraries["react"].Version; got != "18.2.0" {
+			t.Errorf("react version = %s, want the downgrade skipped", got)
+		}
+		if got := config.Libraries["jquery"].Version; got != "3.7.1" {
+			t.Errorf("jquery version = %s, want the safe upgrade applied", got)
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		writeConfig()
+		upgradeAllowDowngrade = true
+		confirmationInput = strings.NewReader("")
+
+		if err := upgradeLibraries([]string{"react@17.0.2", "jquery"}); err != nil {
+			t.Fatalf("upgradeLibraries() error = %v", err)
+		}
+		config, _ := loadConfigForUpgrade(configPath)
+		if got := config.Libraries["react"].Version; got != "17.0.2" {
+			t.Errorf("react version = %s, want 17.0.2 with --allow-downgrade", got)
+		}
+	})
+}
+
+func TestFetchUpgradeTargetPrerelease(t *testing.T) {
+	origFetch := fetchUpgradeVersions
+	origInclude := upgradeIncludePrerelease
+	defer func() {
+		fetchUpgradeVersions = origFetch
+		upgradeIncludePrerelease = origInclude
+	}()
+	fetchUpgradeVersions = func(packageName string, cdn frontend_config.CDN) ([]string, string, error) {
+		return []string{"19.0.0-rc.1", "18.3.1", "18.2.0"}, "19.0.0-rc.1", nil
+	}
+
+	upgradeIncludePrerelease = false
+	if _, _, target, _, err := fetchUpgradeTarget("react", "", frontend_config.CDNUnpkg); err != nil || target != "18.3.1" {
+		t.Errorf("expected the stable 18.3.1 by default, got %q (err %v)", target, err)
+	}
+
+	upgradeIncludePrerelease = true
+	if _, _, target, _, err := fetchUpgradeTarget("react", "", frontend_config.CDNUnpkg); err != nil || target != "19.0.0-rc.1" {
+		t.Errorf("expected 19.0.0-rc.1 with --include-prerelease, got %q (err %v)", target, err)
+	}
+}
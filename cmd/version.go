@@ -1,7 +1,112 @@
 package cmd
 
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Build metadata, injected via ldflags (see .goreleaser.yaml)
 var (
 	version = "dev"
 	commit  = "none"
 	date    = "unknown"
 )
+
+var versionJSON bool
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// versionCmd represents the version command
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version and build information",
+	Long: `Print the smfaman version along with the commit, build date, Go version,
+and target platform it was built for.
+
+Examples:
+  smfaman version
+  smfaman version --json
+  smfaman --version`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runVersion(); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Output build information as JSON")
+}
+
+// runVersion executes the version command
+func runVersion() error {
+	info := getBuildInfo()
+
+	if versionJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(info.String())
+	return nil
+}
+
+// getBuildInfo returns the build metadata, falling back to the Go module
+// and VCS information embedded by 'go install' when ldflags were not set
+func getBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "none" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "unknown" {
+				info.Date = setting.Value
+			}
+		}
+	}
+
+	return info
+}
+
+// String formats build information for display
+func (b buildInfo) String() string {
+	return fmt.Sprintf("smfaman %s\n  commit:   %s\n  built:    %s\n  go:       %s\n  platform: %s/%s\n",
+		b.Version, b.Commit, b.Date, b.GoVersion, b.OS, b.Arch)
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+var (
+	versionTitleStyle = lipgloss.NewStyle().
+				Bold(true).
+				MarginLeft(2)
+
+	versionItemStyle = lipgloss.NewStyle().
+				PaddingLeft(4)
+
+	versionSelectedItemStyle = lipgloss.NewStyle().
+					PaddingLeft(2)
+
+	versionLatestItemStyle = lipgloss.NewStyle().
+				PaddingLeft(2).
+				Bold(true)
+
+	versionPaginationStyle = list.DefaultStyles().
+				PaginationStyle.
+				PaddingLeft(4)
+
+	versionHelpStyle = list.DefaultStyles().
+				HelpStyle.
+				PaddingLeft(4).
+				PaddingBottom(1)
+
+	versionQuitTextStyle = lipgloss.NewStyle().Margin(1, 0, 2, 4)
+)
+
+// versionSelectorOptions configure a version selector
+type versionSelectorOptions struct {
+	// ShowPrereleases lists prerelease versions from the start; p toggles them
+	ShowPrereleases bool
+	// Current is the version in use, preselected and marked in the list
+	Current string
+	// Filter narrows the list from the start, like typing it after /
+	Filter string
+}
+
+// versionSelectedMsg ends a version selector that is part of another TUI;
+// version is empty when the selection was cancelled
+type versionSelectedMsg struct {
+	version string
+}
+
+type versionItem struct {
+	version   string
+	isLatest  bool
+	isCurrent bool
+	index     int
+}
+
+func (i versionItem) FilterValue() string { return i.version }
+
+type versionItemDelegate struct{}
+
+func (d versionItemDelegate) Height() int                             { return 1 }
+func (d versionItemDelegate) Spacing() int                            { return 0 }
+func (d versionItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d versionItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(versionItem)
+	if !ok {
+		return
+	}
+
+	str := versionLabel(i.version)
+
+	// Show index number
+	prefix := fmt.Sprintf("%3d. ", i.index+1)
+
+	// Add latest and current markers
+	var markers []string
+	if i.isLatest {
+		prefix = "→  "
+		markers = append(markers, "latest")
+	}
+	if i.isCurrent {
+		markers = append(markers, "current")
+	}
+	if len(markers) > 0 {
+		str = fmt.Sprintf("%s (%s)", str, strings.Join(markers, ", "))
+	}
+
+	style := versionItemStyle
+	if index == m.Index() {
+		style = versionSelectedItemStyle
+		if i.isLatest {
+			style = versionLatestItemStyle
+		}
+	}
+	fmt.Fprint(w, style.Render(prefix+str))
+}
+
+// versionSelectorKeyMap holds the version selector's keys; list navigation
+// keys come from the list itself
+type versionSelectorKeyMap struct {
+	Select      key.Binding
+	Prereleases key.Binding
+	Cancel      key.Binding
+	ForceQuit   key.Binding
+}
+
+var versionSelectorKeys = versionSelectorKeyMap{
+	Select: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "select"),
+	),
+	Prereleases: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "show/hide prereleases"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("q", "esc"),
+		key.WithHelp("q/esc", "cancel"),
+	),
+	ForceQuit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+// versionSelectorModel lists the versions of a package to pick one from. It
+// runs on its own (runVersionSelector) or as part of another TUI (embedded),
+// where it ends with a versionSelectedMsg instead of quitting.
+type versionSelectorModel struct {
+	list          list.Model
+	packageName   string
+	cdn           string
+	latestVersion string
+	versions      []string // Every published version, newest first
+	options       versionSelectorOptions
+	embedded      bool
+	choice        string
+	quitting      bool
+	showHelp      bool
+}
+
+func newVersionSelector(packageName, cdn, latestVersion string, versions []string, options versionSelectorOptions) versionSelectorModel {
+	const defaultWidth = 80
+	const defaultHeight = 20
+
+	l := list.New(nil, versionItemDelegate{}, defaultWidth, defaultHeight)
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	l.Styles.Title = versionTitleStyle
+	l.Styles.PaginationStyle = versionPaginationStyle
+	l.Styles.HelpStyle = versionHelpStyle
+	useHelpOverlay(&l)
+
+	m := versionSelectorModel{
+		list:          l,
+		packageName:   packageName,
+		cdn:           cdn,
+		latestVersion: latestVersion,
+		versions:      versions,
+		options:       options,
+	}
+	m.list.AdditionalShortHelpKeys = func() []key.Binding {
+		if m.hasPrereleases() {
+			return []key.Binding{versionSelectorKeys.Select, versionSelectorKeys.Prereleases}
+		}
+		return []key.Binding{versionSelectorKeys.Select}
+	}
+
+	selected := options.Current
+	if selected == "" {
+		selected = latestVersion
+	}
+	m.setItems(selected)
+	if options.Filter != "" {
+		m.list.SetFilterText(options.Filter)
+		m.selectVersion(selected)
+	}
+	return m
+}
+
+// visibleVersions returns the versions a selector lists: all of them, or
+// the stable ones and current when prereleases are hidden. Packages that
+// only publish prereleases list them all.
+func visibleVersions(versions []string, showPrereleases bool, current string) []string {
+	if showPrereleases {
+		return versions
+	}
+	var visible []string
+	for _, v := range versions {
+		if !frontend_mgr.IsPrerelease(v) || frontend_mgr.SameVersion(v, current) {
+			visible = append(visible, v)
+		}
+	}
+	if len(visible) == 0 {
+		return versions
+	}
+	return visible
+}
+
+// hasPrereleases reports whether any listed or hidden version is a prerelease
+func (m versionSelectorModel) hasPrereleases() bool {
+	for _, v := range m.versions {
+		if frontend_mgr.IsPrerelease(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// setItems fills the list with the visible versions and selects selected
+func (m *versionSelectorModel) setItems(selected string) {
+	visible := visibleVersions(m.versions, m.options.ShowPrereleases, m.options.Current)
+	items := make([]list.Item, len(visible))
+	for i, v := range visible {
+		items[i] = versionItem{
+			version:   v,
+			isLatest:  frontend_mgr.SameVersion(v, m.latestVersion),
+			isCurrent: m.options.Current != "" && frontend_mgr.SameVersion(v, m.options.Current),
+			index:     i,
+		}
+	}
+	m.list.SetItems(items)
+	if m.list.FilterState() != list.Unfiltered {
+		// SetItems filters in the background; filter right away instead
+		m.list.SetFilterText(m.list.FilterValue())
+	}
+
+	m.list.Title = fmt.Sprintf("Select a version for %s (from %s)", m.packageName, m.cdn)
+	if hidden := len(m.versions) - len(visible); hidden > 0 {
+		m.list.Title += fmt.Sprintf(" · %d %s hidden", hidden, pluralize(hidden, "prerelease", "prereleases"))
+	}
+	m.selectVersion(selected)
+}
+
+// selectVersion moves the cursor to version when it is listed
+func (m *versionSelectorModel) selectVersion(version string) {
+	for i, listItem := range m.list.VisibleItems() {
+		if item, ok := listItem.(versionItem); ok && frontend_mgr.SameVersion(item.version, version) {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// selectedVersion returns the version under the cursor, or ""
+func (m versionSelectorModel) selectedVersion() string {
+	if item, ok := m.list.SelectedItem().(versionItem); ok {
+		return item.version
+	}
+	return ""
+}
+
+// finish ends the selection with version, or cancels it when version is ""
+func (m versionSelectorModel) finish(version string) (tea.Model, tea.Cmd) {
+	if m.embedded {
+		return m, func() tea.Msg { return versionSelectedMsg{version: version} }
+	}
+	m.choice = version
+	m.quitting = version == ""
+	return m, tea.Quit
+}
+
+func (m versionSelectorModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m versionSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// The page size changes with the height, so keep the selection
+		selected := m.selectedVersion()
+		m.list.SetWidth(msg.Width)
+		m.list.SetHeight(msg.Height - 4)
+		m.selectVersion(selected)
+		return m, nil
+
+	case tea.MouseMsg:
+		updateListMouse(&m.list, versionItemDelegate{}, listViewTop, msg)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.showHelp {
+			if key.Matches(msg, versionSelectorKeys.ForceQuit) {
+				m.quitting = true
+				return m, tea.Quit
+			}
+			m.showHelp = !helpClosed(msg)
+			return m, nil
+		}
+
+		filtering := m.list.FilterState() == list.Filtering
+		switch {
+		case helpRequested(msg, filtering):
+			m.showHelp = true
+			return m, nil
+
+		case key.Matches(msg, versionSelectorKeys.ForceQuit):
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, versionSelectorKeys.Select):
+			if version := m.selectedVersion(); version != "" {
+				return m.finish(version)
+			}
+			return m, nil
+
+		case !filtering && key.Matches(msg, versionSelectorKeys.Prereleases) && m.hasPrereleases():
+			m.options.ShowPrereleases = !m.options.ShowPrereleases
+			m.setItems(m.selectedVersion())
+			return m, nil
+
+		case key.Matches(msg, versionSelectorKeys.Cancel):
+			return m.finish("")
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m versionSelectorModel) View() string {
+	if m.choice != "" {
+		return versionQuitTextStyle.Render(fmt.Sprintf("Selected: %s@%s\n", m.packageName, m.choice))
+	}
+	if m.quitting {
+		return versionQuitTextStyle.Render("Cancelled.\n")
+	}
+	if m.showHelp {
+		return renderHelpOverlay("version selector", append(m.helpSections(), generalHelpSection(versionSelectorKeys.ForceQuit))...)
+	}
+	return "\n" + m.list.View()
+}
+
+// helpSections lists the version selector's keys for the help overlay
+func (m versionSelectorModel) helpSections() []helpSection {
+	bindings := []key.Binding{versionSelectorKeys.Select}
+	if m.hasPrereleases() {
+		bindings = append(bindings, versionSelectorKeys.Prereleases)
+	}
+	return []helpSection{
+		{title: "Versions", bindings: append(bindings, versionSelectorKeys.Cancel)},
+		listHelpSection(m.list),
+	}
+}
+
+// runVersionSelector lets the user pick one of versions (newest first) and
+// returns it, or "" when cancelled
+func runVersionSelector(packageName, cdn, latestVersion string, versions []string, options versionSelectorOptions) (string, error) {
+	if accessibleEnabled() {
+		visible := visibleVersions(versions, options.ShowPrereleases, options.Current)
+		return runAccessibleVersionSelect(confirmationInput, os.Stdout, packageName, cdn, latestVersion, visible)
+	}
+
+	m := newVersionSelector(packageName, cdn, latestVersion, versions, options)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return "", fmt.Errorf("error running interactive mode: %w", err)
+	}
+
+	if m, ok := finalModel.(versionSelectorModel); ok {
+		return m.choice, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVersionSelectorPrereleases(t *testing.T) {
+	versions := []string{"4.0.0-beta.1", "3.7.1", "3.7.0", "3.6.0-rc.1", "3.6.0"}
+	var model tea.Model = newVersionSelector("jquery", "unpkg", "3.7.1", versions, versionSelectorOptions{Current: "3.7.0"})
+	send := func(msg tea.Msg) versionSelectorModel {
+		t.Helper()
+		model, _ = model.Update(msg)
+		return model.(versionSelectorModel)
+	}
+
+	m := model.(versionSelectorModel)
+	if got := len(m.list.Items()); got != 3 || !strings.Contains(m.list.Title, "2 prereleases hidden") {
+		t.Errorf("listed %d versions with title %q, want the 3 stable ones", got, m.list.Title)
+	}
+	if m.selectedVersion() != "3.7.0" || !strings.Contains(m.View(), "3.7.0 (current)") {
+		t.Errorf("selected %q, want the current version 3.7.0", m.selectedVersion())
+	}
+
+	// Showing prereleases keeps the selection
+	m = send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if got := len(m.list.Items()); got != 5 || m.selectedVersion() != "3.7.0" {
+		t.Errorf("after p: listed %d versions, selected %q", got, m.selectedVersion())
+	}
+
+	m = send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.choice != "3.7.0" {
+		t.Errorf("choice = %q, want 3.7.0", m.choice)
+	}
+
+	// A prerelease in use stays listed
+	if got := visibleVersions(versions, false, "3.6.0-rc.1"); len(got) != 4 {
+		t.Errorf("visibleVersions() = %v, want the stable versions and 3.6.0-rc.1", got)
+	}
+	if got := visibleVersions([]string{"1.0.0-alpha.2", "1.0.0-alpha.1"}, false, ""); len(got) != 2 {
+		t.Errorf("visibleVersions() = %v, want every version of a prerelease-only package", got)
+	}
+}
+
+func TestVersionSelectorFilterAndResize(t *testing.T) {
+	var versions []string
+	for i := 40; i > 0; i-- {
+		versions = append(versions, fmt.Sprintf("1.%d.0", i))
+	}
+
+	m := newVersionSelector("pkg", "unpkg", "1.40.0", versions, versionSelectorOptions{Filter: "1.3"})
+	for _, item := range m.list.VisibleItems() {
+		if v := item.(versionItem).version; !strings.Contains(v, "3") {
+			t.Errorf("filter 1.3 lists %s", v)
+		}
+	}
+
+	m = newVersionSelector("pkg", "unpkg", "1.40.0", versions, versionSelectorOptions{Current: "1.5.0"})
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 12})
+	if got := model.(versionSelectorModel).selectedVersion(); got != "1.5.0" {
+		t.Errorf("after resizing, selected %q, want 1.5.0", got)
+	}
+}
+
+func TestVersionSelectorEmbedded(t *testing.T) {
+	m := newVersionSelector("jquery", "unpkg", "3.7.1", []string{"3.7.1", "3.7.0"}, versionSelectorOptions{})
+	m.embedded = true
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(versionSelectedMsg); !ok || msg.version != "3.7.0" {
+		t.Errorf("enter sent %#v, want version 3.7.0", cmd())
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if msg, ok := cmd().(versionSelectedMsg); !ok || msg.version != "" {
+		t.Errorf("esc sent %#v, want an empty version", cmd())
+	}
+}
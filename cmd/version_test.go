@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGetBuildInfoUsesInjectedValues(t *testing.T) {
+	oldVersion, oldCommit, oldDate := version, commit, date
+	version, commit, date = "1.2.3", "abc1234", "2025-01-02T03:04:05Z"
+	defer func() { version, commit, date = oldVersion, oldCommit, oldDate }()
+
+	info := getBuildInfo()
+
+	if info.Version != "1.2.3" || info.Commit != "abc1234" || info.Date != "2025-01-02T03:04:05Z" {
+		t.Errorf("expected injected values, got %+v", info)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("expected Go version %q, got %q", runtime.Version(), info.GoVersion)
+	}
+	if info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("expected platform %s/%s, got %s/%s", runtime.GOOS, runtime.GOARCH, info.OS, info.Arch)
+	}
+}
+
+func TestBuildInfoString(t *testing.T) {
+	info := buildInfo{Version: "1.2.3", Commit: "abc1234", Date: "today", GoVersion: "go1.25.4", OS: "linux", Arch: "amd64"}
+	output := info.String()
+
+	for _, want := range []string{"smfaman 1.2.3", "abc1234", "today", "go1.25.4", "linux/amd64"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
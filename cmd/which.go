@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var whichJSON bool
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which <local-path>",
+	Short: "Show which CDN URL a vendored file came from",
+	Long: `Show the provenance of a vendored file.
+
+Every file downloaded by sync or apply is recorded in the lockfile next to the
+frontend configuration (e.g. smartfrontend.lock.json), including the library,
+version, CDN, exact URL, and integrity hash. This command looks up a local
+file in that lockfile.
+
+Examples:
+  smfaman which public/libs/jquery/dist/jquery.min.js
+  smfaman which public/libs/jquery/dist/jquery.min.js --json
+  smfaman -f myproject.yaml which ./vendor/app.css`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runWhich(args[0]); err != nil {
+			exitWithError(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	whichCmd.Flags().BoolVar(&whichJSON, "json", false, "Output provenance as JSON")
+}
+
+// runWhich executes the which command
+func runWhich(localPath string) error {
+	entry, err := lookupProvenance(FrontendConfig, localPath)
+	if err != nil {
+		return err
+	}
+
+	if whichJSON {
+		data, err := json.MarshalIndent(entry, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("File:       %s\n", localPath)
+	fmt.Printf("Library:    %s@%s\n", entry.Library, entry.Version)
+	fmt.Printf("CDN:        %s\n", entry.CDN)
+	fmt.Printf("CDN path:   %s\n", entry.File)
+	fmt.Printf("URL:        %s\n", entry.URL)
+	if entry.Integrity != "" {
+		fmt.Printf("Integrity:  %s\n", entry.Integrity)
+	}
+	fmt.Printf("Downloaded: %s\n", entry.DownloadedAt.Local().Format(time.DateTime))
+
+	if _, err := os.Stat(localPath); os.IsNotExist(err) {
+		fmt.Println("\nNote: the file no longer exists on disk.")
+	}
+
+	return nil
+}
+
+// lookupProvenance finds the manifest entry for a local file
+func lookupProvenance(configPath, localPath string) (*manifestEntry, error) {
+	manifestPath := manifestPathForConfig(configPath)
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no lockfile found at %s (run 'smfaman sync' first)", manifestPath)
+	}
+
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := manifestKey(manifestPath, localPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := manifest.Files[key]
+	if !ok {
+		return nil, fmt.Errorf("no provenance recorded for %s", localPath)
+	}
+
+	return &entry, nil
+}
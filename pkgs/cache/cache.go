@@ -13,14 +13,22 @@ const (
 	// DefaultTTL is the default time-to-live for cache entries (24 hours)
 	DefaultTTL = 24 * time.Hour
 
-	// CacheDirName is the name of the cache directory
-	CacheDirName = ".smfaman-cache"
+	// CacheDirName is the name of the cache directory inside the user cache
+	// directory (os.UserCacheDir, e.g. $XDG_CACHE_HOME)
+	CacheDirName = "smfaman"
+
+	// LegacyCacheDirName is the cache directory in the home directory used by
+	// earlier versions; it is moved to the new location on first use
+	LegacyCacheDirName = ".smfaman-cache"
 
 	// MetadataDirName is the subdirectory for metadata cache
 	MetadataDirName = "metadata"
 
 	// PackagesDirName is the subdirectory for package file cache
 	PackagesDirName = "packages"
+
+	// HashesDirName is the subdirectory for package file hashes
+	HashesDirName = "hashes"
 )
 
 // Entry represents a cached CDN response
@@ -36,9 +44,11 @@ type Manager struct {
 	cacheDir     string
 	metadataDir  string
 	packagesDir  string
+	hashesDir    string
 	ttl          time.Duration
 	enabled      bool
 	packageCache bool
+	refresh      bool
 }
 
 // NewManager creates a new cache manager
@@ -51,11 +61,15 @@ func NewManager(enabled bool, ttl time.Duration) (*Manager, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cache directory: %w", err)
 	}
+	if enabled {
+		cacheDir = migrateLegacyCache(cacheDir)
+	}
 
 	m := &Manager{
 		cacheDir:     cacheDir,
 		metadataDir:  filepath.Join(cacheDir, MetadataDirName),
 		packagesDir:  filepath.Join(cacheDir, PackagesDirName),
+		hashesDir:    filepath.Join(cacheDir, HashesDirName),
 		ttl:          ttl,
 		enabled:      enabled,
 		packageCache: true, // Enable package caching by default
@@ -79,9 +93,22 @@ func (m *Manager) SetPackageCacheEnabled(enabled bool) {
 	m.packageCache = enabled
 }
 
+// SetTTL sets the time-to-live for new metadata entries
+func (m *Manager) SetTTL(ttl time.Duration) {
+	if ttl > 0 {
+		m.ttl = ttl
+	}
+}
+
+// SetRefresh makes metadata reads miss so fresh data is fetched and written
+// back to the cache
+func (m *Manager) SetRefresh(refresh bool) {
+	m.refresh = refresh
+}
+
 // Get retrieves a cached entry if it exists and is not expired
 func (m *Manager) Get(key string, result interface{}) (bool, error) {
-	if !m.enabled {
+	if !m.enabled || m.refresh {
 		return false, nil
 	}
 
@@ -98,9 +125,10 @@ func (m *Manager) Get(key string, result interface{}) (bool, error) {
 		return false, fmt.Errorf("failed to read cache file: %w", err)
 	}
 
-	// Parse cache entry
+	// Parse cache entry, dropping corrupt (e.g., truncated) entries
 	var entry Entry
 	if err := json.Unmarshal(data, &entry); err != nil {
+		os.Remove(filePath)
 		return false, fmt.Errorf("failed to unmarshal cache entry: %w", err)
 	}
 
@@ -119,8 +147,18 @@ func (m *Manager) Get(key string, result interface{}) (bool, error) {
 	return true, nil
 }
 
+// Enabled reports whether the cache is in use
+func (m *Manager) Enabled() bool {
+	return m.enabled
+}
+
 // Set stores data in the cache
 func (m *Manager) Set(key string, data interface{}) error {
+	return m.SetWithTTL(key, data, m.ttl)
+}
+
+// SetWithTTL stores data in the cache with its own time-to-live
+func (m *Manager) SetWithTTL(key string, data interface{}, ttl time.Duration) error {
 	if !m.enabled {
 		return nil
 	}
@@ -136,7 +174,7 @@ func (m *Manager) Set(key string, data interface{}) error {
 		Key:       key,
 		Data:      dataBytes,
 		Timestamp: time.Now(),
-		TTL:       m.ttl,
+		TTL:       ttl,
 	}
 
 	// Marshal entry
@@ -182,10 +220,13 @@ func (m *Manager) ClearPackages() error {
 		return nil
 	}
 
-	// Remove packages directory
+	// Remove packages and hashes directories
 	if err := os.RemoveAll(m.packagesDir); err != nil {
 		return fmt.Errorf("failed to clear package cache: %w", err)
 	}
+	if err := os.RemoveAll(m.hashesDir); err != nil {
+		return fmt.Errorf("failed to clear package hashes: %w", err)
+	}
 
 	// Recreate packages directory
 	if err := os.MkdirAll(m.packagesDir, 0755); err != nil {
@@ -365,6 +406,21 @@ func (m *Manager) GetPackageFile(cdn, library, version, filePath string) ([]byte
 	return data, true, nil
 }
 
+// PackageFilePath returns the location of a cached package file
+// Returns the path and whether the file is present in the cache
+func (m *Manager) PackageFilePath(cdn, library, version, filePath string) (string, bool) {
+	if !m.enabled || !m.packageCache {
+		return "", false
+	}
+
+	cachePath := m.getPackageFilePath(cdn, library, version, filePath)
+	if info, err := os.Stat(cachePath); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+
+	return cachePath, true
+}
+
 // SetPackageFile stores a package file in the cache
 func (m *Manager) SetPackageFile(cdn, library, version, filePath string, data []byte) error {
 	if !m.enabled || !m.packageCache {
@@ -384,17 +440,48 @@ func (m *Manager) SetPackageFile(cdn, library, version, filePath string, data []
 		return fmt.Errorf("failed to write package file to cache: %w", err)
 	}
 
+	// Record hash for later verification
+	if err := m.writePackageHash(cdn, library, version, filePath, data); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // getCacheDir returns the cache directory path
 func getCacheDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	userCacheDir, err := os.UserCacheDir()
 	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(homeDir, CacheDirName), nil
+	return filepath.Join(userCacheDir, CacheDirName), nil
+}
+
+// migrateLegacyCache moves a cache left in ~/.smfaman-cache by earlier
+// versions to cacheDir and returns the directory to use. If the move fails
+// (e.g. across file systems) the legacy cache keeps being used.
+func migrateLegacyCache(cacheDir string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return cacheDir
+	}
+	legacyDir := filepath.Join(homeDir, LegacyCacheDirName)
+
+	if _, err := os.Stat(legacyDir); err != nil {
+		return cacheDir
+	}
+	if _, err := os.Stat(cacheDir); err == nil {
+		return cacheDir // Already migrated, or both exist: prefer the new one
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err != nil {
+		return legacyDir
+	}
+	if err := os.Rename(legacyDir, cacheDir); err != nil {
+		return legacyDir
+	}
+	return cacheDir
 }
 
 // GenerateKey generates a cache key from components
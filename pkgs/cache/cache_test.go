@@ -257,10 +257,96 @@ func TestGetCacheDir(t *testing.T) {
 		t.Error("expected absolute path")
 	}
 
-	homeDir, _ := os.UserHomeDir()
-	expectedDir := filepath.Join(homeDir, CacheDirName)
+	userCacheDir, _ := os.UserCacheDir()
+	expectedDir := filepath.Join(userCacheDir, CacheDirName)
 
 	if dir != expectedDir {
 		t.Errorf("expected cache dir %q, got %q", expectedDir, dir)
 	}
 }
+
+func TestMigrateLegacyCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cacheDir := filepath.Join(home, ".cache", CacheDirName)
+
+	legacyFile := filepath.Join(home, LegacyCacheDirName, PackagesDirName, "unpkg", "react.js")
+	if err := os.MkdirAll(filepath.Dir(legacyFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacyFile, []byte("cached"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := migrateLegacyCache(cacheDir); got != cacheDir {
+		t.Fatalf("migrateLegacyCache() = %q, want %q", got, cacheDir)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, PackagesDirName, "unpkg", "react.js")); err != nil {
+		t.Errorf("cached file not moved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, LegacyCacheDirName)); !os.IsNotExist(err) {
+		t.Errorf("legacy cache still exists: %v", err)
+	}
+
+	// Nothing left to migrate
+	if got := migrateLegacyCache(cacheDir); got != cacheDir {
+		t.Errorf("second migrateLegacyCache() = %q, want %q", got, cacheDir)
+	}
+}
+
+func TestCacheRefresh(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	manager, err := NewManager(true, DefaultTTL)
+	if err != nil {
+		t.Fatalf("failed to create cache manager: %v", err)
+	}
+
+	if err := manager.Set("key", "old"); err != nil {
+		t.Fatalf("failed to set cache entry: %v", err)
+	}
+
+	manager.SetRefresh(true)
+
+	var result string
+	if found, _ := manager.Get("key", &result); found {
+		t.Error("expected refresh to bypass cache reads")
+	}
+
+	// Writes still go through while refreshing
+	if err := manager.Set("key", "new"); err != nil {
+		t.Fatalf("failed to set cache entry: %v", err)
+	}
+
+	manager.SetRefresh(false)
+	if found, _ := manager.Get("key", &result); !found || result != "new" {
+		t.Errorf("expected refreshed value %q, got %q (found %v)", "new", result, found)
+	}
+}
+
+func TestCacheSetWithTTL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	manager, err := NewManager(true, DefaultTTL)
+	if err != nil {
+		t.Fatalf("failed to create cache manager: %v", err)
+	}
+
+	if err := manager.SetWithTTL("short", "data", time.Nanosecond); err != nil {
+		t.Fatalf("failed to set cache entry: %v", err)
+	}
+	if err := manager.SetWithTTL("long", "data", time.Hour); err != nil {
+		t.Fatalf("failed to set cache entry: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	var result string
+	if found, _ := manager.Get("short", &result); found {
+		t.Error("expected the entry with a short TTL to have expired")
+	}
+	if found, _ := manager.Get("long", &result); !found {
+		t.Error("expected the entry with a long TTL to be found")
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"os"
 	"testing"
 )
 
@@ -63,6 +64,25 @@ func TestPackageCache(t *testing.T) {
 		}
 	})
 
+	// Test PackageFilePath locates the stored file
+	t.Run("PackageFilePath", func(t *testing.T) {
+		path, found := manager.PackageFilePath(cdn, library, version, filePath)
+		if !found {
+			t.Fatal("Expected cached file path to be found")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read cached file: %v", err)
+		}
+		if string(data) != string(fileData) {
+			t.Errorf("Cached file content doesn't match. Got %q, want %q", string(data), string(fileData))
+		}
+
+		if _, found := manager.PackageFilePath(cdn, library, version, "nonexistent.js"); found {
+			t.Error("Expected non-existent file not to be found")
+		}
+	})
+
 	// Test Stats includes package cache
 	t.Run("Stats", func(t *testing.T) {
 		stats, err := manager.Stats()
@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageFileRef identifies a file in the package cache
+type PackageFileRef struct {
+	CDN      string
+	Library  string
+	Version  string
+	FilePath string
+}
+
+// VerifyResult summarizes a cache verification run
+type VerifyResult struct {
+	MetadataChecked    int
+	MetadataRemoved    int
+	PackagesChecked    int
+	PackagesUnverified int              // Files without a stored hash
+	PackagesCorrupt    []PackageFileRef // Files whose content no longer matches their hash (removed)
+}
+
+// getPackageHashPath returns the hash file path for a package cache entry
+func (m *Manager) getPackageHashPath(cdn, library, version, filePath string) string {
+	return filepath.Join(m.hashesDir, cdn, library, version, filePath+".sha256")
+}
+
+// writePackageHash records the SHA-256 hash of a package file
+func (m *Manager) writePackageHash(cdn, library, version, filePath string, data []byte) error {
+	hashPath := m.getPackageHashPath(cdn, library, version, filePath)
+
+	if err := os.MkdirAll(filepath.Dir(hashPath), 0755); err != nil {
+		return fmt.Errorf("failed to create hash directory: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if err := os.WriteFile(hashPath, []byte(hex.EncodeToString(sum[:])), 0644); err != nil {
+		return fmt.Errorf("failed to write package file hash: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks every metadata entry and package file, removing metadata
+// entries that can't be parsed and package files that fail their hash check
+func (m *Manager) Verify() (VerifyResult, error) {
+	var result VerifyResult
+	if !m.enabled {
+		return result, nil
+	}
+
+	if err := m.verifyMetadata(&result); err != nil {
+		return result, err
+	}
+	if err := m.verifyPackages(&result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// verifyMetadata removes metadata entries that fail to parse
+func (m *Manager) verifyMetadata(result *VerifyResult) error {
+	entries, err := os.ReadDir(m.metadataDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metadata cache directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		result.MetadataChecked++
+		filePath := filepath.Join(m.metadataDir, entry.Name())
+
+		data, err := os.ReadFile(filePath)
+		var cacheEntry Entry
+		if err == nil {
+			err = json.Unmarshal(data, &cacheEntry)
+		}
+		if err == nil && cacheEntry.Key == "" {
+			err = fmt.Errorf("missing cache key")
+		}
+
+		if err != nil {
+			if err := os.Remove(filePath); err != nil {
+				return fmt.Errorf("failed to remove corrupt cache entry: %w", err)
+			}
+			result.MetadataRemoved++
+		}
+	}
+
+	return nil
+}
+
+// verifyPackages removes package files whose content doesn't match their stored hash
+func (m *Manager) verifyPackages(result *VerifyResult) error {
+	err := filepath.WalkDir(m.packagesDir, func(path string, d os.DirEntry, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(m.packagesDir, path)
+		if err != nil {
+			return err
+		}
+		ref, ok := parsePackageFileRef(filepath.ToSlash(rel))
+		if !ok {
+			return nil
+		}
+
+		result.PackagesChecked++
+
+		expected, err := os.ReadFile(m.getPackageHashPath(ref.CDN, ref.Library, ref.Version, ref.FilePath))
+		if err != nil {
+			result.PackagesUnverified++
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err == nil {
+			sum := sha256.Sum256(data)
+			if hex.EncodeToString(sum[:]) == strings.TrimSpace(string(expected)) {
+				return nil
+			}
+		}
+
+		// Corrupt: drop the file and its hash
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove corrupt package file: %w", err)
+		}
+		os.Remove(m.getPackageHashPath(ref.CDN, ref.Library, ref.Version, ref.FilePath))
+		result.PackagesCorrupt = append(result.PackagesCorrupt, ref)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk package cache directory: %w", err)
+	}
+
+	return nil
+}
+
+// parsePackageFileRef splits a "{cdn}/{library}/{version}/{filepath}" path,
+// where scoped libraries span two segments ("@scope/name")
+func parsePackageFileRef(rel string) (PackageFileRef, bool) {
+	parts := strings.Split(rel, "/")
+
+	libraryParts := 1
+	if len(parts) > 1 && strings.HasPrefix(parts[1], "@") {
+		libraryParts = 2
+	}
+	if len(parts) < 3+libraryParts {
+		return PackageFileRef{}, false
+	}
+
+	return PackageFileRef{
+		CDN:      parts[0],
+		Library:  strings.Join(parts[1:1+libraryParts], "/"),
+		Version:  parts[1+libraryParts],
+		FilePath: strings.Join(parts[2+libraryParts:], "/"),
+	}, true
+}
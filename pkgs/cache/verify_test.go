@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	manager, err := NewManager(true, DefaultTTL)
+	if err != nil {
+		t.Fatalf("failed to create cache manager: %v", err)
+	}
+
+	// Metadata: one valid entry, one truncated entry
+	if err := manager.Set("good", map[string]string{"name": "react"}); err != nil {
+		t.Fatalf("failed to set cache entry: %v", err)
+	}
+	if err := manager.Set("bad", map[string]string{"name": "vue"}); err != nil {
+		t.Fatalf("failed to set cache entry: %v", err)
+	}
+	badPath := manager.getFilePath("bad")
+	if err := os.WriteFile(badPath, []byte(`{"key":"bad","da`), 0644); err != nil {
+		t.Fatalf("failed to truncate cache entry: %v", err)
+	}
+
+	// Packages: one intact, one corrupted, one without a hash
+	if err := manager.SetPackageFile("unpkg", "react", "18.2.0", "umd/react.js", []byte("react")); err != nil {
+		t.Fatalf("SetPackageFile failed: %v", err)
+	}
+	if err := manager.SetPackageFile("unpkg", "@scope/pkg", "1.0.0", "dist/pkg.js", []byte("pkg")); err != nil {
+		t.Fatalf("SetPackageFile failed: %v", err)
+	}
+	corruptPath := manager.getPackageFilePath("unpkg", "@scope/pkg", "1.0.0", "dist/pkg.js")
+	if err := os.WriteFile(corruptPath, []byte("pk"), 0644); err != nil {
+		t.Fatalf("failed to corrupt package file: %v", err)
+	}
+	legacyPath := manager.getPackageFilePath("cdnjs", "vue", "3.0.0", "vue.js")
+	os.MkdirAll(filepath.Dir(legacyPath), 0755)
+	if err := os.WriteFile(legacyPath, []byte("vue"), 0644); err != nil {
+		t.Fatalf("failed to write package file: %v", err)
+	}
+
+	result, err := manager.Verify()
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	if result.MetadataChecked != 2 || result.MetadataRemoved != 1 {
+		t.Errorf("expected 2 metadata entries checked and 1 removed, got %+v", result)
+	}
+	if _, err := os.Stat(badPath); !os.IsNotExist(err) {
+		t.Error("expected corrupt metadata entry to be removed")
+	}
+
+	if result.PackagesChecked != 3 || result.PackagesUnverified != 1 {
+		t.Errorf("expected 3 package files checked and 1 unverified, got %+v", result)
+	}
+	expected := PackageFileRef{CDN: "unpkg", Library: "@scope/pkg", Version: "1.0.0", FilePath: "dist/pkg.js"}
+	if len(result.PackagesCorrupt) != 1 || result.PackagesCorrupt[0] != expected {
+		t.Errorf("expected corrupt %+v, got %+v", expected, result.PackagesCorrupt)
+	}
+	if _, err := os.Stat(corruptPath); !os.IsNotExist(err) {
+		t.Error("expected corrupt package file to be removed")
+	}
+	if _, found, _ := manager.GetPackageFile("unpkg", "react", "18.2.0", "umd/react.js"); !found {
+		t.Error("expected intact package file to be kept")
+	}
+}
+
+func TestGetRemovesCorruptEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	manager, err := NewManager(true, DefaultTTL)
+	if err != nil {
+		t.Fatalf("failed to create cache manager: %v", err)
+	}
+
+	path := manager.getFilePath("key")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatalf("failed to write cache entry: %v", err)
+	}
+
+	var result map[string]string
+	if found, _ := manager.Get("key", &result); found {
+		t.Error("expected corrupt entry to be a cache miss")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected corrupt entry to be removed")
+	}
+}
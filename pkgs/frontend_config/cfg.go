@@ -2,7 +2,9 @@ package frontend_config
 
 import (
 	"fmt"
+	"net"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -20,10 +22,80 @@ const (
 	CDNJsdelivr CDN = "jsdelivr"
 )
 
+// SourceMaps controls how sourcemap (.map) files are handled during sync
+type SourceMaps string
+
+const (
+	// SourceMapsInclude adds the .map file for every selected JS/CSS file
+	SourceMapsInclude SourceMaps = "include"
+
+	// SourceMapsExclude drops .map files and strips sourceMappingURL comments
+	SourceMapsExclude SourceMaps = "exclude"
+)
+
+// Profile selects which file variants are used for libraries
+type Profile string
+
+const (
+	// ProfileDev selects development files (e.g., unminified sources and sourcemaps)
+	ProfileDev Profile = "dev"
+
+	// ProfileProd selects production files (e.g., minified builds only)
+	ProfileProd Profile = "prod"
+)
+
+// FilesMode controls which files are downloaded when a library has no file list
+type FilesMode string
+
+const (
+	// FilesModeEntrypoints downloads only the package's browser entry files
+	FilesModeEntrypoints FilesMode = "entrypoints"
+
+	// FilesModeAll downloads every file in the package
+	FilesModeAll FilesMode = "all"
+
+	// FilesModeMinified downloads only the package's minified JS and CSS files
+	FilesModeMinified FilesMode = "minified"
+)
+
+// LinkMode controls how files from the package store are placed into destinations
+type LinkMode string
+
+const (
+	// LinkModeCopy writes an independent copy of each file (default)
+	LinkModeCopy LinkMode = "copy"
+
+	// LinkModeSymlink creates symbolic links into the package store
+	LinkModeSymlink LinkMode = "symlink"
+
+	// LinkModeHardlink creates hard links to the package store files
+	LinkModeHardlink LinkMode = "hardlink"
+)
+
+// CaseCollisions controls what sync does with files of a library whose paths
+// differ only by case, which overwrite each other on case-insensitive
+// filesystems (macOS, Windows)
+type CaseCollisions string
+
+const (
+	// CaseCollisionsRename saves later files under a numbered name (readme~2.md) (default)
+	CaseCollisionsRename CaseCollisions = "rename"
+
+	// CaseCollisionsSkip keeps only the first file
+	CaseCollisionsSkip CaseCollisions = "skip"
+
+	// CaseCollisionsError stops the sync
+	CaseCollisionsError CaseCollisions = "error"
+)
+
 // FrontendConfig represents the top-level configuration for frontend asset management
 type FrontendConfig struct {
+	// Requires is the range of smfaman versions the config needs (e.g.,
+	// ">=0.5.0"), checked when it is loaded
+	Requires string `yaml:"requires,omitempty"`
+
 	// Destination is the output path template for downloaded libraries
-	// Supports {library_name} placeholder (e.g., "./frontend/{library_name}")
+	// Supports {library_name} and {version} placeholders (e.g., "./frontend/{library_name}")
 	Destination string `yaml:"destination"`
 
 	// ProjectName is an identifier for the project
@@ -34,9 +106,62 @@ type FrontendConfig struct {
 	// Individual libraries can override this with their own CDN setting
 	CDN CDN `yaml:"cdn,omitempty"`
 
+	// Profile is the active file profile: "dev" or "prod"
+	// If empty, the dev profile is used
+	Profile Profile `yaml:"profile,omitempty"`
+
+	// SourceMaps specifies the default sourcemap handling for all libraries
+	// Valid values: "include", "exclude"
+	// If empty, .map files are only downloaded when selected by the file list
+	SourceMaps SourceMaps `yaml:"sourcemaps,omitempty"`
+
+	// FilesMode specifies which files are downloaded for libraries without a file list
+	// Valid values: "entrypoints", "all", "minified"
+	// If empty, only entry files are downloaded
+	FilesMode FilesMode `yaml:"files_mode,omitempty"`
+
+	// LinkMode specifies how synced files are placed into destinations
+	// Valid values: "copy", "symlink", "hardlink"
+	// If empty, files are copied
+	LinkMode LinkMode `yaml:"link_mode,omitempty"`
+
+	// CaseCollisions specifies how files differing only by case are handled
+	// Valid values: "rename", "skip", "error"
+	// If empty, later files are renamed
+	CaseCollisions CaseCollisions `yaml:"case_collisions,omitempty"`
+
+	// Mirrors lists additional output path templates that every library is
+	// copied to after download (e.g., "./docs/static/vendor/{library_name}")
+	Mirrors []string `yaml:"mirrors,omitempty"`
+
+	// Headers are extra HTTP headers sent with file downloads from a host
+	// (e.g. an API key for a mirror); values may refer to environment
+	// variables as $VAR or ${VAR}
+	Headers HostValues `yaml:"headers,omitempty"`
+
+	// Query holds extra query parameters added to file download URLs on a
+	// host, with the same environment variable references as Headers
+	Query HostValues `yaml:"query,omitempty"`
+
+	// Concurrency is the number of parallel CDN requests for this project
+	// If 0, the concurrency setting is used
+	Concurrency int `yaml:"concurrency,omitempty"`
+
+	// Lockfile controls whether sync records downloaded files in the
+	// <config>.lock.json lockfile
+	// If unset, the lockfile is written
+	Lockfile *bool `yaml:"lockfile,omitempty"`
+
 	// Libraries is a map where the key is the library name (e.g., "jquery", "bootstrap")
 	// and the value contains the library configuration
 	Libraries map[string]LibraryConfig `yaml:"libraries"`
+
+	// DevLibraries are development-only libraries (live-reload scripts, debug
+	// builds) that are only synced when asked for and never with the prod profile
+	DevLibraries map[string]LibraryConfig `yaml:"dev_libraries,omitempty"`
+
+	// libraryOrder is the order libraries were read in, kept when saving
+	libraryOrder []string
 }
 
 // LibraryConfig represents configuration for a single library
@@ -49,12 +174,48 @@ type LibraryConfig struct {
 	CDN CDN `yaml:"cdn,omitempty"`
 
 	// Files specifies which files to download from the library
-	// If empty, all files or a default set will be downloaded
+	// If empty, the files are selected by FilesMode
 	Files []string `yaml:"files,omitempty"`
 
+	// FilesMode overrides the global FilesMode for this library
+	FilesMode FilesMode `yaml:"files_mode,omitempty"`
+
+	// FilesDev overrides Files when the dev profile is active
+	FilesDev []string `yaml:"files_dev,omitempty"`
+
+	// FilesProd overrides Files when the prod profile is active
+	FilesProd []string `yaml:"files_prod,omitempty"`
+
+	// StripPrefix is a leading folder (e.g., "dist/") removed from the local
+	// paths of the files under it, keeping deep package paths short
+	StripPrefix string `yaml:"strip_prefix,omitempty"`
+
 	// OutputPath allows overriding the global Destination for this specific library
 	// If empty, the global Destination template is used
 	OutputPath string `yaml:"output_path,omitempty"`
+
+	// Groups assigns the library to named groups (e.g., "admin", "marketing")
+	// so that commands can operate on a subset of libraries
+	Groups []string `yaml:"groups,omitempty"`
+
+	// SourceMaps overrides the global sourcemap handling for this library
+	SourceMaps SourceMaps `yaml:"sourcemaps,omitempty"`
+
+	// Mirrors lists additional output paths for this library, in addition
+	// to the global Mirrors
+	Mirrors []string `yaml:"mirrors,omitempty"`
+
+	// Headers and Query add to (and override) the global Headers and Query
+	// for this library's file downloads
+	Headers HostValues `yaml:"headers,omitempty"`
+	Query   HostValues `yaml:"query,omitempty"`
+
+	// Tags are free-form labels for the library (e.g., "legacy", "ie11")
+	Tags []string `yaml:"tags,omitempty"`
+
+	// Notes records why the library is configured the way it is (e.g.,
+	// "pinned for IE11 support")
+	Notes string `yaml:"notes,omitempty"`
 }
 
 // GetLibraryDestination generates an absolute destination path for a library
@@ -71,8 +232,8 @@ func (fc *FrontendConfig) GetLibraryDestination(libraryName string, libConfig Li
 		return "", fmt.Errorf("no destination path configured for library %s", libraryName)
 	}
 
-	// Replace {library_name} placeholder with actual library name
-	resolvedPath := strings.ReplaceAll(pathTemplate, "{library_name}", libraryName)
+	// Replace placeholders with the library name and version
+	resolvedPath := resolvePathTemplate(pathTemplate, libraryName, libConfig.Version)
 
 	// Convert to absolute path
 	absPath, err := filepath.Abs(resolvedPath)
@@ -83,6 +244,92 @@ func (fc *FrontendConfig) GetLibraryDestination(libraryName string, libConfig Li
 	return absPath, nil
 }
 
+// resolvePathTemplate replaces the {library_name} and {version} placeholders in a path template
+func resolvePathTemplate(template, libraryName, version string) string {
+	resolved := strings.ReplaceAll(template, "{library_name}", libraryName)
+	return strings.ReplaceAll(resolved, "{version}", version)
+}
+
+// GetLibraryMirrors returns the absolute mirror paths for a library, combining
+// the global Mirrors templates with the library's own Mirrors
+func (fc *FrontendConfig) GetLibraryMirrors(libraryName string, libConfig LibraryConfig) ([]string, error) {
+	templates := append(append([]string{}, fc.Mirrors...), libConfig.Mirrors...)
+
+	var mirrors []string
+	seen := make(map[string]bool)
+	for _, template := range templates {
+		resolvedPath := resolvePathTemplate(template, libraryName, libConfig.Version)
+
+		absPath, err := filepath.Abs(resolvedPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", resolvedPath, err)
+		}
+
+		if !seen[absPath] {
+			seen[absPath] = true
+			mirrors = append(mirrors, absPath)
+		}
+	}
+
+	return mirrors, nil
+}
+
+// HostValues maps a host (e.g. "mirror.example.com", or
+// "mirror.example.com:8443") to names and values sent only to that host
+type HostValues map[string]map[string]string
+
+// ForHost returns the values for host, which may include a port. A key with
+// the same port wins over one without a port, which matches any port.
+func (v HostValues) ForHost(host string) map[string]string {
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	for key, values := range v {
+		if strings.EqualFold(key, host) {
+			return values
+		}
+	}
+	for key, values := range v {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// GetLibraryHeaders returns the extra headers of a library's file
+// downloads: the global Headers overridden by the library's own
+func (fc *FrontendConfig) GetLibraryHeaders(libConfig LibraryConfig) HostValues {
+	return mergeHostValues(fc.Headers, libConfig.Headers)
+}
+
+// GetLibraryQuery returns the extra query parameters of a library's file
+// downloads: the global Query overridden by the library's own
+func (fc *FrontendConfig) GetLibraryQuery(libConfig LibraryConfig) HostValues {
+	return mergeHostValues(fc.Query, libConfig.Query)
+}
+
+// mergeHostValues returns base with the entries of override applied host by
+// host, or nil when both are empty
+func mergeHostValues(base, override HostValues) HostValues {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(HostValues, len(base)+len(override))
+	for _, values := range []HostValues{base, override} {
+		for host, entries := range values {
+			if merged[host] == nil {
+				merged[host] = make(map[string]string, len(entries))
+			}
+			for k, v := range entries {
+				merged[host][k] = v
+			}
+		}
+	}
+	return merged
+}
+
 // GetLibraryVersions returns a map of library names to their versions
 func (fc *FrontendConfig) GetLibraryVersions() map[string]string {
 	versions := make(map[string]string, len(fc.Libraries))
@@ -109,6 +356,250 @@ func (fc *FrontendConfig) GetLibraryDestinations() (map[string]string, error) {
 	return destinations, nil
 }
 
+// GetLibraryFiles returns the files to download for a library under the
+// active profile, falling back to Files when no profile-specific list is set
+func (fc *FrontendConfig) GetLibraryFiles(libConfig LibraryConfig) []string {
+	switch fc.Profile {
+	case ProfileProd:
+		if len(libConfig.FilesProd) > 0 {
+			return libConfig.FilesProd
+		}
+	default:
+		if len(libConfig.FilesDev) > 0 {
+			return libConfig.FilesDev
+		}
+	}
+	return libConfig.Files
+}
+
+// GetLibraryFilesMode returns the effective files mode for a library,
+// considering both the library-specific and the global setting
+func (fc *FrontendConfig) GetLibraryFilesMode(libConfig LibraryConfig) FilesMode {
+	if libConfig.FilesMode != "" {
+		return libConfig.FilesMode
+	}
+	if fc.FilesMode != "" {
+		return fc.FilesMode
+	}
+	return FilesModeEntrypoints
+}
+
+// LockfileEnabled reports whether sync writes the lockfile
+func (fc *FrontendConfig) LockfileEnabled() bool {
+	return fc.Lockfile == nil || *fc.Lockfile
+}
+
+// IsValidFilesMode checks if a files mode is one of the supported values
+func IsValidFilesMode(mode FilesMode) bool {
+	switch mode {
+	case "", FilesModeEntrypoints, FilesModeAll, FilesModeMinified:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetLibrarySourceMaps returns the effective sourcemap handling for a library,
+// considering both the library-specific and the global setting
+func (fc *FrontendConfig) GetLibrarySourceMaps(libConfig LibraryConfig) SourceMaps {
+	if libConfig.SourceMaps != "" {
+		return libConfig.SourceMaps
+	}
+	return fc.SourceMaps
+}
+
+// IsValidSourceMaps checks if a sourcemap setting is one of the supported values
+func IsValidSourceMaps(mode SourceMaps) bool {
+	switch mode {
+	case "", SourceMapsInclude, SourceMapsExclude:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidLinkMode checks if a link mode is one of the supported values
+func IsValidLinkMode(mode LinkMode) bool {
+	switch mode {
+	case "", LinkModeCopy, LinkModeSymlink, LinkModeHardlink:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidHost checks if host is a host name, optionally with a port, as
+// used for the keys of HostValues
+func IsValidHost(host string) bool {
+	if host == "" || strings.ContainsAny(host, "/?#@ ") {
+		return false
+	}
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		return h != "" && port != ""
+	}
+	return !strings.Contains(host, ":")
+}
+
+// IsValidHeaderName checks if name can be sent as an HTTP header name
+func IsValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7e || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidCaseCollisions checks if a case collision strategy is one of the supported values
+func IsValidCaseCollisions(strategy CaseCollisions) bool {
+	switch strategy {
+	case "", CaseCollisionsRename, CaseCollisionsSkip, CaseCollisionsError:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidProfile checks if a profile value is one of the supported profiles
+func IsValidProfile(profile Profile) bool {
+	switch profile {
+	case "", ProfileDev, ProfileProd:
+		return true
+	default:
+		return false
+	}
+}
+
+// InGroup reports whether the library belongs to the given group
+func (lc LibraryConfig) InGroup(group string) bool {
+	for _, g := range lc.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGroups returns the sorted, de-duplicated list of groups used by libraries
+func (fc *FrontendConfig) GetGroups() []string {
+	seen := make(map[string]bool)
+	var groups []string
+
+	for _, libConfig := range fc.Libraries {
+		for _, group := range libConfig.Groups {
+			if !seen[group] {
+				seen[group] = true
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	sort.Strings(groups)
+	return groups
+}
+
+// FilterByGroups returns a copy of the config containing only libraries that
+// belong to at least one of the given groups. If no groups are given, the
+// config is returned unchanged.
+func (fc *FrontendConfig) FilterByGroups(groups []string) *FrontendConfig {
+	if len(groups) == 0 {
+		return fc
+	}
+
+	filtered := *fc
+	filtered.Libraries = make(map[string]LibraryConfig)
+	for libraryName, libConfig := range fc.Libraries {
+		for _, group := range groups {
+			if libConfig.InGroup(group) {
+				filtered.Libraries[libraryName] = libConfig
+				break
+			}
+		}
+	}
+
+	return &filtered
+}
+
+// WithDevLibraries returns a copy of the config whose Libraries also contain
+// the DevLibraries. A name in both sections is an error.
+func (fc *FrontendConfig) WithDevLibraries() (*FrontendConfig, error) {
+	if len(fc.DevLibraries) == 0 {
+		return fc, nil
+	}
+
+	merged := *fc
+	merged.Libraries = make(map[string]LibraryConfig, len(fc.Libraries)+len(fc.DevLibraries))
+	for libraryName, libConfig := range fc.Libraries {
+		merged.Libraries[libraryName] = libConfig
+	}
+	for libraryName, libConfig := range fc.DevLibraries {
+		if _, exists := fc.Libraries[libraryName]; exists {
+			return nil, fmt.Errorf("library %s is in both libraries and dev_libraries", libraryName)
+		}
+		merged.Libraries[libraryName] = libConfig
+	}
+
+	return &merged, nil
+}
+
+// DestinationConflict describes two libraries whose destinations are the
+// same folder or nested inside one another
+type DestinationConflict struct {
+	Library      string
+	Path         string
+	OtherLibrary string
+	OtherPath    string
+}
+
+// IsSame reports whether both libraries resolve to the same folder
+func (dc DestinationConflict) IsSame() bool {
+	return dc.Path == dc.OtherPath
+}
+
+// FindDestinationConflicts returns every pair of libraries whose destination
+// folders are identical or nested, sorted by library name
+func (fc *FrontendConfig) FindDestinationConflicts() ([]DestinationConflict, error) {
+	destinations, err := fc.GetLibraryDestinations()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(destinations))
+	for name := range destinations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var conflicts []DestinationConflict
+	for i, a := range names {
+		for _, b := range names[i+1:] {
+			pathA, pathB := destinations[a], destinations[b]
+			if pathA == pathB || isSubPath(pathA, pathB) || isSubPath(pathB, pathA) {
+				conflicts = append(conflicts, DestinationConflict{
+					Library:      a,
+					Path:         pathA,
+					OtherLibrary: b,
+					OtherPath:    pathB,
+				})
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// isSubPath reports whether child is located inside parent
+func isSubPath(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // IsValidCDN checks if a CDN value is one of the supported CDNs
 func IsValidCDN(cdn CDN) bool {
 	switch cdn {
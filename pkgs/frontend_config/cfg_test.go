@@ -2,6 +2,7 @@ package frontend_config
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -441,3 +442,464 @@ func TestGetLibraryDestinations(t *testing.T) {
 		})
 	}
 }
+
+func TestInGroup(t *testing.T) {
+	lib := LibraryConfig{Version: "1.0.0", Groups: []string{"admin", "marketing"}}
+
+	if !lib.InGroup("admin") {
+		t.Error("expected library to be in group admin")
+	}
+	if lib.InGroup("shop") {
+		t.Error("expected library not to be in group shop")
+	}
+	if (LibraryConfig{}).InGroup("admin") {
+		t.Error("expected library without groups not to be in any group")
+	}
+}
+
+func TestGetGroups(t *testing.T) {
+	config := FrontendConfig{
+		Libraries: map[string]LibraryConfig{
+			"jquery":    {Version: "3.7.1", Groups: []string{"marketing", "admin"}},
+			"chart.js":  {Version: "4.4.0", Groups: []string{"admin"}},
+			"bootstrap": {Version: "5.3.0"},
+		},
+	}
+
+	groups := config.GetGroups()
+	expected := []string{"admin", "marketing"}
+
+	if len(groups) != len(expected) {
+		t.Fatalf("expected groups %v, got %v", expected, groups)
+	}
+	for i := range expected {
+		if groups[i] != expected[i] {
+			t.Errorf("expected groups %v, got %v", expected, groups)
+		}
+	}
+}
+
+func TestFilterByGroups(t *testing.T) {
+	config := &FrontendConfig{
+		Destination: "./frontend/{library_name}",
+		Libraries: map[string]LibraryConfig{
+			"jquery":    {Version: "3.7.1", Groups: []string{"marketing", "admin"}},
+			"chart.js":  {Version: "4.4.0", Groups: []string{"admin"}},
+			"swiper":    {Version: "11.0.0", Groups: []string{"marketing"}},
+			"bootstrap": {Version: "5.3.0"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		groups   []string
+		expected []string
+	}{
+		{"no groups keeps everything", nil, []string{"bootstrap", "chart.js", "jquery", "swiper"}},
+		{"single group", []string{"admin"}, []string{"chart.js", "jquery"}},
+		{"multiple groups", []string{"admin", "marketing"}, []string{"chart.js", "jquery", "swiper"}},
+		{"unknown group", []string{"shop"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := config.FilterByGroups(tt.groups)
+
+			if len(filtered.Libraries) != len(tt.expected) {
+				t.Fatalf("expected %d libraries, got %d", len(tt.expected), len(filtered.Libraries))
+			}
+			for _, name := range tt.expected {
+				if _, ok := filtered.Libraries[name]; !ok {
+					t.Errorf("expected library %q in filtered config", name)
+				}
+			}
+			if filtered.Destination != config.Destination {
+				t.Errorf("expected destination %q to be preserved, got %q", config.Destination, filtered.Destination)
+			}
+		})
+	}
+
+	if len(config.Libraries) != 4 {
+		t.Errorf("expected original config to be unchanged, got %d libraries", len(config.Libraries))
+	}
+}
+
+func TestWithDevLibraries(t *testing.T) {
+	config := &FrontendConfig{
+		Destination: "./frontend/{library_name}",
+		Libraries: map[string]LibraryConfig{
+			"htmx.org": {Version: "2.0.4"},
+		},
+		DevLibraries: map[string]LibraryConfig{
+			"livereload-js": {Version: "4.0.2"},
+		},
+	}
+
+	merged, err := config.WithDevLibraries()
+	if err != nil {
+		t.Fatalf("WithDevLibraries failed: %v", err)
+	}
+	if len(merged.Libraries) != 2 || merged.Libraries["livereload-js"].Version != "4.0.2" {
+		t.Errorf("expected both libraries in the merged config, got %v", merged.Libraries)
+	}
+	if len(config.Libraries) != 1 {
+		t.Errorf("expected original config to be unchanged, got %d libraries", len(config.Libraries))
+	}
+
+	config.DevLibraries["htmx.org"] = LibraryConfig{Version: "2.0.4"}
+	if _, err := config.WithDevLibraries(); err == nil {
+		t.Error("expected an error for a library in both sections")
+	}
+}
+
+func TestGetLibraryFiles(t *testing.T) {
+	variants := LibraryConfig{
+		Version:   "3.7.1",
+		Files:     []string{"dist/"},
+		FilesDev:  []string{"dist/jquery.js", "dist/jquery.min.map"},
+		FilesProd: []string{"dist/jquery.min.js"},
+	}
+	filesOnly := LibraryConfig{Version: "3.7.1", Files: []string{"dist/"}}
+
+	tests := []struct {
+		name     string
+		profile  Profile
+		lib      LibraryConfig
+		expected []string
+	}{
+		{"default profile uses dev files", "", variants, variants.FilesDev},
+		{"dev profile", ProfileDev, variants, variants.FilesDev},
+		{"prod profile", ProfileProd, variants, variants.FilesProd},
+		{"dev falls back to files", ProfileDev, filesOnly, filesOnly.Files},
+		{"prod falls back to files", ProfileProd, filesOnly, filesOnly.Files},
+		{"no files at all", ProfileProd, LibraryConfig{Version: "1.0.0"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := FrontendConfig{Profile: tt.profile}
+			result := config.GetLibraryFiles(tt.lib)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, result)
+			}
+			for i := range tt.expected {
+				if result[i] != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestIsValidProfile(t *testing.T) {
+	tests := []struct {
+		profile  Profile
+		expected bool
+	}{
+		{"", true},
+		{ProfileDev, true},
+		{ProfileProd, true},
+		{"staging", false},
+	}
+
+	for _, tt := range tests {
+		if result := IsValidProfile(tt.profile); result != tt.expected {
+			t.Errorf("IsValidProfile(%q) = %v, want %v", tt.profile, result, tt.expected)
+		}
+	}
+}
+
+func TestGetLibrarySourceMaps(t *testing.T) {
+	config := FrontendConfig{SourceMaps: SourceMapsExclude}
+
+	if got := config.GetLibrarySourceMaps(LibraryConfig{}); got != SourceMapsExclude {
+		t.Errorf("expected global setting %q, got %q", SourceMapsExclude, got)
+	}
+	if got := config.GetLibrarySourceMaps(LibraryConfig{SourceMaps: SourceMapsInclude}); got != SourceMapsInclude {
+		t.Errorf("expected library override %q, got %q", SourceMapsInclude, got)
+	}
+	if !IsValidSourceMaps("") || !IsValidSourceMaps(SourceMapsInclude) || IsValidSourceMaps("inline") {
+		t.Error("unexpected IsValidSourceMaps result")
+	}
+}
+
+func TestFindDestinationConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name      string
+		config    FrontendConfig
+		expected  [][2]string
+		sameCount int
+	}{
+		{
+			name: "separate folders",
+			config: FrontendConfig{
+				Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+				Libraries: map[string]LibraryConfig{
+					"react":     {Version: "18.2.0"},
+					"react-dom": {Version: "18.2.0"},
+				},
+			},
+		},
+		{
+			name: "shared destination without placeholder",
+			config: FrontendConfig{
+				Destination: filepath.Join(tmpDir, "vendor"),
+				Libraries: map[string]LibraryConfig{
+					"jquery":    {Version: "3.7.1"},
+					"bootstrap": {Version: "5.3.0"},
+				},
+			},
+			expected:  [][2]string{{"bootstrap", "jquery"}},
+			sameCount: 1,
+		},
+		{
+			name: "nested output path",
+			config: FrontendConfig{
+				Destination: filepath.Join(tmpDir, "libs", "{library_name}"),
+				Libraries: map[string]LibraryConfig{
+					"bootstrap":        {Version: "5.3.0"},
+					"bootstrap-icons":  {Version: "1.11.0", OutputPath: filepath.Join(tmpDir, "libs", "bootstrap", "icons")},
+					"unrelated-plugin": {Version: "1.0.0"},
+				},
+			},
+			expected: [][2]string{{"bootstrap", "bootstrap-icons"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts, err := tt.config.FindDestinationConflicts()
+			if err != nil {
+				t.Fatalf("FindDestinationConflicts failed: %v", err)
+			}
+
+			if len(conflicts) != len(tt.expected) {
+				t.Fatalf("expected %d conflicts, got %d: %+v", len(tt.expected), len(conflicts), conflicts)
+			}
+
+			same := 0
+			for i, pair := range tt.expected {
+				if conflicts[i].Library != pair[0] || conflicts[i].OtherLibrary != pair[1] {
+					t.Errorf("conflict %d: expected %v, got %s/%s", i, pair, conflicts[i].Library, conflicts[i].OtherLibrary)
+				}
+				if conflicts[i].IsSame() {
+					same++
+				}
+			}
+			if same != tt.sameCount {
+				t.Errorf("expected %d identical destinations, got %d", tt.sameCount, same)
+			}
+		})
+	}
+}
+
+func TestGetLibraryMirrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := FrontendConfig{
+		Destination: filepath.Join(tmpDir, "public", "{library_name}"),
+		Mirrors:     []string{filepath.Join(tmpDir, "docs", "{library_name}")},
+	}
+
+	mirrors, err := config.GetLibraryMirrors("jquery", LibraryConfig{
+		Version: "3.7.1",
+		Mirrors: []string{
+			filepath.Join(tmpDir, "legacy", "jquery"),
+			filepath.Join(tmpDir, "docs", "jquery"), // Duplicate of the global mirror
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetLibraryMirrors failed: %v", err)
+	}
+
+	expected := []string{filepath.Join(tmpDir, "docs", "jquery"), filepath.Join(tmpDir, "legacy", "jquery")}
+	if len(mirrors) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, mirrors)
+	}
+	for i := range expected {
+		if mirrors[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, mirrors)
+		}
+	}
+
+	none, err := (&FrontendConfig{}).GetLibraryMirrors("jquery", LibraryConfig{})
+	if err != nil || len(none) != 0 {
+		t.Errorf("expected no mirrors, got %v (err %v)", none, err)
+	}
+}
+
+func TestIsValidLinkMode(t *testing.T) {
+	for _, mode := range []LinkMode{"", LinkModeCopy, LinkModeSymlink, LinkModeHardlink} {
+		if !IsValidLinkMode(mode) {
+			t.Errorf("expected %q to be valid", mode)
+		}
+	}
+	if IsValidLinkMode("reflink") {
+		t.Error("expected reflink to be invalid")
+	}
+}
+
+func TestGetLibraryFilesMode(t *testing.T) {
+	config := FrontendConfig{}
+	if mode := config.GetLibraryFilesMode(LibraryConfig{}); mode != FilesModeEntrypoints {
+		t.Errorf("expected default %q, got %q", FilesModeEntrypoints, mode)
+	}
+
+	config.FilesMode = FilesModeAll
+	if mode := config.GetLibraryFilesMode(LibraryConfig{}); mode != FilesModeAll {
+		t.Errorf("expected global %q, got %q", FilesModeAll, mode)
+	}
+	if mode := config.GetLibraryFilesMode(LibraryConfig{FilesMode: FilesModeEntrypoints}); mode != FilesModeEntrypoints {
+		t.Errorf("expected library override %q, got %q", FilesModeEntrypoints, mode)
+	}
+
+	if !IsValidFilesMode("") || !IsValidFilesMode(FilesModeAll) || !IsValidFilesMode(FilesModeMinified) || IsValidFilesMode("some") {
+		t.Error("unexpected IsValidFilesMode result")
+	}
+}
+
+func TestGetLibraryDestinationVersionPlaceholder(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := FrontendConfig{
+		Destination: filepath.Join(tmpDir, "libs", "{library_name}", "{version}"),
+		Mirrors:     []string{filepath.Join(tmpDir, "docs", "{library_name}@{version}")},
+	}
+	libConfig := LibraryConfig{Version: "3.7.1"}
+
+	dest, err := config.GetLibraryDestination("jquery", libConfig)
+	if err != nil {
+		t.Fatalf("GetLibraryDestination failed: %v", err)
+	}
+	if expected := filepath.Join(tmpDir, "libs", "jquery", "3.7.1"); dest != expected {
+		t.Errorf("expected %s, got %s", expected, dest)
+	}
+
+	mirrors, err := config.GetLibraryMirrors("jquery", libConfig)
+	if err != nil {
+		t.Fatalf("GetLibraryMirrors failed: %v", err)
+	}
+	if expected := filepath.Join(tmpDir, "docs", "jquery@3.7.1"); len(mirrors) != 1 || mirrors[0] != expected {
+		t.Errorf("expected [%s], got %v", expected, mirrors)
+	}
+}
+
+func TestLibraryTagsAndNotesRoundTrip(t *testing.T) {
+	yamlData := `
+libraries:
+  jquery:
+    version: "1.12.4"
+    tags: [legacy, ie11]
+    notes: "pinned for IE11 support"
+  htmx.org:
+    version: "2.0.4"
+`
+	var config FrontendConfig
+	if err := yaml.Unmarshal([]byte(yamlData), &config); err != nil {
+		t.Fatalf("failed to unmarshal YAML: %v", err)
+	}
+
+	data, err := yaml.Marshal(&config)
+	if err != nil {
+		t.Fatalf("failed to marshal config to YAML: %v", err)
+	}
+	var config2 FrontendConfig
+	if err := yaml.Unmarshal(data, &config2); err != nil {
+		t.Fatalf("failed to unmarshal marshalled YAML: %v", err)
+	}
+
+	jquery := config2.Libraries["jquery"]
+	if len(jquery.Tags) != 2 || jquery.Tags[1] != "ie11" || jquery.Notes != "pinned for IE11 support" {
+		t.Errorf("jquery after round-trip = %+v", jquery)
+	}
+	if strings.Contains(string(data), "notes: \"\"") || strings.Count(string(data), "tags:") != 1 {
+		t.Errorf("empty tags and notes should be omitted:\n%s", data)
+	}
+}
+
+func TestLockfileEnabled(t *testing.T) {
+	config := FrontendConfig{}
+	if !config.LockfileEnabled() {
+		t.Error("expected the lockfile to be written by default")
+	}
+
+	off := false
+	config.Lockfile = &off
+	if config.LockfileEnabled() {
+		t.Error("expected lockfile: false to turn the lockfile off")
+	}
+}
+
+func TestGetLibraryHeadersAndQuery(t *testing.T) {
+	config := FrontendConfig{
+		Headers: HostValues{
+			"mirror.example.com": {"X-Api-Key": "${MIRROR_KEY}", "Accept": "*/*"},
+			"cdn.example.com":    {"X-Cdn-Key": "${CDN_KEY}"},
+		},
+		Query: HostValues{"mirror.example.com": {"token": "global"}},
+	}
+	libConfig := LibraryConfig{Headers: HostValues{"mirror.example.com": {"X-Api-Key": "${OTHER_KEY}"}}}
+
+	headers := config.GetLibraryHeaders(libConfig)
+	mirror := headers.ForHost("mirror.example.com")
+	if len(headers) != 2 || len(mirror) != 2 || mirror["X-Api-Key"] != "${OTHER_KEY}" || mirror["Accept"] != "*/*" {
+		t.Errorf("GetLibraryHeaders() = %v", headers)
+	}
+	if query := config.GetLibraryQuery(libConfig).ForHost("mirror.example.com"); len(query) != 1 || query["token"] != "global" {
+		t.Errorf("GetLibraryQuery() = %v", query)
+	}
+	if config.Headers["mirror.example.com"]["X-Api-Key"] != "${MIRROR_KEY}" {
+		t.Error("GetLibraryHeaders changed the global headers")
+	}
+	if none := (&FrontendConfig{}).GetLibraryQuery(LibraryConfig{}); none != nil {
+		t.Errorf("expected no query parameters, got %v", none)
+	}
+}
+
+func TestHostValuesForHost(t *testing.T) {
+	values := HostValues{
+		"mirror.example.com":      {"key": "any port"},
+		"mirror.example.com:8443": {"key": "8443"},
+	}
+	for host, want := range map[string]string{
+		"mirror.example.com":      "any port",
+		"MIRROR.example.com:80":   "any port",
+		"mirror.example.com:8443": "8443",
+		"unpkg.com":               "",
+		"evil.mirror.example.com": "",
+	} {
+		if got := values.ForHost(host)["key"]; got != want {
+			t.Errorf("ForHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestIsValidHost(t *testing.T) {
+	for host, want := range map[string]bool{
+		"mirror.example.com":         true,
+		"mirror.example.com:8443":    true,
+		"":                           false,
+		"https://mirror.example.com": false,
+		"mirror.example.com/npm":     false,
+		"mirror.example.com:":        false,
+	} {
+		if got := IsValidHost(host); got != want {
+			t.Errorf("IsValidHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestIsValidHeaderName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"X-Api-Key":     true,
+		"Authorization": true,
+		"":              false,
+		"X Api Key":     false,
+		"X-Key:":        false,
+	} {
+		if got := IsValidHeaderName(name); got != want {
+			t.Errorf("IsValidHeaderName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
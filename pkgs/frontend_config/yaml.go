@@ -0,0 +1,114 @@
+package frontend_config
+
+import (
+	"bytes"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontendConfigFields has the fields of FrontendConfig without its YAML
+// methods, so they can use the default encoding
+type frontendConfigFields FrontendConfig
+
+// UnmarshalYAML decodes a config and records the order its libraries are
+// written in, so saving it doesn't reorder them
+func (fc *FrontendConfig) UnmarshalYAML(value *yaml.Node) error {
+	if err := value.Decode((*frontendConfigFields)(fc)); err != nil {
+		return err
+	}
+	fc.libraryOrder = libraryKeys(value)
+	return nil
+}
+
+// UnmarshalStrict decodes a config like yaml.Unmarshal, but rejects unknown
+// keys (yaml.Decoder.KnownFields doesn't reach custom unmarshalers)
+func UnmarshalStrict(data []byte, fc *FrontendConfig) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode((*frontendConfigFields)(fc)); err != nil {
+		return err
+	}
+	if len(doc.Content) > 0 {
+		fc.libraryOrder = libraryKeys(doc.Content[0])
+	}
+	return nil
+}
+
+// MarshalYAML encodes a config with its libraries in a stable order: the
+// order they were read in, with new libraries added alphabetically. Configs
+// whose libraries were already alphabetical stay alphabetical.
+func (fc FrontendConfig) MarshalYAML() (any, error) {
+	var node yaml.Node
+	if err := node.Encode((*frontendConfigFields)(&fc)); err != nil {
+		return nil, err
+	}
+
+	libraries := mappingValueNode(&node, "libraries")
+	if libraries == nil || libraries.Kind != yaml.MappingNode || slices.IsSorted(fc.libraryOrder) {
+		return &node, nil
+	}
+
+	// The encoder sorts map keys, so the new libraries are already in order
+	rank := make(map[string]int, len(fc.libraryOrder))
+	for i, name := range fc.libraryOrder {
+		rank[name] = i
+	}
+	pairs := make([][2]*yaml.Node, 0, len(libraries.Content)/2)
+	for i := 0; i+1 < len(libraries.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{libraries.Content[i], libraries.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		ra, knownA := rank[a[0].Value]
+		rb, knownB := rank[b[0].Value]
+		switch {
+		case knownA && knownB:
+			return ra - rb
+		case knownA:
+			return -1
+		case knownB:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	libraries.Content = libraries.Content[:0]
+	for _, pair := range pairs {
+		libraries.Content = append(libraries.Content, pair[0], pair[1])
+	}
+	return &node, nil
+}
+
+// libraryKeys returns the library names of a config mapping node in the
+// order they are written
+func libraryKeys(node *yaml.Node) []string {
+	libraries := mappingValueNode(node, "libraries")
+	if libraries == nil || libraries.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	names := make([]string, 0, len(libraries.Content)/2)
+	for i := 0; i < len(libraries.Content); i += 2 {
+		names = append(names, libraries.Content[i].Value)
+	}
+	return names
+}
+
+// mappingValueNode returns the value of key in a mapping node, or nil
+func mappingValueNode(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
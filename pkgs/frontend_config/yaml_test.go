@@ -0,0 +1,97 @@
+package frontend_config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// libraryOrderOf returns the library names of a marshalled config in order
+func libraryOrderOf(t *testing.T, data []byte) []string {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return libraryKeys(doc.Content[0])
+}
+
+func TestMarshalKeepsLibraryOrder(t *testing.T) {
+	input := `destination: ./vendor/{library_name}
+project_name: demo
+libraries:
+    jquery:
+        version: 3.7.1
+    bootstrap:
+        version: 5.3.3
+    alpinejs:
+        version: 3.14.1
+`
+	var config FrontendConfig
+	if err := yaml.Unmarshal([]byte(input), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-saving an unchanged config writes it back as it was
+	data, err := yaml.Marshal(&config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != input {
+		t.Errorf("round trip changed the config:\n%s", data)
+	}
+
+	// Removed libraries drop out and new ones are appended alphabetically
+	delete(config.Libraries, "bootstrap")
+	config.Libraries["htmx.org"] = LibraryConfig{Version: "2.0.0"}
+	config.Libraries["chart.js"] = LibraryConfig{Version: "4.4.0"}
+	data, err = yaml.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(libraryOrderOf(t, data), ",")
+	if want := "jquery,alpinejs,chart.js,htmx.org"; got != want {
+		t.Errorf("library order = %s, want %s", got, want)
+	}
+}
+
+func TestMarshalSortsAlphabeticalConfigs(t *testing.T) {
+	config := FrontendConfig{Libraries: map[string]LibraryConfig{
+		"vue": {Version: "3.4.0"}, "axios": {Version: "1.7.0"}, "lodash": {Version: "4.17.21"},
+	}}
+
+	for range 5 {
+		data, err := yaml.Marshal(&config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := strings.Join(libraryOrderOf(t, data), ",")
+		if want := "axios,lodash,vue"; got != want {
+			t.Fatalf("library order = %s, want %s", got, want)
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			t.Fatal(err)
+		}
+		config.Libraries["moment"] = LibraryConfig{Version: "2.30.1"}
+		delete(config.Libraries, "moment")
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	var config FrontendConfig
+	err := UnmarshalStrict([]byte("destination: ./vendor\nlibraries:\n  b: {version: 1.0.0}\n  a: {version: 2.0.0}\n"), &config)
+	if err != nil {
+		t.Fatalf("UnmarshalStrict() error = %v", err)
+	}
+	if got := strings.Join(config.libraryOrder, ","); got != "b,a" {
+		t.Errorf("library order = %s, want b,a", got)
+	}
+
+	if err := UnmarshalStrict([]byte("destination: ./vendor\nbogus: true\n"), &config); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+	if err := yaml.Unmarshal([]byte("destination: ./vendor\nbogus: true\n"), &config); err != nil {
+		t.Errorf("yaml.Unmarshal() should still ignore unknown keys, got %v", err)
+	}
+}
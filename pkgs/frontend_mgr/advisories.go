@@ -0,0 +1,95 @@
+package frontend_mgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"nexus-sds.com/smfaman/pkgs/cache"
+)
+
+// Advisory is a security advisory affecting a package version
+type Advisory struct {
+	ID                 int    `json:"id"`
+	Title              string `json:"title"`
+	Severity           string `json:"severity"` // "low", "moderate", "high" or "critical"
+	URL                string `json:"url"`
+	VulnerableVersions string `json:"vulnerable_versions"`
+}
+
+// advisorySeverities orders advisory severities from least to most severe
+var advisorySeverities = []string{"info", "low", "moderate", "high", "critical"}
+
+// SeverityRank returns the position of severity in the order info < low <
+// moderate < high < critical, or -1 for an unknown severity
+func SeverityRank(severity string) int {
+	for i, s := range advisorySeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// FetchAdvisories fetches the security advisories that affect each package
+// version in versions (package name to exact version), keyed by package name.
+// Packages without advisories are left out. Results are cached per package
+// version, and the versions not cached are looked up in one request.
+// Endpoint: POST https://registry.npmjs.org/-/npm/v1/security/advisories/bulk
+func FetchAdvisories(versions map[string]string) (map[string][]Advisory, error) {
+	result := make(map[string][]Advisory)
+	query := make(map[string][]string)
+
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var cached []Advisory
+		if found, _ := CacheManager.Get(cache.GenerateKey("npm", "advisories", name, versions[name]), &cached); found {
+			if len(cached) > 0 {
+				result[name] = cached
+			}
+			continue
+		}
+		query[name] = []string{versions[name]}
+	}
+	if len(query) == 0 {
+		return result, nil
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := registryPost(npmRegistryURL+"/-/npm/v1/security/advisories/bulk", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch advisories from npm registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "npm advisories API", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var fetched map[string][]Advisory
+	if err := decodeResponse(resp, &fetched); err != nil {
+		return nil, fmt.Errorf("failed to decode npm advisories response: %w", err)
+	}
+
+	for name := range query {
+		advisories := fetched[name]
+		if advisories == nil {
+			advisories = []Advisory{}
+		}
+		CacheManager.Set(cache.GenerateKey("npm", "advisories", name, versions[name]), advisories)
+		if len(advisories) > 0 {
+			result[name] = advisories
+		}
+	}
+	return result, nil
+}
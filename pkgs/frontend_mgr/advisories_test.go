@@ -0,0 +1,51 @@
+package frontend_mgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchAdvisories(t *testing.T) {
+	var requests []map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/-/npm/v1/security/advisories/bulk" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var query map[string][]string
+		json.NewDecoder(r.Body).Decode(&query)
+		requests = append(requests, query)
+		fmt.Fprint(w, `{"jquery": [{"id": 1, "title": "XSS in htmlPrefilter", "severity": "moderate", "vulnerable_versions": "<3.5.0"}]}`)
+	}))
+	defer server.Close()
+
+	origURL := npmRegistryURL
+	npmRegistryURL = server.URL
+	defer func() {
+		npmRegistryURL = origURL
+		SetCacheEnabled(true)
+	}()
+	SetCacheEnabled(false)
+
+	advisories, err := FetchAdvisories(map[string]string{"jquery": "3.4.1", "htmx.org": "2.0.4"})
+	if err != nil {
+		t.Fatalf("FetchAdvisories() error = %v", err)
+	}
+	if len(advisories) != 1 || len(advisories["jquery"]) != 1 || advisories["jquery"][0].Severity != "moderate" {
+		t.Errorf("unexpected advisories %+v", advisories)
+	}
+	if len(requests) != 1 || len(requests[0]) != 2 || requests[0]["jquery"][0] != "3.4.1" {
+		t.Errorf("expected one bulk request for both packages, got %v", requests)
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if SeverityRank("critical") <= SeverityRank("high") || SeverityRank("low") <= SeverityRank("info") {
+		t.Error("severities are out of order")
+	}
+	if SeverityRank("bogus") != -1 {
+		t.Error("expected -1 for an unknown severity")
+	}
+}
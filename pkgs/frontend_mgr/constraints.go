@@ -0,0 +1,441 @@
+package frontend_mgr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+)
+
+// exactVersionPattern matches a full semantic version such as "1.2.3" or "1.2.3-beta.1"
+var exactVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// partialVersionPattern matches a version with optional or wildcard minor and patch parts
+var partialVersionPattern = regexp.MustCompile(`^v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// versionPrefixPattern matches a major or major.minor version prefix such as "18" or "3.4"
+var versionPrefixPattern = regexp.MustCompile(`^v?\d+(\.\d+)?$`)
+
+// operatorSpacePattern matches whitespace between a comparison operator and its version
+var operatorSpacePattern = regexp.MustCompile(`(>=|<=|>|<|=|\^|~)\s+`)
+
+// parsedVersions memoizes parseVersion. Version lists are re-read from the
+// cache by every lookup, so the same strings are parsed over and over;
+// parsed versions are immutable and safe to share.
+var parsedVersions sync.Map // string -> *version.Version, or nil when invalid
+
+// parseVersion parses a version string, reusing earlier results
+func parseVersion(v string) (*version.Version, error) {
+	if cached, ok := parsedVersions.Load(v); ok {
+		if parsed := cached.(*version.Version); parsed != nil {
+			return parsed, nil
+		}
+		return nil, fmt.Errorf("malformed version: %s", v)
+	}
+
+	parsed, err := version.NewVersion(v)
+	parsedVersions.Store(v, parsed)
+	return parsed, err
+}
+
+// IsExactVersion reports whether spec names a single version rather than a range or tag
+func IsExactVersion(spec string) bool {
+	return exactVersionPattern.MatchString(strings.TrimSpace(spec))
+}
+
+// NormalizeVersion returns the canonical form of an exact version, without
+// surrounding whitespace or a leading "v" ("v5.3.0" becomes "5.3.0"). Ranges,
+// dist-tags and anything else are returned trimmed but otherwise unchanged.
+func NormalizeVersion(v string) string {
+	v = strings.TrimSpace(v)
+	if IsExactVersion(v) {
+		return strings.TrimPrefix(v, "v")
+	}
+	return v
+}
+
+// SameVersion reports whether a and b name the same version once normalized,
+// so "v5.3.0" and "5.3.0" are equal
+func SameVersion(a, b string) bool {
+	return NormalizeVersion(a) == NormalizeVersion(b)
+}
+
+// FindVersion returns the entry of versions that is the same version as v, in
+// the form the CDN published it (which URLs must use), and whether one was found
+func FindVersion(versions []string, v string) (string, bool) {
+	for _, published := range versions {
+		if SameVersion(published, v) {
+			return published, true
+		}
+	}
+	return "", false
+}
+
+// IsPrerelease reports whether v is a version with a prerelease part, such as "19.0.0-rc.1"
+func IsPrerelease(v string) bool {
+	parsed, err := parseVersion(strings.TrimSpace(v))
+	return err == nil && parsed.Prerelease() != ""
+}
+
+// LatestVersion picks the version "latest" stands for, given versions sorted
+// newest first and the version the CDN tags latest. By default that is the
+// newest stable release: the tagged version, unless the tag points at a
+// prerelease. With includePrerelease it is the newest version published,
+// even when the tag lags behind a prerelease.
+func LatestVersion(versions []string, tagged string, includePrerelease bool) string {
+	if includePrerelease {
+		if len(versions) > 0 {
+			if cmp, err := CompareVersions(versions[0], tagged); tagged == "" || (err == nil && cmp > 0) {
+				return versions[0]
+			}
+		}
+		return tagged
+	}
+
+	if tagged != "" && !IsPrerelease(tagged) {
+		return tagged
+	}
+	for _, v := range versions {
+		if !IsPrerelease(v) {
+			return v
+		}
+	}
+	return tagged
+}
+
+// NewerPrerelease returns the newest prerelease in versions (sorted newest
+// first) when it is newer than latest, or ""
+func NewerPrerelease(versions []string, latest string) string {
+	if len(versions) == 0 || !IsPrerelease(versions[0]) {
+		return ""
+	}
+	if cmp, err := CompareVersions(versions[0], latest); err == nil && cmp <= 0 {
+		return ""
+	}
+	return versions[0]
+}
+
+// IsVersionPrefix reports whether spec is a major or major.minor prefix such
+// as "18" or "3.4", which resolves to the highest release starting with it
+func IsVersionPrefix(spec string) bool {
+	return versionPrefixPattern.MatchString(strings.TrimSpace(spec))
+}
+
+// CompareVersions compares two versions, returning -1, 0 or 1 as a is lower
+// than, equal to or higher than b
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// MajorVersion returns the major version number of v
+func MajorVersion(v string) (int, error) {
+	parsed, err := parseVersion(v)
+	if err != nil {
+		return 0, err
+	}
+	return parsed.Segments()[0], nil
+}
+
+// comparator is a single "operator version" condition
+type comparator struct {
+	op      string // ">=", ">", "<=", "<" or "="
+	version *version.Version
+}
+
+// check reports whether v satisfies the comparator
+func (c comparator) check(v *version.Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// VersionRange is a parsed npm-style version range, e.g. "^18", "~1.2.3",
+// "5.x", ">=1.0 <2" or "1.x || 2.x"
+type VersionRange struct {
+	spec string
+	sets [][]comparator // Any set must match; all comparators within a set must match
+}
+
+// String returns the range as it was written
+func (r *VersionRange) String() string {
+	return r.spec
+}
+
+// ParseVersionRange parses an npm-style version range
+func ParseVersionRange(spec string) (*VersionRange, error) {
+	r := &VersionRange{spec: spec}
+
+	for _, set := range strings.Split(spec, "||") {
+		comparators, err := parseComparatorSet(strings.TrimSpace(set))
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range %q: %w", spec, err)
+		}
+		r.sets = append(r.sets, comparators)
+	}
+
+	return r, nil
+}
+
+// Check reports whether a version satisfies the range. As with npm, a
+// prerelease only matches when the range names a prerelease of the same
+// major.minor.patch, so "^2.0.0-beta.1" doesn't pull in "3.0.0-rc.1".
+func (r *VersionRange) Check(v *version.Version) bool {
+	for _, set := range r.sets {
+		matched := v.Prerelease() == "" || allowsPrerelease(set, v)
+		for _, c := range set {
+			if !matched {
+				break
+			}
+			matched = c.check(v)
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPrerelease reports whether a comparator set names a prerelease of v's release
+func allowsPrerelease(set []comparator, v *version.Version) bool {
+	for _, c := range set {
+		if c.version.Prerelease() != "" && c.version.Core().Equal(v.Core()) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSatisfying returns the highest version that satisfies the range, or ""
+// when none does. Unparseable versions are ignored.
+func (r *VersionRange) MaxSatisfying(versions []string) string {
+	var best *version.Version
+	for _, v := range versions {
+		parsed, err := parseVersion(v)
+		if err != nil || !r.Check(parsed) {
+			continue
+		}
+		if best == nil || parsed.GreaterThan(best) {
+			best = parsed
+		}
+	}
+
+	if best == nil {
+		return ""
+	}
+	return best.Original()
+}
+
+// ResolveVersionSpec resolves an exact version, dist-tag or range to an
+// exact published version
+func ResolveVersionSpec(spec string, versions []string, distTags map[string]string) (string, error) {
+	spec = strings.TrimSpace(spec)
+
+	if IsExactVersion(spec) {
+		if published, ok := FindVersion(versions, spec); ok {
+			return published, nil
+		}
+		return "", fmt.Errorf("version %s is not published", spec)
+	}
+
+	if tagged, ok := distTags[spec]; ok && tagged != "" {
+		return tagged, nil
+	}
+
+	r, err := ParseVersionRange(spec)
+	if err != nil {
+		return "", err
+	}
+	if resolved := r.MaxSatisfying(versions); resolved != "" {
+		return resolved, nil
+	}
+	if IsVersionPrefix(spec) {
+		if prerelease := latestPrereleaseWithPrefix(spec, versions); prerelease != "" {
+			return "", fmt.Errorf("no release starting with %s is published yet, only prereleases such as %s (pass it exactly to use it)", spec, prerelease)
+		}
+		return "", fmt.Errorf("no published release starts with %s", spec)
+	}
+	return "", fmt.Errorf("no published version matches %s", spec)
+}
+
+// latestPrereleaseWithPrefix returns the highest prerelease starting with a
+// version prefix, or ""
+func latestPrereleaseWithPrefix(prefix string, versions []string) string {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "v") + "."
+	var best *version.Version
+	for _, v := range versions {
+		parsed, err := parseVersion(v)
+		if err != nil || parsed.Prerelease() == "" || !strings.HasPrefix(parsed.Core().String()+".", prefix) {
+			continue
+		}
+		if best == nil || parsed.GreaterThan(best) {
+			best = parsed
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Original()
+}
+
+// parseComparatorSet parses a space-separated list of conditions, or a hyphen range
+func parseComparatorSet(set string) ([]comparator, error) {
+	if set == "" || set == "*" || set == "x" || set == "X" {
+		return nil, nil
+	}
+
+	// Hyphen range: "1.2 - 2.3.4"
+	if lower, upper, ok := strings.Cut(set, " - "); ok {
+		from, err := expandCondition(">=", strings.TrimSpace(lower))
+		if err != nil {
+			return nil, err
+		}
+		to, err := expandCondition("<=", strings.TrimSpace(upper))
+		if err != nil {
+			return nil, err
+		}
+		return append(from, to...), nil
+	}
+
+	var comparators []comparator
+	for _, token := range strings.Fields(operatorSpacePattern.ReplaceAllString(set, "$1")) {
+		op := ""
+		for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+			if strings.HasPrefix(token, candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		expanded, err := expandCondition(op, strings.TrimPrefix(token, op))
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, expanded...)
+	}
+	return comparators, nil
+}
+
+// partialVersion is a version whose minor and patch parts may be missing
+type partialVersion struct {
+	major, minor, patch int
+	parts               int    // Number of numeric parts given (0-3)
+	prerelease          string // Including the leading "-"
+}
+
+// parsePartialVersion parses "1", "1.2", "1.2.3", "1.x" or "1.2.3-beta"
+func parsePartialVersion(s string) (partialVersion, error) {
+	m := partialVersionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return partialVersion{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	var p partialVersion
+	for i, field := range []*int{&p.major, &p.minor, &p.patch} {
+		part := m[i+1]
+		if part == "" || part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return partialVersion{}, fmt.Errorf("invalid version %q", s)
+		}
+		*field = n
+		p.parts++
+	}
+	if p.parts == 3 {
+		p.prerelease = m[4]
+	}
+	return p, nil
+}
+
+// newVersion builds a comparable version from its parts
+func newVersion(major, minor, patch int, prerelease string) *version.Version {
+	return version.Must(version.NewVersion(fmt.Sprintf("%d.%d.%d%s", major, minor, patch, prerelease)))
+}
+
+// expandCondition turns an operator and a possibly partial version into comparators
+func expandCondition(op, s string) ([]comparator, error) {
+	p, err := parsePartialVersion(s)
+	if err != nil {
+		return nil, err
+	}
+
+	lower := newVersion(p.major, p.minor, p.patch, p.prerelease)
+	between := func(upper *version.Version) []comparator {
+		return []comparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+	}
+
+	// The version just past a partial version: "1" → 2.0.0, "1.2" → 1.3.0
+	next := func() *version.Version {
+		if p.parts == 1 {
+			return newVersion(p.major+1, 0, 0, "")
+		}
+		return newVersion(p.major, p.minor+1, 0, "")
+	}
+
+	if p.parts == 0 {
+		// Wildcard: "*", "x", ">=*"
+		if op == "<" || op == ">" {
+			return []comparator{{op: "<", version: newVersion(0, 0, 0, "")}}, nil
+		}
+		return nil, nil
+	}
+
+	switch op {
+	case "^":
+		switch {
+		case p.major > 0 || p.parts == 1:
+			return between(newVersion(p.major+1, 0, 0, "")), nil
+		case p.minor > 0 || p.parts == 2:
+			return between(newVersion(0, p.minor+1, 0, "")), nil
+		default:
+			return between(newVersion(0, 0, p.patch+1, "")), nil
+		}
+	case "~":
+		if p.parts == 1 {
+			return between(newVersion(p.major+1, 0, 0, "")), nil
+		}
+		return between(newVersion(p.major, p.minor+1, 0, "")), nil
+	case ">=":
+		return []comparator{{op: ">=", version: lower}}, nil
+	case "<":
+		return []comparator{{op: "<", version: lower}}, nil
+	case ">":
+		if p.parts == 3 {
+			return []comparator{{op: ">", version: lower}}, nil
+		}
+		return []comparator{{op: ">=", version: next()}}, nil
+	case "<=":
+		if p.parts == 3 {
+			return []comparator{{op: "<=", version: lower}}, nil
+		}
+		return []comparator{{op: "<", version: next()}}, nil
+	default:
+		// "=" or a bare (x-)range
+		if p.parts == 3 {
+			return []comparator{{op: "=", version: lower}}, nil
+		}
+		return between(next()), nil
+	}
+}
@@ -0,0 +1,235 @@
+package frontend_mgr
+
+import "testing"
+
+func TestIsExactVersion(t *testing.T) {
+	tests := map[string]bool{
+		"3.7.1":        true,
+		"1.0.0-beta.1": true,
+		"v2.0.0":       true,
+		"18":           false,
+		"5.x":          false,
+		"^18.2.0":      false,
+		"latest":       false,
+		"":             false,
+	}
+	for spec, expected := range tests {
+		if got := IsExactVersion(spec); got != expected {
+			t.Errorf("IsExactVersion(%q) = %v, want %v", spec, got, expected)
+		}
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := map[string]string{
+		"v5.3.0":        "5.3.0",
+		" 5.3.0 ":       "5.3.0",
+		"v1.0.0-beta.1": "1.0.0-beta.1",
+		"^5.3.0":        "^5.3.0",
+		"v5":            "v5",
+		"latest":        "latest",
+	}
+	for v, expected := range tests {
+		if got := NormalizeVersion(v); got != expected {
+			t.Errorf("NormalizeVersion(%q) = %q, want %q", v, got, expected)
+		}
+	}
+}
+
+func TestFindVersion(t *testing.T) {
+	versions := []string{"v5.3.0", "5.2.0"}
+
+	if got, ok := FindVersion(versions, "5.3.0"); !ok || got != "v5.3.0" {
+		t.Errorf("FindVersion(5.3.0) = %q, %v, want the published v5.3.0", got, ok)
+	}
+	if got, ok := FindVersion(versions, "v5.2.0"); !ok || got != "5.2.0" {
+		t.Errorf("FindVersion(v5.2.0) = %q, %v, want the published 5.2.0", got, ok)
+	}
+	if _, ok := FindVersion(versions, "5.1.0"); ok {
+		t.Error("FindVersion(5.1.0) found an unpublished version")
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	versions := []string{"19.0.0-rc.1", "18.3.1", "18.2.0", "18.3.0-beta.2"}
+
+	tests := []struct {
+		name              string
+		versions          []string
+		tagged            string
+		includePrerelease bool
+		expected          string
+	}{
+		{"stable tag", versions, "18.3.1", false, "18.3.1"},
+		{"prerelease tag falls back to stable", versions, "19.0.0-rc.1", false, "18.3.1"},
+		{"no tag", versions, "", false, "18.3.1"},
+		{"only prereleases", []string{"1.0.0-rc.1"}, "1.0.0-rc.1", false, "1.0.0-rc.1"},
+		{"include newer prerelease", versions, "18.3.1", true, "19.0.0-rc.1"},
+		{"include without newer prerelease", []string{"18.3.1", "18.3.0-beta.2"}, "18.3.1", true, "18.3.1"},
+	}
+
+	for _, tt := range tests {
+		if got := LatestVersion(tt.versions, tt.tagged, tt.includePrerelease); got != tt.expected {
+			t.Errorf("%s: LatestVersion() = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestNewerPrerelease(t *testing.T) {
+	if got := NewerPrerelease([]string{"19.0.0-rc.1", "18.3.1"}, "18.3.1"); got != "19.0.0-rc.1" {
+		t.Errorf("NewerPrerelease() = %q, want 19.0.0-rc.1", got)
+	}
+	if got := NewerPrerelease([]string{"18.3.1", "18.3.0-rc.1"}, "18.3.1"); got != "" {
+		t.Errorf("NewerPrerelease() = %q, want none", got)
+	}
+}
+
+func TestIsVersionPrefix(t *testing.T) {
+	tests := map[string]bool{
+		"18":     true,
+		"3.4":    true,
+		"v5":     true,
+		"18.2.0": false,
+		"5.x":    false,
+		"^18":    false,
+		"latest": false,
+		"":       false,
+	}
+	for spec, expected := range tests {
+		if got := IsVersionPrefix(spec); got != expected {
+			t.Errorf("IsVersionPrefix(%q) = %v, want %v", spec, got, expected)
+		}
+	}
+}
+
+func TestVersionRangeMaxSatisfying(t *testing.T) {
+	versions := []string{
+		"0.0.3", "0.2.1", "0.2.5", "0.3.0",
+		"1.2.3", "1.2.9", "1.3.0", "1.9.0",
+		"2.0.0-beta.1", "2.0.0", "2.4.1", "3.0.0-rc.1",
+		"not-a-version",
+	}
+
+	tests := []struct {
+		spec     string
+		expected string
+	}{
+		{"^1.2.3", "1.9.0"},
+		{"^0.2.1", "0.2.5"},
+		{"^0.0.3", "0.0.3"},
+		{"^1", "1.9.0"},
+		{"~1.2.3", "1.2.9"},
+		{"~1", "1.9.0"},
+		{"1", "1.9.0"},
+		{"1.2", "1.2.9"},
+		{"1.2.x", "1.2.9"},
+		{"2.x", "2.4.1"},
+		{"*", "2.4.1"},
+		{"", "2.4.1"},
+		{">=1.3.0 <2", "1.9.0"},
+		{">= 1.3.0 < 2", "1.9.0"},
+		{">1.2", "2.4.1"},
+		{"<=1.2", "1.2.9"},
+		{"<1.2.9", "1.2.3"},
+		{"1.2 - 1.3", "1.3.0"},
+		{"0.x || 1.2", "1.2.9"},
+		{"=2.0.0", "2.0.0"},
+		{"^2.0.0-beta.1", "2.4.1"},
+		{">=3.0.0-rc.1", "3.0.0-rc.1"},
+		{"^4", ""},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseVersionRange(tt.spec)
+		if err != nil {
+			t.Errorf("ParseVersionRange(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := r.MaxSatisfying(versions); got != tt.expected {
+			t.Errorf("MaxSatisfying(%q) = %q, want %q", tt.spec, got, tt.expected)
+		}
+	}
+}
+
+func TestParseVersionRangeInvalid(t *testing.T) {
+	for _, spec := range []string{"latest", "^abc", "1.2.3.4", ">=foo"} {
+		if _, err := ParseVersionRange(spec); err == nil {
+			t.Errorf("expected ParseVersionRange(%q) to fail", spec)
+		}
+	}
+}
+
+func TestResolveVersionSpec(t *testing.T) {
+	versions := []string{"17.0.2", "18.2.0", "18.3.1", "19.0.0-rc.1"}
+	distTags := map[string]string{"latest": "18.3.1", "next": "19.0.0-rc.1"}
+
+	tests := []struct {
+		spec     string
+		expected string
+		wantErr  bool
+	}{
+		{"18.2.0", "18.2.0", false},
+		{"v18.2.0", "18.2.0", false},
+		{"^18", "18.3.1", false},
+		{"17", "17.0.2", false},
+		{"18.2", "18.2.0", false},
+		{"19", "", true},
+		{"latest", "18.3.1", false},
+		{"next", "19.0.0-rc.1", false},
+		{"18.9.9", "", true},
+		{"^20", "", true},
+		{"beta", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveVersionSpec(tt.spec, versions, distTags)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveVersionSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ResolveVersionSpec(%q) = %q, want %q", tt.spec, got, tt.expected)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"2.0.0-rc.1", "2.0.0", -1},
+	}
+	for _, tt := range tests {
+		got, err := CompareVersions(tt.a, tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, %v, want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+
+	if _, err := CompareVersions("^1.2", "1.2.0"); err == nil {
+		t.Error("CompareVersions() accepted a range")
+	}
+	if major, err := MajorVersion("18.2.0"); err != nil || major != 18 {
+		t.Errorf("MajorVersion(18.2.0) = %d, %v, want 18", major, err)
+	}
+}
+
+func TestParseVersionMemoized(t *testing.T) {
+	first, err := parseVersion("4.17.21")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := parseVersion("4.17.21")
+	if err != nil || second != first {
+		t.Errorf("parseVersion() = %p, %v on the second call, want the memoized %p", second, err, first)
+	}
+
+	for range 2 {
+		if _, err := parseVersion("not-a-version"); err == nil {
+			t.Error("expected an error for an invalid version, including from the memo")
+		}
+	}
+}
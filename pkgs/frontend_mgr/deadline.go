@@ -0,0 +1,28 @@
+package frontend_mgr
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestContext bounds every CDN and registry request; a deadline or
+// cancellation on it stops requests in flight
+var RequestContext = context.Background()
+
+// httpGet is http.Get bound to RequestContext
+func httpGet(url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(RequestContext, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// httpHead is http.Head bound to RequestContext
+func httpHead(url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(RequestContext, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
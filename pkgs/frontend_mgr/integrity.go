@@ -0,0 +1,56 @@
+package frontend_mgr
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ComputeSRI returns the Subresource Integrity value (sha384) for file contents,
+// suitable for an HTML integrity attribute
+func ComputeSRI(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// VerifySRI checks file contents against an SRI value such as "sha512-...".
+// The value may list several space-separated hashes; any supported match passes.
+func VerifySRI(data []byte, integrity string) error {
+	supported := false
+
+	for _, entry := range strings.Fields(integrity) {
+		algorithm, expected, ok := strings.Cut(entry, "-")
+		if !ok {
+			continue
+		}
+
+		var digest []byte
+		switch algorithm {
+		case "sha256":
+			sum := sha256.Sum256(data)
+			digest = sum[:]
+		case "sha384":
+			sum := sha512.Sum384(data)
+			digest = sum[:]
+		case "sha512":
+			sum := sha512.Sum512(data)
+			digest = sum[:]
+		default:
+			continue
+		}
+
+		supported = true
+		// Ignore any "?options" suffix allowed by the SRI spec
+		expected, _, _ = strings.Cut(expected, "?")
+		if base64.StdEncoding.EncodeToString(digest) == expected {
+			return nil
+		}
+	}
+
+	if !supported {
+		return fmt.Errorf("no supported hash in integrity value %q", integrity)
+	}
+	return fmt.Errorf("integrity mismatch: expected %s", integrity)
+}
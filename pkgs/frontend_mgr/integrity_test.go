@@ -0,0 +1,37 @@
+package frontend_mgr
+
+import "testing"
+
+func TestComputeSRI(t *testing.T) {
+	// Reference value from: printf "alert('Hello, world.');" | openssl dgst -sha384 -binary | openssl base64 -A
+	expected := "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO"
+	if got := ComputeSRI([]byte("alert('Hello, world.');")); got != expected {
+		t.Errorf("ComputeSRI() = %q, want %q", got, expected)
+	}
+}
+
+func TestVerifySRI(t *testing.T) {
+	data := []byte("alert('Hello, world.');")
+
+	tests := []struct {
+		name      string
+		integrity string
+		wantErr   bool
+	}{
+		{"matching sha384", "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO", false},
+		{"computed value", ComputeSRI(data), false},
+		{"one of several hashes matches", "sha256-invalid sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO", false},
+		{"mismatch", ComputeSRI([]byte("tampered")), true},
+		{"unsupported algorithm", "md5-abc", true},
+		{"malformed value", "garbage", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySRI(data, tt.integrity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySRI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package frontend_mgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize is the largest CDN or registry API response that is
+// decoded by default (100 MiB, well above the largest npm documents)
+const DefaultMaxResponseSize int64 = 100 << 20
+
+// MaxResponseSize caps the size of CDN and registry API responses, so a
+// misbehaving endpoint can't exhaust memory
+var MaxResponseSize = DefaultMaxResponseSize
+
+// maxErrorBodySize caps how much of an error response is kept for messages
+const maxErrorBodySize = 64 << 10
+
+// ErrResponseTooLarge is returned when an API response exceeds MaxResponseSize
+var ErrResponseTooLarge = errors.New("response too large")
+
+// decodeResponse decodes the JSON body of resp into v as it streams in,
+// failing with ErrResponseTooLarge once more than MaxResponseSize bytes
+// have been read
+func decodeResponse(resp *http.Response, v any) error {
+	if resp.ContentLength > MaxResponseSize {
+		return responseTooLarge(resp.ContentLength)
+	}
+
+	body := &countingReader{r: io.LimitReader(resp.Body, MaxResponseSize+1)}
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		if body.n > MaxResponseSize {
+			return responseTooLarge(body.n)
+		}
+		return err
+	}
+	return nil
+}
+
+// readErrorBody returns the start of an error response's body
+func readErrorBody(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return string(body)
+}
+
+// responseTooLarge builds the error for a response of at least size bytes
+func responseTooLarge(size int64) error {
+	return fmt.Errorf("%w: %d bytes or more, the limit is %d MiB (raise max_response_mb in the smfaman settings)",
+		ErrResponseTooLarge, size, MaxResponseSize>>20)
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,50 @@
+package frontend_mgr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseSizeLimit(t *testing.T) {
+	// The document is padded past the limit; chunked responses have no
+	// Content-Length, so the limit is enforced while decoding
+	doc := `{"name": "big", "dist-tags": {"latest": "1.0.0"}, "versions": {"1.0.0": {"version": "1.0.0"}}, "readme": "` +
+		strings.Repeat("x", 4096) + `"}`
+	chunked := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if chunked {
+			w.(http.Flusher).Flush()
+		} else {
+			w.Header().Set("Content-Length", fmt.Sprint(len(doc)))
+		}
+		fmt.Fprint(w, doc)
+	}))
+	defer server.Close()
+
+	origURL, origLimit := npmRegistryURL, MaxResponseSize
+	npmRegistryURL = server.URL
+	defer func() {
+		npmRegistryURL, MaxResponseSize = origURL, origLimit
+		SetCacheEnabled(true)
+	}()
+	SetCacheEnabled(false)
+
+	if _, err := FetchUnpkgVersions("big"); err != nil {
+		t.Fatalf("FetchUnpkgVersions() under the default limit: %v", err)
+	}
+
+	MaxResponseSize = 1024
+	for _, chunked = range []bool{false, true} {
+		_, err := FetchUnpkgVersions("big")
+		if !errors.Is(err, ErrResponseTooLarge) {
+			t.Fatalf("FetchUnpkgVersions() (chunked=%v) error = %v, want ErrResponseTooLarge", chunked, err)
+		}
+		if !strings.Contains(err.Error(), "max_response_mb") {
+			t.Errorf("error %q should say which setting raises the limit", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package frontend_mgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PackageMetadata is descriptive information about a package
+type PackageMetadata struct {
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Homepage    string `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	License     string `yaml:"license,omitempty" json:"license,omitempty"`
+	Repository  string `yaml:"repository,omitempty" json:"repository,omitempty"`
+}
+
+// LicenseName returns the package license from the npm registry document
+func (r *UnpkgPackageResponse) LicenseName() string {
+	return stringOrField(r.License, "type")
+}
+
+// RepositoryURL returns the repository URL from the npm registry document
+func (r *UnpkgPackageResponse) RepositoryURL() string {
+	return stringOrField(r.Repository, "url")
+}
+
+// PublishedAt returns when a version was published, from the full npm
+// registry document, and whether the time is known
+func (r *UnpkgPackageResponse) PublishedAt(version string) (time.Time, bool) {
+	published, err := time.Parse(time.RFC3339, r.Time[version])
+	return published, err == nil
+}
+
+// DeprecationMessage returns the deprecation message of a version, or "" if
+// it isn't deprecated
+func (r *UnpkgPackageResponse) DeprecationMessage(version string) string {
+	return r.Versions[version].Deprecated
+}
+
+// stringOrField decodes a JSON value that is either a string or an object,
+// returning the string or the given field of the object
+func stringOrField(raw json.RawMessage, field string) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		if v, ok := obj[field].(string); ok {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// FetchPackageMetadata fetches description, homepage, license and repository
+// for a package. CDNJS libraries use the CDNJS API; everything else uses the
+// npm registry.
+func FetchPackageMetadata(packageName, cdn string) (*PackageMetadata, error) {
+	if cdn == "cdnjs" {
+		result, err := FetchCdnjsVersions(packageName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch metadata from cdnjs: %w", err)
+		}
+		return &PackageMetadata{
+			Description: result.Description,
+			Homepage:    result.Homepage,
+			License:     result.License,
+			Repository:  cleanRepositoryURL(result.Repository.URL),
+		}, nil
+	}
+
+	result, err := FetchNpmPackument(packageName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata from npm registry: %w", err)
+	}
+	return &PackageMetadata{
+		Description: result.Description,
+		Homepage:    result.Homepage,
+		License:     result.LicenseName(),
+		Repository:  cleanRepositoryURL(result.RepositoryURL()),
+	}, nil
+}
+
+// cleanRepositoryURL turns npm-style repository URLs such as
+// "git+https://github.com/x/y.git" into browsable https URLs
+func cleanRepositoryURL(url string) string {
+	url = strings.TrimPrefix(url, "git+")
+	url = strings.TrimSuffix(url, ".git")
+	if rest, ok := strings.CutPrefix(url, "git://"); ok {
+		url = "https://" + rest
+	}
+	if rest, ok := strings.CutPrefix(url, "git@github.com:"); ok {
+		url = "https://github.com/" + rest
+	}
+	return url
+}
@@ -0,0 +1,63 @@
+package frontend_mgr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnpkgPackageResponseLicenseAndRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		license    string
+		repository string
+	}{
+		{
+			name:       "string values",
+			doc:        `{"license": "MIT", "repository": "github:jquery/jquery"}`,
+			license:    "MIT",
+			repository: "github:jquery/jquery",
+		},
+		{
+			name:       "object values",
+			doc:        `{"license": {"type": "BSD-3-Clause", "url": "x"}, "repository": {"type": "git", "url": "git+https://github.com/lodash/lodash.git"}}`,
+			license:    "BSD-3-Clause",
+			repository: "git+https://github.com/lodash/lodash.git",
+		},
+		{
+			name: "missing values",
+			doc:  `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp UnpkgPackageResponse
+			if err := json.Unmarshal([]byte(tt.doc), &resp); err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if got := resp.LicenseName(); got != tt.license {
+				t.Errorf("LicenseName() = %q, want %q", got, tt.license)
+			}
+			if got := resp.RepositoryURL(); got != tt.repository {
+				t.Errorf("RepositoryURL() = %q, want %q", got, tt.repository)
+			}
+		})
+	}
+}
+
+func TestCleanRepositoryURL(t *testing.T) {
+	tests := map[string]string{
+		"git+https://github.com/lodash/lodash.git": "https://github.com/lodash/lodash",
+		"git://github.com/jquery/jquery.git":       "https://github.com/jquery/jquery",
+		"git@github.com:twbs/bootstrap.git":        "https://github.com/twbs/bootstrap",
+		"https://github.com/vuejs/core":            "https://github.com/vuejs/core",
+		"":                                         "",
+	}
+
+	for input, expected := range tests {
+		if got := cleanRepositoryURL(input); got != expected {
+			t.Errorf("cleanRepositoryURL(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package frontend_mgr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxPackageNameLength is the longest name the npm registry accepts
+const maxPackageNameLength = 214
+
+// packageNamePartPattern matches the characters npm allows in a package
+// name or scope
+var packageNamePartPattern = regexp.MustCompile(`^[a-z0-9._~-]+$`)
+
+// ErrInvalidPackageName is returned for names the npm registry can't have
+var ErrInvalidPackageName = errors.New("invalid package name")
+
+// NormalizePackageName trims whitespace and lowercases a package name, since
+// npm package names are lowercase
+func NormalizePackageName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidatePackageName checks a name against npm's rules for package names:
+// at most 214 characters, lowercase URL-safe characters only, not starting
+// with "." or "_", and scoped names in the form "@scope/name"
+func ValidatePackageName(name string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w %q: %s", ErrInvalidPackageName, name, reason)
+	}
+
+	switch {
+	case name == "":
+		return invalid("the name is empty")
+	case len(name) > maxPackageNameLength:
+		return invalid(fmt.Sprintf("names can be at most %d characters", maxPackageNameLength))
+	case strings.TrimSpace(name) != name || strings.ContainsAny(name, " \t"):
+		return invalid("names can't contain spaces")
+	case strings.ToLower(name) != name:
+		return invalid("names must be lowercase")
+	}
+
+	parts := []string{name}
+	if strings.HasPrefix(name, "@") {
+		scope, pkg, ok := strings.Cut(name[1:], "/")
+		if !ok || scope == "" || pkg == "" || strings.Contains(pkg, "/") {
+			return invalid("scoped names must look like @scope/name")
+		}
+		parts = []string{scope, pkg}
+	}
+
+	for _, part := range parts {
+		if strings.HasPrefix(part, ".") || strings.HasPrefix(part, "_") {
+			return invalid("names can't start with . or _")
+		}
+		if !packageNamePartPattern.MatchString(part) {
+			return invalid("names can only contain letters, digits, '-', '.', '_' and '~'")
+		}
+	}
+	return nil
+}
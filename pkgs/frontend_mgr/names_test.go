@@ -0,0 +1,47 @@
+package frontend_mgr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePackageName(t *testing.T) {
+	valid := []string{"jquery", "chart.js", "htmx.org", "@fortawesome/fontawesome-free", "lodash_es~1", strings.Repeat("a", 214)}
+	for _, name := range valid {
+		if err := ValidatePackageName(name); err != nil {
+			t.Errorf("ValidatePackageName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"":                       "empty",
+		"chart js":               "spaces",
+		"React":                  "lowercase",
+		".hidden":                "start with",
+		"_private":               "start with",
+		"@scope":                 "@scope/name",
+		"@/name":                 "@scope/name",
+		"@scope/a/b":             "@scope/name",
+		"@scope/_x":              "start with",
+		"jquery$":                "only contain",
+		"bootstrap/dist":         "only contain",
+		strings.Repeat("a", 215): "214",
+	}
+	for name, reason := range invalid {
+		err := ValidatePackageName(name)
+		if !errors.Is(err, ErrInvalidPackageName) {
+			t.Errorf("ValidatePackageName(%q) = %v, want ErrInvalidPackageName", name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), reason) {
+			t.Errorf("ValidatePackageName(%q) = %q, want it to mention %q", name, err, reason)
+		}
+	}
+}
+
+func TestNormalizePackageName(t *testing.T) {
+	if got := NormalizePackageName("  JQuery "); got != "jquery" {
+		t.Errorf("NormalizePackageName() = %q, want jquery", got)
+	}
+}
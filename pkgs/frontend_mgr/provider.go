@@ -0,0 +1,237 @@
+package frontend_mgr
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PackageFile is a file published in a package version
+type PackageFile struct {
+	Path      string // Relative to the package root, without a leading slash
+	Size      int64  // 0 when the provider doesn't report sizes
+	Integrity string // SRI hash, if published by the provider
+}
+
+// VersionList holds the published versions and dist-tags of a package
+type VersionList struct {
+	Versions []string          // Newest first; unparseable versions are dropped
+	DistTags map[string]string // e.g. "latest": "1.2.3"
+}
+
+// Latest returns the version tagged latest, or "" if the provider has none
+func (v *VersionList) Latest() string {
+	return v.DistTags["latest"]
+}
+
+// Provider fetches package information and files from a CDN
+type Provider interface {
+	// Name is the CDN name used in configs (e.g. "unpkg")
+	Name() string
+
+	// Versions lists the published versions and dist-tags of a package
+	Versions(packageName string) (*VersionList, error)
+
+	// Manifest lists the files published in a package version
+	Manifest(packageName, version string) ([]PackageFile, error)
+
+	// FileURL returns the URL of a file in a package version
+	FileURL(packageName, version, filePath string) string
+
+	// Search finds packages matching a query
+	Search(query string, limit int) ([]SearchResult, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Provider)
+)
+
+func init() {
+	RegisterProvider(unpkgProvider{})
+	RegisterProvider(cdnjsProvider{})
+	RegisterProvider(jsdelivrProvider{})
+}
+
+// RegisterProvider makes a provider available by name, replacing any
+// provider already registered under that name
+func RegisterProvider(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[p.Name()] = p
+}
+
+// GetProvider returns the provider registered under name
+func GetProvider(name string) (Provider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported CDN: %s", name)
+	}
+	return p, nil
+}
+
+// ProviderNames returns the names of all registered providers, sorted
+func ProviderNames() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unpkgProvider serves packages from UNPKG, with versions from the npm registry
+type unpkgProvider struct{}
+
+func (unpkgProvider) Name() string { return "unpkg" }
+
+func (unpkgProvider) Versions(packageName string) (*VersionList, error) {
+	result, err := FetchUnpkgVersions(packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(result.Versions))
+	for ver := range result.Versions {
+		versions = append(versions, ver)
+	}
+	return &VersionList{Versions: SortVersions(versions), DistTags: result.DistTags}, nil
+}
+
+func (unpkgProvider) Manifest(packageName, version string) ([]PackageFile, error) {
+	meta, err := FetchUnpkgMeta(packageName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return unpkgPackageFiles(meta), nil
+}
+
+// unpkgPackageFiles converts UNPKG metadata, which lists all files (no
+// directories) with their SRI hashes
+func unpkgPackageFiles(meta *UnpkgMetaResponse) []PackageFile {
+	files := make([]PackageFile, 0, len(meta.Files))
+	for _, file := range meta.Files {
+		files = append(files, PackageFile{
+			Path:      strings.TrimPrefix(file.Path, "/"),
+			Size:      int64(file.Size),
+			Integrity: file.Integrity,
+		})
+	}
+	return files
+}
+
+func (unpkgProvider) FileURL(packageName, version, filePath string) string {
+	return UnpkgFileURL(packageName, version, filePath)
+}
+
+func (unpkgProvider) Search(query string, limit int) ([]SearchResult, error) {
+	return SearchNpm(query, limit)
+}
+
+// cdnjsProvider serves libraries hosted on CDNJS
+type cdnjsProvider struct{}
+
+func (cdnjsProvider) Name() string { return "cdnjs" }
+
+func (cdnjsProvider) Versions(packageName string) (*VersionList, error) {
+	result, err := FetchCdnjsVersions(packageName)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionList{
+		Versions: SortVersions(result.Versions),
+		DistTags: map[string]string{"latest": result.Version},
+	}, nil
+}
+
+func (cdnjsProvider) Manifest(packageName, version string) ([]PackageFile, error) {
+	resp, err := FetchCdnjsVersion(packageName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// CDNJS doesn't report sizes in its metadata
+	files := make([]PackageFile, 0, len(resp.Files))
+	for _, file := range resp.Files {
+		files = append(files, PackageFile{Path: file, Integrity: resp.SRI[file]})
+	}
+	return files, nil
+}
+
+func (cdnjsProvider) FileURL(packageName, version, filePath string) string {
+	return CdnjsFileURL(packageName, version, filePath)
+}
+
+func (cdnjsProvider) Search(query string, limit int) ([]SearchResult, error) {
+	return SearchCdnjs(query, limit)
+}
+
+// jsdelivrProvider serves npm packages from jsDelivr
+type jsdelivrProvider struct{}
+
+func (jsdelivrProvider) Name() string { return "jsdelivr" }
+
+func (jsdelivrProvider) Versions(packageName string) (*VersionList, error) {
+	result, err := FetchJsdelivrVersions(packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(result.Versions))
+	for _, vInfo := range result.Versions {
+		versions = append(versions, vInfo.Version)
+	}
+	return &VersionList{Versions: SortVersions(versions), DistTags: result.Tags}, nil
+}
+
+func (jsdelivrProvider) Manifest(packageName, version string) ([]PackageFile, error) {
+	resp, err := FetchJsdelivrPackage(packageName, version)
+	if err != nil {
+		return nil, err
+	}
+	return flattenJsdelivrFiles(resp.Files, ""), nil
+}
+
+func (jsdelivrProvider) FileURL(packageName, version, filePath string) string {
+	return JsdelivrFileURL(packageName, version, filePath)
+}
+
+func (jsdelivrProvider) Search(query string, limit int) ([]SearchResult, error) {
+	return SearchNpm(query, limit)
+}
+
+// flattenJsdelivrFiles recursively collects the files of a jsDelivr file tree
+func flattenJsdelivrFiles(tree []JsdelivrFile, basePath string) []PackageFile {
+	var files []PackageFile
+	for _, f := range tree {
+		filePath := path.Join(basePath, f.Name)
+
+		if f.Type == "file" {
+			files = append(files, PackageFile{Path: filePath, Size: int64(f.Size), Integrity: jsdelivrSRI(f.Hash)})
+		} else if f.Type == "directory" && len(f.Files) > 0 {
+			files = append(files, flattenJsdelivrFiles(f.Files, filePath)...)
+		}
+	}
+	return files
+}
+
+// jsdelivrSRI converts a jsDelivr file hash, the base64 SHA-256 of the file,
+// to an SRI value, or returns "" when the hash isn't one
+func jsdelivrSRI(hash string) string {
+	sum, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil || len(sum) != sha256.Size {
+		return ""
+	}
+	return "sha256-" + hash
+}
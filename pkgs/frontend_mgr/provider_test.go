@@ -0,0 +1,132 @@
+package frontend_mgr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetProvider(t *testing.T) {
+	for _, name := range []string{"unpkg", "cdnjs", "jsdelivr"} {
+		p, err := GetProvider(name)
+		if err != nil {
+			t.Fatalf("GetProvider(%q) failed: %v", name, err)
+		}
+		if p.Name() != name {
+			t.Errorf("GetProvider(%q).Name() = %q", name, p.Name())
+		}
+	}
+
+	if _, err := GetProvider("bogus"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+func TestProviderFileURLs(t *testing.T) {
+	tests := map[string]string{
+		"unpkg":    "https://unpkg.com/jquery@3.7.1/dist/jquery.min.js",
+		"cdnjs":    "https://cdnjs.cloudflare.com/ajax/libs/jquery/3.7.1/dist/jquery.min.js",
+		"jsdelivr": "https://cdn.jsdelivr.net/npm/jquery@3.7.1/dist/jquery.min.js",
+	}
+	for name, expected := range tests {
+		p, _ := GetProvider(name)
+		if got := p.FileURL("jquery", "3.7.1", "dist/jquery.min.js"); got != expected {
+			t.Errorf("%s FileURL = %s, want %s", name, got, expected)
+		}
+	}
+}
+
+// staticProvider is a provider with fixed data, for testing registration
+type staticProvider struct{}
+
+func (staticProvider) Name() string { return "static" }
+func (staticProvider) Versions(string) (*VersionList, error) {
+	return &VersionList{Versions: []string{"1.0.0"}, DistTags: map[string]string{"latest": "1.0.0"}}, nil
+}
+func (staticProvider) Manifest(string, string) ([]PackageFile, error) {
+	return []PackageFile{{Path: "index.js"}}, nil
+}
+func (staticProvider) FileURL(packageName, version, filePath string) string {
+	return "https://static.example/" + packageName + "@" + version + "/" + filePath
+}
+func (staticProvider) Search(string, int) ([]SearchResult, error) { return nil, nil }
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider(staticProvider{})
+	defer func() {
+		providersMu.Lock()
+		delete(providers, "static")
+		providersMu.Unlock()
+	}()
+
+	if !slices.Contains(ProviderNames(), "static") {
+		t.Fatalf("expected static in %v", ProviderNames())
+	}
+	p, err := GetProvider("static")
+	if err != nil {
+		t.Fatalf("GetProvider failed: %v", err)
+	}
+	versions, err := p.Versions("pkg")
+	if err != nil || versions.Latest() != "1.0.0" {
+		t.Errorf("unexpected versions %+v (%v)", versions, err)
+	}
+}
+
+func TestFlattenJsdelivrFiles(t *testing.T) {
+	tree := []JsdelivrFile{
+		{
+			Name: "dist",
+			Type: "directory",
+			Files: []JsdelivrFile{
+				{Name: "jquery.min.js", Type: "file", Size: 1000, Hash: "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="},
+				{Name: "jquery.js", Type: "file", Size: 5000},
+			},
+		},
+		{Name: "package.json", Type: "file", Size: 500},
+		{Name: "empty", Type: "directory"},
+	}
+
+	files := flattenJsdelivrFiles(tree, "")
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %d: %+v", len(files), files)
+	}
+	if files[0].Path != "dist/jquery.min.js" || files[0].Size != 1000 {
+		t.Errorf("unexpected first file: %+v", files[0])
+	}
+	// jsDelivr's base64 SHA-256 becomes an SRI hash that verifies the file
+	if err := VerifySRI([]byte(""), files[0].Integrity); err != nil || files[0].Integrity != "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=" {
+		t.Errorf("Integrity = %q (%v), want the hash of the empty file", files[0].Integrity, err)
+	}
+	if files[1].Integrity != "" {
+		t.Errorf("a file without a hash got Integrity %q", files[1].Integrity)
+	}
+	if files[2].Path != "package.json" {
+		t.Errorf("unexpected root file: %+v", files[2])
+	}
+}
+
+func TestUnpkgPackageFiles(t *testing.T) {
+	integrity := ComputeSRI([]byte("jquery"))
+	files := unpkgPackageFiles(&UnpkgMetaResponse{Files: []UnpkgFile{
+		{Path: "/dist/jquery.min.js", Size: 6, Integrity: integrity},
+		{Path: "/package.json", Size: 2},
+	}})
+	if len(files) != 2 || files[0].Path != "dist/jquery.min.js" || files[0].Size != 6 || files[0].Integrity != integrity {
+		t.Fatalf("unpkgPackageFiles() = %+v", files)
+	}
+	if files[1].Integrity != "" {
+		t.Errorf("a file without a hash got Integrity %q", files[1].Integrity)
+	}
+}
+
+func TestJsdelivrSRI(t *testing.T) {
+	for hash, want := range map[string]string{
+		"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=": "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+		"":           "",
+		"not base64": "",
+		"AAAA":       "", // Too short for SHA-256
+	} {
+		if got := jsdelivrSRI(hash); got != want {
+			t.Errorf("jsdelivrSRI(%q) = %q, want %q", hash, got, want)
+		}
+	}
+}
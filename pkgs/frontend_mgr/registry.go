@@ -0,0 +1,107 @@
+package frontend_mgr
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// npmRegistryURL is the npm registry package documents and searches are
+// read from (overridable in tests)
+var npmRegistryURL = "https://registry.npmjs.org"
+
+// registryToken is sent as a bearer token with npm registry requests
+var registryToken string
+
+// FullRegistryDocuments makes version lookups fetch the full npm registry
+// document instead of the abbreviated one, for registries and proxies that
+// don't serve the abbreviated format correctly
+var FullRegistryDocuments bool
+
+// abbreviatedDocumentAccept asks the registry for the abbreviated ("corgi")
+// package document, falling back to the full one
+const abbreviatedDocumentAccept = "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8, */*"
+
+// ErrPrivatePackage is returned when a package resolves through the
+// authenticated npm registry but the public CDNs can't serve its files
+var ErrPrivatePackage = errors.New("package is not published on the public CDNs")
+
+// SetRegistryToken sets the token used to authenticate npm registry
+// requests, so private packages can be version-resolved. An empty token
+// sends anonymous requests.
+func SetRegistryToken(token string) {
+	registryToken = strings.TrimSpace(token)
+}
+
+// RegistryAuthenticated reports whether npm registry requests carry a token
+func RegistryAuthenticated() bool {
+	return registryToken != ""
+}
+
+// registryGet fetches a URL on the npm registry with the given Accept
+// header, authenticated when a token is set. The token is never sent to
+// other hosts (net/http drops it when a redirect leaves the registry).
+func registryGet(url, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(RequestContext, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if registryToken != "" {
+		req.Header.Set("Authorization", "Bearer "+registryToken)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// registryPost posts a JSON body to a URL on the npm registry, authenticated
+// like registryGet
+func registryPost(url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(RequestContext, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if registryToken != "" {
+		req.Header.Set("Authorization", "Bearer "+registryToken)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// ReadNpmrcToken returns the auth token for registry.npmjs.org from an
+// .npmrc file ("//registry.npmjs.org/:_authToken=..."), expanding ${VAR}
+// references. It returns "" when the file has no token for the registry.
+func ReadNpmrcToken(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	host := strings.TrimPrefix(npmRegistryURL, "https:")
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key != host+"/:_authToken" && key != host+":_authToken" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		return os.Expand(value, os.Getenv), nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return "", nil
+}
@@ -0,0 +1,121 @@
+package frontend_mgr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadNpmrcToken(t *testing.T) {
+	t.Setenv("MY_NPM_TOKEN", "from-env")
+
+	tests := []struct {
+		name, content, want string
+	}{
+		{"plain token", "//registry.npmjs.org/:_authToken=abc123\n", "abc123"},
+		{"env reference", "registry=https://registry.npmjs.org/\n//registry.npmjs.org/:_authToken=${MY_NPM_TOKEN}\n", "from-env"},
+		{"other registry only", "//npm.pkg.github.com/:_authToken=ghp_x\n", ""},
+		{"commented out", "# //registry.npmjs.org/:_authToken=old\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".npmrc")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadNpmrcToken(path)
+			if err != nil || got != tt.want {
+				t.Errorf("ReadNpmrcToken() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ReadNpmrcToken(filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected a not-exist error for a missing file, got %v", err)
+	}
+}
+
+func TestRegistryRequestsSendToken(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"name": "@acme/private", "dist-tags": {"latest": "1.2.0"}, "versions": {"1.2.0": {"version": "1.2.0"}}}`)
+	}))
+	defer server.Close()
+
+	origURL := npmRegistryURL
+	npmRegistryURL = server.URL
+	defer func() {
+		npmRegistryURL = origURL
+		SetRegistryToken("")
+		SetCacheEnabled(true)
+	}()
+	SetCacheEnabled(false)
+
+	SetRegistryToken(" secret \n")
+	if !RegistryAuthenticated() {
+		t.Fatal("expected registry requests to be authenticated")
+	}
+	pkg, err := FetchUnpkgVersions("@acme/private")
+	if err != nil {
+		t.Fatalf("FetchUnpkgVersions() error = %v", err)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the bearer token", gotAuth)
+	}
+	if pkg.DistTags["latest"] != "1.2.0" {
+		t.Errorf("latest = %q, want 1.2.0", pkg.DistTags["latest"])
+	}
+
+	SetRegistryToken("")
+	if _, err := SearchNpm("acme", 5); err != nil {
+		t.Fatalf("SearchNpm() error = %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none without a token", gotAuth)
+	}
+}
+
+func TestRegistryDocumentFormat(t *testing.T) {
+	var gotAccept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAccept = r.Header.Get("Accept")
+		fmt.Fprint(w, `{"name": "lodash", "description": "Lodash modular utilities.", "dist-tags": {"latest": "4.17.21"}, "versions": {"4.17.21": {}}}`)
+	}))
+	defer server.Close()
+
+	origURL := npmRegistryURL
+	npmRegistryURL = server.URL
+	defer func() {
+		npmRegistryURL = origURL
+		FullRegistryDocuments = false
+		SetCacheEnabled(true)
+	}()
+	SetCacheEnabled(false)
+
+	if _, err := FetchUnpkgVersions("lodash"); err != nil {
+		t.Fatalf("FetchUnpkgVersions() error = %v", err)
+	}
+	if gotAccept != abbreviatedDocumentAccept {
+		t.Errorf("version lookup Accept = %q, want the abbreviated document", gotAccept)
+	}
+
+	pkg, err := FetchNpmPackument("lodash")
+	if err != nil {
+		t.Fatalf("FetchNpmPackument() error = %v", err)
+	}
+	if gotAccept != "application/json" || pkg.Description == "" {
+		t.Errorf("packument Accept = %q, description = %q; want the full document", gotAccept, pkg.Description)
+	}
+
+	FullRegistryDocuments = true
+	if _, err := FetchUnpkgVersions("lodash"); err != nil {
+		t.Fatalf("FetchUnpkgVersions() error = %v", err)
+	}
+	if gotAccept != "application/json" {
+		t.Errorf("Accept = %q, want the full document with FullRegistryDocuments", gotAccept)
+	}
+}
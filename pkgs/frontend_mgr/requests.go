@@ -1,11 +1,12 @@
 package frontend_mgr
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-version"
@@ -18,6 +19,9 @@ var (
 
 	// CacheEnabled controls whether caching is enabled globally
 	CacheEnabled = true
+
+	// cdnjsAPIURL is the base URL of the CDNJS API (overridable in tests)
+	cdnjsAPIURL = "https://api.cdnjs.com"
 )
 
 func init() {
@@ -30,6 +34,25 @@ func init() {
 	}
 }
 
+// StatusError reports an unexpected HTTP status from a CDN or registry API
+type StatusError struct {
+	Source     string // e.g. "npm registry API"
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s returned status %d", e.Source, e.StatusCode)
+	}
+	return fmt.Sprintf("%s returned status %d: %s", e.Source, e.StatusCode, e.Body)
+}
+
+// NotFound reports whether the API said the package or version doesn't exist
+func (e *StatusError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
 // SetCacheEnabled enables or disables caching
 func SetCacheEnabled(enabled bool) error {
 	CacheEnabled = enabled
@@ -48,20 +71,20 @@ func FetchUnpkgMeta(libraryName, version string) (*UnpkgMetaResponse, error) {
 		return &result, nil
 	}
 
-	url := fmt.Sprintf("https://unpkg.com/%s@%s/?meta", libraryName, version)
+	url := fmt.Sprintf("https://unpkg.com/%s@%s/?meta", EscapePath(libraryName), url.PathEscape(version))
 
-	resp, err := http.Get(url)
+	resp, err := httpGet(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from UNPKG: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("UNPKG API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "UNPKG API", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode UNPKG response: %w", err)
 	}
 
@@ -74,6 +97,10 @@ func FetchUnpkgMeta(libraryName, version string) (*UnpkgMetaResponse, error) {
 // FetchCdnjsVersion fetches version-specific package data from CDNJS
 // Endpoint: https://api.cdnjs.com/libraries/{library_name}/{version}
 func FetchCdnjsVersion(libraryName, version string) (*CdnjsVersionResponse, error) {
+	if err := checkCdnjsName(libraryName); err != nil {
+		return nil, err
+	}
+
 	// Check cache first
 	cacheKey := cache.GenerateKey("cdnjs", "version", libraryName, version)
 	var result CdnjsVersionResponse
@@ -81,20 +108,20 @@ func FetchCdnjsVersion(libraryName, version string) (*CdnjsVersionResponse, erro
 		return &result, nil
 	}
 
-	url := fmt.Sprintf("https://api.cdnjs.com/libraries/%s/%s", libraryName, version)
+	url := fmt.Sprintf("%s/libraries/%s/%s", cdnjsAPIURL, url.PathEscape(libraryName), url.PathEscape(version))
 
-	resp, err := http.Get(url)
+	resp, err := httpGet(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from CDNJS: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("CDNJS API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "CDNJS API", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode CDNJS response: %w", err)
 	}
 
@@ -104,6 +131,34 @@ func FetchCdnjsVersion(libraryName, version string) (*CdnjsVersionResponse, erro
 	return &result, nil
 }
 
+// FetchContentLength returns the size of a remote file using a HEAD request
+func FetchContentLength(fileURL string) (int64, error) {
+	// Check cache first
+	cacheKey := cache.GenerateKey("head", "size", fileURL)
+	var size int64
+	if found, _ := CacheManager.Get(cacheKey, &size); found {
+		return size, nil
+	}
+
+	resp, err := httpHead(fileURL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch headers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, &StatusError{Source: "server", StatusCode: resp.StatusCode}
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("server did not report Content-Length")
+	}
+
+	// Store in cache
+	CacheManager.Set(cacheKey, resp.ContentLength)
+
+	return resp.ContentLength, nil
+}
+
 // FetchJsdelivrPackage fetches package metadata from jsDelivr CDN
 // Endpoint: https://data.jsdelivr.com/v1/packages/npm/{library_name}@{version}
 func FetchJsdelivrPackage(libraryName, version string) (*JsdelivrPackageResponse, error) {
@@ -114,20 +169,53 @@ func FetchJsdelivrPackage(libraryName, version string) (*JsdelivrPackageResponse
 		return &result, nil
 	}
 
-	url := fmt.Sprintf("https://data.jsdelivr.com/v1/packages/npm/%s@%s", libraryName, version)
+	url := fmt.Sprintf("https://data.jsdelivr.com/v1/packages/npm/%s@%s", EscapePath(libraryName), url.PathEscape(version))
+
+	resp, err := httpGet(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from jsDelivr: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "jsDelivr API", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode jsDelivr response: %w", err)
+	}
+
+	// Store in cache
+	CacheManager.Set(cacheKey, &result)
+
+	return &result, nil
+}
+
+// FetchJsdelivrEntrypoints fetches the recommended browser entry files for a package
+// Endpoint: https://data.jsdelivr.com/v1/packages/npm/{library_name}@{version}/entrypoints
+func FetchJsdelivrEntrypoints(libraryName, version string) (*JsdelivrEntrypointsResponse, error) {
+	// Check cache first
+	cacheKey := cache.GenerateKey("jsdelivr", "entrypoints", libraryName, version)
+	var result JsdelivrEntrypointsResponse
+	if found, _ := CacheManager.Get(cacheKey, &result); found {
+		return &result, nil
+	}
+
+	url := fmt.Sprintf("https://data.jsdelivr.com/v1/packages/npm/%s@%s/entrypoints", EscapePath(libraryName), url.PathEscape(version))
 
-	resp, err := http.Get(url)
+	resp, err := httpGet(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from jsDelivr: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("jsDelivr API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "jsDelivr API", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode jsDelivr response: %w", err)
 	}
 
@@ -137,9 +225,46 @@ func FetchJsdelivrPackage(libraryName, version string) (*JsdelivrPackageResponse
 	return &result, nil
 }
 
+// FetchPackageManifest fetches a package's package.json from UNPKG CDN
+// Endpoint: https://unpkg.com/{library_name}@{version}/package.json
+func FetchPackageManifest(libraryName, version string) (*PackageManifest, error) {
+	// Check cache first
+	cacheKey := cache.GenerateKey("unpkg", "manifest", libraryName, version)
+	var result PackageManifest
+	if found, _ := CacheManager.Get(cacheKey, &result); found {
+		return &result, nil
+	}
+
+	url := UnpkgFileURL(libraryName, version, "package.json")
+
+	resp, err := httpGet(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from UNPKG: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "UNPKG", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode package.json: %w", err)
+	}
+
+	// Store in cache
+	CacheManager.Set(cacheKey, &result)
+
+	return &result, nil
+}
+
 // FetchCdnjsVersions fetches all available versions for a package from CDNJS
 // Endpoint: https://api.cdnjs.com/libraries/{library_name}
 func FetchCdnjsVersions(libraryName string) (*CdnjsLibraryResponse, error) {
+	if err := checkCdnjsName(libraryName); err != nil {
+		return nil, err
+	}
+
 	// Check cache first
 	cacheKey := cache.GenerateKey("cdnjs", "versions", libraryName)
 	var result CdnjsLibraryResponse
@@ -147,20 +272,20 @@ func FetchCdnjsVersions(libraryName string) (*CdnjsLibraryResponse, error) {
 		return &result, nil
 	}
 
-	url := fmt.Sprintf("https://api.cdnjs.com/libraries/%s", libraryName)
+	url := fmt.Sprintf("%s/libraries/%s", cdnjsAPIURL, url.PathEscape(libraryName))
 
-	resp, err := http.Get(url)
+	resp, err := httpGet(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from CDNJS: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("CDNJS API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "CDNJS API", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode CDNJS response: %w", err)
 	}
 
@@ -180,20 +305,20 @@ func FetchJsdelivrVersions(libraryName string) (*JsdelivrVersionsResponse, error
 		return &result, nil
 	}
 
-	url := fmt.Sprintf("https://data.jsdelivr.com/v1/packages/npm/%s", libraryName)
+	url := fmt.Sprintf("https://data.jsdelivr.com/v1/packages/npm/%s", EscapePath(libraryName))
 
-	resp, err := http.Get(url)
+	resp, err := httpGet(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from jsDelivr: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("jsDelivr API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "jsDelivr API", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := decodeResponse(resp, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode jsDelivr response: %w", err)
 	}
 
@@ -206,6 +331,12 @@ func FetchJsdelivrVersions(libraryName string) (*JsdelivrVersionsResponse, error
 // FetchUnpkgVersions fetches all available versions for a package from npm registry
 // UNPKG doesn't have its own versions API, so we use the npm registry
 // Endpoint: https://registry.npmjs.org/{library_name}
+//
+// Only the abbreviated document (dist-tags and versions) is requested, which
+// is a fraction of the full one for packages with long histories; set
+// FullRegistryDocuments to fetch the full document instead. Description,
+// homepage and the other descriptive fields are only filled in by
+// FetchNpmPackument.
 func FetchUnpkgVersions(libraryName string) (*UnpkgPackageResponse, error) {
 	// Check cache first
 	cacheKey := cache.GenerateKey("unpkg", "versions", libraryName)
@@ -214,21 +345,29 @@ func FetchUnpkgVersions(libraryName string) (*UnpkgPackageResponse, error) {
 		return &result, nil
 	}
 
-	url := fmt.Sprintf("https://registry.npmjs.org/%s", libraryName)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch from npm registry: %w", err)
+	if err := fetchRegistryDocument(libraryName, !FullRegistryDocuments, &result); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("npm registry API returned status %d: %s", resp.StatusCode, string(body))
+	// Store in cache
+	CacheManager.Set(cacheKey, &result)
+
+	return &result, nil
+}
+
+// FetchNpmPackument fetches the full npm registry document of a package,
+// including its description, homepage, keywords, license and repository
+// Endpoint: https://registry.npmjs.org/{library_name}
+func FetchNpmPackument(libraryName string) (*UnpkgPackageResponse, error) {
+	// Check cache first
+	cacheKey := cache.GenerateKey("npm", "packument", libraryName)
+	var result UnpkgPackageResponse
+	if found, _ := CacheManager.Get(cacheKey, &result); found {
+		return &result, nil
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode npm registry response: %w", err)
+	if err := fetchRegistryDocument(libraryName, false, &result); err != nil {
+		return nil, err
 	}
 
 	// Store in cache
@@ -237,17 +376,73 @@ func FetchUnpkgVersions(libraryName string) (*UnpkgPackageResponse, error) {
 	return &result, nil
 }
 
+// fetchRegistryDocument fetches the npm registry document of a package into
+// result, asking for the abbreviated form when abbreviated is set
+func fetchRegistryDocument(libraryName string, abbreviated bool, result *UnpkgPackageResponse) error {
+	url := fmt.Sprintf("%s/%s", npmRegistryURL, registryPackagePath(libraryName))
+
+	accept := "application/json"
+	if abbreviated {
+		accept = abbreviatedDocumentAccept
+	}
+	resp, err := registryGet(url, accept)
+	if err != nil {
+		return fmt.Errorf("failed to fetch from npm registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := readErrorBody(resp)
+		return &StatusError{Source: "npm registry API", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	if err := decodeResponse(resp, result); err != nil {
+		return fmt.Errorf("failed to decode npm registry response: %w", err)
+	}
+	return nil
+}
+
+// CachedLatestVersion returns the latest version of a package recorded by an
+// earlier versions lookup on cdn, reading only the metadata cache
+func CachedLatestVersion(cdn, packageName string) (string, bool) {
+	switch cdn {
+	case "unpkg":
+		var result UnpkgPackageResponse
+		if found, _ := CacheManager.Get(cache.GenerateKey("unpkg", "versions", packageName), &result); found {
+			return result.DistTags["latest"], result.DistTags["latest"] != ""
+		}
+	case "jsdelivr":
+		var result JsdelivrVersionsResponse
+		if found, _ := CacheManager.Get(cache.GenerateKey("jsdelivr", "versions", packageName), &result); found {
+			return result.Tags["latest"], result.Tags["latest"] != ""
+		}
+	case "cdnjs":
+		var result CdnjsLibraryResponse
+		if found, _ := CacheManager.Get(cache.GenerateKey("cdnjs", "versions", packageName), &result); found {
+			return result.Version, result.Version != ""
+		}
+	}
+	return "", false
+}
+
 // SortVersions sorts version strings in descending order (newest first)
-// Uses semantic versioning for proper sorting
+// Uses semantic versioning for proper sorting. A version listed both with and
+// without a "v" prefix is kept once, in the form it was first listed.
 func SortVersions(versions []string) []string {
 	sorted := make([]*version.Version, 0, len(versions))
+	seen := make(map[string]bool, len(versions))
 
 	for _, v := range versions {
-		ver, err := version.NewVersion(v)
+		ver, err := parseVersion(v)
 		if err != nil {
 			// If parsing fails, skip this version
 			continue
 		}
+		normalized := NormalizeVersion(v)
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
 		sorted = append(sorted, ver)
 	}
 
@@ -277,21 +472,21 @@ func SearchCdnjs(query string, limit int) ([]SearchResult, error) {
 		return cachedResults, nil
 	}
 
-	url := fmt.Sprintf("https://api.cdnjs.com/libraries?search=%s&limit=%d&fields=name,description,version,homepage,keywords", query, limit)
+	searchURL := fmt.Sprintf("%s/libraries?search=%s&limit=%d&fields=name,description,version,homepage,keywords", cdnjsAPIURL, url.QueryEscape(query), limit)
 
-	resp, err := http.Get(url)
+	resp, err := httpGet(searchURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from CDNJS: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("CDNJS API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "CDNJS API", StatusCode: resp.StatusCode, Body: body}
 	}
 
 	var response CdnjsSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := decodeResponse(resp, &response); err != nil {
 		return nil, fmt.Errorf("failed to decode CDNJS response: %w", err)
 	}
 
@@ -329,21 +524,21 @@ func SearchNpm(query string, limit int) ([]SearchResult, error) {
 		return cachedResults, nil
 	}
 
-	url := fmt.Sprintf("https://registry.npmjs.org/-/v1/search?text=%s&size=%d", query, limit)
+	searchURL := fmt.Sprintf("%s/-/v1/search?text=%s&size=%d", npmRegistryURL, url.QueryEscape(query), limit)
 
-	resp, err := http.Get(url)
+	resp, err := registryGet(searchURL, "application/json")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from npm registry: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("npm registry API returned status %d: %s", resp.StatusCode, string(body))
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "npm registry API", StatusCode: resp.StatusCode, Body: body}
 	}
 
 	var response NpmSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	if err := decodeResponse(resp, &response); err != nil {
 		return nil, fmt.Errorf("failed to decode npm search response: %w", err)
 	}
 
@@ -368,16 +563,26 @@ func SearchNpm(query string, limit int) ([]SearchResult, error) {
 
 // SearchAllCDNs searches across all supported CDNs and returns unified results
 func SearchAllCDNs(query string, limit int) ([]SearchResult, error) {
+	return SearchAllCDNsFiltered(query, limit, SearchFilter{})
+}
+
+// SearchAllCDNsFiltered searches across all supported CDNs, narrowing the
+// results with the given filter. Scope and keyword qualifiers are passed to
+// the npm registry; CDNJS is skipped for scoped searches since it doesn't
+// host scoped packages.
+func SearchAllCDNsFiltered(query string, limit int, filter SearchFilter) ([]SearchResult, error) {
 	var allResults []SearchResult
 
 	// Search CDNJS
-	cdnjsResults, err := SearchCdnjs(query, limit)
-	if err == nil {
-		allResults = append(allResults, cdnjsResults...)
+	if strings.TrimSpace(query) != "" && filter.Scope == "" {
+		cdnjsResults, err := SearchCdnjs(query, limit)
+		if err == nil {
+			allResults = append(allResults, cdnjsResults...)
+		}
 	}
 
 	// Search npm (for UNPKG and jsDelivr)
-	npmResults, err := SearchNpm(query, limit)
+	npmResults, err := SearchNpm(filter.NpmQuery(query), limit)
 	if err == nil {
 		// Mark these as available on both UNPKG and jsDelivr
 		for i := range npmResults {
@@ -397,5 +602,92 @@ func SearchAllCDNs(query string, limit int) ([]SearchResult, error) {
 		}
 	}
 
-	return uniqueResults, nil
+	return filter.Apply(uniqueResults), nil
+}
+
+// LookupNpmPackage looks up a single package by its exact name using the
+// npm registry's full package document
+func LookupNpmPackage(packageName string) (*SearchResult, error) {
+	pkg, err := FetchNpmPackument(packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		Name:        pkg.Name,
+		Version:     pkg.DistTags["latest"],
+		Description: pkg.Description,
+		Homepage:    pkg.Homepage,
+		Keywords:    pkg.Keywords,
+		CDN:         "npm",
+	}, nil
+}
+
+// LookupCdnjsPackage looks up a single library by its exact name on CDNJS,
+// asking only for the fields search shows
+func LookupCdnjsPackage(libraryName string) (*SearchResult, error) {
+	if err := checkCdnjsName(libraryName); err != nil {
+		return nil, err
+	}
+
+	// Check cache first
+	cacheKey := cache.GenerateKey("cdnjs", "lookup", libraryName)
+	var lib CdnjsLibraryResponse
+	if found, _ := CacheManager.Get(cacheKey, &lib); !found {
+		lookupURL := fmt.Sprintf("%s/libraries/%s?fields=name,description,version,homepage,keywords", cdnjsAPIURL, url.PathEscape(libraryName))
+
+		resp, err := httpGet(lookupURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch from CDNJS: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body := readErrorBody(resp)
+			return nil, &StatusError{Source: "CDNJS API", StatusCode: resp.StatusCode, Body: body}
+		}
+		if err := decodeResponse(resp, &lib); err != nil {
+			return nil, fmt.Errorf("failed to decode CDNJS response: %w", err)
+		}
+
+		// Store in cache
+		CacheManager.Set(cacheKey, &lib)
+	}
+
+	return &SearchResult{
+		Name:        lib.Name,
+		Version:     lib.Version,
+		Description: lib.Description,
+		Homepage:    lib.Homepage,
+		Keywords:    lib.Keywords,
+		CDN:         "cdnjs",
+	}, nil
+}
+
+// LookupAllCDNs looks up a package by exact name on all CDNs, preferring the
+// CDNJS entry when the package exists in both places (like SearchAllCDNs).
+// A package missing everywhere has no results; other failures are returned.
+func LookupAllCDNs(packageName string) ([]SearchResult, error) {
+	result, cdnjsErr := LookupCdnjsPackage(packageName)
+	if cdnjsErr == nil && result.Name != "" {
+		return []SearchResult{*result}, nil
+	}
+
+	result, err := LookupNpmPackage(packageName)
+	switch {
+	case err == nil && result.Name != "":
+		result.CDN = "unpkg, jsdelivr"
+		return []SearchResult{*result}, nil
+	case err != nil && !isNotFoundError(err):
+		return nil, err
+	case cdnjsErr != nil && !isNotFoundError(cdnjsErr) && !errors.Is(cdnjsErr, ErrCdnjsScopedPackage):
+		return nil, cdnjsErr
+	}
+	return nil, nil
+}
+
+// isNotFoundError reports whether err is an API saying the package doesn't exist
+func isNotFoundError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.NotFound()
 }
@@ -1,6 +1,7 @@
 package frontend_mgr
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -57,3 +58,26 @@ func TestFetchJsdelivrPackage(t *testing.T) {
 		t.Error("Expected files array to have items")
 	}
 }
+
+func TestJsdelivrEntrypointsFiles(t *testing.T) {
+	data := `{"entrypoints":{"js":{"file":"/dist/jquery.min.js","guessed":false},"css":{"file":"/dist/jquery.css","guessed":true}}}`
+
+	var result JsdelivrEntrypointsResponse
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Failed to decode entrypoints response: %v", err)
+	}
+
+	files := result.Files()
+	expected := []string{"dist/jquery.css", "dist/jquery.min.js"}
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, files)
+		}
+	}
+	if !result.Entrypoints["css"].Guessed {
+		t.Error("Expected css entrypoint to be guessed")
+	}
+}
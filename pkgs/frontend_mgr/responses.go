@@ -1,5 +1,11 @@
 package frontend_mgr
 
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
 // UnpkgMetaResponse represents the response from https://unpkg.com/{library_name}@{version}/?meta
 type UnpkgMetaResponse struct {
 	Package string      `json:"package"`
@@ -50,6 +56,35 @@ type JsdelivrLinks struct {
 	Entrypoints string `json:"entrypoints"` // URL to package entrypoints endpoint
 }
 
+// JsdelivrEntrypointsResponse represents the response from https://data.jsdelivr.com/v1/packages/npm/{library_name}@{version}/entrypoints
+// It lists the package's recommended browser entry files by type
+type JsdelivrEntrypointsResponse struct {
+	Entrypoints map[string]JsdelivrEntrypoint `json:"entrypoints"` // Entry files keyed by type ("js", "css")
+}
+
+// JsdelivrEntrypoint describes a single entry file
+type JsdelivrEntrypoint struct {
+	File    string `json:"file"`    // File path (e.g., "/dist/jquery.min.js")
+	Guessed bool   `json:"guessed"` // Whether jsDelivr guessed the file rather than reading package.json
+}
+
+// Files returns the entry file paths without leading slashes, sorted by type
+func (r *JsdelivrEntrypointsResponse) Files() []string {
+	types := make([]string, 0, len(r.Entrypoints))
+	for entryType := range r.Entrypoints {
+		types = append(types, entryType)
+	}
+	sort.Strings(types)
+
+	var files []string
+	for _, entryType := range types {
+		if file := strings.TrimPrefix(r.Entrypoints[entryType].File, "/"); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
 // CdnjsLibraryResponse represents the response from https://api.cdnjs.com/libraries/{library}
 // This endpoint returns library information including all available versions
 type CdnjsLibraryResponse struct {
@@ -58,6 +93,8 @@ type CdnjsLibraryResponse struct {
 	Version     string   `json:"version"`     // Latest version number
 	Description string   `json:"description"` // Package description
 	Homepage    string   `json:"homepage"`    // Project homepage URL
+	License     string   `json:"license"`     // SPDX license identifier
+	Keywords    []string `json:"keywords"`    // Package keywords
 	Repository  struct {
 		Type string `json:"type"` // Repository type (e.g., "git")
 		URL  string `json:"url"`  // Repository URL
@@ -68,22 +105,22 @@ type CdnjsLibraryResponse struct {
 // JsdelivrVersionsResponse represents the response from https://data.jsdelivr.com/v1/packages/npm/{library}
 // This endpoint returns package information including available versions
 type JsdelivrVersionsResponse struct {
-	Type     string                 `json:"type"`     // Package type (e.g., "npm")
-	Name     string                 `json:"name"`     // Package name
-	Tags     map[string]string      `json:"tags"`     // Version tags (e.g., "latest": "1.2.3")
-	Versions []JsdelivrVersionInfo  `json:"versions"` // Available versions
-	Links    JsdelivrVersionsLinks  `json:"links"`    // Related API endpoints
+	Type     string                `json:"type"`     // Package type (e.g., "npm")
+	Name     string                `json:"name"`     // Package name
+	Tags     map[string]string     `json:"tags"`     // Version tags (e.g., "latest": "1.2.3")
+	Versions []JsdelivrVersionInfo `json:"versions"` // Available versions
+	Links    JsdelivrVersionsLinks `json:"links"`    // Related API endpoints
 }
 
 // JsdelivrVersionInfo represents version information in jsDelivr response
 type JsdelivrVersionInfo struct {
-	Version string                `json:"version"` // Version number
-	Links   JsdelivrVersionLinks  `json:"links"`   // Links to version-specific endpoints
+	Version string               `json:"version"` // Version number
+	Links   JsdelivrVersionLinks `json:"links"`   // Links to version-specific endpoints
 }
 
 // JsdelivrVersionLinks contains URLs to version-specific jsDelivr endpoints
 type JsdelivrVersionLinks struct {
-	Self string `json:"self"` // URL to this version's endpoint
+	Self  string `json:"self"`            // URL to this version's endpoint
 	Stats string `json:"stats,omitempty"` // URL to version stats
 }
 
@@ -97,10 +134,45 @@ type JsdelivrVersionsLinks struct {
 type UnpkgPackageResponse struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description,omitempty"`
-	DistTags    map[string]string `json:"dist-tags"` // Version tags (e.g., "latest": "1.2.3")
+	Homepage    string            `json:"homepage,omitempty"`
+	Keywords    []string          `json:"keywords,omitempty"`
+	License     json.RawMessage   `json:"license,omitempty"`    // String, or {"type": ...} in older packages
+	Repository  json.RawMessage   `json:"repository,omitempty"` // String, or {"type": ..., "url": ...}
+	DistTags    map[string]string `json:"dist-tags"`            // Version tags (e.g., "latest": "1.2.3")
 	Versions    map[string]struct {
-		Version string `json:"version"`
+		Version    string `json:"version"`
+		Deprecated string `json:"deprecated,omitempty"` // Deprecation message, if the version is deprecated
 	} `json:"versions"` // Map of version number to version info
+	Time map[string]string `json:"time,omitempty"` // Publish time of each version (full document only)
+}
+
+// PackageManifest represents a package's package.json as served by https://unpkg.com/{library_name}@{version}/package.json
+// Only the fields describing entry files are decoded
+type PackageManifest struct {
+	Name     string          `json:"name"`
+	Version  string          `json:"version"`
+	Main     string          `json:"main,omitempty"`     // CommonJS entry file
+	Module   string          `json:"module,omitempty"`   // ES module entry file
+	Browser  json.RawMessage `json:"browser,omitempty"`  // Browser entry file, or a map of replacements
+	Unpkg    string          `json:"unpkg,omitempty"`    // File served by UNPKG for the bare package URL
+	Jsdelivr string          `json:"jsdelivr,omitempty"` // File served by jsDelivr for the bare package URL
+	Style    string          `json:"style,omitempty"`    // Main stylesheet
+	Exports  json.RawMessage `json:"exports,omitempty"`  // Conditional exports (string, array or object)
+}
+
+// NpmVersionResponse represents the response from https://registry.npmjs.org/{package}/{version}
+// Only the tarball location and hashes are decoded
+type NpmVersionResponse struct {
+	Name    string  `json:"name"`
+	Version string  `json:"version"`
+	Dist    NpmDist `json:"dist"`
+}
+
+// NpmDist describes the published tarball of a package version
+type NpmDist struct {
+	Tarball   string `json:"tarball"`             // Tarball URL
+	Shasum    string `json:"shasum"`              // Hex SHA-1 of the tarball
+	Integrity string `json:"integrity,omitempty"` // SRI hash of the tarball (e.g. "sha512-..."), missing for old packages
 }
 
 // CdnjsSearchResponse represents the response from https://api.cdnjs.com/libraries?search={query}
@@ -0,0 +1,89 @@
+package frontend_mgr
+
+import (
+	"strings"
+)
+
+// SearchFilter narrows search results down by keyword and npm scope
+type SearchFilter struct {
+	// Keywords that a result must have (all of them, case-insensitive)
+	Keywords []string
+
+	// Scope restricts results to an npm scope (e.g. "@fortawesome")
+	Scope string
+}
+
+// IsEmpty reports whether the filter has no constraints
+func (f SearchFilter) IsEmpty() bool {
+	return len(f.Keywords) == 0 && f.Scope == ""
+}
+
+// NpmQuery appends npm search qualifiers for the filter to a query, so the
+// registry narrows results server-side before they are limited
+func (f SearchFilter) NpmQuery(query string) string {
+	parts := []string{}
+	if strings.TrimSpace(query) != "" {
+		parts = append(parts, strings.TrimSpace(query))
+	}
+	if scope := NormalizeScope(f.Scope); scope != "" {
+		parts = append(parts, "scope:"+strings.TrimPrefix(scope, "@"))
+	}
+	if len(f.Keywords) > 0 {
+		parts = append(parts, "keywords:"+strings.Join(f.Keywords, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Apply returns the results that match the filter
+func (f SearchFilter) Apply(results []SearchResult) []SearchResult {
+	if f.IsEmpty() {
+		return results
+	}
+
+	scope := NormalizeScope(f.Scope)
+	filtered := make([]SearchResult, 0, len(results))
+
+	for _, r := range results {
+		if scope != "" && !strings.HasPrefix(r.Name, scope+"/") {
+			continue
+		}
+		if !hasAllKeywords(r.Keywords, f.Keywords) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+
+	return filtered
+}
+
+// NormalizeScope converts scope input like "fortawesome", "@fortawesome/" or
+// "@fortawesome/*" to the canonical "@fortawesome" form
+func NormalizeScope(scope string) string {
+	scope = strings.TrimSpace(scope)
+	scope = strings.TrimSuffix(scope, "*")
+	scope = strings.TrimSuffix(scope, "/")
+	if scope == "" || scope == "@" {
+		return ""
+	}
+	if !strings.HasPrefix(scope, "@") {
+		scope = "@" + scope
+	}
+	return strings.ToLower(scope)
+}
+
+// hasAllKeywords checks that every wanted keyword appears in the package keywords
+func hasAllKeywords(keywords, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, k := range keywords {
+			if strings.EqualFold(k, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package frontend_mgr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeScope(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"@fortawesome", "@fortawesome"},
+		{"fortawesome", "@fortawesome"},
+		{"@fortawesome/", "@fortawesome"},
+		{"@fortawesome/*", "@fortawesome"},
+		{"@FortAwesome", "@fortawesome"},
+		{"", ""},
+		{"@", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeScope(tt.input); got != tt.want {
+				t.Errorf("NormalizeScope(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilterNpmQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		filter SearchFilter
+		want   string
+	}{
+		{"No filter", "react", SearchFilter{}, "react"},
+		{"Scope only", "", SearchFilter{Scope: "@fortawesome/*"}, "scope:fortawesome"},
+		{"Query and keywords", "icons", SearchFilter{Keywords: []string{"svg", "font"}}, "icons keywords:svg,font"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.NpmQuery(tt.query); got != tt.want {
+				t.Errorf("NpmQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilterApply(t *testing.T) {
+	results := []SearchResult{
+		{Name: "@fortawesome/fontawesome-free", Keywords: []string{"icons", "svg"}},
+		{Name: "@fortawesome/react-fontawesome", Keywords: []string{"react", "icons"}},
+		{Name: "feather-icons", Keywords: []string{"icons", "SVG"}},
+		{Name: "react", Keywords: []string{"react"}},
+	}
+
+	t.Run("Empty filter keeps all results", func(t *testing.T) {
+		if got := (SearchFilter{}).Apply(results); len(got) != len(results) {
+			t.Errorf("Expected %d results, got %d", len(results), len(got))
+		}
+	})
+
+	t.Run("Scope filter", func(t *testing.T) {
+		got := SearchFilter{Scope: "@fortawesome"}.Apply(results)
+		if len(got) != 2 {
+			t.Fatalf("Expected 2 scoped results, got %d", len(got))
+		}
+		for _, r := range got {
+			if r.Name[:12] != "@fortawesome" {
+				t.Errorf("Unexpected result outside scope: %s", r.Name)
+			}
+		}
+	})
+
+	t.Run("Keyword filter is case-insensitive", func(t *testing.T) {
+		got := SearchFilter{Keywords: []string{"svg"}}.Apply(results)
+		if len(got) != 2 {
+			t.Errorf("Expected 2 results with keyword svg, got %d", len(got))
+		}
+	})
+
+	t.Run("All keywords must match", func(t *testing.T) {
+		got := SearchFilter{Keywords: []string{"react", "icons"}}.Apply(results)
+		if len(got) != 1 || got[0].Name != "@fortawesome/react-fontawesome" {
+			t.Errorf("Expected only @fortawesome/react-fontawesome, got %v", got)
+		}
+	})
+}
+
+func TestLookupExactPackage(t *testing.T) {
+	var gotQuery string
+	cdnjs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		fmt.Fprint(w, `{"name": "font-awesome", "version": "6.5.1", "keywords": ["icons", "svg"]}`)
+	}))
+	defer cdnjs.Close()
+	registry := httptest.NewServer(http.NotFoundHandler())
+	defer registry.Close()
+
+	origCdnjs, origRegistry := cdnjsAPIURL, npmRegistryURL
+	cdnjsAPIURL, npmRegistryURL = cdnjs.URL, registry.URL
+	defer func() {
+		cdnjsAPIURL, npmRegistryURL = origCdnjs, origRegistry
+		SetCacheEnabled(true)
+	}()
+	SetCacheEnabled(false)
+
+	// Keywords are requested so keyword filters work on exact lookups
+	result, err := LookupCdnjsPackage("font-awesome")
+	if err != nil {
+		t.Fatalf("LookupCdnjsPackage() error = %v", err)
+	}
+	if gotQuery != "fields=name,description,version,homepage,keywords" {
+		t.Errorf("query = %q, want the search fields", gotQuery)
+	}
+	if got := (SearchFilter{Keywords: []string{"svg"}}).Apply([]SearchResult{*result}); len(got) != 1 {
+		t.Errorf("keyword filter dropped %+v", result)
+	}
+
+	// A package missing everywhere has no results
+	results, err := LookupAllCDNs("@acme/missing")
+	if err != nil || len(results) != 0 {
+		t.Errorf("LookupAllCDNs() = %v, %v; want no results", results, err)
+	}
+
+	// Network failures are reported instead of looking like no results
+	registry.Close()
+	if _, err := LookupAllCDNs("@acme/missing"); err == nil {
+		t.Error("expected the registry error")
+	}
+}
@@ -0,0 +1,180 @@
+package frontend_mgr
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ErrTarballIntegrity is returned when a downloaded tarball doesn't match
+// the hashes the npm registry publishes for it
+var ErrTarballIntegrity = errors.New("tarball failed integrity check")
+
+// PackageTarball is the verified contents of a package version's tarball
+type PackageTarball struct {
+	Integrity string            // SRI hash the tarball was verified against
+	Files     map[string][]byte // File contents keyed by path in the package
+}
+
+// FetchNpmDist fetches the tarball location and hashes of a package version
+// Endpoint: https://registry.npmjs.org/{library_name}/{version}
+func FetchNpmDist(libraryName, version string) (*NpmDist, error) {
+	url := fmt.Sprintf("%s/%s/%s", npmRegistryURL, registryPackagePath(libraryName), EscapePath(version))
+	resp, err := registryGet(url, "application/json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch from npm registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body := readErrorBody(resp)
+		return nil, &StatusError{Source: "npm registry API", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var result NpmVersionResponse
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode npm registry response: %w", err)
+	}
+	if result.Dist.Tarball == "" {
+		return nil, fmt.Errorf("npm registry lists no tarball for %s@%s", libraryName, version)
+	}
+	return &result.Dist, nil
+}
+
+// SRI returns the registry's integrity value for the tarball, derived from
+// the SHA-1 shasum for packages published before npm recorded one
+func (d *NpmDist) SRI() string {
+	if d.Integrity != "" {
+		return d.Integrity
+	}
+	sum, err := hex.DecodeString(d.Shasum)
+	if err != nil || len(sum) != sha1.Size {
+		return ""
+	}
+	return "sha1-" + base64.StdEncoding.EncodeToString(sum)
+}
+
+// Verify checks tarball bytes against dist.integrity, falling back to the
+// shasum when the registry has no integrity value
+func (d *NpmDist) Verify(data []byte) error {
+	if d.Integrity != "" {
+		if err := VerifySRI(data, d.Integrity); err != nil {
+			return fmt.Errorf("%w: %v", ErrTarballIntegrity, err)
+		}
+		return nil
+	}
+	if d.Shasum == "" {
+		return fmt.Errorf("%w: the registry publishes no integrity or shasum", ErrTarballIntegrity)
+	}
+
+	sum := sha1.Sum(data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), d.Shasum) {
+		return fmt.Errorf("%w: shasum mismatch: expected %s", ErrTarballIntegrity, d.Shasum)
+	}
+	return nil
+}
+
+// FetchPackageTarball downloads the tarball of a package version, verifies
+// it against the registry's dist hashes and returns its files. Nothing is
+// extracted from a tarball that fails verification.
+func FetchPackageTarball(libraryName, version string) (*PackageTarball, error) {
+	dist, err := FetchNpmDist(libraryName, version)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := downloadTarball(dist.Tarball)
+	if err != nil {
+		return nil, err
+	}
+	if err := dist.Verify(data); err != nil {
+		return nil, fmt.Errorf("%s@%s: %w", libraryName, version, err)
+	}
+
+	files, err := ExtractTarball(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s@%s: %w", libraryName, version, err)
+	}
+	return &PackageTarball{Integrity: dist.SRI(), Files: files}, nil
+}
+
+// downloadTarball fetches a tarball, sending the registry token only when it
+// is served by the registry itself
+func downloadTarball(url string) ([]byte, error) {
+	var resp *http.Response
+	var err error
+	if strings.HasPrefix(url, npmRegistryURL+"/") {
+		resp, err = registryGet(url, "application/octet-stream")
+	} else {
+		resp, err = httpGet(url)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download tarball: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Source: "npm registry", StatusCode: resp.StatusCode, Body: readErrorBody(resp)}
+	}
+	if resp.ContentLength > MaxResponseSize {
+		return nil, responseTooLarge(resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download tarball: %w", err)
+	}
+	if int64(len(data)) > MaxResponseSize {
+		return nil, responseTooLarge(int64(len(data)))
+	}
+	return data, nil
+}
+
+// ExtractTarball returns the regular files in a gzipped npm tarball keyed by
+// their path in the package, without the top-level folder ("package/") every
+// entry is stored under. Entries that would escape the package are rejected.
+func ExtractTarball(data []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		cleaned := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return nil, fmt.Errorf("unsafe path in tarball: %s", header.Name)
+		}
+		_, name, ok := strings.Cut(cleaned, "/")
+		if !ok {
+			continue
+		}
+		contents, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = contents
+	}
+}
@@ -0,0 +1,98 @@
+package frontend_mgr
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// buildTarball gzips a tar of files stored under package/, like npm pack
+func buildTarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestFetchPackageTarball(t *testing.T) {
+	published := buildTarball(t, map[string]string{
+		"package/package.json":     `{"name": "htmx.org"}`,
+		"package/dist/htmx.min.js": "htmx",
+	})
+	tampered := buildTarball(t, map[string]string{
+		"package/package.json":     `{"name": "htmx.org"}`,
+		"package/dist/htmx.min.js": "evil",
+	})
+	sha1Sum := sha1.Sum(published)
+
+	served, integrity, shasum := published, ComputeSRI(published), ""
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/htmx.org/2.0.4":
+			fmt.Fprintf(w, `{"name": "htmx.org", "version": "2.0.4", "dist": {"tarball": "%s/htmx.org/-/htmx.org-2.0.4.tgz", "shasum": %q, "integrity": %q}}`,
+				server.URL, shasum, integrity)
+		case "/htmx.org/-/htmx.org-2.0.4.tgz":
+			w.Write(served)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	origURL := npmRegistryURL
+	npmRegistryURL = server.URL
+	defer func() { npmRegistryURL = origURL }()
+
+	tarball, err := FetchPackageTarball("htmx.org", "2.0.4")
+	if err != nil {
+		t.Fatalf("FetchPackageTarball() error = %v", err)
+	}
+	if tarball.Integrity != integrity || string(tarball.Files["dist/htmx.min.js"]) != "htmx" {
+		t.Errorf("FetchPackageTarball() = %q, %v; want the verified files", tarball.Integrity, tarball.Files)
+	}
+
+	// A tarball that doesn't match dist.integrity is never extracted
+	served = tampered
+	if tarball, err := FetchPackageTarball("htmx.org", "2.0.4"); !errors.Is(err, ErrTarballIntegrity) || tarball != nil {
+		t.Errorf("FetchPackageTarball() = %v, %v; want an integrity error", tarball, err)
+	}
+
+	// Packages without dist.integrity are checked against the shasum
+	integrity, shasum = "", hex.EncodeToString(sha1Sum[:])
+	if _, err := FetchPackageTarball("htmx.org", "2.0.4"); !errors.Is(err, ErrTarballIntegrity) {
+		t.Errorf("expected the tampered tarball to fail the shasum check, got %v", err)
+	}
+	served = published
+	tarball, err = FetchPackageTarball("htmx.org", "2.0.4")
+	if err != nil {
+		t.Fatalf("FetchPackageTarball() error = %v", err)
+	}
+	if want := "sha1-" + base64.StdEncoding.EncodeToString(sha1Sum[:]); tarball.Integrity != want {
+		t.Errorf("Integrity = %q, want the shasum as %q", tarball.Integrity, want)
+	}
+}
+
+func TestExtractTarballRejectsUnsafePaths(t *testing.T) {
+	data := buildTarball(t, map[string]string{"package/../../etc/passwd": "x"})
+	if _, err := ExtractTarball(data); err == nil {
+		t.Error("expected an error for a path outside the package")
+	}
+}
@@ -0,0 +1,79 @@
+package frontend_mgr
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrCdnjsScopedPackage is returned when a scoped npm package is requested from CDNJS
+var ErrCdnjsScopedPackage = errors.New("cdnjs doesn't host scoped npm packages, use unpkg or jsdelivr")
+
+// IsScopedPackage reports whether name is a scoped npm package (@scope/name)
+func IsScopedPackage(name string) bool {
+	return strings.HasPrefix(name, "@") && strings.Contains(name, "/")
+}
+
+// EscapePath escapes each segment of a slash-separated path for use in a URL,
+// keeping the separators so "@scope/name" and "dist/app.js" stay readable
+func EscapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// registryPackagePath returns the npm registry path for a package, where the
+// slash of a scoped name is encoded ("@scope%2Fname")
+func registryPackagePath(name string) string {
+	return strings.Replace(EscapePath(name), "/", "%2F", 1)
+}
+
+// checkCdnjsName fails fast for package names CDNJS can't serve
+func checkCdnjsName(name string) error {
+	if IsScopedPackage(name) {
+		return fmt.Errorf("%w (%s)", ErrCdnjsScopedPackage, name)
+	}
+	return nil
+}
+
+// UnpkgFileURL returns the UNPKG URL of a file in a package version
+func UnpkgFileURL(name, version, filePath string) string {
+	return fmt.Sprintf("https://unpkg.com/%s@%s/%s", EscapePath(name), url.PathEscape(version), EscapePath(strings.TrimPrefix(filePath, "/")))
+}
+
+// JsdelivrFileURL returns the jsDelivr URL of a file in a package version
+func JsdelivrFileURL(name, version, filePath string) string {
+	return fmt.Sprintf("https://cdn.jsdelivr.net/npm/%s@%s/%s", EscapePath(name), url.PathEscape(version), EscapePath(strings.TrimPrefix(filePath, "/")))
+}
+
+// CdnjsFileURL returns the CDNJS URL of a file in a library version
+func CdnjsFileURL(name, version, filePath string) string {
+	return fmt.Sprintf("https://cdnjs.cloudflare.com/ajax/libs/%s/%s/%s", url.PathEscape(name), url.PathEscape(version), EscapePath(strings.TrimPrefix(filePath, "/")))
+}
+
+// PackagePageURL returns the web page of a package on a CDN: the file
+// browser on UNPKG, the package page on jsDelivr and CDNJS. An empty
+// version links to the latest one.
+func PackagePageURL(cdn, name, version string) string {
+	switch cdn {
+	case "cdnjs":
+		page := "https://cdnjs.com/libraries/" + url.PathEscape(name)
+		if version != "" {
+			page += "/" + url.PathEscape(version)
+		}
+		return page
+	case "jsdelivr":
+		page := "https://www.jsdelivr.com/package/npm/" + EscapePath(name)
+		if version != "" {
+			page += "?version=" + url.QueryEscape(version)
+		}
+		return page
+	}
+	if version == "" {
+		return fmt.Sprintf("https://unpkg.com/browse/%s/", EscapePath(name))
+	}
+	return fmt.Sprintf("https://unpkg.com/browse/%s@%s/", EscapePath(name), url.PathEscape(version))
+}
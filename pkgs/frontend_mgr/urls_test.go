@@ -0,0 +1,75 @@
+package frontend_mgr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsScopedPackage(t *testing.T) {
+	tests := map[string]bool{
+		"react":        false,
+		"@babel/core":  true,
+		"@types/react": true,
+		"@invalid":     false,
+	}
+	for name, expected := range tests {
+		if got := IsScopedPackage(name); got != expected {
+			t.Errorf("IsScopedPackage(%q) = %v, want %v", name, got, expected)
+		}
+	}
+}
+
+func TestFileURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"unpkg", UnpkgFileURL("react", "18.2.0", "/umd/react.js"), "https://unpkg.com/react@18.2.0/umd/react.js"},
+		{"unpkg scoped", UnpkgFileURL("@babel/standalone", "7.22.0", "babel.min.js"), "https://unpkg.com/@babel/standalone@7.22.0/babel.min.js"},
+		{"unpkg base", UnpkgFileURL("react", "18.2.0", ""), "https://unpkg.com/react@18.2.0/"},
+		{"jsdelivr scoped", JsdelivrFileURL("@popperjs/core", "2.11.8", "dist/umd/popper.min.js"), "https://cdn.jsdelivr.net/npm/@popperjs/core@2.11.8/dist/umd/popper.min.js"},
+		{"escaped file", JsdelivrFileURL("pkg", "1.0.0", "docs/read me#1.md"), "https://cdn.jsdelivr.net/npm/pkg@1.0.0/docs/read%20me%231.md"},
+		{"cdnjs", CdnjsFileURL("jquery", "3.7.1", "jquery.min.js"), "https://cdnjs.cloudflare.com/ajax/libs/jquery/3.7.1/jquery.min.js"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestPackagePageURL(t *testing.T) {
+	tests := []struct {
+		cdn, name, version, expected string
+	}{
+		{"unpkg", "@popperjs/core", "2.11.8", "https://unpkg.com/browse/@popperjs/core@2.11.8/"},
+		{"", "react", "", "https://unpkg.com/browse/react/"},
+		{"jsdelivr", "htmx.org", "2.0.0", "https://www.jsdelivr.com/package/npm/htmx.org?version=2.0.0"},
+		{"cdnjs", "jquery", "3.7.1", "https://cdnjs.com/libraries/jquery/3.7.1"},
+		{"cdnjs", "jquery", "", "https://cdnjs.com/libraries/jquery"},
+	}
+	for _, tt := range tests {
+		if got := PackagePageURL(tt.cdn, tt.name, tt.version); got != tt.expected {
+			t.Errorf("PackagePageURL(%q, %q, %q) = %q, want %q", tt.cdn, tt.name, tt.version, got, tt.expected)
+		}
+	}
+}
+
+func TestRegistryPackagePath(t *testing.T) {
+	if got := registryPackagePath("@babel/core"); got != "@babel%2Fcore" {
+		t.Errorf("expected @babel%%2Fcore, got %q", got)
+	}
+	if got := registryPackagePath("react"); got != "react" {
+		t.Errorf("expected react, got %q", got)
+	}
+}
+
+func TestCdnjsRejectsScopedPackages(t *testing.T) {
+	if _, err := FetchCdnjsVersions("@babel/core"); !errors.Is(err, ErrCdnjsScopedPackage) {
+		t.Errorf("expected ErrCdnjsScopedPackage, got %v", err)
+	}
+	if _, err := FetchCdnjsVersion("@babel/core", "7.22.0"); !errors.Is(err, ErrCdnjsScopedPackage) {
+		t.Errorf("expected ErrCdnjsScopedPackage, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package frontend_mgr
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -91,6 +92,11 @@ func TestSortVersions(t *testing.T) {
 			input:    []string{"3.0.0", "2.5.1", "3.0.1", "2.10.0"},
 			expected: []string{"3.0.1", "3.0.0", "2.10.0", "2.5.1"},
 		},
+		{
+			name:     "v prefixed duplicates kept once",
+			input:    []string{"v5.3.0", "5.2.0", "5.3.0", "v5.2.1"},
+			expected: []string{"v5.3.0", "v5.2.1", "5.2.0"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -129,3 +135,36 @@ func TestSortVersionsWithInvalidVersions(t *testing.T) {
 		}
 	}
 }
+
+// largeVersionList returns a react-sized list of versions in publish order
+func largeVersionList() []string {
+	var versions []string
+	for major := 0; major < 20; major++ {
+		for minor := 0; minor < 10; minor++ {
+			for patch := 0; patch < 5; patch++ {
+				versions = append(versions, fmt.Sprintf("%d.%d.%d", major, minor, patch))
+			}
+			versions = append(versions, fmt.Sprintf("%d.%d.0-rc.1", major, minor+1))
+		}
+	}
+	return versions
+}
+
+func BenchmarkSortVersions(b *testing.B) {
+	versions := largeVersionList()
+	b.ReportAllocs()
+	for b.Loop() {
+		SortVersions(versions)
+	}
+}
+
+// BenchmarkResolveVersionSpec resolves a range the way upgrade and pkgver do
+func BenchmarkResolveVersionSpec(b *testing.B) {
+	versions := SortVersions(largeVersionList())
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ResolveVersionSpec("^12.3.0", versions, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,538 @@
+// Package tui holds Bubble Tea components shared by the smfaman commands
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+// Styles of the picker; the commands recolor them for the selected theme
+var (
+	TitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			MarginLeft(2).
+			MarginBottom(1)
+
+	HeaderStyle = lipgloss.NewStyle().
+			Bold(true).
+			PaddingLeft(2)
+
+	ItemStyle = lipgloss.NewStyle().
+			PaddingLeft(2)
+
+	SelectedItemStyle = lipgloss.NewStyle().
+				PaddingLeft(1).
+				Bold(true)
+
+	DetailBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			Padding(0, 1).
+			MarginLeft(2)
+
+	HelpStyle = list.DefaultStyles().
+			HelpStyle.
+			PaddingLeft(4).
+			PaddingBottom(1)
+)
+
+// PickedMsg ends a PackagePicker with the chosen package, or a nil Result
+// when the picker was cancelled
+type PickedMsg struct {
+	Result *frontend_mgr.SearchResult
+}
+
+// SearchFunc searches for packages matching query, or looks up the package
+// named query when exact is set
+type SearchFunc func(query string, exact bool) ([]frontend_mgr.SearchResult, error)
+
+// PickerOptions configure a PackagePicker
+type PickerOptions struct {
+	InitialQuery string   // Searched right away when not empty
+	Exact        bool     // Start with exact-name lookups
+	History      []string // Earlier queries, most recent first, recalled with ↑/↓
+	Recent       []string // Recently picked packages, listed under the prompt
+
+	// Search runs the searches; it defaults to searching every CDN
+	Search SearchFunc
+
+	// OnSearch and OnPick are called with each query searched and the
+	// package picked, e.g. to keep a search history
+	OnSearch func(query string)
+	OnPick   func(result frontend_mgr.SearchResult)
+}
+
+// searchDoneMsg carries the results of a picker search
+type searchDoneMsg struct {
+	results []frontend_mgr.SearchResult
+	err     error
+}
+
+type pickerState int
+
+const (
+	pickerQuery pickerState = iota
+	pickerLoading
+	pickerResults
+)
+
+// pickerKeyMap holds the keys of the picker; list navigation and filtering
+// keys come from the list itself
+type pickerKeyMap struct {
+	Search      key.Binding
+	Older       key.Binding
+	Newer       key.Binding
+	ToggleExact key.Binding
+	Pick        key.Binding
+	Details     key.Binding
+	NewSearch   key.Binding
+	Back        key.Binding
+	Cancel      key.Binding
+}
+
+var pickerKeys = pickerKeyMap{
+	Search:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "search")),
+	Older:       key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "recall an older search")),
+	Newer:       key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "recall a newer search")),
+	ToggleExact: key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "toggle exact-name lookup")),
+	Pick:        key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "use this package")),
+	Details:     key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "show details")),
+	NewSearch:   key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new search")),
+	Back:        key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q/esc", "back to the search prompt")),
+	Cancel:      key.NewBinding(key.WithKeys("ctrl+c", "esc"), key.WithHelp("esc/ctrl+c", "cancel")),
+}
+
+// PackagePicker searches the CDNs and lets the user pick one package. It
+// ends with a PickedMsg, which the model embedding it handles; run on its own
+// by SelectPackage, it quits.
+type PackagePicker struct {
+	opts        PickerOptions
+	state       pickerState
+	queryInput  textinput.Model
+	list        list.Model
+	delegate    resultDelegate
+	query       string
+	exact       bool
+	history     []string
+	recallIndex int    // Position in history while recalling, -1 when not
+	recallDraft string // What was typed before recalling started
+	details     bool
+	err         error
+	width       int
+	height      int
+	picked      *frontend_mgr.SearchResult
+}
+
+// NewPackagePicker creates a package picker
+func NewPackagePicker(opts PickerOptions) PackagePicker {
+	if opts.Search == nil {
+		opts.Search = searchAllCDNs
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "Enter package name (e.g., react, vue, bootstrap)..."
+	ti.Focus()
+	ti.CharLimit = 100
+	ti.Width = 60
+	ti.SetValue(opts.InitialQuery)
+
+	m := PackagePicker{
+		opts:        opts,
+		queryInput:  ti,
+		exact:       opts.Exact,
+		history:     opts.History,
+		recallIndex: -1,
+	}
+	if strings.TrimSpace(opts.InitialQuery) != "" {
+		// Init runs the search
+		m.startSearch()
+	}
+	return m
+}
+
+// searchAllCDNs is the default SearchFunc
+func searchAllCDNs(query string, exact bool) ([]frontend_mgr.SearchResult, error) {
+	if exact {
+		return frontend_mgr.LookupAllCDNs(query)
+	}
+	return frontend_mgr.SearchAllCDNs(query, 20)
+}
+
+// Query returns the text in the query prompt
+func (m PackagePicker) Query() string {
+	return m.queryInput.Value()
+}
+
+// Picked returns the package picked when the picker ran on its own
+func (m PackagePicker) Picked() *frontend_mgr.SearchResult {
+	return m.picked
+}
+
+func (m PackagePicker) Init() tea.Cmd {
+	if m.state == pickerLoading {
+		return tea.Batch(textinput.Blink, m.search())
+	}
+	return textinput.Blink
+}
+
+// startSearch searches for the text in the query prompt
+func (m *PackagePicker) startSearch() tea.Cmd {
+	m.query = strings.TrimSpace(m.queryInput.Value())
+	m.state = pickerLoading
+	m.history = rememberQuery(m.history, m.query)
+	m.recallIndex, m.recallDraft = -1, ""
+	return m.search()
+}
+
+// search runs the search for the current query
+func (m PackagePicker) search() tea.Cmd {
+	query, exact, opts := m.query, m.exact, m.opts
+	return func() tea.Msg {
+		if opts.OnSearch != nil {
+			opts.OnSearch(query)
+		}
+		results, err := opts.Search(query, exact)
+		return searchDoneMsg{results: results, err: err}
+	}
+}
+
+// rememberQuery moves query to the front of the history
+func rememberQuery(history []string, query string) []string {
+	updated := []string{query}
+	for _, q := range history {
+		if q != query {
+			updated = append(updated, q)
+		}
+	}
+	return updated
+}
+
+func (m PackagePicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		if m.state == pickerResults {
+			m.list.SetSize(msg.Width, m.listHeight())
+		}
+		return m, nil
+
+	case PickedMsg:
+		// Only reached when the picker runs on its own
+		m.picked = msg.Result
+		return m, tea.Quit
+
+	case searchDoneMsg:
+		if msg.err != nil {
+			// Stay open so another search can be tried
+			m.err = msg.err
+			m.state = pickerQuery
+			return m, textinput.Blink
+		}
+		m.err = nil
+		m.showResults(msg.results)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.state {
+		case pickerQuery:
+			return m.updateQuery(msg)
+		case pickerResults:
+			return m.updateResults(msg)
+		}
+		if key.Matches(msg, pickerKeys.Cancel) {
+			return m, cancel
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.state {
+	case pickerQuery:
+		m.queryInput, cmd = m.queryInput.Update(msg)
+	case pickerResults:
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+// cancel ends the picker without a package
+func cancel() tea.Msg {
+	return PickedMsg{}
+}
+
+func (m PackagePicker) updateQuery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, pickerKeys.Cancel):
+		return m, cancel
+
+	case key.Matches(msg, pickerKeys.ToggleExact):
+		m.exact = !m.exact
+		return m, nil
+
+	case key.Matches(msg, pickerKeys.Older):
+		// Recall an older search
+		if m.recallIndex+1 < len(m.history) {
+			if m.recallIndex == -1 {
+				m.recallDraft = m.queryInput.Value()
+			}
+			m.recallIndex++
+			m.queryInput.SetValue(m.history[m.recallIndex])
+			m.queryInput.CursorEnd()
+		}
+		return m, nil
+
+	case key.Matches(msg, pickerKeys.Newer):
+		// Back towards the newest search and finally the draft
+		if m.recallIndex >= 0 {
+			m.recallIndex--
+			if m.recallIndex == -1 {
+				m.queryInput.SetValue(m.recallDraft)
+			} else {
+				m.queryInput.SetValue(m.history[m.recallIndex])
+			}
+			m.queryInput.CursorEnd()
+		}
+		return m, nil
+
+	case key.Matches(msg, pickerKeys.Search):
+		if strings.TrimSpace(m.queryInput.Value()) == "" {
+			return m, nil
+		}
+		return m, m.startSearch()
+	}
+
+	var cmd tea.Cmd
+	m.queryInput, cmd = m.queryInput.Update(msg)
+	return m, cmd
+}
+
+func (m PackagePicker) updateResults(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Let the list handle keys while the user is typing a filter
+	if m.list.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, cancel
+
+	case key.Matches(msg, pickerKeys.Pick):
+		i, ok := m.list.SelectedItem().(resultItem)
+		if !ok {
+			return m, nil
+		}
+		if m.opts.OnPick != nil {
+			m.opts.OnPick(i.result)
+		}
+		result := i.result
+		return m, func() tea.Msg { return PickedMsg{Result: &result} }
+
+	case key.Matches(msg, pickerKeys.Details):
+		m.details = !m.details
+		m.list.SetHeight(m.listHeight())
+		return m, nil
+
+	case key.Matches(msg, pickerKeys.Back, pickerKeys.NewSearch):
+		m.state = pickerQuery
+		m.queryInput.SetValue("")
+		m.queryInput.Focus()
+		return m, textinput.Blink
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// showResults lists the results of a search
+func (m *PackagePicker) showResults(results []frontend_mgr.SearchResult) {
+	items := make([]list.Item, len(results))
+	m.delegate = resultDelegate{nameWidth: 15, versionWidth: 10, cdnWidth: 8}
+	for i, r := range results {
+		items[i] = resultItem{result: r}
+		m.delegate.nameWidth = max(m.delegate.nameWidth, min(len(r.Name), 40))
+		m.delegate.versionWidth = max(m.delegate.versionWidth, min(len(r.Version), 15))
+		m.delegate.cdnWidth = max(m.delegate.cdnWidth, min(len(r.CDN), 20))
+	}
+
+	width := m.width
+	if width == 0 {
+		width = 120
+	}
+	l := list.New(items, m.delegate, width, m.listHeight())
+	l.Title = fmt.Sprintf("Results for '%s' (%d packages found)", m.query, len(results))
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(true)
+	l.Styles.Title = TitleStyle
+	l.Styles.HelpStyle = HelpStyle
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{pickerKeys.Pick, pickerKeys.Details, pickerKeys.NewSearch}
+	}
+
+	m.list = l
+	m.state = pickerResults
+}
+
+// detailsHeight is the number of rows the details box takes below the list
+const detailsHeight = 7
+
+// listHeight returns the height of the results list, leaving room for the
+// table header and the details box
+func (m PackagePicker) listHeight() int {
+	if m.height == 0 {
+		return 20
+	}
+	height := m.height - 5
+	if m.details {
+		height -= detailsHeight
+	}
+	return max(height, 5)
+}
+
+func (m PackagePicker) View() string {
+	switch m.state {
+	case pickerLoading:
+		mode := "Searching for"
+		if m.exact {
+			mode = "Looking up"
+		}
+		return "\n" + ItemStyle.Render(fmt.Sprintf("🔍 %s '%s'...", mode, m.query)) + "\n"
+	case pickerResults:
+		return m.viewResults()
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(TitleStyle.Render("🔍 Find a Package to Add"))
+	b.WriteString("\n")
+	b.WriteString(ItemStyle.Render("  " + m.queryInput.View()))
+	b.WriteString("\n\n")
+	if m.err != nil {
+		b.WriteString(ItemStyle.Render(fmt.Sprintf("  Search failed: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+	if m.exact {
+		b.WriteString(ItemStyle.Render("  Mode: exact-name lookup"))
+		b.WriteString("\n\n")
+	}
+	if len(m.opts.Recent) > 0 {
+		b.WriteString(ItemStyle.Render("  Recent packages: " + strings.Join(m.opts.Recent, ", ")))
+		b.WriteString("\n\n")
+	}
+	help := "  Press Enter to search • Ctrl+E to toggle exact match • Esc to cancel"
+	if len(m.history) > 0 {
+		help = "  Press Enter to search • ↑/↓ recent searches • Ctrl+E to toggle exact match • Esc to cancel"
+	}
+	b.WriteString(HelpStyle.Render(help))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m PackagePicker) viewResults() string {
+	d := m.delegate
+	header := fmt.Sprintf("    %s  %s  %s  %s", padRight("PACKAGE", d.nameWidth), padRight("VERSION", d.versionWidth),
+		padRight("CDN", d.cdnWidth), "DESCRIPTION")
+
+	// Insert the table header after the list title
+	lines := strings.Split("\n"+m.list.View(), "\n")
+	if len(lines) > 2 {
+		lines = append(lines[:2:2], append([]string{
+			HeaderStyle.Render(header),
+			HeaderStyle.Render("  " + strings.Repeat("─", len(header)-2)),
+		}, lines[2:]...)...)
+	}
+	view := strings.Join(lines, "\n")
+
+	if i, ok := m.list.SelectedItem().(resultItem); ok && m.details {
+		view += "\n" + DetailBoxStyle.Render(resultDetails(i.result))
+	}
+	return view
+}
+
+// resultDetails describes a search result for the details box
+func resultDetails(r frontend_mgr.SearchResult) string {
+	lines := []string{fmt.Sprintf("%s %s (%s)", r.Name, r.Version, r.CDN)}
+	if r.Description != "" {
+		lines = append(lines, truncate(r.Description, 100))
+	}
+	if r.Homepage != "" {
+		lines = append(lines, "Homepage: "+r.Homepage)
+	}
+	if len(r.Keywords) > 0 {
+		lines = append(lines, "Keywords: "+truncate(strings.Join(r.Keywords, ", "), 90))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SelectPackage runs a package picker on its own and returns the package
+// chosen, or nil when it was cancelled
+func SelectPackage(opts PickerOptions) (*frontend_mgr.SearchResult, error) {
+	final, err := tea.NewProgram(NewPackagePicker(opts), tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, fmt.Errorf("error running package search: %w", err)
+	}
+	return final.(PackagePicker).Picked(), nil
+}
+
+// resultItem is a search result in the results list
+type resultItem struct {
+	result frontend_mgr.SearchResult
+}
+
+func (i resultItem) FilterValue() string {
+	return i.result.Name + " " + i.result.Description + " " + strings.Join(i.result.Keywords, " ")
+}
+
+// resultDelegate renders the results as table rows
+type resultDelegate struct {
+	nameWidth    int
+	versionWidth int
+	cdnWidth     int
+}
+
+func (d resultDelegate) Height() int                             { return 1 }
+func (d resultDelegate) Spacing() int                            { return 0 }
+func (d resultDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d resultDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(resultItem)
+	if !ok {
+		return
+	}
+
+	line := fmt.Sprintf("%s  %s  %s  %s",
+		padRight(truncate(i.result.Name, d.nameWidth), d.nameWidth),
+		padRight(truncate(i.result.Version, d.versionWidth), d.versionWidth),
+		padRight(truncate(i.result.CDN, d.cdnWidth), d.cdnWidth),
+		truncate(i.result.Description, 50))
+
+	if index == m.Index() {
+		fmt.Fprint(w, SelectedItemStyle.Render("→ "+line))
+	} else {
+		fmt.Fprint(w, ItemStyle.Render("  "+line))
+	}
+}
+
+func padRight(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
+
+func truncate(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return s[:width]
+	}
+	return s[:width-3] + "..."
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"nexus-sds.com/smfaman/pkgs/frontend_mgr"
+)
+
+func TestPackagePicker(t *testing.T) {
+	var searched, picked []string
+	m := NewPackagePicker(PickerOptions{
+		InitialQuery: "reac",
+		Search: func(query string, exact bool) ([]frontend_mgr.SearchResult, error) {
+			return []frontend_mgr.SearchResult{
+				{Name: "react", Version: "18.2.0", CDN: "npm"},
+				{Name: "vue", Version: "3.4.0", CDN: "npm"},
+			}, nil
+		},
+		OnSearch: func(query string) { searched = append(searched, query) },
+		OnPick:   func(result frontend_mgr.SearchResult) { picked = append(picked, result.Name) },
+	})
+
+	// The initial query is searched right away
+	var updated tea.Model = m
+	for _, cmd := range m.Init()().(tea.BatchMsg) {
+		updated, _ = updated.Update(cmd())
+	}
+	m = updated.(PackagePicker)
+	if m.state != pickerResults || strings.Join(searched, ",") != "reac" {
+		t.Fatalf("initial search: state %v, searched %v", m.state, searched)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(PickedMsg)
+	if !ok || msg.Result == nil || msg.Result.Name != "react" || strings.Join(picked, ",") != "react" {
+		t.Fatalf("enter should pick react, got %#v", cmd())
+	}
+
+	// On its own the picker quits once a package is picked
+	updated, cmd = m.Update(msg)
+	if m = updated.(PackagePicker); m.Picked() == nil || m.Picked().Name != "react" || cmd == nil {
+		t.Errorf("Picked() = %v", m.Picked())
+	}
+}
+
+func TestPackagePickerCancel(t *testing.T) {
+	m := NewPackagePicker(PickerOptions{})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if msg, ok := cmd().(PickedMsg); !ok || msg.Result != nil {
+		t.Errorf("esc should cancel the picker, got %#v", cmd())
+	}
+}
+
+func TestPackagePickerFailedSearch(t *testing.T) {
+	m := NewPackagePicker(PickerOptions{})
+	updated, _ := m.Update(searchDoneMsg{err: errors.New("offline")})
+	if m = updated.(PackagePicker); m.state != pickerQuery || !strings.Contains(m.View(), "Search failed: offline") {
+		t.Errorf("a failed search should return to the prompt, state %v", m.state)
+	}
+}
+
+func TestPackagePickerWindowSize(t *testing.T) {
+	results := make([]frontend_mgr.SearchResult, 30)
+	for i := range results {
+		results[i] = frontend_mgr.SearchResult{Name: "pkg", Version: "1.0.0", CDN: "npm"}
+	}
+
+	// A size received before the results arrive still fits the list
+	var updated tea.Model = NewPackagePicker(PickerOptions{})
+	updated, _ = updated.Update(tea.WindowSizeMsg{Width: 80, Height: 15})
+	updated, _ = updated.Update(searchDoneMsg{results: results})
+	if lines := strings.Count(updated.View(), "\n") + 1; lines > 15 {
+		t.Errorf("picker rendered %d lines in a 15 line window", lines)
+	}
+}